@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-seidon/local/internal/app"
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/config"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/managing"
+	"github.com/go-seidon/local/internal/quota"
+	"github.com/go-seidon/local/internal/text"
+)
+
+const usage = `usage: client <command> [flags]
+
+commands:
+  create         create a new client, e.g: client create -name backoffice -scopes file:read -cidrs 10.0.0.0/8
+  list           list the clients, e.g: client list -limit 50
+  add-secret     add a new secret to the client, e.g: client add-secret -client-id <id> -label ci -expires-in 720h
+  rotate-secret  replace the secret of the client, e.g: client rotate-secret -client-id <id> -grace 24h
+  list-secrets   list the secrets of the client, e.g: client list-secrets -client-id <id>
+  revoke-secret  revoke the secret of the client, e.g: client revoke-secret -client-id <id> -secret-id <id>
+  set-allowlist  replace the allowed cidrs of the client, e.g: client set-allowlist -client-id <id> -cidrs 10.0.0.0/8
+  set-rate-limit replace the rate limit override of the client, e.g: client set-rate-limit -client-id <id> -request-rate 50 -byte-rate 0
+  set-quota      replace the storage quota of the client, e.g: client set-quota -client-id <id> -max-size 1073741824 -max-file 1000
+  disable        disable the client, e.g: client disable -client-id <id>
+  delete         delete the client, e.g: client delete -client-id <id>
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	name := flags.String("name", "", "name of the client")
+	scopes := flags.String("scopes", strings.Join([]string{
+		auth.SCOPE_FILE_READ, auth.SCOPE_FILE_WRITE, auth.SCOPE_FILE_DELETE,
+	}, ","), "comma separated scopes granted to the client")
+	cidrs := flags.String("cidrs", "", "comma separated cidrs the client is allowed from, any address when it is empty")
+	requestRate := flags.String("request-rate", "", "requests per second of the client, 0 means unlimited, default limit when it is empty")
+	byteRate := flags.String("byte-rate", "", "bytes per second of the client, 0 means unlimited, default limit when it is empty")
+	maxSize := flags.Int64("max-size", 0, "maximum total bytes stored by the client, 0 means unlimited")
+	maxFile := flags.Int64("max-file", 0, "maximum number of files stored by the client, 0 means unlimited")
+	clientId := flags.String("client-id", "", "client id of the managed client")
+	afterId := flags.String("after", "", "list the clients after the specified id")
+	limit := flags.Int("limit", managing.DEFAULT_LIST_LIMIT, "maximum number of listed clients")
+	secretId := flags.String("secret-id", "", "id of the managed secret")
+	label := flags.String("label", managing.DEFAULT_SECRET_LABEL, "label of the new secret")
+	expiresIn := flags.Duration("expires-in", 0, "lifetime of the new secret, never expires when it is not specified")
+	grace := flags.Duration("grace", 0, "period the previous secrets are still valid after rotation")
+	flags.Parse(os.Args[2:])
+
+	appEnv := os.Getenv("APP_ENV")
+	if appEnv == "" {
+		appEnv = "local"
+	}
+
+	appConfig := app.Config{AppEnv: appEnv}
+
+	cfgFileName := fmt.Sprintf("config/%s.toml", appConfig.AppEnv)
+	tomlConfig, err := config.NewViperConfig(
+		config.WithFileName(cfgFileName),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = tomlConfig.LoadConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	err = tomlConfig.ParseConfig(&appConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	logger := logging.NewLogrusLog(
+		logging.WithAppContext(appConfig.AppName, appConfig.AppVersion),
+	)
+
+	repo, err := app.NewRepository(app.WithMySQLRepository(
+		appConfig.MySQLUser, appConfig.MySQLPassword,
+		appConfig.MySQLDBName, appConfig.MySQLHost,
+		appConfig.MySQLPort,
+	))
+	if err != nil {
+		panic(err)
+	}
+
+	hasher, err := app.NewHasher(app.NewHasherParam{
+		Algorithm:         appConfig.HashAlgorithm,
+		BcryptCost:        appConfig.HashBcryptCost,
+		Argon2Memory:      appConfig.HashArgon2Memory,
+		Argon2Iterations:  appConfig.HashArgon2Iterations,
+		Argon2Parallelism: appConfig.HashArgon2Parallelism,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// the signing key is sealed by the server key, so it must match the rest app config
+	var signatureAuth auth.SignatureAuth
+	if appConfig.AuthSignatureKey != "" {
+		signatureAuth, err = auth.NewSignatureAuth(auth.NewSignatureAuthParam{
+			OAuthRepo: repo.OAuthRepo,
+			Key:       []byte(appConfig.AuthSignatureKey),
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	manager, err := managing.NewClientManager(managing.NewClientManagerParam{
+		OAuthRepo:  repo.OAuthRepo,
+		Hasher:     hasher,
+		Identifier: text.NewKsuid(),
+		Signature:  signatureAuth,
+		Logger:     logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	quotaService, err := quota.NewQuota(quota.NewQuotaParam{
+		QuotaRepo: repo.QuotaRepo,
+		Logger:    logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "create":
+		res, err := manager.CreateClient(ctx, managing.CreateClientParam{
+			Name:         *name,
+			Scopes:       splitList(*scopes),
+			AllowedCidrs: splitList(*cidrs),
+		})
+		if err != nil {
+			logger.Errorf("Failed create client: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client_id:     %s\n", res.ClientId)
+		fmt.Printf("secret_id:     %s\n", res.SecretId)
+		fmt.Printf("client_secret: %s\n", res.ClientSecret)
+		printSigningKey(res.SigningKey)
+		fmt.Printf("scopes:        %s\n", strings.Join(res.Scopes, " "))
+		fmt.Printf("allowed_cidrs: %s\n", formatCidrs(res.AllowedCidrs))
+		fmt.Println("the secret is not retrievable afterward, store it securely")
+	case "list":
+		res, err := manager.ListClient(ctx, managing.ListClientParam{
+			AfterId: *afterId,
+			Limit:   *limit,
+		})
+		if err != nil {
+			logger.Errorf("Failed list client: %s", err.Error())
+			os.Exit(1)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tCLIENT ID\tSCOPES\tALLOWED CIDRS\tSTATUS\tCREATED AT")
+		for _, client := range res.Items {
+			status := "active"
+			if client.DisabledAt != nil {
+				status = "disabled"
+			}
+			fmt.Fprintf(
+				w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				client.Id, client.Name, client.ClientId,
+				strings.Join(client.Scopes, " "), formatCidrs(client.AllowedCidrs), status,
+				client.CreatedAt.Format(time.RFC3339),
+			)
+		}
+		w.Flush()
+	case "add-secret":
+		res, err := manager.CreateSecret(ctx, managing.CreateSecretParam{
+			ClientId:  *clientId,
+			Label:     *label,
+			ExpiresIn: *expiresIn,
+		})
+		if err != nil {
+			logger.Errorf("Failed add client secret: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client_id:     %s\n", res.ClientId)
+		fmt.Printf("secret_id:     %s\n", res.SecretId)
+		fmt.Printf("client_secret: %s\n", res.ClientSecret)
+		printSigningKey(res.SigningKey)
+		fmt.Println("the secret is not retrievable afterward, store it securely")
+	case "rotate-secret":
+		res, err := manager.RotateSecret(ctx, managing.RotateSecretParam{
+			ClientId:    *clientId,
+			Label:       *label,
+			ExpiresIn:   *expiresIn,
+			GracePeriod: *grace,
+		})
+		if err != nil {
+			logger.Errorf("Failed rotate client secret: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client_id:     %s\n", res.ClientId)
+		fmt.Printf("secret_id:     %s\n", res.SecretId)
+		fmt.Printf("client_secret: %s\n", res.ClientSecret)
+		printSigningKey(res.SigningKey)
+		fmt.Printf("the previous secrets are valid until %s\n", res.PreviousExpiresAt.Format(time.RFC3339))
+		printPropagation(appConfig)
+	case "list-secrets":
+		res, err := manager.ListSecret(ctx, managing.ListSecretParam{
+			ClientId: *clientId,
+		})
+		if err != nil {
+			logger.Errorf("Failed list client secret: %s", err.Error())
+			os.Exit(1)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tLABEL\tCREATED AT\tEXPIRES AT\tLAST USED AT")
+		for _, secret := range res.Items {
+			fmt.Fprintf(
+				w, "%s\t%s\t%s\t%s\t%s\n",
+				secret.Id, secret.Label,
+				secret.CreatedAt.Format(time.RFC3339),
+				formatTime(secret.ExpiresAt, "never"),
+				formatTime(secret.LastUsedAt, "never"),
+			)
+		}
+		w.Flush()
+	case "revoke-secret":
+		_, err := manager.RevokeSecret(ctx, managing.RevokeSecretParam{
+			ClientId: *clientId,
+			SecretId: *secretId,
+		})
+		if err != nil {
+			logger.Errorf("Failed revoke client secret: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("secret %s is revoked\n", *secretId)
+		printPropagation(appConfig)
+	case "set-allowlist":
+		_, err := manager.UpdateAllowlist(ctx, managing.UpdateAllowlistParam{
+			ClientId:     *clientId,
+			AllowedCidrs: splitList(*cidrs),
+		})
+		if err != nil {
+			logger.Errorf("Failed update client allowlist: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client %s is allowed from %s\n", *clientId, formatCidrs(splitList(*cidrs)))
+	case "set-rate-limit":
+		reqRate, err := parseRate(*requestRate)
+		if err != nil {
+			logger.Errorf("Invalid request rate: %s", err.Error())
+			os.Exit(2)
+		}
+		bRate, err := parseRate(*byteRate)
+		if err != nil {
+			logger.Errorf("Invalid byte rate: %s", err.Error())
+			os.Exit(2)
+		}
+		_, err = manager.UpdateRateLimit(ctx, managing.UpdateRateLimitParam{
+			ClientId:    *clientId,
+			RequestRate: reqRate,
+			ByteRate:    bRate,
+		})
+		if err != nil {
+			logger.Errorf("Failed update client rate limit: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client %s is limited to %s requests/s and %s bytes/s\n", *clientId, formatRate(reqRate), formatRate(bRate))
+	case "set-quota":
+		_, err := quotaService.UpdateQuota(ctx, quota.UpdateQuotaParam{
+			ClientId: *clientId,
+			MaxSize:  *maxSize,
+			MaxFile:  *maxFile,
+		})
+		if err != nil {
+			logger.Errorf("Failed update client quota: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client %s is limited to %s bytes and %s files\n", *clientId, formatLimit(*maxSize), formatLimit(*maxFile))
+	case "disable":
+		_, err := manager.DisableClient(ctx, managing.DisableClientParam{
+			ClientId: *clientId,
+		})
+		if err != nil {
+			logger.Errorf("Failed disable client: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client %s is disabled\n", *clientId)
+		printPropagation(appConfig)
+	case "delete":
+		_, err := manager.DeleteClient(ctx, managing.DeleteClientParam{
+			ClientId: *clientId,
+		})
+		if err != nil {
+			logger.Errorf("Failed delete client: %s", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("client %s is deleted\n", *clientId)
+		printPropagation(appConfig)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func splitList(values string) []string {
+	res := []string{}
+	for _, value := range strings.Split(values, ",") {
+		if v := strings.TrimSpace(value); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func parseRate(rate string) (*int64, error) {
+	if rate == "" {
+		return nil, nil
+	}
+	res, err := strconv.ParseInt(rate, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func formatRate(rate *int64) string {
+	if rate == nil {
+		return "default"
+	}
+	if *rate == 0 {
+		return "unlimited"
+	}
+	return strconv.FormatInt(*rate, 10)
+}
+
+func formatLimit(limit int64) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return strconv.FormatInt(limit, 10)
+}
+
+func formatCidrs(cidrs []string) string {
+	if len(cidrs) == 0 {
+		return "any"
+	}
+	return strings.Join(cidrs, " ")
+}
+
+func formatTime(t *time.Time, empty string) string {
+	if t == nil {
+		return empty
+	}
+	return t.Format(time.RFC3339)
+}
+
+func printSigningKey(signingKey string) {
+	if signingKey == "" {
+		return
+	}
+	fmt.Printf("signing_key:   %s\n", signingKey)
+}
+
+// the cli can not reach the running server, so the cached credential
+// and the issued access token are still accepted until they expire
+func printPropagation(cfg app.Config) {
+	if cfg.AuthCacheTTL > 0 {
+		fmt.Printf("the running server may accept the cached credential for up to %ds (AUTH_CACHE_TTL)\n", cfg.AuthCacheTTL)
+	}
+	if cfg.OAuthTokenSecret != "" {
+		fmt.Printf("the issued access tokens are valid until they expire, up to %ds (OAUTH_TOKEN_TTL)\n", cfg.OAuthTokenTTL)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-seidon/local/internal/app"
+	"github.com/go-seidon/local/internal/config"
+	"github.com/go-seidon/local/internal/encrypting"
+	"github.com/go-seidon/local/internal/logging"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only print the files wrapped by the old master key")
+	batchSize := flag.Int("batch", encrypting.DEFAULT_BATCH_SIZE, "number of files processed per batch")
+	afterId := flag.String("after", "", "resume after the specified file id")
+	checkpoint := flag.String("checkpoint", "", "file storing the last processed id, used for resuming")
+	flag.Parse()
+
+	appEnv := os.Getenv("APP_ENV")
+	if appEnv == "" {
+		appEnv = "local"
+	}
+
+	appConfig := app.Config{AppEnv: appEnv}
+
+	cfgFileName := fmt.Sprintf("config/%s.toml", appConfig.AppEnv)
+	tomlConfig, err := config.NewViperConfig(
+		config.WithFileName(cfgFileName),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = tomlConfig.LoadConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	err = tomlConfig.ParseConfig(&appConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	logger := logging.NewLogrusLog(
+		logging.WithAppContext(appConfig.AppName, appConfig.AppVersion),
+	)
+
+	repo, err := app.NewRepository(app.WithMySQLRepository(
+		appConfig.MySQLUser, appConfig.MySQLPassword,
+		appConfig.MySQLDBName, appConfig.MySQLHost,
+		appConfig.MySQLPort,
+	))
+	if err != nil {
+		panic(err)
+	}
+
+	keyring, err := app.NewKeyring(app.NewKeyringParam{
+		KeyId:     appConfig.EncryptionKeyId,
+		MasterKey: appConfig.EncryptionMasterKey,
+		Keyfile:   appConfig.EncryptionKeyfile,
+	})
+	if err != nil {
+		panic(err)
+	}
+	if keyring == nil {
+		panic("encryption key id is not specified")
+	}
+
+	storage, err := app.NewStorage(app.NewStorageParam{
+		Provider:      appConfig.StorageProvider,
+		S3Endpoint:    appConfig.S3Endpoint,
+		S3Region:      appConfig.S3Region,
+		S3Bucket:      appConfig.S3Bucket,
+		S3AccessKey:   appConfig.S3AccessKey,
+		S3SecretKey:   appConfig.S3SecretKey,
+		S3VirtualHost: appConfig.S3VirtualHost,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fileManager, err := encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+		FileManager: storage.FileManager,
+		Keyring:     keyring,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	rekeyer, err := encrypting.NewRekeyer(encrypting.NewRekeyerParam{
+		FileRepo:    repo.FileRepo,
+		FileManager: fileManager,
+		Keyring:     keyring,
+		Logger:      logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	lastId := *afterId
+	if lastId == "" && *checkpoint != "" {
+		lastId, err = readCheckpoint(*checkpoint)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	ctx := context.Background()
+	total, rekeyed, skipped, failed := 0, 0, 0, 0
+	for {
+		res, err := rekeyer.RekeyFiles(ctx, encrypting.RekeyFilesParam{
+			AfterId:   lastId,
+			BatchSize: *batchSize,
+			DryRun:    *dryRun,
+		})
+		if err != nil {
+			logger.Errorf("Failed re-key files after %q: %s", lastId, err.Error())
+			os.Exit(1)
+		}
+
+		lastId = res.LastId
+		total += res.Total
+		rekeyed += res.Rekeyed
+		skipped += res.Skipped
+		failed += res.Failed
+		logger.Infof(
+			"Progress: %d processed, %d re-keyed, %d skipped, %d failed, last id: %s",
+			total, rekeyed, skipped, failed, lastId,
+		)
+
+		if *checkpoint != "" && !*dryRun {
+			err := os.WriteFile(*checkpoint, []byte(lastId), 0644)
+			if err != nil {
+				logger.Errorf("Failed write checkpoint: %s", err.Error())
+				os.Exit(1)
+			}
+		}
+
+		if !res.HasMore {
+			break
+		}
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func readCheckpoint(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
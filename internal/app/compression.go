@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/compressing"
+	"github.com/go-seidon/local/internal/filesystem"
+)
+
+type NewCompressionParam struct {
+	FileManager filesystem.FileManager
+	Codec       string
+	Mimetypes   []string
+	MinSize     int64
+}
+
+type NewCompressionResult struct {
+	FileManager filesystem.FileManager
+	Policy      compressing.CompressionPolicy
+}
+
+// file manager is always wrapped so the files compressed earlier
+// are still readable after the compression is disabled
+func NewCompression(p NewCompressionParam) (*NewCompressionResult, error) {
+	gzipCodec, err := compressing.NewGzipCodec(compressing.NewGzipCodecParam{})
+	if err != nil {
+		return nil, err
+	}
+
+	fileManager, err := compressing.NewCompressedFileManager(compressing.NewCompressedFileManagerParam{
+		FileManager: p.FileManager,
+		Codecs:      []compressing.Codec{gzipCodec},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &NewCompressionResult{
+		FileManager: fileManager,
+	}
+	if p.Codec == "" {
+		return res, nil
+	}
+
+	if p.Codec != compressing.CODEC_GZIP {
+		return nil, fmt.Errorf("compression codec is not supported")
+	}
+
+	policy, err := compressing.NewCompressionPolicy(compressing.NewCompressionPolicyParam{
+		Codec:     p.Codec,
+		Mimetypes: p.Mimetypes,
+		MinSize:   p.MinSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Policy = policy
+	return res, nil
+}
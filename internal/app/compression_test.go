@@ -0,0 +1,78 @@
+package app_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/app"
+	"github.com/go-seidon/local/internal/filesystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compression Package", func() {
+	Context("NewCompression function", Label("unit"), func() {
+		var (
+			p app.NewCompressionParam
+		)
+
+		BeforeEach(func() {
+			p = app.NewCompressionParam{
+				FileManager: filesystem.NewFileManager(),
+				Codec:       "gzip",
+			}
+		})
+
+		When("codec is not specified", func() {
+			It("should return file manager without policy", func() {
+				p.Codec = ""
+				res, err := app.NewCompression(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.FileManager).ToNot(BeNil())
+				Expect(res.Policy).To(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file manager is not specified", func() {
+			It("should return error", func() {
+				p.FileManager = nil
+				res, err := app.NewCompression(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file manager is not specified")))
+			})
+		})
+
+		When("codec is not supported", func() {
+			It("should return error", func() {
+				p.Codec = "zstd"
+				res, err := app.NewCompression(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("compression codec is not supported")))
+			})
+		})
+
+		When("min size is invalid", func() {
+			It("should return error", func() {
+				p.MinSize = -1
+				res, err := app.NewCompression(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid min size")))
+			})
+		})
+
+		When("codec is gzip", func() {
+			It("should return result", func() {
+				res, err := app.NewCompression(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.FileManager).ToNot(BeNil())
+				Expect(res.Policy).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
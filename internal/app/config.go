@@ -20,6 +20,104 @@ type Config struct {
 	MySQLPassword string `env:"MYSQL_PASSWORD"`
 	MySQLDBName   string `env:"MYSQL_DB_NAME"`
 
-	UploadFormSize  int64  `env:"UPLOAD_FORM_SIZE"`
-	UploadDirectory string `env:"UPLOAD_DIRECTORY"`
+	StorageProvider string `env:"STORAGE_PROVIDER"`
+
+	S3Endpoint    string `env:"S3_ENDPOINT"`
+	S3Region      string `env:"S3_REGION"`
+	S3Bucket      string `env:"S3_BUCKET"`
+	S3AccessKey   string `env:"S3_ACCESS_KEY"`
+	S3SecretKey   string `env:"S3_SECRET_KEY"`
+	S3VirtualHost bool   `env:"S3_VIRTUAL_HOST"`
+
+	OAuthTokenSecret string `env:"OAUTH_TOKEN_SECRET"`
+	OAuthTokenIssuer string `env:"OAUTH_TOKEN_ISSUER"`
+	OAuthTokenTTL    int    `env:"OAUTH_TOKEN_TTL"`
+
+	AuthCacheSize int `env:"AUTH_CACHE_SIZE"`
+	AuthCacheTTL  int `env:"AUTH_CACHE_TTL"`
+
+	AuthLockoutClientThreshold int `env:"AUTH_LOCKOUT_CLIENT_THRESHOLD"`
+	AuthLockoutIpThreshold     int `env:"AUTH_LOCKOUT_IP_THRESHOLD"`
+	AuthLockoutBaseDuration    int `env:"AUTH_LOCKOUT_BASE_DURATION"`
+	AuthLockoutMaxDuration     int `env:"AUTH_LOCKOUT_MAX_DURATION"`
+	AuthLockoutResetAfter      int `env:"AUTH_LOCKOUT_RESET_AFTER"`
+
+	AuthSignatureKey  string `env:"AUTH_SIGNATURE_KEY"`
+	AuthSignatureSkew int    `env:"AUTH_SIGNATURE_SKEW"`
+
+	AuthLegacyFileAccess bool `env:"AUTH_LEGACY_FILE_ACCESS"`
+
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+
+	RateLimitRequestRate int64 `env:"RATE_LIMIT_REQUEST_RATE"`
+	RateLimitByteRate    int64 `env:"RATE_LIMIT_BYTE_RATE"`
+	RateLimitMaxInFlight int64 `env:"RATE_LIMIT_MAX_IN_FLIGHT"`
+	RateLimitMaxStream   int64 `env:"RATE_LIMIT_MAX_STREAM"`
+	RateLimitBurstPeriod int   `env:"RATE_LIMIT_BURST_PERIOD"`
+	RateLimitPolicyTTL   int   `env:"RATE_LIMIT_POLICY_TTL"`
+
+	WebhookDispatchInterval    int  `env:"WEBHOOK_DISPATCH_INTERVAL"`
+	WebhookBatchSize           int  `env:"WEBHOOK_BATCH_SIZE"`
+	WebhookMaxAttempts         int  `env:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookBackoffBase         int  `env:"WEBHOOK_BACKOFF_BASE"`
+	WebhookBackoffMax          int  `env:"WEBHOOK_BACKOFF_MAX"`
+	WebhookTimeout             int  `env:"WEBHOOK_TIMEOUT"`
+	WebhookAllowPrivateNetwork bool `env:"WEBHOOK_ALLOW_PRIVATE_NETWORK"`
+
+	OutboxSinks         []string `env:"OUTBOX_SINKS"`
+	OutboxRelayInterval int      `env:"OUTBOX_RELAY_INTERVAL"`
+	OutboxBatchSize     int      `env:"OUTBOX_BATCH_SIZE"`
+	OutboxBackoffBase   int      `env:"OUTBOX_BACKOFF_BASE"`
+	OutboxBackoffMax    int      `env:"OUTBOX_BACKOFF_MAX"`
+	OutboxRetention     int      `env:"OUTBOX_RETENTION"`
+
+	EventStreamInterval  int `env:"EVENT_STREAM_INTERVAL"`
+	EventStreamBatchSize int `env:"EVENT_STREAM_BATCH_SIZE"`
+	EventStreamHeartbeat int `env:"EVENT_STREAM_HEARTBEAT"`
+
+	HashAlgorithm         string `env:"HASH_ALGORITHM"`
+	HashBcryptCost        int    `env:"HASH_BCRYPT_COST"`
+	HashArgon2Memory      uint32 `env:"HASH_ARGON2_MEMORY"`
+	HashArgon2Iterations  uint32 `env:"HASH_ARGON2_ITERATIONS"`
+	HashArgon2Parallelism uint8  `env:"HASH_ARGON2_PARALLELISM"`
+
+	EncryptionKeyId     string `env:"ENCRYPTION_KEY_ID"`
+	EncryptionMasterKey string `env:"ENCRYPTION_MASTER_KEY"`
+	EncryptionKeyfile   string `env:"ENCRYPTION_KEYFILE"`
+
+	CompressionCodec    string   `env:"COMPRESSION_CODEC"`
+	CompressionMimetype []string `env:"COMPRESSION_MIMETYPE"`
+	CompressionMinSize  int64    `env:"COMPRESSION_MIN_SIZE"`
+
+	UploadFormSize   int64  `env:"UPLOAD_FORM_SIZE"`
+	UploadDirectory  string `env:"UPLOAD_DIRECTORY"`
+	UploadExifPolicy string `env:"UPLOAD_EXIF_POLICY"`
+	UploadVisibility string `env:"UPLOAD_VISIBILITY"`
+	UploadMaxPixels  int64  `env:"UPLOAD_MAX_PIXELS"`
+
+	UploadLocation         string `env:"UPLOAD_LOCATION"`
+	UploadLocationTemplate string `env:"UPLOAD_LOCATION_TEMPLATE"`
+
+	UploadPolicyAllowedMimetype []string             `env:"UPLOAD_POLICY_ALLOWED_MIMETYPE"`
+	UploadPolicyMimetypeSize    []UploadMimetypeSize `env:"UPLOAD_POLICY_MIMETYPE_SIZE"`
+	UploadPolicyClientSize      []UploadClientSize   `env:"UPLOAD_POLICY_CLIENT_SIZE"`
+	UploadPolicyCheckExtension  bool                 `env:"UPLOAD_POLICY_CHECK_EXTENSION"`
+
+	ScanProvider            string `env:"SCAN_PROVIDER"`
+	ScanFailure             string `env:"SCAN_FAILURE"`
+	ScanQuarantineDirectory string `env:"SCAN_QUARANTINE_DIRECTORY"`
+
+	ClamavNetwork string `env:"CLAMAV_NETWORK"`
+	ClamavAddress string `env:"CLAMAV_ADDRESS"`
+	ClamavTimeout int    `env:"CLAMAV_TIMEOUT"`
+}
+
+type UploadMimetypeSize struct {
+	Mimetype string `env:"mimetype"`
+	MaxSize  int64  `env:"max_size"`
+}
+
+type UploadClientSize struct {
+	ClientId string `env:"client_id"`
+	MaxSize  int64  `env:"max_size"`
 }
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/go-seidon/local/internal/encrypting"
+)
+
+type NewKeyringParam struct {
+	KeyId     string
+	MasterKey string
+	Keyfile   string
+}
+
+func NewKeyring(p NewKeyringParam) (encrypting.Keyring, error) {
+	if p.KeyId == "" {
+		return nil, nil
+	}
+
+	keys := map[string][]byte{}
+	if p.Keyfile != "" {
+		file, err := os.Open(p.Keyfile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		keys, err = encrypting.ParseKeyfile(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.MasterKey != "" {
+		key, err := base64.StdEncoding.DecodeString(p.MasterKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key encoding")
+		}
+		keys[p.KeyId] = key
+	}
+
+	keyring, err := encrypting.NewKeyring(encrypting.NewKeyringParam{
+		CurrentKeyId: p.KeyId,
+		Keys:         keys,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
@@ -0,0 +1,99 @@
+package app_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-seidon/local/internal/app"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encryption Package", func() {
+	Context("NewKeyring function", Label("unit"), func() {
+		var (
+			masterKey string
+		)
+
+		BeforeEach(func() {
+			// base64 of 32 bytes key
+			masterKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+		})
+
+		When("key id is not specified", func() {
+			It("should return nil keyring", func() {
+				res, err := app.NewKeyring(app.NewKeyringParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("master key is specified", func() {
+			It("should return result", func() {
+				res, err := app.NewKeyring(app.NewKeyringParam{
+					KeyId:     "key-1",
+					MasterKey: masterKey,
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.CurrentKeyId()).To(Equal("key-1"))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("master key encoding is invalid", func() {
+			It("should return error", func() {
+				res, err := app.NewKeyring(app.NewKeyringParam{
+					KeyId:     "key-1",
+					MasterKey: "%invalid%",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid master key encoding")))
+			})
+		})
+
+		When("keyfile is specified", func() {
+			It("should return result", func() {
+				keyfile := filepath.Join(GinkgoT().TempDir(), "keyfile")
+				data := "# rotated on 2022-08-12\nkey-1:" + masterKey + "\nkey-2:" + masterKey + "\n"
+				err := os.WriteFile(keyfile, []byte(data), 0600)
+				Expect(err).To(BeNil())
+
+				res, err := app.NewKeyring(app.NewKeyringParam{
+					KeyId:   "key-2",
+					Keyfile: keyfile,
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.CurrentKeyId()).To(Equal("key-2"))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("keyfile is unavailable", func() {
+			It("should return error", func() {
+				res, err := app.NewKeyring(app.NewKeyringParam{
+					KeyId:   "key-1",
+					Keyfile: filepath.Join(GinkgoT().TempDir(), "unavailable"),
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("current key is not available", func() {
+			It("should return error", func() {
+				res, err := app.NewKeyring(app.NewKeyringParam{
+					KeyId: "key-1",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("current master key is not specified")))
+			})
+		})
+	})
+})
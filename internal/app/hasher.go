@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/hashing"
+)
+
+const (
+	HASH_ALGORITHM_BCRYPT   = "bcrypt"
+	HASH_ALGORITHM_ARGON2ID = "argon2id"
+)
+
+type NewHasherParam struct {
+	Algorithm string
+
+	BcryptCost int
+
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+// every hasher is able to verify hash generated by the other algorithm,
+// so the algorithm is changeable without invalidating the stored secret
+func NewHasher(p NewHasherParam) (hashing.Hasher, error) {
+	if p.Algorithm == "" || p.Algorithm == HASH_ALGORITHM_BCRYPT {
+		return hashing.NewBcryptHasher(hashing.WithBcryptCost(p.BcryptCost)), nil
+	}
+
+	if p.Algorithm == HASH_ALGORITHM_ARGON2ID {
+		hasher, err := hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+			Memory:      p.Argon2Memory,
+			Iterations:  p.Argon2Iterations,
+			Parallelism: p.Argon2Parallelism,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return hasher, nil
+	}
+
+	return nil, fmt.Errorf("hash algorithm is not supported")
+}
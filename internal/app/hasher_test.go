@@ -0,0 +1,57 @@
+package app_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/app"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hasher Package", func() {
+	Context("NewHasher function", Label("unit"), func() {
+		When("algorithm is not specified", func() {
+			It("should return bcrypt hasher", func() {
+				res, err := app.NewHasher(app.NewHasherParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("algorithm is not supported", func() {
+			It("should return error", func() {
+				res, err := app.NewHasher(app.NewHasherParam{
+					Algorithm: "md5",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("hash algorithm is not supported")))
+			})
+		})
+
+		When("argon2id param is invalid", func() {
+			It("should return error", func() {
+				res, err := app.NewHasher(app.NewHasherParam{
+					Algorithm:         app.HASH_ALGORITHM_ARGON2ID,
+					Argon2Memory:      8,
+					Argon2Parallelism: 2,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("memory must be at least 16 KiB")))
+			})
+		})
+
+		When("success create argon2id hasher", func() {
+			It("should return result", func() {
+				res, err := app.NewHasher(app.NewHasherParam{
+					Algorithm: app.HASH_ALGORITHM_ARGON2ID,
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/uploading"
+)
+
+type NewUploadLocationParam struct {
+	Strategy string
+	Template string
+}
+
+func NewUploadLocation(p NewUploadLocationParam) (uploading.UploadLocation, error) {
+	switch p.Strategy {
+	case "", uploading.LOCATION_DAILY:
+		return uploading.NewDailyRotate(uploading.NewDailyRotateParam{}), nil
+	case uploading.LOCATION_HOURLY:
+		return uploading.NewHourlyRotate(uploading.NewHourlyRotateParam{}), nil
+	case uploading.LOCATION_HASH:
+		location, err := uploading.NewHashPrefix(uploading.NewHashPrefixParam{})
+		if err != nil {
+			return nil, err
+		}
+		return location, nil
+	case uploading.LOCATION_CLIENT:
+		return uploading.NewClientPrefix(uploading.NewClientPrefixParam{}), nil
+	case uploading.LOCATION_TEMPLATE:
+		location, err := uploading.NewTemplateLocation(uploading.NewTemplateLocationParam{
+			Template: p.Template,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return location, nil
+	}
+	return nil, fmt.Errorf("upload location is not supported")
+}
@@ -0,0 +1,76 @@
+package app_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/app"
+	"github.com/go-seidon/local/internal/uploading"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Location Package", func() {
+	Context("NewUploadLocation function", Label("unit"), func() {
+		When("strategy is not specified", func() {
+			It("should return daily rotate", func() {
+				res, err := app.NewUploadLocation(app.NewUploadLocationParam{})
+
+				Expect(res).To(Equal(uploading.NewDailyRotate(uploading.NewDailyRotateParam{})))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("strategy is supported", func() {
+			It("should return result", func() {
+				strategies := []string{
+					uploading.LOCATION_DAILY,
+					uploading.LOCATION_HOURLY,
+					uploading.LOCATION_HASH,
+					uploading.LOCATION_CLIENT,
+				}
+				for _, strategy := range strategies {
+					res, err := app.NewUploadLocation(app.NewUploadLocationParam{
+						Strategy: strategy,
+					})
+
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+				}
+			})
+		})
+
+		When("template is specified", func() {
+			It("should return result", func() {
+				res, err := app.NewUploadLocation(app.NewUploadLocationParam{
+					Strategy: uploading.LOCATION_TEMPLATE,
+					Template: "{client}/{yyyy}/{mm}",
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("template is invalid", func() {
+			It("should return error", func() {
+				res, err := app.NewUploadLocation(app.NewUploadLocationParam{
+					Strategy: uploading.LOCATION_TEMPLATE,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("template is not specified")))
+			})
+		})
+
+		When("strategy is not supported", func() {
+			It("should return error", func() {
+				res, err := app.NewUploadLocation(app.NewUploadLocationParam{
+					Strategy: "weekly",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("upload location is not supported")))
+			})
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/serialization"
+)
+
+type NewOutboxSinksParam struct {
+	Sinks []string
+
+	Publisher  notifying.Publisher
+	Broker     notifying.Broker
+	Serializer serialization.Serializer
+}
+
+func NewOutboxSinks(p NewOutboxSinksParam) ([]notifying.Sink, error) {
+	names := p.Sinks
+	if len(names) == 0 {
+		names = []string{notifying.SINK_WEBHOOK}
+	}
+
+	sinks := []notifying.Sink{}
+	added := map[string]bool{}
+	for _, name := range names {
+		if added[name] {
+			return nil, fmt.Errorf("outbox sink is duplicated")
+		}
+		added[name] = true
+
+		switch name {
+		case notifying.SINK_WEBHOOK:
+			sink, err := notifying.NewWebhookSink(p.Publisher)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case notifying.SINK_LOG:
+			sink, err := notifying.NewLogSink(notifying.NewLogSinkParam{
+				Serializer: p.Serializer,
+			})
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case notifying.SINK_BROKER:
+			if p.Broker == nil {
+				return nil, fmt.Errorf("broker is not specified")
+			}
+			sinks = append(sinks, p.Broker)
+		default:
+			return nil, fmt.Errorf("outbox sink is not supported")
+		}
+	}
+	return sinks, nil
+}
@@ -0,0 +1,104 @@
+package app_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/app"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/serialization"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outbox Package", func() {
+	Context("NewOutboxSinks function", Label("unit"), func() {
+		var (
+			p app.NewOutboxSinksParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = app.NewOutboxSinksParam{
+				Publisher:  mock.NewMockPublisher(ctrl),
+				Broker:     mock.NewMockBroker(ctrl),
+				Serializer: serialization.NewJsonSerializer(),
+			}
+		})
+
+		When("sink is not specified", func() {
+			It("should return webhook sink", func() {
+				res, err := app.NewOutboxSinks(p)
+
+				Expect(len(res)).To(Equal(1))
+				Expect(res[0].Name()).To(Equal("webhook"))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("all sinks are specified", func() {
+			It("should return the sinks in order", func() {
+				p.Sinks = []string{"log", "broker", "webhook"}
+				res, err := app.NewOutboxSinks(p)
+
+				Expect(len(res)).To(Equal(3))
+				Expect(res[0].Name()).To(Equal("log"))
+				Expect(res[1]).To(Equal(p.Broker))
+				Expect(res[2].Name()).To(Equal("webhook"))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("sink is not supported", func() {
+			It("should return error", func() {
+				p.Sinks = []string{"kafka"}
+				res, err := app.NewOutboxSinks(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("outbox sink is not supported")))
+			})
+		})
+
+		When("sink is duplicated", func() {
+			It("should return error", func() {
+				p.Sinks = []string{"log", "log"}
+				res, err := app.NewOutboxSinks(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("outbox sink is duplicated")))
+			})
+		})
+
+		When("publisher is not specified", func() {
+			It("should return error", func() {
+				p.Publisher = nil
+				res, err := app.NewOutboxSinks(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("publisher is not specified")))
+			})
+		})
+
+		When("serializer is not specified", func() {
+			It("should return error", func() {
+				p.Sinks = []string{"log"}
+				p.Serializer = nil
+				res, err := app.NewOutboxSinks(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("serializer is not specified")))
+			})
+		})
+
+		When("broker is not specified", func() {
+			It("should return error", func() {
+				p.Sinks = []string{"broker"}
+				p.Broker = nil
+				res, err := app.NewOutboxSinks(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("broker is not specified")))
+			})
+		})
+	})
+})
@@ -54,9 +54,49 @@ func newMySQLRepository(p NewRepositoryOption) (*NewRepositoryResult, error) {
 		return nil, err
 	}
 
+	quotaRepo, err := repository_mysql.NewQuotaRepository(
+		repository_mysql.WithDbClient(client),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	shareRepo, err := repository_mysql.NewShareRepository(
+		repository_mysql.WithDbClient(client),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRepo, err := repository_mysql.NewAuditRepository(
+		repository_mysql.WithDbClient(client),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookRepo, err := repository_mysql.NewWebhookRepository(
+		repository_mysql.WithDbClient(client),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRepo, err := repository_mysql.NewOutboxRepository(
+		repository_mysql.WithDbClient(client),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &NewRepositoryResult{
-		FileRepo:  fileRepo,
-		OAuthRepo: oauthRepo,
+		FileRepo:    fileRepo,
+		OAuthRepo:   oauthRepo,
+		QuotaRepo:   quotaRepo,
+		ShareRepo:   shareRepo,
+		AuditRepo:   auditRepo,
+		WebhookRepo: webhookRepo,
+		OutboxRepo:  outboxRepo,
 	}
 	return r, nil
 }
@@ -76,8 +116,13 @@ type NewRepositoryOption struct {
 }
 
 type NewRepositoryResult struct {
-	FileRepo  repository.FileRepository
-	OAuthRepo repository.OAuthRepository
+	FileRepo    repository.FileRepository
+	OAuthRepo   repository.OAuthRepository
+	QuotaRepo   repository.QuotaRepository
+	ShareRepo   repository.ShareRepository
+	AuditRepo   repository.AuditRepository
+	WebhookRepo repository.WebhookRepository
+	OutboxRepo  repository.OutboxRepository
 }
 
 type mysqlRepositoryOption struct {
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/scanning"
+)
+
+const (
+	SCAN_PROVIDER_NOOP   = "noop"
+	SCAN_PROVIDER_CLAMAV = "clamav"
+)
+
+type NewScannerParam struct {
+	Provider string
+
+	ClamavNetwork string
+	ClamavAddress string
+	ClamavTimeout time.Duration
+}
+
+func NewScanner(p NewScannerParam) (scanning.Scanner, error) {
+	if p.Provider == "" || p.Provider == SCAN_PROVIDER_NOOP {
+		return scanning.NewNoopScanner(scanning.NewNoopScannerParam{}), nil
+	}
+
+	if p.Provider == SCAN_PROVIDER_CLAMAV {
+		scanner, err := scanning.NewClamavScanner(scanning.NewClamavScannerParam{
+			Network: p.ClamavNetwork,
+			Address: p.ClamavAddress,
+			Timeout: p.ClamavTimeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return scanner, nil
+	}
+
+	return nil, fmt.Errorf("scan provider is not supported")
+}
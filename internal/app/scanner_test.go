@@ -0,0 +1,57 @@
+package app_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/app"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scanner Package", func() {
+	Context("NewScanner function", Label("unit"), func() {
+		When("provider is not specified", func() {
+			It("should return noop scanner", func() {
+				res, err := app.NewScanner(app.NewScannerParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("provider is not supported", func() {
+			It("should return error", func() {
+				res, err := app.NewScanner(app.NewScannerParam{
+					Provider: "invalid",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("scan provider is not supported")))
+			})
+		})
+
+		When("clamav param is invalid", func() {
+			It("should return error", func() {
+				res, err := app.NewScanner(app.NewScannerParam{
+					Provider: app.SCAN_PROVIDER_CLAMAV,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("address is not specified")))
+			})
+		})
+
+		When("success create clamav scanner", func() {
+			It("should return result", func() {
+				res, err := app.NewScanner(app.NewScannerParam{
+					Provider:      app.SCAN_PROVIDER_CLAMAV,
+					ClamavNetwork: "tcp",
+					ClamavAddress: "localhost:3310",
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/filesystem"
+)
+
+const (
+	STORAGE_PROVIDER_LOCAL = "local"
+	STORAGE_PROVIDER_S3    = "s3"
+)
+
+type NewStorageParam struct {
+	Provider string
+
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3VirtualHost bool
+}
+
+type NewStorageResult struct {
+	FileManager filesystem.FileManager
+	DirManager  filesystem.DirectoryManager
+}
+
+func NewStorage(p NewStorageParam) (*NewStorageResult, error) {
+	if p.Provider == "" || p.Provider == STORAGE_PROVIDER_LOCAL {
+		res := &NewStorageResult{
+			FileManager: filesystem.NewFileManager(),
+			DirManager:  filesystem.NewDirectoryManager(),
+		}
+		return res, nil
+	}
+
+	if p.Provider == STORAGE_PROVIDER_S3 {
+		fileManager, err := filesystem.NewS3FileManager(filesystem.NewS3FileManagerParam{
+			Endpoint:    p.S3Endpoint,
+			Region:      p.S3Region,
+			Bucket:      p.S3Bucket,
+			AccessKey:   p.S3AccessKey,
+			SecretKey:   p.S3SecretKey,
+			VirtualHost: p.S3VirtualHost,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		res := &NewStorageResult{
+			FileManager: fileManager,
+			DirManager:  filesystem.NewObjectDirectoryManager(),
+		}
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("storage provider is not supported")
+}
@@ -0,0 +1,64 @@
+package app_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/local/internal/app"
+	"github.com/go-seidon/local/internal/filesystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage Package", func() {
+	Context("NewStorage function", Label("unit"), func() {
+		When("provider is not specified", func() {
+			It("should return local storage", func() {
+				res, err := app.NewStorage(app.NewStorageParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.FileManager).To(Equal(filesystem.NewFileManager()))
+				Expect(res.DirManager).To(Equal(filesystem.NewDirectoryManager()))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("provider is s3", func() {
+			It("should return object storage", func() {
+				res, err := app.NewStorage(app.NewStorageParam{
+					Provider:    app.STORAGE_PROVIDER_S3,
+					S3Endpoint:  "http://localhost:9000",
+					S3Bucket:    "mock-bucket",
+					S3AccessKey: "mock-access-key",
+					S3SecretKey: "mock-secret-key",
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.DirManager).To(Equal(filesystem.NewObjectDirectoryManager()))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("s3 config is invalid", func() {
+			It("should return error", func() {
+				res, err := app.NewStorage(app.NewStorageParam{
+					Provider:   app.STORAGE_PROVIDER_S3,
+					S3Endpoint: "http://localhost:9000",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("bucket is not specified")))
+			})
+		})
+
+		When("provider is not supported", func() {
+			It("should return error", func() {
+				res, err := app.NewStorage(app.NewStorageParam{
+					Provider: "gcs",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("storage provider is not supported")))
+			})
+		})
+	})
+})
@@ -0,0 +1,223 @@
+package auditing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/text"
+)
+
+const (
+	ACTION_UPLOAD       = "upload"
+	ACTION_RETRIEVE     = "retrieve"
+	ACTION_DELETE       = "delete"
+	ACTION_RESTORE      = "restore"
+	ACTION_AUTH_FAILURE = "auth_failure"
+
+	OUTCOME_SUCCESS = "success"
+	OUTCOME_FAILURE = "failure"
+	OUTCOME_DENIED  = "denied"
+
+	DEFAULT_LIST_LIMIT = 100
+	MAX_LIST_LIMIT     = 1000
+	MAX_REASON_SIZE    = 256
+)
+
+type Auditor interface {
+	Record(ctx context.Context, p RecordParam) (*RecordResult, error)
+	ListLog(ctx context.Context, p ListLogParam) (*ListLogResult, error)
+}
+
+type RecordParam struct {
+	ClientId  string
+	Action    string
+	FileId    string
+	IpAddress string
+	RequestId string
+	Outcome   string
+	Reason    string
+}
+
+type RecordResult struct {
+	Id        string
+	CreatedAt time.Time
+}
+
+type ListLogParam struct {
+	ClientId    string
+	Action      string
+	FileId      string
+	Outcome     string
+	RequestId   string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	AfterId     string
+	Limit       int
+}
+
+type ListLogResult struct {
+	Items []LogItem
+}
+
+type LogItem struct {
+	Id        string
+	ClientId  string
+	Action    string
+	FileId    string
+	IpAddress string
+	RequestId string
+	Outcome   string
+	Reason    string
+	CreatedAt time.Time
+}
+
+type auditor struct {
+	auditRepo  repository.AuditRepository
+	identifier text.Identifier
+	log        logging.Logger
+}
+
+func (a *auditor) Record(ctx context.Context, p RecordParam) (*RecordResult, error) {
+	a.log.Debug("In function: Record")
+	defer a.log.Debug("Returning function: Record")
+
+	if !IsValidAction(p.Action) {
+		return nil, fmt.Errorf("invalid action parameter")
+	}
+	if !IsValidOutcome(p.Outcome) {
+		return nil, fmt.Errorf("invalid outcome parameter")
+	}
+
+	id, err := a.identifier.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+
+	reason := p.Reason
+	if len(reason) > MAX_REASON_SIZE {
+		reason = reason[:MAX_REASON_SIZE]
+	}
+
+	log, err := a.auditRepo.CreateAuditLog(ctx, repository.CreateAuditLogParam{
+		Id:        id,
+		ClientId:  p.ClientId,
+		Action:    p.Action,
+		FileId:    p.FileId,
+		IpAddress: p.IpAddress,
+		RequestId: p.RequestId,
+		Outcome:   p.Outcome,
+		Reason:    reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RecordResult{
+		Id:        id,
+		CreatedAt: log.CreatedAt,
+	}
+	return res, nil
+}
+
+func (a *auditor) ListLog(ctx context.Context, p ListLogParam) (*ListLogResult, error) {
+	a.log.Debug("In function: ListLog")
+	defer a.log.Debug("Returning function: ListLog")
+
+	if p.Action != "" && !IsValidAction(p.Action) {
+		return nil, fmt.Errorf("invalid action parameter")
+	}
+	if p.Outcome != "" && !IsValidOutcome(p.Outcome) {
+		return nil, fmt.Errorf("invalid outcome parameter")
+	}
+	if p.CreatedFrom != nil && p.CreatedTo != nil && !p.CreatedFrom.Before(*p.CreatedTo) {
+		return nil, fmt.Errorf("invalid created time parameter")
+	}
+	if p.Limit < 0 || p.Limit > MAX_LIST_LIMIT {
+		return nil, fmt.Errorf("invalid limit parameter")
+	}
+	limit := DEFAULT_LIST_LIMIT
+	if p.Limit > 0 {
+		limit = p.Limit
+	}
+
+	logs, err := a.auditRepo.ListAuditLog(ctx, repository.ListAuditLogParam{
+		ClientId:    p.ClientId,
+		Action:      p.Action,
+		FileId:      p.FileId,
+		Outcome:     p.Outcome,
+		RequestId:   p.RequestId,
+		CreatedFrom: p.CreatedFrom,
+		CreatedTo:   p.CreatedTo,
+		AfterId:     p.AfterId,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []LogItem{}
+	for _, log := range logs.Items {
+		items = append(items, LogItem{
+			Id:        log.Id,
+			ClientId:  log.ClientId,
+			Action:    log.Action,
+			FileId:    log.FileId,
+			IpAddress: log.IpAddress,
+			RequestId: log.RequestId,
+			Outcome:   log.Outcome,
+			Reason:    log.Reason,
+			CreatedAt: log.CreatedAt,
+		})
+	}
+
+	res := &ListLogResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func IsValidAction(action string) bool {
+	switch action {
+	case ACTION_UPLOAD, ACTION_RETRIEVE, ACTION_DELETE, ACTION_RESTORE, ACTION_AUTH_FAILURE:
+		return true
+	}
+	return false
+}
+
+func IsValidOutcome(outcome string) bool {
+	switch outcome {
+	case OUTCOME_SUCCESS, OUTCOME_FAILURE, OUTCOME_DENIED:
+		return true
+	}
+	return false
+}
+
+type NewAuditorParam struct {
+	AuditRepo  repository.AuditRepository
+	Logger     logging.Logger
+	Identifier text.Identifier
+}
+
+func NewAuditor(p NewAuditorParam) (*auditor, error) {
+	if p.AuditRepo == nil {
+		return nil, fmt.Errorf("audit repo is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+
+	identifier := p.Identifier
+	if identifier == nil {
+		identifier = text.NewKsuid()
+	}
+
+	a := &auditor{
+		auditRepo:  p.AuditRepo,
+		identifier: identifier,
+		log:        p.Logger,
+	}
+	return a, nil
+}
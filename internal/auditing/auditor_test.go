@@ -0,0 +1,365 @@
+package auditing_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-seidon/local/internal/auditing"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAuditing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auditing Package")
+}
+
+var _ = Describe("Auditor Service", func() {
+	Context("NewAuditor function", Label("unit"), func() {
+		var (
+			p auditing.NewAuditorParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = auditing.NewAuditorParam{
+				AuditRepo: mock.NewMockAuditRepository(ctrl),
+				Logger:    mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := auditing.NewAuditor(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("identifier is specified", func() {
+			It("should return result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				p.Identifier = mock.NewMockIdentifier(ctrl)
+				res, err := auditing.NewAuditor(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("audit repo is not specified", func() {
+			It("should return error", func() {
+				p.AuditRepo = nil
+				res, err := auditing.NewAuditor(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("audit repo is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := auditing.NewAuditor(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+	})
+
+	Context("Record function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			auditRepo   *mock.MockAuditRepository
+			identifier  *mock.MockIdentifier
+			log         *mock.MockLogger
+			a           auditing.Auditor
+			p           auditing.RecordParam
+			createParam repository.CreateAuditLogParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			auditRepo = mock.NewMockAuditRepository(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			a, _ = auditing.NewAuditor(auditing.NewAuditorParam{
+				AuditRepo:  auditRepo,
+				Identifier: identifier,
+				Logger:     log,
+			})
+			p = auditing.RecordParam{
+				ClientId:  "client-id",
+				Action:    auditing.ACTION_DELETE,
+				FileId:    "file-id",
+				IpAddress: "10.0.0.1",
+				RequestId: "request-id",
+				Outcome:   auditing.OUTCOME_SUCCESS,
+			}
+			createParam = repository.CreateAuditLogParam{
+				Id:        "audit-id",
+				ClientId:  "client-id",
+				Action:    auditing.ACTION_DELETE,
+				FileId:    "file-id",
+				IpAddress: "10.0.0.1",
+				RequestId: "request-id",
+				Outcome:   auditing.OUTCOME_SUCCESS,
+			}
+
+			log.EXPECT().Debug("In function: Record").Times(1)
+			log.EXPECT().Debug("Returning function: Record").Times(1)
+		})
+
+		When("action is invalid", func() {
+			It("should return error", func() {
+				p.Action = "invalid"
+				res, err := a.Record(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid action parameter")))
+			})
+		})
+
+		When("outcome is invalid", func() {
+			It("should return error", func() {
+				p.Outcome = ""
+				res, err := a.Record(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid outcome parameter")))
+			})
+		})
+
+		When("failed generate id", func() {
+			It("should return error", func() {
+				identifier.EXPECT().GenerateId().Return("", fmt.Errorf("id error")).Times(1)
+
+				res, err := a.Record(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("id error")))
+			})
+		})
+
+		When("failed create audit log", func() {
+			It("should return error", func() {
+				identifier.EXPECT().GenerateId().Return("audit-id", nil).Times(1)
+				auditRepo.EXPECT().
+					CreateAuditLog(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := a.Record(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("reason exceeds the maximum size", func() {
+			It("should truncate the reason", func() {
+				p.Outcome = auditing.OUTCOME_FAILURE
+				p.Reason = strings.Repeat("a", 300)
+				createParam.Outcome = auditing.OUTCOME_FAILURE
+				createParam.Reason = strings.Repeat("a", 256)
+				identifier.EXPECT().GenerateId().Return("audit-id", nil).Times(1)
+				auditRepo.EXPECT().
+					CreateAuditLog(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateAuditLogResult{CreatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := a.Record(ctx, p)
+
+				Expect(res).To(Equal(&auditing.RecordResult{
+					Id:        "audit-id",
+					CreatedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success record audit log", func() {
+			It("should return result", func() {
+				identifier.EXPECT().GenerateId().Return("audit-id", nil).Times(1)
+				auditRepo.EXPECT().
+					CreateAuditLog(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateAuditLogResult{CreatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := a.Record(ctx, p)
+
+				Expect(res).To(Equal(&auditing.RecordResult{
+					Id:        "audit-id",
+					CreatedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListLog function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			auditRepo *mock.MockAuditRepository
+			log       *mock.MockLogger
+			a         auditing.Auditor
+			p         auditing.ListLogParam
+			listParam repository.ListAuditLogParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			auditRepo = mock.NewMockAuditRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			a, _ = auditing.NewAuditor(auditing.NewAuditorParam{
+				AuditRepo: auditRepo,
+				Logger:    log,
+			})
+			p = auditing.ListLogParam{
+				ClientId: "client-id",
+				Action:   auditing.ACTION_AUTH_FAILURE,
+			}
+			listParam = repository.ListAuditLogParam{
+				ClientId: "client-id",
+				Action:   auditing.ACTION_AUTH_FAILURE,
+				Limit:    auditing.DEFAULT_LIST_LIMIT,
+			}
+
+			log.EXPECT().Debug("In function: ListLog").Times(1)
+			log.EXPECT().Debug("Returning function: ListLog").Times(1)
+		})
+
+		When("action is invalid", func() {
+			It("should return error", func() {
+				p.Action = "invalid"
+				res, err := a.ListLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid action parameter")))
+			})
+		})
+
+		When("outcome is invalid", func() {
+			It("should return error", func() {
+				p.Outcome = "invalid"
+				res, err := a.ListLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid outcome parameter")))
+			})
+		})
+
+		When("created time range is invalid", func() {
+			It("should return error", func() {
+				from := currentTs
+				to := currentTs
+				p.CreatedFrom = &from
+				p.CreatedTo = &to
+				res, err := a.ListLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid created time parameter")))
+			})
+		})
+
+		When("limit is invalid", func() {
+			It("should return error", func() {
+				p.Limit = auditing.MAX_LIST_LIMIT + 1
+				res, err := a.ListLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid limit parameter")))
+			})
+		})
+
+		When("failed list audit log", func() {
+			It("should return error", func() {
+				auditRepo.EXPECT().
+					ListAuditLog(gomock.Eq(ctx), gomock.Eq(listParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := a.ListLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("there is no audit log", func() {
+			It("should return empty result", func() {
+				auditRepo.EXPECT().
+					ListAuditLog(gomock.Eq(ctx), gomock.Eq(listParam)).
+					Return(&repository.ListAuditLogResult{}, nil).
+					Times(1)
+
+				res, err := a.ListLog(ctx, p)
+
+				Expect(res).To(Equal(&auditing.ListLogResult{Items: []auditing.LogItem{}}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success list audit log", func() {
+			It("should return result", func() {
+				from := currentTs.Add(-time.Hour)
+				p.CreatedFrom = &from
+				p.AfterId = "after-id"
+				p.Limit = 10
+				listParam.CreatedFrom = &from
+				listParam.AfterId = "after-id"
+				listParam.Limit = 10
+				auditRepo.EXPECT().
+					ListAuditLog(gomock.Eq(ctx), gomock.Eq(listParam)).
+					Return(&repository.ListAuditLogResult{
+						Items: []repository.AuditLog{
+							{
+								Id:        "audit-id",
+								ClientId:  "client-id",
+								Action:    auditing.ACTION_AUTH_FAILURE,
+								IpAddress: "10.0.0.1",
+								RequestId: "request-id",
+								Outcome:   auditing.OUTCOME_DENIED,
+								Reason:    "Unauthorized",
+								CreatedAt: currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+
+				res, err := a.ListLog(ctx, p)
+
+				Expect(res).To(Equal(&auditing.ListLogResult{
+					Items: []auditing.LogItem{
+						{
+							Id:        "audit-id",
+							ClientId:  "client-id",
+							Action:    auditing.ACTION_AUTH_FAILURE,
+							IpAddress: "10.0.0.1",
+							RequestId: "request-id",
+							Outcome:   auditing.OUTCOME_DENIED,
+							Reason:    "Unauthorized",
+							CreatedAt: currentTs,
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
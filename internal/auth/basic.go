@@ -2,14 +2,22 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-seidon/local/internal/datetime"
 	"github.com/go-seidon/local/internal/encoding"
 	"github.com/go-seidon/local/internal/hashing"
 	"github.com/go-seidon/local/internal/repository"
 )
 
+const (
+	DEFAULT_USAGE_INTERVAL = 60 * time.Second
+)
+
 type BasicAuth interface {
 	ParseAuthToken(ctx context.Context, p ParseAuthTokenParam) (*ParseAuthTokenResult, error)
 	CheckCredential(ctx context.Context, p CheckCredentialParam) (*CheckCredentialResult, error)
@@ -20,7 +28,13 @@ type CheckCredentialParam struct {
 }
 
 type CheckCredentialResult struct {
-	TokenValid bool
+	TokenValid   bool
+	ClientId     string
+	Scopes       []string
+	AllowedCidrs []string
+	SecretId     string
+	// the client exists even though the secret is invalid, so the failure is counted to the client
+	ClientFound bool
 }
 
 type ParseAuthTokenParam struct {
@@ -33,9 +47,15 @@ type ParseAuthTokenResult struct {
 }
 
 type basicAuth struct {
-	oAuthRepo repository.OAuthRepository
-	encoder   encoding.Encoder
-	hasher    hashing.Hasher
+	oAuthRepo     repository.OAuthRepository
+	encoder       encoding.Encoder
+	hasher        hashing.Hasher
+	cache         CredentialCache
+	clock         datetime.Clock
+	usageInterval time.Duration
+	mu            sync.Mutex
+	usedSince     time.Time
+	usedSecrets   map[string]struct{}
 }
 
 func (a *basicAuth) ParseAuthToken(ctx context.Context, p ParseAuthTokenParam) (*ParseAuthTokenResult, error) {
@@ -67,7 +87,22 @@ func (a *basicAuth) ParseAuthToken(ctx context.Context, p ParseAuthTokenParam) (
 	return res, nil
 }
 
+// only the successful verification is cached,
+// so the invalid credential is always verified against the database,
+// the cached credential is accepted until the cache ttl even though it is revoked by another process
 func (a *basicAuth) CheckCredential(ctx context.Context, p CheckCredentialParam) (*CheckCredentialResult, error) {
+	if a.cache != nil {
+		res, ok := a.cache.Get(p.AuthToken)
+		if ok {
+			if a.claimUsage(res.SecretId) {
+				// error is ommited since failing to record the usage should not reject a valid credential
+				a.oAuthRepo.UpdateSecretUsage(ctx, repository.UpdateSecretUsageParam{
+					SecretId: res.SecretId,
+				})
+			}
+			return res, nil
+		}
+	}
 
 	client, err := a.ParseAuthToken(ctx, ParseAuthTokenParam{
 		Token: p.AuthToken,
@@ -80,27 +115,65 @@ func (a *basicAuth) CheckCredential(ctx context.Context, p CheckCredentialParam)
 		ClientId: client.ClientId,
 	})
 	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			res := &CheckCredentialResult{
+				TokenValid: false,
+			}
+			return res, nil
+		}
 		return nil, err
 	}
 
-	err = a.hasher.Verify(oClient.ClientSecret, client.ClientSecret)
+	secret, err := verifyClientSecret(ctx, a.oAuthRepo, a.hasher, oClient, client.ClientSecret)
 	if err != nil {
 		res := &CheckCredentialResult{
-			TokenValid: false,
+			TokenValid:  false,
+			ClientFound: true,
 		}
 		return res, nil
 	}
 
 	res := &CheckCredentialResult{
-		TokenValid: true,
+		TokenValid:   true,
+		ClientId:     client.ClientId,
+		Scopes:       oClient.Scopes,
+		AllowedCidrs: oClient.AllowedCidrs,
+		SecretId:     secret.Id,
+		ClientFound:  true,
+	}
+	if a.cache != nil {
+		a.claimUsage(secret.Id)
+		a.cache.Set(p.AuthToken, *res, secret.ExpiresAt)
 	}
 	return res, nil
 }
 
+// the usage of each secret is recorded at most once per interval,
+// the recorded secrets are forgotten when the interval is passed so the set stays bounded
+func (a *basicAuth) claimUsage(secretId string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	currentTimestamp := a.clock.Now()
+	if !currentTimestamp.Before(a.usedSince.Add(a.usageInterval)) {
+		a.usedSince = currentTimestamp
+		a.usedSecrets = map[string]struct{}{}
+	}
+	if _, ok := a.usedSecrets[secretId]; ok {
+		return false
+	}
+	a.usedSecrets[secretId] = struct{}{}
+	return true
+}
+
 type NewBasicAuthParam struct {
 	OAuthRepo repository.OAuthRepository
 	Encoder   encoding.Encoder
 	Hasher    hashing.Hasher
+	Cache     CredentialCache
+	// the interval the secret usage is recorded when the credential is cached
+	UsageInterval time.Duration
+	Clock         datetime.Clock
 }
 
 func NewBasicAuth(p NewBasicAuthParam) (*basicAuth, error) {
@@ -113,11 +186,27 @@ func NewBasicAuth(p NewBasicAuthParam) (*basicAuth, error) {
 	if p.Hasher == nil {
 		return nil, fmt.Errorf("hasher is not specified")
 	}
+	if p.UsageInterval < 0 {
+		return nil, fmt.Errorf("invalid usage interval")
+	}
+
+	usageInterval := DEFAULT_USAGE_INTERVAL
+	if p.UsageInterval > 0 {
+		usageInterval = p.UsageInterval
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
 
 	a := &basicAuth{
-		oAuthRepo: p.OAuthRepo,
-		encoder:   p.Encoder,
-		hasher:    p.Hasher,
+		oAuthRepo:     p.OAuthRepo,
+		encoder:       p.Encoder,
+		hasher:        p.Hasher,
+		cache:         p.Cache,
+		clock:         clock,
+		usageInterval: usageInterval,
+		usedSecrets:   map[string]struct{}{},
 	}
 	return a, nil
 }
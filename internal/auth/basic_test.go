@@ -3,6 +3,7 @@ package auth_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-seidon/local/internal/auth"
 	"github.com/go-seidon/local/internal/mock"
@@ -61,6 +62,16 @@ var _ = Describe("Basic Auth Package", func() {
 			})
 		})
 
+		When("usage interval is negative", func() {
+			It("should return error", func() {
+				p.UsageInterval = -1
+				res, err := auth.NewBasicAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid usage interval")))
+			})
+		})
+
 		When("all parameter are specified", func() {
 			It("should return result", func() {
 				res, err := auth.NewBasicAuth(p)
@@ -219,8 +230,12 @@ var _ = Describe("Basic Auth Package", func() {
 				ClientId: "client_id",
 			}
 			findRes = &repository.FindClientResult{
-				ClientId:     "client_id",
-				ClientSecret: "hashed_client_secret",
+				ClientId: "client_id",
+				Scopes:   []string{"file:read"},
+				Secrets: []repository.ClientSecret{
+					{Id: "secret-id-2", Secret: "hashed_client_secret_2"},
+					{Id: "secret-id-1", Secret: "hashed_client_secret_1"},
+				},
 			}
 		})
 
@@ -255,6 +270,54 @@ var _ = Describe("Basic Auth Package", func() {
 			})
 		})
 
+		When("client is not found", func() {
+			It("should return invalid result", func() {
+				encoder.
+					EXPECT().
+					Decode(gomock.Eq(p.AuthToken)).
+					Return([]byte("client_id:client_secret"), nil).
+					Times(1)
+
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := basicAuth.CheckCredential(ctx, p)
+
+				Expect(res).To(Equal(&auth.CheckCredentialResult{
+					TokenValid: false,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("client has no active secret", func() {
+			It("should return invalid result", func() {
+				findRes.Secrets = []repository.ClientSecret{}
+				encoder.
+					EXPECT().
+					Decode(gomock.Eq(p.AuthToken)).
+					Return([]byte("client_id:client_secret"), nil).
+					Times(1)
+
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findRes, nil).
+					Times(1)
+
+				res, err := basicAuth.CheckCredential(ctx, p)
+
+				Expect(res).To(Equal(&auth.CheckCredentialResult{
+					TokenValid:  false,
+					ClientFound: true,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
 		When("client secret is invalid", func() {
 			It("should return error", func() {
 				encoder.
@@ -271,14 +334,15 @@ var _ = Describe("Basic Auth Package", func() {
 
 				hasher.
 					EXPECT().
-					Verify(gomock.Eq(findRes.ClientSecret), gomock.Eq("client_secret")).
+					Verify(gomock.Any(), gomock.Eq("client_secret")).
 					Return(fmt.Errorf("invalid")).
-					Times(1)
+					Times(2)
 
 				res, err := basicAuth.CheckCredential(ctx, p)
 
 				expectedRes := &auth.CheckCredentialResult{
-					TokenValid: false,
+					TokenValid:  false,
+					ClientFound: true,
 				}
 				Expect(res).To(Equal(expectedRes))
 				Expect(err).To(BeNil())
@@ -299,17 +363,297 @@ var _ = Describe("Basic Auth Package", func() {
 					Return(findRes, nil).
 					Times(1)
 
+				gomock.InOrder(
+					hasher.
+						EXPECT().
+						Verify(gomock.Eq("hashed_client_secret_2"), gomock.Eq("client_secret")).
+						Return(fmt.Errorf("invalid")),
+					hasher.
+						EXPECT().
+						Verify(gomock.Eq("hashed_client_secret_1"), gomock.Eq("client_secret")).
+						Return(nil),
+				)
+
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Eq(repository.UpdateSecretUsageParam{
+						SecretId: "secret-id-1",
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
 				hasher.
 					EXPECT().
-					Verify(gomock.Eq(findRes.ClientSecret), gomock.Eq("client_secret")).
-					Return(nil).
+					NeedsRehash(gomock.Eq("hashed_client_secret_1")).
+					Return(false).
 					Times(1)
 
 				res, err := basicAuth.CheckCredential(ctx, p)
 
 				expectedRes := &auth.CheckCredentialResult{
+					TokenValid:  true,
+					ClientId:    "client_id",
+					Scopes:      []string{"file:read"},
+					SecretId:    "secret-id-1",
+					ClientFound: true,
+				}
+				Expect(res).To(Equal(expectedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("stored hash is outdated", func() {
+			It("should upgrade the stored hash", func() {
+				findRes.Secrets = []repository.ClientSecret{
+					{Id: "secret-id-1", Secret: "bcrypt_client_secret"},
+				}
+
+				encoder.
+					EXPECT().
+					Decode(gomock.Eq(p.AuthToken)).
+					Return([]byte("client_id:client_secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findRes, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Verify(gomock.Eq("bcrypt_client_secret"), gomock.Eq("client_secret")).
+					Return(nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					NeedsRehash(gomock.Eq("bcrypt_client_secret")).
+					Return(true).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("client_secret")).
+					Return([]byte("argon2id_client_secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretHash(gomock.Eq(ctx), gomock.Eq(repository.UpdateSecretHashParam{
+						SecretId: "secret-id-1",
+						Secret:   "argon2id_client_secret",
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := basicAuth.CheckCredential(ctx, p)
+
+				Expect(res).To(Equal(&auth.CheckCredentialResult{
+					TokenValid:  true,
+					ClientId:    "client_id",
+					Scopes:      []string{"file:read"},
+					SecretId:    "secret-id-1",
+					ClientFound: true,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed generate upgraded hash", func() {
+			It("should keep the stored hash", func() {
+				findRes.Secrets = []repository.ClientSecret{
+					{Id: "secret-id-1", Secret: "bcrypt_client_secret"},
+				}
+
+				encoder.
+					EXPECT().
+					Decode(gomock.Eq(p.AuthToken)).
+					Return([]byte("client_id:client_secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findRes, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Verify(gomock.Eq("bcrypt_client_secret"), gomock.Eq("client_secret")).
+					Return(nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					NeedsRehash(gomock.Eq("bcrypt_client_secret")).
+					Return(true).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("client_secret")).
+					Return(nil, fmt.Errorf("entropy error")).
+					Times(1)
+
+				res, err := basicAuth.CheckCredential(ctx, p)
+
+				Expect(res.TokenValid).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("credential is cached", func() {
+			It("should return cached result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				basicAuth, _ = auth.NewBasicAuth(auth.NewBasicAuthParam{
+					OAuthRepo: oAuthRepo,
+					Encoder:   encoder,
+					Hasher:    hasher,
+					Cache:     cache,
+				})
+				cachedRes := &auth.CheckCredentialResult{
 					TokenValid: true,
+					ClientId:   "client_id",
+					Scopes:     []string{"file:read"},
+					SecretId:   "secret-id-1",
+				}
+
+				cache.
+					EXPECT().
+					Get(gomock.Eq(p.AuthToken)).
+					Return(cachedRes, true).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Eq(repository.UpdateSecretUsageParam{
+						SecretId: "secret-id-1",
+					})).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(1)
+
+				res, err := basicAuth.CheckCredential(ctx, p)
+
+				Expect(res).To(Equal(cachedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("cached credential is used within the usage interval", func() {
+			It("should record the usage once", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				clock := mock.NewMockClock(ctrl)
+				basicAuth, _ = auth.NewBasicAuth(auth.NewBasicAuthParam{
+					OAuthRepo:     oAuthRepo,
+					Encoder:       encoder,
+					Hasher:        hasher,
+					Cache:         cache,
+					UsageInterval: time.Minute,
+					Clock:         clock,
+				})
+				cachedRes := &auth.CheckCredentialResult{
+					TokenValid: true,
+					ClientId:   "client_id",
+					SecretId:   "secret-id-1",
+				}
+				currentTs := time.Unix(1660000000, 0)
+
+				cache.
+					EXPECT().
+					Get(gomock.Eq(p.AuthToken)).
+					Return(cachedRes, true).
+					Times(3)
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(1)
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs.Add(59 * time.Second)).
+					Times(1)
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs.Add(time.Minute)).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(2)
+
+				basicAuth.CheckCredential(ctx, p)
+				basicAuth.CheckCredential(ctx, p)
+				res, err := basicAuth.CheckCredential(ctx, p)
+
+				Expect(res).To(Equal(cachedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("credential is not cached", func() {
+			It("should cache the valid result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				basicAuth, _ = auth.NewBasicAuth(auth.NewBasicAuthParam{
+					OAuthRepo: oAuthRepo,
+					Encoder:   encoder,
+					Hasher:    hasher,
+					Cache:     cache,
+				})
+				expiresAt := time.Unix(1660000000, 0)
+				findRes.Secrets = []repository.ClientSecret{
+					{Id: "secret-id-1", Secret: "hashed_client_secret_1", ExpiresAt: &expiresAt},
+				}
+				expectedRes := &auth.CheckCredentialResult{
+					TokenValid:  true,
+					ClientId:    "client_id",
+					Scopes:      []string{"file:read"},
+					SecretId:    "secret-id-1",
+					ClientFound: true,
 				}
+
+				cache.
+					EXPECT().
+					Get(gomock.Eq(p.AuthToken)).
+					Return(nil, false).
+					Times(1)
+				encoder.
+					EXPECT().
+					Decode(gomock.Eq(p.AuthToken)).
+					Return([]byte("client_id:client_secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findRes, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Verify(gomock.Eq("hashed_client_secret_1"), gomock.Eq("client_secret")).
+					Return(nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					NeedsRehash(gomock.Eq("hashed_client_secret_1")).
+					Return(false).
+					Times(1)
+				cache.
+					EXPECT().
+					Set(gomock.Eq(p.AuthToken), gomock.Eq(*expectedRes), gomock.Eq(&expiresAt)).
+					Times(1)
+
+				res, err := basicAuth.CheckCredential(ctx, p)
+
 				Expect(res).To(Equal(expectedRes))
 				Expect(err).To(BeNil())
 			})
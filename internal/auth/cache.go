@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+)
+
+const (
+	DEFAULT_CACHE_SIZE = 10000
+	DEFAULT_CACHE_TTL  = 60 * time.Second
+
+	CACHE_KEY_SIZE = 32
+)
+
+type CredentialCache interface {
+	Get(token string) (*CheckCredentialResult, bool)
+	Set(token string, res CheckCredentialResult, expiresAt *time.Time)
+	InvalidateClient(clientId string)
+	Stats() CacheStats
+}
+
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+type cacheEntry struct {
+	key       string
+	result    CheckCredentialResult
+	expiresAt time.Time
+}
+
+type credentialCache struct {
+	mu        sync.Mutex
+	key       []byte
+	maxSize   int
+	ttl       time.Duration
+	clock     datetime.Clock
+	entries   *list.List
+	items     map[string]*list.Element
+	clients   map[string]map[string]struct{}
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func (c *credentialCache) Get(token string) (*CheckCredentialResult, bool) {
+	key := c.hashToken(token)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !c.clock.Now().Before(entry.expiresAt) {
+		c.remove(elem)
+		c.misses++
+		return nil, false
+	}
+
+	c.entries.MoveToFront(elem)
+	c.hits++
+
+	res := entry.result
+	res.Scopes = append([]string{}, entry.result.Scopes...)
+	res.AllowedCidrs = append([]string{}, entry.result.AllowedCidrs...)
+	return &res, true
+}
+
+func (c *credentialCache) Set(token string, res CheckCredentialResult, expiresAt *time.Time) {
+	key := c.hashToken(token)
+	entryExpiresAt := c.clock.Now().Add(c.ttl)
+	if expiresAt != nil && expiresAt.Before(entryExpiresAt) {
+		entryExpiresAt = *expiresAt
+	}
+	res.Scopes = append([]string{}, res.Scopes...)
+	res.AllowedCidrs = append([]string{}, res.AllowedCidrs...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+
+	elem := c.entries.PushFront(&cacheEntry{
+		key:       key,
+		result:    res,
+		expiresAt: entryExpiresAt,
+	})
+	c.items[key] = elem
+	if _, ok := c.clients[res.ClientId]; !ok {
+		c.clients[res.ClientId] = map[string]struct{}{}
+	}
+	c.clients[res.ClientId][key] = struct{}{}
+
+	for c.entries.Len() > c.maxSize {
+		c.remove(c.entries.Back())
+		c.evictions++
+	}
+}
+
+func (c *credentialCache) InvalidateClient(clientId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.clients[clientId] {
+		if elem, ok := c.items[key]; ok {
+			c.remove(elem)
+		}
+	}
+	delete(c.clients, clientId)
+}
+
+func (c *credentialCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.entries.Len(),
+	}
+}
+
+// caller must hold the lock
+func (c *credentialCache) remove(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.entries.Remove(elem)
+	delete(c.items, entry.key)
+
+	keys := c.clients[entry.result.ClientId]
+	delete(keys, entry.key)
+	if len(keys) == 0 {
+		delete(c.clients, entry.result.ClientId)
+	}
+}
+
+// the plain token is never stored, the keyed hash prevents
+// the cache key from being used to brute force the secret offline
+func (c *credentialCache) hashToken(token string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type NewCredentialCacheParam struct {
+	MaxSize int
+	TTL     time.Duration
+	Key     []byte
+	Clock   datetime.Clock
+}
+
+func NewCredentialCache(p NewCredentialCacheParam) (*credentialCache, error) {
+	if p.MaxSize < 0 {
+		return nil, fmt.Errorf("invalid cache size")
+	}
+	if p.TTL < 0 {
+		return nil, fmt.Errorf("invalid cache ttl")
+	}
+
+	maxSize := DEFAULT_CACHE_SIZE
+	if p.MaxSize > 0 {
+		maxSize = p.MaxSize
+	}
+	ttl := DEFAULT_CACHE_TTL
+	if p.TTL > 0 {
+		ttl = p.TTL
+	}
+	key := p.Key
+	if len(key) == 0 {
+		key = make([]byte, CACHE_KEY_SIZE)
+		_, err := rand.Read(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	c := &credentialCache{
+		key:     key,
+		maxSize: maxSize,
+		ttl:     ttl,
+		clock:   clock,
+		entries: list.New(),
+		items:   map[string]*list.Element{},
+		clients: map[string]map[string]struct{}{},
+	}
+	return c, nil
+}
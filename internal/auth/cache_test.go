@@ -0,0 +1,226 @@
+package auth_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credential Cache Package", func() {
+	Context("NewCredentialCache function", Label("unit"), func() {
+		When("size is invalid", func() {
+			It("should return error", func() {
+				res, err := auth.NewCredentialCache(auth.NewCredentialCacheParam{
+					MaxSize: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid cache size")))
+			})
+		})
+
+		When("ttl is invalid", func() {
+			It("should return error", func() {
+				res, err := auth.NewCredentialCache(auth.NewCredentialCacheParam{
+					TTL: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid cache ttl")))
+			})
+		})
+
+		When("parameter is not specified", func() {
+			It("should return result", func() {
+				res, err := auth.NewCredentialCache(auth.NewCredentialCacheParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("Get function", Label("unit"), func() {
+		var (
+			currentTs time.Time
+			clock     *mock.MockClock
+			cache     auth.CredentialCache
+			result    auth.CheckCredentialResult
+		)
+
+		BeforeEach(func() {
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			clock = mock.NewMockClock(ctrl)
+			cache, _ = auth.NewCredentialCache(auth.NewCredentialCacheParam{
+				MaxSize: 2,
+				TTL:     time.Minute,
+				Key:     []byte("cache-key"),
+				Clock:   clock,
+			})
+			result = auth.CheckCredentialResult{
+				TokenValid:   true,
+				ClientId:     "client-id",
+				Scopes:       []string{"file:read"},
+				AllowedCidrs: []string{"10.0.0.0/8"},
+				SecretId:     "secret-id",
+			}
+		})
+
+		When("token is not cached", func() {
+			It("should return miss", func() {
+				res, ok := cache.Get("token")
+
+				Expect(res).To(BeNil())
+				Expect(ok).To(BeFalse())
+				Expect(cache.Stats()).To(Equal(auth.CacheStats{
+					Misses: 1,
+				}))
+			})
+		})
+
+		When("token is cached", func() {
+			It("should return hit", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(2)
+
+				cache.Set("token", result, nil)
+				res, ok := cache.Get("token")
+
+				Expect(res).To(Equal(&result))
+				Expect(ok).To(BeTrue())
+				Expect(cache.Stats()).To(Equal(auth.CacheStats{
+					Hits: 1,
+					Size: 1,
+				}))
+			})
+		})
+
+		When("cached result is modified by the caller", func() {
+			It("should not affect the cache", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(3)
+
+				cache.Set("token", result, nil)
+				res, _ := cache.Get("token")
+				res.Scopes[0] = "admin"
+				res, _ = cache.Get("token")
+
+				Expect(res.Scopes).To(Equal([]string{"file:read"}))
+			})
+		})
+
+		When("entry is expired", func() {
+			It("should return miss", func() {
+				gomock.InOrder(
+					clock.EXPECT().Now().Return(currentTs),
+					clock.EXPECT().Now().Return(currentTs.Add(time.Minute)),
+				)
+
+				cache.Set("token", result, nil)
+				res, ok := cache.Get("token")
+
+				Expect(res).To(BeNil())
+				Expect(ok).To(BeFalse())
+				Expect(cache.Stats()).To(Equal(auth.CacheStats{
+					Misses: 1,
+				}))
+			})
+		})
+
+		When("secret is expired before the cache ttl", func() {
+			It("should return miss", func() {
+				gomock.InOrder(
+					clock.EXPECT().Now().Return(currentTs),
+					clock.EXPECT().Now().Return(currentTs.Add(10*time.Second)),
+				)
+				expiresAt := currentTs.Add(10 * time.Second)
+
+				cache.Set("token", result, &expiresAt)
+				res, ok := cache.Get("token")
+
+				Expect(res).To(BeNil())
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("cache is full", func() {
+			It("should evict the least recently used entry", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					AnyTimes()
+
+				cache.Set("token-1", result, nil)
+				cache.Set("token-2", result, nil)
+				cache.Get("token-1")
+				cache.Set("token-3", result, nil)
+
+				_, ok1 := cache.Get("token-1")
+				_, ok2 := cache.Get("token-2")
+				_, ok3 := cache.Get("token-3")
+
+				Expect(ok1).To(BeTrue())
+				Expect(ok2).To(BeFalse())
+				Expect(ok3).To(BeTrue())
+				Expect(cache.Stats()).To(Equal(auth.CacheStats{
+					Hits:      3,
+					Misses:    1,
+					Evictions: 1,
+					Size:      2,
+				}))
+			})
+		})
+	})
+
+	Context("InvalidateClient function", Label("unit"), func() {
+		var (
+			cache auth.CredentialCache
+		)
+
+		BeforeEach(func() {
+			cache, _ = auth.NewCredentialCache(auth.NewCredentialCacheParam{})
+		})
+
+		When("client has cached credential", func() {
+			It("should remove every credential of the client", func() {
+				cache.Set("token-1", auth.CheckCredentialResult{ClientId: "client-1"}, nil)
+				cache.Set("token-2", auth.CheckCredentialResult{ClientId: "client-1"}, nil)
+				cache.Set("token-3", auth.CheckCredentialResult{ClientId: "client-2"}, nil)
+
+				cache.InvalidateClient("client-1")
+
+				_, ok1 := cache.Get("token-1")
+				_, ok2 := cache.Get("token-2")
+				_, ok3 := cache.Get("token-3")
+
+				Expect(ok1).To(BeFalse())
+				Expect(ok2).To(BeFalse())
+				Expect(ok3).To(BeTrue())
+				Expect(cache.Stats().Size).To(Equal(1))
+			})
+		})
+
+		When("client has no cached credential", func() {
+			It("should not remove other credential", func() {
+				cache.Set("token-1", auth.CheckCredentialResult{ClientId: "client-1"}, nil)
+
+				cache.InvalidateClient("client-2")
+
+				Expect(cache.Stats().Size).To(Equal(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package auth
+
+import "context"
+
+type clientContextKey struct{}
+
+func NewClientContext(ctx context.Context, clientId string) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, clientId)
+}
+
+func ClientFromContext(ctx context.Context) (string, bool) {
+	clientId, ok := ctx.Value(clientContextKey{}).(string)
+	return clientId, ok
+}
+
+type scopeContextKey struct{}
+
+func NewScopeContext(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, scopes)
+}
+
+func ScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(scopeContextKey{}).([]string)
+	return scopes, ok
+}
+
+type allowlistContextKey struct{}
+
+func NewAllowlistContext(ctx context.Context, allowedCidrs []string) context.Context {
+	return context.WithValue(ctx, allowlistContextKey{}, allowedCidrs)
+}
+
+func AllowlistFromContext(ctx context.Context) ([]string, bool) {
+	allowedCidrs, ok := ctx.Value(allowlistContextKey{}).([]string)
+	return allowedCidrs, ok
+}
@@ -0,0 +1,74 @@
+package auth_test
+
+import (
+	"context"
+
+	"github.com/go-seidon/local/internal/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Context Package", func() {
+	Context("ClientFromContext function", Label("unit"), func() {
+		When("client is not attached", func() {
+			It("should return empty client", func() {
+				clientId, ok := auth.ClientFromContext(context.Background())
+
+				Expect(clientId).To(Equal(""))
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("client is attached", func() {
+			It("should return the client", func() {
+				ctx := auth.NewClientContext(context.Background(), "mock-client-id")
+				clientId, ok := auth.ClientFromContext(ctx)
+
+				Expect(clientId).To(Equal("mock-client-id"))
+				Expect(ok).To(BeTrue())
+			})
+		})
+	})
+
+	Context("ScopesFromContext function", Label("unit"), func() {
+		When("scopes are not attached", func() {
+			It("should return empty scopes", func() {
+				scopes, ok := auth.ScopesFromContext(context.Background())
+
+				Expect(scopes).To(BeNil())
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("scopes are attached", func() {
+			It("should return the scopes", func() {
+				ctx := auth.NewScopeContext(context.Background(), []string{"file:read"})
+				scopes, ok := auth.ScopesFromContext(ctx)
+
+				Expect(scopes).To(Equal([]string{"file:read"}))
+				Expect(ok).To(BeTrue())
+			})
+		})
+	})
+
+	Context("AllowlistFromContext function", Label("unit"), func() {
+		When("allowlist is not attached", func() {
+			It("should return empty allowlist", func() {
+				allowedCidrs, ok := auth.AllowlistFromContext(context.Background())
+
+				Expect(allowedCidrs).To(BeNil())
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("allowlist is attached", func() {
+			It("should return the allowlist", func() {
+				ctx := auth.NewAllowlistContext(context.Background(), []string{"10.0.0.0/8"})
+				allowedCidrs, ok := auth.AllowlistFromContext(ctx)
+
+				Expect(allowedCidrs).To(Equal([]string{"10.0.0.0/8"}))
+				Expect(ok).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,18 @@
+package auth
+
+import "errors"
+
+var (
+	ErrorInvalidClient     = errors.New("invalid client credential")
+	ErrorUnknownClient     = errors.New("invalid client credential")
+	ErrorInvalidToken      = errors.New("invalid access token")
+	ErrorTokenExpired      = errors.New("access token is expired")
+	ErrorInvalidScope      = errors.New("requested scope is not granted")
+	ErrorInvalidSignature  = errors.New("invalid request signature")
+	ErrorSignatureMismatch = errors.New("invalid request signature")
+	ErrorSignatureExpired  = errors.New("request timestamp is outside the allowed skew")
+	ErrorReplayedRequest   = errors.New("request nonce is already used")
+	ErrorNonceExhausted    = errors.New("too many signed requests are remembered")
+	ErrorBodyHashMismatch  = errors.New("request body does not match the signed hash")
+	ErrorAddressNotAllowed = errors.New("ip address is not allowed")
+)
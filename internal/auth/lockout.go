@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/logging"
+)
+
+const (
+	DEFAULT_LOCKOUT_CLIENT_THRESHOLD = 5
+	DEFAULT_LOCKOUT_IP_THRESHOLD     = 20
+	DEFAULT_LOCKOUT_BASE_DURATION    = 30 * time.Second
+	DEFAULT_LOCKOUT_MAX_DURATION     = 15 * time.Minute
+	DEFAULT_LOCKOUT_RESET_AFTER      = 15 * time.Minute
+	DEFAULT_LOCKOUT_MAX_ENTRIES      = 100000
+)
+
+type Lockout interface {
+	Check(ctx context.Context, p CheckLockoutParam) (*CheckLockoutResult, error)
+	RecordFailure(ctx context.Context, p RecordFailureParam) (*RecordFailureResult, error)
+	RecordSuccess(ctx context.Context, p RecordSuccessParam) error
+}
+
+type CheckLockoutParam struct {
+	ClientId  string
+	IpAddress string
+}
+
+type CheckLockoutResult struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+type RecordFailureParam struct {
+	ClientId  string
+	IpAddress string
+}
+
+type RecordFailureResult struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+type RecordSuccessParam struct {
+	ClientId  string
+	IpAddress string
+}
+
+type attemptState struct {
+	key         string
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+type lockoutKey struct {
+	key       string
+	threshold int
+}
+
+type lockout struct {
+	mu              sync.Mutex
+	entries         *list.List
+	attempts        map[string]*list.Element
+	clientThreshold int
+	ipThreshold     int
+	baseDuration    time.Duration
+	maxDuration     time.Duration
+	resetAfter      time.Duration
+	maxEntries      int
+	clock           datetime.Clock
+	log             logging.Logger
+}
+
+func (l *lockout) Check(ctx context.Context, p CheckLockoutParam) (*CheckLockoutResult, error) {
+	currentTs := l.clock.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	retryAfter := time.Duration(0)
+	for _, k := range l.keys(p.ClientId, p.IpAddress) {
+		elem, ok := l.attempts[k.key]
+		if !ok {
+			continue
+		}
+		state := elem.Value.(*attemptState)
+		if wait := state.lockedUntil.Sub(currentTs); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	res := &CheckLockoutResult{
+		Locked:     retryAfter > 0,
+		RetryAfter: retryAfter,
+	}
+	return res, nil
+}
+
+func (l *lockout) RecordFailure(ctx context.Context, p RecordFailureParam) (*RecordFailureResult, error) {
+	currentTs := l.clock.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	retryAfter := time.Duration(0)
+	for _, k := range l.keys(p.ClientId, p.IpAddress) {
+		state := l.track(k.key, currentTs)
+		state.failures++
+		state.lastFailure = currentTs
+
+		if state.failures < k.threshold {
+			continue
+		}
+
+		duration := l.lockDuration(state.failures - k.threshold)
+		state.lockedUntil = currentTs.Add(duration)
+		if duration > retryAfter {
+			retryAfter = duration
+		}
+
+		l.log.WithFields(map[string]interface{}{
+			"event":        "auth.lockout",
+			"key":          k.key,
+			"client_id":    p.ClientId,
+			"ip_address":   p.IpAddress,
+			"failures":     state.failures,
+			"locked_until": state.lockedUntil.UnixMilli(),
+		}).Warnf("Credential is locked out for %s", duration)
+	}
+
+	res := &RecordFailureResult{
+		Locked:     retryAfter > 0,
+		RetryAfter: retryAfter,
+	}
+	return res, nil
+}
+
+// only the client counter is reset, so a valid credential
+// can not be used to reset the counter of the shared ip address
+func (l *lockout) RecordSuccess(ctx context.Context, p RecordSuccessParam) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.attempts["client:"+p.ClientId]; ok {
+		l.remove(elem)
+	}
+	return nil
+}
+
+func (l *lockout) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.entries.Len()
+}
+
+// the entries are ordered by the last failure, the least recently failed entry
+// is evicted when the tracked entries are full, caller must hold the lock
+func (l *lockout) track(key string, currentTs time.Time) *attemptState {
+	if elem, ok := l.attempts[key]; ok {
+		state := elem.Value.(*attemptState)
+		if l.isStale(state, currentTs) {
+			*state = attemptState{key: key}
+		}
+		l.entries.MoveToFront(elem)
+		return state
+	}
+
+	for l.entries.Len() >= l.maxEntries {
+		l.remove(l.entries.Back())
+	}
+
+	state := &attemptState{key: key}
+	l.attempts[key] = l.entries.PushFront(state)
+	return state
+}
+
+func (l *lockout) keys(clientId, ipAddress string) []lockoutKey {
+	keys := []lockoutKey{}
+	if l.clientThreshold > 0 && clientId != "" {
+		keys = append(keys, lockoutKey{key: "client:" + clientId, threshold: l.clientThreshold})
+	}
+	if l.ipThreshold > 0 && ipAddress != "" {
+		keys = append(keys, lockoutKey{key: "ip:" + ipAddress, threshold: l.ipThreshold})
+	}
+	return keys
+}
+
+func (l *lockout) isStale(state *attemptState, currentTs time.Time) bool {
+	lastActivity := state.lastFailure
+	if state.lockedUntil.After(lastActivity) {
+		lastActivity = state.lockedUntil
+	}
+	return currentTs.Sub(lastActivity) >= l.resetAfter
+}
+
+func (l *lockout) lockDuration(exceeded int) time.Duration {
+	duration := l.baseDuration
+	for i := 0; i < exceeded; i++ {
+		duration *= 2
+		if duration >= l.maxDuration {
+			return l.maxDuration
+		}
+	}
+	return duration
+}
+
+// caller must hold the lock
+func (l *lockout) remove(elem *list.Element) {
+	state := elem.Value.(*attemptState)
+	l.entries.Remove(elem)
+	delete(l.attempts, state.key)
+}
+
+type NewLockoutParam struct {
+	ClientThreshold int
+	IpThreshold     int
+	BaseDuration    time.Duration
+	MaxDuration     time.Duration
+	ResetAfter      time.Duration
+	MaxEntries      int
+	Clock           datetime.Clock
+	Logger          logging.Logger
+}
+
+func NewLockout(p NewLockoutParam) (*lockout, error) {
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+	if p.BaseDuration < 0 || p.MaxDuration < 0 || p.ResetAfter < 0 || p.MaxEntries < 0 {
+		return nil, fmt.Errorf("invalid lockout parameter")
+	}
+
+	clientThreshold := DEFAULT_LOCKOUT_CLIENT_THRESHOLD
+	if p.ClientThreshold != 0 {
+		clientThreshold = p.ClientThreshold
+	}
+	ipThreshold := DEFAULT_LOCKOUT_IP_THRESHOLD
+	if p.IpThreshold != 0 {
+		ipThreshold = p.IpThreshold
+	}
+	baseDuration := DEFAULT_LOCKOUT_BASE_DURATION
+	if p.BaseDuration > 0 {
+		baseDuration = p.BaseDuration
+	}
+	maxDuration := DEFAULT_LOCKOUT_MAX_DURATION
+	if p.MaxDuration > 0 {
+		maxDuration = p.MaxDuration
+	}
+	if maxDuration < baseDuration {
+		return nil, fmt.Errorf("max duration must be greater than base duration")
+	}
+	resetAfter := DEFAULT_LOCKOUT_RESET_AFTER
+	if p.ResetAfter > 0 {
+		resetAfter = p.ResetAfter
+	}
+	maxEntries := DEFAULT_LOCKOUT_MAX_ENTRIES
+	if p.MaxEntries > 0 {
+		maxEntries = p.MaxEntries
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	l := &lockout{
+		entries:         list.New(),
+		attempts:        map[string]*list.Element{},
+		clientThreshold: clientThreshold,
+		ipThreshold:     ipThreshold,
+		baseDuration:    baseDuration,
+		maxDuration:     maxDuration,
+		resetAfter:      resetAfter,
+		maxEntries:      maxEntries,
+		clock:           clock,
+		log:             p.Logger,
+	}
+	return l, nil
+}
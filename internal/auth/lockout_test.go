@@ -0,0 +1,368 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lockout Package", func() {
+	Context("NewLockout function", Label("unit"), func() {
+		var (
+			p auth.NewLockoutParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = auth.NewLockoutParam{
+				Logger: mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := auth.NewLockout(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+
+		When("duration is invalid", func() {
+			It("should return error", func() {
+				p.BaseDuration = -1
+				res, err := auth.NewLockout(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid lockout parameter")))
+			})
+		})
+
+		When("max duration is less than base duration", func() {
+			It("should return error", func() {
+				p.BaseDuration = time.Minute
+				p.MaxDuration = time.Second
+				res, err := auth.NewLockout(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("max duration must be greater than base duration")))
+			})
+		})
+
+		When("optional parameter is not specified", func() {
+			It("should return result", func() {
+				res, err := auth.NewLockout(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RecordFailure function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			clock     *mock.MockClock
+			logger    *mock.MockLogger
+			lockout   auth.Lockout
+			p         auth.RecordFailureParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			clock = mock.NewMockClock(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			lockout, _ = auth.NewLockout(auth.NewLockoutParam{
+				ClientThreshold: 3,
+				IpThreshold:     -1,
+				BaseDuration:    10 * time.Second,
+				MaxDuration:     30 * time.Second,
+				ResetAfter:      time.Minute,
+				Clock:           clock,
+				Logger:          logger,
+			})
+			p = auth.RecordFailureParam{
+				ClientId:  "client-id",
+				IpAddress: "10.0.0.1",
+			}
+		})
+
+		When("failure is below the threshold", func() {
+			It("should not lock", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(2)
+
+				lockout.RecordFailure(ctx, p)
+				res, err := lockout.RecordFailure(ctx, p)
+
+				Expect(res).To(Equal(&auth.RecordFailureResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failure reaches the threshold", func() {
+			It("should lock and write audit log", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(4)
+				logger.
+					EXPECT().
+					WithFields(gomock.Eq(map[string]interface{}{
+						"event":        "auth.lockout",
+						"key":          "client:client-id",
+						"client_id":    "client-id",
+						"ip_address":   "10.0.0.1",
+						"failures":     3,
+						"locked_until": currentTs.Add(10 * time.Second).UnixMilli(),
+					})).
+					Return(logger).
+					Times(1)
+				logger.
+					EXPECT().
+					Warnf(gomock.Eq("Credential is locked out for %s"), gomock.Eq(10*time.Second)).
+					Times(1)
+
+				lockout.RecordFailure(ctx, p)
+				lockout.RecordFailure(ctx, p)
+				res, err := lockout.RecordFailure(ctx, p)
+
+				Expect(res).To(Equal(&auth.RecordFailureResult{
+					Locked:     true,
+					RetryAfter: 10 * time.Second,
+				}))
+				Expect(err).To(BeNil())
+
+				cRes, err := lockout.Check(ctx, auth.CheckLockoutParam{
+					ClientId:  "client-id",
+					IpAddress: "10.0.0.2",
+				})
+
+				Expect(cRes).To(Equal(&auth.CheckLockoutResult{
+					Locked:     true,
+					RetryAfter: 10 * time.Second,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failure exceeds the threshold", func() {
+			It("should double the duration up to the max duration", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					AnyTimes()
+				logger.
+					EXPECT().
+					WithFields(gomock.Any()).
+					Return(logger).
+					Times(4)
+				logger.
+					EXPECT().
+					Warnf(gomock.Any(), gomock.Any()).
+					Times(4)
+
+				durations := []time.Duration{}
+				for i := 0; i < 6; i++ {
+					res, _ := lockout.RecordFailure(ctx, p)
+					durations = append(durations, res.RetryAfter)
+				}
+
+				Expect(durations).To(Equal([]time.Duration{
+					0, 0, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second,
+				}))
+			})
+		})
+
+		When("failure is older than the reset period", func() {
+			It("should start a new counter", func() {
+				gomock.InOrder(
+					clock.EXPECT().Now().Return(currentTs).Times(2),
+					clock.EXPECT().Now().Return(currentTs.Add(time.Minute)),
+				)
+
+				lockout.RecordFailure(ctx, p)
+				lockout.RecordFailure(ctx, p)
+				res, err := lockout.RecordFailure(ctx, p)
+
+				Expect(res).To(Equal(&auth.RecordFailureResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("tracked entries are full", func() {
+			It("should evict the least recently failed entry", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					AnyTimes()
+				l, _ := auth.NewLockout(auth.NewLockoutParam{
+					MaxEntries: 10,
+					Clock:      clock,
+					Logger:     logger,
+				})
+
+				for i := 0; i < 100; i++ {
+					l.RecordFailure(ctx, auth.RecordFailureParam{
+						ClientId:  fmt.Sprintf("client-id-%d", i),
+						IpAddress: fmt.Sprintf("10.0.0.%d", i),
+					})
+					Expect(l.Size()).To(BeNumerically("<=", 10))
+				}
+				Expect(l.Size()).To(Equal(10))
+			})
+		})
+	})
+
+	Context("Check function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			clock     *mock.MockClock
+			lockout   auth.Lockout
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			clock = mock.NewMockClock(ctrl)
+			logger := mock.NewMockLogger(ctrl)
+			lockout, _ = auth.NewLockout(auth.NewLockoutParam{
+				ClientThreshold: 5,
+				IpThreshold:     2,
+				BaseDuration:    10 * time.Second,
+				Clock:           clock,
+				Logger:          logger,
+			})
+			logger.
+				EXPECT().
+				WithFields(gomock.Any()).
+				Return(logger).
+				AnyTimes()
+			logger.
+				EXPECT().
+				Warnf(gomock.Any(), gomock.Any()).
+				AnyTimes()
+		})
+
+		When("nothing is tracked", func() {
+			It("should not be locked", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(1)
+
+				res, err := lockout.Check(ctx, auth.CheckLockoutParam{
+					ClientId:  "client-id",
+					IpAddress: "10.0.0.1",
+				})
+
+				Expect(res).To(Equal(&auth.CheckLockoutResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("ip address is locked", func() {
+			It("should lock every client from the ip address", func() {
+				gomock.InOrder(
+					clock.EXPECT().Now().Return(currentTs).Times(2),
+					clock.EXPECT().Now().Return(currentTs.Add(4*time.Second)),
+				)
+
+				lockout.RecordFailure(ctx, auth.RecordFailureParam{ClientId: "client-1", IpAddress: "10.0.0.1"})
+				lockout.RecordFailure(ctx, auth.RecordFailureParam{ClientId: "client-2", IpAddress: "10.0.0.1"})
+				res, err := lockout.Check(ctx, auth.CheckLockoutParam{
+					ClientId:  "client-3",
+					IpAddress: "10.0.0.1",
+				})
+
+				Expect(res).To(Equal(&auth.CheckLockoutResult{
+					Locked:     true,
+					RetryAfter: 6 * time.Second,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("lockout is ended", func() {
+			It("should not be locked", func() {
+				gomock.InOrder(
+					clock.EXPECT().Now().Return(currentTs).Times(2),
+					clock.EXPECT().Now().Return(currentTs.Add(10*time.Second)),
+				)
+
+				lockout.RecordFailure(ctx, auth.RecordFailureParam{IpAddress: "10.0.0.1"})
+				lockout.RecordFailure(ctx, auth.RecordFailureParam{IpAddress: "10.0.0.1"})
+				res, err := lockout.Check(ctx, auth.CheckLockoutParam{
+					IpAddress: "10.0.0.1",
+				})
+
+				Expect(res).To(Equal(&auth.CheckLockoutResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RecordSuccess function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			clock     *mock.MockClock
+			lockout   auth.Lockout
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			clock = mock.NewMockClock(ctrl)
+			lockout, _ = auth.NewLockout(auth.NewLockoutParam{
+				ClientThreshold: 2,
+				IpThreshold:     2,
+				Clock:           clock,
+				Logger:          mock.NewMockLogger(ctrl),
+			})
+		})
+
+		When("client has failed attempt", func() {
+			It("should reset the client counter only", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(3)
+
+				lockout.RecordFailure(ctx, auth.RecordFailureParam{ClientId: "client-id", IpAddress: "10.0.0.1"})
+				err := lockout.RecordSuccess(ctx, auth.RecordSuccessParam{
+					ClientId:  "client-id",
+					IpAddress: "10.0.0.1",
+				})
+				lockout.RecordFailure(ctx, auth.RecordFailureParam{ClientId: "client-id", IpAddress: "10.0.0.2"})
+				res, _ := lockout.Check(ctx, auth.CheckLockoutParam{
+					ClientId: "client-id",
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Locked).To(BeFalse())
+			})
+		})
+	})
+})
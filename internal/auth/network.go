@@ -0,0 +1,31 @@
+package auth
+
+import "net"
+
+func IsValidCidr(cidr string) bool {
+	_, _, err := net.ParseCIDR(cidr)
+	return err == nil
+}
+
+// empty allowlist allows any address,
+// the invalid address is never allowed when the allowlist is specified
+func IsAllowedAddress(allowedCidrs []string, ipAddress string) bool {
+	if len(allowedCidrs) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range allowedCidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
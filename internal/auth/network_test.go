@@ -0,0 +1,61 @@
+package auth_test
+
+import (
+	"github.com/go-seidon/local/internal/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network Package", func() {
+	Context("IsValidCidr function", Label("unit"), func() {
+		When("cidr is valid", func() {
+			It("should return true", func() {
+				Expect(auth.IsValidCidr("10.0.0.0/8")).To(BeTrue())
+				Expect(auth.IsValidCidr("192.168.1.10/32")).To(BeTrue())
+				Expect(auth.IsValidCidr("fd00::/8")).To(BeTrue())
+			})
+		})
+
+		When("cidr is invalid", func() {
+			It("should return false", func() {
+				Expect(auth.IsValidCidr("10.0.0.1")).To(BeFalse())
+				Expect(auth.IsValidCidr("10.0.0.0/33")).To(BeFalse())
+				Expect(auth.IsValidCidr("")).To(BeFalse())
+			})
+		})
+	})
+
+	Context("IsAllowedAddress function", Label("unit"), func() {
+		When("allowlist is empty", func() {
+			It("should return true", func() {
+				res := auth.IsAllowedAddress(nil, "203.0.113.10")
+
+				Expect(res).To(BeTrue())
+			})
+		})
+
+		When("address is in the allowlist", func() {
+			It("should return true", func() {
+				res := auth.IsAllowedAddress([]string{"192.168.0.0/16", "10.0.0.0/8"}, "10.1.2.3")
+
+				Expect(res).To(BeTrue())
+			})
+		})
+
+		When("address is not in the allowlist", func() {
+			It("should return false", func() {
+				res := auth.IsAllowedAddress([]string{"10.0.0.0/8"}, "203.0.113.10")
+
+				Expect(res).To(BeFalse())
+			})
+		})
+
+		When("address is invalid", func() {
+			It("should return false", func() {
+				res := auth.IsAllowedAddress([]string{"10.0.0.0/8"}, "unknown")
+
+				Expect(res).To(BeFalse())
+			})
+		})
+	})
+})
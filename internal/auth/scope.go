@@ -0,0 +1,25 @@
+package auth
+
+const (
+	SCOPE_FILE_READ   = "file:read"
+	SCOPE_FILE_WRITE  = "file:write"
+	SCOPE_FILE_DELETE = "file:delete"
+	SCOPE_ADMIN       = "admin"
+)
+
+func IsValidScope(scope string) bool {
+	switch scope {
+	case SCOPE_FILE_READ, SCOPE_FILE_WRITE, SCOPE_FILE_DELETE, SCOPE_ADMIN:
+		return true
+	}
+	return false
+}
+
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope || s == SCOPE_ADMIN {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package auth_test
+
+import (
+	"github.com/go-seidon/local/internal/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scope Package", func() {
+	Context("IsValidScope function", Label("unit"), func() {
+		When("scope is supported", func() {
+			It("should return true", func() {
+				Expect(auth.IsValidScope("file:read")).To(BeTrue())
+				Expect(auth.IsValidScope("file:write")).To(BeTrue())
+				Expect(auth.IsValidScope("file:delete")).To(BeTrue())
+				Expect(auth.IsValidScope("admin")).To(BeTrue())
+			})
+		})
+
+		When("scope is not supported", func() {
+			It("should return false", func() {
+				Expect(auth.IsValidScope("file:*")).To(BeFalse())
+				Expect(auth.IsValidScope("")).To(BeFalse())
+			})
+		})
+	})
+
+	Context("HasScope function", Label("unit"), func() {
+		When("scope is granted", func() {
+			It("should return true", func() {
+				res := auth.HasScope([]string{"file:read", "file:write"}, "file:write")
+
+				Expect(res).To(BeTrue())
+			})
+		})
+
+		When("admin scope is granted", func() {
+			It("should return true", func() {
+				res := auth.HasScope([]string{"admin"}, "file:delete")
+
+				Expect(res).To(BeTrue())
+			})
+		})
+
+		When("scope is not granted", func() {
+			It("should return false", func() {
+				res := auth.HasScope([]string{"file:read"}, "file:delete")
+
+				Expect(res).To(BeFalse())
+			})
+		})
+
+		When("no scope is granted", func() {
+			It("should return false", func() {
+				res := auth.HasScope(nil, "file:read")
+
+				Expect(res).To(BeFalse())
+			})
+		})
+	})
+})
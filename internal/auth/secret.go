@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/go-seidon/local/internal/hashing"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+func verifyClientSecret(ctx context.Context, oAuthRepo repository.OAuthRepository, hasher hashing.Hasher, client *repository.FindClientResult, secret string) (*repository.ClientSecret, error) {
+	for i := range client.Secrets {
+		s := client.Secrets[i]
+		err := hasher.Verify(s.Secret, secret)
+		if err != nil {
+			continue
+		}
+
+		// error is ommited since failing to record the usage should not reject a valid credential
+		oAuthRepo.UpdateSecretUsage(ctx, repository.UpdateSecretUsageParam{
+			SecretId: s.Id,
+		})
+		rehashClientSecret(ctx, oAuthRepo, hasher, s, secret)
+		return &s, nil
+	}
+	return nil, ErrorInvalidClient
+}
+
+// the stored hash is upgraded transparently when it is generated by an outdated algorithm or cost,
+// error is ommited since the old hash is still verifiable
+func rehashClientSecret(ctx context.Context, oAuthRepo repository.OAuthRepository, hasher hashing.Hasher, s repository.ClientSecret, secret string) {
+	if !hasher.NeedsRehash(s.Secret) {
+		return
+	}
+
+	hash, err := hasher.Generate(secret)
+	if err != nil {
+		return
+	}
+	oAuthRepo.UpdateSecretHash(ctx, repository.UpdateSecretHashParam{
+		SecretId: s.Id,
+		Secret:   string(hash),
+	})
+}
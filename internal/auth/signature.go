@@ -0,0 +1,357 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+const (
+	SIGNATURE_SCHEME = "HMAC-SHA256"
+
+	HEADER_SIGNATURE_TIMESTAMP = "X-Signature-Timestamp"
+	HEADER_SIGNATURE_NONCE     = "X-Signature-Nonce"
+	HEADER_CONTENT_SHA256      = "X-Content-Sha256"
+
+	DEFAULT_SIGNATURE_SKEW        = 5 * time.Minute
+	DEFAULT_SIGNATURE_MAX_ENTRIES = 100000
+
+	SIGNATURE_MIN_KEY_SIZE   = 32
+	SIGNATURE_MIN_NONCE_SIZE = 16
+	SIGNATURE_MAX_NONCE_SIZE = 128
+)
+
+// alternative of the basic auth where the secret is never sent over the wire,
+// the client signs the request with the signing key of its secret:
+//
+//	Authorization: HMAC-SHA256 Credential=<client_id>/<secret_id>, Signature=<hex signature>
+//	X-Signature-Timestamp: <unix seconds>
+//	X-Signature-Nonce: <random string>
+//	X-Content-Sha256: <hex sha256 of the body>
+//
+// the signing key is derived from the client secret (see DeriveSigningKey), the stored client secret is hashed
+// so the signing key is stored sealed by the server key when the secret is issued, the secret issued
+// before the request signing is enabled has no signing key and must be rotated,
+// the used nonces are remembered in memory so the replay is only rejected by the same instance
+type SignatureAuth interface {
+	ParseAuthorization(ctx context.Context, p ParseAuthorizationParam) (*ParseAuthorizationResult, error)
+	VerifySignature(ctx context.Context, p VerifySignatureParam) (*VerifySignatureResult, error)
+	SealSigningKey(p SealSigningKeyParam) (string, error)
+}
+
+type ParseAuthorizationParam struct {
+	Authorization string
+}
+
+type ParseAuthorizationResult struct {
+	ClientId  string
+	SecretId  string
+	Signature string
+}
+
+type VerifySignatureParam struct {
+	ClientId  string
+	SecretId  string
+	Signature string
+	Timestamp string
+	Nonce     string
+	Method    string
+	Path      string
+	Query     string
+	BodyHash  string
+}
+
+type VerifySignatureResult struct {
+	ClientId     string
+	Scopes       []string
+	AllowedCidrs []string
+	SecretId     string
+}
+
+type DeriveSigningKeyParam struct {
+	ClientId     string
+	SecretId     string
+	ClientSecret string
+}
+
+type SealSigningKeyParam struct {
+	ClientId   string
+	SecretId   string
+	SigningKey string
+}
+
+type SignRequestParam struct {
+	SigningKey string
+	Timestamp  string
+	Nonce      string
+	Method     string
+	Path       string
+	Query      string
+	BodyHash   string
+}
+
+type signatureAuth struct {
+	mu         sync.Mutex
+	oAuthRepo  repository.OAuthRepository
+	aead       cipher.AEAD
+	random     io.Reader
+	skew       time.Duration
+	maxEntries int
+	clock      datetime.Clock
+	nonces     map[string]time.Time
+}
+
+func (a *signatureAuth) ParseAuthorization(ctx context.Context, p ParseAuthorizationParam) (*ParseAuthorizationResult, error) {
+	res := &ParseAuthorizationResult{}
+	for _, part := range strings.Split(p.Authorization, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrorInvalidSignature
+		}
+		switch kv[0] {
+		case "Credential":
+			credential := strings.SplitN(kv[1], "/", 2)
+			if len(credential) != 2 {
+				return nil, ErrorInvalidSignature
+			}
+			res.ClientId = credential[0]
+			res.SecretId = credential[1]
+		case "Signature":
+			res.Signature = kv[1]
+		}
+	}
+
+	if res.ClientId == "" || res.SecretId == "" || res.Signature == "" {
+		return nil, ErrorInvalidSignature
+	}
+	return res, nil
+}
+
+// the nonce is only recorded after the signature is verified,
+// so the unauthenticated request can not exhaust the nonce of other client,
+// the mismatch error is only returned for an existing client so the failure can be counted to it
+func (a *signatureAuth) VerifySignature(ctx context.Context, p VerifySignatureParam) (*VerifySignatureResult, error) {
+	currentTs := a.clock.Now()
+
+	ts, err := strconv.ParseInt(p.Timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrorInvalidSignature
+	}
+	signedAt := time.Unix(ts, 0)
+	if signedAt.Before(currentTs.Add(-a.skew)) || signedAt.After(currentTs.Add(a.skew)) {
+		return nil, ErrorSignatureExpired
+	}
+	if len(p.Nonce) < SIGNATURE_MIN_NONCE_SIZE || len(p.Nonce) > SIGNATURE_MAX_NONCE_SIZE {
+		return nil, ErrorInvalidSignature
+	}
+
+	client, err := a.oAuthRepo.FindClient(ctx, repository.FindClientParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorInvalidSignature
+		}
+		return nil, err
+	}
+
+	var secret *repository.ClientSecret
+	for i := range client.Secrets {
+		if client.Secrets[i].Id == p.SecretId {
+			secret = &client.Secrets[i]
+			break
+		}
+	}
+	if secret == nil {
+		return nil, ErrorSignatureMismatch
+	}
+	signingKey, err := a.openSigningKey(p.ClientId, p.SecretId, secret.SigningKey)
+	if err != nil {
+		return nil, ErrorSignatureMismatch
+	}
+
+	signature := SignRequest(SignRequestParam{
+		SigningKey: signingKey,
+		Timestamp:  p.Timestamp,
+		Nonce:      p.Nonce,
+		Method:     p.Method,
+		Path:       p.Path,
+		Query:      p.Query,
+		BodyHash:   p.BodyHash,
+	})
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(strings.ToLower(p.Signature))) != 1 {
+		return nil, ErrorSignatureMismatch
+	}
+
+	err = a.useNonce(p.ClientId+":"+p.Nonce, signedAt.Add(a.skew), currentTs)
+	if err != nil {
+		return nil, err
+	}
+
+	// error is ommited since failing to record the usage should not reject a valid signature
+	a.oAuthRepo.UpdateSecretUsage(ctx, repository.UpdateSecretUsageParam{
+		SecretId: secret.Id,
+	})
+
+	res := &VerifySignatureResult{
+		ClientId:     client.ClientId,
+		Scopes:       client.Scopes,
+		AllowedCidrs: client.AllowedCidrs,
+		SecretId:     secret.Id,
+	}
+	return res, nil
+}
+
+// sealed format: base64 of nonce | sealed signing key,
+// the credential is used as additional data so the sealed key can not be moved to another secret
+func (a *signatureAuth) SealSigningKey(p SealSigningKeyParam) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	_, err := io.ReadFull(a.random, nonce)
+	if err != nil {
+		return "", err
+	}
+	sealed := a.aead.Seal(nonce, nonce, []byte(p.SigningKey), []byte(p.ClientId+"/"+p.SecretId))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (a *signatureAuth) openSigningKey(clientId, secretId, sealedKey string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(sealedKey)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < a.aead.NonceSize() {
+		return "", ErrorInvalidSignature
+	}
+	nonce := sealed[:a.aead.NonceSize()]
+	signingKey, err := a.aead.Open(nil, nonce, sealed[a.aead.NonceSize():], []byte(clientId+"/"+secretId))
+	if err != nil {
+		return "", err
+	}
+	return string(signingKey), nil
+}
+
+func (a *signatureAuth) useNonce(key string, expiresAt time.Time, currentTs time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if usedUntil, ok := a.nonces[key]; ok && currentTs.Before(usedUntil) {
+		return ErrorReplayedRequest
+	}
+	if len(a.nonces) >= a.maxEntries {
+		for k, usedUntil := range a.nonces {
+			if !currentTs.Before(usedUntil) {
+				delete(a.nonces, k)
+			}
+		}
+	}
+	// the unexpired nonce is never evicted, otherwise the request is replayable
+	if len(a.nonces) >= a.maxEntries {
+		return ErrorNonceExhausted
+	}
+	a.nonces[key] = expiresAt
+	return nil
+}
+
+// the string to sign is the newline delimited scheme, timestamp, nonce,
+// upper case method, path, raw query and hex sha256 of the body
+func SignRequest(p SignRequestParam) string {
+	stringToSign := strings.Join([]string{
+		SIGNATURE_SCHEME,
+		p.Timestamp,
+		p.Nonce,
+		strings.ToUpper(p.Method),
+		p.Path,
+		p.Query,
+		strings.ToLower(p.BodyHash),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(p.SigningKey))
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// the client derives the signing key from its own secret:
+// hex hmac-sha256 of "HMAC-SHA256/<client_id>/<secret_id>" using the client secret
+func DeriveSigningKey(p DeriveSigningKeyParam) string {
+	mac := hmac.New(sha256.New, []byte(p.ClientSecret))
+	mac.Write([]byte(SIGNATURE_SCHEME + "/" + p.ClientId + "/" + p.SecretId))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type NewSignatureAuthParam struct {
+	OAuthRepo repository.OAuthRepository
+	// at least 32 bytes, the stored signing keys are sealed by it,
+	// so changing the key invalidates every issued signing key
+	Key  []byte
+	Skew time.Duration
+	// maximum remembered nonces, the request is rejected until the nonce is expired, default to 100000
+	MaxEntries int
+	Clock      datetime.Clock
+	Random     io.Reader
+}
+
+func NewSignatureAuth(p NewSignatureAuthParam) (*signatureAuth, error) {
+	if p.OAuthRepo == nil {
+		return nil, fmt.Errorf("oauth repo is not specified")
+	}
+	if len(p.Key) < SIGNATURE_MIN_KEY_SIZE {
+		return nil, fmt.Errorf("signature key must be at least %d bytes", SIGNATURE_MIN_KEY_SIZE)
+	}
+	if p.Skew < 0 || p.MaxEntries < 0 {
+		return nil, fmt.Errorf("invalid signature parameter")
+	}
+
+	skew := DEFAULT_SIGNATURE_SKEW
+	if p.Skew > 0 {
+		skew = p.Skew
+	}
+	maxEntries := DEFAULT_SIGNATURE_MAX_ENTRIES
+	if p.MaxEntries > 0 {
+		maxEntries = p.MaxEntries
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+	random := p.Random
+	if random == nil {
+		random = rand.Reader
+	}
+
+	key := sha256.Sum256(p.Key)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &signatureAuth{
+		oAuthRepo:  p.OAuthRepo,
+		aead:       aead,
+		random:     random,
+		skew:       skew,
+		maxEntries: maxEntries,
+		clock:      clock,
+		nonces:     map[string]time.Time{},
+	}
+	return a, nil
+}
@@ -0,0 +1,430 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature Auth Package", func() {
+	Context("NewSignatureAuth function", Label("unit"), func() {
+		var (
+			p auth.NewSignatureAuthParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = auth.NewSignatureAuthParam{
+				OAuthRepo: mock.NewMockOAuthRepository(ctrl),
+				Key:       []byte("0123456789abcdef0123456789abcdef"),
+			}
+		})
+
+		When("oauth repo is not specified", func() {
+			It("should return error", func() {
+				p.OAuthRepo = nil
+				res, err := auth.NewSignatureAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("oauth repo is not specified")))
+			})
+		})
+
+		When("key is too short", func() {
+			It("should return error", func() {
+				p.Key = []byte("short-key")
+				res, err := auth.NewSignatureAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("signature key must be at least 32 bytes")))
+			})
+		})
+
+		When("skew is invalid", func() {
+			It("should return error", func() {
+				p.Skew = -1
+				res, err := auth.NewSignatureAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid signature parameter")))
+			})
+		})
+
+		When("all parameter are specified", func() {
+			It("should return result", func() {
+				res, err := auth.NewSignatureAuth(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ParseAuthorization function", Label("unit"), func() {
+		var (
+			ctx           context.Context
+			signatureAuth auth.SignatureAuth
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			signatureAuth, _ = auth.NewSignatureAuth(auth.NewSignatureAuthParam{
+				OAuthRepo: mock.NewMockOAuthRepository(ctrl),
+				Key:       []byte("0123456789abcdef0123456789abcdef"),
+			})
+		})
+
+		When("credential is not specified", func() {
+			It("should return error", func() {
+				res, err := signatureAuth.ParseAuthorization(ctx, auth.ParseAuthorizationParam{
+					Authorization: "Signature=abc",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidSignature))
+			})
+		})
+
+		When("credential is malformed", func() {
+			It("should return error", func() {
+				res, err := signatureAuth.ParseAuthorization(ctx, auth.ParseAuthorizationParam{
+					Authorization: "Credential=client-id, Signature=abc",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidSignature))
+			})
+		})
+
+		When("authorization is valid", func() {
+			It("should return result", func() {
+				res, err := signatureAuth.ParseAuthorization(ctx, auth.ParseAuthorizationParam{
+					Authorization: "Credential=client-id/secret-id, Signature=abc",
+				})
+
+				Expect(res).To(Equal(&auth.ParseAuthorizationResult{
+					ClientId:  "client-id",
+					SecretId:  "secret-id",
+					Signature: "abc",
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("VerifySignature function", Label("unit"), func() {
+		var (
+			ctx           context.Context
+			currentTs     time.Time
+			oAuthRepo     *mock.MockOAuthRepository
+			clock         *mock.MockClock
+			signatureAuth auth.SignatureAuth
+			p             auth.VerifySignatureParam
+			signingKey    string
+			findParam     repository.FindClientParam
+			findResult    *repository.FindClientResult
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			clock = mock.NewMockClock(ctrl)
+			signatureAuth, _ = auth.NewSignatureAuth(auth.NewSignatureAuthParam{
+				OAuthRepo: oAuthRepo,
+				Key:       []byte("0123456789abcdef0123456789abcdef"),
+				Skew:      time.Minute,
+				Clock:     clock,
+			})
+			bodyHash := sha256.Sum256([]byte("content"))
+			p = auth.VerifySignatureParam{
+				ClientId:  "client-id",
+				SecretId:  "secret-id",
+				Timestamp: "1660000030",
+				Nonce:     "0123456789abcdef",
+				Method:    "POST",
+				Path:      "/file",
+				Query:     "a=b",
+				BodyHash:  hex.EncodeToString(bodyHash[:]),
+			}
+			signingKey = auth.DeriveSigningKey(auth.DeriveSigningKeyParam{
+				ClientId:     "client-id",
+				SecretId:     "secret-id",
+				ClientSecret: "client-secret",
+			})
+			sealedKey, _ := signatureAuth.SealSigningKey(auth.SealSigningKeyParam{
+				ClientId:   "client-id",
+				SecretId:   "secret-id",
+				SigningKey: signingKey,
+			})
+			p.Signature = auth.SignRequest(auth.SignRequestParam{
+				SigningKey: signingKey,
+				Timestamp:  p.Timestamp,
+				Nonce:      p.Nonce,
+				Method:     p.Method,
+				Path:       p.Path,
+				Query:      p.Query,
+				BodyHash:   p.BodyHash,
+			})
+			findParam = repository.FindClientParam{
+				ClientId: "client-id",
+			}
+			findResult = &repository.FindClientResult{
+				ClientId: "client-id",
+				Scopes:   []string{"file:read"},
+				Secrets: []repository.ClientSecret{
+					{Id: "other-secret-id"},
+					{Id: "secret-id", SigningKey: sealedKey},
+				},
+			}
+			clock.
+				EXPECT().
+				Now().
+				Return(currentTs).
+				AnyTimes()
+		})
+
+		When("timestamp is malformed", func() {
+			It("should return error", func() {
+				p.Timestamp = "yesterday"
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidSignature))
+			})
+		})
+
+		When("timestamp is outside the skew", func() {
+			It("should return error", func() {
+				p.Timestamp = "1659999939"
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorSignatureExpired))
+			})
+		})
+
+		When("nonce is too short", func() {
+			It("should return error", func() {
+				p.Nonce = "nonce"
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidSignature))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidSignature))
+			})
+		})
+
+		When("failed find client", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("secret is not active", func() {
+			It("should return error", func() {
+				findResult.Secrets = findResult.Secrets[:1]
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorSignatureMismatch))
+			})
+		})
+
+		When("signing key is not stored", func() {
+			It("should return error", func() {
+				findResult.Secrets[1].SigningKey = ""
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorSignatureMismatch))
+			})
+		})
+
+		When("signing key is sealed for another secret", func() {
+			It("should return error", func() {
+				findResult.Secrets[0].SigningKey = findResult.Secrets[1].SigningKey
+				p.SecretId = "other-secret-id"
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorSignatureMismatch))
+			})
+		})
+
+		When("signed content is modified", func() {
+			It("should return error", func() {
+				p.Path = "/file/other-id"
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorSignatureMismatch))
+			})
+		})
+
+		When("signature is valid", func() {
+			It("should return result", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Eq(repository.UpdateSecretUsageParam{
+						SecretId: "secret-id",
+					})).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(1)
+
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(Equal(&auth.VerifySignatureResult{
+					ClientId: "client-id",
+					Scopes:   []string{"file:read"},
+					SecretId: "secret-id",
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("request is replayed", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(2)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(1)
+
+				signatureAuth.VerifySignature(ctx, p)
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorReplayedRequest))
+			})
+		})
+
+		When("remembered nonces are exhausted", func() {
+			It("should return error", func() {
+				signatureAuth, _ = auth.NewSignatureAuth(auth.NewSignatureAuthParam{
+					OAuthRepo:  oAuthRepo,
+					Key:        []byte("0123456789abcdef0123456789abcdef"),
+					Skew:       time.Minute,
+					MaxEntries: 1,
+					Clock:      clock,
+				})
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(2)
+				oAuthRepo.
+					EXPECT().
+					UpdateSecretUsage(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.UpdateSecretUsageResult{}, nil).
+					Times(1)
+
+				_, err := signatureAuth.VerifySignature(ctx, p)
+				Expect(err).To(BeNil())
+
+				p.Nonce = "fedcba9876543210"
+				p.Signature = auth.SignRequest(auth.SignRequestParam{
+					SigningKey: signingKey,
+					Timestamp:  p.Timestamp,
+					Nonce:      p.Nonce,
+					Method:     p.Method,
+					Path:       p.Path,
+					Query:      p.Query,
+					BodyHash:   p.BodyHash,
+				})
+				res, err := signatureAuth.VerifySignature(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorNonceExhausted))
+			})
+		})
+	})
+
+	Context("DeriveSigningKey function", Label("unit"), func() {
+		When("secret is different", func() {
+			It("should return different key", func() {
+				key1 := auth.DeriveSigningKey(auth.DeriveSigningKeyParam{
+					ClientId:     "client-id",
+					SecretId:     "secret-id",
+					ClientSecret: "client-secret-1",
+				})
+				key2 := auth.DeriveSigningKey(auth.DeriveSigningKeyParam{
+					ClientId:     "client-id",
+					SecretId:     "secret-id",
+					ClientSecret: "client-secret-2",
+				})
+
+				Expect(key1).To(HaveLen(64))
+				Expect(key1).ToNot(Equal(key2))
+			})
+		})
+	})
+})
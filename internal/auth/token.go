@@ -0,0 +1,264 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/hashing"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+const (
+	GRANT_CLIENT_CREDENTIALS = "client_credentials"
+	TOKEN_TYPE_BEARER        = "Bearer"
+
+	DEFAULT_TOKEN_TTL    = 3600 * time.Second
+	DEFAULT_TOKEN_ISSUER = "go-seidon/local"
+
+	MIN_SIGNING_KEY_SIZE = 32
+)
+
+type TokenAuth interface {
+	IssueToken(ctx context.Context, p IssueTokenParam) (*IssueTokenResult, error)
+	VerifyToken(ctx context.Context, p VerifyTokenParam) (*VerifyTokenResult, error)
+}
+
+type IssueTokenParam struct {
+	ClientId     string
+	ClientSecret string
+	Scopes       []string
+	IpAddress    string
+}
+
+type IssueTokenResult struct {
+	AccessToken string
+	TokenType   string
+	Scopes      []string
+	ExpiresIn   time.Duration
+	ExpiresAt   time.Time
+}
+
+type VerifyTokenParam struct {
+	Token string
+}
+
+type VerifyTokenResult struct {
+	ClientId     string
+	Scopes       []string
+	AllowedCidrs []string
+	ExpiresAt    time.Time
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Issuer       string `json:"iss"`
+	Subject      string `json:"sub"`
+	IssuedAt     int64  `json:"iat"`
+	ExpiresAt    int64  `json:"exp"`
+	Scope        string `json:"scope,omitempty"`
+	AllowedCidrs string `json:"cidr,omitempty"`
+}
+
+type tokenAuth struct {
+	oAuthRepo  repository.OAuthRepository
+	hasher     hashing.Hasher
+	signingKey []byte
+	issuer     string
+	ttl        time.Duration
+	clock      datetime.Clock
+}
+
+func (a *tokenAuth) IssueToken(ctx context.Context, p IssueTokenParam) (*IssueTokenResult, error) {
+	if strings.TrimSpace(p.ClientId) == "" || strings.TrimSpace(p.ClientSecret) == "" {
+		return nil, ErrorUnknownClient
+	}
+
+	oClient, err := a.oAuthRepo.FindClient(ctx, repository.FindClientParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorUnknownClient
+		}
+		return nil, err
+	}
+
+	_, err = verifyClientSecret(ctx, a.oAuthRepo, a.hasher, oClient, p.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsAllowedAddress(oClient.AllowedCidrs, p.IpAddress) {
+		return nil, ErrorAddressNotAllowed
+	}
+
+	scopes := oClient.Scopes
+	if len(p.Scopes) > 0 {
+		for _, scope := range p.Scopes {
+			if !IsValidScope(scope) || !HasScope(oClient.Scopes, scope) {
+				return nil, ErrorInvalidScope
+			}
+		}
+		scopes = p.Scopes
+	}
+
+	currentTimestamp := a.clock.Now()
+	expiresAt := currentTimestamp.Add(a.ttl)
+	token, err := a.sign(jwtClaims{
+		Issuer:       a.issuer,
+		Subject:      oClient.ClientId,
+		IssuedAt:     currentTimestamp.Unix(),
+		ExpiresAt:    expiresAt.Unix(),
+		Scope:        strings.Join(scopes, " "),
+		AllowedCidrs: strings.Join(oClient.AllowedCidrs, " "),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &IssueTokenResult{
+		AccessToken: token,
+		TokenType:   TOKEN_TYPE_BEARER,
+		Scopes:      scopes,
+		ExpiresIn:   a.ttl,
+		ExpiresAt:   time.Unix(expiresAt.Unix(), 0),
+	}
+	return res, nil
+}
+
+func (a *tokenAuth) VerifyToken(ctx context.Context, p VerifyTokenParam) (*VerifyTokenResult, error) {
+	parts := strings.Split(p.Token, ".")
+	if len(parts) != 3 {
+		return nil, ErrorInvalidToken
+	}
+
+	encHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrorInvalidToken
+	}
+	header := jwtHeader{}
+	err = json.Unmarshal(encHeader, &header)
+	if err != nil {
+		return nil, ErrorInvalidToken
+	}
+	// the algorithm is pinned to prevent "none" or algorithm confusion attack
+	if header.Algorithm != "HS256" {
+		return nil, ErrorInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrorInvalidToken
+	}
+	if !hmac.Equal(signature, a.signature(parts[0]+"."+parts[1])) {
+		return nil, ErrorInvalidToken
+	}
+
+	encClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrorInvalidToken
+	}
+	claims := jwtClaims{}
+	err = json.Unmarshal(encClaims, &claims)
+	if err != nil {
+		return nil, ErrorInvalidToken
+	}
+	if claims.Issuer != a.issuer || claims.Subject == "" {
+		return nil, ErrorInvalidToken
+	}
+	if a.clock.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrorTokenExpired
+	}
+
+	res := &VerifyTokenResult{
+		ClientId:     claims.Subject,
+		Scopes:       strings.Fields(claims.Scope),
+		AllowedCidrs: strings.Fields(claims.AllowedCidrs),
+		ExpiresAt:    time.Unix(claims.ExpiresAt, 0),
+	}
+	return res, nil
+}
+
+func (a *tokenAuth) sign(claims jwtClaims) (string, error) {
+	header, err := json.Marshal(jwtHeader{
+		Algorithm: "HS256",
+		Type:      "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	content := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	signature := base64.RawURLEncoding.EncodeToString(a.signature(content))
+	return content + "." + signature, nil
+}
+
+func (a *tokenAuth) signature(content string) []byte {
+	mac := hmac.New(sha256.New, a.signingKey)
+	mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
+
+type NewTokenAuthParam struct {
+	OAuthRepo  repository.OAuthRepository
+	Hasher     hashing.Hasher
+	SigningKey []byte
+	Issuer     string
+	TTL        time.Duration
+	Clock      datetime.Clock
+}
+
+func NewTokenAuth(p NewTokenAuthParam) (*tokenAuth, error) {
+	if p.OAuthRepo == nil {
+		return nil, fmt.Errorf("oauth repo is not specified")
+	}
+	if p.Hasher == nil {
+		return nil, fmt.Errorf("hasher is not specified")
+	}
+	if len(p.SigningKey) < MIN_SIGNING_KEY_SIZE {
+		return nil, fmt.Errorf("signing key must be at least %d bytes", MIN_SIGNING_KEY_SIZE)
+	}
+	if p.TTL < 0 {
+		return nil, fmt.Errorf("invalid token ttl")
+	}
+
+	issuer := DEFAULT_TOKEN_ISSUER
+	if p.Issuer != "" {
+		issuer = p.Issuer
+	}
+	ttl := DEFAULT_TOKEN_TTL
+	if p.TTL > 0 {
+		ttl = p.TTL
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	a := &tokenAuth{
+		oAuthRepo:  p.OAuthRepo,
+		hasher:     p.Hasher,
+		signingKey: p.SigningKey,
+		issuer:     issuer,
+		ttl:        ttl,
+		clock:      clock,
+	}
+	return a, nil
+}
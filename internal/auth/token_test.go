@@ -0,0 +1,425 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token Auth Package", func() {
+	Context("NewTokenAuth function", Label("unit"), func() {
+		var (
+			p auth.NewTokenAuthParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = auth.NewTokenAuthParam{
+				OAuthRepo:  mock.NewMockOAuthRepository(ctrl),
+				Hasher:     mock.NewMockHasher(ctrl),
+				SigningKey: []byte("0123456789abcdef0123456789abcdef"),
+			}
+		})
+
+		When("oauth repo is not specified", func() {
+			It("should return error", func() {
+				p.OAuthRepo = nil
+				res, err := auth.NewTokenAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("oauth repo is not specified")))
+			})
+		})
+
+		When("hasher is not specified", func() {
+			It("should return error", func() {
+				p.Hasher = nil
+				res, err := auth.NewTokenAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("hasher is not specified")))
+			})
+		})
+
+		When("signing key is too short", func() {
+			It("should return error", func() {
+				p.SigningKey = []byte("short-key")
+				res, err := auth.NewTokenAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("signing key must be at least 32 bytes")))
+			})
+		})
+
+		When("ttl is invalid", func() {
+			It("should return error", func() {
+				p.TTL = -1
+				res, err := auth.NewTokenAuth(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid token ttl")))
+			})
+		})
+
+		When("all parameter are specified", func() {
+			It("should return result", func() {
+				res, err := auth.NewTokenAuth(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("IssueToken function", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			oAuthRepo  *mock.MockOAuthRepository
+			hasher     *mock.MockHasher
+			clock      *mock.MockClock
+			tokenAuth  auth.TokenAuth
+			p          auth.IssueTokenParam
+			findParam  repository.FindClientParam
+			findResult *repository.FindClientResult
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			hasher = mock.NewMockHasher(ctrl)
+			clock = mock.NewMockClock(ctrl)
+			tokenAuth, _ = auth.NewTokenAuth(auth.NewTokenAuthParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     hasher,
+				SigningKey: []byte("0123456789abcdef0123456789abcdef"),
+				TTL:        15 * time.Minute,
+				Clock:      clock,
+			})
+			p = auth.IssueTokenParam{
+				ClientId:     "client-id",
+				ClientSecret: "client-secret",
+				Scopes:       []string{"file:read"},
+				IpAddress:    "10.0.0.1",
+			}
+			findParam = repository.FindClientParam{
+				ClientId: "client-id",
+			}
+			oAuthRepo.
+				EXPECT().
+				UpdateSecretUsage(gomock.Eq(ctx), gomock.Eq(repository.UpdateSecretUsageParam{
+					SecretId: "secret-id",
+				})).
+				Return(&repository.UpdateSecretUsageResult{}, nil).
+				AnyTimes()
+			findResult = &repository.FindClientResult{
+				ClientId:     "client-id",
+				Scopes:       []string{"file:read", "file:write"},
+				AllowedCidrs: []string{"10.0.0.0/8"},
+				Secrets: []repository.ClientSecret{
+					{Id: "secret-id", Secret: "hashed-secret"},
+				},
+			}
+		})
+
+		When("client credential is not specified", func() {
+			It("should return error", func() {
+				p.ClientSecret = ""
+				res, err := tokenAuth.IssueToken(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorUnknownClient))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := tokenAuth.IssueToken(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorUnknownClient))
+			})
+		})
+
+		When("failed find client", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := tokenAuth.IssueToken(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client secret is invalid", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Verify(gomock.Eq("hashed-secret"), gomock.Eq("client-secret")).
+					Return(fmt.Errorf("hash mismatch")).
+					Times(1)
+
+				res, err := tokenAuth.IssueToken(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidClient))
+			})
+		})
+
+		When("ip address is not allowed", func() {
+			It("should return error", func() {
+				p.IpAddress = "192.168.0.1"
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Verify(gomock.Eq("hashed-secret"), gomock.Eq("client-secret")).
+					Return(nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					NeedsRehash(gomock.Eq("hashed-secret")).
+					Return(false).
+					Times(1)
+
+				res, err := tokenAuth.IssueToken(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorAddressNotAllowed))
+			})
+		})
+
+		When("success issue token", func() {
+			It("should return verifiable token", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClient(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(findResult, nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Verify(gomock.Eq("hashed-secret"), gomock.Eq("client-secret")).
+					Return(nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					NeedsRehash(gomock.Eq("hashed-secret")).
+					Return(false).
+					Times(1)
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(2)
+
+				res, err := tokenAuth.IssueToken(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.TokenType).To(Equal("Bearer"))
+				Expect(res.Scopes).To(Equal([]string{"file:read"}))
+				Expect(res.ExpiresIn).To(Equal(15 * time.Minute))
+				Expect(res.ExpiresAt).To(Equal(currentTs.Add(15 * time.Minute)))
+
+				vRes, err := tokenAuth.VerifyToken(ctx, auth.VerifyTokenParam{
+					Token: res.AccessToken,
+				})
+
+				Expect(vRes).To(Equal(&auth.VerifyTokenResult{
+					ClientId:     "client-id",
+					Scopes:       []string{"file:read"},
+					AllowedCidrs: []string{"10.0.0.0/8"},
+					ExpiresAt:    currentTs.Add(15 * time.Minute),
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("VerifyToken function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			oAuthRepo *mock.MockOAuthRepository
+			hasher    *mock.MockHasher
+			clock     *mock.MockClock
+			tokenAuth auth.TokenAuth
+			token     string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			hasher = mock.NewMockHasher(ctrl)
+			clock = mock.NewMockClock(ctrl)
+			tokenAuth, _ = auth.NewTokenAuth(auth.NewTokenAuthParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     hasher,
+				SigningKey: []byte("0123456789abcdef0123456789abcdef"),
+				TTL:        time.Minute,
+				Clock:      clock,
+			})
+
+			oAuthRepo.
+				EXPECT().
+				FindClient(gomock.Any(), gomock.Any()).
+				Return(&repository.FindClientResult{
+					ClientId: "client-id",
+					Secrets:  []repository.ClientSecret{{Id: "secret-id"}},
+				}, nil).
+				Times(1)
+			oAuthRepo.
+				EXPECT().
+				UpdateSecretUsage(gomock.Any(), gomock.Any()).
+				Return(&repository.UpdateSecretUsageResult{}, nil).
+				Times(1)
+			hasher.
+				EXPECT().
+				Verify(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(1)
+			hasher.
+				EXPECT().
+				NeedsRehash(gomock.Any()).
+				Return(false).
+				Times(1)
+			clock.
+				EXPECT().
+				Now().
+				Return(currentTs).
+				Times(1)
+			res, _ := tokenAuth.IssueToken(ctx, auth.IssueTokenParam{
+				ClientId:     "client-id",
+				ClientSecret: "client-secret",
+			})
+			token = res.AccessToken
+		})
+
+		When("token is malformed", func() {
+			It("should return error", func() {
+				res, err := tokenAuth.VerifyToken(ctx, auth.VerifyTokenParam{
+					Token: "not-a-jwt",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidToken))
+			})
+		})
+
+		When("signature is invalid", func() {
+			It("should return error", func() {
+				parts := strings.Split(token, ".")
+				parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"go-seidon/local","sub":"admin","exp":9999999999}`))
+
+				res, err := tokenAuth.VerifyToken(ctx, auth.VerifyTokenParam{
+					Token: strings.Join(parts, "."),
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidToken))
+			})
+		})
+
+		When("algorithm is not supported", func() {
+			It("should return error", func() {
+				parts := strings.Split(token, ".")
+				parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+				res, err := tokenAuth.VerifyToken(ctx, auth.VerifyTokenParam{
+					Token: strings.Join(parts[:2], ".") + ".",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidToken))
+			})
+		})
+
+		When("token is signed by other key", func() {
+			It("should return error", func() {
+				otherAuth, _ := auth.NewTokenAuth(auth.NewTokenAuthParam{
+					OAuthRepo:  oAuthRepo,
+					Hasher:     hasher,
+					SigningKey: []byte("fedcba9876543210fedcba9876543210"),
+					Clock:      clock,
+				})
+
+				res, err := otherAuth.VerifyToken(ctx, auth.VerifyTokenParam{
+					Token: token,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorInvalidToken))
+			})
+		})
+
+		When("token is expired", func() {
+			It("should return error", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs.Add(time.Minute)).
+					Times(1)
+
+				res, err := tokenAuth.VerifyToken(ctx, auth.VerifyTokenParam{
+					Token: token,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(auth.ErrorTokenExpired))
+			})
+		})
+
+		When("token is valid", func() {
+			It("should return result", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs.Add(59 * time.Second)).
+					Times(1)
+
+				res, err := tokenAuth.VerifyToken(ctx, auth.VerifyTokenParam{
+					Token: token,
+				})
+
+				Expect(res).To(Equal(&auth.VerifyTokenResult{
+					ClientId:     "client-id",
+					Scopes:       []string{},
+					AllowedCidrs: []string{},
+					ExpiresAt:    currentTs.Add(time.Minute),
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,66 @@
+package compressing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+const (
+	CODEC_GZIP = "gzip"
+)
+
+type Codec interface {
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+type gzipCodec struct {
+	level int
+}
+
+func (c *gzipCodec) Name() string {
+	return CODEC_GZIP
+}
+
+func (c *gzipCodec) Compress(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w, err := gzip.NewWriterLevel(&b, c.level)
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (c *gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type NewGzipCodecParam struct {
+	Level int
+}
+
+func NewGzipCodec(p NewGzipCodecParam) (*gzipCodec, error) {
+	level := gzip.DefaultCompression
+	if p.Level != 0 {
+		level = p.Level
+	}
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return nil, fmt.Errorf("invalid compression level")
+	}
+
+	c := &gzipCodec{
+		level: level,
+	}
+	return c, nil
+}
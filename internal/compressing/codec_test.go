@@ -0,0 +1,117 @@
+package compressing_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-seidon/local/internal/compressing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCompressing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Compressing Package")
+}
+
+var _ = Describe("Gzip Codec", func() {
+	Context("NewGzipCodec function", Label("unit"), func() {
+		When("level is not specified", func() {
+			It("should return result", func() {
+				res, err := compressing.NewGzipCodec(compressing.NewGzipCodecParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.Name()).To(Equal(compressing.CODEC_GZIP))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("level is invalid", func() {
+			It("should return error", func() {
+				res, err := compressing.NewGzipCodec(compressing.NewGzipCodecParam{
+					Level: 10,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid compression level")))
+			})
+		})
+	})
+
+	Context("Compress function", Label("unit"), func() {
+		var (
+			codec compressing.Codec
+			data  []byte
+		)
+
+		BeforeEach(func() {
+			codec, _ = compressing.NewGzipCodec(compressing.NewGzipCodecParam{
+				Level: gzip.BestSpeed,
+			})
+			data = []byte(strings.Repeat("lorem ipsum dolor sit amet ", 100))
+		})
+
+		When("success compress data", func() {
+			It("should return gzip stream", func() {
+				res, err := codec.Compress(data)
+
+				Expect(err).To(BeNil())
+				Expect(len(res)).To(BeNumerically("<", len(data)))
+
+				r, err := gzip.NewReader(bytes.NewReader(res))
+				Expect(err).To(BeNil())
+				decompressed, err := io.ReadAll(r)
+				Expect(err).To(BeNil())
+				Expect(decompressed).To(Equal(data))
+			})
+		})
+
+		When("data is empty", func() {
+			It("should return valid gzip stream", func() {
+				res, err := codec.Compress([]byte{})
+				Expect(err).To(BeNil())
+
+				r, err := codec.NewReader(bytes.NewReader(res))
+				Expect(err).To(BeNil())
+				decompressed, err := io.ReadAll(r)
+				Expect(err).To(BeNil())
+				Expect(decompressed).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("NewReader function", Label("unit"), func() {
+		var (
+			codec compressing.Codec
+		)
+
+		BeforeEach(func() {
+			codec, _ = compressing.NewGzipCodec(compressing.NewGzipCodecParam{})
+		})
+
+		When("data is not gzip stream", func() {
+			It("should return error", func() {
+				res, err := codec.NewReader(strings.NewReader("plain text"))
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(gzip.ErrHeader))
+			})
+		})
+
+		When("data is gzip stream", func() {
+			It("should decompress the data", func() {
+				compressed, _ := codec.Compress([]byte("mock-data"))
+				res, err := codec.NewReader(bytes.NewReader(compressed))
+				Expect(err).To(BeNil())
+
+				data, err := io.ReadAll(res)
+				Expect(err).To(BeNil())
+				Expect(data).To(Equal([]byte("mock-data")))
+			})
+		})
+	})
+})
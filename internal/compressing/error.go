@@ -0,0 +1,8 @@
+package compressing
+
+import "errors"
+
+var (
+	ErrorCodecNotSupported = errors.New("codec is not supported")
+	ErrorSizeExceeded      = errors.New("decompressed content exceeds the original size")
+)
@@ -0,0 +1,147 @@
+package compressing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/go-seidon/local/internal/filesystem"
+)
+
+type codecContextKey struct{}
+
+type sizeContextKey struct{}
+
+func NewCodecContext(ctx context.Context, codec string) context.Context {
+	return context.WithValue(ctx, codecContextKey{}, codec)
+}
+
+func CodecFromContext(ctx context.Context) string {
+	codec, _ := ctx.Value(codecContextKey{}).(string)
+	return codec
+}
+
+func NewSizeContext(ctx context.Context, size int64) context.Context {
+	return context.WithValue(ctx, sizeContextKey{}, size)
+}
+
+func SizeFromContext(ctx context.Context) int64 {
+	size, _ := ctx.Value(sizeContextKey{}).(int64)
+	return size
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+type compressedFileManager struct {
+	fileManager filesystem.FileManager
+	codecs      map[string]Codec
+}
+
+func (fm *compressedFileManager) IsFileExists(ctx context.Context, p filesystem.IsFileExistsParam) (bool, error) {
+	return fm.fileManager.IsFileExists(ctx, p)
+}
+
+func (fm *compressedFileManager) OpenFile(ctx context.Context, p filesystem.OpenFileParam) (*filesystem.OpenFileResult, error) {
+	name := CodecFromContext(ctx)
+	if name == "" {
+		return fm.fileManager.OpenFile(ctx, p)
+	}
+
+	codec, ok := fm.codecs[name]
+	if !ok {
+		return nil, ErrorCodecNotSupported
+	}
+
+	size := SizeFromContext(ctx)
+	if size <= 0 {
+		return nil, fmt.Errorf("original size is not specified")
+	}
+
+	oRes, err := fm.fileManager.OpenFile(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	defer oRes.File.Close()
+
+	r, err := codec.NewReader(oRes.File)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, size+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > size {
+		return nil, ErrorSizeExceeded
+	}
+
+	res := &filesystem.OpenFileResult{
+		File: nopCloser{bytes.NewReader(data)},
+	}
+	return res, nil
+}
+
+func (fm *compressedFileManager) SaveFile(ctx context.Context, p filesystem.SaveFileParam) (*filesystem.SaveFileResult, error) {
+	name := CodecFromContext(ctx)
+	if name == "" {
+		return fm.fileManager.SaveFile(ctx, p)
+	}
+
+	codec, ok := fm.codecs[name]
+	if !ok {
+		return nil, ErrorCodecNotSupported
+	}
+
+	data, err := codec.Compress(p.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return fm.fileManager.SaveFile(ctx, filesystem.SaveFileParam{
+		Name:       p.Name,
+		Data:       data,
+		Permission: p.Permission,
+	})
+}
+
+func (fm *compressedFileManager) RemoveFile(ctx context.Context, p filesystem.RemoveFileParam) (*filesystem.RemoveFileResult, error) {
+	return fm.fileManager.RemoveFile(ctx, p)
+}
+
+func (fm *compressedFileManager) MoveFile(ctx context.Context, p filesystem.MoveFileParam) (*filesystem.MoveFileResult, error) {
+	return fm.fileManager.MoveFile(ctx, p)
+}
+
+type NewCompressedFileManagerParam struct {
+	FileManager filesystem.FileManager
+	Codecs      []Codec
+}
+
+func NewCompressedFileManager(p NewCompressedFileManagerParam) (*compressedFileManager, error) {
+	if p.FileManager == nil {
+		return nil, fmt.Errorf("file manager is not specified")
+	}
+	if len(p.Codecs) == 0 {
+		return nil, fmt.Errorf("codec is not specified")
+	}
+
+	codecs := map[string]Codec{}
+	for _, codec := range p.Codecs {
+		codecs[codec.Name()] = codec
+	}
+
+	fm := &compressedFileManager{
+		fileManager: p.FileManager,
+		codecs:      codecs,
+	}
+	return fm, nil
+}
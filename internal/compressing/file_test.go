@@ -0,0 +1,376 @@
+package compressing_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-seidon/local/internal/compressing"
+	"github.com/go-seidon/local/internal/filesystem"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type readSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (readSeekCloser) Close() error {
+	return nil
+}
+
+var _ = Describe("Compressed File Manager", func() {
+	Context("NewCompressedFileManager function", Label("unit"), func() {
+		var (
+			p compressing.NewCompressedFileManagerParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			codec, _ := compressing.NewGzipCodec(compressing.NewGzipCodecParam{})
+			p = compressing.NewCompressedFileManagerParam{
+				FileManager: mock.NewMockFileManager(ctrl),
+				Codecs:      []compressing.Codec{codec},
+			}
+		})
+
+		When("success create file manager", func() {
+			It("should return result", func() {
+				res, err := compressing.NewCompressedFileManager(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file manager is not specified", func() {
+			It("should return error", func() {
+				p.FileManager = nil
+				res, err := compressing.NewCompressedFileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file manager is not specified")))
+			})
+		})
+
+		When("codec is not specified", func() {
+			It("should return error", func() {
+				p.Codecs = nil
+				res, err := compressing.NewCompressedFileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("codec is not specified")))
+			})
+		})
+	})
+
+	Context("SizeFromContext function", Label("unit"), func() {
+		When("size is not attached", func() {
+			It("should return zero", func() {
+				res := compressing.SizeFromContext(context.Background())
+
+				Expect(res).To(Equal(int64(0)))
+			})
+		})
+
+		When("size is attached", func() {
+			It("should return the size", func() {
+				ctx := compressing.NewSizeContext(context.Background(), 1024)
+				res := compressing.SizeFromContext(ctx)
+
+				Expect(res).To(Equal(int64(1024)))
+			})
+		})
+	})
+
+	Context("CodecFromContext function", Label("unit"), func() {
+		When("codec is not attached", func() {
+			It("should return empty codec", func() {
+				res := compressing.CodecFromContext(context.Background())
+
+				Expect(res).To(Equal(""))
+			})
+		})
+
+		When("codec is attached", func() {
+			It("should return the codec", func() {
+				ctx := compressing.NewCodecContext(context.Background(), compressing.CODEC_GZIP)
+				res := compressing.CodecFromContext(ctx)
+
+				Expect(res).To(Equal(compressing.CODEC_GZIP))
+			})
+		})
+	})
+
+	Context("SaveFile function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			fileManager *mock.MockFileManager
+			codec       compressing.Codec
+			fm          filesystem.FileManager
+			p           filesystem.SaveFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			fileManager = mock.NewMockFileManager(ctrl)
+			codec, _ = compressing.NewGzipCodec(compressing.NewGzipCodecParam{})
+			fm, _ = compressing.NewCompressedFileManager(compressing.NewCompressedFileManagerParam{
+				FileManager: fileManager,
+				Codecs:      []compressing.Codec{codec},
+			})
+			p = filesystem.SaveFileParam{
+				Name:       "storage/mock-id.txt",
+				Data:       []byte("mock-data"),
+				Permission: 0644,
+			}
+		})
+
+		When("codec is not attached", func() {
+			It("should save the data as is", func() {
+				fileManager.
+					EXPECT().
+					SaveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&filesystem.SaveFileResult{}, nil).
+					Times(1)
+
+				res, err := fm.SaveFile(ctx, p)
+
+				Expect(res).To(Equal(&filesystem.SaveFileResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("codec is not supported", func() {
+			It("should return error", func() {
+				ctx = compressing.NewCodecContext(ctx, "zstd")
+				res, err := fm.SaveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(compressing.ErrorCodecNotSupported))
+			})
+		})
+
+		When("failed save file", func() {
+			It("should return error", func() {
+				ctx = compressing.NewCodecContext(ctx, compressing.CODEC_GZIP)
+				fileManager.
+					EXPECT().
+					SaveFile(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("disk error")).
+					Times(1)
+
+				res, err := fm.SaveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
+			})
+		})
+
+		When("codec is attached", func() {
+			It("should save the compressed data", func() {
+				ctx = compressing.NewCodecContext(ctx, compressing.CODEC_GZIP)
+				fileManager.
+					EXPECT().
+					SaveFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, sp filesystem.SaveFileParam) (*filesystem.SaveFileResult, error) {
+						Expect(sp.Name).To(Equal(p.Name))
+						Expect(sp.Permission).To(Equal(p.Permission))
+
+						r, err := codec.NewReader(bytes.NewReader(sp.Data))
+						Expect(err).To(BeNil())
+						data, err := io.ReadAll(r)
+						Expect(err).To(BeNil())
+						Expect(data).To(Equal(p.Data))
+						return &filesystem.SaveFileResult{}, nil
+					}).
+					Times(1)
+
+				res, err := fm.SaveFile(ctx, p)
+
+				Expect(res).To(Equal(&filesystem.SaveFileResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("OpenFile function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			fileManager *mock.MockFileManager
+			codec       compressing.Codec
+			fm          filesystem.FileManager
+			p           filesystem.OpenFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			fileManager = mock.NewMockFileManager(ctrl)
+			codec, _ = compressing.NewGzipCodec(compressing.NewGzipCodecParam{})
+			fm, _ = compressing.NewCompressedFileManager(compressing.NewCompressedFileManagerParam{
+				FileManager: fileManager,
+				Codecs:      []compressing.Codec{codec},
+			})
+			p = filesystem.OpenFileParam{
+				Path: "storage/mock-id.txt",
+			}
+		})
+
+		When("codec is not attached", func() {
+			It("should return the file as is", func() {
+				oRes := &filesystem.OpenFileResult{
+					File: &os.File{},
+				}
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(oRes, nil).
+					Times(1)
+
+				res, err := fm.OpenFile(ctx, p)
+
+				Expect(res).To(Equal(oRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("codec is not supported", func() {
+			It("should return error", func() {
+				ctx = compressing.NewCodecContext(ctx, "zstd")
+				res, err := fm.OpenFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(compressing.ErrorCodecNotSupported))
+			})
+		})
+
+		When("original size is not specified", func() {
+			It("should return error", func() {
+				ctx = compressing.NewCodecContext(ctx, compressing.CODEC_GZIP)
+				res, err := fm.OpenFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("original size is not specified")))
+			})
+		})
+
+		When("decompressed content exceeds the original size", func() {
+			It("should return error", func() {
+				ctx = compressing.NewCodecContext(ctx, compressing.CODEC_GZIP)
+				ctx = compressing.NewSizeContext(ctx, 4)
+				compressed, _ := codec.Compress([]byte("mock-data"))
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&filesystem.OpenFileResult{
+						File: readSeekCloser{bytes.NewReader(compressed)},
+					}, nil).
+					Times(1)
+
+				res, err := fm.OpenFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(compressing.ErrorSizeExceeded))
+			})
+		})
+
+		When("failed open file", func() {
+			It("should return error", func() {
+				ctx = compressing.NewCodecContext(ctx, compressing.CODEC_GZIP)
+				ctx = compressing.NewSizeContext(ctx, 9)
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, filesystem.ErrorFileNotFound).
+					Times(1)
+
+				res, err := fm.OpenFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+			})
+		})
+
+		When("file is not compressed", func() {
+			It("should return error", func() {
+				ctx = compressing.NewCodecContext(ctx, compressing.CODEC_GZIP)
+				ctx = compressing.NewSizeContext(ctx, 9)
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&filesystem.OpenFileResult{
+						File: readSeekCloser{bytes.NewReader([]byte("plain text"))},
+					}, nil).
+					Times(1)
+
+				res, err := fm.OpenFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("codec is attached", func() {
+			It("should return seekable decompressed file", func() {
+				ctx = compressing.NewCodecContext(ctx, compressing.CODEC_GZIP)
+				ctx = compressing.NewSizeContext(ctx, 9)
+				compressed, _ := codec.Compress([]byte("mock-data"))
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&filesystem.OpenFileResult{
+						File: readSeekCloser{bytes.NewReader(compressed)},
+					}, nil).
+					Times(1)
+
+				res, err := fm.OpenFile(ctx, p)
+				Expect(err).To(BeNil())
+
+				data, err := io.ReadAll(res.File)
+				Expect(err).To(BeNil())
+				Expect(data).To(Equal([]byte("mock-data")))
+
+				_, err = res.File.Seek(5, io.SeekStart)
+				Expect(err).To(BeNil())
+				data, err = io.ReadAll(res.File)
+				Expect(err).To(BeNil())
+				Expect(data).To(Equal([]byte("data")))
+				Expect(res.File.Close()).To(BeNil())
+			})
+		})
+	})
+
+	Context("RemoveFile function", Label("unit"), func() {
+		When("codec is attached", func() {
+			It("should remove the file as is", func() {
+				ctx := compressing.NewCodecContext(context.Background(), compressing.CODEC_GZIP)
+				ctrl := gomock.NewController(GinkgoT())
+				fileManager := mock.NewMockFileManager(ctrl)
+				codec, _ := compressing.NewGzipCodec(compressing.NewGzipCodecParam{})
+				fm, _ := compressing.NewCompressedFileManager(compressing.NewCompressedFileManagerParam{
+					FileManager: fileManager,
+					Codecs:      []compressing.Codec{codec},
+				})
+				p := filesystem.RemoveFileParam{
+					Path: "storage/mock-id.txt",
+				}
+				fileManager.
+					EXPECT().
+					RemoveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&filesystem.RemoveFileResult{}, nil).
+					Times(1)
+
+				res, err := fm.RemoveFile(ctx, p)
+
+				Expect(res).To(Equal(&filesystem.RemoveFileResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,90 @@
+package compressing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	DEFAULT_MIN_SIZE = 1024
+)
+
+var DEFAULT_MIMETYPES = []string{
+	"text/*",
+	"application/json",
+	"application/x-ndjson",
+	"application/xml",
+	"application/javascript",
+	"application/x-yaml",
+	"application/sql",
+	"image/svg+xml",
+}
+
+type CompressionPolicy interface {
+	SelectCodec(ctx context.Context, p SelectCodecParam) string
+}
+
+type SelectCodecParam struct {
+	Mimetype string
+	Size     int64
+}
+
+type compressionPolicy struct {
+	codec     string
+	mimetypes map[string]bool
+	minSize   int64
+}
+
+func (cp *compressionPolicy) SelectCodec(ctx context.Context, p SelectCodecParam) string {
+	if p.Size < cp.minSize {
+		return ""
+	}
+
+	mimetype := strings.ToLower(strings.TrimSpace(strings.Split(p.Mimetype, ";")[0]))
+	if cp.mimetypes[mimetype] {
+		return cp.codec
+	}
+	if cp.mimetypes[strings.Split(mimetype, "/")[0]+"/*"] {
+		return cp.codec
+	}
+	if strings.HasSuffix(mimetype, "+json") || strings.HasSuffix(mimetype, "+xml") {
+		return cp.codec
+	}
+	return ""
+}
+
+type NewCompressionPolicyParam struct {
+	Codec     string
+	Mimetypes []string
+	MinSize   int64
+}
+
+func NewCompressionPolicy(p NewCompressionPolicyParam) (*compressionPolicy, error) {
+	if p.Codec == "" {
+		return nil, fmt.Errorf("codec is not specified")
+	}
+	if p.MinSize < 0 {
+		return nil, fmt.Errorf("invalid min size")
+	}
+
+	minSize := int64(DEFAULT_MIN_SIZE)
+	if p.MinSize > 0 {
+		minSize = p.MinSize
+	}
+	list := DEFAULT_MIMETYPES
+	if len(p.Mimetypes) > 0 {
+		list = p.Mimetypes
+	}
+	mimetypes := map[string]bool{}
+	for _, m := range list {
+		mimetypes[strings.ToLower(strings.TrimSpace(m))] = true
+	}
+
+	cp := &compressionPolicy{
+		codec:     p.Codec,
+		mimetypes: mimetypes,
+		minSize:   minSize,
+	}
+	return cp, nil
+}
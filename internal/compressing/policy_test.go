@@ -0,0 +1,137 @@
+package compressing_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-seidon/local/internal/compressing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compression Policy", func() {
+	Context("NewCompressionPolicy function", Label("unit"), func() {
+		When("codec is not specified", func() {
+			It("should return error", func() {
+				res, err := compressing.NewCompressionPolicy(compressing.NewCompressionPolicyParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("codec is not specified")))
+			})
+		})
+
+		When("min size is invalid", func() {
+			It("should return error", func() {
+				res, err := compressing.NewCompressionPolicy(compressing.NewCompressionPolicyParam{
+					Codec:   compressing.CODEC_GZIP,
+					MinSize: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid min size")))
+			})
+		})
+
+		When("success create policy", func() {
+			It("should return result", func() {
+				res, err := compressing.NewCompressionPolicy(compressing.NewCompressionPolicyParam{
+					Codec: compressing.CODEC_GZIP,
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("SelectCodec function", Label("unit"), func() {
+		var (
+			ctx    context.Context
+			policy compressing.CompressionPolicy
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			policy, _ = compressing.NewCompressionPolicy(compressing.NewCompressionPolicyParam{
+				Codec: compressing.CODEC_GZIP,
+			})
+		})
+
+		When("file is smaller than min size", func() {
+			It("should return empty codec", func() {
+				res := policy.SelectCodec(ctx, compressing.SelectCodecParam{
+					Mimetype: "text/plain",
+					Size:     compressing.DEFAULT_MIN_SIZE - 1,
+				})
+
+				Expect(res).To(Equal(""))
+			})
+		})
+
+		When("mimetype is not compressible", func() {
+			It("should return empty codec", func() {
+				res := policy.SelectCodec(ctx, compressing.SelectCodecParam{
+					Mimetype: "image/jpeg",
+					Size:     4096,
+				})
+
+				Expect(res).To(Equal(""))
+			})
+		})
+
+		When("mimetype is matched exactly", func() {
+			It("should return the codec", func() {
+				res := policy.SelectCodec(ctx, compressing.SelectCodecParam{
+					Mimetype: "Application/JSON; charset=utf-8",
+					Size:     4096,
+				})
+
+				Expect(res).To(Equal(compressing.CODEC_GZIP))
+			})
+		})
+
+		When("mimetype is matched by wildcard", func() {
+			It("should return the codec", func() {
+				res := policy.SelectCodec(ctx, compressing.SelectCodecParam{
+					Mimetype: "text/csv",
+					Size:     4096,
+				})
+
+				Expect(res).To(Equal(compressing.CODEC_GZIP))
+			})
+		})
+
+		When("mimetype has structured syntax suffix", func() {
+			It("should return the codec", func() {
+				res := policy.SelectCodec(ctx, compressing.SelectCodecParam{
+					Mimetype: "application/vnd.api+json",
+					Size:     4096,
+				})
+
+				Expect(res).To(Equal(compressing.CODEC_GZIP))
+			})
+		})
+
+		When("mimetypes are specified", func() {
+			It("should only compress the specified mimetypes", func() {
+				policy, _ = compressing.NewCompressionPolicy(compressing.NewCompressionPolicyParam{
+					Codec:     compressing.CODEC_GZIP,
+					Mimetypes: []string{"application/pdf"},
+					MinSize:   10,
+				})
+
+				pdf := policy.SelectCodec(ctx, compressing.SelectCodecParam{
+					Mimetype: "application/pdf",
+					Size:     10,
+				})
+				text := policy.SelectCodec(ctx, compressing.SelectCodecParam{
+					Mimetype: "text/plain",
+					Size:     10,
+				})
+
+				Expect(pdf).To(Equal(compressing.CODEC_GZIP))
+				Expect(text).To(Equal(""))
+			})
+		})
+	})
+})
@@ -16,7 +16,8 @@ type Deleter interface {
 }
 
 type DeleteFileParam struct {
-	FileId string
+	FileId   string
+	ClientId string
 }
 
 type DeleteFileResult struct {
@@ -24,9 +25,10 @@ type DeleteFileResult struct {
 }
 
 type deleter struct {
-	fileRepo    repository.FileRepository
-	fileManager filesystem.FileManager
-	log         logging.Logger
+	fileRepo         repository.FileRepository
+	fileManager      filesystem.FileManager
+	log              logging.Logger
+	legacyFileAccess bool
 }
 
 func NewDeleteFn(fileManager filesystem.FileManager) repository.DeleteFn {
@@ -62,14 +64,19 @@ func (s *deleter) DeleteFile(ctx context.Context, p DeleteFileParam) (*DeleteFil
 	}
 
 	delRes, err := s.fileRepo.DeleteFile(ctx, repository.DeleteFileParam{
-		UniqueId: p.FileId,
-		DeleteFn: NewDeleteFn(s.fileManager),
+		UniqueId:     p.FileId,
+		ClientId:     p.ClientId,
+		AllowUnowned: s.legacyFileAccess,
+		DeleteFn:     NewDeleteFn(s.fileManager),
 	})
 
 	if err != nil {
 		if errors.Is(err, repository.ErrorRecordNotFound) {
 			return nil, ErrorResourceNotFound
 		}
+		if errors.Is(err, repository.ErrorAccessDenied) {
+			return nil, ErrorForbidden
+		}
 		return nil, err
 	}
 
@@ -80,9 +87,10 @@ func (s *deleter) DeleteFile(ctx context.Context, p DeleteFileParam) (*DeleteFil
 }
 
 type NewDeleterParam struct {
-	FileRepo    repository.FileRepository
-	FileManager filesystem.FileManager
-	Logger      logging.Logger
+	FileRepo         repository.FileRepository
+	FileManager      filesystem.FileManager
+	Logger           logging.Logger
+	LegacyFileAccess bool
 }
 
 func NewDeleter(p NewDeleterParam) (*deleter, error) {
@@ -97,9 +105,10 @@ func NewDeleter(p NewDeleterParam) (*deleter, error) {
 	}
 
 	s := &deleter{
-		fileRepo:    p.FileRepo,
-		fileManager: p.FileManager,
-		log:         p.Logger,
+		fileRepo:         p.FileRepo,
+		fileManager:      p.FileManager,
+		log:              p.Logger,
+		legacyFileAccess: p.LegacyFileAccess,
 	}
 	return s, nil
 }
@@ -166,6 +166,50 @@ var _ = Describe("Deleter Service", func() {
 			})
 		})
 
+		When("client has no access to the file", func() {
+			It("should return error", func() {
+				p.ClientId = "other-client"
+				fileRepo.
+					EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, dp repository.DeleteFileParam) (*repository.DeleteFileResult, error) {
+						Expect(dp.UniqueId).To(Equal("mock-file-id"))
+						Expect(dp.ClientId).To(Equal("other-client"))
+						return nil, repository.ErrorAccessDenied
+					}).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(deleting.ErrorForbidden))
+			})
+		})
+
+		When("legacy file access is allowed", func() {
+			It("should allow the file without owner", func() {
+				s, _ = deleting.NewDeleter(deleting.NewDeleterParam{
+					FileRepo:         fileRepo,
+					FileManager:      fileManager,
+					Logger:           log,
+					LegacyFileAccess: true,
+				})
+				fileRepo.
+					EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, dp repository.DeleteFileParam) (*repository.DeleteFileResult, error) {
+						Expect(dp.AllowUnowned).To(BeTrue())
+						return deleteRes, nil
+					}).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(Equal(finalRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
 		When("failed success file", func() {
 			It("should return result", func() {
 				fileRepo.
@@ -0,0 +1,10 @@
+package encrypting
+
+import "errors"
+
+var (
+	ErrorKeyNotFound       = errors.New("master key not found")
+	ErrorDecryptionFailed  = errors.New("failed decrypt file")
+	ErrorFileNotEncrypted  = errors.New("file is not encrypted")
+	ErrorInvalidFileHeader = errors.New("invalid encrypted file header")
+)
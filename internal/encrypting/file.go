@@ -0,0 +1,504 @@
+package encrypting
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/filesystem"
+)
+
+const (
+	HEADER_MAGIC       = "GSLENC01"
+	DATA_KEY_SIZE      = 32
+	NONCE_PREFIX_SIZE  = 7
+	DEFAULT_CHUNK_SIZE = 64 * 1024
+	MAX_CHUNK_SIZE     = 16 * 1024 * 1024
+)
+
+type EncryptedFileManager interface {
+	filesystem.FileManager
+	RekeyFile(ctx context.Context, p RekeyFileParam) (*RekeyFileResult, error)
+}
+
+type RekeyFileParam struct {
+	Path string
+}
+
+type RekeyFileResult struct {
+	OldKeyId  string
+	NewKeyId  string
+	RekeyedAt time.Time
+}
+
+// encrypted file format:
+// header: magic (8) | key id size (1) | key id | wrapped key size (2) | wrapped key | chunk size (4) | nonce prefix (7)
+// body: sequence of frames, each frame is a chunk of plaintext sealed with its own nonce,
+// the nonce is nonce prefix | frame index (4) | last frame flag (1) so frames can not be reordered or truncated
+type fileHeader struct {
+	keyId       string
+	wrappedKey  []byte
+	chunkSize   int64
+	noncePrefix []byte
+}
+
+func (h *fileHeader) Encode() []byte {
+	b := bytes.NewBufferString(HEADER_MAGIC)
+	b.WriteByte(byte(len(h.keyId)))
+	b.WriteString(h.keyId)
+	binary.Write(b, binary.BigEndian, uint16(len(h.wrappedKey)))
+	b.Write(h.wrappedKey)
+	binary.Write(b, binary.BigEndian, uint32(h.chunkSize))
+	b.Write(h.noncePrefix)
+	return b.Bytes()
+}
+
+func (h *fileHeader) Size() int64 {
+	return int64(len(HEADER_MAGIC) + 1 + len(h.keyId) + 2 + len(h.wrappedKey) + 4 + NONCE_PREFIX_SIZE)
+}
+
+func readFileHeader(r io.Reader) (*fileHeader, error) {
+	magic := make([]byte, len(HEADER_MAGIC))
+	_, err := io.ReadFull(r, magic)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, ErrorFileNotEncrypted
+	}
+	if err != nil {
+		return nil, err
+	}
+	if string(magic) != HEADER_MAGIC {
+		return nil, ErrorFileNotEncrypted
+	}
+
+	size := make([]byte, 1)
+	_, err = io.ReadFull(r, size)
+	if err != nil {
+		return nil, ErrorInvalidFileHeader
+	}
+	keyId := make([]byte, size[0])
+	_, err = io.ReadFull(r, keyId)
+	if err != nil {
+		return nil, ErrorInvalidFileHeader
+	}
+
+	var wrappedSize uint16
+	err = binary.Read(r, binary.BigEndian, &wrappedSize)
+	if err != nil {
+		return nil, ErrorInvalidFileHeader
+	}
+	wrappedKey := make([]byte, wrappedSize)
+	_, err = io.ReadFull(r, wrappedKey)
+	if err != nil {
+		return nil, ErrorInvalidFileHeader
+	}
+
+	var chunkSize uint32
+	err = binary.Read(r, binary.BigEndian, &chunkSize)
+	if err != nil {
+		return nil, ErrorInvalidFileHeader
+	}
+	if chunkSize == 0 || chunkSize > MAX_CHUNK_SIZE {
+		return nil, ErrorInvalidFileHeader
+	}
+
+	noncePrefix := make([]byte, NONCE_PREFIX_SIZE)
+	_, err = io.ReadFull(r, noncePrefix)
+	if err != nil {
+		return nil, ErrorInvalidFileHeader
+	}
+
+	h := &fileHeader{
+		keyId:       string(keyId),
+		wrappedKey:  wrappedKey,
+		chunkSize:   int64(chunkSize),
+		noncePrefix: noncePrefix,
+	}
+	return h, nil
+}
+
+func frameNonce(prefix []byte, index int64, last bool) []byte {
+	nonce := make([]byte, 0, NONCE_PREFIX_SIZE+5)
+	nonce = append(nonce, prefix...)
+	nonce = append(nonce, byte(index>>24), byte(index>>16), byte(index>>8), byte(index))
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type encryptedFileManager struct {
+	fileManager filesystem.FileManager
+	keyring     Keyring
+	chunkSize   int64
+	random      io.Reader
+	clock       datetime.Clock
+}
+
+func (fm *encryptedFileManager) IsFileExists(ctx context.Context, p filesystem.IsFileExistsParam) (bool, error) {
+	return fm.fileManager.IsFileExists(ctx, p)
+}
+
+// the file is decrypted lazily per frame, files stored before encryption
+// is enabled are returned as is
+func (fm *encryptedFileManager) OpenFile(ctx context.Context, p filesystem.OpenFileParam) (*filesystem.OpenFileResult, error) {
+	oRes, err := fm.fileManager.OpenFile(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fm.openEncryptedFile(oRes.File)
+	if err == nil {
+		res := &filesystem.OpenFileResult{
+			File: file,
+		}
+		return res, nil
+	}
+
+	if err == ErrorFileNotEncrypted {
+		_, err = oRes.File.Seek(0, io.SeekStart)
+		if err == nil {
+			return oRes, nil
+		}
+	}
+	oRes.File.Close()
+	return nil, err
+}
+
+func (fm *encryptedFileManager) openEncryptedFile(file io.ReadSeekCloser) (*decryptedFile, error) {
+	header, err := readFileHeader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := fm.keyring.UnwrapKey(header.keyId, header.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAead(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	frameSize := header.chunkSize + int64(aead.Overhead())
+	bodySize := end - header.Size()
+	frames := (bodySize + frameSize - 1) / frameSize
+	size := bodySize - frames*int64(aead.Overhead())
+	if frames == 0 || size < 0 || size > frames*header.chunkSize {
+		return nil, ErrorDecryptionFailed
+	}
+
+	f := &decryptedFile{
+		file:       file,
+		aead:       aead,
+		header:     header,
+		dataOffset: header.Size(),
+		bodySize:   bodySize,
+		frameSize:  frameSize,
+		frames:     frames,
+		size:       size,
+		frameIndex: -1,
+	}
+	return f, nil
+}
+
+func (fm *encryptedFileManager) SaveFile(ctx context.Context, p filesystem.SaveFileParam) (*filesystem.SaveFileResult, error) {
+	data, err := fm.encrypt(p.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return fm.fileManager.SaveFile(ctx, filesystem.SaveFileParam{
+		Name:       p.Name,
+		Data:       data,
+		Permission: p.Permission,
+	})
+}
+
+func (fm *encryptedFileManager) encrypt(data []byte) ([]byte, error) {
+	dataKey := make([]byte, DATA_KEY_SIZE)
+	_, err := io.ReadFull(fm.random, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	wRes, err := fm.keyring.WrapKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	noncePrefix := make([]byte, NONCE_PREFIX_SIZE)
+	_, err = io.ReadFull(fm.random, noncePrefix)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAead(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &fileHeader{
+		keyId:       wRes.KeyId,
+		wrappedKey:  wRes.WrappedKey,
+		chunkSize:   fm.chunkSize,
+		noncePrefix: noncePrefix,
+	}
+
+	frames := (int64(len(data)) + fm.chunkSize - 1) / fm.chunkSize
+	if frames == 0 {
+		frames = 1
+	}
+	res := make([]byte, 0, header.Size()+int64(len(data))+frames*int64(aead.Overhead()))
+	res = append(res, header.Encode()...)
+	for i := int64(0); i < frames; i++ {
+		start := i * fm.chunkSize
+		end := start + fm.chunkSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		nonce := frameNonce(noncePrefix, i, i == frames-1)
+		res = aead.Seal(res, nonce, data[start:end], nil)
+	}
+	return res, nil
+}
+
+func (fm *encryptedFileManager) RemoveFile(ctx context.Context, p filesystem.RemoveFileParam) (*filesystem.RemoveFileResult, error) {
+	return fm.fileManager.RemoveFile(ctx, p)
+}
+
+func (fm *encryptedFileManager) MoveFile(ctx context.Context, p filesystem.MoveFileParam) (*filesystem.MoveFileResult, error) {
+	return fm.fileManager.MoveFile(ctx, p)
+}
+
+// only the header is changed, the new content is written into temporary file
+// and moved into the original path so the file is never left half written,
+// the body is streamed when the file manager is able to write from the reader
+func (fm *encryptedFileManager) RekeyFile(ctx context.Context, p RekeyFileParam) (*RekeyFileResult, error) {
+	oRes, err := fm.fileManager.OpenFile(ctx, filesystem.OpenFileParam{
+		Path: p.Path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer oRes.File.Close()
+
+	header, err := readFileHeader(oRes.File)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RekeyFileResult{
+		OldKeyId: header.keyId,
+		NewKeyId: fm.keyring.CurrentKeyId(),
+	}
+	if header.keyId == res.NewKeyId {
+		res.RekeyedAt = fm.clock.Now()
+		return res, nil
+	}
+
+	dataKey, err := fm.keyring.UnwrapKey(header.keyId, header.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	wRes, err := fm.keyring.WrapKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	newHeader := &fileHeader{
+		keyId:       wRes.KeyId,
+		wrappedKey:  wRes.WrappedKey,
+		chunkSize:   header.chunkSize,
+		noncePrefix: header.noncePrefix,
+	}
+	tempPath := p.Path + ".rekey"
+	err = fm.writeRekeyedFile(ctx, tempPath, newHeader, header.Size(), oRes.File)
+	if err != nil {
+		return nil, err
+	}
+	_, err = fm.fileManager.MoveFile(ctx, filesystem.MoveFileParam{
+		OldPath: tempPath,
+		NewPath: p.Path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res.NewKeyId = wRes.KeyId
+	res.RekeyedAt = fm.clock.Now()
+	return res, nil
+}
+
+func (fm *encryptedFileManager) writeRekeyedFile(ctx context.Context, path string, header *fileHeader, bodyOffset int64, file io.ReadSeeker) error {
+	writer, ok := fm.fileManager.(filesystem.FileWriter)
+	if !ok {
+		body, err := io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		_, err = fm.fileManager.SaveFile(ctx, filesystem.SaveFileParam{
+			Name:       path,
+			Data:       append(header.Encode(), body...),
+			Permission: 0644,
+		})
+		return err
+	}
+
+	end, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	_, err = file.Seek(bodyOffset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	_, err = writer.WriteFile(ctx, filesystem.WriteFileParam{
+		Name:       path,
+		Reader:     io.MultiReader(bytes.NewReader(header.Encode()), file),
+		Size:       header.Size() + end - bodyOffset,
+		Permission: 0644,
+	})
+	return err
+}
+
+type decryptedFile struct {
+	file       io.ReadSeekCloser
+	aead       cipher.AEAD
+	header     *fileHeader
+	dataOffset int64
+	bodySize   int64
+	frameSize  int64
+	frames     int64
+	size       int64
+	offset     int64
+	frameIndex int64
+	frame      []byte
+}
+
+func (f *decryptedFile) Read(p []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+
+	index := f.offset / f.header.chunkSize
+	err := f.loadFrame(index)
+	if err != nil {
+		return 0, err
+	}
+
+	n := copy(p, f.frame[f.offset-index*f.header.chunkSize:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *decryptedFile) loadFrame(index int64) error {
+	if f.frameIndex == index {
+		return nil
+	}
+
+	start := index * f.frameSize
+	size := f.frameSize
+	if start+size > f.bodySize {
+		size = f.bodySize - start
+	}
+	_, err := f.file.Seek(f.dataOffset+start, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	sealed := make([]byte, size)
+	_, err = io.ReadFull(f.file, sealed)
+	if err != nil {
+		return err
+	}
+
+	nonce := frameNonce(f.header.noncePrefix, index, index == f.frames-1)
+	frame, err := f.aead.Open(sealed[:0], nonce, sealed, nil)
+	if err != nil {
+		return ErrorDecryptionFailed
+	}
+	f.frame = frame
+	f.frameIndex = index
+	return nil
+}
+
+func (f *decryptedFile) Seek(offset int64, whence int) (int64, error) {
+	var next int64
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = f.offset + offset
+	case io.SeekEnd:
+		next = f.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence")
+	}
+	if next < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+	f.offset = next
+	return next, nil
+}
+
+func (f *decryptedFile) Close() error {
+	return f.file.Close()
+}
+
+type NewEncryptedFileManagerParam struct {
+	FileManager filesystem.FileManager
+	Keyring     Keyring
+	ChunkSize   int64
+	Random      io.Reader
+	Clock       datetime.Clock
+}
+
+func NewEncryptedFileManager(p NewEncryptedFileManagerParam) (*encryptedFileManager, error) {
+	if p.FileManager == nil {
+		return nil, fmt.Errorf("file manager is not specified")
+	}
+	if p.Keyring == nil {
+		return nil, fmt.Errorf("keyring is not specified")
+	}
+	if p.ChunkSize < 0 || p.ChunkSize > MAX_CHUNK_SIZE {
+		return nil, fmt.Errorf("invalid chunk size")
+	}
+
+	chunkSize := int64(DEFAULT_CHUNK_SIZE)
+	if p.ChunkSize > 0 {
+		chunkSize = p.ChunkSize
+	}
+	random := p.Random
+	if random == nil {
+		random = rand.Reader
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	fm := &encryptedFileManager{
+		fileManager: p.FileManager,
+		keyring:     p.Keyring,
+		chunkSize:   chunkSize,
+		random:      random,
+		clock:       clock,
+	}
+	return fm, nil
+}
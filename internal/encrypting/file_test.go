@@ -0,0 +1,486 @@
+package encrypting_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-seidon/local/internal/encrypting"
+	"github.com/go-seidon/local/internal/filesystem"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type memoryFile struct {
+	*bytes.Reader
+	closed bool
+}
+
+func (f *memoryFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+// @note: in memory file manager used as the underlying storage
+type memoryFileManager struct {
+	files map[string][]byte
+	// paths written from the reader
+	written []string
+}
+
+func (fm *memoryFileManager) IsFileExists(ctx context.Context, p filesystem.IsFileExistsParam) (bool, error) {
+	_, ok := fm.files[p.Path]
+	return ok, nil
+}
+
+func (fm *memoryFileManager) OpenFile(ctx context.Context, p filesystem.OpenFileParam) (*filesystem.OpenFileResult, error) {
+	data, ok := fm.files[p.Path]
+	if !ok {
+		return nil, filesystem.ErrorFileNotFound
+	}
+	return &filesystem.OpenFileResult{File: &memoryFile{Reader: bytes.NewReader(data)}}, nil
+}
+
+func (fm *memoryFileManager) SaveFile(ctx context.Context, p filesystem.SaveFileParam) (*filesystem.SaveFileResult, error) {
+	fm.files[p.Name] = p.Data
+	return &filesystem.SaveFileResult{SavedAt: time.Now()}, nil
+}
+
+func (fm *memoryFileManager) WriteFile(ctx context.Context, p filesystem.WriteFileParam) (*filesystem.WriteFileResult, error) {
+	data, err := io.ReadAll(p.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != p.Size {
+		return nil, fmt.Errorf("content size does not match")
+	}
+	fm.files[p.Name] = data
+	fm.written = append(fm.written, p.Name)
+	return &filesystem.WriteFileResult{WrittenAt: time.Now()}, nil
+}
+
+func (fm *memoryFileManager) RemoveFile(ctx context.Context, p filesystem.RemoveFileParam) (*filesystem.RemoveFileResult, error) {
+	delete(fm.files, p.Path)
+	return &filesystem.RemoveFileResult{RemovedAt: time.Now()}, nil
+}
+
+func (fm *memoryFileManager) MoveFile(ctx context.Context, p filesystem.MoveFileParam) (*filesystem.MoveFileResult, error) {
+	fm.files[p.NewPath] = fm.files[p.OldPath]
+	delete(fm.files, p.OldPath)
+	return &filesystem.MoveFileResult{MovedAt: time.Now()}, nil
+}
+
+var _ = Describe("Encrypted File Manager", func() {
+	var (
+		oldKey  []byte
+		newKey  []byte
+		keyring encrypting.Keyring
+	)
+
+	BeforeEach(func() {
+		oldKey = bytes.Repeat([]byte{1}, encrypting.MASTER_KEY_SIZE)
+		newKey = bytes.Repeat([]byte{2}, encrypting.MASTER_KEY_SIZE)
+		keyring, _ = encrypting.NewKeyring(encrypting.NewKeyringParam{
+			CurrentKeyId: "key-1",
+			Keys:         map[string][]byte{"key-1": oldKey},
+		})
+	})
+
+	Context("NewEncryptedFileManager function", Label("unit"), func() {
+		var (
+			p encrypting.NewEncryptedFileManagerParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = encrypting.NewEncryptedFileManagerParam{
+				FileManager: mock.NewMockFileManager(ctrl),
+				Keyring:     keyring,
+			}
+		})
+
+		When("file manager is not specified", func() {
+			It("should return error", func() {
+				p.FileManager = nil
+				res, err := encrypting.NewEncryptedFileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file manager is not specified")))
+			})
+		})
+
+		When("keyring is not specified", func() {
+			It("should return error", func() {
+				p.Keyring = nil
+				res, err := encrypting.NewEncryptedFileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("keyring is not specified")))
+			})
+		})
+
+		When("chunk size is invalid", func() {
+			It("should return error", func() {
+				p.ChunkSize = encrypting.MAX_CHUNK_SIZE + 1
+				res, err := encrypting.NewEncryptedFileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid chunk size")))
+			})
+		})
+
+		When("all param is specified", func() {
+			It("should return result", func() {
+				res, err := encrypting.NewEncryptedFileManager(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Describe("Encrypted File Manager", Label("unit"), func() {
+		var (
+			ctx     context.Context
+			storage *memoryFileManager
+			fm      encrypting.EncryptedFileManager
+			data    []byte
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			storage = &memoryFileManager{files: map[string][]byte{}}
+			fm, _ = encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+				FileManager: storage,
+				Keyring:     keyring,
+				ChunkSize:   16,
+			})
+			data = []byte("the quick brown fox jumps over the lazy dog")
+		})
+
+		Context("SaveFile function", func() {
+			When("file is saved", func() {
+				It("should store the encrypted content", func() {
+					res, err := fm.SaveFile(ctx, filesystem.SaveFileParam{
+						Name: "storage/file.txt",
+						Data: data,
+					})
+
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+
+					stored := storage.files["storage/file.txt"]
+					Expect(stored[:len(encrypting.HEADER_MAGIC)]).To(Equal([]byte(encrypting.HEADER_MAGIC)))
+					Expect(bytes.Contains(stored, []byte("quick brown"))).To(BeFalse())
+				})
+			})
+
+			When("failed read random", func() {
+				It("should return error", func() {
+					fm, _ = encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+						FileManager: storage,
+						Keyring:     keyring,
+						Random:      bytes.NewReader(nil),
+					})
+					res, err := fm.SaveFile(ctx, filesystem.SaveFileParam{
+						Name: "storage/file.txt",
+						Data: data,
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(io.EOF))
+				})
+			})
+		})
+
+		Context("OpenFile function", func() {
+			When("file is unavailable", func() {
+				It("should return error", func() {
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/unavailable.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+				})
+			})
+
+			When("file is encrypted", func() {
+				It("should return the plaintext", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					Expect(err).To(BeNil())
+
+					plain, err := io.ReadAll(res.File)
+					Expect(plain).To(Equal(data))
+					Expect(err).To(BeNil())
+					Expect(res.File.Close()).To(BeNil())
+				})
+			})
+
+			When("file is empty", func() {
+				It("should return empty content", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/empty.txt", Data: []byte{}})
+
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/empty.txt",
+					})
+					Expect(err).To(BeNil())
+
+					plain, err := io.ReadAll(res.File)
+					Expect(plain).To(BeEmpty())
+					Expect(err).To(BeNil())
+				})
+			})
+
+			When("file is seeked", func() {
+				It("should decrypt only the requested frame", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+					res, _ := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+
+					size, err := res.File.Seek(0, io.SeekEnd)
+					Expect(size).To(Equal(int64(len(data))))
+					Expect(err).To(BeNil())
+
+					offset, err := res.File.Seek(-8, io.SeekEnd)
+					Expect(offset).To(Equal(int64(len(data) - 8)))
+					Expect(err).To(BeNil())
+					tail, _ := io.ReadAll(res.File)
+					Expect(string(tail)).To(Equal("lazy dog"))
+
+					res.File.Seek(10, io.SeekStart)
+					part := make([]byte, 15)
+					_, err = io.ReadFull(res.File, part)
+					Expect(string(part)).To(Equal("brown fox jumps"))
+					Expect(err).To(BeNil())
+
+					_, err = res.File.Seek(-1, io.SeekStart)
+					Expect(err).To(Equal(fmt.Errorf("negative position")))
+				})
+			})
+
+			When("file is stored as plaintext", func() {
+				It("should return the file as is", func() {
+					storage.files["storage/plain.txt"] = []byte("plain")
+
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/plain.txt",
+					})
+					Expect(err).To(BeNil())
+
+					plain, _ := io.ReadAll(res.File)
+					Expect(string(plain)).To(Equal("plain"))
+				})
+			})
+
+			When("file is tampered", func() {
+				It("should return error on read", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+					stored := storage.files["storage/file.txt"]
+					stored[len(stored)-1] ^= 0xff
+
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					Expect(err).To(BeNil())
+
+					_, err = io.ReadAll(res.File)
+					Expect(err).To(Equal(encrypting.ErrorDecryptionFailed))
+				})
+			})
+
+			When("file is truncated at frame boundary", func() {
+				It("should return error on read", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+					stored := storage.files["storage/file.txt"]
+					// drop the last frame: 43 bytes plaintext is 3 frames of 16 bytes chunk
+					storage.files["storage/file.txt"] = stored[:len(stored)-(11+16)]
+
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					Expect(err).To(BeNil())
+
+					_, err = io.ReadAll(res.File)
+					Expect(err).To(Equal(encrypting.ErrorDecryptionFailed))
+				})
+			})
+
+			When("master key is unavailable", func() {
+				It("should return error", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+					otherKeyring, _ := encrypting.NewKeyring(encrypting.NewKeyringParam{
+						CurrentKeyId: "key-2",
+						Keys:         map[string][]byte{"key-2": newKey},
+					})
+					otherFm, _ := encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+						FileManager: storage,
+						Keyring:     otherKeyring,
+					})
+
+					res, err := otherFm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(encrypting.ErrorKeyNotFound))
+				})
+			})
+
+			When("header is invalid", func() {
+				It("should return error", func() {
+					storage.files["storage/file.txt"] = []byte(encrypting.HEADER_MAGIC + "\x05ab")
+
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(encrypting.ErrorInvalidFileHeader))
+				})
+			})
+		})
+
+		Context("RekeyFile function", func() {
+			var (
+				rotatedFm encrypting.EncryptedFileManager
+			)
+
+			BeforeEach(func() {
+				rotatedKeyring, _ := encrypting.NewKeyring(encrypting.NewKeyringParam{
+					CurrentKeyId: "key-2",
+					Keys:         map[string][]byte{"key-1": oldKey, "key-2": newKey},
+				})
+				rotatedFm, _ = encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+					FileManager: storage,
+					Keyring:     rotatedKeyring,
+				})
+			})
+
+			When("file is unavailable", func() {
+				It("should return error", func() {
+					res, err := rotatedFm.RekeyFile(ctx, encrypting.RekeyFileParam{
+						Path: "storage/unavailable.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+				})
+			})
+
+			When("file is not encrypted", func() {
+				It("should return error", func() {
+					storage.files["storage/plain.txt"] = []byte("plain")
+					res, err := rotatedFm.RekeyFile(ctx, encrypting.RekeyFileParam{
+						Path: "storage/plain.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(encrypting.ErrorFileNotEncrypted))
+				})
+			})
+
+			When("file is wrapped by the old master key", func() {
+				It("should re-wrap the data key", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+
+					res, err := rotatedFm.RekeyFile(ctx, encrypting.RekeyFileParam{
+						Path: "storage/file.txt",
+					})
+					Expect(err).To(BeNil())
+					Expect(res.OldKeyId).To(Equal("key-1"))
+					Expect(res.NewKeyId).To(Equal("key-2"))
+					Expect(storage.files).ToNot(HaveKey("storage/file.txt.rekey"))
+					Expect(storage.written).To(Equal([]string{"storage/file.txt.rekey"}))
+
+					// readable without the old master key
+					newKeyring, _ := encrypting.NewKeyring(encrypting.NewKeyringParam{
+						CurrentKeyId: "key-2",
+						Keys:         map[string][]byte{"key-2": newKey},
+					})
+					newFm, _ := encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+						FileManager: storage,
+						Keyring:     newKeyring,
+					})
+					oRes, err := newFm.OpenFile(ctx, filesystem.OpenFileParam{Path: "storage/file.txt"})
+					Expect(err).To(BeNil())
+					plain, err := io.ReadAll(oRes.File)
+					Expect(plain).To(Equal(data))
+					Expect(err).To(BeNil())
+				})
+			})
+
+			When("file manager is not able to write from the reader", func() {
+				It("should save the buffered content", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+					rotatedKeyring, _ := encrypting.NewKeyring(encrypting.NewKeyringParam{
+						CurrentKeyId: "key-2",
+						Keys:         map[string][]byte{"key-1": oldKey, "key-2": newKey},
+					})
+					bufferedFm, _ := encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+						FileManager: struct{ filesystem.FileManager }{storage},
+						Keyring:     rotatedKeyring,
+					})
+
+					res, err := bufferedFm.RekeyFile(ctx, encrypting.RekeyFileParam{
+						Path: "storage/file.txt",
+					})
+					Expect(err).To(BeNil())
+					Expect(res.NewKeyId).To(Equal("key-2"))
+					Expect(storage.written).To(BeEmpty())
+
+					oRes, err := bufferedFm.OpenFile(ctx, filesystem.OpenFileParam{Path: "storage/file.txt"})
+					Expect(err).To(BeNil())
+					plain, err := io.ReadAll(oRes.File)
+					Expect(plain).To(Equal(data))
+					Expect(err).To(BeNil())
+				})
+			})
+
+			When("file is wrapped by the current master key", func() {
+				It("should not rewrite the file", func() {
+					rotatedFm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+					stored := storage.files["storage/file.txt"]
+
+					res, err := rotatedFm.RekeyFile(ctx, encrypting.RekeyFileParam{
+						Path: "storage/file.txt",
+					})
+
+					Expect(err).To(BeNil())
+					Expect(res.OldKeyId).To(Equal("key-2"))
+					Expect(res.NewKeyId).To(Equal("key-2"))
+					Expect(storage.files["storage/file.txt"]).To(Equal(stored))
+				})
+			})
+		})
+
+		Context("Passthrough function", func() {
+			When("managing encrypted file", func() {
+				It("should delegate to the underlying file manager", func() {
+					fm.SaveFile(ctx, filesystem.SaveFileParam{Name: "storage/file.txt", Data: data})
+
+					exists, err := fm.IsFileExists(ctx, filesystem.IsFileExistsParam{Path: "storage/file.txt"})
+					Expect(exists).To(BeTrue())
+					Expect(err).To(BeNil())
+
+					_, err = fm.MoveFile(ctx, filesystem.MoveFileParam{OldPath: "storage/file.txt", NewPath: "storage/moved.txt"})
+					Expect(err).To(BeNil())
+					Expect(storage.files).To(HaveKey("storage/moved.txt"))
+
+					_, err = fm.RemoveFile(ctx, filesystem.RemoveFileParam{Path: "storage/moved.txt"})
+					Expect(err).To(BeNil())
+					Expect(storage.files).To(BeEmpty())
+				})
+			})
+		})
+	})
+})
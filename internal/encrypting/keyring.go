@@ -0,0 +1,154 @@
+package encrypting
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	MASTER_KEY_SIZE  = 32
+	MAX_KEY_ID_SIZE  = 64
+	WRAPPED_KEY_SIZE = 12 + MASTER_KEY_SIZE + 16
+)
+
+type Keyring interface {
+	CurrentKeyId() string
+	WrapKey(dataKey []byte) (*WrapKeyResult, error)
+	UnwrapKey(keyId string, wrappedKey []byte) ([]byte, error)
+}
+
+type WrapKeyResult struct {
+	KeyId      string
+	WrappedKey []byte
+}
+
+type keyring struct {
+	currentKeyId string
+	keys         map[string]cipher.AEAD
+	random       io.Reader
+}
+
+func (k *keyring) CurrentKeyId() string {
+	return k.currentKeyId
+}
+
+// wrapped key format: nonce (12 bytes) | sealed data key
+// key id is used as additional data so the wrapped key can not be moved under another key id
+func (k *keyring) WrapKey(dataKey []byte) (*WrapKeyResult, error) {
+	aead := k.keys[k.currentKeyId]
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err := io.ReadFull(k.random, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped := aead.Seal(nonce, nonce, dataKey, []byte(k.currentKeyId))
+	res := &WrapKeyResult{
+		KeyId:      k.currentKeyId,
+		WrappedKey: wrapped,
+	}
+	return res, nil
+}
+
+func (k *keyring) UnwrapKey(keyId string, wrappedKey []byte) ([]byte, error) {
+	aead, ok := k.keys[keyId]
+	if !ok {
+		return nil, ErrorKeyNotFound
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, ErrorDecryptionFailed
+	}
+
+	nonce := wrappedKey[:aead.NonceSize()]
+	dataKey, err := aead.Open(nil, nonce, wrappedKey[aead.NonceSize():], []byte(keyId))
+	if err != nil {
+		return nil, ErrorDecryptionFailed
+	}
+	return dataKey, nil
+}
+
+// keyfile contains one master key per line: <key-id>:<base64 encoded 32 bytes key>
+// empty line and line started with # are ignored
+func ParseKeyfile(r io.Reader) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid keyfile line")
+		}
+		keyId := strings.TrimSpace(parts[0])
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key encoding")
+		}
+		if _, ok := keys[keyId]; ok {
+			return nil, fmt.Errorf("duplicate master key id")
+		}
+		keys[keyId] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+type NewKeyringParam struct {
+	CurrentKeyId string
+	Keys         map[string][]byte
+	Random       io.Reader
+}
+
+func NewKeyring(p NewKeyringParam) (*keyring, error) {
+	if p.CurrentKeyId == "" {
+		return nil, fmt.Errorf("current key id is not specified")
+	}
+	if _, ok := p.Keys[p.CurrentKeyId]; !ok {
+		return nil, fmt.Errorf("current master key is not specified")
+	}
+
+	keys := map[string]cipher.AEAD{}
+	for keyId, key := range p.Keys {
+		if keyId == "" || len(keyId) > MAX_KEY_ID_SIZE {
+			return nil, fmt.Errorf("invalid master key id")
+		}
+		if len(key) != MASTER_KEY_SIZE {
+			return nil, fmt.Errorf("invalid master key size")
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keys[keyId] = aead
+	}
+
+	random := p.Random
+	if random == nil {
+		random = rand.Reader
+	}
+
+	k := &keyring{
+		currentKeyId: p.CurrentKeyId,
+		keys:         keys,
+		random:       random,
+	}
+	return k, nil
+}
@@ -0,0 +1,213 @@
+package encrypting_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-seidon/local/internal/encrypting"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEncrypting(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Encrypting Package")
+}
+
+var _ = Describe("Keyring", func() {
+	var (
+		oldKey []byte
+		newKey []byte
+	)
+
+	BeforeEach(func() {
+		oldKey = bytes.Repeat([]byte{1}, encrypting.MASTER_KEY_SIZE)
+		newKey = bytes.Repeat([]byte{2}, encrypting.MASTER_KEY_SIZE)
+	})
+
+	Context("NewKeyring function", Label("unit"), func() {
+		When("current key id is not specified", func() {
+			It("should return error", func() {
+				res, err := encrypting.NewKeyring(encrypting.NewKeyringParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("current key id is not specified")))
+			})
+		})
+
+		When("current key is not specified", func() {
+			It("should return error", func() {
+				res, err := encrypting.NewKeyring(encrypting.NewKeyringParam{
+					CurrentKeyId: "key-2",
+					Keys:         map[string][]byte{"key-1": oldKey},
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("current master key is not specified")))
+			})
+		})
+
+		When("key id is too long", func() {
+			It("should return error", func() {
+				keyId := strings.Repeat("k", encrypting.MAX_KEY_ID_SIZE+1)
+				res, err := encrypting.NewKeyring(encrypting.NewKeyringParam{
+					CurrentKeyId: keyId,
+					Keys:         map[string][]byte{keyId: oldKey},
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid master key id")))
+			})
+		})
+
+		When("key size is invalid", func() {
+			It("should return error", func() {
+				res, err := encrypting.NewKeyring(encrypting.NewKeyringParam{
+					CurrentKeyId: "key-1",
+					Keys:         map[string][]byte{"key-1": []byte("short")},
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid master key size")))
+			})
+		})
+
+		When("all param is specified", func() {
+			It("should return result", func() {
+				res, err := encrypting.NewKeyring(encrypting.NewKeyringParam{
+					CurrentKeyId: "key-1",
+					Keys:         map[string][]byte{"key-1": oldKey},
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("WrapKey function", Label("unit"), func() {
+		var (
+			oldKeyring encrypting.Keyring
+			newKeyring encrypting.Keyring
+			dataKey    []byte
+		)
+
+		BeforeEach(func() {
+			oldKeyring, _ = encrypting.NewKeyring(encrypting.NewKeyringParam{
+				CurrentKeyId: "key-1",
+				Keys:         map[string][]byte{"key-1": oldKey},
+			})
+			newKeyring, _ = encrypting.NewKeyring(encrypting.NewKeyringParam{
+				CurrentKeyId: "key-2",
+				Keys:         map[string][]byte{"key-1": oldKey, "key-2": newKey},
+			})
+			dataKey = bytes.Repeat([]byte{9}, encrypting.DATA_KEY_SIZE)
+		})
+
+		When("failed read random", func() {
+			It("should return error", func() {
+				keyring, _ := encrypting.NewKeyring(encrypting.NewKeyringParam{
+					CurrentKeyId: "key-1",
+					Keys:         map[string][]byte{"key-1": oldKey},
+					Random:       bytes.NewReader(nil),
+				})
+				res, err := keyring.WrapKey(dataKey)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("key is wrapped by the old master key", func() {
+			It("should be unwrapped by the new keyring", func() {
+				wRes, err := oldKeyring.WrapKey(dataKey)
+				Expect(err).To(BeNil())
+				Expect(wRes.KeyId).To(Equal("key-1"))
+				Expect(wRes.WrappedKey).To(HaveLen(encrypting.WRAPPED_KEY_SIZE))
+
+				res, err := newKeyring.UnwrapKey(wRes.KeyId, wRes.WrappedKey)
+
+				Expect(res).To(Equal(dataKey))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("key id is unknown", func() {
+			It("should return error", func() {
+				wRes, _ := newKeyring.WrapKey(dataKey)
+				res, err := oldKeyring.UnwrapKey(wRes.KeyId, wRes.WrappedKey)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(encrypting.ErrorKeyNotFound))
+			})
+		})
+
+		When("wrapped key is moved under another key id", func() {
+			It("should return error", func() {
+				keyring, _ := encrypting.NewKeyring(encrypting.NewKeyringParam{
+					CurrentKeyId: "key-2",
+					Keys:         map[string][]byte{"key-1": newKey, "key-2": newKey},
+				})
+				wRes, _ := keyring.WrapKey(dataKey)
+				res, err := keyring.UnwrapKey("key-1", wRes.WrappedKey)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(encrypting.ErrorDecryptionFailed))
+			})
+		})
+
+		When("wrapped key is too short", func() {
+			It("should return error", func() {
+				res, err := oldKeyring.UnwrapKey("key-1", []byte("short"))
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(encrypting.ErrorDecryptionFailed))
+			})
+		})
+	})
+
+	Context("ParseKeyfile function", Label("unit"), func() {
+		When("keyfile is valid", func() {
+			It("should return result", func() {
+				data := "# comment\n\nkey-1:AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=\n key-2 : AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI= \n"
+				res, err := encrypting.ParseKeyfile(strings.NewReader(data))
+
+				Expect(res).To(Equal(map[string][]byte{
+					"key-1": oldKey,
+					"key-2": newKey,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("line is invalid", func() {
+			It("should return error", func() {
+				res, err := encrypting.ParseKeyfile(strings.NewReader("key-1"))
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid keyfile line")))
+			})
+		})
+
+		When("key encoding is invalid", func() {
+			It("should return error", func() {
+				res, err := encrypting.ParseKeyfile(strings.NewReader("key-1:%%%"))
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid master key encoding")))
+			})
+		})
+
+		When("key id is duplicated", func() {
+			It("should return error", func() {
+				data := "key-1:AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=\nkey-1:AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="
+				res, err := encrypting.ParseKeyfile(strings.NewReader(data))
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("duplicate master key id")))
+			})
+		})
+	})
+})
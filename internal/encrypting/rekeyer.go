@@ -0,0 +1,139 @@
+package encrypting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+const (
+	DEFAULT_BATCH_SIZE = 100
+)
+
+type Rekeyer interface {
+	RekeyFiles(ctx context.Context, p RekeyFilesParam) (*RekeyFilesResult, error)
+}
+
+type RekeyFilesParam struct {
+	AfterId   string
+	BatchSize int
+	DryRun    bool
+}
+
+type RekeyFilesResult struct {
+	LastId  string
+	HasMore bool
+	Total   int
+	Rekeyed int
+	Skipped int
+	Failed  int
+}
+
+func NewRekeyFn(fileManager EncryptedFileManager) repository.RekeyFn {
+	return func(ctx context.Context, rp repository.RekeyFnParam) error {
+		_, err := fileManager.RekeyFile(ctx, RekeyFileParam{
+			Path: rp.FilePath,
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+type rekeyer struct {
+	fileRepo    repository.FileRepository
+	fileManager EncryptedFileManager
+	keyring     Keyring
+	log         logging.Logger
+}
+
+func (s *rekeyer) RekeyFiles(ctx context.Context, p RekeyFilesParam) (*RekeyFilesResult, error) {
+	s.log.Debug("In function: RekeyFiles")
+	defer s.log.Debug("Returning function: RekeyFiles")
+
+	if p.BatchSize < 0 {
+		return nil, fmt.Errorf("invalid batch size")
+	}
+	batchSize := DEFAULT_BATCH_SIZE
+	if p.BatchSize > 0 {
+		batchSize = p.BatchSize
+	}
+
+	files, err := s.fileRepo.ListFile(ctx, repository.ListFileParam{
+		AfterId: p.AfterId,
+		Limit:   batchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	currentKeyId := s.keyring.CurrentKeyId()
+	res := &RekeyFilesResult{
+		LastId:  p.AfterId,
+		HasMore: len(files.Items) == batchSize,
+		Total:   len(files.Items),
+	}
+	for _, file := range files.Items {
+		res.LastId = file.UniqueId
+
+		if file.EncryptionKeyId == "" || file.EncryptionKeyId == currentKeyId {
+			res.Skipped++
+			continue
+		}
+
+		if p.DryRun {
+			s.log.Infof("File %s will be re-keyed: %s -> %s", file.UniqueId, file.EncryptionKeyId, currentKeyId)
+			res.Rekeyed++
+			continue
+		}
+
+		_, err := s.fileRepo.UpdateFileKey(ctx, repository.UpdateFileKeyParam{
+			UniqueId: file.UniqueId,
+			OldKeyId: file.EncryptionKeyId,
+			NewKeyId: currentKeyId,
+			RekeyFn:  NewRekeyFn(s.fileManager),
+		})
+		if err != nil {
+			s.log.Errorf("Failed re-key file %s: %s", file.UniqueId, err.Error())
+			res.Failed++
+			continue
+		}
+
+		s.log.Infof("File %s is re-keyed: %s -> %s", file.UniqueId, file.EncryptionKeyId, currentKeyId)
+		res.Rekeyed++
+	}
+	return res, nil
+}
+
+type NewRekeyerParam struct {
+	FileRepo    repository.FileRepository
+	FileManager EncryptedFileManager
+	Keyring     Keyring
+	Logger      logging.Logger
+}
+
+func NewRekeyer(p NewRekeyerParam) (*rekeyer, error) {
+	if p.FileRepo == nil {
+		return nil, fmt.Errorf("file repo is not specified")
+	}
+	if p.FileManager == nil {
+		return nil, fmt.Errorf("file manager is not specified")
+	}
+	if p.Keyring == nil {
+		return nil, fmt.Errorf("keyring is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+
+	s := &rekeyer{
+		fileRepo:    p.FileRepo,
+		fileManager: p.FileManager,
+		keyring:     p.Keyring,
+		log:         p.Logger,
+	}
+	return s, nil
+}
@@ -0,0 +1,313 @@
+package encrypting_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-seidon/local/internal/encrypting"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rekeyer Service", func() {
+	Context("NewRekeyer function", Label("unit"), func() {
+		var (
+			p encrypting.NewRekeyerParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = encrypting.NewRekeyerParam{
+				FileRepo:    mock.NewMockFileRepository(ctrl),
+				FileManager: mock.NewMockEncryptedFileManager(ctrl),
+				Keyring:     mock.NewMockKeyring(ctrl),
+				Logger:      mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := encrypting.NewRekeyer(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file repo is not specified", func() {
+			It("should return error", func() {
+				p.FileRepo = nil
+				res, err := encrypting.NewRekeyer(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file repo is not specified")))
+			})
+		})
+
+		When("file manager is not specified", func() {
+			It("should return error", func() {
+				p.FileManager = nil
+				res, err := encrypting.NewRekeyer(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file manager is not specified")))
+			})
+		})
+
+		When("keyring is not specified", func() {
+			It("should return error", func() {
+				p.Keyring = nil
+				res, err := encrypting.NewRekeyer(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("keyring is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := encrypting.NewRekeyer(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+	})
+
+	Context("NewRekeyFn function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			fileManager *mock.MockEncryptedFileManager
+			fn          repository.RekeyFn
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			fileManager = mock.NewMockEncryptedFileManager(ctrl)
+			fn = encrypting.NewRekeyFn(fileManager)
+		})
+
+		When("failed rekey file", func() {
+			It("should return error", func() {
+				fileManager.
+					EXPECT().
+					RekeyFile(gomock.Eq(ctx), gomock.Eq(encrypting.RekeyFileParam{
+						Path: "storage/mock-id.jpg",
+					})).
+					Return(nil, fmt.Errorf("disk error")).
+					Times(1)
+
+				err := fn(ctx, repository.RekeyFnParam{FilePath: "storage/mock-id.jpg"})
+
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
+			})
+		})
+
+		When("success rekey file", func() {
+			It("should return nil", func() {
+				fileManager.
+					EXPECT().
+					RekeyFile(gomock.Eq(ctx), gomock.Eq(encrypting.RekeyFileParam{
+						Path: "storage/mock-id.jpg",
+					})).
+					Return(&encrypting.RekeyFileResult{}, nil).
+					Times(1)
+
+				err := fn(ctx, repository.RekeyFnParam{FilePath: "storage/mock-id.jpg"})
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RekeyFiles function", Label("unit"), func() {
+		var (
+			ctx      context.Context
+			fileRepo *mock.MockFileRepository
+			keyring  *mock.MockKeyring
+			logger   *mock.MockLogger
+			s        encrypting.Rekeyer
+			p        encrypting.RekeyFilesParam
+			listRes  *repository.ListFileResult
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			keyring = mock.NewMockKeyring(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			s, _ = encrypting.NewRekeyer(encrypting.NewRekeyerParam{
+				FileRepo:    fileRepo,
+				FileManager: mock.NewMockEncryptedFileManager(ctrl),
+				Keyring:     keyring,
+				Logger:      logger,
+			})
+			p = encrypting.RekeyFilesParam{
+				AfterId:   "mock-after-id",
+				BatchSize: 3,
+			}
+			listRes = &repository.ListFileResult{
+				Items: []repository.ListFileItem{
+					{UniqueId: "mock-id-1", Path: "storage/mock-id-1", EncryptionKeyId: "key-1"},
+					{UniqueId: "mock-id-2", Path: "storage/mock-id-2", EncryptionKeyId: "key-2"},
+					{UniqueId: "mock-id-3", Path: "storage/mock-id-3"},
+				},
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: RekeyFiles").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: RekeyFiles").
+				Times(1)
+		})
+
+		When("batch size is invalid", func() {
+			It("should return error", func() {
+				p.BatchSize = -1
+				res, err := s.RekeyFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid batch size")))
+			})
+		})
+
+		When("failed list files", func() {
+			It("should return error", func() {
+				p.BatchSize = 0
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Eq(repository.ListFileParam{
+						AfterId: "mock-after-id",
+						Limit:   encrypting.DEFAULT_BATCH_SIZE,
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.RekeyFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("dry run is enabled", func() {
+			It("should not rekey the file", func() {
+				p.DryRun = true
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Any()).
+					Return(listRes, nil).
+					Times(1)
+				keyring.
+					EXPECT().
+					CurrentKeyId().
+					Return("key-2").
+					Times(1)
+				logger.
+					EXPECT().
+					Infof("File %s will be re-keyed: %s -> %s", "mock-id-1", "key-1", "key-2").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFileKey(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				res, err := s.RekeyFiles(ctx, p)
+
+				Expect(res).To(Equal(&encrypting.RekeyFilesResult{
+					LastId:  "mock-id-3",
+					HasMore: true,
+					Total:   3,
+					Rekeyed: 1,
+					Skipped: 2,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed update file key", func() {
+			It("should count the failure", func() {
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Any()).
+					Return(listRes, nil).
+					Times(1)
+				keyring.
+					EXPECT().
+					CurrentKeyId().
+					Return("key-2").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFileKey(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, repository.ErrorRecordChanged).
+					Times(1)
+				logger.
+					EXPECT().
+					Errorf("Failed re-key file %s: %s", "mock-id-1", "record has been changed").
+					Times(1)
+
+				res, err := s.RekeyFiles(ctx, p)
+
+				Expect(res).To(Equal(&encrypting.RekeyFilesResult{
+					LastId:  "mock-id-3",
+					HasMore: true,
+					Total:   3,
+					Skipped: 2,
+					Failed:  1,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success rekey files", func() {
+			It("should return result", func() {
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Any()).
+					Return(listRes, nil).
+					Times(1)
+				keyring.
+					EXPECT().
+					CurrentKeyId().
+					Return("key-2").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFileKey(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, up repository.UpdateFileKeyParam) (*repository.UpdateFileKeyResult, error) {
+						Expect(up.UniqueId).To(Equal("mock-id-1"))
+						Expect(up.OldKeyId).To(Equal("key-1"))
+						Expect(up.NewKeyId).To(Equal("key-2"))
+						Expect(up.RekeyFn).ToNot(BeNil())
+						return &repository.UpdateFileKeyResult{}, nil
+					}).
+					Times(1)
+				logger.
+					EXPECT().
+					Infof("File %s is re-keyed: %s -> %s", "mock-id-1", "key-1", "key-2").
+					Times(1)
+
+				res, err := s.RekeyFiles(ctx, p)
+
+				Expect(res).To(Equal(&encrypting.RekeyFilesResult{
+					LastId:  "mock-id-3",
+					HasMore: true,
+					Total:   3,
+					Rekeyed: 1,
+					Skipped: 2,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -3,6 +3,7 @@ package filesystem
 import (
 	"context"
 	"errors"
+	"io"
 	"io/fs"
 	"os"
 	"time"
@@ -13,6 +14,13 @@ type FileManager interface {
 	OpenFile(ctx context.Context, p OpenFileParam) (*OpenFileResult, error)
 	SaveFile(ctx context.Context, p SaveFileParam) (*SaveFileResult, error)
 	RemoveFile(ctx context.Context, p RemoveFileParam) (*RemoveFileResult, error)
+	MoveFile(ctx context.Context, p MoveFileParam) (*MoveFileResult, error)
+}
+
+// the content is written from the reader without buffering it in the memory,
+// implemented by the storage file manager
+type FileWriter interface {
+	WriteFile(ctx context.Context, p WriteFileParam) (*WriteFileResult, error)
 }
 
 type IsFileExistsParam struct {
@@ -24,7 +32,7 @@ type OpenFileParam struct {
 }
 
 type OpenFileResult struct {
-	File *os.File
+	File io.ReadSeekCloser
 }
 
 type SaveFileParam struct {
@@ -37,6 +45,18 @@ type SaveFileResult struct {
 	SavedAt time.Time
 }
 
+type WriteFileParam struct {
+	Name   string
+	Reader io.Reader
+	// exact size of the content read from the reader
+	Size       int64
+	Permission fs.FileMode
+}
+
+type WriteFileResult struct {
+	WrittenAt time.Time
+}
+
 type RemoveFileParam struct {
 	Path string
 }
@@ -45,6 +65,15 @@ type RemoveFileResult struct {
 	RemovedAt time.Time
 }
 
+type MoveFileParam struct {
+	OldPath string
+	NewPath string
+}
+
+type MoveFileResult struct {
+	MovedAt time.Time
+}
+
 type fileManager struct {
 }
 
@@ -92,6 +121,29 @@ func (fm *fileManager) SaveFile(ctx context.Context, p SaveFileParam) (*SaveFile
 	return res, nil
 }
 
+// write file/overwrite if exists
+func (fm *fileManager) WriteFile(ctx context.Context, p WriteFileParam) (*WriteFileResult, error) {
+	file, err := os.OpenFile(p.Name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, p.Permission)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.Copy(file, p.Reader)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	err = file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &WriteFileResult{
+		WrittenAt: time.Now(),
+	}
+	return res, nil
+}
+
 func (fm *fileManager) RemoveFile(ctx context.Context, p RemoveFileParam) (*RemoveFileResult, error) {
 	err := os.Remove(p.Path)
 	if err == nil {
@@ -108,6 +160,22 @@ func (fm *fileManager) RemoveFile(ctx context.Context, p RemoveFileParam) (*Remo
 	return nil, err
 }
 
+func (fm *fileManager) MoveFile(ctx context.Context, p MoveFileParam) (*MoveFileResult, error) {
+	err := os.Rename(p.OldPath, p.NewPath)
+	if err == nil {
+		res := &MoveFileResult{
+			MovedAt: time.Now(),
+		}
+		return res, nil
+	}
+
+	notExists := errors.Is(err, os.ErrNotExist)
+	if notExists {
+		return nil, ErrorFileNotFound
+	}
+	return nil, err
+}
+
 func NewFileManager() *fileManager {
 	s := &fileManager{}
 	return s
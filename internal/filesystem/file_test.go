@@ -4,6 +4,7 @@ import (
 	"context"
 	"io/fs"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/go-seidon/local/internal/filesystem"
@@ -151,6 +152,53 @@ var _ = Describe("File Manager", func() {
 			})
 		})
 
+		Context("WriteFile function", Ordered, func() {
+			var (
+				fileName string
+			)
+
+			BeforeAll(func() {
+				fileName = "temp-write-file.txt"
+			})
+
+			AfterAll(func() {
+				err := os.Remove(fileName)
+				if err != nil {
+					AbortSuite("failed cleaningup temp file: " + err.Error())
+				}
+			})
+
+			When("failed write file", func() {
+				It("should return error", func() {
+					res, err := fm.(filesystem.FileWriter).WriteFile(ctx, filesystem.WriteFileParam{
+						Name:       "", //should specify file name
+						Reader:     strings.NewReader("content"),
+						Size:       7,
+						Permission: fs.ModePerm,
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).ToNot(BeNil())
+				})
+			})
+
+			When("success write file", func() {
+				It("should return result", func() {
+					res, err := fm.(filesystem.FileWriter).WriteFile(ctx, filesystem.WriteFileParam{
+						Name:       fileName,
+						Reader:     strings.NewReader("content"),
+						Size:       7,
+						Permission: fs.ModePerm,
+					})
+
+					data, _ := os.ReadFile(fileName)
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+					Expect(string(data)).To(Equal("content"))
+				})
+			})
+		})
+
 		Context("RemoveFile function", Ordered, func() {
 			var (
 				fileName string
@@ -197,5 +245,64 @@ var _ = Describe("File Manager", func() {
 				})
 			})
 		})
+
+		Context("MoveFile function", Ordered, func() {
+			var (
+				oldName string
+				newName string
+			)
+
+			BeforeAll(func() {
+				oldName = "temp-move-file.txt"
+				newName = "temp-moved-file.txt"
+				err := os.WriteFile(oldName, nil, fs.ModeTemporary)
+				if err != nil {
+					AbortSuite("failed settingup temp file: " + err.Error())
+				}
+			})
+
+			AfterAll(func() {
+				err := os.Remove(newName)
+				if err != nil {
+					AbortSuite("failed cleaningup temp file: " + err.Error())
+				}
+			})
+
+			When("failed move file", func() {
+				It("should return error", func() {
+					res, err := fm.MoveFile(ctx, filesystem.MoveFileParam{
+						OldPath: "\000",
+						NewPath: newName,
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).ToNot(BeNil())
+				})
+			})
+
+			When("file is unavailable", func() {
+				It("should return result", func() {
+					res, err := fm.MoveFile(ctx, filesystem.MoveFileParam{
+						OldPath: "unavailable-file",
+						NewPath: newName,
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+				})
+			})
+
+			When("file is available", func() {
+				It("should return result", func() {
+					res, err := fm.MoveFile(ctx, filesystem.MoveFileParam{
+						OldPath: oldName,
+						NewPath: newName,
+					})
+
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+				})
+			})
+		})
 	})
 })
@@ -0,0 +1,458 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+)
+
+const (
+	S3_DEFAULT_REGION          = "us-east-1"
+	S3_DEFAULT_DIAL_TIMEOUT    = 10 * time.Second
+	S3_DEFAULT_HEADER_TIMEOUT  = 30 * time.Second
+	S3_DEFAULT_REQUEST_TIMEOUT = 60 * time.Second
+	S3_SERVICE                 = "s3"
+	S3_UNSIGNED_PAYLOAD        = "UNSIGNED-PAYLOAD"
+)
+
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+type s3FileManager struct {
+	endpoint    *url.URL
+	bucket      string
+	virtualHost bool
+	signer      SigV4Signer
+	httpClient  *http.Client
+	timeout     time.Duration
+	clock       datetime.Clock
+}
+
+func (fm *s3FileManager) IsFileExists(ctx context.Context, p IsFileExistsParam) (bool, error) {
+	_, err := fm.headObject(ctx, p.Path)
+	if err == nil {
+		return true, nil
+	}
+	if err == ErrorFileNotFound {
+		return false, nil
+	}
+	return false, err
+}
+
+func (fm *s3FileManager) OpenFile(ctx context.Context, p OpenFileParam) (*OpenFileResult, error) {
+	size, err := fm.headObject(ctx, p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &OpenFileResult{
+		File: &s3Object{
+			ctx:  ctx,
+			fm:   fm,
+			path: p.Path,
+			size: size,
+		},
+	}
+	return res, nil
+}
+
+func (fm *s3FileManager) SaveFile(ctx context.Context, p SaveFileParam) (*SaveFileResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, fm.timeout)
+	defer cancel()
+
+	header := http.Header{}
+	header.Set("Content-Type", http.DetectContentType(p.Data))
+
+	res, err := fm.doRequest(ctx, http.MethodPut, p.Name, header, p.Data)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, parseS3Error(res)
+	}
+
+	r := &SaveFileResult{
+		SavedAt: fm.clock.Now(),
+	}
+	return r, nil
+}
+
+// the streamed payload is not signed, the content is not bounded by the request timeout like the download
+func (fm *s3FileManager) WriteFile(ctx context.Context, p WriteFileParam) (*WriteFileResult, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+
+	res, err := fm.sendRequest(ctx, http.MethodPut, p.Name, header, p.Reader, p.Size, S3_UNSIGNED_PAYLOAD)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, parseS3Error(res)
+	}
+
+	r := &WriteFileResult{
+		WrittenAt: fm.clock.Now(),
+	}
+	return r, nil
+}
+
+func (fm *s3FileManager) RemoveFile(ctx context.Context, p RemoveFileParam) (*RemoveFileResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, fm.timeout)
+	defer cancel()
+
+	_, err := fm.headObject(ctx, p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := fm.doRequest(ctx, http.MethodDelete, p.Path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return nil, parseS3Error(res)
+	}
+
+	r := &RemoveFileResult{
+		RemovedAt: fm.clock.Now(),
+	}
+	return r, nil
+}
+
+// move file/overwrite if exists, object storage has no rename
+// so the object is copied into the new key before the old key is deleted
+func (fm *s3FileManager) MoveFile(ctx context.Context, p MoveFileParam) (*MoveFileResult, error) {
+	oldKey, err := objectKey(p.OldPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fm.timeout)
+	defer cancel()
+
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", "/"+fm.bucket+"/"+UriEncode(oldKey, false))
+
+	res, err := fm.doRequest(ctx, http.MethodPut, p.NewPath, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrorFileNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, parseS3Error(res)
+	}
+
+	// copy object may fail after the status code has been sent
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(body, []byte("<Error>")) {
+		return nil, decodeS3Error(res.StatusCode, body)
+	}
+
+	_, err = fm.RemoveFile(ctx, RemoveFileParam{
+		Path: p.OldPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &MoveFileResult{
+		MovedAt: fm.clock.Now(),
+	}
+	return r, nil
+}
+
+func (fm *s3FileManager) headObject(ctx context.Context, path string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, fm.timeout)
+	defer cancel()
+
+	res, err := fm.doRequest(ctx, http.MethodHead, path, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return 0, ErrorFileNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("s3 request failed: %s", res.Status)
+	}
+	return res.ContentLength, nil
+}
+
+func (fm *s3FileManager) doRequest(ctx context.Context, method, path string, header http.Header, data []byte) (*http.Response, error) {
+	var body io.Reader
+	payloadHash := SIGV4_EMPTY_HASH
+	if data != nil {
+		body = bytes.NewReader(data)
+		payloadHash = hashHex(data)
+	}
+	return fm.sendRequest(ctx, method, path, header, body, int64(len(data)), payloadHash)
+}
+
+func (fm *s3FileManager) sendRequest(ctx context.Context, method, path string, header http.Header, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	key, err := objectKey(path)
+	if err != nil {
+		return nil, err
+	}
+
+	u := *fm.endpoint
+	if fm.virtualHost {
+		u.Host = fm.bucket + "." + u.Host
+		u.Path = "/" + key
+		u.RawPath = "/" + UriEncode(key, false)
+	} else {
+		u.Path = "/" + fm.bucket + "/" + key
+		u.RawPath = "/" + UriEncode(fm.bucket, true) + "/" + UriEncode(key, false)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for name, values := range header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	now := fm.clock.Now()
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", now.UTC().Format(SIGV4_DATE_FORMAT))
+	fm.signer.SignRequest(req, payloadHash, now)
+
+	return fm.httpClient.Do(req)
+}
+
+func objectKey(p string) (string, error) {
+	key := strings.TrimPrefix(path.Clean("/"+p), "/")
+	if key == "" {
+		return "", fmt.Errorf("invalid path")
+	}
+	return key, nil
+}
+
+func parseS3Error(res *http.Response) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return decodeS3Error(res.StatusCode, body)
+}
+
+func decodeS3Error(statusCode int, body []byte) error {
+	var e s3Error
+	err := xml.Unmarshal(body, &e)
+	if err != nil || e.Code == "" {
+		return fmt.Errorf("s3 request failed: %d", statusCode)
+	}
+	return fmt.Errorf("s3 request failed: %s: %s", e.Code, e.Message)
+}
+
+type s3Object struct {
+	ctx    context.Context
+	fm     *s3FileManager
+	path   string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+
+	if o.body == nil {
+		header := http.Header{}
+		header.Set("Range", fmt.Sprintf("bytes=%d-", o.offset))
+
+		res, err := o.fm.doRequest(o.ctx, http.MethodGet, o.path, header, nil)
+		if err != nil {
+			return 0, err
+		}
+		if res.StatusCode == http.StatusNotFound {
+			res.Body.Close()
+			return 0, ErrorFileNotFound
+		}
+		if res.StatusCode != http.StatusPartialContent && res.StatusCode != http.StatusOK {
+			defer res.Body.Close()
+			return 0, parseS3Error(res)
+		}
+		// the range is optional for the server, the whole object is returned when it is ignored
+		if res.StatusCode == http.StatusOK && o.offset > 0 {
+			_, err = io.CopyN(io.Discard, res.Body, o.offset)
+			if err != nil {
+				res.Body.Close()
+				return 0, fmt.Errorf("failed skip object content: %s", err.Error())
+			}
+		}
+		o.body = res.Body
+	}
+
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	var next int64
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = o.offset + offset
+	case io.SeekEnd:
+		next = o.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence")
+	}
+	if next < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+
+	if next != o.offset && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.offset = next
+	return next, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
+	return err
+}
+
+type NewS3FileManagerParam struct {
+	Endpoint    string
+	Bucket      string
+	AccessKey   string
+	SecretKey   string
+	Region      string
+	VirtualHost bool
+	HttpClient  *http.Client
+	// deadline of every request except the object download, default to 60 seconds
+	RequestTimeout time.Duration
+	Clock          datetime.Clock
+}
+
+func NewS3FileManager(p NewS3FileManagerParam) (*s3FileManager, error) {
+	if p.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is not specified")
+	}
+	endpoint, err := url.Parse(p.Endpoint)
+	if err != nil || endpoint.Host == "" ||
+		(endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid endpoint")
+	}
+	if p.Bucket == "" {
+		return nil, fmt.Errorf("bucket is not specified")
+	}
+
+	region := S3_DEFAULT_REGION
+	if p.Region != "" {
+		region = p.Region
+	}
+	signer, err := NewSigV4Signer(NewSigV4SignerParam{
+		AccessKey: p.AccessKey,
+		SecretKey: p.SecretKey,
+		Region:    region,
+		Service:   S3_SERVICE,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the client has no overall timeout since it also bounds reading the body,
+	// which would cut the download of a large object
+	httpClient := p.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   S3_DEFAULT_DIAL_TIMEOUT,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   S3_DEFAULT_DIAL_TIMEOUT,
+				ResponseHeaderTimeout: S3_DEFAULT_HEADER_TIMEOUT,
+				MaxIdleConns:          100,
+				IdleConnTimeout:       90 * time.Second,
+				ForceAttemptHTTP2:     true,
+			},
+		}
+	}
+	timeout := S3_DEFAULT_REQUEST_TIMEOUT
+	if p.RequestTimeout > 0 {
+		timeout = p.RequestTimeout
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	endpoint.Path = ""
+	endpoint.RawPath = ""
+	fm := &s3FileManager{
+		endpoint:    endpoint,
+		bucket:      p.Bucket,
+		virtualHost: p.VirtualHost,
+		signer:      signer,
+		httpClient:  httpClient,
+		timeout:     timeout,
+		clock:       clock,
+	}
+	return fm, nil
+}
+
+type objectDirectoryManager struct {
+}
+
+func (dm *objectDirectoryManager) IsDirectoryExists(ctx context.Context, p IsDirectoryExistsParam) (bool, error) {
+	return true, nil
+}
+
+func (dm *objectDirectoryManager) CreateDir(ctx context.Context, p CreateDirParam) (*CreateDirResult, error) {
+	res := &CreateDirResult{
+		CreatedAt: time.Now(),
+	}
+	return res, nil
+}
+
+func NewObjectDirectoryManager() *objectDirectoryManager {
+	return &objectDirectoryManager{}
+}
@@ -0,0 +1,576 @@
+package filesystem_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/filesystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// @note: minimal in memory s3 server supporting object put, copy, get, head and delete
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	fail    bool
+	// the whole object is returned for the range request
+	ignoreRange bool
+	// delay before the response header and before the object content
+	delay     time.Duration
+	bodyDelay time.Duration
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(req.Header.Get("Authorization"), filesystem.SIGV4_ALGORITHM) ||
+		req.Header.Get("X-Amz-Date") == "" ||
+		req.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+		return
+	}
+	if s.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, "<Error><Code>InternalError</Code><Message>We encountered an internal error</Message></Error>")
+		return
+	}
+
+	key := req.URL.Path
+	switch req.Method {
+	case http.MethodPut:
+		source := req.Header.Get("X-Amz-Copy-Source")
+		if source != "" {
+			source, _ = url.PathUnescape(source)
+			data, ok := s.objects[source]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, "<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>")
+				return
+			}
+			s.objects[key] = data
+			fmt.Fprint(w, "<CopyObjectResult></CopyObjectResult>")
+			return
+		}
+		data, _ := io.ReadAll(req.Body)
+		s.objects[key] = data
+	case http.MethodHead:
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	case http.MethodGet:
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		start := 0
+		if r := req.Header.Get("Range"); r != "" && !s.ignoreRange {
+			start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r, "bytes="), "-"))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		if s.bodyDelay > 0 {
+			w.(http.Flusher).Flush()
+			time.Sleep(s.bodyDelay)
+		}
+		w.Write(data[start:])
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+var _ = Describe("S3 File Manager", func() {
+	Context("NewS3FileManager function", Label("unit"), func() {
+		var (
+			p filesystem.NewS3FileManagerParam
+		)
+
+		BeforeEach(func() {
+			p = filesystem.NewS3FileManagerParam{
+				Endpoint:  "http://localhost:9000",
+				Bucket:    "mock-bucket",
+				AccessKey: "mock-access-key",
+				SecretKey: "mock-secret-key",
+			}
+		})
+
+		When("endpoint is not specified", func() {
+			It("should return error", func() {
+				p.Endpoint = ""
+				res, err := filesystem.NewS3FileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("endpoint is not specified")))
+			})
+		})
+
+		When("endpoint is invalid", func() {
+			It("should return error", func() {
+				p.Endpoint = "localhost:9000"
+				res, err := filesystem.NewS3FileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid endpoint")))
+			})
+		})
+
+		When("bucket is not specified", func() {
+			It("should return error", func() {
+				p.Bucket = ""
+				res, err := filesystem.NewS3FileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("bucket is not specified")))
+			})
+		})
+
+		When("credential is not specified", func() {
+			It("should return error", func() {
+				p.AccessKey = ""
+				res, err := filesystem.NewS3FileManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("access key is not specified")))
+			})
+		})
+
+		When("all param is specified", func() {
+			It("should return result", func() {
+				p.Region = "ap-southeast-1"
+				p.VirtualHost = true
+				p.HttpClient = &http.Client{}
+				res, err := filesystem.NewS3FileManager(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Describe("S3 File Manager", Label("unit"), func() {
+		var (
+			ctx    context.Context
+			s3     *fakeS3
+			server *httptest.Server
+			fm     filesystem.FileManager
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			s3 = &fakeS3{
+				objects: map[string][]byte{
+					"/mock-bucket/storage/file.txt": []byte("hello object storage"),
+				},
+			}
+			server = httptest.NewServer(s3)
+			fm, _ = filesystem.NewS3FileManager(filesystem.NewS3FileManagerParam{
+				Endpoint:  server.URL,
+				Bucket:    "mock-bucket",
+				AccessKey: "mock-access-key",
+				SecretKey: "mock-secret-key",
+			})
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		Context("IsFileExists function", func() {
+			When("object is available", func() {
+				It("should return true", func() {
+					res, err := fm.IsFileExists(ctx, filesystem.IsFileExistsParam{
+						Path: "storage/file.txt",
+					})
+
+					Expect(res).To(BeTrue())
+					Expect(err).To(BeNil())
+				})
+			})
+
+			When("object is unavailable", func() {
+				It("should return false", func() {
+					res, err := fm.IsFileExists(ctx, filesystem.IsFileExistsParam{
+						Path: "storage/unavailable.txt",
+					})
+
+					Expect(res).To(BeFalse())
+					Expect(err).To(BeNil())
+				})
+			})
+
+			When("path is invalid", func() {
+				It("should return error", func() {
+					res, err := fm.IsFileExists(ctx, filesystem.IsFileExistsParam{
+						Path: "/",
+					})
+
+					Expect(res).To(BeFalse())
+					Expect(err).To(Equal(fmt.Errorf("invalid path")))
+				})
+			})
+
+			When("server is failing", func() {
+				It("should return error", func() {
+					s3.fail = true
+					res, err := fm.IsFileExists(ctx, filesystem.IsFileExistsParam{
+						Path: "storage/file.txt",
+					})
+
+					Expect(res).To(BeFalse())
+					Expect(err).To(Equal(fmt.Errorf("s3 request failed: 500 Internal Server Error")))
+				})
+			})
+
+			When("server is slower than the request timeout", func() {
+				It("should return error", func() {
+					s3.delay = 200 * time.Millisecond
+					fm, _ = filesystem.NewS3FileManager(filesystem.NewS3FileManagerParam{
+						Endpoint:       server.URL,
+						Bucket:         "mock-bucket",
+						AccessKey:      "mock-access-key",
+						SecretKey:      "mock-secret-key",
+						RequestTimeout: 20 * time.Millisecond,
+					})
+					res, err := fm.IsFileExists(ctx, filesystem.IsFileExistsParam{
+						Path: "storage/file.txt",
+					})
+
+					Expect(res).To(BeFalse())
+					Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+				})
+			})
+		})
+
+		Context("OpenFile function", func() {
+			When("object is unavailable", func() {
+				It("should return error", func() {
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/unavailable.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+				})
+			})
+
+			When("object is available", func() {
+				It("should read the object content", func() {
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					Expect(err).To(BeNil())
+					defer res.File.Close()
+
+					data, err := io.ReadAll(res.File)
+					Expect(err).To(BeNil())
+					Expect(string(data)).To(Equal("hello object storage"))
+				})
+			})
+
+			When("object download is slower than the request timeout", func() {
+				It("should read the object content", func() {
+					s3.bodyDelay = 100 * time.Millisecond
+					fm, _ = filesystem.NewS3FileManager(filesystem.NewS3FileManagerParam{
+						Endpoint:       server.URL,
+						Bucket:         "mock-bucket",
+						AccessKey:      "mock-access-key",
+						SecretKey:      "mock-secret-key",
+						RequestTimeout: 20 * time.Millisecond,
+					})
+					res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					Expect(err).To(BeNil())
+					defer res.File.Close()
+
+					data, err := io.ReadAll(res.File)
+					Expect(err).To(BeNil())
+					Expect(string(data)).To(Equal("hello object storage"))
+				})
+			})
+
+			When("object is seeked", func() {
+				It("should read from the new offset", func() {
+					res, _ := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					defer res.File.Close()
+
+					head := make([]byte, 5)
+					_, err := io.ReadFull(res.File, head)
+					Expect(err).To(BeNil())
+					Expect(string(head)).To(Equal("hello"))
+
+					size, err := res.File.Seek(0, io.SeekEnd)
+					Expect(err).To(BeNil())
+					Expect(size).To(Equal(int64(20)))
+
+					offset, err := res.File.Seek(-7, io.SeekCurrent)
+					Expect(err).To(BeNil())
+					Expect(offset).To(Equal(int64(13)))
+
+					data, err := io.ReadAll(res.File)
+					Expect(err).To(BeNil())
+					Expect(string(data)).To(Equal("storage"))
+				})
+			})
+
+			When("server ignores the range", func() {
+				It("should read from the new offset", func() {
+					s3.ignoreRange = true
+					res, _ := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					defer res.File.Close()
+
+					offset, err := res.File.Seek(6, io.SeekStart)
+					Expect(err).To(BeNil())
+					Expect(offset).To(Equal(int64(6)))
+
+					data, err := io.ReadAll(res.File)
+					Expect(err).To(BeNil())
+					Expect(string(data)).To(Equal("object storage"))
+				})
+			})
+
+			When("seek position is negative", func() {
+				It("should return error", func() {
+					res, _ := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					defer res.File.Close()
+
+					offset, err := res.File.Seek(-1, io.SeekStart)
+
+					Expect(offset).To(Equal(int64(0)))
+					Expect(err).To(Equal(fmt.Errorf("negative position")))
+				})
+			})
+
+			When("object is removed after opened", func() {
+				It("should return error", func() {
+					res, _ := fm.OpenFile(ctx, filesystem.OpenFileParam{
+						Path: "storage/file.txt",
+					})
+					defer res.File.Close()
+					delete(s3.objects, "/mock-bucket/storage/file.txt")
+
+					_, err := io.ReadAll(res.File)
+
+					Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+				})
+			})
+		})
+
+		Context("SaveFile function", func() {
+			When("success save file", func() {
+				It("should store the object", func() {
+					res, err := fm.SaveFile(ctx, filesystem.SaveFileParam{
+						Name: "storage/2022/08/new file.txt",
+						Data: []byte("new content"),
+					})
+
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+					Expect(s3.objects["/mock-bucket/storage/2022/08/new file.txt"]).To(Equal([]byte("new content")))
+				})
+			})
+
+			When("server is failing", func() {
+				It("should return error", func() {
+					s3.fail = true
+					res, err := fm.SaveFile(ctx, filesystem.SaveFileParam{
+						Name: "storage/new.txt",
+						Data: []byte("new content"),
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(fmt.Errorf("s3 request failed: InternalError: We encountered an internal error")))
+				})
+			})
+		})
+
+		Context("WriteFile function", func() {
+			When("success write file", func() {
+				It("should store the streamed object", func() {
+					res, err := fm.(filesystem.FileWriter).WriteFile(ctx, filesystem.WriteFileParam{
+						Name:   "storage/2022/08/streamed.txt",
+						Reader: strings.NewReader("streamed content"),
+						Size:   16,
+					})
+
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+					Expect(s3.objects["/mock-bucket/storage/2022/08/streamed.txt"]).To(Equal([]byte("streamed content")))
+				})
+			})
+
+			When("server is failing", func() {
+				It("should return error", func() {
+					s3.fail = true
+					res, err := fm.(filesystem.FileWriter).WriteFile(ctx, filesystem.WriteFileParam{
+						Name:   "storage/streamed.txt",
+						Reader: strings.NewReader("streamed content"),
+						Size:   16,
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(fmt.Errorf("s3 request failed: InternalError: We encountered an internal error")))
+				})
+			})
+		})
+
+		Context("RemoveFile function", func() {
+			When("object is unavailable", func() {
+				It("should return error", func() {
+					res, err := fm.RemoveFile(ctx, filesystem.RemoveFileParam{
+						Path: "storage/unavailable.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+				})
+			})
+
+			When("object is available", func() {
+				It("should remove the object", func() {
+					res, err := fm.RemoveFile(ctx, filesystem.RemoveFileParam{
+						Path: "storage/file.txt",
+					})
+
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+					Expect(s3.objects).ToNot(HaveKey("/mock-bucket/storage/file.txt"))
+				})
+			})
+		})
+
+		Context("MoveFile function", func() {
+			When("object is unavailable", func() {
+				It("should return error", func() {
+					res, err := fm.MoveFile(ctx, filesystem.MoveFileParam{
+						OldPath: "storage/unavailable.txt",
+						NewPath: "storage/moved.txt",
+					})
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(filesystem.ErrorFileNotFound))
+				})
+			})
+
+			When("object is available", func() {
+				It("should move the object", func() {
+					res, err := fm.MoveFile(ctx, filesystem.MoveFileParam{
+						OldPath: "storage/file.txt",
+						NewPath: "storage/2022/moved.txt",
+					})
+
+					Expect(res).ToNot(BeNil())
+					Expect(err).To(BeNil())
+					Expect(s3.objects).ToNot(HaveKey("/mock-bucket/storage/file.txt"))
+					Expect(s3.objects["/mock-bucket/storage/2022/moved.txt"]).To(Equal([]byte("hello object storage")))
+				})
+			})
+		})
+	})
+
+	// @note: run against a local minio, e.g:
+	// docker compose up -d minio && S3_TEST_ENDPOINT=http://localhost:9002 go test ./internal/filesystem/
+	Describe("S3 File Manager", Label("integration"), Ordered, func() {
+		var (
+			ctx context.Context
+			fm  filesystem.FileManager
+		)
+
+		BeforeAll(func() {
+			endpoint := os.Getenv("S3_TEST_ENDPOINT")
+			if endpoint == "" {
+				Skip("S3_TEST_ENDPOINT is not specified")
+			}
+			ctx = context.Background()
+			fm, _ = filesystem.NewS3FileManager(filesystem.NewS3FileManagerParam{
+				Endpoint:  endpoint,
+				Bucket:    "goseidon-local-test",
+				AccessKey: "admin",
+				SecretKey: "12345678",
+			})
+		})
+
+		It("should save, read, move and remove object", func() {
+			name := fmt.Sprintf("storage/%d.txt", time.Now().UnixNano())
+			_, err := fm.SaveFile(ctx, filesystem.SaveFileParam{
+				Name: name,
+				Data: []byte("hello minio"),
+			})
+			Expect(err).To(BeNil())
+
+			moved := name + ".moved"
+			_, err = fm.MoveFile(ctx, filesystem.MoveFileParam{
+				OldPath: name,
+				NewPath: moved,
+			})
+			Expect(err).To(BeNil())
+
+			exists, err := fm.IsFileExists(ctx, filesystem.IsFileExistsParam{Path: name})
+			Expect(exists).To(BeFalse())
+			Expect(err).To(BeNil())
+
+			res, err := fm.OpenFile(ctx, filesystem.OpenFileParam{Path: moved})
+			Expect(err).To(BeNil())
+			_, err = res.File.Seek(6, io.SeekStart)
+			Expect(err).To(BeNil())
+			data, err := io.ReadAll(res.File)
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(Equal("minio"))
+			Expect(res.File.Close()).To(BeNil())
+
+			_, err = fm.RemoveFile(ctx, filesystem.RemoveFileParam{Path: moved})
+			Expect(err).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("Object Directory Manager", func() {
+	Context("ObjectDirectoryManager", Label("unit"), func() {
+		When("checking directory", func() {
+			It("should always exist", func() {
+				dm := filesystem.NewObjectDirectoryManager()
+				res, err := dm.IsDirectoryExists(context.Background(), filesystem.IsDirectoryExistsParam{
+					Path: "any/directory",
+				})
+
+				Expect(res).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("creating directory", func() {
+			It("should return result", func() {
+				dm := filesystem.NewObjectDirectoryManager()
+				res, err := dm.CreateDir(context.Background(), filesystem.CreateDirParam{
+					Path: "any/directory",
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
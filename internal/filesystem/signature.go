@@ -0,0 +1,166 @@
+package filesystem
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SIGV4_ALGORITHM   = "AWS4-HMAC-SHA256"
+	SIGV4_DATE_FORMAT = "20060102T150405Z"
+	SIGV4_EMPTY_HASH  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+type SigV4Signer interface {
+	SignRequest(req *http.Request, payloadHash string, t time.Time)
+}
+
+type sigV4Signer struct {
+	accessKey string
+	secretKey string
+	region    string
+	service   string
+}
+
+func (s *sigV4Signer) SignRequest(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	date := t.Format("20060102")
+	scope := strings.Join([]string{date, s.region, s.service, "aws4_request"}, "/")
+
+	headers := map[string]string{
+		"host": req.Host,
+	}
+	if req.Host == "" {
+		headers["host"] = req.URL.Host
+	}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "authorization" || name == "user-agent" {
+			continue
+		}
+		trimmed := []string{}
+		for _, v := range values {
+			trimmed = append(trimmed, strings.Join(strings.Fields(v), " "))
+		}
+		headers[name] = strings.Join(trimmed, ",")
+	}
+	names := []string{}
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		SIGV4_ALGORITHM,
+		t.Format(SIGV4_DATE_FORMAT),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSum([]byte("AWS4"+s.secretKey), date)
+	key = hmacSum(key, s.region)
+	key = hmacSum(key, s.service)
+	key = hmacSum(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSum(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		SIGV4_ALGORITHM, s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	keys := []string{}
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, UriEncode(k, true)+"="+UriEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func UriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		unreserved := (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') ||
+			(c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~'
+		if unreserved || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSum(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+type NewSigV4SignerParam struct {
+	AccessKey string
+	SecretKey string
+	Region    string
+	Service   string
+}
+
+func NewSigV4Signer(p NewSigV4SignerParam) (*sigV4Signer, error) {
+	if p.AccessKey == "" {
+		return nil, fmt.Errorf("access key is not specified")
+	}
+	if p.SecretKey == "" {
+		return nil, fmt.Errorf("secret key is not specified")
+	}
+	if p.Region == "" {
+		return nil, fmt.Errorf("region is not specified")
+	}
+	if p.Service == "" {
+		return nil, fmt.Errorf("service is not specified")
+	}
+
+	s := &sigV4Signer{
+		accessKey: p.AccessKey,
+		secretKey: p.SecretKey,
+		region:    p.Region,
+		service:   p.Service,
+	}
+	return s, nil
+}
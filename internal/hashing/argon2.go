@@ -0,0 +1,190 @@
+package hashing
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	ARGON2ID_PREFIX = "$argon2id$"
+
+	// default parameter according to RFC 9106 second recommended option
+	ARGON2_DEFAULT_MEMORY      = 64 * 1024
+	ARGON2_DEFAULT_ITERATIONS  = 3
+	ARGON2_DEFAULT_PARALLELISM = 4
+	ARGON2_DEFAULT_SALT_LENGTH = 16
+	ARGON2_DEFAULT_KEY_LENGTH  = 32
+)
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// hash is encoded as PHC string, e.g:
+// $argon2id$v=19$m=65536,t=3,p=4$<base64 salt>$<base64 key>
+type argon2Hasher struct {
+	params     argon2Params
+	saltLength int
+	keyLength  uint32
+}
+
+func (h *argon2Hasher) Generate(src string) ([]byte, error) {
+	salt := make([]byte, h.saltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey(
+		[]byte(src), salt,
+		h.params.iterations, h.params.memory,
+		h.params.parallelism, h.keyLength,
+	)
+
+	hash := fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		ARGON2ID_PREFIX, argon2.Version,
+		h.params.memory, h.params.iterations, h.params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+	return []byte(hash), nil
+}
+
+func (h *argon2Hasher) Verify(hash string, text string) error {
+	if !strings.HasPrefix(hash, ARGON2ID_PREFIX) {
+		return verifyBcrypt(hash, text)
+	}
+	return verifyArgon2(hash, text)
+}
+
+func (h *argon2Hasher) NeedsRehash(hash string) bool {
+	params, _, key, err := decodeArgon2(hash)
+	if err != nil {
+		return true
+	}
+	return params != h.params || uint32(len(key)) != h.keyLength
+}
+
+func verifyArgon2(hash string, text string) error {
+	params, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey(
+		[]byte(text), salt,
+		params.iterations, params.memory,
+		params.parallelism, uint32(len(key)),
+	)
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return ErrorHashMismatch
+	}
+	return nil
+}
+
+func verifyBcrypt(hash string, text string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(text))
+	if err == nil {
+		return nil
+	}
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return ErrorHashMismatch
+	}
+	return ErrorUnsupportedHash
+}
+
+func decodeArgon2(hash string) (argon2Params, []byte, []byte, error) {
+	params := argon2Params{}
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrorInvalidHash
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return params, nil, nil, ErrorInvalidHash
+	}
+	if version != argon2.Version {
+		return params, nil, nil, ErrorUnsupportedHash
+	}
+
+	_, err = fmt.Sscanf(
+		parts[3], "m=%d,t=%d,p=%d",
+		&params.memory, &params.iterations, &params.parallelism,
+	)
+	if err != nil {
+		return params, nil, nil, ErrorInvalidHash
+	}
+	if params.memory == 0 || params.iterations == 0 || params.parallelism == 0 {
+		return params, nil, nil, ErrorInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrorInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrorInvalidHash
+	}
+	return params, salt, key, nil
+}
+
+type NewArgon2HasherParam struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  int
+	KeyLength   uint32
+}
+
+func NewArgon2Hasher(p NewArgon2HasherParam) (*argon2Hasher, error) {
+	if p.SaltLength < 0 {
+		return nil, fmt.Errorf("invalid salt length")
+	}
+
+	params := argon2Params{
+		memory:      ARGON2_DEFAULT_MEMORY,
+		iterations:  ARGON2_DEFAULT_ITERATIONS,
+		parallelism: ARGON2_DEFAULT_PARALLELISM,
+	}
+	if p.Memory > 0 {
+		params.memory = p.Memory
+	}
+	if p.Iterations > 0 {
+		params.iterations = p.Iterations
+	}
+	if p.Parallelism > 0 {
+		params.parallelism = p.Parallelism
+	}
+	// argon2 requires at least 8 KiB of memory per lane
+	if params.memory < 8*uint32(params.parallelism) {
+		return nil, fmt.Errorf("memory must be at least %d KiB", 8*uint32(params.parallelism))
+	}
+
+	saltLength := ARGON2_DEFAULT_SALT_LENGTH
+	if p.SaltLength > 0 {
+		saltLength = p.SaltLength
+	}
+	keyLength := uint32(ARGON2_DEFAULT_KEY_LENGTH)
+	if p.KeyLength > 0 {
+		keyLength = p.KeyLength
+	}
+
+	h := &argon2Hasher{
+		params:     params,
+		saltLength: saltLength,
+		keyLength:  keyLength,
+	}
+	return h, nil
+}
@@ -0,0 +1,203 @@
+package hashing_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-seidon/local/internal/hashing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Argon2 Hasher Package", func() {
+	Context("NewArgon2Hasher function", Label("unit"), func() {
+		When("parameter is not specified", func() {
+			It("should return result", func() {
+				res, err := hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("salt length is invalid", func() {
+			It("should return error", func() {
+				res, err := hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+					SaltLength: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid salt length")))
+			})
+		})
+
+		When("memory is too small", func() {
+			It("should return error", func() {
+				res, err := hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+					Memory:      16,
+					Parallelism: 4,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("memory must be at least 32 KiB")))
+			})
+		})
+	})
+
+	Context("Generate function", Label("unit"), func() {
+		var (
+			h hashing.Hasher
+		)
+
+		BeforeEach(func() {
+			h, _ = hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+				Memory:      64,
+				Iterations:  1,
+				Parallelism: 1,
+			})
+		})
+
+		When("success generate hash", func() {
+			It("should return phc string", func() {
+				res, err := h.Generate("some-secret")
+
+				Expect(err).To(BeNil())
+				Expect(string(res)).To(HavePrefix("$argon2id$v=19$m=64,t=1,p=1$"))
+				Expect(strings.Split(string(res), "$")).To(HaveLen(6))
+				Expect(h.Verify(string(res), "some-secret")).To(BeNil())
+			})
+		})
+
+		When("same text is hashed twice", func() {
+			It("should return different hash", func() {
+				res1, _ := h.Generate("some-secret")
+				res2, _ := h.Generate("some-secret")
+
+				Expect(res1).ToNot(Equal(res2))
+			})
+		})
+	})
+
+	Context("Verify function", Label("unit"), func() {
+		var (
+			h    hashing.Hasher
+			hash string
+		)
+
+		BeforeEach(func() {
+			h, _ = hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+				Memory:      64,
+				Iterations:  1,
+				Parallelism: 1,
+			})
+			res, _ := h.Generate("some-secret")
+			hash = string(res)
+		})
+
+		When("hash is equal", func() {
+			It("should return nil", func() {
+				err := h.Verify(hash, "some-secret")
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("hash is not equal", func() {
+			It("should return error", func() {
+				err := h.Verify(hash, "other-secret")
+
+				Expect(err).To(Equal(hashing.ErrorHashMismatch))
+			})
+		})
+
+		When("hash is generated with other parameter", func() {
+			It("should verify using the encoded parameter", func() {
+				other, _ := hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+					Memory:      128,
+					Iterations:  2,
+					Parallelism: 2,
+				})
+
+				err := other.Verify(hash, "some-secret")
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("hash is malformed", func() {
+			It("should return error", func() {
+				err := h.Verify("$argon2id$v=19$m=64,t=1$salt$key", "some-secret")
+
+				Expect(err).To(Equal(hashing.ErrorInvalidHash))
+			})
+		})
+
+		When("hash version is not supported", func() {
+			It("should return error", func() {
+				err := h.Verify("$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5", "some-secret")
+
+				Expect(err).To(Equal(hashing.ErrorUnsupportedHash))
+			})
+		})
+
+		When("hash is generated by bcrypt", func() {
+			It("should verify the hash", func() {
+				bcryptHash := "$2a$10$xA9.FPfIYi2ZI6V5/jw5leFVUCjsgN4lBS5iS8loLv1hngJj1ys/2"
+
+				Expect(h.Verify(bcryptHash, "some-secret")).To(BeNil())
+				Expect(h.Verify(bcryptHash, "other-secret")).To(Equal(hashing.ErrorHashMismatch))
+			})
+		})
+
+		When("hash algorithm is unknown", func() {
+			It("should return error", func() {
+				err := h.Verify("$md5$hash", "some-secret")
+
+				Expect(err).To(Equal(hashing.ErrorUnsupportedHash))
+			})
+		})
+	})
+
+	Context("NeedsRehash function", Label("unit"), func() {
+		var (
+			h hashing.Hasher
+		)
+
+		BeforeEach(func() {
+			h, _ = hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+				Memory:      64,
+				Iterations:  1,
+				Parallelism: 1,
+			})
+		})
+
+		When("parameter is equal", func() {
+			It("should return false", func() {
+				hash, _ := h.Generate("some-secret")
+
+				Expect(h.NeedsRehash(string(hash))).To(BeFalse())
+			})
+		})
+
+		When("parameter is outdated", func() {
+			It("should return true", func() {
+				other, _ := hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+					Memory:      64,
+					Iterations:  2,
+					Parallelism: 1,
+				})
+				hash, _ := other.Generate("some-secret")
+
+				Expect(h.NeedsRehash(string(hash))).To(BeTrue())
+			})
+		})
+
+		When("hash is generated by bcrypt", func() {
+			It("should return true", func() {
+				hash := "$2a$10$xA9.FPfIYi2ZI6V5/jw5leFVUCjsgN4lBS5iS8loLv1hngJj1ys/2"
+
+				Expect(h.NeedsRehash(hash)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -1,19 +1,54 @@
 package hashing
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	BCRYPT_DEFAULT_COST = bcrypt.DefaultCost
+)
 
 type bcryptHasher struct {
+	cost int
 }
 
 func (h *bcryptHasher) Generate(src string) ([]byte, error) {
-	return bcrypt.GenerateFromPassword([]byte(src), bcrypt.DefaultCost)
+	return bcrypt.GenerateFromPassword([]byte(src), h.cost)
 }
 
 func (h *bcryptHasher) Verify(hash string, text string) error {
+	if strings.HasPrefix(hash, ARGON2ID_PREFIX) {
+		return verifyArgon2(hash, text)
+	}
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(text))
 }
 
-func NewBcryptHasher() *bcryptHasher {
-	h := &bcryptHasher{}
+func (h *bcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
+
+type BcryptOption func(*bcryptHasher)
+
+func WithBcryptCost(cost int) BcryptOption {
+	return func(h *bcryptHasher) {
+		if cost >= bcrypt.MinCost && cost <= bcrypt.MaxCost {
+			h.cost = cost
+		}
+	}
+}
+
+func NewBcryptHasher(opts ...BcryptOption) *bcryptHasher {
+	h := &bcryptHasher{
+		cost: BCRYPT_DEFAULT_COST,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
 	return h
 }
@@ -73,4 +73,63 @@ var _ = Describe("Bcrypt Hasher Package", func() {
 		})
 	})
 
+	Context("Verify argon2id hash", Label("unit"), func() {
+		When("hash is generated by argon2id", func() {
+			It("should verify the hash", func() {
+				a, _ := hashing.NewArgon2Hasher(hashing.NewArgon2HasherParam{
+					Memory:      64,
+					Iterations:  1,
+					Parallelism: 1,
+				})
+				hash, _ := a.Generate("some-secret")
+				h := hashing.NewBcryptHasher()
+
+				Expect(h.Verify(string(hash), "some-secret")).To(BeNil())
+				Expect(h.Verify(string(hash), "other-secret")).To(Equal(hashing.ErrorHashMismatch))
+			})
+		})
+	})
+
+	Context("NeedsRehash function", Label("unit"), func() {
+		var (
+			hash string
+		)
+
+		BeforeEach(func() {
+			hash = "$2a$10$xA9.FPfIYi2ZI6V5/jw5leFVUCjsgN4lBS5iS8loLv1hngJj1ys/2"
+		})
+
+		When("cost is equal", func() {
+			It("should return false", func() {
+				h := hashing.NewBcryptHasher()
+
+				Expect(h.NeedsRehash(hash)).To(BeFalse())
+			})
+		})
+
+		When("cost is outdated", func() {
+			It("should return true", func() {
+				h := hashing.NewBcryptHasher(hashing.WithBcryptCost(12))
+
+				Expect(h.NeedsRehash(hash)).To(BeTrue())
+			})
+		})
+
+		When("cost is out of range", func() {
+			It("should use the default cost", func() {
+				h := hashing.NewBcryptHasher(hashing.WithBcryptCost(64))
+
+				Expect(h.NeedsRehash(hash)).To(BeFalse())
+			})
+		})
+
+		When("hash is generated by other algorithm", func() {
+			It("should return true", func() {
+				h := hashing.NewBcryptHasher()
+
+				Expect(h.NeedsRehash("$argon2id$v=19$m=64,t=1,p=1$c2FsdA$a2V5")).To(BeTrue())
+			})
+		})
+	})
+
 })
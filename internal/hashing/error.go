@@ -0,0 +1,9 @@
+package hashing
+
+import "errors"
+
+var (
+	ErrorHashMismatch    = errors.New("hash does not match the text")
+	ErrorInvalidHash     = errors.New("invalid hash format")
+	ErrorUnsupportedHash = errors.New("hash algorithm is not supported")
+)
@@ -3,6 +3,7 @@ package hashing
 type Hasher interface {
 	Generator
 	Verificator
+	Rehasher
 }
 
 type Generator interface {
@@ -12,3 +13,7 @@ type Generator interface {
 type Verificator interface {
 	Verify(hash string, text string) error
 }
+
+type Rehasher interface {
+	NeedsRehash(hash string) bool
+}
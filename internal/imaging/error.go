@@ -0,0 +1,7 @@
+package imaging
+
+import "errors"
+
+var (
+	ErrorImageTooLarge = errors.New("image dimension exceeds the limit")
+)
@@ -0,0 +1,312 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	EXIF_MAKE              = "make"
+	EXIF_MODEL             = "model"
+	EXIF_ORIENTATION       = "orientation"
+	EXIF_SOFTWARE          = "software"
+	EXIF_DATETIME          = "datetime"
+	EXIF_DATETIME_ORIGINAL = "datetime_original"
+	EXIF_EXPOSURE_TIME     = "exposure_time"
+	EXIF_F_NUMBER          = "f_number"
+	EXIF_ISO               = "iso"
+	EXIF_FOCAL_LENGTH      = "focal_length"
+	EXIF_LENS_MODEL        = "lens_model"
+	EXIF_GPS_LATITUDE      = "gps_latitude"
+	EXIF_GPS_LONGITUDE     = "gps_longitude"
+	EXIF_GPS_ALTITUDE      = "gps_altitude"
+)
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	xmpHeader  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+)
+
+type Exif struct {
+	Orientation int
+	Fields      map[string]string
+}
+
+const (
+	tiffByte      = 1
+	tiffAscii     = 2
+	tiffShort     = 3
+	tiffLong      = 4
+	tiffRational  = 5
+	tiffUndefined = 7
+	tiffSLong     = 9
+	tiffSRational = 10
+)
+
+var tiffTypeSize = map[uint16]uint32{
+	tiffByte:      1,
+	tiffAscii:     1,
+	tiffShort:     2,
+	tiffLong:      4,
+	tiffRational:  8,
+	tiffUndefined: 1,
+	tiffSLong:     4,
+	tiffSRational: 8,
+}
+
+var (
+	ifd0Tags = map[uint16]string{
+		0x010F: EXIF_MAKE,
+		0x0110: EXIF_MODEL,
+		0x0112: EXIF_ORIENTATION,
+		0x0131: EXIF_SOFTWARE,
+		0x0132: EXIF_DATETIME,
+	}
+	exifTags = map[uint16]string{
+		0x829A: EXIF_EXPOSURE_TIME,
+		0x829D: EXIF_F_NUMBER,
+		0x8827: EXIF_ISO,
+		0x9003: EXIF_DATETIME_ORIGINAL,
+		0x920A: EXIF_FOCAL_LENGTH,
+		0xA434: EXIF_LENS_MODEL,
+	}
+)
+
+const (
+	tagExifPointer = 0x8769
+	tagGpsPointer  = 0x8825
+
+	tagGpsLatitudeRef  = 0x0001
+	tagGpsLatitude     = 0x0002
+	tagGpsLongitudeRef = 0x0003
+	tagGpsLongitude    = 0x0004
+	tagGpsAltitudeRef  = 0x0005
+	tagGpsAltitude     = 0x0006
+)
+
+type tiffEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func ParseJpegExif(data []byte) (*Exif, error) {
+	var payload []byte
+	err := walkJpegSegments(data, func(marker byte, segment []byte) bool {
+		if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+			payload = segment[len(exifHeader):]
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, nil
+	}
+	return ParseTiff(payload)
+}
+
+func ParseTiff(data []byte) (*Exif, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("invalid exif header")
+	}
+
+	r := &tiffReader{data: data}
+	switch string(data[0:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid exif byte order")
+	}
+	if r.order.Uint16(data[2:4]) != 42 {
+		return nil, fmt.Errorf("invalid exif header")
+	}
+
+	ifd0, err := r.readIfd(r.order.Uint32(data[4:8]))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Exif{
+		Fields: map[string]string{},
+	}
+	r.collect(ifd0, ifd0Tags, res.Fields)
+
+	if e, ok := ifd0[0x0112]; ok {
+		o := r.uint(e)
+		if o >= 1 && o <= 8 {
+			res.Orientation = int(o)
+		}
+	}
+
+	if e, ok := ifd0[tagExifPointer]; ok {
+		exifIfd, err := r.readIfd(r.uint(e))
+		if err != nil {
+			return nil, err
+		}
+		r.collect(exifIfd, exifTags, res.Fields)
+	}
+
+	if e, ok := ifd0[tagGpsPointer]; ok {
+		gpsIfd, err := r.readIfd(r.uint(e))
+		if err != nil {
+			return nil, err
+		}
+		r.collectGps(gpsIfd, res.Fields)
+	}
+
+	return res, nil
+}
+
+func (r *tiffReader) readIfd(offset uint32) (map[uint16]tiffEntry, error) {
+	if uint64(offset)+2 > uint64(len(r.data)) {
+		return nil, fmt.Errorf("invalid exif ifd offset")
+	}
+
+	total := uint32(r.order.Uint16(r.data[offset : offset+2]))
+	start := offset + 2
+	if uint64(start)+uint64(total)*12 > uint64(len(r.data)) {
+		return nil, fmt.Errorf("invalid exif ifd size")
+	}
+
+	entries := map[uint16]tiffEntry{}
+	for i := uint32(0); i < total; i++ {
+		e := r.data[start+i*12 : start+(i+1)*12]
+		tag := r.order.Uint16(e[0:2])
+		typ := r.order.Uint16(e[2:4])
+		count := r.order.Uint32(e[4:8])
+
+		size, ok := tiffTypeSize[typ]
+		if !ok {
+			continue
+		}
+
+		length := uint64(size) * uint64(count)
+		value := e[8:12]
+		if length > 4 {
+			valOffset := uint64(r.order.Uint32(e[8:12]))
+			if valOffset+length > uint64(len(r.data)) {
+				continue
+			}
+			value = r.data[valOffset : valOffset+length]
+		} else {
+			value = value[:length]
+		}
+
+		entries[tag] = tiffEntry{
+			typ:   typ,
+			count: count,
+			value: value,
+		}
+	}
+	return entries, nil
+}
+
+func (r *tiffReader) collect(entries map[uint16]tiffEntry, tags map[uint16]string, fields map[string]string) {
+	for tag, name := range tags {
+		e, ok := entries[tag]
+		if !ok {
+			continue
+		}
+		v := r.format(e)
+		if v == "" {
+			continue
+		}
+		fields[name] = v
+	}
+}
+
+func (r *tiffReader) collectGps(entries map[uint16]tiffEntry, fields map[string]string) {
+	lat, latOk := r.coordinate(entries, tagGpsLatitude, tagGpsLatitudeRef, "S")
+	lon, lonOk := r.coordinate(entries, tagGpsLongitude, tagGpsLongitudeRef, "W")
+	if latOk && lonOk {
+		fields[EXIF_GPS_LATITUDE] = strconv.FormatFloat(lat, 'f', 6, 64)
+		fields[EXIF_GPS_LONGITUDE] = strconv.FormatFloat(lon, 'f', 6, 64)
+	}
+
+	if e, ok := entries[tagGpsAltitude]; ok && e.typ == tiffRational && e.count >= 1 {
+		alt := r.rational(e.value[0:8])
+		if ref, ok := entries[tagGpsAltitudeRef]; ok && len(ref.value) > 0 && ref.value[0] == 1 {
+			alt = -alt
+		}
+		fields[EXIF_GPS_ALTITUDE] = strconv.FormatFloat(alt, 'f', 2, 64)
+	}
+}
+
+func (r *tiffReader) coordinate(entries map[uint16]tiffEntry, tag, refTag uint16, negativeRef string) (float64, bool) {
+	e, ok := entries[tag]
+	if !ok || e.typ != tiffRational || e.count < 3 {
+		return 0, false
+	}
+
+	deg := r.rational(e.value[0:8])
+	min := r.rational(e.value[8:16])
+	sec := r.rational(e.value[16:24])
+	res := deg + min/60 + sec/3600
+
+	if ref, ok := entries[refTag]; ok && strings.HasPrefix(r.ascii(ref.value), negativeRef) {
+		res = -res
+	}
+	return res, true
+}
+
+func (r *tiffReader) format(e tiffEntry) string {
+	switch e.typ {
+	case tiffAscii:
+		return r.ascii(e.value)
+	case tiffUndefined:
+		return ""
+	case tiffShort, tiffLong, tiffByte:
+		return strconv.FormatUint(uint64(r.uint(e)), 10)
+	case tiffSLong:
+		return strconv.FormatInt(int64(int32(r.uint(e))), 10)
+	case tiffRational, tiffSRational:
+		if len(e.value) < 8 {
+			return ""
+		}
+		num := r.order.Uint32(e.value[0:4])
+		den := r.order.Uint32(e.value[4:8])
+		if e.typ == tiffSRational {
+			return fmt.Sprintf("%d/%d", int32(num), int32(den))
+		}
+		return fmt.Sprintf("%d/%d", num, den)
+	}
+	return ""
+}
+
+func (r *tiffReader) uint(e tiffEntry) uint32 {
+	switch {
+	case e.typ == tiffShort && len(e.value) >= 2:
+		return uint32(r.order.Uint16(e.value[0:2]))
+	case (e.typ == tiffLong || e.typ == tiffSLong) && len(e.value) >= 4:
+		return r.order.Uint32(e.value[0:4])
+	case e.typ == tiffByte && len(e.value) >= 1:
+		return uint32(e.value[0])
+	}
+	return 0
+}
+
+func (r *tiffReader) rational(b []byte) float64 {
+	num := r.order.Uint32(b[0:4])
+	den := r.order.Uint32(b[4:8])
+	if den == 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
+
+func (r *tiffReader) ascii(b []byte) string {
+	return strings.TrimSpace(strings.TrimRight(string(b), "\x00"))
+}
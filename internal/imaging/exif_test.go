@@ -0,0 +1,202 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	"github.com/go-seidon/local/internal/imaging"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type exifEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func buildIfd(start uint32, entries []exifEntry) []byte {
+	le := binary.LittleEndian
+	header := new(bytes.Buffer)
+	extra := new(bytes.Buffer)
+	dataStart := start + 2 + uint32(len(entries))*12 + 4
+
+	binary.Write(header, le, uint16(len(entries)))
+	for _, e := range entries {
+		binary.Write(header, le, e.tag)
+		binary.Write(header, le, e.typ)
+		binary.Write(header, le, e.count)
+		if len(e.data) <= 4 {
+			v := make([]byte, 4)
+			copy(v, e.data)
+			header.Write(v)
+			continue
+		}
+		binary.Write(header, le, dataStart+uint32(extra.Len()))
+		extra.Write(e.data)
+	}
+	binary.Write(header, le, uint32(0))
+	header.Write(extra.Bytes())
+	return header.Bytes()
+}
+
+func rationals(vals ...uint32) []byte {
+	b := new(bytes.Buffer)
+	for _, v := range vals {
+		binary.Write(b, binary.LittleEndian, v)
+		binary.Write(b, binary.LittleEndian, uint32(1))
+	}
+	return b.Bytes()
+}
+
+func short(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	return b
+}
+
+func long(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func BuildTiff(orientation uint16, withGps bool) []byte {
+	ifd0 := []exifEntry{
+		{tag: 0x010F, typ: 2, count: 6, data: []byte("Canon\x00")},
+		{tag: 0x0112, typ: 3, count: 1, data: short(orientation)},
+	}
+	ifd0Size := uint32(2 + 12*3 + 4 + 6)
+	if withGps {
+		ifd0 = append(ifd0, exifEntry{tag: 0x8825, typ: 4, count: 1, data: long(8 + ifd0Size)})
+	} else {
+		ifd0 = append(ifd0, exifEntry{tag: 0x0131, typ: 2, count: 4, data: []byte("gim\x00")})
+	}
+
+	res := new(bytes.Buffer)
+	res.Write([]byte("II"))
+	binary.Write(res, binary.LittleEndian, uint16(42))
+	binary.Write(res, binary.LittleEndian, uint32(8))
+	res.Write(buildIfd(8, ifd0))
+
+	if withGps {
+		res.Write(buildIfd(uint32(res.Len()), []exifEntry{
+			{tag: 0x0001, typ: 2, count: 2, data: []byte("S\x00")},
+			{tag: 0x0002, typ: 5, count: 3, data: rationals(6, 30, 0)},
+			{tag: 0x0003, typ: 2, count: 2, data: []byte("E\x00")},
+			{tag: 0x0004, typ: 5, count: 3, data: rationals(106, 45, 0)},
+		}))
+	}
+	return res.Bytes()
+}
+
+func BuildJpeg(w, h int, tiff []byte) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 10), G: uint8(y * 10), B: 100, A: 255})
+		}
+	}
+	buff := new(bytes.Buffer)
+	jpeg.Encode(buff, img, nil)
+	data := buff.Bytes()
+	if tiff == nil {
+		return data
+	}
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = append(segment, short(0)...)
+	binary.BigEndian.PutUint16(segment[2:4], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	res := []byte{}
+	res = append(res, data[0:2]...)
+	res = append(res, segment...)
+	res = append(res, data[2:]...)
+	return res
+}
+
+var _ = Describe("Exif Package", func() {
+	Context("ParseJpegExif function", Label("unit"), func() {
+		When("data is not a jpeg", func() {
+			It("should return error", func() {
+				res, err := imaging.ParseJpegExif([]byte("not-a-jpeg"))
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("exif is not available", func() {
+			It("should return empty result", func() {
+				res, err := imaging.ParseJpegExif(BuildJpeg(4, 2, nil))
+
+				Expect(res).To(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("exif contains gps", func() {
+			It("should return result", func() {
+				res, err := imaging.ParseJpegExif(BuildJpeg(4, 2, BuildTiff(6, true)))
+
+				Expect(err).To(BeNil())
+				Expect(res.Orientation).To(Equal(6))
+				Expect(res.Fields).To(Equal(map[string]string{
+					"make":          "Canon",
+					"orientation":   "6",
+					"gps_latitude":  "-6.500000",
+					"gps_longitude": "106.750000",
+				}))
+			})
+		})
+
+		When("exif does not contain gps", func() {
+			It("should return result", func() {
+				res, err := imaging.ParseJpegExif(BuildJpeg(4, 2, BuildTiff(1, false)))
+
+				Expect(err).To(BeNil())
+				Expect(res.Orientation).To(Equal(1))
+				Expect(res.Fields).To(Equal(map[string]string{
+					"make":        "Canon",
+					"orientation": "1",
+					"software":    "gim",
+				}))
+			})
+		})
+	})
+
+	Context("ParseTiff function", Label("unit"), func() {
+		When("header is too short", func() {
+			It("should return error", func() {
+				res, err := imaging.ParseTiff([]byte("II"))
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid exif header"))
+			})
+		})
+
+		When("byte order is invalid", func() {
+			It("should return error", func() {
+				res, err := imaging.ParseTiff([]byte("XX*\x00\x08\x00\x00\x00"))
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid exif byte order"))
+			})
+		})
+
+		When("ifd offset is invalid", func() {
+			It("should return error", func() {
+				res, err := imaging.ParseTiff([]byte("II*\x00\xff\x00\x00\x00"))
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid exif ifd offset"))
+			})
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package imaging_test
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/go-seidon/local/internal/imaging"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Orient Package", func() {
+	Context("Orient function", Label("unit"), func() {
+		var (
+			src    *image.RGBA
+			marker color.RGBA
+		)
+
+		BeforeEach(func() {
+			// 3x2 image with marker on top-left pixel
+			src = image.NewRGBA(image.Rect(0, 0, 3, 2))
+			marker = color.RGBA{R: 255, A: 255}
+			src.Set(0, 0, marker)
+		})
+
+		When("orientation is normal", func() {
+			It("should return source image", func() {
+				res := imaging.Orient(src, 1)
+
+				Expect(res).To(Equal(src))
+			})
+		})
+
+		When("orientation is invalid", func() {
+			It("should return source image", func() {
+				res := imaging.Orient(src, 9)
+
+				Expect(res).To(Equal(src))
+			})
+		})
+
+		When("orientation is mirrored horizontally", func() {
+			It("should flip the image", func() {
+				res := imaging.Orient(src, 2)
+
+				Expect(res.Bounds()).To(Equal(image.Rect(0, 0, 3, 2)))
+				Expect(res.At(2, 0)).To(Equal(marker))
+			})
+		})
+
+		When("orientation is rotated 180", func() {
+			It("should rotate the image", func() {
+				res := imaging.Orient(src, 3)
+
+				Expect(res.Bounds()).To(Equal(image.Rect(0, 0, 3, 2)))
+				Expect(res.At(2, 1)).To(Equal(marker))
+			})
+		})
+
+		When("orientation is rotated 90 clockwise", func() {
+			It("should rotate the image", func() {
+				res := imaging.Orient(src, 6)
+
+				Expect(res.Bounds()).To(Equal(image.Rect(0, 0, 2, 3)))
+				Expect(res.At(1, 0)).To(Equal(marker))
+			})
+		})
+
+		When("orientation is rotated 90 counter clockwise", func() {
+			It("should rotate the image", func() {
+				res := imaging.Orient(src, 8)
+
+				Expect(res.Bounds()).To(Equal(image.Rect(0, 0, 2, 3)))
+				Expect(res.At(0, 2)).To(Equal(marker))
+			})
+		})
+	})
+})
@@ -0,0 +1,207 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"strings"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	FORMAT_JPEG = "jpeg"
+	FORMAT_PNG  = "png"
+	FORMAT_GIF  = "gif"
+
+	ORIENTATION_NORMAL = 1
+
+	DEFAULT_MAX_PIXELS = 50000000
+)
+
+type ImageProcessor interface {
+	ExtractMetadata(ctx context.Context, p ExtractMetadataParam) (*ExtractMetadataResult, error)
+	StripMetadata(ctx context.Context, p StripMetadataParam) (*StripMetadataResult, error)
+}
+
+type ExtractMetadataParam struct {
+	Data []byte
+}
+
+type ExtractMetadataResult struct {
+	Format      string
+	Width       int
+	Height      int
+	Orientation int
+	Exif        map[string]string
+}
+
+type StripMetadataParam struct {
+	Data             []byte
+	ApplyOrientation bool
+}
+
+type StripMetadataResult struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+type imageProcessor struct {
+	jpegQuality int
+	maxPixels   int64
+}
+
+func (ip *imageProcessor) ExtractMetadata(ctx context.Context, p ExtractMetadataParam) (*ExtractMetadataResult, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(p.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ExtractMetadataResult{
+		Format:      format,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Orientation: ORIENTATION_NORMAL,
+		Exif:        map[string]string{},
+	}
+	if format != FORMAT_JPEG {
+		return res, nil
+	}
+
+	exif, err := ParseJpegExif(p.Data)
+	if err != nil {
+		return nil, err
+	}
+	if exif == nil {
+		return res, nil
+	}
+
+	if exif.Orientation != 0 {
+		res.Orientation = exif.Orientation
+	}
+	res.Exif = exif.Fields
+	return res, nil
+}
+
+func (ip *imageProcessor) StripMetadata(ctx context.Context, p StripMetadataParam) (*StripMetadataResult, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(p.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FORMAT_JPEG:
+		return ip.stripJpeg(p, cfg)
+	case FORMAT_PNG:
+		data, err := StripPngMetadata(p.Data)
+		if err != nil {
+			return nil, err
+		}
+		res := &StripMetadataResult{
+			Data:   data,
+			Width:  cfg.Width,
+			Height: cfg.Height,
+		}
+		return res, nil
+	}
+
+	res := &StripMetadataResult{
+		Data:   p.Data,
+		Width:  cfg.Width,
+		Height: cfg.Height,
+	}
+	return res, nil
+}
+
+func (ip *imageProcessor) stripJpeg(p StripMetadataParam, cfg image.Config) (*StripMetadataResult, error) {
+	orientation := ORIENTATION_NORMAL
+	if p.ApplyOrientation {
+		// corrupt exif is stripped without applying the orientation
+		exif, err := ParseJpegExif(p.Data)
+		if err == nil && exif != nil && exif.Orientation != 0 {
+			orientation = exif.Orientation
+		}
+	}
+
+	if orientation == ORIENTATION_NORMAL {
+		data, err := StripJpegMetadata(p.Data)
+		if err != nil {
+			return nil, err
+		}
+		res := &StripMetadataResult{
+			Data:   data,
+			Width:  cfg.Width,
+			Height: cfg.Height,
+		}
+		return res, nil
+	}
+
+	// the decoded pixels are held in memory, the dimension is checked before decoding
+	if int64(cfg.Width)*int64(cfg.Height) > ip.maxPixels {
+		return nil, ErrorImageTooLarge
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(p.Data))
+	if err != nil {
+		return nil, err
+	}
+	oriented := Orient(img, orientation)
+
+	buff := new(bytes.Buffer)
+	err = jpeg.Encode(buff, oriented, &jpeg.Options{
+		Quality: ip.jpegQuality,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := oriented.Bounds()
+	res := &StripMetadataResult{
+		Data:   buff.Bytes(),
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}
+	return res, nil
+}
+
+func IsSupportedMimetype(mimetype string) bool {
+	m := strings.ToLower(strings.TrimSpace(strings.Split(mimetype, ";")[0]))
+	switch m {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+type NewImageProcessorParam struct {
+	JpegQuality int
+	MaxPixels   int64
+}
+
+func NewImageProcessor(p NewImageProcessorParam) (*imageProcessor, error) {
+	quality := 90
+	if p.JpegQuality != 0 {
+		quality = p.JpegQuality
+	}
+	if quality < 1 || quality > 100 {
+		return nil, fmt.Errorf("invalid jpeg quality")
+	}
+	if p.MaxPixels < 0 {
+		return nil, fmt.Errorf("invalid max pixels")
+	}
+
+	maxPixels := int64(DEFAULT_MAX_PIXELS)
+	if p.MaxPixels > 0 {
+		maxPixels = p.MaxPixels
+	}
+
+	ip := &imageProcessor{
+		jpegQuality: quality,
+		maxPixels:   maxPixels,
+	}
+	return ip, nil
+}
@@ -0,0 +1,263 @@
+package imaging_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/go-seidon/local/internal/imaging"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestImaging(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Imaging Package")
+}
+
+var _ = Describe("Image Processor", func() {
+	Context("NewImageProcessor function", Label("unit"), func() {
+		When("param is empty", func() {
+			It("should return result", func() {
+				res, err := imaging.NewImageProcessor(imaging.NewImageProcessorParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("jpeg quality is invalid", func() {
+			It("should return error", func() {
+				res, err := imaging.NewImageProcessor(imaging.NewImageProcessorParam{
+					JpegQuality: 101,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid jpeg quality")))
+			})
+		})
+
+		When("max pixels is invalid", func() {
+			It("should return error", func() {
+				res, err := imaging.NewImageProcessor(imaging.NewImageProcessorParam{
+					MaxPixels: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid max pixels")))
+			})
+		})
+	})
+
+	Context("IsSupportedMimetype function", Label("unit"), func() {
+		When("mimetype is supported", func() {
+			It("should return true", func() {
+				Expect(imaging.IsSupportedMimetype("image/jpeg")).To(BeTrue())
+				Expect(imaging.IsSupportedMimetype("IMAGE/PNG")).To(BeTrue())
+				Expect(imaging.IsSupportedMimetype("image/gif; charset=binary")).To(BeTrue())
+			})
+		})
+
+		When("mimetype is not supported", func() {
+			It("should return false", func() {
+				Expect(imaging.IsSupportedMimetype("image/webp")).To(BeFalse())
+				Expect(imaging.IsSupportedMimetype("text/plain")).To(BeFalse())
+			})
+		})
+	})
+
+	Context("ExtractMetadata function", Label("unit"), func() {
+		var (
+			ctx context.Context
+			ip  imaging.ImageProcessor
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ip, _ = imaging.NewImageProcessor(imaging.NewImageProcessorParam{})
+		})
+
+		When("data is not an image", func() {
+			It("should return error", func() {
+				res, err := ip.ExtractMetadata(ctx, imaging.ExtractMetadataParam{
+					Data: []byte("plain text"),
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(image.ErrFormat))
+			})
+		})
+
+		When("image is a png", func() {
+			It("should return result", func() {
+				buff := new(bytes.Buffer)
+				png.Encode(buff, image.NewRGBA(image.Rect(0, 0, 3, 5)))
+
+				res, err := ip.ExtractMetadata(ctx, imaging.ExtractMetadataParam{
+					Data: buff.Bytes(),
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(&imaging.ExtractMetadataResult{
+					Format:      "png",
+					Width:       3,
+					Height:      5,
+					Orientation: 1,
+					Exif:        map[string]string{},
+				}))
+			})
+		})
+
+		When("jpeg does not have exif", func() {
+			It("should return result", func() {
+				res, err := ip.ExtractMetadata(ctx, imaging.ExtractMetadataParam{
+					Data: BuildJpeg(4, 2, nil),
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(&imaging.ExtractMetadataResult{
+					Format:      "jpeg",
+					Width:       4,
+					Height:      2,
+					Orientation: 1,
+					Exif:        map[string]string{},
+				}))
+			})
+		})
+
+		When("jpeg has exif", func() {
+			It("should return result", func() {
+				res, err := ip.ExtractMetadata(ctx, imaging.ExtractMetadataParam{
+					Data: BuildJpeg(4, 2, BuildTiff(6, true)),
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Format).To(Equal("jpeg"))
+				Expect(res.Width).To(Equal(4))
+				Expect(res.Height).To(Equal(2))
+				Expect(res.Orientation).To(Equal(6))
+				Expect(res.Exif["gps_latitude"]).To(Equal("-6.500000"))
+			})
+		})
+	})
+
+	Context("StripMetadata function", Label("unit"), func() {
+		var (
+			ctx context.Context
+			ip  imaging.ImageProcessor
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ip, _ = imaging.NewImageProcessor(imaging.NewImageProcessorParam{})
+		})
+
+		When("data is not an image", func() {
+			It("should return error", func() {
+				res, err := ip.StripMetadata(ctx, imaging.StripMetadataParam{
+					Data: []byte("plain text"),
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(image.ErrFormat))
+			})
+		})
+
+		When("jpeg orientation is not applied", func() {
+			It("should remove exif segment", func() {
+				data := BuildJpeg(4, 2, BuildTiff(6, true))
+				res, err := ip.StripMetadata(ctx, imaging.StripMetadataParam{
+					Data: data,
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Width).To(Equal(4))
+				Expect(res.Height).To(Equal(2))
+				Expect(res.Data).To(Equal(BuildJpeg(4, 2, nil)))
+			})
+		})
+
+		When("jpeg orientation is applied", func() {
+			It("should rotate and remove exif", func() {
+				data := BuildJpeg(4, 2, BuildTiff(6, true))
+				res, err := ip.StripMetadata(ctx, imaging.StripMetadataParam{
+					Data:             data,
+					ApplyOrientation: true,
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Width).To(Equal(2))
+				Expect(res.Height).To(Equal(4))
+
+				exif, err := imaging.ParseJpegExif(res.Data)
+				Expect(exif).To(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("jpeg exif is corrupt", func() {
+			It("should remove exif segment without applying the orientation", func() {
+				data := BuildJpeg(4, 2, []byte("XX*\x00\x08\x00\x00\x00"))
+				res, err := ip.StripMetadata(ctx, imaging.StripMetadataParam{
+					Data:             data,
+					ApplyOrientation: true,
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Width).To(Equal(4))
+				Expect(res.Height).To(Equal(2))
+				Expect(res.Data).To(Equal(BuildJpeg(4, 2, nil)))
+			})
+		})
+
+		When("jpeg dimension exceeds the limit", func() {
+			It("should return error", func() {
+				ip, _ = imaging.NewImageProcessor(imaging.NewImageProcessorParam{
+					MaxPixels: 7,
+				})
+				data := BuildJpeg(4, 2, BuildTiff(6, true))
+				res, err := ip.StripMetadata(ctx, imaging.StripMetadataParam{
+					Data:             data,
+					ApplyOrientation: true,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(imaging.ErrorImageTooLarge))
+			})
+		})
+
+		When("jpeg orientation is normal", func() {
+			It("should not re-encode the image", func() {
+				data := BuildJpeg(4, 2, BuildTiff(1, false))
+				res, err := ip.StripMetadata(ctx, imaging.StripMetadataParam{
+					Data:             data,
+					ApplyOrientation: true,
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Data).To(Equal(BuildJpeg(4, 2, nil)))
+			})
+		})
+
+		When("image is a png", func() {
+			It("should return result", func() {
+				buff := new(bytes.Buffer)
+				png.Encode(buff, image.NewRGBA(image.Rect(0, 0, 3, 5)))
+
+				res, err := ip.StripMetadata(ctx, imaging.StripMetadataParam{
+					Data: buff.Bytes(),
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(&imaging.StripMetadataResult{
+					Data:   buff.Bytes(),
+					Width:  3,
+					Height: 5,
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+var (
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	pngXmpKey    = []byte("XML:com.adobe.xmp\x00")
+	pngExifKey   = []byte("Raw profile type exif\x00")
+)
+
+type jpegSegment struct {
+	marker  byte
+	start   int
+	end     int
+	payload []byte
+}
+
+func walkJpegSegments(data []byte, fn func(marker byte, payload []byte) bool) error {
+	segments, _, err := readJpegSegments(data)
+	if err != nil {
+		return err
+	}
+	for _, s := range segments {
+		if !fn(s.marker, s.payload) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func readJpegSegments(data []byte) ([]jpegSegment, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, fmt.Errorf("invalid jpeg data")
+	}
+
+	segments := []jpegSegment{}
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, 0, fmt.Errorf("invalid jpeg marker")
+		}
+
+		start := i
+		// skip marker fill bytes
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, 0, fmt.Errorf("invalid jpeg marker")
+		}
+		marker := data[i]
+		i++
+
+		// start of scan or end of image, the rest is entropy coded data
+		if marker == 0xDA || marker == 0xD9 {
+			return segments, start, nil
+		}
+
+		// standalone marker without length
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			segments = append(segments, jpegSegment{
+				marker: marker,
+				start:  start,
+				end:    i,
+			})
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, 0, fmt.Errorf("invalid jpeg segment")
+		}
+		length := int(binary.BigEndian.Uint16(data[i : i+2]))
+		if length < 2 || i+length > len(data) {
+			return nil, 0, fmt.Errorf("invalid jpeg segment")
+		}
+
+		segments = append(segments, jpegSegment{
+			marker:  marker,
+			start:   start,
+			end:     i + length,
+			payload: data[i+2 : i+length],
+		})
+		i += length
+	}
+	return nil, 0, fmt.Errorf("invalid jpeg data")
+}
+
+func StripJpegMetadata(data []byte) ([]byte, error) {
+	segments, scanStart, err := readJpegSegments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	res := bytes.NewBuffer(make([]byte, 0, len(data)))
+	res.Write(data[0:2])
+	for _, s := range segments {
+		if s.marker == 0xE1 {
+			if bytes.HasPrefix(s.payload, exifHeader) || bytes.HasPrefix(s.payload, xmpHeader) {
+				continue
+			}
+		}
+		res.Write(data[s.start:s.end])
+	}
+	res.Write(data[scanStart:])
+	return res.Bytes(), nil
+}
+
+func StripPngMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("invalid png data")
+	}
+
+	res := bytes.NewBuffer(make([]byte, 0, len(data)))
+	res.Write(pngSignature)
+
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("invalid png chunk")
+		}
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("invalid png chunk")
+		}
+
+		payload := data[i+8 : i+8+length]
+		skip := chunkType == "eXIf"
+		if chunkType == "iTXt" || chunkType == "tEXt" || chunkType == "zTXt" {
+			skip = bytes.HasPrefix(payload, pngXmpKey) || bytes.HasPrefix(payload, pngExifKey)
+		}
+		if !skip {
+			res.Write(data[i:end])
+		}
+
+		i = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+	return res.Bytes(), nil
+}
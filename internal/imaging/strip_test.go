@@ -0,0 +1,104 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/go-seidon/local/internal/imaging"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func pngChunk(typ string, data []byte) []byte {
+	b := new(bytes.Buffer)
+	binary.Write(b, binary.BigEndian, uint32(len(data)))
+	b.WriteString(typ)
+	b.Write(data)
+	binary.Write(b, binary.BigEndian, crc32.ChecksumIEEE(append([]byte(typ), data...)))
+	return b.Bytes()
+}
+
+var _ = Describe("Strip Package", func() {
+	Context("StripJpegMetadata function", Label("unit"), func() {
+		When("data is not a jpeg", func() {
+			It("should return error", func() {
+				res, err := imaging.StripJpegMetadata([]byte("plain"))
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid jpeg data"))
+			})
+		})
+
+		When("segment length is invalid", func() {
+			It("should return error", func() {
+				res, err := imaging.StripJpegMetadata([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid jpeg segment"))
+			})
+		})
+
+		When("jpeg does not have exif", func() {
+			It("should return identical data", func() {
+				data := BuildJpeg(4, 2, nil)
+				res, err := imaging.StripJpegMetadata(data)
+
+				Expect(res).To(Equal(data))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("jpeg has exif", func() {
+			It("should remove exif segment", func() {
+				res, err := imaging.StripJpegMetadata(BuildJpeg(4, 2, BuildTiff(3, true)))
+
+				Expect(res).To(Equal(BuildJpeg(4, 2, nil)))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("StripPngMetadata function", Label("unit"), func() {
+		When("data is not a png", func() {
+			It("should return error", func() {
+				res, err := imaging.StripPngMetadata([]byte("plain"))
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid png data"))
+			})
+		})
+
+		When("png has metadata chunk", func() {
+			It("should remove metadata chunk", func() {
+				signature := []byte("\x89PNG\r\n\x1a\n")
+				ihdr := pngChunk("IHDR", make([]byte, 13))
+				text := pngChunk("tEXt", []byte("Title\x00dolphin"))
+				iend := pngChunk("IEND", nil)
+
+				data := bytes.Join([][]byte{
+					signature, ihdr,
+					pngChunk("eXIf", BuildTiff(1, true)),
+					pngChunk("iTXt", []byte("XML:com.adobe.xmp\x00<x/>")),
+					text, iend,
+				}, nil)
+
+				res, err := imaging.StripPngMetadata(data)
+
+				Expect(res).To(Equal(bytes.Join([][]byte{signature, ihdr, text, iend}, nil)))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("chunk is truncated", func() {
+			It("should return error", func() {
+				data := append([]byte("\x89PNG\r\n\x1a\n"), 0, 0, 0, 10, 'I')
+
+				res, err := imaging.StripPngMetadata(data)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid png chunk"))
+			})
+		})
+	})
+})
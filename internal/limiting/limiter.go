@@ -0,0 +1,335 @@
+package limiting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+const (
+	DEFAULT_BURST_PERIOD       = time.Second
+	DEFAULT_POLICY_TTL         = time.Minute
+	DEFAULT_POLICY_MAX_ENTRIES = 100000
+)
+
+// requests and transferred bytes are limited per client with token buckets,
+// the transferred bytes are only known after the content is transferred,
+// so the byte bucket is left in debt and the next transfer is rejected until it is repaid
+type Limiter interface {
+	AllowRequest(ctx context.Context, p AllowRequestParam) (*AllowRequestResult, error)
+	AllowTransfer(ctx context.Context, p AllowTransferParam) (*AllowTransferResult, error)
+	RecordTransfer(ctx context.Context, p RecordTransferParam) error
+	Acquire(ctx context.Context, p AcquireParam) (*AcquireResult, error)
+	Release(ctx context.Context, p ReleaseParam) error
+}
+
+type AllowRequestParam struct {
+	ClientId string
+}
+
+type AllowRequestResult struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+type AllowTransferParam struct {
+	ClientId string
+}
+
+type AllowTransferResult struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+type RecordTransferParam struct {
+	ClientId string
+	Size     int64
+}
+
+// the in-flight request is only tracked per instance,
+// the stream is long lived so it is counted toward its own limit
+type AcquireParam struct {
+	ClientId string
+	Stream   bool
+}
+
+type AcquireResult struct {
+	Allowed bool
+	Limit   int64
+}
+
+// must be called once the acquired request is finished
+type ReleaseParam struct {
+	ClientId string
+	Stream   bool
+}
+
+type policy struct {
+	requestRate int64
+	byteRate    int64
+	expiresAt   time.Time
+}
+
+type limiter struct {
+	mu          sync.Mutex
+	store       Store
+	oAuthRepo   repository.OAuthRepository
+	requestRate int64
+	byteRate    int64
+	burstPeriod time.Duration
+	policyTTL   time.Duration
+	maxEntries  int
+	clock       datetime.Clock
+	policies    map[string]*policy
+	maxInFlight int64
+	inFlight    map[string]int64
+	maxStream   int64
+	streams     map[string]int64
+}
+
+func (l *limiter) AllowRequest(ctx context.Context, p AllowRequestParam) (*AllowRequestResult, error) {
+	pol, err := l.resolve(ctx, p.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	if pol.requestRate == 0 {
+		return &AllowRequestResult{Allowed: true}, nil
+	}
+
+	burst := l.burst(pol.requestRate)
+	tRes, err := l.store.Take(ctx, TakeParam{
+		Key:   "request:" + p.ClientId,
+		Rate:  float64(pol.requestRate),
+		Burst: burst,
+		Cost:  1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &AllowRequestResult{
+		Allowed:    tRes.Allowed,
+		Limit:      int64(burst),
+		Remaining:  remaining(tRes.Remaining),
+		RetryAfter: tRes.RetryAfter,
+		ResetAfter: tRes.ResetAfter,
+	}
+	return res, nil
+}
+
+func (l *limiter) AllowTransfer(ctx context.Context, p AllowTransferParam) (*AllowTransferResult, error) {
+	pol, err := l.resolve(ctx, p.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	if pol.byteRate == 0 {
+		return &AllowTransferResult{Allowed: true}, nil
+	}
+
+	burst := l.burst(pol.byteRate)
+	tRes, err := l.store.Take(ctx, TakeParam{
+		Key:   "byte:" + p.ClientId,
+		Rate:  float64(pol.byteRate),
+		Burst: burst,
+		Cost:  0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &AllowTransferResult{
+		Allowed:    tRes.Allowed,
+		Limit:      int64(burst),
+		Remaining:  remaining(tRes.Remaining),
+		RetryAfter: tRes.RetryAfter,
+		ResetAfter: tRes.ResetAfter,
+	}
+	return res, nil
+}
+
+func (l *limiter) RecordTransfer(ctx context.Context, p RecordTransferParam) error {
+	if p.Size <= 0 {
+		return nil
+	}
+
+	pol, err := l.resolve(ctx, p.ClientId)
+	if err != nil {
+		return err
+	}
+	if pol.byteRate == 0 {
+		return nil
+	}
+
+	_, err = l.store.Take(ctx, TakeParam{
+		Key:       "byte:" + p.ClientId,
+		Rate:      float64(pol.byteRate),
+		Burst:     l.burst(pol.byteRate),
+		Cost:      float64(p.Size),
+		AllowDebt: true,
+	})
+	return err
+}
+
+func (l *limiter) Acquire(ctx context.Context, p AcquireParam) (*AcquireResult, error) {
+	limit, acquired := l.concurrency(p.Stream)
+	if limit == 0 {
+		return &AcquireResult{Allowed: true}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if acquired[p.ClientId] >= limit {
+		return &AcquireResult{Allowed: false, Limit: limit}, nil
+	}
+	acquired[p.ClientId]++
+	return &AcquireResult{Allowed: true, Limit: limit}, nil
+}
+
+func (l *limiter) Release(ctx context.Context, p ReleaseParam) error {
+	limit, acquired := l.concurrency(p.Stream)
+	if limit == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	acquired[p.ClientId]--
+	if acquired[p.ClientId] <= 0 {
+		delete(acquired, p.ClientId)
+	}
+	return nil
+}
+
+func (l *limiter) concurrency(stream bool) (int64, map[string]int64) {
+	if stream {
+		return l.maxStream, l.streams
+	}
+	return l.maxInFlight, l.inFlight
+}
+
+func (l *limiter) resolve(ctx context.Context, clientId string) (*policy, error) {
+	currentTs := l.clock.Now()
+
+	l.mu.Lock()
+	pol, ok := l.policies[clientId]
+	l.mu.Unlock()
+	if ok && currentTs.Before(pol.expiresAt) {
+		return pol, nil
+	}
+
+	pol = &policy{
+		requestRate: l.requestRate,
+		byteRate:    l.byteRate,
+		expiresAt:   currentTs.Add(l.policyTTL),
+	}
+	override, err := l.oAuthRepo.FindClientRateLimit(ctx, repository.FindClientRateLimitParam{
+		ClientId: clientId,
+	})
+	if err != nil && !errors.Is(err, repository.ErrorRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		if override.RequestRate != nil {
+			pol.requestRate = *override.RequestRate
+		}
+		if override.ByteRate != nil {
+			pol.byteRate = *override.ByteRate
+		}
+	}
+
+	l.mu.Lock()
+	if len(l.policies) >= l.maxEntries {
+		for key, cached := range l.policies {
+			if !currentTs.Before(cached.expiresAt) {
+				delete(l.policies, key)
+			}
+		}
+	}
+	l.policies[clientId] = pol
+	l.mu.Unlock()
+	return pol, nil
+}
+
+func (l *limiter) burst(rate int64) float64 {
+	return math.Max(1, math.Floor(float64(rate)*l.burstPeriod.Seconds()))
+}
+
+func remaining(tokens float64) int64 {
+	if tokens <= 0 {
+		return 0
+	}
+	return int64(math.Floor(tokens))
+}
+
+type NewLimiterParam struct {
+	Store       Store
+	OAuthRepo   repository.OAuthRepository
+	RequestRate int64
+	ByteRate    int64
+	MaxInFlight int64
+	MaxStream   int64
+	BurstPeriod time.Duration
+	PolicyTTL   time.Duration
+	MaxEntries  int
+	Clock       datetime.Clock
+}
+
+func NewLimiter(p NewLimiterParam) (*limiter, error) {
+	if p.Store == nil {
+		return nil, fmt.Errorf("store is not specified")
+	}
+	if p.OAuthRepo == nil {
+		return nil, fmt.Errorf("oauth repo is not specified")
+	}
+	if p.RequestRate < 0 || p.ByteRate < 0 || p.MaxInFlight < 0 || p.MaxStream < 0 || p.BurstPeriod < 0 || p.PolicyTTL < 0 || p.MaxEntries < 0 {
+		return nil, fmt.Errorf("invalid limiter parameter")
+	}
+
+	burstPeriod := DEFAULT_BURST_PERIOD
+	if p.BurstPeriod > 0 {
+		burstPeriod = p.BurstPeriod
+	}
+	policyTTL := DEFAULT_POLICY_TTL
+	if p.PolicyTTL > 0 {
+		policyTTL = p.PolicyTTL
+	}
+	maxEntries := DEFAULT_POLICY_MAX_ENTRIES
+	if p.MaxEntries > 0 {
+		maxEntries = p.MaxEntries
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	l := &limiter{
+		store:       p.Store,
+		oAuthRepo:   p.OAuthRepo,
+		requestRate: p.RequestRate,
+		byteRate:    p.ByteRate,
+		burstPeriod: burstPeriod,
+		policyTTL:   policyTTL,
+		maxEntries:  maxEntries,
+		clock:       clock,
+		policies:    map[string]*policy{},
+		maxInFlight: p.MaxInFlight,
+		inFlight:    map[string]int64{},
+		maxStream:   p.MaxStream,
+		streams:     map[string]int64{},
+	}
+	return l, nil
+}
@@ -0,0 +1,491 @@
+package limiting_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/limiting"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter Package", func() {
+	Context("NewLimiter function", Label("unit"), func() {
+		var (
+			p limiting.NewLimiterParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = limiting.NewLimiterParam{
+				Store:     mock.NewMockStore(ctrl),
+				OAuthRepo: mock.NewMockOAuthRepository(ctrl),
+			}
+		})
+
+		When("store is not specified", func() {
+			It("should return error", func() {
+				p.Store = nil
+				res, err := limiting.NewLimiter(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("store is not specified")))
+			})
+		})
+
+		When("oauth repo is not specified", func() {
+			It("should return error", func() {
+				p.OAuthRepo = nil
+				res, err := limiting.NewLimiter(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("oauth repo is not specified")))
+			})
+		})
+
+		When("rate is invalid", func() {
+			It("should return error", func() {
+				p.RequestRate = -1
+				res, err := limiting.NewLimiter(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid limiter parameter")))
+			})
+		})
+
+		When("optional parameter is not specified", func() {
+			It("should return result", func() {
+				res, err := limiting.NewLimiter(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("AllowRequest function", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			clock      *mock.MockClock
+			store      *mock.MockStore
+			oAuthRepo  *mock.MockOAuthRepository
+			limiter    limiting.Limiter
+			p          limiting.AllowRequestParam
+			findParam  repository.FindClientRateLimitParam
+			takeParam  limiting.TakeParam
+			takeResult *limiting.TakeResult
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			clock = mock.NewMockClock(ctrl)
+			store = mock.NewMockStore(ctrl)
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			limiter, _ = limiting.NewLimiter(limiting.NewLimiterParam{
+				Store:       store,
+				OAuthRepo:   oAuthRepo,
+				RequestRate: 10,
+				BurstPeriod: 2 * time.Second,
+				Clock:       clock,
+			})
+			p = limiting.AllowRequestParam{
+				ClientId: "client-id",
+			}
+			findParam = repository.FindClientRateLimitParam{
+				ClientId: "client-id",
+			}
+			takeParam = limiting.TakeParam{
+				Key:   "request:client-id",
+				Rate:  10,
+				Burst: 20,
+				Cost:  1,
+			}
+			takeResult = &limiting.TakeResult{
+				Allowed:    true,
+				Remaining:  19,
+				ResetAfter: 100 * time.Millisecond,
+			}
+			clock.
+				EXPECT().
+				Now().
+				Return(currentTs).
+				AnyTimes()
+		})
+
+		When("failed find rate limit", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClientRateLimit(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := limiter.AllowRequest(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should apply the default limit", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClientRateLimit(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+				store.
+					EXPECT().
+					Take(gomock.Eq(ctx), gomock.Eq(takeParam)).
+					Return(takeResult, nil).
+					Times(1)
+
+				res, err := limiter.AllowRequest(ctx, p)
+
+				Expect(res).To(Equal(&limiting.AllowRequestResult{
+					Allowed:    true,
+					Limit:      20,
+					Remaining:  19,
+					ResetAfter: 100 * time.Millisecond,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("client is unlimited", func() {
+			It("should allow without taking", func() {
+				requestRate := int64(0)
+				oAuthRepo.
+					EXPECT().
+					FindClientRateLimit(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(&repository.FindClientRateLimitResult{
+						ClientId:    "client-id",
+						RequestRate: &requestRate,
+					}, nil).
+					Times(1)
+
+				res, err := limiter.AllowRequest(ctx, p)
+
+				Expect(res).To(Equal(&limiting.AllowRequestResult{
+					Allowed: true,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed take token", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClientRateLimit(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(&repository.FindClientRateLimitResult{ClientId: "client-id"}, nil).
+					Times(1)
+				store.
+					EXPECT().
+					Take(gomock.Eq(ctx), gomock.Eq(takeParam)).
+					Return(nil, fmt.Errorf("store error")).
+					Times(1)
+
+				res, err := limiter.AllowRequest(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("store error")))
+			})
+		})
+
+		When("rate is overridden", func() {
+			It("should apply the override and cache it", func() {
+				requestRate := int64(1)
+				oAuthRepo.
+					EXPECT().
+					FindClientRateLimit(gomock.Eq(ctx), gomock.Eq(findParam)).
+					Return(&repository.FindClientRateLimitResult{
+						ClientId:    "client-id",
+						RequestRate: &requestRate,
+					}, nil).
+					Times(1)
+				takeParam.Rate = 1
+				takeParam.Burst = 2
+				store.
+					EXPECT().
+					Take(gomock.Eq(ctx), gomock.Eq(takeParam)).
+					Return(&limiting.TakeResult{
+						Allowed:    false,
+						Remaining:  0.5,
+						RetryAfter: 500 * time.Millisecond,
+						ResetAfter: 1500 * time.Millisecond,
+					}, nil).
+					Times(2)
+
+				limiter.AllowRequest(ctx, p)
+				res, err := limiter.AllowRequest(ctx, p)
+
+				Expect(res).To(Equal(&limiting.AllowRequestResult{
+					Allowed:    false,
+					Limit:      2,
+					Remaining:  0,
+					RetryAfter: 500 * time.Millisecond,
+					ResetAfter: 1500 * time.Millisecond,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("AllowTransfer function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			store     *mock.MockStore
+			oAuthRepo *mock.MockOAuthRepository
+			limiter   limiting.Limiter
+			p         limiting.AllowTransferParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			store = mock.NewMockStore(ctrl)
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			limiter, _ = limiting.NewLimiter(limiting.NewLimiterParam{
+				Store:     store,
+				OAuthRepo: oAuthRepo,
+				ByteRate:  1024,
+				Clock:     clock,
+			})
+			p = limiting.AllowTransferParam{
+				ClientId: "client-id",
+			}
+			clock.
+				EXPECT().
+				Now().
+				Return(time.Unix(1660000000, 0)).
+				AnyTimes()
+			oAuthRepo.
+				EXPECT().
+				FindClientRateLimit(gomock.Eq(ctx), gomock.Any()).
+				Return(&repository.FindClientRateLimitResult{ClientId: "client-id"}, nil).
+				Times(1)
+		})
+
+		When("byte bucket is in debt", func() {
+			It("should return not allowed", func() {
+				store.
+					EXPECT().
+					Take(gomock.Eq(ctx), gomock.Eq(limiting.TakeParam{
+						Key:   "byte:client-id",
+						Rate:  1024,
+						Burst: 1024,
+					})).
+					Return(&limiting.TakeResult{
+						Allowed:    false,
+						Remaining:  -2048,
+						RetryAfter: 2 * time.Second,
+						ResetAfter: 3 * time.Second,
+					}, nil).
+					Times(1)
+
+				res, err := limiter.AllowTransfer(ctx, p)
+
+				Expect(res).To(Equal(&limiting.AllowTransferResult{
+					Allowed:    false,
+					Limit:      1024,
+					Remaining:  0,
+					RetryAfter: 2 * time.Second,
+					ResetAfter: 3 * time.Second,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RecordTransfer function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			store     *mock.MockStore
+			oAuthRepo *mock.MockOAuthRepository
+			limiter   limiting.Limiter
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			store = mock.NewMockStore(ctrl)
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			limiter, _ = limiting.NewLimiter(limiting.NewLimiterParam{
+				Store:     store,
+				OAuthRepo: oAuthRepo,
+				ByteRate:  1024,
+				Clock:     clock,
+			})
+			clock.
+				EXPECT().
+				Now().
+				Return(time.Unix(1660000000, 0)).
+				AnyTimes()
+		})
+
+		When("nothing is transferred", func() {
+			It("should not take", func() {
+				err := limiter.RecordTransfer(ctx, limiting.RecordTransferParam{
+					ClientId: "client-id",
+				})
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("content is transferred", func() {
+			It("should take with debt", func() {
+				oAuthRepo.
+					EXPECT().
+					FindClientRateLimit(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+				store.
+					EXPECT().
+					Take(gomock.Eq(ctx), gomock.Eq(limiting.TakeParam{
+						Key:       "byte:client-id",
+						Rate:      1024,
+						Burst:     1024,
+						Cost:      4096,
+						AllowDebt: true,
+					})).
+					Return(&limiting.TakeResult{Remaining: -3072}, nil).
+					Times(1)
+
+				err := limiter.RecordTransfer(ctx, limiting.RecordTransferParam{
+					ClientId: "client-id",
+					Size:     4096,
+				})
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("Acquire function", Label("unit"), func() {
+		var (
+			ctx     context.Context
+			limiter limiting.Limiter
+			p       limiting.AcquireParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			limiter, _ = limiting.NewLimiter(limiting.NewLimiterParam{
+				Store:       mock.NewMockStore(ctrl),
+				OAuthRepo:   mock.NewMockOAuthRepository(ctrl),
+				MaxInFlight: 2,
+			})
+			p = limiting.AcquireParam{
+				ClientId: "client-id",
+			}
+		})
+
+		When("in-flight request is below the limit", func() {
+			It("should return allowed", func() {
+				limiter.Acquire(ctx, p)
+				res, err := limiter.Acquire(ctx, p)
+
+				Expect(res).To(Equal(&limiting.AcquireResult{
+					Allowed: true,
+					Limit:   2,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("in-flight request reaches the limit", func() {
+			It("should return not allowed", func() {
+				limiter.Acquire(ctx, p)
+				limiter.Acquire(ctx, p)
+				res, err := limiter.Acquire(ctx, p)
+
+				Expect(res).To(Equal(&limiting.AcquireResult{
+					Allowed: false,
+					Limit:   2,
+				}))
+				Expect(err).To(BeNil())
+
+				oRes, _ := limiter.Acquire(ctx, limiting.AcquireParam{
+					ClientId: "other-client-id",
+				})
+
+				Expect(oRes.Allowed).To(BeTrue())
+			})
+		})
+
+		When("in-flight request is released", func() {
+			It("should return allowed", func() {
+				limiter.Acquire(ctx, p)
+				limiter.Acquire(ctx, p)
+				err := limiter.Release(ctx, limiting.ReleaseParam{
+					ClientId: "client-id",
+				})
+				res, _ := limiter.Acquire(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Allowed).To(BeTrue())
+			})
+		})
+
+		When("stream is acquired", func() {
+			It("should be limited separately from the in-flight request", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				limiter, _ = limiting.NewLimiter(limiting.NewLimiterParam{
+					Store:       mock.NewMockStore(ctrl),
+					OAuthRepo:   mock.NewMockOAuthRepository(ctrl),
+					MaxInFlight: 2,
+					MaxStream:   1,
+				})
+				sp := limiting.AcquireParam{
+					ClientId: "client-id",
+					Stream:   true,
+				}
+				limiter.Acquire(ctx, sp)
+				sRes, _ := limiter.Acquire(ctx, sp)
+				res, _ := limiter.Acquire(ctx, p)
+
+				Expect(sRes).To(Equal(&limiting.AcquireResult{
+					Allowed: false,
+					Limit:   1,
+				}))
+				Expect(res.Allowed).To(BeTrue())
+
+				limiter.Release(ctx, limiting.ReleaseParam{
+					ClientId: "client-id",
+					Stream:   true,
+				})
+				sRes, _ = limiter.Acquire(ctx, sp)
+
+				Expect(sRes.Allowed).To(BeTrue())
+			})
+		})
+
+		When("concurrency is unlimited", func() {
+			It("should return allowed", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				limiter, _ = limiting.NewLimiter(limiting.NewLimiterParam{
+					Store:     mock.NewMockStore(ctrl),
+					OAuthRepo: mock.NewMockOAuthRepository(ctrl),
+				})
+				res, err := limiter.Acquire(ctx, p)
+
+				Expect(res).To(Equal(&limiting.AcquireResult{
+					Allowed: true,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package limiting_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLimiting(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Limiting Package")
+}
@@ -0,0 +1,135 @@
+package limiting
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+)
+
+const (
+	DEFAULT_STORE_MAX_ENTRIES = 100000
+)
+
+type Store interface {
+	Take(ctx context.Context, p TakeParam) (*TakeResult, error)
+}
+
+type TakeParam struct {
+	Key   string
+	Rate  float64
+	Burst float64
+	Cost  float64
+	// take the tokens even when the bucket is insufficient and leave the bucket in debt,
+	// used when the cost is only known after the work is done, e.g: transferred bytes
+	AllowDebt bool
+}
+
+type TakeResult struct {
+	Allowed    bool
+	Remaining  float64
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+type memoryStore struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	maxEntries int
+	clock      datetime.Clock
+}
+
+func (s *memoryStore) Take(ctx context.Context, p TakeParam) (*TakeResult, error) {
+	if p.Rate <= 0 || p.Burst <= 0 || p.Cost < 0 {
+		return nil, fmt.Errorf("invalid take parameter")
+	}
+
+	currentTs := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[p.Key]
+	if !ok {
+		if len(s.buckets) >= s.maxEntries {
+			s.prune(currentTs)
+		}
+		b = &bucket{tokens: p.Burst, updatedAt: currentTs}
+		s.buckets[p.Key] = b
+	}
+
+	elapsed := currentTs.Sub(b.updatedAt).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(p.Burst, b.tokens+elapsed*p.Rate)
+	}
+	b.updatedAt = currentTs
+
+	allowed := b.tokens >= p.Cost
+	if allowed || p.AllowDebt {
+		b.tokens -= p.Cost
+	}
+	b.fullAt = currentTs.Add(refillDuration(p.Burst-b.tokens, p.Rate))
+
+	res := &TakeResult{
+		Allowed:    allowed,
+		Remaining:  b.tokens,
+		ResetAfter: b.fullAt.Sub(currentTs),
+	}
+	if !allowed {
+		res.RetryAfter = refillDuration(p.Cost-b.tokens, p.Rate)
+	}
+	return res, nil
+}
+
+// full bucket is identical to the missing one, caller must hold the lock
+func (s *memoryStore) prune(currentTs time.Time) {
+	for key, b := range s.buckets {
+		if !currentTs.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func refillDuration(tokens float64, rate float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens / rate * float64(time.Second)))
+}
+
+type NewMemoryStoreParam struct {
+	MaxEntries int
+	Clock      datetime.Clock
+}
+
+// the limit is only enforced per instance
+func NewMemoryStore(p NewMemoryStoreParam) (*memoryStore, error) {
+	if p.MaxEntries < 0 {
+		return nil, fmt.Errorf("invalid store parameter")
+	}
+
+	maxEntries := DEFAULT_STORE_MAX_ENTRIES
+	if p.MaxEntries > 0 {
+		maxEntries = p.MaxEntries
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	s := &memoryStore{
+		buckets:    map[string]*bucket{},
+		maxEntries: maxEntries,
+		clock:      clock,
+	}
+	return s, nil
+}
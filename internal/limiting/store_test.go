@@ -0,0 +1,171 @@
+package limiting_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/limiting"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store Package", func() {
+	Context("NewMemoryStore function", Label("unit"), func() {
+		When("max entries is invalid", func() {
+			It("should return error", func() {
+				res, err := limiting.NewMemoryStore(limiting.NewMemoryStoreParam{
+					MaxEntries: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid store parameter")))
+			})
+		})
+
+		When("optional parameter is not specified", func() {
+			It("should return result", func() {
+				res, err := limiting.NewMemoryStore(limiting.NewMemoryStoreParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("Take function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			clock     *mock.MockClock
+			store     limiting.Store
+			p         limiting.TakeParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			clock = mock.NewMockClock(ctrl)
+			store, _ = limiting.NewMemoryStore(limiting.NewMemoryStoreParam{
+				Clock: clock,
+			})
+			p = limiting.TakeParam{
+				Key:   "request:client-id",
+				Rate:  2,
+				Burst: 2,
+				Cost:  1,
+			}
+		})
+
+		When("parameter is invalid", func() {
+			It("should return error", func() {
+				p.Rate = 0
+				res, err := store.Take(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid take parameter")))
+			})
+		})
+
+		When("bucket is new", func() {
+			It("should take from the full bucket", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(1)
+
+				res, err := store.Take(ctx, p)
+
+				Expect(res).To(Equal(&limiting.TakeResult{
+					Allowed:    true,
+					Remaining:  1,
+					ResetAfter: 500 * time.Millisecond,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("bucket is empty", func() {
+			It("should return not allowed", func() {
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(3)
+
+				store.Take(ctx, p)
+				store.Take(ctx, p)
+				res, err := store.Take(ctx, p)
+
+				Expect(res).To(Equal(&limiting.TakeResult{
+					Allowed:    false,
+					Remaining:  0,
+					RetryAfter: 500 * time.Millisecond,
+					ResetAfter: time.Second,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("bucket is refilled", func() {
+			It("should not exceed the burst", func() {
+				gomock.InOrder(
+					clock.EXPECT().Now().Return(currentTs).Times(2),
+					clock.EXPECT().Now().Return(currentTs.Add(time.Hour)),
+				)
+
+				store.Take(ctx, p)
+				store.Take(ctx, p)
+				res, err := store.Take(ctx, p)
+
+				Expect(res.Allowed).To(BeTrue())
+				Expect(res.Remaining).To(Equal(float64(1)))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("debt is allowed", func() {
+			It("should reject the next take until the debt is repaid", func() {
+				gomock.InOrder(
+					clock.EXPECT().Now().Return(currentTs).Times(2),
+					clock.EXPECT().Now().Return(currentTs.Add(2*time.Second)),
+				)
+
+				dRes, err := store.Take(ctx, limiting.TakeParam{
+					Key:       "byte:client-id",
+					Rate:      100,
+					Burst:     100,
+					Cost:      300,
+					AllowDebt: true,
+				})
+
+				Expect(dRes.Allowed).To(BeFalse())
+				Expect(dRes.Remaining).To(Equal(float64(-200)))
+				Expect(err).To(BeNil())
+
+				cRes, err := store.Take(ctx, limiting.TakeParam{
+					Key:   "byte:client-id",
+					Rate:  100,
+					Burst: 100,
+				})
+
+				Expect(cRes.Allowed).To(BeFalse())
+				Expect(cRes.RetryAfter).To(Equal(2 * time.Second))
+				Expect(err).To(BeNil())
+
+				res, err := store.Take(ctx, limiting.TakeParam{
+					Key:   "byte:client-id",
+					Rate:  100,
+					Burst: 100,
+				})
+
+				Expect(res.Allowed).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
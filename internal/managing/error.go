@@ -0,0 +1,7 @@
+package managing
+
+import "errors"
+
+var (
+	ErrorResourceNotFound = errors.New("resource not found")
+)
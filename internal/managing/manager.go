@@ -0,0 +1,674 @@
+package managing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/hashing"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/text"
+)
+
+const (
+	DEFAULT_LIST_LIMIT   = 100
+	MAX_LIST_LIMIT       = 1000
+	DEFAULT_SECRET_LABEL = "default"
+	MAX_SECRET_LABEL     = 128
+	MAX_ALLOWED_CIDRS    = 20
+)
+
+type ClientManager interface {
+	CreateClient(ctx context.Context, p CreateClientParam) (*CreateClientResult, error)
+	ListClient(ctx context.Context, p ListClientParam) (*ListClientResult, error)
+	DisableClient(ctx context.Context, p DisableClientParam) (*DisableClientResult, error)
+	DeleteClient(ctx context.Context, p DeleteClientParam) (*DeleteClientResult, error)
+	CreateSecret(ctx context.Context, p CreateSecretParam) (*CreateSecretResult, error)
+	RotateSecret(ctx context.Context, p RotateSecretParam) (*RotateSecretResult, error)
+	ListSecret(ctx context.Context, p ListSecretParam) (*ListSecretResult, error)
+	RevokeSecret(ctx context.Context, p RevokeSecretParam) (*RevokeSecretResult, error)
+	UpdateAllowlist(ctx context.Context, p UpdateAllowlistParam) (*UpdateAllowlistResult, error)
+	UpdateRateLimit(ctx context.Context, p UpdateRateLimitParam) (*UpdateRateLimitResult, error)
+}
+
+type CreateClientParam struct {
+	Name         string
+	Scopes       []string
+	AllowedCidrs []string
+}
+
+type CreateClientResult struct {
+	Id           string
+	Name         string
+	ClientId     string
+	ClientSecret string
+	SecretId     string
+	SigningKey   string
+	Scopes       []string
+	AllowedCidrs []string
+	CreatedAt    time.Time
+}
+
+type ListClientParam struct {
+	AfterId string
+	Limit   int
+}
+
+type ListClientResult struct {
+	Items []ClientItem
+}
+
+type ClientItem struct {
+	Id           string
+	Name         string
+	ClientId     string
+	Scopes       []string
+	AllowedCidrs []string
+	DisabledAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type DisableClientParam struct {
+	ClientId string
+}
+
+type DisableClientResult struct {
+	DisabledAt time.Time
+}
+
+type DeleteClientParam struct {
+	ClientId string
+}
+
+type DeleteClientResult struct {
+	DeletedAt time.Time
+}
+
+type CreateSecretParam struct {
+	ClientId  string
+	Label     string
+	ExpiresIn time.Duration
+}
+
+type CreateSecretResult struct {
+	ClientId     string
+	SecretId     string
+	ClientSecret string
+	SigningKey   string
+	Label        string
+	ExpiresAt    *time.Time
+	CreatedAt    time.Time
+}
+
+type RotateSecretParam struct {
+	ClientId    string
+	Label       string
+	ExpiresIn   time.Duration
+	GracePeriod time.Duration
+}
+
+type RotateSecretResult struct {
+	ClientId          string
+	SecretId          string
+	ClientSecret      string
+	SigningKey        string
+	RotatedAt         time.Time
+	PreviousExpiresAt time.Time
+}
+
+type ListSecretParam struct {
+	ClientId string
+}
+
+type ListSecretResult struct {
+	Items []SecretItem
+}
+
+type SecretItem struct {
+	Id         string
+	Label      string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+type RevokeSecretParam struct {
+	ClientId string
+	SecretId string
+}
+
+type RevokeSecretResult struct {
+	RevokedAt time.Time
+}
+
+type UpdateAllowlistParam struct {
+	ClientId     string
+	AllowedCidrs []string
+}
+
+type UpdateAllowlistResult struct {
+	UpdatedAt time.Time
+}
+
+// nil rate resets the client to the default limit, zero rate means unlimited
+type UpdateRateLimitParam struct {
+	ClientId    string
+	RequestRate *int64
+	ByteRate    *int64
+}
+
+type UpdateRateLimitResult struct {
+	UpdatedAt time.Time
+}
+
+type clientManager struct {
+	oAuthRepo  repository.OAuthRepository
+	hasher     hashing.Hasher
+	identifier text.Identifier
+	secret     text.Identifier
+	clock      datetime.Clock
+	cache      auth.CredentialCache
+	signature  auth.SignatureAuth
+	log        logging.Logger
+}
+
+func (m *clientManager) CreateClient(ctx context.Context, p CreateClientParam) (*CreateClientResult, error) {
+	m.log.Debug("In function: CreateClient")
+	defer m.log.Debug("Returning function: CreateClient")
+
+	if strings.TrimSpace(p.Name) == "" {
+		return nil, fmt.Errorf("invalid name parameter")
+	}
+	if len(p.Scopes) == 0 {
+		return nil, fmt.Errorf("invalid scope parameter")
+	}
+	for _, scope := range p.Scopes {
+		if !auth.IsValidScope(scope) {
+			return nil, fmt.Errorf("invalid scope parameter")
+		}
+	}
+	if !isValidAllowlist(p.AllowedCidrs) {
+		return nil, fmt.Errorf("invalid allowed cidrs parameter")
+	}
+
+	id, err := m.identifier.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+	clientId, err := m.identifier.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+	secretId, err := m.identifier.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, hash, err := m.generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	signingKey, sealedKey, err := m.signingKey(clientId, secretId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := m.oAuthRepo.CreateClient(ctx, repository.CreateClientParam{
+		Id:           id,
+		Name:         p.Name,
+		ClientId:     clientId,
+		Scopes:       p.Scopes,
+		AllowedCidrs: p.AllowedCidrs,
+		ClientSecret: hash,
+		SecretId:     secretId,
+		SecretLabel:  DEFAULT_SECRET_LABEL,
+		SigningKey:   sealedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CreateClientResult{
+		Id:           id,
+		Name:         p.Name,
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		SecretId:     secretId,
+		SigningKey:   signingKey,
+		Scopes:       p.Scopes,
+		AllowedCidrs: p.AllowedCidrs,
+		CreatedAt:    client.CreatedAt,
+	}
+	return res, nil
+}
+
+func (m *clientManager) ListClient(ctx context.Context, p ListClientParam) (*ListClientResult, error) {
+	m.log.Debug("In function: ListClient")
+	defer m.log.Debug("Returning function: ListClient")
+
+	if p.Limit < 0 || p.Limit > MAX_LIST_LIMIT {
+		return nil, fmt.Errorf("invalid limit parameter")
+	}
+	limit := DEFAULT_LIST_LIMIT
+	if p.Limit > 0 {
+		limit = p.Limit
+	}
+
+	clients, err := m.oAuthRepo.ListClient(ctx, repository.ListClientParam{
+		AfterId: p.AfterId,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []ClientItem{}
+	for _, client := range clients.Items {
+		items = append(items, ClientItem{
+			Id:           client.Id,
+			Name:         client.Name,
+			ClientId:     client.ClientId,
+			Scopes:       client.Scopes,
+			AllowedCidrs: client.AllowedCidrs,
+			DisabledAt:   client.DisabledAt,
+			CreatedAt:    client.CreatedAt,
+			UpdatedAt:    client.UpdatedAt,
+		})
+	}
+
+	res := &ListClientResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (m *clientManager) DisableClient(ctx context.Context, p DisableClientParam) (*DisableClientResult, error) {
+	m.log.Debug("In function: DisableClient")
+	defer m.log.Debug("Returning function: DisableClient")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+
+	client, err := m.oAuthRepo.DisableClient(ctx, repository.DisableClientParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	m.invalidateClient(p.ClientId)
+
+	res := &DisableClientResult{
+		DisabledAt: client.DisabledAt,
+	}
+	return res, nil
+}
+
+func (m *clientManager) DeleteClient(ctx context.Context, p DeleteClientParam) (*DeleteClientResult, error) {
+	m.log.Debug("In function: DeleteClient")
+	defer m.log.Debug("Returning function: DeleteClient")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+
+	client, err := m.oAuthRepo.DeleteClient(ctx, repository.DeleteClientParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	m.invalidateClient(p.ClientId)
+
+	res := &DeleteClientResult{
+		DeletedAt: client.DeletedAt,
+	}
+	return res, nil
+}
+
+func (m *clientManager) CreateSecret(ctx context.Context, p CreateSecretParam) (*CreateSecretResult, error) {
+	m.log.Debug("In function: CreateSecret")
+	defer m.log.Debug("Returning function: CreateSecret")
+
+	return m.createSecret(ctx, p)
+}
+
+func (m *clientManager) RotateSecret(ctx context.Context, p RotateSecretParam) (*RotateSecretResult, error) {
+	m.log.Debug("In function: RotateSecret")
+	defer m.log.Debug("Returning function: RotateSecret")
+
+	if p.GracePeriod < 0 {
+		return nil, fmt.Errorf("invalid grace period parameter")
+	}
+
+	secret, err := m.createSecret(ctx, CreateSecretParam{
+		ClientId:  p.ClientId,
+		Label:     p.Label,
+		ExpiresIn: p.ExpiresIn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	previousExpiresAt := secret.CreatedAt.Add(p.GracePeriod)
+	_, err = m.oAuthRepo.ExpireClientSecret(ctx, repository.ExpireClientSecretParam{
+		ClientId:  p.ClientId,
+		ExceptId:  secret.SecretId,
+		ExpiresAt: previousExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.invalidateClient(p.ClientId)
+
+	res := &RotateSecretResult{
+		ClientId:          p.ClientId,
+		SecretId:          secret.SecretId,
+		ClientSecret:      secret.ClientSecret,
+		SigningKey:        secret.SigningKey,
+		RotatedAt:         secret.CreatedAt,
+		PreviousExpiresAt: previousExpiresAt,
+	}
+	return res, nil
+}
+
+func (m *clientManager) ListSecret(ctx context.Context, p ListSecretParam) (*ListSecretResult, error) {
+	m.log.Debug("In function: ListSecret")
+	defer m.log.Debug("Returning function: ListSecret")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+
+	secrets, err := m.oAuthRepo.ListClientSecret(ctx, repository.ListClientSecretParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []SecretItem{}
+	for _, secret := range secrets.Items {
+		items = append(items, SecretItem{
+			Id:         secret.Id,
+			Label:      secret.Label,
+			CreatedAt:  secret.CreatedAt,
+			ExpiresAt:  secret.ExpiresAt,
+			LastUsedAt: secret.LastUsedAt,
+		})
+	}
+
+	res := &ListSecretResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (m *clientManager) RevokeSecret(ctx context.Context, p RevokeSecretParam) (*RevokeSecretResult, error) {
+	m.log.Debug("In function: RevokeSecret")
+	defer m.log.Debug("Returning function: RevokeSecret")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if p.SecretId == "" {
+		return nil, fmt.Errorf("invalid secret id parameter")
+	}
+
+	secret, err := m.oAuthRepo.DeleteClientSecret(ctx, repository.DeleteClientSecretParam{
+		ClientId: p.ClientId,
+		SecretId: p.SecretId,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	m.invalidateClient(p.ClientId)
+
+	res := &RevokeSecretResult{
+		RevokedAt: secret.DeletedAt,
+	}
+	return res, nil
+}
+
+func (m *clientManager) createSecret(ctx context.Context, p CreateSecretParam) (*CreateSecretResult, error) {
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if len(p.Label) > MAX_SECRET_LABEL {
+		return nil, fmt.Errorf("invalid label parameter")
+	}
+	if p.ExpiresIn < 0 {
+		return nil, fmt.Errorf("invalid expires in parameter")
+	}
+
+	label := DEFAULT_SECRET_LABEL
+	if strings.TrimSpace(p.Label) != "" {
+		label = strings.TrimSpace(p.Label)
+	}
+
+	var expiresAt *time.Time
+	if p.ExpiresIn > 0 {
+		t := m.clock.Now().Add(p.ExpiresIn)
+		expiresAt = &t
+	}
+
+	secretId, err := m.identifier.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, hash, err := m.generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	signingKey, sealedKey, err := m.signingKey(p.ClientId, secretId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := m.oAuthRepo.CreateClientSecret(ctx, repository.CreateClientSecretParam{
+		Id:         secretId,
+		ClientId:   p.ClientId,
+		Label:      label,
+		Secret:     hash,
+		SigningKey: sealedKey,
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	res := &CreateSecretResult{
+		ClientId:     p.ClientId,
+		SecretId:     secretId,
+		ClientSecret: clientSecret,
+		SigningKey:   signingKey,
+		Label:        label,
+		ExpiresAt:    expiresAt,
+		CreatedAt:    secret.CreatedAt,
+	}
+	return res, nil
+}
+
+// the bearer token issued before the update keeps the previous allowlist until it is expired
+func (m *clientManager) UpdateAllowlist(ctx context.Context, p UpdateAllowlistParam) (*UpdateAllowlistResult, error) {
+	m.log.Debug("In function: UpdateAllowlist")
+	defer m.log.Debug("Returning function: UpdateAllowlist")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if !isValidAllowlist(p.AllowedCidrs) {
+		return nil, fmt.Errorf("invalid allowed cidrs parameter")
+	}
+
+	client, err := m.oAuthRepo.UpdateClientAllowlist(ctx, repository.UpdateClientAllowlistParam{
+		ClientId:     p.ClientId,
+		AllowedCidrs: p.AllowedCidrs,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	m.invalidateClient(p.ClientId)
+
+	res := &UpdateAllowlistResult{
+		UpdatedAt: client.UpdatedAt,
+	}
+	return res, nil
+}
+
+// the rate limiter caches the override, so the updated override is applied after the cache is expired
+func (m *clientManager) UpdateRateLimit(ctx context.Context, p UpdateRateLimitParam) (*UpdateRateLimitResult, error) {
+	m.log.Debug("In function: UpdateRateLimit")
+	defer m.log.Debug("Returning function: UpdateRateLimit")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if p.RequestRate != nil && *p.RequestRate < 0 {
+		return nil, fmt.Errorf("invalid request rate parameter")
+	}
+	if p.ByteRate != nil && *p.ByteRate < 0 {
+		return nil, fmt.Errorf("invalid byte rate parameter")
+	}
+
+	client, err := m.oAuthRepo.UpdateClientRateLimit(ctx, repository.UpdateClientRateLimitParam{
+		ClientId:    p.ClientId,
+		RequestRate: p.RequestRate,
+		ByteRate:    p.ByteRate,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	res := &UpdateRateLimitResult{
+		UpdatedAt: client.UpdatedAt,
+	}
+	return res, nil
+}
+
+func isValidAllowlist(allowedCidrs []string) bool {
+	if len(allowedCidrs) > MAX_ALLOWED_CIDRS {
+		return false
+	}
+	for _, cidr := range allowedCidrs {
+		if !auth.IsValidCidr(cidr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *clientManager) signingKey(clientId, secretId, clientSecret string) (string, string, error) {
+	if m.signature == nil {
+		return "", "", nil
+	}
+	signingKey := auth.DeriveSigningKey(auth.DeriveSigningKeyParam{
+		ClientId:     clientId,
+		SecretId:     secretId,
+		ClientSecret: clientSecret,
+	})
+	sealedKey, err := m.signature.SealSigningKey(auth.SealSigningKeyParam{
+		ClientId:   clientId,
+		SecretId:   secretId,
+		SigningKey: signingKey,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return signingKey, sealedKey, nil
+}
+
+// only the cache of this process is dropped, the other server applies the change after the cache ttl
+func (m *clientManager) invalidateClient(clientId string) {
+	if m.cache == nil {
+		return
+	}
+	m.cache.InvalidateClient(clientId)
+}
+
+func (m *clientManager) generateSecret() (string, string, error) {
+	secret, err := m.secret.GenerateId()
+	if err != nil {
+		return "", "", err
+	}
+	hash, err := m.hasher.Generate(secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, string(hash), nil
+}
+
+type NewClientManagerParam struct {
+	OAuthRepo       repository.OAuthRepository
+	Hasher          hashing.Hasher
+	Identifier      text.Identifier
+	SecretGenerator text.Identifier
+	Clock           datetime.Clock
+	Cache           auth.CredentialCache
+	Signature       auth.SignatureAuth
+	Logger          logging.Logger
+}
+
+func NewClientManager(p NewClientManagerParam) (*clientManager, error) {
+	if p.OAuthRepo == nil {
+		return nil, fmt.Errorf("oauth repo is not specified")
+	}
+	if p.Hasher == nil {
+		return nil, fmt.Errorf("hasher is not specified")
+	}
+	if p.Identifier == nil {
+		return nil, fmt.Errorf("identifier is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+
+	secret := p.SecretGenerator
+	if secret == nil {
+		secret = text.NewRandomSecret(text.DEFAULT_SECRET_SIZE)
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	m := &clientManager{
+		oAuthRepo:  p.OAuthRepo,
+		hasher:     p.Hasher,
+		identifier: p.Identifier,
+		secret:     secret,
+		clock:      clock,
+		cache:      p.Cache,
+		signature:  p.Signature,
+		log:        p.Logger,
+	}
+	return m, nil
+}
@@ -0,0 +1,1757 @@
+package managing_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/managing"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestManaging(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Managing Package")
+}
+
+var _ = Describe("Client Manager Service", func() {
+	Context("NewClientManager function", Label("unit"), func() {
+		var (
+			p managing.NewClientManagerParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = managing.NewClientManagerParam{
+				OAuthRepo:  mock.NewMockOAuthRepository(ctrl),
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := managing.NewClientManager(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("oauth repo is not specified", func() {
+			It("should return error", func() {
+				p.OAuthRepo = nil
+				res, err := managing.NewClientManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("oauth repo is not specified")))
+			})
+		})
+
+		When("hasher is not specified", func() {
+			It("should return error", func() {
+				p.Hasher = nil
+				res, err := managing.NewClientManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("hasher is not specified")))
+			})
+		})
+
+		When("identifier is not specified", func() {
+			It("should return error", func() {
+				p.Identifier = nil
+				res, err := managing.NewClientManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("identifier is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := managing.NewClientManager(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+	})
+
+	Context("CreateClient function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			oAuthRepo   *mock.MockOAuthRepository
+			hasher      *mock.MockHasher
+			identifier  *mock.MockIdentifier
+			secret      *mock.MockIdentifier
+			log         *mock.MockLogger
+			m           managing.ClientManager
+			p           managing.CreateClientParam
+			createParam repository.CreateClientParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			hasher = mock.NewMockHasher(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			secret = mock.NewMockIdentifier(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:       oAuthRepo,
+				Hasher:          hasher,
+				Identifier:      identifier,
+				SecretGenerator: secret,
+				Logger:          log,
+			})
+			p = managing.CreateClientParam{
+				Name:   "frontend",
+				Scopes: []string{"file:read"},
+			}
+			createParam = repository.CreateClientParam{
+				Id:           "mock-id",
+				Name:         "frontend",
+				ClientId:     "mock-client-id",
+				ClientSecret: "hashed-secret",
+				Scopes:       []string{"file:read"},
+				SecretId:     "mock-secret-id",
+				SecretLabel:  "default",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: CreateClient").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: CreateClient").
+				Times(1)
+		})
+
+		When("name is not specified", func() {
+			It("should return error", func() {
+				p.Name = " "
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid name parameter")))
+			})
+		})
+
+		When("scope is not specified", func() {
+			It("should return error", func() {
+				p.Scopes = nil
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid scope parameter")))
+			})
+		})
+
+		When("scope is not supported", func() {
+			It("should return error", func() {
+				p.Scopes = []string{"file:read", "file:*"}
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid scope parameter")))
+			})
+		})
+
+		When("allowed cidr is invalid", func() {
+			It("should return error", func() {
+				p.AllowedCidrs = []string{"10.0.0.0/8", "10.0.0.1"}
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid allowed cidrs parameter")))
+			})
+		})
+
+		When("failed generate id", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("", fmt.Errorf("generate error")).
+					Times(1)
+
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("generate error")))
+			})
+		})
+
+		When("failed generate secret", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-id", nil).
+					Times(3)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("", fmt.Errorf("entropy error")).
+					Times(1)
+
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("entropy error")))
+			})
+		})
+
+		When("failed hash secret", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-id", nil).
+					Times(3)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return(nil, fmt.Errorf("hash error")).
+					Times(1)
+
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("hash error")))
+			})
+		})
+
+		When("failed create client", func() {
+			It("should return error", func() {
+				gomock.InOrder(
+					identifier.EXPECT().GenerateId().Return("mock-id", nil),
+					identifier.EXPECT().GenerateId().Return("mock-client-id", nil),
+					identifier.EXPECT().GenerateId().Return("mock-secret-id", nil),
+				)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClient(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success create client", func() {
+			It("should return plain secret", func() {
+				gomock.InOrder(
+					identifier.EXPECT().GenerateId().Return("mock-id", nil),
+					identifier.EXPECT().GenerateId().Return("mock-client-id", nil),
+					identifier.EXPECT().GenerateId().Return("mock-secret-id", nil),
+				)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClient(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateClientResult{
+						CreatedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.CreateClient(ctx, p)
+
+				Expect(res).To(Equal(&managing.CreateClientResult{
+					Id:           "mock-id",
+					Name:         "frontend",
+					ClientId:     "mock-client-id",
+					ClientSecret: "plain-secret",
+					SecretId:     "mock-secret-id",
+					Scopes:       []string{"file:read"},
+					CreatedAt:    currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListClient function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			oAuthRepo *mock.MockOAuthRepository
+			log       *mock.MockLogger
+			m         managing.ClientManager
+			p         managing.ListClientParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     log,
+			})
+			p = managing.ListClientParam{
+				AfterId: "mock-after-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ListClient").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ListClient").
+				Times(1)
+		})
+
+		When("limit is too large", func() {
+			It("should return error", func() {
+				p.Limit = 1001
+				res, err := m.ListClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid limit parameter")))
+			})
+		})
+
+		When("failed list client", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					ListClient(gomock.Eq(ctx), gomock.Eq(repository.ListClientParam{
+						AfterId: "mock-after-id",
+						Limit:   100,
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.ListClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success list client", func() {
+			It("should return result", func() {
+				p.Limit = 10
+				oAuthRepo.
+					EXPECT().
+					ListClient(gomock.Eq(ctx), gomock.Eq(repository.ListClientParam{
+						AfterId: "mock-after-id",
+						Limit:   10,
+					})).
+					Return(&repository.ListClientResult{
+						Items: []repository.ListClientItem{
+							{
+								Id:         "mock-id",
+								Name:       "frontend",
+								ClientId:   "mock-client-id",
+								Scopes:     []string{"file:read"},
+								DisabledAt: &currentTs,
+								CreatedAt:  currentTs,
+								UpdatedAt:  currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+
+				res, err := m.ListClient(ctx, p)
+
+				Expect(res).To(Equal(&managing.ListClientResult{
+					Items: []managing.ClientItem{
+						{
+							Id:         "mock-id",
+							Name:       "frontend",
+							ClientId:   "mock-client-id",
+							Scopes:     []string{"file:read"},
+							DisabledAt: &currentTs,
+							CreatedAt:  currentTs,
+							UpdatedAt:  currentTs,
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CreateSecret function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			oAuthRepo   *mock.MockOAuthRepository
+			hasher      *mock.MockHasher
+			identifier  *mock.MockIdentifier
+			secret      *mock.MockIdentifier
+			clock       *mock.MockClock
+			log         *mock.MockLogger
+			m           managing.ClientManager
+			p           managing.CreateSecretParam
+			createParam repository.CreateClientSecretParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			hasher = mock.NewMockHasher(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			secret = mock.NewMockIdentifier(ctrl)
+			clock = mock.NewMockClock(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:       oAuthRepo,
+				Hasher:          hasher,
+				Identifier:      identifier,
+				SecretGenerator: secret,
+				Clock:           clock,
+				Logger:          log,
+			})
+			p = managing.CreateSecretParam{
+				ClientId: "mock-client-id",
+				Label:    " ci-runner ",
+			}
+			createParam = repository.CreateClientSecretParam{
+				Id:       "mock-secret-id",
+				ClientId: "mock-client-id",
+				Label:    "ci-runner",
+				Secret:   "hashed-secret",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: CreateSecret").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: CreateSecret").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("label is too long", func() {
+			It("should return error", func() {
+				p.Label = strings.Repeat("a", 129)
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid label parameter")))
+			})
+		})
+
+		When("expires in is invalid", func() {
+			It("should return error", func() {
+				p.ExpiresIn = -1 * time.Second
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid expires in parameter")))
+			})
+		})
+
+		When("failed generate id", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("", fmt.Errorf("generate error")).
+					Times(1)
+
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("generate error")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(managing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed create secret", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success create expiring secret", func() {
+			It("should return plain secret", func() {
+				p.ExpiresIn = time.Hour
+				expiresAt := currentTs.Add(time.Hour)
+				createParam.ExpiresAt = &expiresAt
+
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTs).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateClientSecretResult{
+						CreatedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(Equal(&managing.CreateSecretResult{
+					ClientId:     "mock-client-id",
+					SecretId:     "mock-secret-id",
+					ClientSecret: "plain-secret",
+					Label:        "ci-runner",
+					ExpiresAt:    &expiresAt,
+					CreatedAt:    currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("request signing is enabled", func() {
+			It("should return signing key", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				signature := mock.NewMockSignatureAuth(ctrl)
+				m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+					OAuthRepo:       oAuthRepo,
+					Hasher:          hasher,
+					Identifier:      identifier,
+					SecretGenerator: secret,
+					Clock:           clock,
+					Signature:       signature,
+					Logger:          log,
+				})
+
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				signingKey := auth.DeriveSigningKey(auth.DeriveSigningKeyParam{
+					ClientId:     "mock-client-id",
+					SecretId:     "mock-secret-id",
+					ClientSecret: "plain-secret",
+				})
+				signature.
+					EXPECT().
+					SealSigningKey(gomock.Eq(auth.SealSigningKeyParam{
+						ClientId:   "mock-client-id",
+						SecretId:   "mock-secret-id",
+						SigningKey: signingKey,
+					})).
+					Return("sealed-signing-key", nil).
+					Times(1)
+				createParam.SigningKey = "sealed-signing-key"
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateClientSecretResult{
+						CreatedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res.SigningKey).To(Equal(signingKey))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed seal signing key", func() {
+			It("should return error", func() {
+				signature := mock.NewMockSignatureAuth(gomock.NewController(GinkgoT()))
+				m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+					OAuthRepo:       oAuthRepo,
+					Hasher:          hasher,
+					Identifier:      identifier,
+					SecretGenerator: secret,
+					Clock:           clock,
+					Signature:       signature,
+					Logger:          log,
+				})
+
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				signature.
+					EXPECT().
+					SealSigningKey(gomock.Any()).
+					Return("", fmt.Errorf("random error")).
+					Times(1)
+
+				res, err := m.CreateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("random error")))
+			})
+		})
+	})
+
+	Context("RotateSecret function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			oAuthRepo   *mock.MockOAuthRepository
+			hasher      *mock.MockHasher
+			identifier  *mock.MockIdentifier
+			secret      *mock.MockIdentifier
+			log         *mock.MockLogger
+			m           managing.ClientManager
+			p           managing.RotateSecretParam
+			createParam repository.CreateClientSecretParam
+			expireParam repository.ExpireClientSecretParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			hasher = mock.NewMockHasher(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			secret = mock.NewMockIdentifier(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:       oAuthRepo,
+				Hasher:          hasher,
+				Identifier:      identifier,
+				SecretGenerator: secret,
+				Logger:          log,
+			})
+			p = managing.RotateSecretParam{
+				ClientId:    "mock-client-id",
+				GracePeriod: 24 * time.Hour,
+			}
+			createParam = repository.CreateClientSecretParam{
+				Id:       "mock-secret-id",
+				ClientId: "mock-client-id",
+				Label:    "default",
+				Secret:   "hashed-secret",
+			}
+			expireParam = repository.ExpireClientSecretParam{
+				ClientId:  "mock-client-id",
+				ExceptId:  "mock-secret-id",
+				ExpiresAt: currentTs.Add(24 * time.Hour),
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: RotateSecret").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: RotateSecret").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.RotateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("grace period is invalid", func() {
+			It("should return error", func() {
+				p.GracePeriod = -1 * time.Second
+				res, err := m.RotateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid grace period parameter")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := m.RotateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(managing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed expire previous secret", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateClientSecretResult{
+						CreatedAt: currentTs,
+					}, nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					ExpireClientSecret(gomock.Eq(ctx), gomock.Eq(expireParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.RotateSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success rotate secret", func() {
+			It("should return plain secret", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateClientSecretResult{
+						CreatedAt: currentTs,
+					}, nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					ExpireClientSecret(gomock.Eq(ctx), gomock.Eq(expireParam)).
+					Return(&repository.ExpireClientSecretResult{
+						TotalExpired: 1,
+					}, nil).
+					Times(1)
+
+				res, err := m.RotateSecret(ctx, p)
+
+				Expect(res).To(Equal(&managing.RotateSecretResult{
+					ClientId:          "mock-client-id",
+					SecretId:          "mock-secret-id",
+					ClientSecret:      "plain-secret",
+					RotatedAt:         currentTs,
+					PreviousExpiresAt: currentTs.Add(24 * time.Hour),
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("credential cache is specified", func() {
+			It("should invalidate the client", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+					OAuthRepo:       oAuthRepo,
+					Hasher:          hasher,
+					Identifier:      identifier,
+					SecretGenerator: secret,
+					Cache:           cache,
+					Logger:          log,
+				})
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-secret-id", nil).
+					Times(1)
+				secret.
+					EXPECT().
+					GenerateId().
+					Return("plain-secret", nil).
+					Times(1)
+				hasher.
+					EXPECT().
+					Generate(gomock.Eq("plain-secret")).
+					Return([]byte("hashed-secret"), nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					CreateClientSecret(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateClientSecretResult{
+						CreatedAt: currentTs,
+					}, nil).
+					Times(1)
+				oAuthRepo.
+					EXPECT().
+					ExpireClientSecret(gomock.Eq(ctx), gomock.Eq(expireParam)).
+					Return(&repository.ExpireClientSecretResult{}, nil).
+					Times(1)
+				cache.
+					EXPECT().
+					InvalidateClient(gomock.Eq("mock-client-id")).
+					Times(1)
+
+				res, err := m.RotateSecret(ctx, p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListSecret function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			oAuthRepo *mock.MockOAuthRepository
+			log       *mock.MockLogger
+			m         managing.ClientManager
+			p         managing.ListSecretParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     log,
+			})
+			p = managing.ListSecretParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ListSecret").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ListSecret").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.ListSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("failed list secret", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					ListClientSecret(gomock.Eq(ctx), gomock.Eq(repository.ListClientSecretParam{
+						ClientId: "mock-client-id",
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.ListSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success list secret", func() {
+			It("should return result without the hash", func() {
+				oAuthRepo.
+					EXPECT().
+					ListClientSecret(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListClientSecretResult{
+						Items: []repository.ClientSecret{
+							{
+								Id:         "mock-secret-id",
+								Label:      "default",
+								Secret:     "hashed-secret",
+								CreatedAt:  currentTs,
+								ExpiresAt:  &currentTs,
+								LastUsedAt: &currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+
+				res, err := m.ListSecret(ctx, p)
+
+				Expect(res).To(Equal(&managing.ListSecretResult{
+					Items: []managing.SecretItem{
+						{
+							Id:         "mock-secret-id",
+							Label:      "default",
+							CreatedAt:  currentTs,
+							ExpiresAt:  &currentTs,
+							LastUsedAt: &currentTs,
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RevokeSecret function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			oAuthRepo *mock.MockOAuthRepository
+			log       *mock.MockLogger
+			m         managing.ClientManager
+			p         managing.RevokeSecretParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     log,
+			})
+			p = managing.RevokeSecretParam{
+				ClientId: "mock-client-id",
+				SecretId: "mock-secret-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: RevokeSecret").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: RevokeSecret").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.RevokeSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("secret id is not specified", func() {
+			It("should return error", func() {
+				p.SecretId = ""
+				res, err := m.RevokeSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid secret id parameter")))
+			})
+		})
+
+		When("secret is not found", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					DeleteClientSecret(gomock.Eq(ctx), gomock.Eq(repository.DeleteClientSecretParam{
+						ClientId: "mock-client-id",
+						SecretId: "mock-secret-id",
+					})).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := m.RevokeSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(managing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed revoke secret", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					DeleteClientSecret(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.RevokeSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success revoke secret", func() {
+			It("should return result", func() {
+				oAuthRepo.
+					EXPECT().
+					DeleteClientSecret(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.DeleteClientSecretResult{
+						DeletedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.RevokeSecret(ctx, p)
+
+				Expect(res).To(Equal(&managing.RevokeSecretResult{
+					RevokedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("credential cache is specified", func() {
+			It("should invalidate the client", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+					OAuthRepo:  oAuthRepo,
+					Hasher:     mock.NewMockHasher(ctrl),
+					Identifier: mock.NewMockIdentifier(ctrl),
+					Cache:      cache,
+					Logger:     log,
+				})
+				oAuthRepo.
+					EXPECT().
+					DeleteClientSecret(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.DeleteClientSecretResult{
+						DeletedAt: currentTs,
+					}, nil).
+					Times(1)
+				cache.
+					EXPECT().
+					InvalidateClient(gomock.Eq("mock-client-id")).
+					Times(1)
+
+				res, err := m.RevokeSecret(ctx, p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DisableClient function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			oAuthRepo *mock.MockOAuthRepository
+			log       *mock.MockLogger
+			m         managing.ClientManager
+			p         managing.DisableClientParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     log,
+			})
+			p = managing.DisableClientParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: DisableClient").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: DisableClient").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.DisableClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					DisableClient(gomock.Eq(ctx), gomock.Eq(repository.DisableClientParam{
+						ClientId: "mock-client-id",
+					})).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := m.DisableClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(managing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed disable client", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					DisableClient(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.DisableClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success disable client", func() {
+			It("should return result", func() {
+				oAuthRepo.
+					EXPECT().
+					DisableClient(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.DisableClientResult{
+						DisabledAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.DisableClient(ctx, p)
+
+				Expect(res).To(Equal(&managing.DisableClientResult{
+					DisabledAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("credential cache is specified", func() {
+			It("should invalidate the client", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+					OAuthRepo:  oAuthRepo,
+					Hasher:     mock.NewMockHasher(ctrl),
+					Identifier: mock.NewMockIdentifier(ctrl),
+					Cache:      cache,
+					Logger:     log,
+				})
+				oAuthRepo.
+					EXPECT().
+					DisableClient(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.DisableClientResult{
+						DisabledAt: currentTs,
+					}, nil).
+					Times(1)
+				cache.
+					EXPECT().
+					InvalidateClient(gomock.Eq("mock-client-id")).
+					Times(1)
+
+				res, err := m.DisableClient(ctx, p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateAllowlist function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			oAuthRepo   *mock.MockOAuthRepository
+			log         *mock.MockLogger
+			m           managing.ClientManager
+			p           managing.UpdateAllowlistParam
+			updateParam repository.UpdateClientAllowlistParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     log,
+			})
+			p = managing.UpdateAllowlistParam{
+				ClientId:     "mock-client-id",
+				AllowedCidrs: []string{"10.0.0.0/8"},
+			}
+			updateParam = repository.UpdateClientAllowlistParam{
+				ClientId:     "mock-client-id",
+				AllowedCidrs: []string{"10.0.0.0/8"},
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UpdateAllowlist").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UpdateAllowlist").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.UpdateAllowlist(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("allowed cidr is invalid", func() {
+			It("should return error", func() {
+				p.AllowedCidrs = []string{"vpc"}
+				res, err := m.UpdateAllowlist(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid allowed cidrs parameter")))
+			})
+		})
+
+		When("allowlist is too long", func() {
+			It("should return error", func() {
+				p.AllowedCidrs = []string{}
+				for i := 0; i <= managing.MAX_ALLOWED_CIDRS; i++ {
+					p.AllowedCidrs = append(p.AllowedCidrs, fmt.Sprintf("10.0.%d.0/24", i))
+				}
+				res, err := m.UpdateAllowlist(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid allowed cidrs parameter")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					UpdateClientAllowlist(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := m.UpdateAllowlist(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(managing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed update allowlist", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					UpdateClientAllowlist(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.UpdateAllowlist(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("allowlist is cleared", func() {
+			It("should return result", func() {
+				p.AllowedCidrs = nil
+				updateParam.AllowedCidrs = nil
+				oAuthRepo.
+					EXPECT().
+					UpdateClientAllowlist(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(&repository.UpdateClientAllowlistResult{
+						UpdatedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.UpdateAllowlist(ctx, p)
+
+				Expect(res).To(Equal(&managing.UpdateAllowlistResult{
+					UpdatedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("credential cache is specified", func() {
+			It("should invalidate the client", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+					OAuthRepo:  oAuthRepo,
+					Hasher:     mock.NewMockHasher(ctrl),
+					Identifier: mock.NewMockIdentifier(ctrl),
+					Cache:      cache,
+					Logger:     log,
+				})
+				oAuthRepo.
+					EXPECT().
+					UpdateClientAllowlist(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(&repository.UpdateClientAllowlistResult{
+						UpdatedAt: currentTs,
+					}, nil).
+					Times(1)
+				cache.
+					EXPECT().
+					InvalidateClient(gomock.Eq("mock-client-id")).
+					Times(1)
+
+				res, err := m.UpdateAllowlist(ctx, p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DeleteClient function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			currentTs time.Time
+			oAuthRepo *mock.MockOAuthRepository
+			log       *mock.MockLogger
+			m         managing.ClientManager
+			p         managing.DeleteClientParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     log,
+			})
+			p = managing.DeleteClientParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: DeleteClient").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: DeleteClient").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.DeleteClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					DeleteClient(gomock.Eq(ctx), gomock.Eq(repository.DeleteClientParam{
+						ClientId: "mock-client-id",
+					})).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := m.DeleteClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(managing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed delete client", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					DeleteClient(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.DeleteClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success delete client", func() {
+			It("should return result", func() {
+				oAuthRepo.
+					EXPECT().
+					DeleteClient(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.DeleteClientResult{
+						DeletedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.DeleteClient(ctx, p)
+
+				Expect(res).To(Equal(&managing.DeleteClientResult{
+					DeletedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("credential cache is specified", func() {
+			It("should invalidate the client", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				cache := mock.NewMockCredentialCache(ctrl)
+				m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+					OAuthRepo:  oAuthRepo,
+					Hasher:     mock.NewMockHasher(ctrl),
+					Identifier: mock.NewMockIdentifier(ctrl),
+					Cache:      cache,
+					Logger:     log,
+				})
+				oAuthRepo.
+					EXPECT().
+					DeleteClient(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.DeleteClientResult{
+						DeletedAt: currentTs,
+					}, nil).
+					Times(1)
+				cache.
+					EXPECT().
+					InvalidateClient(gomock.Eq("mock-client-id")).
+					Times(1)
+
+				res, err := m.DeleteClient(ctx, p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateRateLimit function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			oAuthRepo   *mock.MockOAuthRepository
+			log         *mock.MockLogger
+			m           managing.ClientManager
+			p           managing.UpdateRateLimitParam
+			updateParam repository.UpdateClientRateLimitParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			oAuthRepo = mock.NewMockOAuthRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			m, _ = managing.NewClientManager(managing.NewClientManagerParam{
+				OAuthRepo:  oAuthRepo,
+				Hasher:     mock.NewMockHasher(ctrl),
+				Identifier: mock.NewMockIdentifier(ctrl),
+				Logger:     log,
+			})
+			requestRate := int64(50)
+			byteRate := int64(0)
+			p = managing.UpdateRateLimitParam{
+				ClientId:    "mock-client-id",
+				RequestRate: &requestRate,
+				ByteRate:    &byteRate,
+			}
+			updateParam = repository.UpdateClientRateLimitParam{
+				ClientId:    "mock-client-id",
+				RequestRate: &requestRate,
+				ByteRate:    &byteRate,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UpdateRateLimit").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UpdateRateLimit").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := m.UpdateRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("request rate is negative", func() {
+			It("should return error", func() {
+				requestRate := int64(-1)
+				p.RequestRate = &requestRate
+				res, err := m.UpdateRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid request rate parameter")))
+			})
+		})
+
+		When("byte rate is negative", func() {
+			It("should return error", func() {
+				byteRate := int64(-1)
+				p.ByteRate = &byteRate
+				res, err := m.UpdateRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid byte rate parameter")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					UpdateClientRateLimit(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := m.UpdateRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(managing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed update rate limit", func() {
+			It("should return error", func() {
+				oAuthRepo.
+					EXPECT().
+					UpdateClientRateLimit(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := m.UpdateRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("rate limit is reset to default", func() {
+			It("should return result", func() {
+				p.RequestRate = nil
+				p.ByteRate = nil
+				updateParam.RequestRate = nil
+				updateParam.ByteRate = nil
+				oAuthRepo.
+					EXPECT().
+					UpdateClientRateLimit(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(&repository.UpdateClientRateLimitResult{
+						UpdatedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := m.UpdateRateLimit(ctx, p)
+
+				Expect(res).To(Equal(&managing.UpdateRateLimitResult{
+					UpdatedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auditing/auditor.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auditing "github.com/go-seidon/local/internal/auditing"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditor is a mock of Auditor interface.
+type MockAuditor struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditorMockRecorder
+}
+
+// MockAuditorMockRecorder is the mock recorder for MockAuditor.
+type MockAuditorMockRecorder struct {
+	mock *MockAuditor
+}
+
+// NewMockAuditor creates a new mock instance.
+func NewMockAuditor(ctrl *gomock.Controller) *MockAuditor {
+	mock := &MockAuditor{ctrl: ctrl}
+	mock.recorder = &MockAuditorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditor) EXPECT() *MockAuditorMockRecorder {
+	return m.recorder
+}
+
+// ListLog mocks base method.
+func (m *MockAuditor) ListLog(ctx context.Context, p auditing.ListLogParam) (*auditing.ListLogResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLog", ctx, p)
+	ret0, _ := ret[0].(*auditing.ListLogResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLog indicates an expected call of ListLog.
+func (mr *MockAuditorMockRecorder) ListLog(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLog", reflect.TypeOf((*MockAuditor)(nil).ListLog), ctx, p)
+}
+
+// Record mocks base method.
+func (m *MockAuditor) Record(ctx context.Context, p auditing.RecordParam) (*auditing.RecordResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, p)
+	ret0, _ := ret[0].(*auditing.RecordResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditorMockRecorder) Record(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditor)(nil).Record), ctx, p)
+}
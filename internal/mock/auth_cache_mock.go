@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auth/cache.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	time "time"
+
+	auth "github.com/go-seidon/local/internal/auth"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCredentialCache is a mock of CredentialCache interface.
+type MockCredentialCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialCacheMockRecorder
+}
+
+// MockCredentialCacheMockRecorder is the mock recorder for MockCredentialCache.
+type MockCredentialCacheMockRecorder struct {
+	mock *MockCredentialCache
+}
+
+// NewMockCredentialCache creates a new mock instance.
+func NewMockCredentialCache(ctrl *gomock.Controller) *MockCredentialCache {
+	mock := &MockCredentialCache{ctrl: ctrl}
+	mock.recorder = &MockCredentialCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredentialCache) EXPECT() *MockCredentialCacheMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockCredentialCache) Get(token string) (*auth.CheckCredentialResult, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", token)
+	ret0, _ := ret[0].(*auth.CheckCredentialResult)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCredentialCacheMockRecorder) Get(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCredentialCache)(nil).Get), token)
+}
+
+// InvalidateClient mocks base method.
+func (m *MockCredentialCache) InvalidateClient(clientId string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateClient", clientId)
+}
+
+// InvalidateClient indicates an expected call of InvalidateClient.
+func (mr *MockCredentialCacheMockRecorder) InvalidateClient(clientId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateClient", reflect.TypeOf((*MockCredentialCache)(nil).InvalidateClient), clientId)
+}
+
+// Set mocks base method.
+func (m *MockCredentialCache) Set(token string, res auth.CheckCredentialResult, expiresAt *time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", token, res, expiresAt)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCredentialCacheMockRecorder) Set(token, res, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCredentialCache)(nil).Set), token, res, expiresAt)
+}
+
+// Stats mocks base method.
+func (m *MockCredentialCache) Stats() auth.CacheStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(auth.CacheStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockCredentialCacheMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockCredentialCache)(nil).Stats))
+}
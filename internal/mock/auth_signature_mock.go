@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auth/signature.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/go-seidon/local/internal/auth"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSignatureAuth is a mock of SignatureAuth interface.
+type MockSignatureAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignatureAuthMockRecorder
+}
+
+// MockSignatureAuthMockRecorder is the mock recorder for MockSignatureAuth.
+type MockSignatureAuthMockRecorder struct {
+	mock *MockSignatureAuth
+}
+
+// NewMockSignatureAuth creates a new mock instance.
+func NewMockSignatureAuth(ctrl *gomock.Controller) *MockSignatureAuth {
+	mock := &MockSignatureAuth{ctrl: ctrl}
+	mock.recorder = &MockSignatureAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignatureAuth) EXPECT() *MockSignatureAuthMockRecorder {
+	return m.recorder
+}
+
+// ParseAuthorization mocks base method.
+func (m *MockSignatureAuth) ParseAuthorization(ctx context.Context, p auth.ParseAuthorizationParam) (*auth.ParseAuthorizationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseAuthorization", ctx, p)
+	ret0, _ := ret[0].(*auth.ParseAuthorizationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseAuthorization indicates an expected call of ParseAuthorization.
+func (mr *MockSignatureAuthMockRecorder) ParseAuthorization(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseAuthorization", reflect.TypeOf((*MockSignatureAuth)(nil).ParseAuthorization), ctx, p)
+}
+
+// SealSigningKey mocks base method.
+func (m *MockSignatureAuth) SealSigningKey(p auth.SealSigningKeyParam) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SealSigningKey", p)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SealSigningKey indicates an expected call of SealSigningKey.
+func (mr *MockSignatureAuthMockRecorder) SealSigningKey(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SealSigningKey", reflect.TypeOf((*MockSignatureAuth)(nil).SealSigningKey), p)
+}
+
+// VerifySignature mocks base method.
+func (m *MockSignatureAuth) VerifySignature(ctx context.Context, p auth.VerifySignatureParam) (*auth.VerifySignatureResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySignature", ctx, p)
+	ret0, _ := ret[0].(*auth.VerifySignatureResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifySignature indicates an expected call of VerifySignature.
+func (mr *MockSignatureAuthMockRecorder) VerifySignature(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySignature", reflect.TypeOf((*MockSignatureAuth)(nil).VerifySignature), ctx, p)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auth/token.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/go-seidon/local/internal/auth"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTokenAuth is a mock of TokenAuth interface.
+type MockTokenAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenAuthMockRecorder
+}
+
+// MockTokenAuthMockRecorder is the mock recorder for MockTokenAuth.
+type MockTokenAuthMockRecorder struct {
+	mock *MockTokenAuth
+}
+
+// NewMockTokenAuth creates a new mock instance.
+func NewMockTokenAuth(ctrl *gomock.Controller) *MockTokenAuth {
+	mock := &MockTokenAuth{ctrl: ctrl}
+	mock.recorder = &MockTokenAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenAuth) EXPECT() *MockTokenAuthMockRecorder {
+	return m.recorder
+}
+
+// IssueToken mocks base method.
+func (m *MockTokenAuth) IssueToken(ctx context.Context, p auth.IssueTokenParam) (*auth.IssueTokenResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueToken", ctx, p)
+	ret0, _ := ret[0].(*auth.IssueTokenResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueToken indicates an expected call of IssueToken.
+func (mr *MockTokenAuthMockRecorder) IssueToken(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueToken", reflect.TypeOf((*MockTokenAuth)(nil).IssueToken), ctx, p)
+}
+
+// VerifyToken mocks base method.
+func (m *MockTokenAuth) VerifyToken(ctx context.Context, p auth.VerifyTokenParam) (*auth.VerifyTokenResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyToken", ctx, p)
+	ret0, _ := ret[0].(*auth.VerifyTokenResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyToken indicates an expected call of VerifyToken.
+func (mr *MockTokenAuthMockRecorder) VerifyToken(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockTokenAuth)(nil).VerifyToken), ctx, p)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/compressing/policy.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	compressing "github.com/go-seidon/local/internal/compressing"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCompressionPolicy is a mock of CompressionPolicy interface.
+type MockCompressionPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompressionPolicyMockRecorder
+}
+
+// MockCompressionPolicyMockRecorder is the mock recorder for MockCompressionPolicy.
+type MockCompressionPolicyMockRecorder struct {
+	mock *MockCompressionPolicy
+}
+
+// NewMockCompressionPolicy creates a new mock instance.
+func NewMockCompressionPolicy(ctrl *gomock.Controller) *MockCompressionPolicy {
+	mock := &MockCompressionPolicy{ctrl: ctrl}
+	mock.recorder = &MockCompressionPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompressionPolicy) EXPECT() *MockCompressionPolicyMockRecorder {
+	return m.recorder
+}
+
+// SelectCodec mocks base method.
+func (m *MockCompressionPolicy) SelectCodec(ctx context.Context, p compressing.SelectCodecParam) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectCodec", ctx, p)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SelectCodec indicates an expected call of SelectCodec.
+func (mr *MockCompressionPolicyMockRecorder) SelectCodec(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectCodec", reflect.TypeOf((*MockCompressionPolicy)(nil).SelectCodec), ctx, p)
+}
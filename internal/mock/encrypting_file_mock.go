@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/encrypting/file.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	encrypting "github.com/go-seidon/local/internal/encrypting"
+	filesystem "github.com/go-seidon/local/internal/filesystem"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEncryptedFileManager is a mock of EncryptedFileManager interface.
+type MockEncryptedFileManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockEncryptedFileManagerMockRecorder
+}
+
+// MockEncryptedFileManagerMockRecorder is the mock recorder for MockEncryptedFileManager.
+type MockEncryptedFileManagerMockRecorder struct {
+	mock *MockEncryptedFileManager
+}
+
+// NewMockEncryptedFileManager creates a new mock instance.
+func NewMockEncryptedFileManager(ctrl *gomock.Controller) *MockEncryptedFileManager {
+	mock := &MockEncryptedFileManager{ctrl: ctrl}
+	mock.recorder = &MockEncryptedFileManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEncryptedFileManager) EXPECT() *MockEncryptedFileManagerMockRecorder {
+	return m.recorder
+}
+
+// IsFileExists mocks base method.
+func (m *MockEncryptedFileManager) IsFileExists(ctx context.Context, p filesystem.IsFileExistsParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFileExists", ctx, p)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFileExists indicates an expected call of IsFileExists.
+func (mr *MockEncryptedFileManagerMockRecorder) IsFileExists(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFileExists", reflect.TypeOf((*MockEncryptedFileManager)(nil).IsFileExists), ctx, p)
+}
+
+// MoveFile mocks base method.
+func (m *MockEncryptedFileManager) MoveFile(ctx context.Context, p filesystem.MoveFileParam) (*filesystem.MoveFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveFile", ctx, p)
+	ret0, _ := ret[0].(*filesystem.MoveFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveFile indicates an expected call of MoveFile.
+func (mr *MockEncryptedFileManagerMockRecorder) MoveFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFile", reflect.TypeOf((*MockEncryptedFileManager)(nil).MoveFile), ctx, p)
+}
+
+// OpenFile mocks base method.
+func (m *MockEncryptedFileManager) OpenFile(ctx context.Context, p filesystem.OpenFileParam) (*filesystem.OpenFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFile", ctx, p)
+	ret0, _ := ret[0].(*filesystem.OpenFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenFile indicates an expected call of OpenFile.
+func (mr *MockEncryptedFileManagerMockRecorder) OpenFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockEncryptedFileManager)(nil).OpenFile), ctx, p)
+}
+
+// RekeyFile mocks base method.
+func (m *MockEncryptedFileManager) RekeyFile(ctx context.Context, p encrypting.RekeyFileParam) (*encrypting.RekeyFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RekeyFile", ctx, p)
+	ret0, _ := ret[0].(*encrypting.RekeyFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RekeyFile indicates an expected call of RekeyFile.
+func (mr *MockEncryptedFileManagerMockRecorder) RekeyFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RekeyFile", reflect.TypeOf((*MockEncryptedFileManager)(nil).RekeyFile), ctx, p)
+}
+
+// RemoveFile mocks base method.
+func (m *MockEncryptedFileManager) RemoveFile(ctx context.Context, p filesystem.RemoveFileParam) (*filesystem.RemoveFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFile", ctx, p)
+	ret0, _ := ret[0].(*filesystem.RemoveFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveFile indicates an expected call of RemoveFile.
+func (mr *MockEncryptedFileManagerMockRecorder) RemoveFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFile", reflect.TypeOf((*MockEncryptedFileManager)(nil).RemoveFile), ctx, p)
+}
+
+// SaveFile mocks base method.
+func (m *MockEncryptedFileManager) SaveFile(ctx context.Context, p filesystem.SaveFileParam) (*filesystem.SaveFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFile", ctx, p)
+	ret0, _ := ret[0].(*filesystem.SaveFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveFile indicates an expected call of SaveFile.
+func (mr *MockEncryptedFileManagerMockRecorder) SaveFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFile", reflect.TypeOf((*MockEncryptedFileManager)(nil).SaveFile), ctx, p)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/encrypting/keyring.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	encrypting "github.com/go-seidon/local/internal/encrypting"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockKeyring is a mock of Keyring interface.
+type MockKeyring struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyringMockRecorder
+}
+
+// MockKeyringMockRecorder is the mock recorder for MockKeyring.
+type MockKeyringMockRecorder struct {
+	mock *MockKeyring
+}
+
+// NewMockKeyring creates a new mock instance.
+func NewMockKeyring(ctrl *gomock.Controller) *MockKeyring {
+	mock := &MockKeyring{ctrl: ctrl}
+	mock.recorder = &MockKeyringMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyring) EXPECT() *MockKeyringMockRecorder {
+	return m.recorder
+}
+
+// CurrentKeyId mocks base method.
+func (m *MockKeyring) CurrentKeyId() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentKeyId")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CurrentKeyId indicates an expected call of CurrentKeyId.
+func (mr *MockKeyringMockRecorder) CurrentKeyId() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentKeyId", reflect.TypeOf((*MockKeyring)(nil).CurrentKeyId))
+}
+
+// UnwrapKey mocks base method.
+func (m *MockKeyring) UnwrapKey(keyId string, wrappedKey []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnwrapKey", keyId, wrappedKey)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnwrapKey indicates an expected call of UnwrapKey.
+func (mr *MockKeyringMockRecorder) UnwrapKey(keyId, wrappedKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnwrapKey", reflect.TypeOf((*MockKeyring)(nil).UnwrapKey), keyId, wrappedKey)
+}
+
+// WrapKey mocks base method.
+func (m *MockKeyring) WrapKey(dataKey []byte) (*encrypting.WrapKeyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WrapKey", dataKey)
+	ret0, _ := ret[0].(*encrypting.WrapKeyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WrapKey indicates an expected call of WrapKey.
+func (mr *MockKeyringMockRecorder) WrapKey(dataKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WrapKey", reflect.TypeOf((*MockKeyring)(nil).WrapKey), dataKey)
+}
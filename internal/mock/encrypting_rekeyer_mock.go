@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/encrypting/rekeyer.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	encrypting "github.com/go-seidon/local/internal/encrypting"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRekeyer is a mock of Rekeyer interface.
+type MockRekeyer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRekeyerMockRecorder
+}
+
+// MockRekeyerMockRecorder is the mock recorder for MockRekeyer.
+type MockRekeyerMockRecorder struct {
+	mock *MockRekeyer
+}
+
+// NewMockRekeyer creates a new mock instance.
+func NewMockRekeyer(ctrl *gomock.Controller) *MockRekeyer {
+	mock := &MockRekeyer{ctrl: ctrl}
+	mock.recorder = &MockRekeyerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRekeyer) EXPECT() *MockRekeyerMockRecorder {
+	return m.recorder
+}
+
+// RekeyFiles mocks base method.
+func (m *MockRekeyer) RekeyFiles(ctx context.Context, p encrypting.RekeyFilesParam) (*encrypting.RekeyFilesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RekeyFiles", ctx, p)
+	ret0, _ := ret[0].(*encrypting.RekeyFilesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RekeyFiles indicates an expected call of RekeyFiles.
+func (mr *MockRekeyerMockRecorder) RekeyFiles(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RekeyFiles", reflect.TypeOf((*MockRekeyer)(nil).RekeyFiles), ctx, p)
+}
@@ -35,34 +35,34 @@ func (m *MockFileManager) EXPECT() *MockFileManagerMockRecorder {
 	return m.recorder
 }
 
-// CreateDir mocks base method.
-func (m *MockFileManager) CreateDir(ctx context.Context, p filesystem.CreateDirParam) (*filesystem.CreateDirResult, error) {
+// IsFileExists mocks base method.
+func (m *MockFileManager) IsFileExists(ctx context.Context, p filesystem.IsFileExistsParam) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateDir", ctx, p)
-	ret0, _ := ret[0].(*filesystem.CreateDirResult)
+	ret := m.ctrl.Call(m, "IsFileExists", ctx, p)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateDir indicates an expected call of CreateDir.
-func (mr *MockFileManagerMockRecorder) CreateDir(ctx, p interface{}) *gomock.Call {
+// IsFileExists indicates an expected call of IsFileExists.
+func (mr *MockFileManagerMockRecorder) IsFileExists(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDir", reflect.TypeOf((*MockFileManager)(nil).CreateDir), ctx, p)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFileExists", reflect.TypeOf((*MockFileManager)(nil).IsFileExists), ctx, p)
 }
 
-// IsFileExists mocks base method.
-func (m *MockFileManager) IsFileExists(ctx context.Context, p filesystem.IsFileExistsParam) (bool, error) {
+// MoveFile mocks base method.
+func (m *MockFileManager) MoveFile(ctx context.Context, p filesystem.MoveFileParam) (*filesystem.MoveFileResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsFileExists", ctx, p)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "MoveFile", ctx, p)
+	ret0, _ := ret[0].(*filesystem.MoveFileResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsFileExists indicates an expected call of IsFileExists.
-func (mr *MockFileManagerMockRecorder) IsFileExists(ctx, p interface{}) *gomock.Call {
+// MoveFile indicates an expected call of MoveFile.
+func (mr *MockFileManagerMockRecorder) MoveFile(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFileExists", reflect.TypeOf((*MockFileManager)(nil).IsFileExists), ctx, p)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFile", reflect.TypeOf((*MockFileManager)(nil).MoveFile), ctx, p)
 }
 
 // OpenFile mocks base method.
@@ -48,6 +48,20 @@ func (mr *MockHasherMockRecorder) Generate(src interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockHasher)(nil).Generate), src)
 }
 
+// NeedsRehash mocks base method.
+func (m *MockHasher) NeedsRehash(hash string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRehash", hash)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRehash indicates an expected call of NeedsRehash.
+func (mr *MockHasherMockRecorder) NeedsRehash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRehash", reflect.TypeOf((*MockHasher)(nil).NeedsRehash), hash)
+}
+
 // Verify mocks base method.
 func (m *MockHasher) Verify(hash, text string) error {
 	m.ctrl.T.Helper()
@@ -136,3 +150,40 @@ func (mr *MockVerificatorMockRecorder) Verify(hash, text interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockVerificator)(nil).Verify), hash, text)
 }
+
+// MockRehasher is a mock of Rehasher interface.
+type MockRehasher struct {
+	ctrl     *gomock.Controller
+	recorder *MockRehasherMockRecorder
+}
+
+// MockRehasherMockRecorder is the mock recorder for MockRehasher.
+type MockRehasherMockRecorder struct {
+	mock *MockRehasher
+}
+
+// NewMockRehasher creates a new mock instance.
+func NewMockRehasher(ctrl *gomock.Controller) *MockRehasher {
+	mock := &MockRehasher{ctrl: ctrl}
+	mock.recorder = &MockRehasherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRehasher) EXPECT() *MockRehasherMockRecorder {
+	return m.recorder
+}
+
+// NeedsRehash mocks base method.
+func (m *MockRehasher) NeedsRehash(hash string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRehash", hash)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRehash indicates an expected call of NeedsRehash.
+func (mr *MockRehasherMockRecorder) NeedsRehash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRehash", reflect.TypeOf((*MockRehasher)(nil).NeedsRehash), hash)
+}
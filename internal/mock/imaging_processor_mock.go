@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/imaging/processor.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	imaging "github.com/go-seidon/local/internal/imaging"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockImageProcessor is a mock of ImageProcessor interface.
+type MockImageProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageProcessorMockRecorder
+}
+
+// MockImageProcessorMockRecorder is the mock recorder for MockImageProcessor.
+type MockImageProcessorMockRecorder struct {
+	mock *MockImageProcessor
+}
+
+// NewMockImageProcessor creates a new mock instance.
+func NewMockImageProcessor(ctrl *gomock.Controller) *MockImageProcessor {
+	mock := &MockImageProcessor{ctrl: ctrl}
+	mock.recorder = &MockImageProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageProcessor) EXPECT() *MockImageProcessorMockRecorder {
+	return m.recorder
+}
+
+// ExtractMetadata mocks base method.
+func (m *MockImageProcessor) ExtractMetadata(ctx context.Context, p imaging.ExtractMetadataParam) (*imaging.ExtractMetadataResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtractMetadata", ctx, p)
+	ret0, _ := ret[0].(*imaging.ExtractMetadataResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtractMetadata indicates an expected call of ExtractMetadata.
+func (mr *MockImageProcessorMockRecorder) ExtractMetadata(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractMetadata", reflect.TypeOf((*MockImageProcessor)(nil).ExtractMetadata), ctx, p)
+}
+
+// StripMetadata mocks base method.
+func (m *MockImageProcessor) StripMetadata(ctx context.Context, p imaging.StripMetadataParam) (*imaging.StripMetadataResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StripMetadata", ctx, p)
+	ret0, _ := ret[0].(*imaging.StripMetadataResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StripMetadata indicates an expected call of StripMetadata.
+func (mr *MockImageProcessorMockRecorder) StripMetadata(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StripMetadata", reflect.TypeOf((*MockImageProcessor)(nil).StripMetadata), ctx, p)
+}
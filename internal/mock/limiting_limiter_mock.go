@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/limiting/limiter.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	limiting "github.com/go-seidon/local/internal/limiting"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLimiter is a mock of Limiter interface.
+type MockLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimiterMockRecorder
+}
+
+// MockLimiterMockRecorder is the mock recorder for MockLimiter.
+type MockLimiterMockRecorder struct {
+	mock *MockLimiter
+}
+
+// NewMockLimiter creates a new mock instance.
+func NewMockLimiter(ctrl *gomock.Controller) *MockLimiter {
+	mock := &MockLimiter{ctrl: ctrl}
+	mock.recorder = &MockLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimiter) EXPECT() *MockLimiterMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockLimiter) Acquire(ctx context.Context, p limiting.AcquireParam) (*limiting.AcquireResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, p)
+	ret0, _ := ret[0].(*limiting.AcquireResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockLimiterMockRecorder) Acquire(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockLimiter)(nil).Acquire), ctx, p)
+}
+
+// AllowRequest mocks base method.
+func (m *MockLimiter) AllowRequest(ctx context.Context, p limiting.AllowRequestParam) (*limiting.AllowRequestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowRequest", ctx, p)
+	ret0, _ := ret[0].(*limiting.AllowRequestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllowRequest indicates an expected call of AllowRequest.
+func (mr *MockLimiterMockRecorder) AllowRequest(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowRequest", reflect.TypeOf((*MockLimiter)(nil).AllowRequest), ctx, p)
+}
+
+// AllowTransfer mocks base method.
+func (m *MockLimiter) AllowTransfer(ctx context.Context, p limiting.AllowTransferParam) (*limiting.AllowTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowTransfer", ctx, p)
+	ret0, _ := ret[0].(*limiting.AllowTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllowTransfer indicates an expected call of AllowTransfer.
+func (mr *MockLimiterMockRecorder) AllowTransfer(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowTransfer", reflect.TypeOf((*MockLimiter)(nil).AllowTransfer), ctx, p)
+}
+
+// RecordTransfer mocks base method.
+func (m *MockLimiter) RecordTransfer(ctx context.Context, p limiting.RecordTransferParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTransfer", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordTransfer indicates an expected call of RecordTransfer.
+func (mr *MockLimiterMockRecorder) RecordTransfer(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTransfer", reflect.TypeOf((*MockLimiter)(nil).RecordTransfer), ctx, p)
+}
+
+// Release mocks base method.
+func (m *MockLimiter) Release(ctx context.Context, p limiting.ReleaseParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLimiterMockRecorder) Release(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLimiter)(nil).Release), ctx, p)
+}
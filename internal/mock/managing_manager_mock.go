@@ -0,0 +1,186 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/managing/manager.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	managing "github.com/go-seidon/local/internal/managing"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockClientManager is a mock of ClientManager interface.
+type MockClientManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientManagerMockRecorder
+}
+
+// MockClientManagerMockRecorder is the mock recorder for MockClientManager.
+type MockClientManagerMockRecorder struct {
+	mock *MockClientManager
+}
+
+// NewMockClientManager creates a new mock instance.
+func NewMockClientManager(ctrl *gomock.Controller) *MockClientManager {
+	mock := &MockClientManager{ctrl: ctrl}
+	mock.recorder = &MockClientManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientManager) EXPECT() *MockClientManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateClient mocks base method.
+func (m *MockClientManager) CreateClient(ctx context.Context, p managing.CreateClientParam) (*managing.CreateClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx, p)
+	ret0, _ := ret[0].(*managing.CreateClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockClientManagerMockRecorder) CreateClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockClientManager)(nil).CreateClient), ctx, p)
+}
+
+// CreateSecret mocks base method.
+func (m *MockClientManager) CreateSecret(ctx context.Context, p managing.CreateSecretParam) (*managing.CreateSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSecret", ctx, p)
+	ret0, _ := ret[0].(*managing.CreateSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSecret indicates an expected call of CreateSecret.
+func (mr *MockClientManagerMockRecorder) CreateSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecret", reflect.TypeOf((*MockClientManager)(nil).CreateSecret), ctx, p)
+}
+
+// DeleteClient mocks base method.
+func (m *MockClientManager) DeleteClient(ctx context.Context, p managing.DeleteClientParam) (*managing.DeleteClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, p)
+	ret0, _ := ret[0].(*managing.DeleteClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockClientManagerMockRecorder) DeleteClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockClientManager)(nil).DeleteClient), ctx, p)
+}
+
+// DisableClient mocks base method.
+func (m *MockClientManager) DisableClient(ctx context.Context, p managing.DisableClientParam) (*managing.DisableClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableClient", ctx, p)
+	ret0, _ := ret[0].(*managing.DisableClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableClient indicates an expected call of DisableClient.
+func (mr *MockClientManagerMockRecorder) DisableClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableClient", reflect.TypeOf((*MockClientManager)(nil).DisableClient), ctx, p)
+}
+
+// ListClient mocks base method.
+func (m *MockClientManager) ListClient(ctx context.Context, p managing.ListClientParam) (*managing.ListClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClient", ctx, p)
+	ret0, _ := ret[0].(*managing.ListClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClient indicates an expected call of ListClient.
+func (mr *MockClientManagerMockRecorder) ListClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClient", reflect.TypeOf((*MockClientManager)(nil).ListClient), ctx, p)
+}
+
+// ListSecret mocks base method.
+func (m *MockClientManager) ListSecret(ctx context.Context, p managing.ListSecretParam) (*managing.ListSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecret", ctx, p)
+	ret0, _ := ret[0].(*managing.ListSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecret indicates an expected call of ListSecret.
+func (mr *MockClientManagerMockRecorder) ListSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecret", reflect.TypeOf((*MockClientManager)(nil).ListSecret), ctx, p)
+}
+
+// RevokeSecret mocks base method.
+func (m *MockClientManager) RevokeSecret(ctx context.Context, p managing.RevokeSecretParam) (*managing.RevokeSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSecret", ctx, p)
+	ret0, _ := ret[0].(*managing.RevokeSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSecret indicates an expected call of RevokeSecret.
+func (mr *MockClientManagerMockRecorder) RevokeSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSecret", reflect.TypeOf((*MockClientManager)(nil).RevokeSecret), ctx, p)
+}
+
+// RotateSecret mocks base method.
+func (m *MockClientManager) RotateSecret(ctx context.Context, p managing.RotateSecretParam) (*managing.RotateSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSecret", ctx, p)
+	ret0, _ := ret[0].(*managing.RotateSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSecret indicates an expected call of RotateSecret.
+func (mr *MockClientManagerMockRecorder) RotateSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSecret", reflect.TypeOf((*MockClientManager)(nil).RotateSecret), ctx, p)
+}
+
+// UpdateAllowlist mocks base method.
+func (m *MockClientManager) UpdateAllowlist(ctx context.Context, p managing.UpdateAllowlistParam) (*managing.UpdateAllowlistResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAllowlist", ctx, p)
+	ret0, _ := ret[0].(*managing.UpdateAllowlistResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAllowlist indicates an expected call of UpdateAllowlist.
+func (mr *MockClientManagerMockRecorder) UpdateAllowlist(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAllowlist", reflect.TypeOf((*MockClientManager)(nil).UpdateAllowlist), ctx, p)
+}
+
+// UpdateRateLimit mocks base method.
+func (m *MockClientManager) UpdateRateLimit(ctx context.Context, p managing.UpdateRateLimitParam) (*managing.UpdateRateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRateLimit", ctx, p)
+	ret0, _ := ret[0].(*managing.UpdateRateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRateLimit indicates an expected call of UpdateRateLimit.
+func (mr *MockClientManagerMockRecorder) UpdateRateLimit(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRateLimit", reflect.TypeOf((*MockClientManager)(nil).UpdateRateLimit), ctx, p)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/notifying/relay.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notifying "github.com/go-seidon/local/internal/notifying"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRelay is a mock of Relay interface.
+type MockRelay struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayMockRecorder
+}
+
+// MockRelayMockRecorder is the mock recorder for MockRelay.
+type MockRelayMockRecorder struct {
+	mock *MockRelay
+}
+
+// NewMockRelay creates a new mock instance.
+func NewMockRelay(ctrl *gomock.Controller) *MockRelay {
+	mock := &MockRelay{ctrl: ctrl}
+	mock.recorder = &MockRelayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelay) EXPECT() *MockRelayMockRecorder {
+	return m.recorder
+}
+
+// Relay mocks base method.
+func (m *MockRelay) Relay(ctx context.Context) (*notifying.RelayResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Relay", ctx)
+	ret0, _ := ret[0].(*notifying.RelayResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Relay indicates an expected call of Relay.
+func (mr *MockRelayMockRecorder) Relay(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Relay", reflect.TypeOf((*MockRelay)(nil).Relay), ctx)
+}
+
+// Start mocks base method.
+func (m *MockRelay) Start() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockRelayMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRelay)(nil).Start))
+}
+
+// Stop mocks base method.
+func (m *MockRelay) Stop() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockRelayMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockRelay)(nil).Stop))
+}
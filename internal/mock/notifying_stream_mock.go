@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/notifying/stream.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notifying "github.com/go-seidon/local/internal/notifying"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEventStream is a mock of EventStream interface.
+type MockEventStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStreamMockRecorder
+}
+
+// MockEventStreamMockRecorder is the mock recorder for MockEventStream.
+type MockEventStreamMockRecorder struct {
+	mock *MockEventStream
+}
+
+// NewMockEventStream creates a new mock instance.
+func NewMockEventStream(ctrl *gomock.Controller) *MockEventStream {
+	mock := &MockEventStream{ctrl: ctrl}
+	mock.recorder = &MockEventStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStream) EXPECT() *MockEventStreamMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockEventStream) Subscribe(ctx context.Context, p notifying.SubscribeParam) (*notifying.SubscribeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, p)
+	ret0, _ := ret[0].(*notifying.SubscribeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventStreamMockRecorder) Subscribe(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventStream)(nil).Subscribe), ctx, p)
+}
@@ -0,0 +1,149 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/notifying/webhook.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notifying "github.com/go-seidon/local/internal/notifying"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockPublisher) Publish(ctx context.Context, p notifying.PublishParam) (*notifying.PublishResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, p)
+	ret0, _ := ret[0].(*notifying.PublishResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPublisherMockRecorder) Publish(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisher)(nil).Publish), ctx, p)
+}
+
+// MockWebhook is a mock of Webhook interface.
+type MockWebhook struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookMockRecorder
+}
+
+// MockWebhookMockRecorder is the mock recorder for MockWebhook.
+type MockWebhookMockRecorder struct {
+	mock *MockWebhook
+}
+
+// NewMockWebhook creates a new mock instance.
+func NewMockWebhook(ctrl *gomock.Controller) *MockWebhook {
+	mock := &MockWebhook{ctrl: ctrl}
+	mock.recorder = &MockWebhookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhook) EXPECT() *MockWebhookMockRecorder {
+	return m.recorder
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhook) CreateSubscription(ctx context.Context, p notifying.CreateSubscriptionParam) (*notifying.CreateSubscriptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, p)
+	ret0, _ := ret[0].(*notifying.CreateSubscriptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhookMockRecorder) CreateSubscription(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhook)(nil).CreateSubscription), ctx, p)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhook) DeleteSubscription(ctx context.Context, p notifying.DeleteSubscriptionParam) (*notifying.DeleteSubscriptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, p)
+	ret0, _ := ret[0].(*notifying.DeleteSubscriptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookMockRecorder) DeleteSubscription(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhook)(nil).DeleteSubscription), ctx, p)
+}
+
+// ListDelivery mocks base method.
+func (m *MockWebhook) ListDelivery(ctx context.Context, p notifying.ListDeliveryParam) (*notifying.ListDeliveryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelivery", ctx, p)
+	ret0, _ := ret[0].(*notifying.ListDeliveryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDelivery indicates an expected call of ListDelivery.
+func (mr *MockWebhookMockRecorder) ListDelivery(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelivery", reflect.TypeOf((*MockWebhook)(nil).ListDelivery), ctx, p)
+}
+
+// ListSubscription mocks base method.
+func (m *MockWebhook) ListSubscription(ctx context.Context, p notifying.ListSubscriptionParam) (*notifying.ListSubscriptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscription", ctx, p)
+	ret0, _ := ret[0].(*notifying.ListSubscriptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscription indicates an expected call of ListSubscription.
+func (mr *MockWebhookMockRecorder) ListSubscription(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscription", reflect.TypeOf((*MockWebhook)(nil).ListSubscription), ctx, p)
+}
+
+// Publish mocks base method.
+func (m *MockWebhook) Publish(ctx context.Context, p notifying.PublishParam) (*notifying.PublishResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, p)
+	ret0, _ := ret[0].(*notifying.PublishResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockWebhookMockRecorder) Publish(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockWebhook)(nil).Publish), ctx, p)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/quota/quota.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	quota "github.com/go-seidon/local/internal/quota"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuota is a mock of Quota interface.
+type MockQuota struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaMockRecorder
+}
+
+// MockQuotaMockRecorder is the mock recorder for MockQuota.
+type MockQuotaMockRecorder struct {
+	mock *MockQuota
+}
+
+// NewMockQuota creates a new mock instance.
+func NewMockQuota(ctrl *gomock.Controller) *MockQuota {
+	mock := &MockQuota{ctrl: ctrl}
+	mock.recorder = &MockQuotaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuota) EXPECT() *MockQuotaMockRecorder {
+	return m.recorder
+}
+
+// RetrieveUsage mocks base method.
+func (m *MockQuota) RetrieveUsage(ctx context.Context, p quota.RetrieveUsageParam) (*quota.RetrieveUsageResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveUsage", ctx, p)
+	ret0, _ := ret[0].(*quota.RetrieveUsageResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveUsage indicates an expected call of RetrieveUsage.
+func (mr *MockQuotaMockRecorder) RetrieveUsage(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveUsage", reflect.TypeOf((*MockQuota)(nil).RetrieveUsage), ctx, p)
+}
+
+// UpdateQuota mocks base method.
+func (m *MockQuota) UpdateQuota(ctx context.Context, p quota.UpdateQuotaParam) (*quota.UpdateQuotaResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQuota", ctx, p)
+	ret0, _ := ret[0].(*quota.UpdateQuotaResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateQuota indicates an expected call of UpdateQuota.
+func (mr *MockQuotaMockRecorder) UpdateQuota(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuota", reflect.TypeOf((*MockQuota)(nil).UpdateQuota), ctx, p)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/relocating/relocator.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	relocating "github.com/go-seidon/local/internal/relocating"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRelocator is a mock of Relocator interface.
+type MockRelocator struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelocatorMockRecorder
+}
+
+// MockRelocatorMockRecorder is the mock recorder for MockRelocator.
+type MockRelocatorMockRecorder struct {
+	mock *MockRelocator
+}
+
+// NewMockRelocator creates a new mock instance.
+func NewMockRelocator(ctrl *gomock.Controller) *MockRelocator {
+	mock := &MockRelocator{ctrl: ctrl}
+	mock.recorder = &MockRelocatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelocator) EXPECT() *MockRelocatorMockRecorder {
+	return m.recorder
+}
+
+// RelocateFiles mocks base method.
+func (m *MockRelocator) RelocateFiles(ctx context.Context, p relocating.RelocateFilesParam) (*relocating.RelocateFilesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelocateFiles", ctx, p)
+	ret0, _ := ret[0].(*relocating.RelocateFilesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelocateFiles indicates an expected call of RelocateFiles.
+func (mr *MockRelocatorMockRecorder) RelocateFiles(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelocateFiles", reflect.TypeOf((*MockRelocator)(nil).RelocateFiles), ctx, p)
+}
@@ -65,6 +65,21 @@ func (mr *MockFileRepositoryMockRecorder) DeleteFile(ctx, p interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockFileRepository)(nil).DeleteFile), ctx, p)
 }
 
+// ListFile mocks base method.
+func (m *MockFileRepository) ListFile(ctx context.Context, p repository.ListFileParam) (*repository.ListFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFile", ctx, p)
+	ret0, _ := ret[0].(*repository.ListFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFile indicates an expected call of ListFile.
+func (mr *MockFileRepositoryMockRecorder) ListFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFile", reflect.TypeOf((*MockFileRepository)(nil).ListFile), ctx, p)
+}
+
 // RetrieveFile mocks base method.
 func (m *MockFileRepository) RetrieveFile(ctx context.Context, p repository.RetrieveFileParam) (*repository.RetrieveFileResult, error) {
 	m.ctrl.T.Helper()
@@ -79,3 +94,48 @@ func (mr *MockFileRepositoryMockRecorder) RetrieveFile(ctx, p interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveFile", reflect.TypeOf((*MockFileRepository)(nil).RetrieveFile), ctx, p)
 }
+
+// UpdateFileKey mocks base method.
+func (m *MockFileRepository) UpdateFileKey(ctx context.Context, p repository.UpdateFileKeyParam) (*repository.UpdateFileKeyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileKey", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateFileKeyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFileKey indicates an expected call of UpdateFileKey.
+func (mr *MockFileRepositoryMockRecorder) UpdateFileKey(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileKey", reflect.TypeOf((*MockFileRepository)(nil).UpdateFileKey), ctx, p)
+}
+
+// UpdateFilePath mocks base method.
+func (m *MockFileRepository) UpdateFilePath(ctx context.Context, p repository.UpdateFilePathParam) (*repository.UpdateFilePathResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFilePath", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateFilePathResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFilePath indicates an expected call of UpdateFilePath.
+func (mr *MockFileRepositoryMockRecorder) UpdateFilePath(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilePath", reflect.TypeOf((*MockFileRepository)(nil).UpdateFilePath), ctx, p)
+}
+
+// UpdateFileVisibility mocks base method.
+func (m *MockFileRepository) UpdateFileVisibility(ctx context.Context, p repository.UpdateFileVisibilityParam) (*repository.UpdateFileVisibilityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileVisibility", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateFileVisibilityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFileVisibility indicates an expected call of UpdateFileVisibility.
+func (mr *MockFileRepositoryMockRecorder) UpdateFileVisibility(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileVisibility", reflect.TypeOf((*MockFileRepository)(nil).UpdateFileVisibility), ctx, p)
+}
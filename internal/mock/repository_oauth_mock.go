@@ -35,6 +35,96 @@ func (m *MockOAuthRepository) EXPECT() *MockOAuthRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CreateClient mocks base method.
+func (m *MockOAuthRepository) CreateClient(ctx context.Context, p repository.CreateClientParam) (*repository.CreateClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx, p)
+	ret0, _ := ret[0].(*repository.CreateClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockOAuthRepositoryMockRecorder) CreateClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockOAuthRepository)(nil).CreateClient), ctx, p)
+}
+
+// CreateClientSecret mocks base method.
+func (m *MockOAuthRepository) CreateClientSecret(ctx context.Context, p repository.CreateClientSecretParam) (*repository.CreateClientSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClientSecret", ctx, p)
+	ret0, _ := ret[0].(*repository.CreateClientSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClientSecret indicates an expected call of CreateClientSecret.
+func (mr *MockOAuthRepositoryMockRecorder) CreateClientSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClientSecret", reflect.TypeOf((*MockOAuthRepository)(nil).CreateClientSecret), ctx, p)
+}
+
+// DeleteClient mocks base method.
+func (m *MockOAuthRepository) DeleteClient(ctx context.Context, p repository.DeleteClientParam) (*repository.DeleteClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, p)
+	ret0, _ := ret[0].(*repository.DeleteClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockOAuthRepositoryMockRecorder) DeleteClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockOAuthRepository)(nil).DeleteClient), ctx, p)
+}
+
+// DeleteClientSecret mocks base method.
+func (m *MockOAuthRepository) DeleteClientSecret(ctx context.Context, p repository.DeleteClientSecretParam) (*repository.DeleteClientSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClientSecret", ctx, p)
+	ret0, _ := ret[0].(*repository.DeleteClientSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClientSecret indicates an expected call of DeleteClientSecret.
+func (mr *MockOAuthRepositoryMockRecorder) DeleteClientSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientSecret", reflect.TypeOf((*MockOAuthRepository)(nil).DeleteClientSecret), ctx, p)
+}
+
+// DisableClient mocks base method.
+func (m *MockOAuthRepository) DisableClient(ctx context.Context, p repository.DisableClientParam) (*repository.DisableClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableClient", ctx, p)
+	ret0, _ := ret[0].(*repository.DisableClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableClient indicates an expected call of DisableClient.
+func (mr *MockOAuthRepositoryMockRecorder) DisableClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableClient", reflect.TypeOf((*MockOAuthRepository)(nil).DisableClient), ctx, p)
+}
+
+// ExpireClientSecret mocks base method.
+func (m *MockOAuthRepository) ExpireClientSecret(ctx context.Context, p repository.ExpireClientSecretParam) (*repository.ExpireClientSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireClientSecret", ctx, p)
+	ret0, _ := ret[0].(*repository.ExpireClientSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireClientSecret indicates an expected call of ExpireClientSecret.
+func (mr *MockOAuthRepositoryMockRecorder) ExpireClientSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireClientSecret", reflect.TypeOf((*MockOAuthRepository)(nil).ExpireClientSecret), ctx, p)
+}
+
 // FindClient mocks base method.
 func (m *MockOAuthRepository) FindClient(ctx context.Context, p repository.FindClientParam) (*repository.FindClientResult, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +139,108 @@ func (mr *MockOAuthRepositoryMockRecorder) FindClient(ctx, p interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClient", reflect.TypeOf((*MockOAuthRepository)(nil).FindClient), ctx, p)
 }
+
+// FindClientRateLimit mocks base method.
+func (m *MockOAuthRepository) FindClientRateLimit(ctx context.Context, p repository.FindClientRateLimitParam) (*repository.FindClientRateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindClientRateLimit", ctx, p)
+	ret0, _ := ret[0].(*repository.FindClientRateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindClientRateLimit indicates an expected call of FindClientRateLimit.
+func (mr *MockOAuthRepositoryMockRecorder) FindClientRateLimit(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClientRateLimit", reflect.TypeOf((*MockOAuthRepository)(nil).FindClientRateLimit), ctx, p)
+}
+
+// ListClient mocks base method.
+func (m *MockOAuthRepository) ListClient(ctx context.Context, p repository.ListClientParam) (*repository.ListClientResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClient", ctx, p)
+	ret0, _ := ret[0].(*repository.ListClientResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClient indicates an expected call of ListClient.
+func (mr *MockOAuthRepositoryMockRecorder) ListClient(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClient", reflect.TypeOf((*MockOAuthRepository)(nil).ListClient), ctx, p)
+}
+
+// ListClientSecret mocks base method.
+func (m *MockOAuthRepository) ListClientSecret(ctx context.Context, p repository.ListClientSecretParam) (*repository.ListClientSecretResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClientSecret", ctx, p)
+	ret0, _ := ret[0].(*repository.ListClientSecretResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClientSecret indicates an expected call of ListClientSecret.
+func (mr *MockOAuthRepositoryMockRecorder) ListClientSecret(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClientSecret", reflect.TypeOf((*MockOAuthRepository)(nil).ListClientSecret), ctx, p)
+}
+
+// UpdateClientAllowlist mocks base method.
+func (m *MockOAuthRepository) UpdateClientAllowlist(ctx context.Context, p repository.UpdateClientAllowlistParam) (*repository.UpdateClientAllowlistResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateClientAllowlist", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateClientAllowlistResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateClientAllowlist indicates an expected call of UpdateClientAllowlist.
+func (mr *MockOAuthRepositoryMockRecorder) UpdateClientAllowlist(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClientAllowlist", reflect.TypeOf((*MockOAuthRepository)(nil).UpdateClientAllowlist), ctx, p)
+}
+
+// UpdateClientRateLimit mocks base method.
+func (m *MockOAuthRepository) UpdateClientRateLimit(ctx context.Context, p repository.UpdateClientRateLimitParam) (*repository.UpdateClientRateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateClientRateLimit", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateClientRateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateClientRateLimit indicates an expected call of UpdateClientRateLimit.
+func (mr *MockOAuthRepositoryMockRecorder) UpdateClientRateLimit(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClientRateLimit", reflect.TypeOf((*MockOAuthRepository)(nil).UpdateClientRateLimit), ctx, p)
+}
+
+// UpdateSecretHash mocks base method.
+func (m *MockOAuthRepository) UpdateSecretHash(ctx context.Context, p repository.UpdateSecretHashParam) (*repository.UpdateSecretHashResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSecretHash", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateSecretHashResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSecretHash indicates an expected call of UpdateSecretHash.
+func (mr *MockOAuthRepositoryMockRecorder) UpdateSecretHash(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecretHash", reflect.TypeOf((*MockOAuthRepository)(nil).UpdateSecretHash), ctx, p)
+}
+
+// UpdateSecretUsage mocks base method.
+func (m *MockOAuthRepository) UpdateSecretUsage(ctx context.Context, p repository.UpdateSecretUsageParam) (*repository.UpdateSecretUsageResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSecretUsage", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateSecretUsageResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSecretUsage indicates an expected call of UpdateSecretUsage.
+func (mr *MockOAuthRepositoryMockRecorder) UpdateSecretUsage(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecretUsage", reflect.TypeOf((*MockOAuthRepository)(nil).UpdateSecretUsage), ctx, p)
+}
@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/outbox.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repository "github.com/go-seidon/local/internal/repository"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimEvent mocks base method.
+func (m *MockOutboxRepository) ClaimEvent(ctx context.Context, p repository.ClaimEventParam) (*repository.ClaimEventResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimEvent", ctx, p)
+	ret0, _ := ret[0].(*repository.ClaimEventResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimEvent indicates an expected call of ClaimEvent.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimEvent(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimEvent", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimEvent), ctx, p)
+}
+
+// DeletePublishedEvent mocks base method.
+func (m *MockOutboxRepository) DeletePublishedEvent(ctx context.Context, p repository.DeletePublishedEventParam) (*repository.DeletePublishedEventResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublishedEvent", ctx, p)
+	ret0, _ := ret[0].(*repository.DeletePublishedEventResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePublishedEvent indicates an expected call of DeletePublishedEvent.
+func (mr *MockOutboxRepositoryMockRecorder) DeletePublishedEvent(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublishedEvent", reflect.TypeOf((*MockOutboxRepository)(nil).DeletePublishedEvent), ctx, p)
+}
+
+// FindLastEvent mocks base method.
+func (m *MockOutboxRepository) FindLastEvent(ctx context.Context, p repository.FindLastEventParam) (*repository.FindLastEventResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLastEvent", ctx, p)
+	ret0, _ := ret[0].(*repository.FindLastEventResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLastEvent indicates an expected call of FindLastEvent.
+func (mr *MockOutboxRepositoryMockRecorder) FindLastEvent(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastEvent", reflect.TypeOf((*MockOutboxRepository)(nil).FindLastEvent), ctx, p)
+}
+
+// ListClientEvent mocks base method.
+func (m *MockOutboxRepository) ListClientEvent(ctx context.Context, p repository.ListClientEventParam) (*repository.ListClientEventResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClientEvent", ctx, p)
+	ret0, _ := ret[0].(*repository.ListClientEventResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClientEvent indicates an expected call of ListClientEvent.
+func (mr *MockOutboxRepositoryMockRecorder) ListClientEvent(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClientEvent", reflect.TypeOf((*MockOutboxRepository)(nil).ListClientEvent), ctx, p)
+}
+
+// ListPendingEvent mocks base method.
+func (m *MockOutboxRepository) ListPendingEvent(ctx context.Context, p repository.ListPendingEventParam) (*repository.ListPendingEventResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingEvent", ctx, p)
+	ret0, _ := ret[0].(*repository.ListPendingEventResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingEvent indicates an expected call of ListPendingEvent.
+func (mr *MockOutboxRepositoryMockRecorder) ListPendingEvent(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingEvent", reflect.TypeOf((*MockOutboxRepository)(nil).ListPendingEvent), ctx, p)
+}
+
+// PublishEvent mocks base method.
+func (m *MockOutboxRepository) PublishEvent(ctx context.Context, p repository.PublishEventParam) (*repository.PublishEventResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishEvent", ctx, p)
+	ret0, _ := ret[0].(*repository.PublishEventResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishEvent indicates an expected call of PublishEvent.
+func (mr *MockOutboxRepositoryMockRecorder) PublishEvent(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishEvent", reflect.TypeOf((*MockOutboxRepository)(nil).PublishEvent), ctx, p)
+}
+
+// RetryEvent mocks base method.
+func (m *MockOutboxRepository) RetryEvent(ctx context.Context, p repository.RetryEventParam) (*repository.RetryEventResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryEvent", ctx, p)
+	ret0, _ := ret[0].(*repository.RetryEventResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryEvent indicates an expected call of RetryEvent.
+func (mr *MockOutboxRepositoryMockRecorder) RetryEvent(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryEvent", reflect.TypeOf((*MockOutboxRepository)(nil).RetryEvent), ctx, p)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/quota.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repository "github.com/go-seidon/local/internal/repository"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuotaRepository is a mock of QuotaRepository interface.
+type MockQuotaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaRepositoryMockRecorder
+}
+
+// MockQuotaRepositoryMockRecorder is the mock recorder for MockQuotaRepository.
+type MockQuotaRepositoryMockRecorder struct {
+	mock *MockQuotaRepository
+}
+
+// NewMockQuotaRepository creates a new mock instance.
+func NewMockQuotaRepository(ctrl *gomock.Controller) *MockQuotaRepository {
+	mock := &MockQuotaRepository{ctrl: ctrl}
+	mock.recorder = &MockQuotaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotaRepository) EXPECT() *MockQuotaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindQuota mocks base method.
+func (m *MockQuotaRepository) FindQuota(ctx context.Context, p repository.FindQuotaParam) (*repository.FindQuotaResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindQuota", ctx, p)
+	ret0, _ := ret[0].(*repository.FindQuotaResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindQuota indicates an expected call of FindQuota.
+func (mr *MockQuotaRepositoryMockRecorder) FindQuota(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindQuota", reflect.TypeOf((*MockQuotaRepository)(nil).FindQuota), ctx, p)
+}
+
+// UpdateQuota mocks base method.
+func (m *MockQuotaRepository) UpdateQuota(ctx context.Context, p repository.UpdateQuotaParam) (*repository.UpdateQuotaResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQuota", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateQuotaResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateQuota indicates an expected call of UpdateQuota.
+func (mr *MockQuotaRepositoryMockRecorder) UpdateQuota(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuota", reflect.TypeOf((*MockQuotaRepository)(nil).UpdateQuota), ctx, p)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/share.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repository "github.com/go-seidon/local/internal/repository"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockShareRepository is a mock of ShareRepository interface.
+type MockShareRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareRepositoryMockRecorder
+}
+
+// MockShareRepositoryMockRecorder is the mock recorder for MockShareRepository.
+type MockShareRepositoryMockRecorder struct {
+	mock *MockShareRepository
+}
+
+// NewMockShareRepository creates a new mock instance.
+func NewMockShareRepository(ctrl *gomock.Controller) *MockShareRepository {
+	mock := &MockShareRepository{ctrl: ctrl}
+	mock.recorder = &MockShareRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareRepository) EXPECT() *MockShareRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateShare mocks base method.
+func (m *MockShareRepository) CreateShare(ctx context.Context, p repository.CreateShareParam) (*repository.CreateShareResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShare", ctx, p)
+	ret0, _ := ret[0].(*repository.CreateShareResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateShare indicates an expected call of CreateShare.
+func (mr *MockShareRepositoryMockRecorder) CreateShare(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShare", reflect.TypeOf((*MockShareRepository)(nil).CreateShare), ctx, p)
+}
+
+// DeleteShare mocks base method.
+func (m *MockShareRepository) DeleteShare(ctx context.Context, p repository.DeleteShareParam) (*repository.DeleteShareResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShare", ctx, p)
+	ret0, _ := ret[0].(*repository.DeleteShareResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteShare indicates an expected call of DeleteShare.
+func (mr *MockShareRepositoryMockRecorder) DeleteShare(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShare", reflect.TypeOf((*MockShareRepository)(nil).DeleteShare), ctx, p)
+}
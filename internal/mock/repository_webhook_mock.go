@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/webhook.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repository "github.com/go-seidon/local/internal/repository"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDelivery mocks base method.
+func (m *MockWebhookRepository) ClaimDelivery(ctx context.Context, p repository.ClaimDeliveryParam) (*repository.ClaimDeliveryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDelivery", ctx, p)
+	ret0, _ := ret[0].(*repository.ClaimDeliveryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDelivery indicates an expected call of ClaimDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) ClaimDelivery(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).ClaimDelivery), ctx, p)
+}
+
+// CreateDelivery mocks base method.
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, p repository.CreateDeliveryParam) (*repository.CreateDeliveryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", ctx, p)
+	ret0, _ := ret[0].(*repository.CreateDeliveryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) CreateDelivery(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).CreateDelivery), ctx, p)
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, p repository.CreateSubscriptionParam) (*repository.CreateSubscriptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, p)
+	ret0, _ := ret[0].(*repository.CreateSubscriptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) CreateSubscription(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).CreateSubscription), ctx, p)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, p repository.DeleteSubscriptionParam) (*repository.DeleteSubscriptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, p)
+	ret0, _ := ret[0].(*repository.DeleteSubscriptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteSubscription(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteSubscription), ctx, p)
+}
+
+// ListDelivery mocks base method.
+func (m *MockWebhookRepository) ListDelivery(ctx context.Context, p repository.ListDeliveryParam) (*repository.ListDeliveryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelivery", ctx, p)
+	ret0, _ := ret[0].(*repository.ListDeliveryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDelivery indicates an expected call of ListDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) ListDelivery(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).ListDelivery), ctx, p)
+}
+
+// ListDueDelivery mocks base method.
+func (m *MockWebhookRepository) ListDueDelivery(ctx context.Context, p repository.ListDueDeliveryParam) (*repository.ListDueDeliveryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueDelivery", ctx, p)
+	ret0, _ := ret[0].(*repository.ListDueDeliveryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueDelivery indicates an expected call of ListDueDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) ListDueDelivery(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).ListDueDelivery), ctx, p)
+}
+
+// ListSubscription mocks base method.
+func (m *MockWebhookRepository) ListSubscription(ctx context.Context, p repository.ListSubscriptionParam) (*repository.ListSubscriptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscription", ctx, p)
+	ret0, _ := ret[0].(*repository.ListSubscriptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscription indicates an expected call of ListSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) ListSubscription(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).ListSubscription), ctx, p)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, p repository.UpdateDeliveryParam) (*repository.UpdateDeliveryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, p)
+	ret0, _ := ret[0].(*repository.UpdateDeliveryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateDelivery(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateDelivery), ctx, p)
+}
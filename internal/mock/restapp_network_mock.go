@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/rest-app/network.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIpResolver is a mock of IpResolver interface.
+type MockIpResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockIpResolverMockRecorder
+}
+
+// MockIpResolverMockRecorder is the mock recorder for MockIpResolver.
+type MockIpResolverMockRecorder struct {
+	mock *MockIpResolver
+}
+
+// NewMockIpResolver creates a new mock instance.
+func NewMockIpResolver(ctrl *gomock.Controller) *MockIpResolver {
+	mock := &MockIpResolver{ctrl: ctrl}
+	mock.recorder = &MockIpResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIpResolver) EXPECT() *MockIpResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockIpResolver) Resolve(r *http.Request) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", r)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockIpResolverMockRecorder) Resolve(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockIpResolver)(nil).Resolve), r)
+}
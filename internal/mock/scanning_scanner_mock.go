@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/scanning/scanner.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	scanning "github.com/go-seidon/local/internal/scanning"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockScanner is a mock of Scanner interface.
+type MockScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockScannerMockRecorder
+}
+
+// MockScannerMockRecorder is the mock recorder for MockScanner.
+type MockScannerMockRecorder struct {
+	mock *MockScanner
+}
+
+// NewMockScanner creates a new mock instance.
+func NewMockScanner(ctrl *gomock.Controller) *MockScanner {
+	mock := &MockScanner{ctrl: ctrl}
+	mock.recorder = &MockScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScanner) EXPECT() *MockScannerMockRecorder {
+	return m.recorder
+}
+
+// ScanFile mocks base method.
+func (m *MockScanner) ScanFile(ctx context.Context, p scanning.ScanFileParam) (*scanning.ScanFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanFile", ctx, p)
+	ret0, _ := ret[0].(*scanning.ScanFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanFile indicates an expected call of ScanFile.
+func (mr *MockScannerMockRecorder) ScanFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanFile", reflect.TypeOf((*MockScanner)(nil).ScanFile), ctx, p)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/sharing/sharer.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sharing "github.com/go-seidon/local/internal/sharing"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSharer is a mock of Sharer interface.
+type MockSharer struct {
+	ctrl     *gomock.Controller
+	recorder *MockSharerMockRecorder
+}
+
+// MockSharerMockRecorder is the mock recorder for MockSharer.
+type MockSharerMockRecorder struct {
+	mock *MockSharer
+}
+
+// NewMockSharer creates a new mock instance.
+func NewMockSharer(ctrl *gomock.Controller) *MockSharer {
+	mock := &MockSharer{ctrl: ctrl}
+	mock.recorder = &MockSharerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSharer) EXPECT() *MockSharerMockRecorder {
+	return m.recorder
+}
+
+// ShareFile mocks base method.
+func (m *MockSharer) ShareFile(ctx context.Context, p sharing.ShareFileParam) (*sharing.ShareFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareFile", ctx, p)
+	ret0, _ := ret[0].(*sharing.ShareFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareFile indicates an expected call of ShareFile.
+func (mr *MockSharerMockRecorder) ShareFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareFile", reflect.TypeOf((*MockSharer)(nil).ShareFile), ctx, p)
+}
+
+// UnshareFile mocks base method.
+func (m *MockSharer) UnshareFile(ctx context.Context, p sharing.UnshareFileParam) (*sharing.UnshareFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnshareFile", ctx, p)
+	ret0, _ := ret[0].(*sharing.UnshareFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnshareFile indicates an expected call of UnshareFile.
+func (mr *MockSharerMockRecorder) UnshareFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnshareFile", reflect.TypeOf((*MockSharer)(nil).UnshareFile), ctx, p)
+}
+
+// UpdateVisibility mocks base method.
+func (m *MockSharer) UpdateVisibility(ctx context.Context, p sharing.UpdateVisibilityParam) (*sharing.UpdateVisibilityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVisibility", ctx, p)
+	ret0, _ := ret[0].(*sharing.UpdateVisibilityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVisibility indicates an expected call of UpdateVisibility.
+func (mr *MockSharerMockRecorder) UpdateVisibility(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVisibility", reflect.TypeOf((*MockSharer)(nil).UpdateVisibility), ctx, p)
+}
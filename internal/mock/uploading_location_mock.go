@@ -7,6 +7,7 @@ package mock
 import (
 	reflect "reflect"
 
+	uploading "github.com/go-seidon/local/internal/uploading"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,15 +35,15 @@ func (m *MockUploadLocation) EXPECT() *MockUploadLocationMockRecorder {
 }
 
 // GetLocation mocks base method.
-func (m *MockUploadLocation) GetLocation() string {
+func (m *MockUploadLocation) GetLocation(p uploading.GetLocationParam) string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLocation")
+	ret := m.ctrl.Call(m, "GetLocation", p)
 	ret0, _ := ret[0].(string)
 	return ret0
 }
 
 // GetLocation indicates an expected call of GetLocation.
-func (mr *MockUploadLocationMockRecorder) GetLocation() *gomock.Call {
+func (mr *MockUploadLocationMockRecorder) GetLocation(p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocation", reflect.TypeOf((*MockUploadLocation)(nil).GetLocation))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocation", reflect.TypeOf((*MockUploadLocation)(nil).GetLocation), p)
 }
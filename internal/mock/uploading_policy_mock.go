@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/uploading/policy.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uploading "github.com/go-seidon/local/internal/uploading"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUploadPolicy is a mock of UploadPolicy interface.
+type MockUploadPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadPolicyMockRecorder
+}
+
+// MockUploadPolicyMockRecorder is the mock recorder for MockUploadPolicy.
+type MockUploadPolicyMockRecorder struct {
+	mock *MockUploadPolicy
+}
+
+// NewMockUploadPolicy creates a new mock instance.
+func NewMockUploadPolicy(ctrl *gomock.Controller) *MockUploadPolicy {
+	mock := &MockUploadPolicy{ctrl: ctrl}
+	mock.recorder = &MockUploadPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadPolicy) EXPECT() *MockUploadPolicyMockRecorder {
+	return m.recorder
+}
+
+// ValidateFile mocks base method.
+func (m *MockUploadPolicy) ValidateFile(ctx context.Context, p uploading.ValidateFileParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateFile", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateFile indicates an expected call of ValidateFile.
+func (mr *MockUploadPolicyMockRecorder) ValidateFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateFile", reflect.TypeOf((*MockUploadPolicy)(nil).ValidateFile), ctx, p)
+}
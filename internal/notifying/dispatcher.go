@@ -0,0 +1,324 @@
+package notifying
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+const (
+	HEADER_WEBHOOK_ID        = "X-Webhook-Id"
+	HEADER_WEBHOOK_DELIVERY  = "X-Webhook-Delivery"
+	HEADER_WEBHOOK_EVENT     = "X-Webhook-Event"
+	HEADER_WEBHOOK_TIMESTAMP = "X-Webhook-Timestamp"
+	HEADER_WEBHOOK_SIGNATURE = "X-Webhook-Signature"
+
+	DEFAULT_DISPATCH_INTERVAL = 5 * time.Second
+	DEFAULT_DISPATCH_BATCH    = 100
+	DEFAULT_MAX_ATTEMPTS      = 8
+	DEFAULT_BACKOFF_BASE      = 10 * time.Second
+	DEFAULT_BACKOFF_MAX       = time.Hour
+	DEFAULT_DELIVERY_TIMEOUT  = 10 * time.Second
+	MAX_ERROR_SIZE            = 256
+	MAX_RESPONSE_DRAIN        = 64 * 1024
+)
+
+type HttpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// the delivery is at least once, the receiver must discard the duplicate event by the event id
+type Dispatcher interface {
+	Start() error
+	Stop() error
+	Dispatch(ctx context.Context) (*DispatchResult, error)
+}
+
+type DispatchResult struct {
+	Delivered int
+	Retried   int
+	Dead      int
+	Skipped   int
+}
+
+type dispatcher struct {
+	webhookRepo repository.WebhookRepository
+	httpClient  HttpClient
+	clock       datetime.Clock
+	log         logging.Logger
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	timeout     time.Duration
+
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	stopped chan struct{}
+}
+
+func (d *dispatcher) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopCh != nil {
+		return fmt.Errorf("dispatcher is already started")
+	}
+	d.stopCh = make(chan struct{})
+	d.stopped = make(chan struct{})
+
+	go d.run(d.stopCh, d.stopped)
+	return nil
+}
+
+func (d *dispatcher) Stop() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopCh == nil {
+		return nil
+	}
+	close(d.stopCh)
+	<-d.stopped
+	d.stopCh = nil
+	d.stopped = nil
+	return nil
+}
+
+func (d *dispatcher) run(stopCh chan struct{}, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			_, err := d.Dispatch(context.Background())
+			if err != nil {
+				d.log.Errorf("Failed dispatch webhook: %s", err.Error())
+			}
+		}
+	}
+}
+
+func (d *dispatcher) Dispatch(ctx context.Context) (*DispatchResult, error) {
+	currentTs := d.clock.Now()
+
+	deliveries, err := d.webhookRepo.ListDueDelivery(ctx, repository.ListDueDeliveryParam{
+		DueAt: currentTs,
+		Limit: d.batchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DispatchResult{}
+	for _, delivery := range deliveries.Items {
+		_, err := d.webhookRepo.ClaimDelivery(ctx, repository.ClaimDeliveryParam{
+			Id:            delivery.Id,
+			NextAttemptAt: delivery.NextAttemptAt,
+			LeaseUntil:    d.clock.Now().Add(d.timeout + d.interval),
+		})
+		if errors.Is(err, repository.ErrorRecordChanged) {
+			res.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := d.send(ctx, delivery)
+		attempt := delivery.Attempt + 1
+		p := repository.UpdateDeliveryParam{
+			Id:            delivery.Id,
+			Status:        repository.DELIVERY_DELIVERED,
+			Attempt:       attempt,
+			NextAttemptAt: delivery.NextAttemptAt,
+			ResponseCode:  status,
+		}
+		if err != nil {
+			p.LastError = truncate(err.Error(), MAX_ERROR_SIZE)
+			if attempt >= d.maxAttempts {
+				p.Status = repository.DELIVERY_DEAD
+			} else {
+				p.Status = repository.DELIVERY_PENDING
+				p.NextAttemptAt = d.clock.Now().Add(backoff(d.backoffBase, d.backoffMax, attempt))
+			}
+		}
+
+		_, err = d.webhookRepo.UpdateDelivery(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+
+		switch p.Status {
+		case repository.DELIVERY_DELIVERED:
+			res.Delivered++
+		case repository.DELIVERY_DEAD:
+			res.Dead++
+			d.log.WithFields(map[string]interface{}{
+				"event":           "webhook.dead",
+				"delivery_id":     delivery.Id,
+				"subscription_id": delivery.SubscriptionId,
+				"event_id":        delivery.EventId,
+			}).Warn("Webhook delivery is dead lettered")
+		default:
+			res.Retried++
+		}
+	}
+	return res, nil
+}
+
+// the signature is hex encoded hmac-sha256 of "<timestamp>.<payload>" using the subscription secret,
+// the timestamp is signed so the receiver is able to reject the replayed request
+func (d *dispatcher) send(ctx context.Context, delivery repository.DueDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(d.clock.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HEADER_WEBHOOK_ID, delivery.EventId)
+	req.Header.Set(HEADER_WEBHOOK_DELIVERY, delivery.Id)
+	req.Header.Set(HEADER_WEBHOOK_EVENT, delivery.EventType)
+	req.Header.Set(HEADER_WEBHOOK_TIMESTAMP, timestamp)
+	req.Header.Set(HEADER_WEBHOOK_SIGNATURE, "v1="+Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, MAX_RESPONSE_DRAIN))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func backoff(base time.Duration, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+func Sign(secret string, timestamp string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func truncate(s string, size int) string {
+	if len(s) > size {
+		return s[:size]
+	}
+	return s
+}
+
+type NewDispatcherParam struct {
+	WebhookRepo repository.WebhookRepository
+	Logger      logging.Logger
+	HttpClient  HttpClient
+	// the delivery to the private network address is allowed, ignored when the http client is specified
+	AllowPrivateNetwork bool
+	Clock               datetime.Clock
+	Interval            time.Duration
+	BatchSize           int
+	MaxAttempts         int
+	BackoffBase         time.Duration
+	BackoffMax          time.Duration
+	Timeout             time.Duration
+}
+
+func NewDispatcher(p NewDispatcherParam) (*dispatcher, error) {
+	if p.WebhookRepo == nil {
+		return nil, fmt.Errorf("webhook repo is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+	if p.Interval < 0 || p.BatchSize < 0 || p.MaxAttempts < 0 ||
+		p.BackoffBase < 0 || p.BackoffMax < 0 || p.Timeout < 0 {
+		return nil, fmt.Errorf("invalid dispatcher parameter")
+	}
+
+	interval := DEFAULT_DISPATCH_INTERVAL
+	if p.Interval > 0 {
+		interval = p.Interval
+	}
+	batchSize := DEFAULT_DISPATCH_BATCH
+	if p.BatchSize > 0 {
+		batchSize = p.BatchSize
+	}
+	maxAttempts := DEFAULT_MAX_ATTEMPTS
+	if p.MaxAttempts > 0 {
+		maxAttempts = p.MaxAttempts
+	}
+	backoffBase := DEFAULT_BACKOFF_BASE
+	if p.BackoffBase > 0 {
+		backoffBase = p.BackoffBase
+	}
+	backoffMax := DEFAULT_BACKOFF_MAX
+	if p.BackoffMax > 0 {
+		backoffMax = p.BackoffMax
+	}
+	if backoffMax < backoffBase {
+		backoffMax = backoffBase
+	}
+	timeout := DEFAULT_DELIVERY_TIMEOUT
+	if p.Timeout > 0 {
+		timeout = p.Timeout
+	}
+	httpClient := p.HttpClient
+	if httpClient == nil {
+		httpClient = NewHttpClient(NewHttpClientParam{
+			Timeout:             timeout,
+			AllowPrivateNetwork: p.AllowPrivateNetwork,
+		})
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	d := &dispatcher{
+		webhookRepo: p.WebhookRepo,
+		httpClient:  httpClient,
+		clock:       clock,
+		log:         p.Logger,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		backoffBase: backoffBase,
+		backoffMax:  backoffMax,
+		timeout:     timeout,
+	}
+	return d, nil
+}
@@ -0,0 +1,488 @@
+package notifying_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dispatcher Service", func() {
+	Context("NewDispatcher function", Label("unit"), func() {
+		var (
+			p notifying.NewDispatcherParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = notifying.NewDispatcherParam{
+				WebhookRepo: mock.NewMockWebhookRepository(ctrl),
+				Logger:      mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := notifying.NewDispatcher(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("optional parameters are specified", func() {
+			It("should return result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				p.HttpClient = mock.NewMockHttpClient(ctrl)
+				p.Clock = mock.NewMockClock(ctrl)
+				p.Interval = time.Second
+				p.BatchSize = 10
+				p.MaxAttempts = 3
+				p.BackoffBase = time.Minute
+				p.BackoffMax = time.Second
+				p.Timeout = time.Second
+				res, err := notifying.NewDispatcher(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("webhook repo is not specified", func() {
+			It("should return error", func() {
+				p.WebhookRepo = nil
+				res, err := notifying.NewDispatcher(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("webhook repo is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := notifying.NewDispatcher(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+
+		When("parameter is negative", func() {
+			It("should return error", func() {
+				p.MaxAttempts = -1
+				res, err := notifying.NewDispatcher(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid dispatcher parameter")))
+			})
+		})
+	})
+
+	Context("Dispatch function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			webhookRepo *mock.MockWebhookRepository
+			httpClient  *mock.MockHttpClient
+			clock       *mock.MockClock
+			log         *mock.MockLogger
+			d           notifying.Dispatcher
+			delivery    repository.DueDelivery
+			claimParam  repository.ClaimDeliveryParam
+			updateParam repository.UpdateDeliveryParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			webhookRepo = mock.NewMockWebhookRepository(ctrl)
+			httpClient = mock.NewMockHttpClient(ctrl)
+			clock = mock.NewMockClock(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			d, _ = notifying.NewDispatcher(notifying.NewDispatcherParam{
+				WebhookRepo: webhookRepo,
+				HttpClient:  httpClient,
+				Clock:       clock,
+				Logger:      log,
+				Interval:    5 * time.Second,
+				BatchSize:   10,
+				MaxAttempts: 3,
+				BackoffBase: 10 * time.Second,
+				BackoffMax:  15 * time.Second,
+				Timeout:     10 * time.Second,
+			})
+			delivery = repository.DueDelivery{
+				Id:             "delivery-id",
+				SubscriptionId: "subscription-id",
+				EventId:        "event-id",
+				EventType:      "file.uploaded",
+				Payload:        `{"id":"event-id"}`,
+				Attempt:        0,
+				NextAttemptAt:  currentTs.Add(-time.Second),
+				Url:            "https://example.com/hook",
+				Secret:         "secret",
+			}
+			claimParam = repository.ClaimDeliveryParam{
+				Id:            "delivery-id",
+				NextAttemptAt: currentTs.Add(-time.Second),
+				LeaseUntil:    currentTs.Add(15 * time.Second),
+			}
+			updateParam = repository.UpdateDeliveryParam{
+				Id:            "delivery-id",
+				Status:        repository.DELIVERY_DELIVERED,
+				Attempt:       1,
+				NextAttemptAt: currentTs.Add(-time.Second),
+				ResponseCode:  204,
+			}
+
+			clock.EXPECT().Now().Return(currentTs).AnyTimes()
+		})
+
+		response := func(status int) *http.Response {
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader("ok")),
+			}
+		}
+
+		When("failed list due delivery", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Eq(repository.ListDueDeliveryParam{
+						DueAt: currentTs,
+						Limit: 10,
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("delivery is claimed by the other dispatcher", func() {
+			It("should skip the delivery", func() {
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListDueDeliveryResult{Items: []repository.DueDelivery{delivery}}, nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					ClaimDelivery(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(nil, repository.ErrorRecordChanged).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(Equal(&notifying.DispatchResult{Skipped: 1}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed claim delivery", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListDueDeliveryResult{Items: []repository.DueDelivery{delivery}}, nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					ClaimDelivery(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("delivery is accepted", func() {
+			It("should send signed payload and mark it delivered", func() {
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListDueDeliveryResult{Items: []repository.DueDelivery{delivery}}, nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					ClaimDelivery(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimDeliveryResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				httpClient.
+					EXPECT().
+					Do(gomock.Any()).
+					DoAndReturn(func(req *http.Request) (*http.Response, error) {
+						body, _ := io.ReadAll(req.Body)
+						Expect(req.Method).To(Equal(http.MethodPost))
+						Expect(req.URL.String()).To(Equal("https://example.com/hook"))
+						Expect(string(body)).To(Equal(`{"id":"event-id"}`))
+						Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+						Expect(req.Header.Get("X-Webhook-Id")).To(Equal("event-id"))
+						Expect(req.Header.Get("X-Webhook-Delivery")).To(Equal("delivery-id"))
+						Expect(req.Header.Get("X-Webhook-Event")).To(Equal("file.uploaded"))
+						Expect(req.Header.Get("X-Webhook-Timestamp")).To(Equal("1660000000"))
+						Expect(req.Header.Get("X-Webhook-Signature")).To(Equal(
+							"v1=" + notifying.Sign("secret", "1660000000", `{"id":"event-id"}`),
+						))
+						return response(204), nil
+					}).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					UpdateDelivery(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(&repository.UpdateDeliveryResult{UpdatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(Equal(&notifying.DispatchResult{Delivered: 1}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("delivery is rejected", func() {
+			It("should reschedule with backoff", func() {
+				delivery.Attempt = 1
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListDueDeliveryResult{Items: []repository.DueDelivery{delivery}}, nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					ClaimDelivery(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimDeliveryResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				httpClient.
+					EXPECT().
+					Do(gomock.Any()).
+					Return(response(500), nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					UpdateDelivery(gomock.Eq(ctx), gomock.Eq(repository.UpdateDeliveryParam{
+						Id:            "delivery-id",
+						Status:        repository.DELIVERY_PENDING,
+						Attempt:       2,
+						NextAttemptAt: currentTs.Add(15 * time.Second),
+						ResponseCode:  500,
+						LastError:     "unexpected response status: 500",
+					})).
+					Return(&repository.UpdateDeliveryResult{UpdatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(Equal(&notifying.DispatchResult{Retried: 1}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("receiver is unreachable", func() {
+			It("should reschedule with base backoff", func() {
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListDueDeliveryResult{Items: []repository.DueDelivery{delivery}}, nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					ClaimDelivery(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimDeliveryResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				httpClient.
+					EXPECT().
+					Do(gomock.Any()).
+					Return(nil, fmt.Errorf("connection refused")).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					UpdateDelivery(gomock.Eq(ctx), gomock.Eq(repository.UpdateDeliveryParam{
+						Id:            "delivery-id",
+						Status:        repository.DELIVERY_PENDING,
+						Attempt:       1,
+						NextAttemptAt: currentTs.Add(10 * time.Second),
+						LastError:     "connection refused",
+					})).
+					Return(&repository.UpdateDeliveryResult{UpdatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(Equal(&notifying.DispatchResult{Retried: 1}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("maximum attempts is reached", func() {
+			It("should dead letter the delivery", func() {
+				delivery.Attempt = 2
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListDueDeliveryResult{Items: []repository.DueDelivery{delivery}}, nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					ClaimDelivery(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimDeliveryResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				httpClient.
+					EXPECT().
+					Do(gomock.Any()).
+					Return(response(410), nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					UpdateDelivery(gomock.Eq(ctx), gomock.Eq(repository.UpdateDeliveryParam{
+						Id:            "delivery-id",
+						Status:        repository.DELIVERY_DEAD,
+						Attempt:       3,
+						NextAttemptAt: currentTs.Add(-time.Second),
+						ResponseCode:  410,
+						LastError:     "unexpected response status: 410",
+					})).
+					Return(&repository.UpdateDeliveryResult{UpdatedAt: currentTs}, nil).
+					Times(1)
+				log.
+					EXPECT().
+					WithFields(gomock.Eq(map[string]interface{}{
+						"event":           "webhook.dead",
+						"delivery_id":     "delivery-id",
+						"subscription_id": "subscription-id",
+						"event_id":        "event-id",
+					})).
+					Return(log).
+					Times(1)
+				log.
+					EXPECT().
+					Warn(gomock.Eq("Webhook delivery is dead lettered")).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(Equal(&notifying.DispatchResult{Dead: 1}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed update delivery", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListDueDeliveryResult{Items: []repository.DueDelivery{delivery}}, nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					ClaimDelivery(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimDeliveryResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				httpClient.
+					EXPECT().
+					Do(gomock.Any()).
+					Return(response(204), nil).
+					Times(1)
+				webhookRepo.
+					EXPECT().
+					UpdateDelivery(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := d.Dispatch(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+	})
+
+	Context("Start function", Label("unit"), func() {
+		var (
+			webhookRepo *mock.MockWebhookRepository
+			log         *mock.MockLogger
+			d           notifying.Dispatcher
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			webhookRepo = mock.NewMockWebhookRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			d, _ = notifying.NewDispatcher(notifying.NewDispatcherParam{
+				WebhookRepo: webhookRepo,
+				Logger:      log,
+				Interval:    time.Millisecond,
+			})
+		})
+
+		When("dispatcher is started", func() {
+			It("should dispatch periodically until it is stopped", func() {
+				dispatched := make(chan struct{}, 1)
+				webhookRepo.
+					EXPECT().
+					ListDueDelivery(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p repository.ListDueDeliveryParam) (*repository.ListDueDeliveryResult, error) {
+						select {
+						case dispatched <- struct{}{}:
+						default:
+						}
+						return nil, fmt.Errorf("db error")
+					}).
+					MinTimes(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed dispatch webhook: %s"), gomock.Eq("db error")).
+					MinTimes(1)
+
+				err := d.Start()
+				Expect(err).To(BeNil())
+
+				Eventually(dispatched).Should(Receive())
+
+				err = d.Stop()
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("dispatcher is already started", func() {
+			It("should return error", func() {
+				webhookRepo.EXPECT().ListDueDelivery(gomock.Any(), gomock.Any()).Return(&repository.ListDueDeliveryResult{}, nil).AnyTimes()
+
+				err := d.Start()
+				Expect(err).To(BeNil())
+
+				err = d.Start()
+				Expect(err).To(Equal(fmt.Errorf("dispatcher is already started")))
+
+				Expect(d.Stop()).To(BeNil())
+			})
+		})
+
+		When("dispatcher is not started", func() {
+			It("should stop without error", func() {
+				err := d.Stop()
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,8 @@
+package notifying
+
+import "errors"
+
+var (
+	ErrorResourceNotFound  = errors.New("resource not found")
+	ErrorAddressNotAllowed = errors.New("destination address is not allowed")
+)
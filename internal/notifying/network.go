@@ -0,0 +1,103 @@
+package notifying
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var reservedNetworks = parseCidrs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func parseCidrs(cidrs ...string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func IsPublicAddress(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// the host name is resolved when it is dialed, so only the literal address is checked
+func isPublicUrl(u *url.URL) bool {
+	host := u.Hostname()
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || IsPublicAddress(ip)
+}
+
+type NewHttpClientParam struct {
+	Timeout             time.Duration
+	AllowPrivateNetwork bool
+}
+
+// the webhook url is given by the client, so the resolved address is checked when it is dialed
+// and the redirect is not followed, the service must not be usable to reach the internal host
+func NewHttpClient(p NewHttpClientParam) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: p.Timeout,
+	}
+	if !p.AllowPrivateNetwork {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !IsPublicAddress(ip) {
+				return ErrorAddressNotAllowed
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: p.Timeout,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	client := &http.Client{
+		Timeout:   p.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return client
+}
@@ -0,0 +1,102 @@
+package notifying_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-seidon/local/internal/notifying"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network", func() {
+	Context("IsPublicAddress function", Label("unit"), func() {
+		When("address is not public", func() {
+			It("should return false", func() {
+				for _, ip := range []string{
+					"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1",
+					"169.254.169.254", "100.64.0.1", "0.0.0.0",
+					"::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1",
+				} {
+					Expect(notifying.IsPublicAddress(net.ParseIP(ip))).To(BeFalse(), ip)
+				}
+			})
+		})
+
+		When("address is public", func() {
+			It("should return true", func() {
+				for _, ip := range []string{"93.184.216.34", "8.8.8.8", "2606:4700::1111"} {
+					Expect(notifying.IsPublicAddress(net.ParseIP(ip))).To(BeTrue(), ip)
+				}
+			})
+		})
+	})
+
+	Context("NewHttpClient function", Label("unit"), func() {
+		var (
+			server *httptest.Server
+		)
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/hook", http.StatusFound)
+			})
+			server = httptest.NewServer(mux)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		When("destination is within the private network", func() {
+			It("should return error", func() {
+				client := notifying.NewHttpClient(notifying.NewHttpClientParam{
+					Timeout: time.Second,
+				})
+
+				res, err := client.Get(server.URL + "/hook")
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, notifying.ErrorAddressNotAllowed)).To(BeTrue())
+			})
+		})
+
+		When("private network is allowed", func() {
+			It("should send the request", func() {
+				client := notifying.NewHttpClient(notifying.NewHttpClientParam{
+					Timeout:             time.Second,
+					AllowPrivateNetwork: true,
+				})
+
+				res, err := client.Get(server.URL + "/hook")
+
+				Expect(err).To(BeNil())
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				res.Body.Close()
+			})
+		})
+
+		When("destination redirects the request", func() {
+			It("should not follow the redirect", func() {
+				client := notifying.NewHttpClient(notifying.NewHttpClientParam{
+					Timeout:             time.Second,
+					AllowPrivateNetwork: true,
+				})
+
+				res, err := client.Get(server.URL + "/redirect")
+
+				Expect(err).To(BeNil())
+				Expect(res.StatusCode).To(Equal(http.StatusFound))
+				res.Body.Close()
+			})
+		})
+	})
+})
@@ -0,0 +1,269 @@
+package notifying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+const (
+	DEFAULT_RELAY_INTERVAL  = 2 * time.Second
+	DEFAULT_RELAY_BATCH     = 100
+	DEFAULT_RELAY_LEASE     = time.Minute
+	DEFAULT_RELAY_RETENTION = 7 * 24 * time.Hour
+)
+
+// the outbox event is published to every sink at least once,
+// the event is retried on all the sinks when one of them is failed
+type Relay interface {
+	Start() error
+	Stop() error
+	Relay(ctx context.Context) (*RelayResult, error)
+}
+
+type RelayResult struct {
+	Published int
+	Retried   int
+	Skipped   int
+	Pruned    int64
+}
+
+type relay struct {
+	outboxRepo  repository.OutboxRepository
+	sinks       []Sink
+	clock       datetime.Clock
+	log         logging.Logger
+	interval    time.Duration
+	batchSize   int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	lease       time.Duration
+	retention   time.Duration
+
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	stopped chan struct{}
+}
+
+func (r *relay) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopCh != nil {
+		return fmt.Errorf("relay is already started")
+	}
+	r.stopCh = make(chan struct{})
+	r.stopped = make(chan struct{})
+
+	go r.run(r.stopCh, r.stopped)
+	return nil
+}
+
+func (r *relay) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopCh == nil {
+		return nil
+	}
+	close(r.stopCh)
+	<-r.stopped
+	r.stopCh = nil
+	r.stopped = nil
+	return nil
+}
+
+func (r *relay) run(stopCh chan struct{}, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			_, err := r.Relay(context.Background())
+			if err != nil {
+				r.log.Errorf("Failed relay outbox event: %s", err.Error())
+			}
+		}
+	}
+}
+
+func (r *relay) Relay(ctx context.Context) (*RelayResult, error) {
+	currentTs := r.clock.Now()
+
+	events, err := r.outboxRepo.ListPendingEvent(ctx, repository.ListPendingEventParam{
+		DueAt: currentTs,
+		Limit: r.batchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RelayResult{}
+	for _, event := range events.Items {
+		_, err := r.outboxRepo.ClaimEvent(ctx, repository.ClaimEventParam{
+			Id:            event.Id,
+			NextAttemptAt: event.NextAttemptAt,
+			LeaseUntil:    r.clock.Now().Add(r.lease),
+		})
+		if errors.Is(err, repository.ErrorRecordChanged) {
+			res.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = r.send(ctx, event)
+		if err != nil {
+			attempt := event.Attempt + 1
+			_, err = r.outboxRepo.RetryEvent(ctx, repository.RetryEventParam{
+				Id:            event.Id,
+				Attempt:       attempt,
+				NextAttemptAt: r.clock.Now().Add(backoff(r.backoffBase, r.backoffMax, attempt)),
+				LastError:     truncate(err.Error(), MAX_ERROR_SIZE),
+			})
+			if err != nil {
+				return nil, err
+			}
+			res.Retried++
+			continue
+		}
+
+		_, err = r.outboxRepo.PublishEvent(ctx, repository.PublishEventParam{
+			Id: event.Id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		res.Published++
+	}
+
+	pruned, err := r.outboxRepo.DeletePublishedEvent(ctx, repository.DeletePublishedEventParam{
+		PublishedBefore: currentTs.Add(-r.retention),
+		Limit:           r.batchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Pruned = pruned.TotalDeleted
+	return res, nil
+}
+
+func (r *relay) send(ctx context.Context, event repository.OutboxEvent) error {
+	e := newOutboxEvent(event)
+	for _, sink := range r.sinks {
+		err := sink.Send(ctx, e)
+		if err != nil {
+			return fmt.Errorf("%s: %s", sink.Name(), err.Error())
+		}
+	}
+	return nil
+}
+
+func newOutboxEvent(event repository.OutboxEvent) Event {
+	return Event{
+		Id:       event.IdempotencyKey,
+		Sequence: event.Id,
+		Type:     event.EventType,
+		ClientId: event.ClientId,
+		File: FileData{
+			Id:        event.FileId,
+			Name:      event.Name,
+			Mimetype:  event.Mimetype,
+			Extension: event.Extension,
+			Size:      event.Size,
+		},
+		OccurredAt: event.CreatedAt,
+	}
+}
+
+type NewRelayParam struct {
+	OutboxRepo  repository.OutboxRepository
+	Sinks       []Sink
+	Logger      logging.Logger
+	Clock       datetime.Clock
+	Interval    time.Duration
+	BatchSize   int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Lease       time.Duration
+	Retention   time.Duration
+}
+
+func NewRelay(p NewRelayParam) (*relay, error) {
+	if p.OutboxRepo == nil {
+		return nil, fmt.Errorf("outbox repo is not specified")
+	}
+	if len(p.Sinks) == 0 {
+		return nil, fmt.Errorf("sinks are not specified")
+	}
+	for _, sink := range p.Sinks {
+		if sink == nil {
+			return nil, fmt.Errorf("invalid sink specified")
+		}
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+	if p.Interval < 0 || p.BatchSize < 0 || p.BackoffBase < 0 ||
+		p.BackoffMax < 0 || p.Lease < 0 || p.Retention < 0 {
+		return nil, fmt.Errorf("invalid relay parameter")
+	}
+
+	interval := DEFAULT_RELAY_INTERVAL
+	if p.Interval > 0 {
+		interval = p.Interval
+	}
+	batchSize := DEFAULT_RELAY_BATCH
+	if p.BatchSize > 0 {
+		batchSize = p.BatchSize
+	}
+	backoffBase := DEFAULT_BACKOFF_BASE
+	if p.BackoffBase > 0 {
+		backoffBase = p.BackoffBase
+	}
+	backoffMax := DEFAULT_BACKOFF_MAX
+	if p.BackoffMax > 0 {
+		backoffMax = p.BackoffMax
+	}
+	if backoffMax < backoffBase {
+		backoffMax = backoffBase
+	}
+	lease := DEFAULT_RELAY_LEASE
+	if p.Lease > 0 {
+		lease = p.Lease
+	}
+	retention := DEFAULT_RELAY_RETENTION
+	if p.Retention > 0 {
+		retention = p.Retention
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	r := &relay{
+		outboxRepo:  p.OutboxRepo,
+		sinks:       p.Sinks,
+		clock:       clock,
+		log:         p.Logger,
+		interval:    interval,
+		batchSize:   batchSize,
+		backoffBase: backoffBase,
+		backoffMax:  backoffMax,
+		lease:       lease,
+		retention:   retention,
+	}
+	return r, nil
+}
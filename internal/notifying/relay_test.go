@@ -0,0 +1,491 @@
+package notifying_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Relay Service", func() {
+	Context("NewRelay function", Label("unit"), func() {
+		var (
+			p notifying.NewRelayParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = notifying.NewRelayParam{
+				OutboxRepo: mock.NewMockOutboxRepository(ctrl),
+				Sinks:      []notifying.Sink{mock.NewMockSink(ctrl)},
+				Logger:     mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := notifying.NewRelay(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("optional parameters are specified", func() {
+			It("should return result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				p.Clock = mock.NewMockClock(ctrl)
+				p.Interval = time.Second
+				p.BatchSize = 10
+				p.BackoffBase = time.Minute
+				p.BackoffMax = time.Second
+				p.Lease = time.Second
+				p.Retention = time.Hour
+				res, err := notifying.NewRelay(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("outbox repo is not specified", func() {
+			It("should return error", func() {
+				p.OutboxRepo = nil
+				res, err := notifying.NewRelay(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("outbox repo is not specified")))
+			})
+		})
+
+		When("sinks are not specified", func() {
+			It("should return error", func() {
+				p.Sinks = nil
+				res, err := notifying.NewRelay(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("sinks are not specified")))
+			})
+		})
+
+		When("sink is nil", func() {
+			It("should return error", func() {
+				p.Sinks = []notifying.Sink{nil}
+				res, err := notifying.NewRelay(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid sink specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := notifying.NewRelay(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+
+		When("parameter is negative", func() {
+			It("should return error", func() {
+				p.Lease = -1
+				res, err := notifying.NewRelay(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid relay parameter")))
+			})
+		})
+	})
+
+	Context("Relay function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			outboxRepo  *mock.MockOutboxRepository
+			webhookSink *mock.MockSink
+			logSink     *mock.MockSink
+			clock       *mock.MockClock
+			r           notifying.Relay
+			event       repository.OutboxEvent
+			sinkEvent   notifying.Event
+			claimParam  repository.ClaimEventParam
+			pruneParam  repository.DeletePublishedEventParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Unix(1660000000, 0)
+			ctrl := gomock.NewController(GinkgoT())
+			outboxRepo = mock.NewMockOutboxRepository(ctrl)
+			webhookSink = mock.NewMockSink(ctrl)
+			logSink = mock.NewMockSink(ctrl)
+			clock = mock.NewMockClock(ctrl)
+			r, _ = notifying.NewRelay(notifying.NewRelayParam{
+				OutboxRepo:  outboxRepo,
+				Sinks:       []notifying.Sink{webhookSink, logSink},
+				Clock:       clock,
+				Logger:      mock.NewMockLogger(ctrl),
+				BatchSize:   10,
+				BackoffBase: 10 * time.Second,
+				BackoffMax:  15 * time.Second,
+				Lease:       30 * time.Second,
+				Retention:   time.Hour,
+			})
+			event = repository.OutboxEvent{
+				Id:             1,
+				IdempotencyKey: "file.uploaded:file-id",
+				EventType:      "file.uploaded",
+				FileId:         "file-id",
+				ClientId:       "client-id",
+				Name:           "dolphin",
+				Mimetype:       "image/jpeg",
+				Extension:      "jpg",
+				Size:           200,
+				Attempt:        1,
+				NextAttemptAt:  currentTs.Add(-time.Second),
+				CreatedAt:      currentTs.Add(-time.Minute),
+			}
+			sinkEvent = notifying.Event{
+				Id:       "file.uploaded:file-id",
+				Sequence: 1,
+				Type:     "file.uploaded",
+				ClientId: "client-id",
+				File: notifying.FileData{
+					Id:        "file-id",
+					Name:      "dolphin",
+					Mimetype:  "image/jpeg",
+					Extension: "jpg",
+					Size:      200,
+				},
+				OccurredAt: currentTs.Add(-time.Minute),
+			}
+			claimParam = repository.ClaimEventParam{
+				Id:            1,
+				NextAttemptAt: currentTs.Add(-time.Second),
+				LeaseUntil:    currentTs.Add(30 * time.Second),
+			}
+			pruneParam = repository.DeletePublishedEventParam{
+				PublishedBefore: currentTs.Add(-time.Hour),
+				Limit:           10,
+			}
+
+			clock.EXPECT().Now().Return(currentTs).AnyTimes()
+			webhookSink.EXPECT().Name().Return("webhook").AnyTimes()
+			logSink.EXPECT().Name().Return("log").AnyTimes()
+		})
+
+		When("failed list pending event", func() {
+			It("should return error", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Eq(repository.ListPendingEventParam{
+						DueAt: currentTs,
+						Limit: 10,
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("event is claimed by the other relay", func() {
+			It("should skip the event", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListPendingEventResult{Items: []repository.OutboxEvent{event}}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ClaimEvent(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(nil, repository.ErrorRecordChanged).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					DeletePublishedEvent(gomock.Eq(ctx), gomock.Eq(pruneParam)).
+					Return(&repository.DeletePublishedEventResult{}, nil).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(Equal(&notifying.RelayResult{Skipped: 1}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed claim event", func() {
+			It("should return error", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListPendingEventResult{Items: []repository.OutboxEvent{event}}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ClaimEvent(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("event is sent to all sinks", func() {
+			It("should mark the event published", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListPendingEventResult{Items: []repository.OutboxEvent{event}}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ClaimEvent(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimEventResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				webhookSink.
+					EXPECT().
+					Send(gomock.Eq(ctx), gomock.Eq(sinkEvent)).
+					Return(nil).
+					Times(1)
+				logSink.
+					EXPECT().
+					Send(gomock.Eq(ctx), gomock.Eq(sinkEvent)).
+					Return(nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					PublishEvent(gomock.Eq(ctx), gomock.Eq(repository.PublishEventParam{Id: 1})).
+					Return(&repository.PublishEventResult{PublishedAt: currentTs}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					DeletePublishedEvent(gomock.Eq(ctx), gomock.Eq(pruneParam)).
+					Return(&repository.DeletePublishedEventResult{TotalDeleted: 3}, nil).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(Equal(&notifying.RelayResult{Published: 1, Pruned: 3}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed send event to the sink", func() {
+			It("should reschedule with backoff", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListPendingEventResult{Items: []repository.OutboxEvent{event}}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ClaimEvent(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimEventResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				webhookSink.
+					EXPECT().
+					Send(gomock.Eq(ctx), gomock.Eq(sinkEvent)).
+					Return(fmt.Errorf("db error")).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					RetryEvent(gomock.Eq(ctx), gomock.Eq(repository.RetryEventParam{
+						Id:            1,
+						Attempt:       2,
+						NextAttemptAt: currentTs.Add(15 * time.Second),
+						LastError:     "webhook: db error",
+					})).
+					Return(&repository.RetryEventResult{UpdatedAt: currentTs}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					DeletePublishedEvent(gomock.Eq(ctx), gomock.Eq(pruneParam)).
+					Return(&repository.DeletePublishedEventResult{}, nil).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(Equal(&notifying.RelayResult{Retried: 1}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed retry event", func() {
+			It("should return error", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListPendingEventResult{Items: []repository.OutboxEvent{event}}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ClaimEvent(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimEventResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				webhookSink.
+					EXPECT().
+					Send(gomock.Eq(ctx), gomock.Eq(sinkEvent)).
+					Return(nil).
+					Times(1)
+				logSink.
+					EXPECT().
+					Send(gomock.Eq(ctx), gomock.Eq(sinkEvent)).
+					Return(fmt.Errorf("write error")).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					RetryEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed publish event", func() {
+			It("should return error", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListPendingEventResult{Items: []repository.OutboxEvent{event}}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ClaimEvent(gomock.Eq(ctx), gomock.Eq(claimParam)).
+					Return(&repository.ClaimEventResult{ClaimedAt: currentTs}, nil).
+					Times(1)
+				webhookSink.
+					EXPECT().
+					Send(gomock.Eq(ctx), gomock.Eq(sinkEvent)).
+					Return(nil).
+					Times(1)
+				logSink.
+					EXPECT().
+					Send(gomock.Eq(ctx), gomock.Eq(sinkEvent)).
+					Return(nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					PublishEvent(gomock.Eq(ctx), gomock.Eq(repository.PublishEventParam{Id: 1})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed delete published event", func() {
+			It("should return error", func() {
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListPendingEventResult{}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					DeletePublishedEvent(gomock.Eq(ctx), gomock.Eq(pruneParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := r.Relay(ctx)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+	})
+
+	Context("Start function", Label("unit"), func() {
+		var (
+			outboxRepo *mock.MockOutboxRepository
+			log        *mock.MockLogger
+			r          notifying.Relay
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			outboxRepo = mock.NewMockOutboxRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			r, _ = notifying.NewRelay(notifying.NewRelayParam{
+				OutboxRepo: outboxRepo,
+				Sinks:      []notifying.Sink{mock.NewMockSink(ctrl)},
+				Logger:     log,
+				Interval:   time.Millisecond,
+			})
+		})
+
+		When("relay is started", func() {
+			It("should relay periodically until it is stopped", func() {
+				relayed := make(chan struct{}, 1)
+				outboxRepo.
+					EXPECT().
+					ListPendingEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p repository.ListPendingEventParam) (*repository.ListPendingEventResult, error) {
+						select {
+						case relayed <- struct{}{}:
+						default:
+						}
+						return nil, fmt.Errorf("db error")
+					}).
+					MinTimes(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed relay outbox event: %s"), gomock.Eq("db error")).
+					MinTimes(1)
+
+				err := r.Start()
+				Expect(err).To(BeNil())
+
+				Eventually(relayed).Should(Receive())
+
+				err = r.Stop()
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("relay is already started", func() {
+			It("should return error", func() {
+				outboxRepo.EXPECT().ListPendingEvent(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("db error")).AnyTimes()
+				log.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+
+				err := r.Start()
+				Expect(err).To(BeNil())
+
+				err = r.Start()
+				Expect(err).To(Equal(fmt.Errorf("relay is already started")))
+
+				Expect(r.Stop()).To(BeNil())
+			})
+		})
+
+		When("relay is not started", func() {
+			It("should stop without error", func() {
+				err := r.Stop()
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
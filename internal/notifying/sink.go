@@ -0,0 +1,208 @@
+package notifying
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-seidon/local/internal/serialization"
+)
+
+const (
+	SINK_WEBHOOK = "webhook"
+	SINK_LOG     = "log"
+	SINK_BROKER  = "broker"
+
+	DEFAULT_BROKER_BUFFER     = 100
+	DEFAULT_BROKER_DEDUP_SIZE = 10000
+)
+
+type Event struct {
+	Id         string
+	Sequence   int64
+	Type       string
+	ClientId   string
+	File       FileData
+	OccurredAt time.Time
+}
+
+// the event is sent at least once, the sink must discard the duplicate event by the event id
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, e Event) error
+}
+
+func IsValidSink(name string) bool {
+	switch name {
+	case SINK_WEBHOOK, SINK_LOG, SINK_BROKER:
+		return true
+	}
+	return false
+}
+
+type webhookSink struct {
+	publisher Publisher
+}
+
+func (s *webhookSink) Name() string {
+	return SINK_WEBHOOK
+}
+
+func (s *webhookSink) Send(ctx context.Context, e Event) error {
+	_, err := s.publisher.Publish(ctx, PublishParam{
+		EventId:    e.Id,
+		EventType:  e.Type,
+		ClientId:   e.ClientId,
+		File:       e.File,
+		OccurredAt: e.OccurredAt,
+	})
+	return err
+}
+
+func NewWebhookSink(publisher Publisher) (*webhookSink, error) {
+	if publisher == nil {
+		return nil, fmt.Errorf("publisher is not specified")
+	}
+	s := &webhookSink{
+		publisher: publisher,
+	}
+	return s, nil
+}
+
+type logSink struct {
+	writer     io.Writer
+	serializer serialization.Serializer
+
+	mu sync.Mutex
+}
+
+func (s *logSink) Name() string {
+	return SINK_LOG
+}
+
+func (s *logSink) Send(ctx context.Context, e Event) error {
+	payload, err := s.serializer.Marshal(newEventPayload(e))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.writer.Write(append(payload, '\n'))
+	return err
+}
+
+type NewLogSinkParam struct {
+	Serializer serialization.Serializer
+	Writer     io.Writer
+}
+
+func NewLogSink(p NewLogSinkParam) (*logSink, error) {
+	if p.Serializer == nil {
+		return nil, fmt.Errorf("serializer is not specified")
+	}
+
+	writer := p.Writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	s := &logSink{
+		writer:     writer,
+		serializer: p.Serializer,
+	}
+	return s, nil
+}
+
+// in-memory stand-in of the message broker, the event is fanned out to the subscribers
+// of the current process only, the event is dropped when the subscriber is not keeping up
+type Broker interface {
+	Sink
+	Subscribe(ctx context.Context) <-chan Event
+}
+
+type broker struct {
+	buffer    int
+	dedupSize int
+
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	sent        map[string]struct{}
+	sentOrder   []string
+}
+
+func (b *broker) Name() string {
+	return SINK_BROKER
+}
+
+func (b *broker) Send(ctx context.Context, e Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.sent[e.Id]; ok {
+		return nil
+	}
+	if len(b.sentOrder) >= b.dedupSize {
+		delete(b.sent, b.sentOrder[0])
+		b.sentOrder = b.sentOrder[1:]
+	}
+	b.sent[e.Id] = struct{}{}
+	b.sentOrder = append(b.sentOrder, e.Id)
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *broker) Subscribe(ctx context.Context) <-chan Event {
+	ch := make(chan Event, b.buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+	return ch
+}
+
+type NewBrokerParam struct {
+	Buffer    int
+	DedupSize int
+}
+
+func NewBroker(p NewBrokerParam) (*broker, error) {
+	if p.Buffer < 0 || p.DedupSize < 0 {
+		return nil, fmt.Errorf("invalid broker parameter")
+	}
+
+	buffer := DEFAULT_BROKER_BUFFER
+	if p.Buffer > 0 {
+		buffer = p.Buffer
+	}
+	dedupSize := DEFAULT_BROKER_DEDUP_SIZE
+	if p.DedupSize > 0 {
+		dedupSize = p.DedupSize
+	}
+
+	b := &broker{
+		buffer:      buffer,
+		dedupSize:   dedupSize,
+		subscribers: map[chan Event]struct{}{},
+		sent:        map[string]struct{}{},
+		sentOrder:   []string{},
+	}
+	return b, nil
+}
@@ -0,0 +1,278 @@
+package notifying_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/serialization"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sink Package", func() {
+	var (
+		event notifying.Event
+	)
+
+	BeforeEach(func() {
+		event = notifying.Event{
+			Id:       "file.uploaded:file-id",
+			Type:     "file.uploaded",
+			ClientId: "client-id",
+			File: notifying.FileData{
+				Id:        "file-id",
+				Name:      "dolphin",
+				Mimetype:  "image/jpeg",
+				Extension: "jpg",
+				Size:      200,
+			},
+			OccurredAt: time.UnixMilli(1660000000000),
+		}
+	})
+
+	Context("IsValidSink function", Label("unit"), func() {
+		When("sink is supported", func() {
+			It("should return true", func() {
+				Expect(notifying.IsValidSink("webhook")).To(BeTrue())
+				Expect(notifying.IsValidSink("log")).To(BeTrue())
+				Expect(notifying.IsValidSink("broker")).To(BeTrue())
+			})
+		})
+
+		When("sink is not supported", func() {
+			It("should return false", func() {
+				Expect(notifying.IsValidSink("kafka")).To(BeFalse())
+			})
+		})
+	})
+
+	Context("Webhook sink", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			publisher *mock.MockWebhook
+			s         notifying.Sink
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			publisher = mock.NewMockWebhook(ctrl)
+			s, _ = notifying.NewWebhookSink(publisher)
+		})
+
+		When("publisher is not specified", func() {
+			It("should return error", func() {
+				res, err := notifying.NewWebhookSink(nil)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("publisher is not specified")))
+			})
+		})
+
+		When("event is published", func() {
+			It("should publish with the event id", func() {
+				publisher.
+					EXPECT().
+					Publish(gomock.Eq(ctx), gomock.Eq(notifying.PublishParam{
+						EventId:    "file.uploaded:file-id",
+						EventType:  "file.uploaded",
+						ClientId:   "client-id",
+						File:       event.File,
+						OccurredAt: event.OccurredAt,
+					})).
+					Return(&notifying.PublishResult{EventId: "file.uploaded:file-id"}, nil).
+					Times(1)
+
+				err := s.Send(ctx, event)
+
+				Expect(s.Name()).To(Equal("webhook"))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed publish event", func() {
+			It("should return error", func() {
+				publisher.
+					EXPECT().
+					Publish(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				err := s.Send(ctx, event)
+
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+	})
+
+	Context("Log sink", Label("unit"), func() {
+		var (
+			ctx    context.Context
+			writer *bytes.Buffer
+			s      notifying.Sink
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			writer = &bytes.Buffer{}
+			s, _ = notifying.NewLogSink(notifying.NewLogSinkParam{
+				Serializer: serialization.NewJsonSerializer(),
+				Writer:     writer,
+			})
+		})
+
+		When("serializer is not specified", func() {
+			It("should return error", func() {
+				res, err := notifying.NewLogSink(notifying.NewLogSinkParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("serializer is not specified")))
+			})
+		})
+
+		When("writer is not specified", func() {
+			It("should return result", func() {
+				res, err := notifying.NewLogSink(notifying.NewLogSinkParam{
+					Serializer: serialization.NewJsonSerializer(),
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("event is sent", func() {
+			It("should write a json line", func() {
+				err := s.Send(ctx, event)
+				Expect(err).To(BeNil())
+				err = s.Send(ctx, event)
+				Expect(err).To(BeNil())
+
+				line := `{"id":"file.uploaded:file-id","type":"file.uploaded","created_at":1660000000000,` +
+					`"data":{"id":"file-id","name":"dolphin","mimetype":"image/jpeg","extension":"jpg","size":200,"client_id":"client-id"}}` + "\n"
+				Expect(s.Name()).To(Equal("log"))
+				Expect(writer.String()).To(Equal(line + line))
+			})
+		})
+
+		When("failed marshal event", func() {
+			It("should return error", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				serializer := mock.NewMockSerializer(ctrl)
+				serializer.
+					EXPECT().
+					Marshal(gomock.Any()).
+					Return(nil, fmt.Errorf("marshal error")).
+					Times(1)
+				s, _ = notifying.NewLogSink(notifying.NewLogSinkParam{
+					Serializer: serializer,
+					Writer:     writer,
+				})
+
+				err := s.Send(ctx, event)
+
+				Expect(err).To(Equal(fmt.Errorf("marshal error")))
+				Expect(writer.Len()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("Broker sink", Label("unit"), func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			b      notifying.Broker
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			b, _ = notifying.NewBroker(notifying.NewBrokerParam{
+				Buffer:    1,
+				DedupSize: 1,
+			})
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		When("parameter is negative", func() {
+			It("should return error", func() {
+				res, err := notifying.NewBroker(notifying.NewBrokerParam{Buffer: -1})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid broker parameter")))
+			})
+		})
+
+		When("event is sent", func() {
+			It("should fan out to the subscribers", func() {
+				ch1 := b.Subscribe(ctx)
+				ch2 := b.Subscribe(ctx)
+
+				err := b.Send(ctx, event)
+
+				Expect(b.Name()).To(Equal("broker"))
+				Expect(err).To(BeNil())
+				Expect(ch1).To(Receive(Equal(event)))
+				Expect(ch2).To(Receive(Equal(event)))
+			})
+		})
+
+		When("event is sent twice", func() {
+			It("should discard the duplicate event", func() {
+				ch := b.Subscribe(ctx)
+
+				Expect(b.Send(ctx, event)).To(BeNil())
+				Expect(ch).To(Receive(Equal(event)))
+				Expect(b.Send(ctx, event)).To(BeNil())
+				Expect(ch).ToNot(Receive())
+			})
+		})
+
+		When("duplicate event is evicted", func() {
+			It("should send the event again", func() {
+				ch := b.Subscribe(ctx)
+				other := event
+				other.Id = "file.deleted:file-id"
+
+				Expect(b.Send(ctx, event)).To(BeNil())
+				Expect(ch).To(Receive(Equal(event)))
+				Expect(b.Send(ctx, other)).To(BeNil())
+				Expect(ch).To(Receive(Equal(other)))
+				Expect(b.Send(ctx, event)).To(BeNil())
+				Expect(ch).To(Receive(Equal(event)))
+			})
+		})
+
+		When("subscriber is not keeping up", func() {
+			It("should drop the event", func() {
+				ch := b.Subscribe(ctx)
+				other := event
+				other.Id = "file.deleted:file-id"
+
+				Expect(b.Send(ctx, event)).To(BeNil())
+				Expect(b.Send(ctx, other)).To(BeNil())
+				Expect(ch).To(Receive(Equal(event)))
+				Expect(ch).ToNot(Receive())
+			})
+		})
+
+		When("subscription context is done", func() {
+			It("should close the channel", func() {
+				subCtx, subCancel := context.WithCancel(ctx)
+				ch := b.Subscribe(subCtx)
+
+				subCancel()
+
+				Eventually(ch).Should(BeClosed())
+			})
+		})
+	})
+})
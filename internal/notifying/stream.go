@@ -0,0 +1,133 @@
+package notifying
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/repository"
+)
+
+const (
+	DEFAULT_STREAM_INTERVAL = 2 * time.Second
+	DEFAULT_STREAM_BATCH    = 100
+)
+
+// every subscriber reads the outbox after its last received sequence, so the events of every replica
+// are streamed and the subscriber is able to resume on any replica, the events of the owned files
+// and the files shared to the client with read permission are streamed,
+// the events older than the outbox retention are not replayed
+type EventStream interface {
+	Subscribe(ctx context.Context, p SubscribeParam) (*SubscribeResult, error)
+}
+
+type SubscribeParam struct {
+	ClientId      string
+	AfterSequence int64
+}
+
+type SubscribeResult struct {
+	// closed when the context is done or the outbox is failed to be read,
+	// so the subscriber is able to resume from the last received sequence
+	Events <-chan Event
+}
+
+type eventStream struct {
+	outboxRepo repository.OutboxRepository
+	interval   time.Duration
+	batchSize  int
+}
+
+func (s *eventStream) Subscribe(ctx context.Context, p SubscribeParam) (*SubscribeResult, error) {
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if p.AfterSequence < 0 {
+		return nil, fmt.Errorf("invalid sequence parameter")
+	}
+
+	afterSequence := p.AfterSequence
+	if afterSequence == 0 {
+		last, err := s.outboxRepo.FindLastEvent(ctx, repository.FindLastEventParam{})
+		if err != nil {
+			return nil, err
+		}
+		afterSequence = last.Id
+	}
+
+	ch := make(chan Event)
+	go s.poll(ctx, p.ClientId, afterSequence, ch)
+
+	res := &SubscribeResult{
+		Events: ch,
+	}
+	return res, nil
+}
+
+// the next page is read immediately when the page is full, otherwise after the interval
+func (s *eventStream) poll(ctx context.Context, clientId string, afterSequence int64, ch chan<- Event) {
+	defer close(ch)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := s.outboxRepo.ListClientEvent(ctx, repository.ListClientEventParam{
+			ClientId: clientId,
+			AfterId:  afterSequence,
+			Limit:    s.batchSize,
+		})
+		if err != nil {
+			return
+		}
+
+		for _, event := range events.Items {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- newOutboxEvent(event):
+				afterSequence = event.Id
+			}
+		}
+		if len(events.Items) == s.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type NewEventStreamParam struct {
+	OutboxRepo repository.OutboxRepository
+	Interval   time.Duration
+	BatchSize  int
+}
+
+func NewEventStream(p NewEventStreamParam) (*eventStream, error) {
+	if p.OutboxRepo == nil {
+		return nil, fmt.Errorf("outbox repo is not specified")
+	}
+	if p.Interval < 0 || p.BatchSize < 0 {
+		return nil, fmt.Errorf("invalid event stream parameter")
+	}
+
+	interval := DEFAULT_STREAM_INTERVAL
+	if p.Interval > 0 {
+		interval = p.Interval
+	}
+	batchSize := DEFAULT_STREAM_BATCH
+	if p.BatchSize > 0 {
+		batchSize = p.BatchSize
+	}
+
+	s := &eventStream{
+		outboxRepo: p.OutboxRepo,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+	return s, nil
+}
@@ -0,0 +1,244 @@
+package notifying_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Event Stream", func() {
+	Context("NewEventStream function", Label("unit"), func() {
+		When("outbox repo is not specified", func() {
+			It("should return error", func() {
+				res, err := notifying.NewEventStream(notifying.NewEventStreamParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("outbox repo is not specified")))
+			})
+		})
+
+		When("parameter is negative", func() {
+			It("should return error", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				res, err := notifying.NewEventStream(notifying.NewEventStreamParam{
+					OutboxRepo: mock.NewMockOutboxRepository(ctrl),
+					BatchSize:  -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid event stream parameter")))
+			})
+		})
+
+		When("success create stream", func() {
+			It("should return result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				res, err := notifying.NewEventStream(notifying.NewEventStreamParam{
+					OutboxRepo: mock.NewMockOutboxRepository(ctrl),
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("Subscribe function", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			cancel     context.CancelFunc
+			outboxRepo *mock.MockOutboxRepository
+			s          notifying.EventStream
+			item       func(id int64, clientId string) repository.OutboxEvent
+			event      func(id int64, clientId string) notifying.Event
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			ctrl := gomock.NewController(GinkgoT())
+			outboxRepo = mock.NewMockOutboxRepository(ctrl)
+			s, _ = notifying.NewEventStream(notifying.NewEventStreamParam{
+				OutboxRepo: outboxRepo,
+				Interval:   time.Hour,
+				BatchSize:  2,
+			})
+			item = func(id int64, clientId string) repository.OutboxEvent {
+				return repository.OutboxEvent{
+					Id:             id,
+					IdempotencyKey: fmt.Sprintf("file.uploaded:file-%d", id),
+					EventType:      "file.uploaded",
+					FileId:         fmt.Sprintf("file-%d", id),
+					ClientId:       clientId,
+					CreatedAt:      time.UnixMilli(1660000000000),
+				}
+			}
+			event = func(id int64, clientId string) notifying.Event {
+				return notifying.Event{
+					Id:         fmt.Sprintf("file.uploaded:file-%d", id),
+					Sequence:   id,
+					Type:       "file.uploaded",
+					ClientId:   clientId,
+					File:       notifying.FileData{Id: fmt.Sprintf("file-%d", id)},
+					OccurredAt: time.UnixMilli(1660000000000),
+				}
+			}
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				res, err := s.Subscribe(ctx, notifying.SubscribeParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("sequence is negative", func() {
+			It("should return error", func() {
+				res, err := s.Subscribe(ctx, notifying.SubscribeParam{
+					ClientId:      "client-id",
+					AfterSequence: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid sequence parameter")))
+			})
+		})
+
+		When("failed find last event", func() {
+			It("should return error", func() {
+				outboxRepo.
+					EXPECT().
+					FindLastEvent(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.Subscribe(ctx, notifying.SubscribeParam{
+					ClientId: "client-id",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("sequence is not specified", func() {
+			It("should only stream the subsequent events", func() {
+				outboxRepo.
+					EXPECT().
+					FindLastEvent(gomock.Any(), gomock.Any()).
+					Return(&repository.FindLastEventResult{Id: 7}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ListClientEvent(gomock.Any(), gomock.Eq(repository.ListClientEventParam{
+						ClientId: "client-id",
+						AfterId:  7,
+						Limit:    2,
+					})).
+					Return(&repository.ListClientEventResult{
+						Items: []repository.OutboxEvent{item(8, "client-id")},
+					}, nil).
+					Times(1)
+
+				res, err := s.Subscribe(ctx, notifying.SubscribeParam{
+					ClientId: "client-id",
+				})
+
+				Expect(err).To(BeNil())
+				Eventually(res.Events).Should(Receive(Equal(event(8, "client-id"))))
+			})
+		})
+
+		When("page of events is full", func() {
+			It("should read the next page after the last event", func() {
+				outboxRepo.
+					EXPECT().
+					ListClientEvent(gomock.Any(), gomock.Eq(repository.ListClientEventParam{
+						ClientId: "client-id",
+						AfterId:  1,
+						Limit:    2,
+					})).
+					Return(&repository.ListClientEventResult{
+						Items: []repository.OutboxEvent{
+							item(2, "client-id"),
+							item(3, "owner-id"),
+						},
+					}, nil).
+					Times(1)
+				outboxRepo.
+					EXPECT().
+					ListClientEvent(gomock.Any(), gomock.Eq(repository.ListClientEventParam{
+						ClientId: "client-id",
+						AfterId:  3,
+						Limit:    2,
+					})).
+					Return(&repository.ListClientEventResult{
+						Items: []repository.OutboxEvent{item(5, "client-id")},
+					}, nil).
+					Times(1)
+
+				res, err := s.Subscribe(ctx, notifying.SubscribeParam{
+					ClientId:      "client-id",
+					AfterSequence: 1,
+				})
+
+				Expect(err).To(BeNil())
+				Eventually(res.Events).Should(Receive(Equal(event(2, "client-id"))))
+				Eventually(res.Events).Should(Receive(Equal(event(3, "owner-id"))))
+				Eventually(res.Events).Should(Receive(Equal(event(5, "client-id"))))
+			})
+		})
+
+		When("failed list client event", func() {
+			It("should close the channel", func() {
+				outboxRepo.
+					EXPECT().
+					ListClientEvent(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.Subscribe(ctx, notifying.SubscribeParam{
+					ClientId:      "client-id",
+					AfterSequence: 1,
+				})
+
+				Expect(err).To(BeNil())
+				Eventually(res.Events).Should(BeClosed())
+			})
+		})
+
+		When("subscription context is done", func() {
+			It("should close the channel", func() {
+				outboxRepo.
+					EXPECT().
+					ListClientEvent(gomock.Any(), gomock.Any()).
+					Return(&repository.ListClientEventResult{
+						Items: []repository.OutboxEvent{},
+					}, nil).
+					AnyTimes()
+				subCtx, subCancel := context.WithCancel(ctx)
+				res, _ := s.Subscribe(subCtx, notifying.SubscribeParam{
+					ClientId:      "client-id",
+					AfterSequence: 1,
+				})
+
+				subCancel()
+
+				Eventually(res.Events).Should(BeClosed())
+			})
+		})
+	})
+})
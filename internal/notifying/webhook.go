@@ -0,0 +1,504 @@
+package notifying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/serialization"
+	"github.com/go-seidon/local/internal/text"
+)
+
+const (
+	EVENT_FILE_UPLOADED = repository.EVENT_FILE_UPLOADED
+	EVENT_FILE_DELETED  = repository.EVENT_FILE_DELETED
+
+	DEFAULT_LIST_LIMIT = 100
+	MAX_LIST_LIMIT     = 1000
+	MAX_SUBSCRIPTION   = 10
+	MAX_URL_SIZE       = 2048
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, p PublishParam) (*PublishResult, error)
+}
+
+type Webhook interface {
+	Publisher
+	CreateSubscription(ctx context.Context, p CreateSubscriptionParam) (*CreateSubscriptionResult, error)
+	ListSubscription(ctx context.Context, p ListSubscriptionParam) (*ListSubscriptionResult, error)
+	DeleteSubscription(ctx context.Context, p DeleteSubscriptionParam) (*DeleteSubscriptionResult, error)
+	ListDelivery(ctx context.Context, p ListDeliveryParam) (*ListDeliveryResult, error)
+}
+
+type PublishParam struct {
+	EventId    string
+	EventType  string
+	ClientId   string
+	File       FileData
+	OccurredAt time.Time
+}
+
+type FileData struct {
+	Id        string
+	Name      string
+	Mimetype  string
+	Extension string
+	Size      int64
+}
+
+type PublishResult struct {
+	EventId       string
+	TotalDelivery int
+}
+
+type CreateSubscriptionParam struct {
+	ClientId   string
+	Url        string
+	EventTypes []string
+}
+
+type CreateSubscriptionResult struct {
+	Id         string
+	Url        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+type ListSubscriptionParam struct {
+	ClientId string
+}
+
+type ListSubscriptionResult struct {
+	Items []SubscriptionItem
+}
+
+type SubscriptionItem struct {
+	Id         string
+	Url        string
+	EventTypes []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type DeleteSubscriptionParam struct {
+	Id       string
+	ClientId string
+}
+
+type DeleteSubscriptionResult struct {
+	DeletedAt time.Time
+}
+
+type ListDeliveryParam struct {
+	ClientId       string
+	SubscriptionId string
+	Status         string
+	AfterId        string
+	Limit          int
+}
+
+type ListDeliveryResult struct {
+	Items []DeliveryItem
+}
+
+type DeliveryItem struct {
+	Id             string
+	SubscriptionId string
+	EventId        string
+	EventType      string
+	Status         string
+	Attempt        int
+	NextAttemptAt  time.Time
+	ResponseCode   int
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+type webhook struct {
+	webhookRepo         repository.WebhookRepository
+	identifier          text.Identifier
+	secret              text.Identifier
+	serializer          serialization.Serializer
+	clock               datetime.Clock
+	log                 logging.Logger
+	allowPrivateNetwork bool
+}
+
+type eventPayload struct {
+	Id        string           `json:"id"`
+	Type      string           `json:"type"`
+	CreatedAt int64            `json:"created_at"`
+	Data      eventPayloadData `json:"data"`
+}
+
+type eventPayloadData struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Mimetype  string `json:"mimetype"`
+	Extension string `json:"extension"`
+	Size      int64  `json:"size"`
+	ClientId  string `json:"client_id"`
+}
+
+func newEventPayload(e Event) eventPayload {
+	return eventPayload{
+		Id:        e.Id,
+		Type:      e.Type,
+		CreatedAt: e.OccurredAt.UnixMilli(),
+		Data: eventPayloadData{
+			Id:        e.File.Id,
+			Name:      e.File.Name,
+			Mimetype:  e.File.Mimetype,
+			Extension: e.File.Extension,
+			Size:      e.File.Size,
+			ClientId:  e.ClientId,
+		},
+	}
+}
+
+func (w *webhook) Publish(ctx context.Context, p PublishParam) (*PublishResult, error) {
+	w.log.Debug("In function: Publish")
+	defer w.log.Debug("Returning function: Publish")
+
+	if !IsValidEventType(p.EventType) {
+		return nil, fmt.Errorf("invalid event type parameter")
+	}
+
+	eventId := p.EventId
+	if eventId == "" {
+		id, err := w.identifier.GenerateId()
+		if err != nil {
+			return nil, err
+		}
+		eventId = id
+	}
+	if p.ClientId == "" {
+		return &PublishResult{EventId: eventId}, nil
+	}
+
+	subscriptions, err := w.webhookRepo.ListSubscription(ctx, repository.ListSubscriptionParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	occurredAt := p.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = w.clock.Now()
+	}
+	payload, err := w.serializer.Marshal(newEventPayload(Event{
+		Id:         eventId,
+		Type:       p.EventType,
+		ClientId:   p.ClientId,
+		File:       p.File,
+		OccurredAt: occurredAt,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []repository.CreateDeliveryItem{}
+	for _, subscription := range subscriptions.Items {
+		if !hasEventType(subscription.EventTypes, p.EventType) {
+			continue
+		}
+		id, err := w.identifier.GenerateId()
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, repository.CreateDeliveryItem{
+			Id:             id,
+			SubscriptionId: subscription.Id,
+			ClientId:       p.ClientId,
+			EventId:        eventId,
+			EventType:      p.EventType,
+			Payload:        string(payload),
+		})
+	}
+	if len(deliveries) > 0 {
+		_, err = w.webhookRepo.CreateDelivery(ctx, repository.CreateDeliveryParam{
+			Items: deliveries,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := &PublishResult{
+		EventId:       eventId,
+		TotalDelivery: len(deliveries),
+	}
+	return res, nil
+}
+
+func (w *webhook) CreateSubscription(ctx context.Context, p CreateSubscriptionParam) (*CreateSubscriptionResult, error) {
+	w.log.Debug("In function: CreateSubscription")
+	defer w.log.Debug("Returning function: CreateSubscription")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if !isValidUrl(p.Url, w.allowPrivateNetwork) {
+		return nil, fmt.Errorf("invalid url parameter")
+	}
+	if len(p.EventTypes) == 0 {
+		return nil, fmt.Errorf("invalid event type parameter")
+	}
+	eventTypes := []string{}
+	for _, eventType := range p.EventTypes {
+		if !IsValidEventType(eventType) {
+			return nil, fmt.Errorf("invalid event type parameter")
+		}
+		if !hasEventType(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	subscriptions, err := w.webhookRepo.ListSubscription(ctx, repository.ListSubscriptionParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(subscriptions.Items) >= MAX_SUBSCRIPTION {
+		return nil, fmt.Errorf("subscription limit exceeded")
+	}
+
+	id, err := w.identifier.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := w.secret.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := w.webhookRepo.CreateSubscription(ctx, repository.CreateSubscriptionParam{
+		Id:         id,
+		ClientId:   p.ClientId,
+		Url:        p.Url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CreateSubscriptionResult{
+		Id:         id,
+		Url:        p.Url,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		CreatedAt:  subscription.CreatedAt,
+	}
+	return res, nil
+}
+
+func (w *webhook) ListSubscription(ctx context.Context, p ListSubscriptionParam) (*ListSubscriptionResult, error) {
+	w.log.Debug("In function: ListSubscription")
+	defer w.log.Debug("Returning function: ListSubscription")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+
+	subscriptions, err := w.webhookRepo.ListSubscription(ctx, repository.ListSubscriptionParam{
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []SubscriptionItem{}
+	for _, subscription := range subscriptions.Items {
+		items = append(items, SubscriptionItem{
+			Id:         subscription.Id,
+			Url:        subscription.Url,
+			EventTypes: subscription.EventTypes,
+			CreatedAt:  subscription.CreatedAt,
+			UpdatedAt:  subscription.UpdatedAt,
+		})
+	}
+
+	res := &ListSubscriptionResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (w *webhook) DeleteSubscription(ctx context.Context, p DeleteSubscriptionParam) (*DeleteSubscriptionResult, error) {
+	w.log.Debug("In function: DeleteSubscription")
+	defer w.log.Debug("Returning function: DeleteSubscription")
+
+	if p.Id == "" {
+		return nil, fmt.Errorf("invalid subscription id parameter")
+	}
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+
+	subscription, err := w.webhookRepo.DeleteSubscription(ctx, repository.DeleteSubscriptionParam{
+		Id:       p.Id,
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	res := &DeleteSubscriptionResult{
+		DeletedAt: subscription.DeletedAt,
+	}
+	return res, nil
+}
+
+func (w *webhook) ListDelivery(ctx context.Context, p ListDeliveryParam) (*ListDeliveryResult, error) {
+	w.log.Debug("In function: ListDelivery")
+	defer w.log.Debug("Returning function: ListDelivery")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if p.Status != "" && !isValidDeliveryStatus(p.Status) {
+		return nil, fmt.Errorf("invalid status parameter")
+	}
+	if p.Limit < 0 || p.Limit > MAX_LIST_LIMIT {
+		return nil, fmt.Errorf("invalid limit parameter")
+	}
+	limit := DEFAULT_LIST_LIMIT
+	if p.Limit > 0 {
+		limit = p.Limit
+	}
+
+	deliveries, err := w.webhookRepo.ListDelivery(ctx, repository.ListDeliveryParam{
+		ClientId:       p.ClientId,
+		SubscriptionId: p.SubscriptionId,
+		Status:         p.Status,
+		AfterId:        p.AfterId,
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []DeliveryItem{}
+	for _, delivery := range deliveries.Items {
+		items = append(items, DeliveryItem{
+			Id:             delivery.Id,
+			SubscriptionId: delivery.SubscriptionId,
+			EventId:        delivery.EventId,
+			EventType:      delivery.EventType,
+			Status:         delivery.Status,
+			Attempt:        delivery.Attempt,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			ResponseCode:   delivery.ResponseCode,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+			UpdatedAt:      delivery.UpdatedAt,
+			DeliveredAt:    delivery.DeliveredAt,
+		})
+	}
+
+	res := &ListDeliveryResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func IsValidEventType(eventType string) bool {
+	switch eventType {
+	case EVENT_FILE_UPLOADED, EVENT_FILE_DELETED:
+		return true
+	}
+	return false
+}
+
+func isValidDeliveryStatus(status string) bool {
+	switch status {
+	case repository.DELIVERY_PENDING, repository.DELIVERY_DELIVERED,
+		repository.DELIVERY_DEAD, repository.DELIVERY_CANCELED:
+		return true
+	}
+	return false
+}
+
+func isValidUrl(rawUrl string, allowPrivateNetwork bool) bool {
+	if len(rawUrl) > MAX_URL_SIZE {
+		return false
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return false
+	}
+	return allowPrivateNetwork || isPublicUrl(u)
+}
+
+func hasEventType(eventTypes []string, eventType string) bool {
+	for _, t := range eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type NewWebhookParam struct {
+	WebhookRepo         repository.WebhookRepository
+	Identifier          text.Identifier
+	Logger              logging.Logger
+	SecretGenerator     text.Identifier
+	Serializer          serialization.Serializer
+	Clock               datetime.Clock
+	AllowPrivateNetwork bool
+}
+
+func NewWebhook(p NewWebhookParam) (*webhook, error) {
+	if p.WebhookRepo == nil {
+		return nil, fmt.Errorf("webhook repo is not specified")
+	}
+	if p.Identifier == nil {
+		return nil, fmt.Errorf("identifier is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+
+	secret := p.SecretGenerator
+	if secret == nil {
+		secret = text.NewRandomSecret(text.DEFAULT_SECRET_SIZE)
+	}
+	serializer := p.Serializer
+	if serializer == nil {
+		serializer = serialization.NewJsonSerializer()
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	w := &webhook{
+		webhookRepo:         p.WebhookRepo,
+		identifier:          p.Identifier,
+		secret:              secret,
+		serializer:          serializer,
+		clock:               clock,
+		log:                 p.Logger,
+		allowPrivateNetwork: p.AllowPrivateNetwork,
+	}
+	return w, nil
+}
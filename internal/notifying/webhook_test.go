@@ -0,0 +1,781 @@
+package notifying_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestNotifying(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Notifying Package")
+}
+
+var _ = Describe("Webhook Service", func() {
+	Context("NewWebhook function", Label("unit"), func() {
+		var (
+			p notifying.NewWebhookParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = notifying.NewWebhookParam{
+				WebhookRepo: mock.NewMockWebhookRepository(ctrl),
+				Identifier:  mock.NewMockIdentifier(ctrl),
+				Logger:      mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := notifying.NewWebhook(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("optional parameters are specified", func() {
+			It("should return result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				p.SecretGenerator = mock.NewMockIdentifier(ctrl)
+				p.Serializer = mock.NewMockSerializer(ctrl)
+				p.Clock = mock.NewMockClock(ctrl)
+				res, err := notifying.NewWebhook(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("webhook repo is not specified", func() {
+			It("should return error", func() {
+				p.WebhookRepo = nil
+				res, err := notifying.NewWebhook(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("webhook repo is not specified")))
+			})
+		})
+
+		When("identifier is not specified", func() {
+			It("should return error", func() {
+				p.Identifier = nil
+				res, err := notifying.NewWebhook(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("identifier is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := notifying.NewWebhook(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+	})
+
+	var (
+		ctx         context.Context
+		currentTs   time.Time
+		webhookRepo *mock.MockWebhookRepository
+		identifier  *mock.MockIdentifier
+		secret      *mock.MockIdentifier
+		clock       *mock.MockClock
+		log         *mock.MockLogger
+		w           notifying.Webhook
+	)
+
+	setup := func(fn string) {
+		ctx = context.Background()
+		currentTs = time.UnixMilli(1660000000000)
+		ctrl := gomock.NewController(GinkgoT())
+		webhookRepo = mock.NewMockWebhookRepository(ctrl)
+		identifier = mock.NewMockIdentifier(ctrl)
+		secret = mock.NewMockIdentifier(ctrl)
+		clock = mock.NewMockClock(ctrl)
+		log = mock.NewMockLogger(ctrl)
+		w, _ = notifying.NewWebhook(notifying.NewWebhookParam{
+			WebhookRepo:     webhookRepo,
+			Identifier:      identifier,
+			SecretGenerator: secret,
+			Clock:           clock,
+			Logger:          log,
+		})
+
+		log.EXPECT().Debug("In function: " + fn).Times(1)
+		log.EXPECT().Debug("Returning function: " + fn).Times(1)
+	}
+
+	Context("Publish function", Label("unit"), func() {
+		var (
+			p             notifying.PublishParam
+			subscriptions *repository.ListSubscriptionResult
+			payload       string
+		)
+
+		BeforeEach(func() {
+			setup("Publish")
+			p = notifying.PublishParam{
+				EventId:   "event-id",
+				EventType: notifying.EVENT_FILE_UPLOADED,
+				ClientId:  "client-id",
+				File: notifying.FileData{
+					Id:        "file-id",
+					Name:      "dolphin",
+					Mimetype:  "image/jpeg",
+					Extension: "jpg",
+					Size:      200,
+				},
+				OccurredAt: currentTs,
+			}
+			subscriptions = &repository.ListSubscriptionResult{
+				Items: []repository.WebhookSubscription{
+					{Id: "subscription-id-1", EventTypes: []string{"file.uploaded", "file.deleted"}},
+					{Id: "subscription-id-2", EventTypes: []string{"file.deleted"}},
+					{Id: "subscription-id-3", EventTypes: []string{"file.uploaded"}},
+				},
+			}
+			payload = `{"id":"event-id","type":"file.uploaded","created_at":1660000000000,"data":{"id":"file-id","name":"dolphin","mimetype":"image/jpeg","extension":"jpg","size":200,"client_id":"client-id"}}`
+		})
+
+		When("event type is invalid", func() {
+			It("should return error", func() {
+				p.EventType = "file.renamed"
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid event type parameter")))
+			})
+		})
+
+		When("failed generate event id", func() {
+			It("should return error", func() {
+				p.EventId = ""
+				identifier.EXPECT().GenerateId().Return("", fmt.Errorf("id error")).Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("id error")))
+			})
+		})
+
+		When("file has no owner", func() {
+			It("should not schedule delivery", func() {
+				p.EventId = ""
+				p.ClientId = ""
+				identifier.EXPECT().GenerateId().Return("generated-event-id", nil).Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(Equal(&notifying.PublishResult{EventId: "generated-event-id"}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed list subscription", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Eq(repository.ListSubscriptionParam{ClientId: "client-id"})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("there is no matching subscription", func() {
+			It("should not schedule delivery", func() {
+				p.EventType = notifying.EVENT_FILE_DELETED
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListSubscriptionResult{
+						Items: []repository.WebhookSubscription{
+							{Id: "subscription-id-1", EventTypes: []string{"file.uploaded"}},
+						},
+					}, nil).
+					Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(Equal(&notifying.PublishResult{EventId: "event-id"}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed generate delivery id", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(subscriptions, nil).
+					Times(1)
+				identifier.EXPECT().GenerateId().Return("", fmt.Errorf("id error")).Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("id error")))
+			})
+		})
+
+		When("failed create delivery", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(subscriptions, nil).
+					Times(1)
+				identifier.EXPECT().GenerateId().Return("delivery-id", nil).Times(2)
+				webhookRepo.
+					EXPECT().
+					CreateDelivery(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success publish event", func() {
+			It("should schedule delivery to the matching subscriptions", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(subscriptions, nil).
+					Times(1)
+				identifier.EXPECT().GenerateId().Return("delivery-id-1", nil).Times(1)
+				identifier.EXPECT().GenerateId().Return("delivery-id-3", nil).Times(1)
+				webhookRepo.
+					EXPECT().
+					CreateDelivery(gomock.Eq(ctx), gomock.Eq(repository.CreateDeliveryParam{
+						Items: []repository.CreateDeliveryItem{
+							{
+								Id:             "delivery-id-1",
+								SubscriptionId: "subscription-id-1",
+								ClientId:       "client-id",
+								EventId:        "event-id",
+								EventType:      "file.uploaded",
+								Payload:        payload,
+							},
+							{
+								Id:             "delivery-id-3",
+								SubscriptionId: "subscription-id-3",
+								ClientId:       "client-id",
+								EventId:        "event-id",
+								EventType:      "file.uploaded",
+								Payload:        payload,
+							},
+						},
+					})).
+					Return(&repository.CreateDeliveryResult{CreatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(Equal(&notifying.PublishResult{
+					EventId:       "event-id",
+					TotalDelivery: 2,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("occurred time is not specified", func() {
+			It("should use current time", func() {
+				p.OccurredAt = time.Time{}
+				clock.EXPECT().Now().Return(currentTs).Times(1)
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListSubscriptionResult{
+						Items: []repository.WebhookSubscription{
+							{Id: "subscription-id-1", EventTypes: []string{"file.uploaded"}},
+						},
+					}, nil).
+					Times(1)
+				identifier.EXPECT().GenerateId().Return("delivery-id-1", nil).Times(1)
+				webhookRepo.
+					EXPECT().
+					CreateDelivery(gomock.Eq(ctx), gomock.Eq(repository.CreateDeliveryParam{
+						Items: []repository.CreateDeliveryItem{
+							{
+								Id:             "delivery-id-1",
+								SubscriptionId: "subscription-id-1",
+								ClientId:       "client-id",
+								EventId:        "event-id",
+								EventType:      "file.uploaded",
+								Payload:        payload,
+							},
+						},
+					})).
+					Return(&repository.CreateDeliveryResult{CreatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := w.Publish(ctx, p)
+
+				Expect(res).To(Equal(&notifying.PublishResult{
+					EventId:       "event-id",
+					TotalDelivery: 1,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CreateSubscription function", Label("unit"), func() {
+		var (
+			p           notifying.CreateSubscriptionParam
+			createParam repository.CreateSubscriptionParam
+		)
+
+		BeforeEach(func() {
+			setup("CreateSubscription")
+			p = notifying.CreateSubscriptionParam{
+				ClientId:   "client-id",
+				Url:        "https://example.com/hook",
+				EventTypes: []string{"file.uploaded", "file.deleted", "file.uploaded"},
+			}
+			createParam = repository.CreateSubscriptionParam{
+				Id:         "subscription-id",
+				ClientId:   "client-id",
+				Url:        "https://example.com/hook",
+				Secret:     "secret",
+				EventTypes: []string{"file.uploaded", "file.deleted"},
+			}
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("url is invalid", func() {
+			It("should return error", func() {
+				log.EXPECT().Debug(gomock.Any()).AnyTimes()
+				for _, u := range []string{
+					"", "ftp://example.com", "/hook", "https://", "http://[::1",
+					"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://169.254.169.254/latest",
+					"http://[::1]/hook", "http://10.0.0.1/hook",
+				} {
+					p.Url = u
+					res, err := w.CreateSubscription(ctx, p)
+
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(fmt.Errorf("invalid url parameter")))
+				}
+			})
+		})
+
+		When("event type is not specified", func() {
+			It("should return error", func() {
+				p.EventTypes = nil
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid event type parameter")))
+			})
+		})
+
+		When("event type is invalid", func() {
+			It("should return error", func() {
+				p.EventTypes = []string{"file.uploaded", "file.renamed"}
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid event type parameter")))
+			})
+		})
+
+		When("failed list subscription", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Eq(repository.ListSubscriptionParam{ClientId: "client-id"})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("subscription limit is exceeded", func() {
+			It("should return error", func() {
+				items := make([]repository.WebhookSubscription, notifying.MAX_SUBSCRIPTION)
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListSubscriptionResult{Items: items}, nil).
+					Times(1)
+
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("subscription limit exceeded")))
+			})
+		})
+
+		When("failed generate secret", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListSubscriptionResult{}, nil).
+					Times(1)
+				identifier.EXPECT().GenerateId().Return("subscription-id", nil).Times(1)
+				secret.EXPECT().GenerateId().Return("", fmt.Errorf("rand error")).Times(1)
+
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rand error")))
+			})
+		})
+
+		When("failed create subscription", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListSubscriptionResult{}, nil).
+					Times(1)
+				identifier.EXPECT().GenerateId().Return("subscription-id", nil).Times(1)
+				secret.EXPECT().GenerateId().Return("secret", nil).Times(1)
+				webhookRepo.
+					EXPECT().
+					CreateSubscription(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success create subscription", func() {
+			It("should return result", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.ListSubscriptionResult{}, nil).
+					Times(1)
+				identifier.EXPECT().GenerateId().Return("subscription-id", nil).Times(1)
+				secret.EXPECT().GenerateId().Return("secret", nil).Times(1)
+				webhookRepo.
+					EXPECT().
+					CreateSubscription(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateSubscriptionResult{CreatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := w.CreateSubscription(ctx, p)
+
+				Expect(res).To(Equal(&notifying.CreateSubscriptionResult{
+					Id:         "subscription-id",
+					Url:        "https://example.com/hook",
+					EventTypes: []string{"file.uploaded", "file.deleted"},
+					Secret:     "secret",
+					CreatedAt:  currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListSubscription function", Label("unit"), func() {
+		BeforeEach(func() {
+			setup("ListSubscription")
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				res, err := w.ListSubscription(ctx, notifying.ListSubscriptionParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("failed list subscription", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Eq(repository.ListSubscriptionParam{ClientId: "client-id"})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := w.ListSubscription(ctx, notifying.ListSubscriptionParam{ClientId: "client-id"})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success list subscription", func() {
+			It("should not return the secret", func() {
+				webhookRepo.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Eq(repository.ListSubscriptionParam{ClientId: "client-id"})).
+					Return(&repository.ListSubscriptionResult{
+						Items: []repository.WebhookSubscription{
+							{
+								Id:         "subscription-id",
+								ClientId:   "client-id",
+								Url:        "https://example.com/hook",
+								Secret:     "secret",
+								EventTypes: []string{"file.deleted"},
+								CreatedAt:  currentTs,
+								UpdatedAt:  currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+
+				res, err := w.ListSubscription(ctx, notifying.ListSubscriptionParam{ClientId: "client-id"})
+
+				Expect(res).To(Equal(&notifying.ListSubscriptionResult{
+					Items: []notifying.SubscriptionItem{
+						{
+							Id:         "subscription-id",
+							Url:        "https://example.com/hook",
+							EventTypes: []string{"file.deleted"},
+							CreatedAt:  currentTs,
+							UpdatedAt:  currentTs,
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DeleteSubscription function", Label("unit"), func() {
+		var (
+			p           notifying.DeleteSubscriptionParam
+			deleteParam repository.DeleteSubscriptionParam
+		)
+
+		BeforeEach(func() {
+			setup("DeleteSubscription")
+			p = notifying.DeleteSubscriptionParam{
+				Id:       "subscription-id",
+				ClientId: "client-id",
+			}
+			deleteParam = repository.DeleteSubscriptionParam{
+				Id:       "subscription-id",
+				ClientId: "client-id",
+			}
+		})
+
+		When("subscription id is not specified", func() {
+			It("should return error", func() {
+				p.Id = ""
+				res, err := w.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid subscription id parameter")))
+			})
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := w.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("subscription is not available", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					DeleteSubscription(gomock.Eq(ctx), gomock.Eq(deleteParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := w.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(notifying.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed delete subscription", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					DeleteSubscription(gomock.Eq(ctx), gomock.Eq(deleteParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := w.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success delete subscription", func() {
+			It("should return result", func() {
+				webhookRepo.
+					EXPECT().
+					DeleteSubscription(gomock.Eq(ctx), gomock.Eq(deleteParam)).
+					Return(&repository.DeleteSubscriptionResult{DeletedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := w.DeleteSubscription(ctx, p)
+
+				Expect(res).To(Equal(&notifying.DeleteSubscriptionResult{DeletedAt: currentTs}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListDelivery function", Label("unit"), func() {
+		var (
+			p         notifying.ListDeliveryParam
+			listParam repository.ListDeliveryParam
+		)
+
+		BeforeEach(func() {
+			setup("ListDelivery")
+			p = notifying.ListDeliveryParam{
+				ClientId:       "client-id",
+				SubscriptionId: "subscription-id",
+				Status:         repository.DELIVERY_DEAD,
+			}
+			listParam = repository.ListDeliveryParam{
+				ClientId:       "client-id",
+				SubscriptionId: "subscription-id",
+				Status:         repository.DELIVERY_DEAD,
+				Limit:          notifying.DEFAULT_LIST_LIMIT,
+			}
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := w.ListDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("status is invalid", func() {
+			It("should return error", func() {
+				p.Status = "failed"
+				res, err := w.ListDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid status parameter")))
+			})
+		})
+
+		When("limit is invalid", func() {
+			It("should return error", func() {
+				p.Limit = -1
+				res, err := w.ListDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid limit parameter")))
+			})
+		})
+
+		When("failed list delivery", func() {
+			It("should return error", func() {
+				webhookRepo.
+					EXPECT().
+					ListDelivery(gomock.Eq(ctx), gomock.Eq(listParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := w.ListDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success list delivery", func() {
+			It("should return result", func() {
+				webhookRepo.
+					EXPECT().
+					ListDelivery(gomock.Eq(ctx), gomock.Eq(listParam)).
+					Return(&repository.ListDeliveryResult{
+						Items: []repository.WebhookDelivery{
+							{
+								Id:             "delivery-id",
+								SubscriptionId: "subscription-id",
+								ClientId:       "client-id",
+								EventId:        "event-id",
+								EventType:      "file.deleted",
+								Status:         repository.DELIVERY_DEAD,
+								Attempt:        8,
+								NextAttemptAt:  currentTs,
+								ResponseCode:   500,
+								LastError:      "unexpected response status: 500",
+								CreatedAt:      currentTs,
+								UpdatedAt:      currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+
+				res, err := w.ListDelivery(ctx, p)
+
+				Expect(res).To(Equal(&notifying.ListDeliveryResult{
+					Items: []notifying.DeliveryItem{
+						{
+							Id:             "delivery-id",
+							SubscriptionId: "subscription-id",
+							EventId:        "event-id",
+							EventType:      "file.deleted",
+							Status:         repository.DELIVERY_DEAD,
+							Attempt:        8,
+							NextAttemptAt:  currentTs,
+							ResponseCode:   500,
+							LastError:      "unexpected response status: 500",
+							CreatedAt:      currentTs,
+							UpdatedAt:      currentTs,
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,7 @@
+package quota
+
+import "errors"
+
+var (
+	ErrorResourceNotFound = errors.New("resource not found")
+)
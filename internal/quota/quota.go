@@ -0,0 +1,127 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+type Quota interface {
+	RetrieveUsage(ctx context.Context, p RetrieveUsageParam) (*RetrieveUsageResult, error)
+	UpdateQuota(ctx context.Context, p UpdateQuotaParam) (*UpdateQuotaResult, error)
+}
+
+type RetrieveUsageParam struct {
+	ClientId string
+}
+
+type RetrieveUsageResult struct {
+	ClientId string
+	UsedSize int64
+	UsedFile int64
+	MaxSize  int64
+	MaxFile  int64
+}
+
+// zero limit means unlimited
+type UpdateQuotaParam struct {
+	ClientId string
+	MaxSize  int64
+	MaxFile  int64
+}
+
+type UpdateQuotaResult struct {
+	UpdatedAt time.Time
+}
+
+type quota struct {
+	quotaRepo repository.QuotaRepository
+	log       logging.Logger
+}
+
+func (s *quota) RetrieveUsage(ctx context.Context, p RetrieveUsageParam) (*RetrieveUsageResult, error) {
+	s.log.Debug("In function: RetrieveUsage")
+	defer s.log.Debug("Returning function: RetrieveUsage")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+
+	q, err := s.quotaRepo.FindQuota(ctx, repository.FindQuotaParam{
+		ClientId: p.ClientId,
+	})
+	if errors.Is(err, repository.ErrorRecordNotFound) {
+		res := &RetrieveUsageResult{
+			ClientId: p.ClientId,
+		}
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RetrieveUsageResult{
+		ClientId: q.ClientId,
+		UsedSize: q.UsedSize,
+		UsedFile: q.UsedFile,
+		MaxSize:  q.MaxSize,
+		MaxFile:  q.MaxFile,
+	}
+	return res, nil
+}
+
+func (s *quota) UpdateQuota(ctx context.Context, p UpdateQuotaParam) (*UpdateQuotaResult, error) {
+	s.log.Debug("In function: UpdateQuota")
+	defer s.log.Debug("Returning function: UpdateQuota")
+
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if p.MaxSize < 0 {
+		return nil, fmt.Errorf("invalid max size parameter")
+	}
+	if p.MaxFile < 0 {
+		return nil, fmt.Errorf("invalid max file parameter")
+	}
+
+	q, err := s.quotaRepo.UpdateQuota(ctx, repository.UpdateQuotaParam{
+		ClientId: p.ClientId,
+		MaxSize:  p.MaxSize,
+		MaxFile:  p.MaxFile,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	res := &UpdateQuotaResult{
+		UpdatedAt: q.UpdatedAt,
+	}
+	return res, nil
+}
+
+type NewQuotaParam struct {
+	QuotaRepo repository.QuotaRepository
+	Logger    logging.Logger
+}
+
+func NewQuota(p NewQuotaParam) (*quota, error) {
+	if p.QuotaRepo == nil {
+		return nil, fmt.Errorf("quota repo is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+
+	s := &quota{
+		quotaRepo: p.QuotaRepo,
+		log:       p.Logger,
+	}
+	return s, nil
+}
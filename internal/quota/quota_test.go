@@ -0,0 +1,295 @@
+package quota_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/quota"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestQuota(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Quota Package")
+}
+
+var _ = Describe("Quota Service", func() {
+	Context("NewQuota function", Label("unit"), func() {
+		var (
+			p quota.NewQuotaParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = quota.NewQuotaParam{
+				QuotaRepo: mock.NewMockQuotaRepository(ctrl),
+				Logger:    mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := quota.NewQuota(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("quota repo is not specified", func() {
+			It("should return error", func() {
+				p.QuotaRepo = nil
+				res, err := quota.NewQuota(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("quota repo is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := quota.NewQuota(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+	})
+
+	Context("RetrieveUsage function", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			quotaRepo  *mock.MockQuotaRepository
+			logger     *mock.MockLogger
+			s          quota.Quota
+			p          quota.RetrieveUsageParam
+			quotaParam repository.FindQuotaParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			quotaRepo = mock.NewMockQuotaRepository(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			s, _ = quota.NewQuota(quota.NewQuotaParam{
+				QuotaRepo: quotaRepo,
+				Logger:    logger,
+			})
+			p = quota.RetrieveUsageParam{
+				ClientId: "mock-client-id",
+			}
+			quotaParam = repository.FindQuotaParam{
+				ClientId: "mock-client-id",
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: RetrieveUsage").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: RetrieveUsage").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := s.RetrieveUsage(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("failed find quota", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.RetrieveUsage(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("quota is not found", func() {
+			It("should return empty usage", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := s.RetrieveUsage(ctx, p)
+
+				Expect(res).To(Equal(&quota.RetrieveUsageResult{
+					ClientId: "mock-client-id",
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("quota is found", func() {
+			It("should return result", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(&repository.FindQuotaResult{
+						ClientId:  "mock-client-id",
+						MaxSize:   2048,
+						MaxFile:   10,
+						UsedSize:  1024,
+						UsedFile:  3,
+						UpdatedAt: time.Now(),
+					}, nil).
+					Times(1)
+
+				res, err := s.RetrieveUsage(ctx, p)
+
+				Expect(res).To(Equal(&quota.RetrieveUsageResult{
+					ClientId: "mock-client-id",
+					UsedSize: 1024,
+					UsedFile: 3,
+					MaxSize:  2048,
+					MaxFile:  10,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateQuota function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			currentTs   time.Time
+			quotaRepo   *mock.MockQuotaRepository
+			logger      *mock.MockLogger
+			s           quota.Quota
+			p           quota.UpdateQuotaParam
+			updateParam repository.UpdateQuotaParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			quotaRepo = mock.NewMockQuotaRepository(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			s, _ = quota.NewQuota(quota.NewQuotaParam{
+				QuotaRepo: quotaRepo,
+				Logger:    logger,
+			})
+			p = quota.UpdateQuotaParam{
+				ClientId: "mock-client-id",
+				MaxSize:  2048,
+				MaxFile:  10,
+			}
+			updateParam = repository.UpdateQuotaParam{
+				ClientId: "mock-client-id",
+				MaxSize:  2048,
+				MaxFile:  10,
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: UpdateQuota").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: UpdateQuota").
+				Times(1)
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := s.UpdateQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("max size is negative", func() {
+			It("should return error", func() {
+				p.MaxSize = -1
+				res, err := s.UpdateQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid max size parameter")))
+			})
+		})
+
+		When("max file is negative", func() {
+			It("should return error", func() {
+				p.MaxFile = -1
+				res, err := s.UpdateQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid max file parameter")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					UpdateQuota(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := s.UpdateQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(quota.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed update quota", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					UpdateQuota(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.UpdateQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success update quota", func() {
+			It("should return result", func() {
+				quotaRepo.
+					EXPECT().
+					UpdateQuota(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(&repository.UpdateQuotaResult{
+						UpdatedAt: currentTs,
+					}, nil).
+					Times(1)
+
+				res, err := s.UpdateQuota(ctx, p)
+
+				Expect(res).To(Equal(&quota.UpdateQuotaResult{
+					UpdatedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,7 @@
+package relocating
+
+import "errors"
+
+var (
+	ErrorResourceExists = errors.New("resource already exists")
+)
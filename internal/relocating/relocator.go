@@ -0,0 +1,225 @@
+package relocating
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/go-seidon/local/internal/filesystem"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/uploading"
+)
+
+const (
+	DEFAULT_BATCH_SIZE = 100
+)
+
+type Relocator interface {
+	RelocateFiles(ctx context.Context, p RelocateFilesParam) (*RelocateFilesResult, error)
+}
+
+type RelocateFilesParam struct {
+	AfterId   string
+	BatchSize int
+	DryRun    bool
+}
+
+type RelocateFilesResult struct {
+	LastId    string
+	HasMore   bool
+	Total     int
+	Relocated int
+	Skipped   int
+	Failed    int
+}
+
+func NewMoveFn(fileManager filesystem.FileManager, dirManager filesystem.DirectoryManager) repository.MoveFn {
+	return func(ctx context.Context, mp repository.MoveFnParam) error {
+		dir := filepath.Dir(mp.NewPath)
+		exists, err := dirManager.IsDirectoryExists(ctx, filesystem.IsDirectoryExistsParam{
+			Path: dir,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			_, err := dirManager.CreateDir(ctx, filesystem.CreateDirParam{
+				Path:       dir,
+				Permission: 0644,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		exists, err = fileManager.IsFileExists(ctx, filesystem.IsFileExistsParam{
+			Path: mp.NewPath,
+		})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrorResourceExists
+		}
+
+		_, err = fileManager.MoveFile(ctx, filesystem.MoveFileParam{
+			OldPath: mp.OldPath,
+			NewPath: mp.NewPath,
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+type relocator struct {
+	fileRepo    repository.FileRepository
+	fileManager filesystem.FileManager
+	dirManager  filesystem.DirectoryManager
+	log         logging.Logger
+	location    uploading.UploadLocation
+	uploadDir   string
+}
+
+func (s *relocator) RelocateFiles(ctx context.Context, p RelocateFilesParam) (*RelocateFilesResult, error) {
+	s.log.Debug("In function: RelocateFiles")
+	defer s.log.Debug("Returning function: RelocateFiles")
+
+	if p.BatchSize < 0 {
+		return nil, fmt.Errorf("invalid batch size")
+	}
+	batchSize := DEFAULT_BATCH_SIZE
+	if p.BatchSize > 0 {
+		batchSize = p.BatchSize
+	}
+
+	files, err := s.fileRepo.ListFile(ctx, repository.ListFileParam{
+		AfterId: p.AfterId,
+		Limit:   batchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RelocateFilesResult{
+		LastId:  p.AfterId,
+		HasMore: len(files.Items) == batchSize,
+		Total:   len(files.Items),
+	}
+	for _, file := range files.Items {
+		res.LastId = file.UniqueId
+
+		newPath := s.buildPath(file)
+		if newPath == file.Path {
+			res.Skipped++
+			continue
+		}
+
+		if p.DryRun {
+			s.log.Infof("File %s will be relocated: %s -> %s", file.UniqueId, file.Path, newPath)
+			res.Relocated++
+			continue
+		}
+
+		err := s.relocateFile(ctx, file, newPath)
+		if err != nil {
+			s.log.Errorf("Failed relocate file %s: %s", file.UniqueId, err.Error())
+			res.Failed++
+			continue
+		}
+
+		s.log.Infof("File %s is relocated: %s -> %s", file.UniqueId, file.Path, newPath)
+		res.Relocated++
+	}
+	return res, nil
+}
+
+// the file is moved inside the record update transaction,
+// it's moved back when the transaction is failed after the file has been moved
+func (s *relocator) relocateFile(ctx context.Context, file repository.ListFileItem, newPath string) error {
+	_, err := s.fileRepo.UpdateFilePath(ctx, repository.UpdateFilePathParam{
+		UniqueId: file.UniqueId,
+		OldPath:  file.Path,
+		NewPath:  newPath,
+		MoveFn:   NewMoveFn(s.fileManager, s.dirManager),
+	})
+	if err == nil {
+		return nil
+	}
+
+	oldExists, oErr := s.fileManager.IsFileExists(ctx, filesystem.IsFileExistsParam{
+		Path: file.Path,
+	})
+	newExists, nErr := s.fileManager.IsFileExists(ctx, filesystem.IsFileExistsParam{
+		Path: newPath,
+	})
+	if oErr != nil || nErr != nil || oldExists || !newExists {
+		return err
+	}
+
+	_, mErr := s.fileManager.MoveFile(ctx, filesystem.MoveFileParam{
+		OldPath: newPath,
+		NewPath: file.Path,
+	})
+	if mErr != nil {
+		s.log.Errorf("Failed restore file %s: %s", file.UniqueId, mErr.Error())
+	}
+	return err
+}
+
+func (s *relocator) buildPath(file repository.ListFileItem) string {
+	location := s.location.GetLocation(uploading.GetLocationParam{
+		UniqueId: file.UniqueId,
+		ClientId: file.ClientId,
+		Time:     file.CreatedAt,
+	})
+
+	path := fmt.Sprintf("%s/%s/%s", s.uploadDir, location, file.UniqueId)
+	if file.Extension != "" {
+		path = fmt.Sprintf("%s.%s", path, file.Extension)
+	}
+	return path
+}
+
+type NewRelocatorParam struct {
+	FileRepo    repository.FileRepository
+	FileManager filesystem.FileManager
+	DirManager  filesystem.DirectoryManager
+	Logger      logging.Logger
+	Location    uploading.UploadLocation
+	UploadDir   string
+}
+
+func NewRelocator(p NewRelocatorParam) (*relocator, error) {
+	if p.FileRepo == nil {
+		return nil, fmt.Errorf("file repo is not specified")
+	}
+	if p.FileManager == nil {
+		return nil, fmt.Errorf("file manager is not specified")
+	}
+	if p.DirManager == nil {
+		return nil, fmt.Errorf("directory manager is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+	if p.Location == nil {
+		return nil, fmt.Errorf("location is not specified")
+	}
+	if p.UploadDir == "" {
+		return nil, fmt.Errorf("upload directory is not specified")
+	}
+
+	s := &relocator{
+		fileRepo:    p.FileRepo,
+		fileManager: p.FileManager,
+		dirManager:  p.DirManager,
+		log:         p.Logger,
+		location:    p.Location,
+		uploadDir:   p.UploadDir,
+	}
+	return s, nil
+}
@@ -0,0 +1,575 @@
+package relocating_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-seidon/local/internal/filesystem"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/relocating"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/uploading"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRelocating(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Relocating Package")
+}
+
+var _ = Describe("Relocator Service", func() {
+	Context("NewRelocator function", Label("unit"), func() {
+		var (
+			p relocating.NewRelocatorParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = relocating.NewRelocatorParam{
+				FileRepo:    mock.NewMockFileRepository(ctrl),
+				FileManager: mock.NewMockFileManager(ctrl),
+				DirManager:  mock.NewMockDirectoryManager(ctrl),
+				Logger:      mock.NewMockLogger(ctrl),
+				Location:    mock.NewMockUploadLocation(ctrl),
+				UploadDir:   "storage",
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := relocating.NewRelocator(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file repo is not specified", func() {
+			It("should return error", func() {
+				p.FileRepo = nil
+				res, err := relocating.NewRelocator(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file repo is not specified")))
+			})
+		})
+
+		When("file manager is not specified", func() {
+			It("should return error", func() {
+				p.FileManager = nil
+				res, err := relocating.NewRelocator(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file manager is not specified")))
+			})
+		})
+
+		When("directory manager is not specified", func() {
+			It("should return error", func() {
+				p.DirManager = nil
+				res, err := relocating.NewRelocator(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("directory manager is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := relocating.NewRelocator(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+
+		When("location is not specified", func() {
+			It("should return error", func() {
+				p.Location = nil
+				res, err := relocating.NewRelocator(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("location is not specified")))
+			})
+		})
+
+		When("upload directory is not specified", func() {
+			It("should return error", func() {
+				p.UploadDir = ""
+				res, err := relocating.NewRelocator(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("upload directory is not specified")))
+			})
+		})
+	})
+
+	Context("NewMoveFn function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			fileManager *mock.MockFileManager
+			dirManager  *mock.MockDirectoryManager
+			fn          repository.MoveFn
+			p           repository.MoveFnParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			fileManager = mock.NewMockFileManager(ctrl)
+			dirManager = mock.NewMockDirectoryManager(ctrl)
+			fn = relocating.NewMoveFn(fileManager, dirManager)
+			p = repository.MoveFnParam{
+				OldPath: "storage/2022/08/07/mock-id.jpg",
+				NewPath: "storage/ab/cd/mock-id.jpg",
+			}
+		})
+
+		When("failed check directory existance", func() {
+			It("should return error", func() {
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsDirectoryExistsParam{
+						Path: "storage/ab/cd",
+					})).
+					Return(false, fmt.Errorf("disk error")).
+					Times(1)
+
+				err := fn(ctx, p)
+
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
+			})
+		})
+
+		When("failed create directory", func() {
+			It("should return error", func() {
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(false, nil).
+					Times(1)
+				dirManager.
+					EXPECT().
+					CreateDir(gomock.Eq(ctx), gomock.Eq(filesystem.CreateDirParam{
+						Path:       "storage/ab/cd",
+						Permission: 0644,
+					})).
+					Return(nil, fmt.Errorf("r/w error")).
+					Times(1)
+
+				err := fn(ctx, p)
+
+				Expect(err).To(Equal(fmt.Errorf("r/w error")))
+			})
+		})
+
+		When("failed check file existance", func() {
+			It("should return error", func() {
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(true, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsFileExistsParam{
+						Path: p.NewPath,
+					})).
+					Return(false, fmt.Errorf("disk error")).
+					Times(1)
+
+				err := fn(ctx, p)
+
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
+			})
+		})
+
+		When("new path already exists", func() {
+			It("should return error", func() {
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(true, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Any()).
+					Return(true, nil).
+					Times(1)
+
+				err := fn(ctx, p)
+
+				Expect(err).To(Equal(relocating.ErrorResourceExists))
+			})
+		})
+
+		When("failed move file", func() {
+			It("should return error", func() {
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(true, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Any()).
+					Return(false, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					MoveFile(gomock.Eq(ctx), gomock.Eq(filesystem.MoveFileParam{
+						OldPath: p.OldPath,
+						NewPath: p.NewPath,
+					})).
+					Return(nil, fmt.Errorf("r/w error")).
+					Times(1)
+
+				err := fn(ctx, p)
+
+				Expect(err).To(Equal(fmt.Errorf("r/w error")))
+			})
+		})
+
+		When("success move file", func() {
+			It("should return nil", func() {
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(false, nil).
+					Times(1)
+				dirManager.
+					EXPECT().
+					CreateDir(gomock.Eq(ctx), gomock.Any()).
+					Return(&filesystem.CreateDirResult{}, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Any()).
+					Return(false, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					MoveFile(gomock.Eq(ctx), gomock.Any()).
+					Return(&filesystem.MoveFileResult{}, nil).
+					Times(1)
+
+				err := fn(ctx, p)
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RelocateFiles function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			createdAt   time.Time
+			fileRepo    *mock.MockFileRepository
+			fileManager *mock.MockFileManager
+			logger      *mock.MockLogger
+			location    *mock.MockUploadLocation
+			s           relocating.Relocator
+			p           relocating.RelocateFilesParam
+			listRes     *repository.ListFileResult
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			createdAt = time.UnixMilli(1659830400000)
+			ctrl := gomock.NewController(GinkgoT())
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			fileManager = mock.NewMockFileManager(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			location = mock.NewMockUploadLocation(ctrl)
+			s, _ = relocating.NewRelocator(relocating.NewRelocatorParam{
+				FileRepo:    fileRepo,
+				FileManager: fileManager,
+				DirManager:  mock.NewMockDirectoryManager(ctrl),
+				Logger:      logger,
+				Location:    location,
+				UploadDir:   "storage",
+			})
+			p = relocating.RelocateFilesParam{
+				AfterId:   "mock-after-id",
+				BatchSize: 2,
+			}
+			listRes = &repository.ListFileResult{
+				Items: []repository.ListFileItem{
+					{
+						UniqueId:  "mock-id-1",
+						Path:      "storage/ab/cd/mock-id-1.jpg",
+						Extension: "jpg",
+						ClientId:  "mock-client-id",
+						CreatedAt: createdAt,
+					},
+					{
+						UniqueId:  "mock-id-2",
+						Path:      "storage/2022/08/07/mock-id-2",
+						CreatedAt: createdAt,
+					},
+				},
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: RelocateFiles").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: RelocateFiles").
+				Times(1)
+		})
+
+		When("batch size is invalid", func() {
+			It("should return error", func() {
+				p.BatchSize = -1
+				res, err := s.RelocateFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid batch size")))
+			})
+		})
+
+		When("failed list files", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Eq(repository.ListFileParam{
+						AfterId: "mock-after-id",
+						Limit:   2,
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.RelocateFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("there is no file", func() {
+			It("should return result", func() {
+				p.BatchSize = 0
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Eq(repository.ListFileParam{
+						AfterId: "mock-after-id",
+						Limit:   relocating.DEFAULT_BATCH_SIZE,
+					})).
+					Return(&repository.ListFileResult{}, nil).
+					Times(1)
+
+				res, err := s.RelocateFiles(ctx, p)
+
+				Expect(res).To(Equal(&relocating.RelocateFilesResult{
+					LastId: "mock-after-id",
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("dry run is enabled", func() {
+			It("should not move the file", func() {
+				p.DryRun = true
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Any()).
+					Return(listRes, nil).
+					Times(1)
+				location.
+					EXPECT().
+					GetLocation(gomock.Eq(uploading.GetLocationParam{
+						UniqueId: "mock-id-1",
+						ClientId: "mock-client-id",
+						Time:     createdAt,
+					})).
+					Return("ab/cd").
+					Times(1)
+				location.
+					EXPECT().
+					GetLocation(gomock.Eq(uploading.GetLocationParam{
+						UniqueId: "mock-id-2",
+						Time:     createdAt,
+					})).
+					Return("ef/01").
+					Times(1)
+				logger.
+					EXPECT().
+					Infof(
+						"File %s will be relocated: %s -> %s",
+						"mock-id-2", "storage/2022/08/07/mock-id-2", "storage/ef/01/mock-id-2",
+					).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFilePath(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				res, err := s.RelocateFiles(ctx, p)
+
+				Expect(res).To(Equal(&relocating.RelocateFilesResult{
+					LastId:    "mock-id-2",
+					HasMore:   true,
+					Total:     2,
+					Relocated: 1,
+					Skipped:   1,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed relocate file", func() {
+			It("should continue the batch", func() {
+				listRes.Items = listRes.Items[1:]
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Any()).
+					Return(listRes, nil).
+					Times(1)
+				location.
+					EXPECT().
+					GetLocation(gomock.Any()).
+					Return("ef/01").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFilePath(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, repository.ErrorRecordChanged).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsFileExistsParam{
+						Path: "storage/2022/08/07/mock-id-2",
+					})).
+					Return(true, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsFileExistsParam{
+						Path: "storage/ef/01/mock-id-2",
+					})).
+					Return(false, nil).
+					Times(1)
+				logger.
+					EXPECT().
+					Errorf("Failed relocate file %s: %s", "mock-id-2", "record has been changed").
+					Times(1)
+
+				res, err := s.RelocateFiles(ctx, p)
+
+				Expect(res).To(Equal(&relocating.RelocateFilesResult{
+					LastId: "mock-id-2",
+					Total:  1,
+					Failed: 1,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed commit after file is moved", func() {
+			It("should move the file back", func() {
+				listRes.Items = listRes.Items[1:]
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Any()).
+					Return(listRes, nil).
+					Times(1)
+				location.
+					EXPECT().
+					GetLocation(gomock.Any()).
+					Return("ef/01").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFilePath(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("commit error")).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsFileExistsParam{
+						Path: "storage/2022/08/07/mock-id-2",
+					})).
+					Return(false, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					IsFileExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsFileExistsParam{
+						Path: "storage/ef/01/mock-id-2",
+					})).
+					Return(true, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					MoveFile(gomock.Eq(ctx), gomock.Eq(filesystem.MoveFileParam{
+						OldPath: "storage/ef/01/mock-id-2",
+						NewPath: "storage/2022/08/07/mock-id-2",
+					})).
+					Return(nil, fmt.Errorf("r/w error")).
+					Times(1)
+				logger.
+					EXPECT().
+					Errorf("Failed restore file %s: %s", "mock-id-2", "r/w error").
+					Times(1)
+				logger.
+					EXPECT().
+					Errorf("Failed relocate file %s: %s", "mock-id-2", "commit error").
+					Times(1)
+
+				res, err := s.RelocateFiles(ctx, p)
+
+				Expect(res.Failed).To(Equal(1))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success relocate file", func() {
+			It("should return result", func() {
+				fileRepo.
+					EXPECT().
+					ListFile(gomock.Eq(ctx), gomock.Any()).
+					Return(listRes, nil).
+					Times(1)
+				location.
+					EXPECT().
+					GetLocation(gomock.Any()).
+					Return("ab/cd").
+					Times(2)
+				fileRepo.
+					EXPECT().
+					UpdateFilePath(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, up repository.UpdateFilePathParam) (*repository.UpdateFilePathResult, error) {
+						Expect(up.UniqueId).To(Equal("mock-id-2"))
+						Expect(up.OldPath).To(Equal("storage/2022/08/07/mock-id-2"))
+						Expect(up.NewPath).To(Equal("storage/ab/cd/mock-id-2"))
+						Expect(up.MoveFn).ToNot(BeNil())
+						return &repository.UpdateFilePathResult{}, nil
+					}).
+					Times(1)
+				logger.
+					EXPECT().
+					Infof(
+						"File %s is relocated: %s -> %s",
+						"mock-id-2", "storage/2022/08/07/mock-id-2", "storage/ab/cd/mock-id-2",
+					).
+					Times(1)
+
+				res, err := s.RelocateFiles(ctx, p)
+
+				Expect(res).To(Equal(&relocating.RelocateFilesResult{
+					LastId:    "mock-id-2",
+					HasMore:   true,
+					Total:     2,
+					Relocated: 1,
+					Skipped:   1,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
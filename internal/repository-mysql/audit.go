@@ -0,0 +1,149 @@
+package repository_mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+type auditRepository struct {
+	dbClient *sql.DB
+	clock    datetime.Clock
+}
+
+func (r *auditRepository) CreateAuditLog(ctx context.Context, p repository.CreateAuditLogParam) (*repository.CreateAuditLogResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	insertQuery := `
+		INSERT INTO audit_log (
+			id, client_id, action, file_id, ip_address,
+			request_id, outcome, reason, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.dbClient.Exec(
+		insertQuery,
+		p.Id,
+		p.ClientId,
+		p.Action,
+		p.FileId,
+		p.IpAddress,
+		p.RequestId,
+		p.Outcome,
+		p.Reason,
+		currentTimestamp.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.CreateAuditLogResult{
+		CreatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *auditRepository) ListAuditLog(ctx context.Context, p repository.ListAuditLogParam) (*repository.ListAuditLogResult, error) {
+	conditions := []string{"id > ?"}
+	args := []interface{}{p.AfterId}
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"client_id", p.ClientId},
+		{"action", p.Action},
+		{"file_id", p.FileId},
+		{"outcome", p.Outcome},
+		{"request_id", p.RequestId},
+	}
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+		conditions = append(conditions, filter.column+" = ?")
+		args = append(args, filter.value)
+	}
+	if p.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, p.CreatedFrom.UnixMilli())
+	}
+	if p.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, p.CreatedTo.UnixMilli())
+	}
+	args = append(args, p.Limit)
+
+	listQuery := `
+		SELECT 
+			id, client_id, action, file_id, ip_address,
+			request_id, outcome, reason, created_at
+		FROM audit_log
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY id ASC
+		LIMIT ?
+	`
+	rows, err := r.dbClient.Query(listQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []repository.AuditLog{}
+	for rows.Next() {
+		var item repository.AuditLog
+		var createdAt int64
+		err := rows.Scan(
+			&item.Id,
+			&item.ClientId,
+			&item.Action,
+			&item.FileId,
+			&item.IpAddress,
+			&item.RequestId,
+			&item.Outcome,
+			&item.Reason,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.CreatedAt = time.UnixMilli(createdAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListAuditLogResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func NewAuditRepository(opts ...RepoOption) (*auditRepository, error) {
+	option := RepositoryOption{}
+	for _, opt := range opts {
+		opt(&option)
+	}
+
+	if option.dbClient == nil {
+		return nil, fmt.Errorf("invalid db client specified")
+	}
+
+	var clock datetime.Clock
+	if option.clock == nil {
+		clock = datetime.NewClock()
+	} else {
+		clock = option.clock
+	}
+
+	r := &auditRepository{
+		dbClient: option.dbClient,
+		clock:    clock,
+	}
+	return r, nil
+}
@@ -0,0 +1,239 @@
+package repository_mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	repository_mysql "github.com/go-seidon/local/internal/repository-mysql"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit Repository", func() {
+
+	Context("NewAuditRepository function", Label("unit"), func() {
+		When("db client is not specified", func() {
+			It("should return error", func() {
+				res, err := repository_mysql.NewAuditRepository()
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid db client specified")))
+			})
+		})
+
+		When("required parameter is specified", func() {
+			It("should return result", func() {
+				opt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewAuditRepository(opt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("clock is specified", func() {
+			It("should return result", func() {
+				clockOpt := repository_mysql.WithClock(&mock.MockClock{})
+				dbOpt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewAuditRepository(clockOpt, dbOpt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CreateAuditLog function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.AuditRepository
+			p                repository.CreateAuditLogParam
+			insertQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewAuditRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.CreateAuditLogParam{
+				Id:        "mock-id",
+				ClientId:  "mock-client-id",
+				Action:    "delete",
+				FileId:    "mock-file-id",
+				IpAddress: "10.0.0.1",
+				RequestId: "mock-request-id",
+				Outcome:   "success",
+			}
+			insertQuery = regexp.QuoteMeta(`
+				INSERT INTO audit_log (
+					id, client_id, action, file_id, ip_address,
+					request_id, outcome, reason, created_at
+				)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`)
+		})
+
+		When("failed create audit log", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.CreateAuditLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success create audit log", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WithArgs(
+						p.Id, p.ClientId, p.Action, p.FileId, p.IpAddress,
+						p.RequestId, p.Outcome, "", currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.CreateAuditLog(ctx, p)
+
+				Expect(res).To(Equal(&repository.CreateAuditLogResult{
+					CreatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListAuditLog function", Label("unit"), func() {
+		var (
+			ctx      context.Context
+			dbClient sqlmock.Sqlmock
+			repo     repository.AuditRepository
+			p        repository.ListAuditLogParam
+			columns  []string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewAuditRepository(repository_mysql.WithDbClient(db))
+			p = repository.ListAuditLogParam{
+				Limit: 2,
+			}
+			columns = []string{
+				"id", "client_id", "action", "file_id", "ip_address",
+				"request_id", "outcome", "reason", "created_at",
+			}
+		})
+
+		When("failed query audit logs", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(regexp.QuoteMeta(`WHERE id > ?`)).
+					WithArgs("", 2).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListAuditLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan row", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("mock-id")
+				dbClient.
+					ExpectQuery(regexp.QuoteMeta(`WHERE id > ?`)).
+					WillReturnRows(rows)
+
+				res, err := repo.ListAuditLog(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("filters are specified", func() {
+			It("should return result", func() {
+				createdFrom := time.UnixMilli(1660000000000)
+				createdTo := time.UnixMilli(1660086400000)
+				p = repository.ListAuditLogParam{
+					ClientId:    "mock-client-id",
+					Action:      "delete",
+					FileId:      "mock-file-id",
+					CreatedFrom: &createdFrom,
+					CreatedTo:   &createdTo,
+					AfterId:     "mock-after-id",
+					Limit:       2,
+				}
+				rows := sqlmock.NewRows(columns).
+					AddRow(
+						"mock-id-1", "mock-client-id", "delete", "mock-file-id", "10.0.0.1",
+						"mock-request-id", "success", "", int64(1660000001000),
+					)
+				dbClient.
+					ExpectQuery(regexp.QuoteMeta(`
+						FROM audit_log
+						WHERE id > ? AND client_id = ? AND action = ? AND file_id = ? AND created_at >= ? AND created_at < ?
+						ORDER BY id ASC
+						LIMIT ?
+					`)).
+					WithArgs(
+						"mock-after-id", "mock-client-id", "delete", "mock-file-id",
+						int64(1660000000000), int64(1660086400000), 2,
+					).
+					WillReturnRows(rows)
+
+				res, err := repo.ListAuditLog(ctx, p)
+
+				Expect(res).To(Equal(&repository.ListAuditLogResult{
+					Items: []repository.AuditLog{
+						{
+							Id:        "mock-id-1",
+							ClientId:  "mock-client-id",
+							Action:    "delete",
+							FileId:    "mock-file-id",
+							IpAddress: "10.0.0.1",
+							RequestId: "mock-request-id",
+							Outcome:   "success",
+							CreatedAt: time.UnixMilli(1660000001000),
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-seidon/local/internal/datetime"
 	"github.com/go-seidon/local/internal/repository"
@@ -47,6 +50,20 @@ func (r *FileRepository) DeleteFile(ctx context.Context, p repository.DeleteFile
 		return nil, repository.ErrorRecordDeleted
 	}
 
+	err = r.checkAccess(tx, checkAccessParam{
+		File:         file,
+		ClientId:     p.ClientId,
+		Permission:   repository.PERMISSION_DELETE,
+		AllowUnowned: p.AllowUnowned,
+	})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
 	deleteQuery := `
 		UPDATE file 
 		SET deleted_at = ?
@@ -75,6 +92,47 @@ func (r *FileRepository) DeleteFile(ctx context.Context, p repository.DeleteFile
 		return nil, fmt.Errorf("record is not updated")
 	}
 
+	if file.ClientId != "" {
+		releaseQuery := `
+			UPDATE client_quota
+			SET used_size = GREATEST(used_size - ?, 0), 
+				used_file = GREATEST(used_file - 1, 0), 
+				updated_at = ?
+			WHERE client_id = ?
+		`
+		_, err = tx.Exec(
+			releaseQuery,
+			file.Size,
+			currentTimestamp.UnixMilli(),
+			file.ClientId,
+		)
+		if err != nil {
+			txErr := tx.Rollback()
+			if txErr != nil {
+				return nil, txErr
+			}
+			return nil, err
+		}
+	}
+
+	err = insertOutboxEvent(tx, insertOutboxEventParam{
+		EventType: repository.EVENT_FILE_DELETED,
+		FileId:    file.UniqueId,
+		ClientId:  file.ClientId,
+		Name:      file.Name,
+		Mimetype:  file.MimeType,
+		Extension: file.Extension,
+		Size:      file.Size,
+		Timestamp: currentTimestamp.UnixMilli(),
+	})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
 	err = p.DeleteFn(ctx, repository.DeleteFnParam{
 		FilePath: file.Path,
 	})
@@ -92,6 +150,12 @@ func (r *FileRepository) DeleteFile(ctx context.Context, p repository.DeleteFile
 	}
 
 	res := &repository.DeleteFileResult{
+		UniqueId:  file.UniqueId,
+		Name:      file.Name,
+		MimeType:  file.MimeType,
+		Extension: file.Extension,
+		Size:      file.Size,
+		ClientId:  file.ClientId,
 		DeletedAt: currentTimestamp,
 	}
 	return res, nil
@@ -109,12 +173,28 @@ func (r *FileRepository) RetrieveFile(ctx context.Context, p repository.Retrieve
 		return nil, repository.ErrorRecordDeleted
 	}
 
+	if !p.SkipAccessCheck {
+		err = r.checkAccess(r.dbClient, checkAccessParam{
+			File:         file,
+			ClientId:     p.ClientId,
+			Permission:   repository.PERMISSION_READ,
+			AllowUnowned: p.AllowUnowned,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	res := &repository.RetrieveFileResult{
-		UniqueId:  file.UniqueId,
-		Name:      file.Name,
-		Path:      file.Path,
-		MimeType:  file.MimeType,
-		Extension: file.Extension,
+		UniqueId:   file.UniqueId,
+		Name:       file.Name,
+		Path:       file.Path,
+		MimeType:   file.MimeType,
+		Extension:  file.Extension,
+		Size:       file.Size,
+		Codec:      file.Codec,
+		ClientId:   file.ClientId,
+		Visibility: file.Visibility,
 	}
 	return res, nil
 }
@@ -122,6 +202,11 @@ func (r *FileRepository) RetrieveFile(ctx context.Context, p repository.Retrieve
 func (r *FileRepository) CreateFile(ctx context.Context, p repository.CreateFileParam) (*repository.CreateFileResult, error) {
 	currentTimestamp := r.clock.Now()
 
+	visibility := repository.VISIBILITY_PRIVATE
+	if p.Visibility != "" {
+		visibility = p.Visibility
+	}
+
 	tx, err := r.dbClient.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
 		return nil, err
@@ -131,9 +216,10 @@ func (r *FileRepository) CreateFile(ctx context.Context, p repository.CreateFile
 		INSERT INTO file (
 			id, name, path, 
 			mimetype, extension, size, 
+			encryption_key_id, codec, client_id, visibility,
 			created_at, updated_at
 		) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err = tx.Exec(
 		insertQuery,
@@ -143,6 +229,10 @@ func (r *FileRepository) CreateFile(ctx context.Context, p repository.CreateFile
 		p.Mimetype,
 		p.Extension,
 		p.Size,
+		p.EncryptionKeyId,
+		p.Codec,
+		p.ClientId,
+		visibility,
 		currentTimestamp.UnixMilli(),
 		currentTimestamp.UnixMilli(),
 	)
@@ -154,6 +244,76 @@ func (r *FileRepository) CreateFile(ctx context.Context, p repository.CreateFile
 		return nil, err
 	}
 
+	if p.ClientId != "" {
+		err = r.reserveQuota(tx, reserveQuotaParam{
+			ClientId:  p.ClientId,
+			Size:      p.Size,
+			Timestamp: currentTimestamp.UnixMilli(),
+		})
+		if err != nil {
+			txErr := tx.Rollback()
+			if txErr != nil {
+				return nil, txErr
+			}
+			return nil, err
+		}
+	}
+
+	if len(p.Attributes) > 0 {
+		attrQuery, attrArgs := buildInsertAttributeQuery(p.UniqueId, p.Attributes, currentTimestamp.UnixMilli())
+		_, err = tx.Exec(attrQuery, attrArgs...)
+		if err != nil {
+			txErr := tx.Rollback()
+			if txErr != nil {
+				return nil, txErr
+			}
+			return nil, err
+		}
+	}
+
+	if p.Scan != nil {
+		scanQuery := `
+			INSERT INTO file_scan (
+				file_id, status, engine, 
+				signature, scanned_at
+			)
+			VALUES (?, ?, ?, ?, ?)
+		`
+		_, err = tx.Exec(
+			scanQuery,
+			p.UniqueId,
+			p.Scan.Status,
+			p.Scan.Engine,
+			p.Scan.Signature,
+			p.Scan.ScannedAt.UnixMilli(),
+		)
+		if err != nil {
+			txErr := tx.Rollback()
+			if txErr != nil {
+				return nil, txErr
+			}
+			return nil, err
+		}
+	}
+
+	err = insertOutboxEvent(tx, insertOutboxEventParam{
+		EventType: repository.EVENT_FILE_UPLOADED,
+		FileId:    p.UniqueId,
+		ClientId:  p.ClientId,
+		Name:      p.Name,
+		Mimetype:  p.Mimetype,
+		Extension: p.Extension,
+		Size:      p.Size,
+		Timestamp: currentTimestamp.UnixMilli(),
+	})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
 	err = p.CreateFn(ctx, repository.CreateFnParam{
 		FilePath: p.Path,
 	})
@@ -170,13 +330,275 @@ func (r *FileRepository) CreateFile(ctx context.Context, p repository.CreateFile
 		return nil, txErr
 	}
 	res := &repository.CreateFileResult{
-		UniqueId:  p.UniqueId,
-		Name:      p.Name,
-		Path:      p.Path,
-		Mimetype:  p.Mimetype,
-		Extension: p.Extension,
-		Size:      p.Size,
-		CreatedAt: currentTimestamp,
+		UniqueId:        p.UniqueId,
+		Name:            p.Name,
+		Path:            p.Path,
+		Mimetype:        p.Mimetype,
+		Extension:       p.Extension,
+		Size:            p.Size,
+		Attributes:      p.Attributes,
+		Scan:            p.Scan,
+		EncryptionKeyId: p.EncryptionKeyId,
+		Codec:           p.Codec,
+		ClientId:        p.ClientId,
+		Visibility:      visibility,
+		CreatedAt:       currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *FileRepository) ListFile(ctx context.Context, p repository.ListFileParam) (*repository.ListFileResult, error) {
+	listQuery := `
+		SELECT 
+			id, name, path,
+			mimetype, extension, size,
+			encryption_key_id, client_id, created_at
+		FROM file
+		WHERE id > ? AND deleted_at IS NULL
+		ORDER BY id ASC
+		LIMIT ?
+	`
+	rows, err := r.dbClient.Query(listQuery, p.AfterId, p.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []repository.ListFileItem{}
+	for rows.Next() {
+		var item repository.ListFileItem
+		var createdAt int64
+		err := rows.Scan(
+			&item.UniqueId,
+			&item.Name,
+			&item.Path,
+			&item.Mimetype,
+			&item.Extension,
+			&item.Size,
+			&item.EncryptionKeyId,
+			&item.ClientId,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.CreatedAt = time.UnixMilli(createdAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListFileResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (r *FileRepository) UpdateFilePath(ctx context.Context, p repository.UpdateFilePathParam) (*repository.UpdateFilePathResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	tx, err := r.dbClient.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := r.findFile(ctx, findFileParam{
+		UniqueId:      p.UniqueId,
+		DbTransaction: tx,
+		ShouldLock:    true,
+	})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	if file.DeletedAt != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, repository.ErrorRecordDeleted
+	}
+
+	if file.Path != p.OldPath {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, repository.ErrorRecordChanged
+	}
+
+	updateQuery := `
+		UPDATE file 
+		SET path = ?, updated_at = ?
+		WHERE id = ?
+	`
+	qRes, err := tx.Exec(
+		updateQuery,
+		p.NewPath,
+		currentTimestamp.UnixMilli(),
+		file.UniqueId,
+	)
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected != 1 {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, fmt.Errorf("record is not updated")
+	}
+
+	err = p.MoveFn(ctx, repository.MoveFnParam{
+		OldPath: file.Path,
+		NewPath: p.NewPath,
+	})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	txErr := tx.Commit()
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	res := &repository.UpdateFilePathResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *FileRepository) UpdateFileKey(ctx context.Context, p repository.UpdateFileKeyParam) (*repository.UpdateFileKeyResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	tx, err := r.dbClient.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := r.findFile(ctx, findFileParam{
+		UniqueId:      p.UniqueId,
+		DbTransaction: tx,
+		ShouldLock:    true,
+	})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	if file.DeletedAt != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, repository.ErrorRecordDeleted
+	}
+
+	if file.EncryptionKeyId != p.OldKeyId {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, repository.ErrorRecordChanged
+	}
+
+	updateQuery := `
+		UPDATE file 
+		SET encryption_key_id = ?, updated_at = ?
+		WHERE id = ?
+	`
+	qRes, err := tx.Exec(
+		updateQuery,
+		p.NewKeyId,
+		currentTimestamp.UnixMilli(),
+		file.UniqueId,
+	)
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected != 1 {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, fmt.Errorf("record is not updated")
+	}
+
+	err = p.RekeyFn(ctx, repository.RekeyFnParam{
+		FilePath: file.Path,
+	})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	txErr := tx.Commit()
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	res := &repository.UpdateFileKeyResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *FileRepository) UpdateFileVisibility(ctx context.Context, p repository.UpdateFileVisibilityParam) (*repository.UpdateFileVisibilityResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	updateQuery := `
+		UPDATE file
+		SET visibility = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+	qRes, err := r.dbClient.Exec(
+		updateQuery,
+		p.Visibility,
+		currentTimestamp.UnixMilli(),
+		p.UniqueId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected != 1 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.UpdateFileVisibilityResult{
+		UpdatedAt: currentTimestamp,
 	}
 	return res, nil
 }
@@ -193,6 +615,7 @@ func (r *FileRepository) findFile(ctx context.Context, p findFileParam) (*findFi
 		SELECT 
 			id, name, path,
 			mimetype, extension, size,
+			encryption_key_id, codec, client_id, visibility,
 			created_at, updated_at, deleted_at
 		FROM file
 		WHERE id = ?
@@ -210,6 +633,10 @@ func (r *FileRepository) findFile(ctx context.Context, p findFileParam) (*findFi
 		&res.MimeType,
 		&res.Extension,
 		&res.Size,
+		&res.EncryptionKeyId,
+		&res.Codec,
+		&res.ClientId,
+		&res.Visibility,
 		&res.CreatedAt,
 		&res.UpdatedAt,
 		&res.DeletedAt,
@@ -224,6 +651,135 @@ func (r *FileRepository) findFile(ctx context.Context, p findFileParam) (*findFi
 	return nil, err
 }
 
+// the owner and the client having share grant are allowed to access the file,
+// file uploaded without client is only accessible when it is allowed by the caller
+func (r *FileRepository) checkAccess(q Query, p checkAccessParam) error {
+	if p.ClientId == "" {
+		return repository.ErrorAccessDenied
+	}
+	if p.File.ClientId == p.ClientId {
+		return nil
+	}
+	if p.File.ClientId == "" {
+		if p.AllowUnowned {
+			return nil
+		}
+		return repository.ErrorAccessDenied
+	}
+
+	sqlQuery := `
+		SELECT file_id
+		FROM file_share
+		WHERE file_id = ? AND client_id = ? AND permission = ?
+	`
+	var fileId string
+	err := q.QueryRow(
+		sqlQuery,
+		p.File.UniqueId,
+		p.ClientId,
+		p.Permission,
+	).Scan(&fileId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrorAccessDenied
+	}
+	return err
+}
+
+func (r *FileRepository) reserveQuota(tx *sql.Tx, p reserveQuotaParam) error {
+	findQuery := `
+		SELECT 
+			max_size, max_file, 
+			used_size, used_file
+		FROM client_quota
+		WHERE client_id = ?
+		FOR UPDATE
+	`
+	var maxSize, maxFile, usedSize, usedFile int64
+	err := tx.QueryRow(findQuery, p.ClientId).Scan(
+		&maxSize, &maxFile,
+		&usedSize, &usedFile,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		insertQuery := `
+			INSERT INTO client_quota (
+				client_id, used_size, used_file, 
+				created_at, updated_at
+			)
+			VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE 
+				used_size = used_size + VALUES(used_size), 
+				used_file = used_file + VALUES(used_file), 
+				updated_at = VALUES(updated_at)
+		`
+		_, err = tx.Exec(
+			insertQuery,
+			p.ClientId, p.Size, 1,
+			p.Timestamp, p.Timestamp,
+		)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if maxSize > 0 && usedSize+p.Size > maxSize {
+		return repository.ErrorQuotaExceeded
+	}
+	if maxFile > 0 && usedFile+1 > maxFile {
+		return repository.ErrorQuotaExceeded
+	}
+
+	updateQuery := `
+		UPDATE client_quota
+		SET used_size = used_size + ?, 
+			used_file = used_file + 1, 
+			updated_at = ?
+		WHERE client_id = ?
+	`
+	_, err = tx.Exec(
+		updateQuery,
+		p.Size,
+		p.Timestamp,
+		p.ClientId,
+	)
+	return err
+}
+
+func buildInsertAttributeQuery(fileId string, attrs map[string]string, createdAt int64) (string, []interface{}) {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	args := make([]interface{}, 0, len(names)*4)
+	for _, name := range names {
+		values = append(values, "(?, ?, ?, ?)")
+		args = append(args, fileId, name, attrs[name], createdAt)
+	}
+
+	query := `
+		INSERT INTO file_attribute (
+			file_id, name, value, created_at
+		)
+		VALUES ` + strings.Join(values, ", ")
+	return query, args
+}
+
+type checkAccessParam struct {
+	File         *findFileResult
+	ClientId     string
+	Permission   string
+	AllowUnowned bool
+}
+
+type reserveQuotaParam struct {
+	ClientId  string
+	Size      int64
+	Timestamp int64
+}
+
 type findFileParam struct {
 	UniqueId      string
 	ShouldLock    bool
@@ -231,15 +787,19 @@ type findFileParam struct {
 }
 
 type findFileResult struct {
-	UniqueId  string
-	Name      string
-	Path      string
-	MimeType  string
-	Extension string
-	Size      int64
-	CreatedAt int64
-	UpdatedAt int64
-	DeletedAt *int64
+	UniqueId        string
+	Name            string
+	Path            string
+	MimeType        string
+	Extension       string
+	Size            int64
+	EncryptionKeyId string
+	Codec           string
+	ClientId        string
+	Visibility      string
+	CreatedAt       int64
+	UpdatedAt       int64
+	DeletedAt       *int64
 }
 
 func NewFileRepository(opts ...RepoOption) (*FileRepository, error) {
@@ -61,6 +61,7 @@ var _ = Describe("File Repository", func() {
 			p                repository.DeleteFileParam
 			findFileQuery    string
 			deleteFileQuery  string
+			outboxQuery      string
 			fileRows         *sqlmock.Rows
 		)
 
@@ -84,7 +85,9 @@ var _ = Describe("File Repository", func() {
 			repo, _ = repository_mysql.NewFileRepository(clockOpt, dbOpt)
 
 			p = repository.DeleteFileParam{
-				UniqueId: "mock-unique-id",
+				UniqueId:     "mock-unique-id",
+				ClientId:     "mock-client-id",
+				AllowUnowned: true,
 				DeleteFn: func(ctx context.Context, p repository.DeleteFnParam) error {
 					return nil
 				},
@@ -93,6 +96,7 @@ var _ = Describe("File Repository", func() {
 				SELECT 
 					id, name, path,
 					mimetype, extension, size,
+					encryption_key_id, codec, client_id, visibility,
 					created_at, updated_at, deleted_at
 				FROM file
 				WHERE id = ?
@@ -102,9 +106,18 @@ var _ = Describe("File Repository", func() {
 				SET deleted_at = ?
 				WHERE id = ?
 			`)
+			outboxQuery = regexp.QuoteMeta(`
+				INSERT INTO file_event_outbox (
+					idempotency_key, event_type, file_id, client_id,
+					name, mimetype, extension, size,
+					next_attempt_at, created_at
+				)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`)
 			fileRows = sqlmock.NewRows([]string{
 				"id", "name", "path",
 				"mimetype", "extension", "size",
+				"encryption_key_id", "codec", "client_id", "visibility",
 				"created_at", "updated_at", "deleted_at",
 			}).AddRow(
 				"mock-unique-id",
@@ -113,6 +126,10 @@ var _ = Describe("File Repository", func() {
 				"mock-mimetype",
 				"mock-extension",
 				0,
+				"",
+				"",
+				"",
+				"private",
 				0,
 				0,
 				nil,
@@ -138,6 +155,7 @@ var _ = Describe("File Repository", func() {
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "path",
 					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
 					"created_at", "updated_at", "deleted_at",
 				}).AddRow(
 					"mock-unique-id",
@@ -146,6 +164,10 @@ var _ = Describe("File Repository", func() {
 					"mock-mimetype",
 					"mock-extension",
 					"invalid_int_value", //should be int64
+					"",
+					"",
+					"",
+					"private",
 					0,
 					0,
 					0,
@@ -208,6 +230,7 @@ var _ = Describe("File Repository", func() {
 				fileRows = sqlmock.NewRows([]string{
 					"id", "name", "path",
 					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
 					"created_at", "updated_at", "deleted_at",
 				}).AddRow(
 					"mock-unique-id",
@@ -216,6 +239,10 @@ var _ = Describe("File Repository", func() {
 					"mock-mimetype",
 					"mock-extension",
 					0,
+					"",
+					"",
+					"",
+					"private",
 					0,
 					0,
 					1, //deleted
@@ -237,6 +264,7 @@ var _ = Describe("File Repository", func() {
 				fileRows = sqlmock.NewRows([]string{
 					"id", "name", "path",
 					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
 					"created_at", "updated_at", "deleted_at",
 				}).AddRow(
 					"mock-unique-id",
@@ -245,6 +273,10 @@ var _ = Describe("File Repository", func() {
 					"mock-mimetype",
 					"mock-extension",
 					0,
+					"",
+					"",
+					"",
+					"private",
 					0,
 					0,
 					1, //deleted
@@ -335,6 +367,25 @@ var _ = Describe("File Repository", func() {
 			})
 		})
 
+		When("failed insert outbox event", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.ExpectQuery(findFileQuery).WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(deleteFileQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
 		When("failed execute delete function", func() {
 			It("should return error", func() {
 				dbClient.ExpectBegin()
@@ -346,6 +397,21 @@ var _ = Describe("File Repository", func() {
 						p.UniqueId,
 					).
 					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WithArgs(
+						"file.deleted:mock-unique-id",
+						"file.deleted",
+						"mock-unique-id",
+						"",
+						"mock-name",
+						"mock-mimetype",
+						"mock-extension",
+						0,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(1))
 				p.DeleteFn = func(ctx context.Context, p repository.DeleteFnParam) error {
 					return fmt.Errorf("delete fn error")
 				}
@@ -369,6 +435,21 @@ var _ = Describe("File Repository", func() {
 						p.UniqueId,
 					).
 					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WithArgs(
+						"file.deleted:mock-unique-id",
+						"file.deleted",
+						"mock-unique-id",
+						"",
+						"mock-name",
+						"mock-mimetype",
+						"mock-extension",
+						0,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(1))
 				p.DeleteFn = func(ctx context.Context, p repository.DeleteFnParam) error {
 					return fmt.Errorf("delete fn error")
 				}
@@ -392,6 +473,21 @@ var _ = Describe("File Repository", func() {
 						p.UniqueId,
 					).
 					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WithArgs(
+						"file.deleted:mock-unique-id",
+						"file.deleted",
+						"mock-unique-id",
+						"",
+						"mock-name",
+						"mock-mimetype",
+						"mock-extension",
+						0,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(1))
 				dbClient.ExpectCommit().WillReturnError(fmt.Errorf("commit error"))
 
 				res, err := repo.DeleteFile(ctx, p)
@@ -412,17 +508,195 @@ var _ = Describe("File Repository", func() {
 						p.UniqueId,
 					).
 					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WithArgs(
+						"file.deleted:mock-unique-id",
+						"file.deleted",
+						"mock-unique-id",
+						"",
+						"mock-name",
+						"mock-mimetype",
+						"mock-extension",
+						0,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.DeleteFile(ctx, p)
+
+				expectedRes := &repository.DeleteFileResult{
+					UniqueId:  "mock-unique-id",
+					Name:      "mock-name",
+					MimeType:  "mock-mimetype",
+					Extension: "mock-extension",
+					DeletedAt: currentTimestamp,
+				}
+				Expect(res).To(Equal(expectedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file is owned by a client", func() {
+			It("should release the client quota", func() {
+				releaseQuery := regexp.QuoteMeta(`
+					UPDATE client_quota
+					SET used_size = GREATEST(used_size - ?, 0), 
+						used_file = GREATEST(used_file - 1, 0), 
+						updated_at = ?
+					WHERE client_id = ?
+				`)
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"mock-mimetype", "mock-extension", 200,
+					"", "", "mock-client-id", "private", 0, 0, nil,
+				)
+				dbClient.ExpectBegin()
+				dbClient.ExpectQuery(findFileQuery).WillReturnRows(rows)
+				dbClient.
+					ExpectExec(deleteFileQuery).
+					WithArgs(
+						currentTimestamp.UnixMilli(),
+						p.UniqueId,
+					).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(releaseQuery).
+					WithArgs(
+						200,
+						currentTimestamp.UnixMilli(),
+						"mock-client-id",
+					).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WithArgs(
+						"file.deleted:mock-unique-id",
+						"file.deleted",
+						"mock-unique-id",
+						"mock-client-id",
+						"mock-name",
+						"mock-mimetype",
+						"mock-extension",
+						200,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(1))
 				dbClient.ExpectCommit()
 
 				res, err := repo.DeleteFile(ctx, p)
 
 				expectedRes := &repository.DeleteFileResult{
+					UniqueId:  "mock-unique-id",
+					Name:      "mock-name",
+					MimeType:  "mock-mimetype",
+					Extension: "mock-extension",
+					Size:      200,
+					ClientId:  "mock-client-id",
 					DeletedAt: currentTimestamp,
 				}
 				Expect(res).To(Equal(expectedRes))
 				Expect(err).To(BeNil())
 			})
 		})
+
+		When("client is not allowed to delete the file", func() {
+			It("should return error", func() {
+				p.ClientId = "mock-other-client-id"
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"mock-mimetype", "mock-extension", 200,
+					"", "", "mock-client-id", "private", 0, 0, nil,
+				)
+				findShareQuery := regexp.QuoteMeta(`
+					SELECT file_id
+					FROM file_share
+					WHERE file_id = ? AND client_id = ? AND permission = ?
+				`)
+				dbClient.ExpectBegin()
+				dbClient.ExpectQuery(findFileQuery).WillReturnRows(rows)
+				dbClient.
+					ExpectQuery(findShareQuery).
+					WithArgs("mock-unique-id", "mock-other-client-id", "delete").
+					WillReturnError(sql.ErrNoRows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorAccessDenied))
+			})
+		})
+
+		When("client is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				dbClient.ExpectBegin()
+				dbClient.ExpectQuery(findFileQuery).WillReturnRows(fileRows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorAccessDenied))
+			})
+		})
+
+		When("file has no owner and it is not allowed", func() {
+			It("should return error", func() {
+				p.AllowUnowned = false
+				dbClient.ExpectBegin()
+				dbClient.ExpectQuery(findFileQuery).WillReturnRows(fileRows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorAccessDenied))
+			})
+		})
+
+		When("failed release client quota", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"mock-mimetype", "mock-extension", 200,
+					"", "", "mock-client-id", "private", 0, 0, nil,
+				)
+				dbClient.ExpectBegin()
+				dbClient.ExpectQuery(findFileQuery).WillReturnRows(rows)
+				dbClient.
+					ExpectExec(deleteFileQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec("UPDATE client_quota").
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
 	})
 
 	Context("DeleteFile function", Label("integration"), Ordered, func() {
@@ -452,7 +726,9 @@ var _ = Describe("File Repository", func() {
 
 		BeforeEach(func() {
 			p = repository.DeleteFileParam{
-				UniqueId: "mock-unique-id",
+				UniqueId:     "mock-unique-id",
+				ClientId:     "mock-client-id",
+				AllowUnowned: true,
 				DeleteFn: func(ctx context.Context, p repository.DeleteFnParam) error {
 					return nil
 				},
@@ -508,12 +784,13 @@ var _ = Describe("File Repository", func() {
 
 	Context("RetrieveFile function", Label("unit"), func() {
 		var (
-			ctx           context.Context
-			dbClient      sqlmock.Sqlmock
-			repo          *repository_mysql.FileRepository
-			p             repository.RetrieveFileParam
-			findFileQuery string
-			fileRows      *sqlmock.Rows
+			ctx            context.Context
+			dbClient       sqlmock.Sqlmock
+			repo           *repository_mysql.FileRepository
+			p              repository.RetrieveFileParam
+			findFileQuery  string
+			findShareQuery string
+			fileRows       *sqlmock.Rows
 		)
 
 		BeforeEach(func() {
@@ -529,19 +806,28 @@ var _ = Describe("File Repository", func() {
 			repo, _ = repository_mysql.NewFileRepository(dbOpt)
 
 			p = repository.RetrieveFileParam{
-				UniqueId: "mock-unique-id",
+				UniqueId:     "mock-unique-id",
+				ClientId:     "mock-client-id",
+				AllowUnowned: true,
 			}
 			findFileQuery = regexp.QuoteMeta(`
 				SELECT 
 					id, name, path,
 					mimetype, extension, size,
+					encryption_key_id, codec, client_id, visibility,
 					created_at, updated_at, deleted_at
 				FROM file
 				WHERE id = ?
 			`)
+			findShareQuery = regexp.QuoteMeta(`
+				SELECT file_id
+				FROM file_share
+				WHERE file_id = ? AND client_id = ? AND permission = ?
+			`)
 			fileRows = sqlmock.NewRows([]string{
 				"id", "name", "path",
 				"mimetype", "extension", "size",
+				"encryption_key_id", "codec", "client_id", "visibility",
 				"created_at", "updated_at", "deleted_at",
 			}).AddRow(
 				"mock-unique-id",
@@ -550,6 +836,10 @@ var _ = Describe("File Repository", func() {
 				"mock-mimetype",
 				"mock-extension",
 				0,
+				"",
+				"",
+				"",
+				"private",
 				0,
 				0,
 				nil,
@@ -561,6 +851,7 @@ var _ = Describe("File Repository", func() {
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "path",
 					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
 					"created_at", "updated_at", "deleted_at",
 				}).AddRow(
 					"mock-unique-id",
@@ -569,6 +860,10 @@ var _ = Describe("File Repository", func() {
 					"mock-mimetype",
 					"mock-extension",
 					"invalid_int_value", //should be int64
+					"",
+					"",
+					"",
+					"private",
 					0,
 					0,
 					0,
@@ -611,6 +906,7 @@ var _ = Describe("File Repository", func() {
 				fileRows = sqlmock.NewRows([]string{
 					"id", "name", "path",
 					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
 					"created_at", "updated_at", "deleted_at",
 				}).AddRow(
 					"mock-unique-id",
@@ -619,6 +915,10 @@ var _ = Describe("File Repository", func() {
 					"mock-mimetype",
 					"mock-extension",
 					0,
+					"",
+					"",
+					"",
+					"private",
 					0,
 					0,
 					1,
@@ -641,118 +941,344 @@ var _ = Describe("File Repository", func() {
 				res, err := repo.RetrieveFile(ctx, p)
 
 				eRes := &repository.RetrieveFileResult{
-					UniqueId:  "mock-unique-id",
-					Name:      "mock-name",
-					Path:      "mock-path",
-					MimeType:  "mock-mimetype",
-					Extension: "mock-extension",
+					UniqueId:   "mock-unique-id",
+					Name:       "mock-name",
+					Path:       "mock-path",
+					MimeType:   "mock-mimetype",
+					Extension:  "mock-extension",
+					Visibility: "private",
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
 			})
 		})
-	})
-
-	Context("CreateFile function", Label("unit"), func() {
-		var (
-			ctx              context.Context
-			currentTimestamp time.Time
-			dbClient         sqlmock.Sqlmock
-			repo             *repository_mysql.FileRepository
-			p                repository.CreateFileParam
-			insertSqlQuery   string
-		)
-
-		BeforeEach(func() {
-			t := GinkgoT()
-			ctrl := gomock.NewController(t)
-			ctx = context.Background()
-			currentTimestamp = time.Now()
-			clock := mock.NewMockClock(ctrl)
-			clock.
-				EXPECT().
-				Now().
-				Return(currentTimestamp).
-				Times(1)
 
-			db, mock, err := sqlmock.New()
-			if err != nil {
-				AbortSuite("failed create db mock: " + err.Error())
-			}
-			dbClient = mock
+		When("client is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				dbClient.ExpectQuery(findFileQuery).
+					WillReturnRows(fileRows)
 
-			dbOpt := repository_mysql.WithDbClient(db)
-			clockOpt := repository_mysql.WithClock(clock)
-			repo, _ = repository_mysql.NewFileRepository(dbOpt, clockOpt)
+				res, err := repo.RetrieveFile(ctx, p)
 
-			p = repository.CreateFileParam{
-				UniqueId:  "mock-unique-id",
-				Name:      "mock-name",
-				Path:      "/temp",
-				Mimetype:  "image/jpeg",
-				Extension: "jpg",
-				Size:      200,
-				CreateFn: func(ctx context.Context, p repository.CreateFnParam) error {
-					return nil
-				},
-			}
-			insertSqlQuery = regexp.QuoteMeta(`
-				INSERT INTO file (
-					id, name, path, 
-					mimetype, extension, size, 
-					created_at, updated_at
-				) 
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-			`)
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorAccessDenied))
+			})
 		})
 
-		When("failed start db trx", func() {
+		When("file has no owner and it is not allowed", func() {
 			It("should return error", func() {
-				dbClient.
-					ExpectBegin().
-					WillReturnError(fmt.Errorf("db error"))
+				p.AllowUnowned = false
+				dbClient.ExpectQuery(findFileQuery).
+					WillReturnRows(fileRows)
 
-				res, err := repo.CreateFile(ctx, p)
+				res, err := repo.RetrieveFile(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("db error")))
+				Expect(err).To(Equal(repository.ErrorAccessDenied))
 			})
 		})
 
-		When("failed rollback insert record", func() {
-			It("should return error", func() {
-				dbClient.ExpectBegin()
-				dbClient.
-					ExpectExec(insertSqlQuery).
-					WithArgs(
-						p.UniqueId, p.Name, p.Path,
-						p.Mimetype, p.Extension, p.Size,
-						currentTimestamp.UnixMilli(),
-						currentTimestamp.UnixMilli(),
-					).
-					WillReturnError(fmt.Errorf("insert error"))
-				dbClient.ExpectRollback().
-					WillReturnError(fmt.Errorf("rollback error"))
+		When("access check is skipped", func() {
+			It("should return result", func() {
+				p.ClientId = ""
+				p.AllowUnowned = false
+				p.SkipAccessCheck = true
+				dbClient.ExpectQuery(findFileQuery).
+					WillReturnRows(fileRows)
 
-				res, err := repo.CreateFile(ctx, p)
+				res, err := repo.RetrieveFile(ctx, p)
 
-				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
 			})
 		})
 
-		When("failed insert record", func() {
+		When("client is not the owner and has no share grant", func() {
 			It("should return error", func() {
-				dbClient.ExpectBegin()
-				dbClient.
-					ExpectExec(insertSqlQuery).
-					WithArgs(
-						p.UniqueId, p.Name, p.Path,
-						p.Mimetype, p.Extension, p.Size,
-						currentTimestamp.UnixMilli(),
-						currentTimestamp.UnixMilli(),
-					).
-					WillReturnError(fmt.Errorf("insert error"))
+				p.ClientId = "mock-other-client-id"
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"text/plain", "txt", 2048,
+					"", "", "mock-client-id", "private", 0, 0, nil,
+				)
+				dbClient.ExpectQuery(findFileQuery).
+					WillReturnRows(rows)
+				dbClient.ExpectQuery(findShareQuery).
+					WithArgs("mock-unique-id", "mock-other-client-id", "read").
+					WillReturnError(sql.ErrNoRows)
+
+				res, err := repo.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorAccessDenied))
+			})
+		})
+
+		When("failed find share grant", func() {
+			It("should return error", func() {
+				p.ClientId = "mock-other-client-id"
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"text/plain", "txt", 2048,
+					"", "", "mock-client-id", "private", 0, 0, nil,
+				)
+				dbClient.ExpectQuery(findFileQuery).
+					WillReturnRows(rows)
+				dbClient.ExpectQuery(findShareQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client has share grant", func() {
+			It("should return result", func() {
+				p.ClientId = "mock-other-client-id"
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"text/plain", "txt", 2048,
+					"", "", "mock-client-id", "private", 0, 0, nil,
+				)
+				shareRows := sqlmock.NewRows([]string{"file_id"}).AddRow("mock-unique-id")
+				dbClient.ExpectQuery(findFileQuery).
+					WillReturnRows(rows)
+				dbClient.ExpectQuery(findShareQuery).
+					WithArgs("mock-unique-id", "mock-other-client-id", "read").
+					WillReturnRows(shareRows)
+
+				res, err := repo.RetrieveFile(ctx, p)
+
+				eRes := &repository.RetrieveFileResult{
+					UniqueId:   "mock-unique-id",
+					Name:       "mock-name",
+					Path:       "mock-path",
+					MimeType:   "text/plain",
+					Extension:  "txt",
+					Size:       2048,
+					ClientId:   "mock-client-id",
+					Visibility: "private",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file is compressed", func() {
+			It("should return the codec", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"text/plain", "txt", 2048,
+					"", "gzip", "", "private", 0, 0, nil,
+				)
+				dbClient.ExpectQuery(findFileQuery).
+					WillReturnRows(rows)
+
+				res, err := repo.RetrieveFile(ctx, p)
+
+				eRes := &repository.RetrieveFileResult{
+					UniqueId:   "mock-unique-id",
+					Name:       "mock-name",
+					Path:       "mock-path",
+					MimeType:   "text/plain",
+					Extension:  "txt",
+					Size:       2048,
+					Codec:      "gzip",
+					Visibility: "private",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CreateFile function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             *repository_mysql.FileRepository
+			p                repository.CreateFileParam
+			insertSqlQuery   string
+			insertAttrQuery  string
+			insertScanQuery  string
+			findQuotaQuery   string
+			insertQuotaQuery string
+			updateQuotaQuery string
+			outboxQuery      string
+		)
+
+		BeforeEach(func() {
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			clock := mock.NewMockClock(ctrl)
+			clock.
+				EXPECT().
+				Now().
+				Return(currentTimestamp).
+				Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			dbOpt := repository_mysql.WithDbClient(db)
+			clockOpt := repository_mysql.WithClock(clock)
+			repo, _ = repository_mysql.NewFileRepository(dbOpt, clockOpt)
+
+			p = repository.CreateFileParam{
+				UniqueId:   "mock-unique-id",
+				Name:       "mock-name",
+				Path:       "/temp",
+				Mimetype:   "image/jpeg",
+				Extension:  "jpg",
+				Size:       200,
+				Codec:      "gzip",
+				Visibility: "public",
+				CreateFn: func(ctx context.Context, p repository.CreateFnParam) error {
+					return nil
+				},
+			}
+			insertSqlQuery = regexp.QuoteMeta(`
+				INSERT INTO file (
+					id, name, path, 
+					mimetype, extension, size, 
+					encryption_key_id, codec, client_id, visibility,
+					created_at, updated_at
+				) 
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`)
+			insertAttrQuery = regexp.QuoteMeta(`
+				INSERT INTO file_attribute (
+					file_id, name, value, created_at
+				)
+				VALUES (?, ?, ?, ?)
+			`)
+			insertScanQuery = regexp.QuoteMeta(`
+				INSERT INTO file_scan (
+					file_id, status, engine, 
+					signature, scanned_at
+				)
+				VALUES (?, ?, ?, ?, ?)
+			`)
+			findQuotaQuery = regexp.QuoteMeta(`
+				SELECT 
+					max_size, max_file, 
+					used_size, used_file
+				FROM client_quota
+				WHERE client_id = ?
+				FOR UPDATE
+			`)
+			insertQuotaQuery = regexp.QuoteMeta(`
+				INSERT INTO client_quota (
+					client_id, used_size, used_file, 
+					created_at, updated_at
+				)
+				VALUES (?, ?, ?, ?, ?)
+				ON DUPLICATE KEY UPDATE 
+					used_size = used_size + VALUES(used_size), 
+					used_file = used_file + VALUES(used_file), 
+					updated_at = VALUES(updated_at)
+			`)
+			updateQuotaQuery = regexp.QuoteMeta(`
+				UPDATE client_quota
+				SET used_size = used_size + ?, 
+					used_file = used_file + 1, 
+					updated_at = ?
+				WHERE client_id = ?
+			`)
+			outboxQuery = regexp.QuoteMeta(`
+				INSERT INTO file_event_outbox (
+					idempotency_key, event_type, file_id, client_id,
+					name, mimetype, extension, size,
+					next_attempt_at, created_at
+				)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`)
+		})
+
+		When("failed start db trx", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectBegin().
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed rollback insert record", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WithArgs(
+						p.UniqueId, p.Name, p.Path,
+						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						p.Visibility,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnError(fmt.Errorf("insert error"))
+				dbClient.ExpectRollback().
+					WillReturnError(fmt.Errorf("rollback error"))
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+			})
+		})
+
+		When("failed insert record", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WithArgs(
+						p.UniqueId, p.Name, p.Path,
+						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						p.Visibility,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnError(fmt.Errorf("insert error"))
 				dbClient.ExpectRollback()
 
 				res, err := repo.CreateFile(ctx, p)
@@ -770,10 +1296,17 @@ var _ = Describe("File Repository", func() {
 					WithArgs(
 						p.UniqueId, p.Name, p.Path,
 						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						p.Visibility,
 						currentTimestamp.UnixMilli(),
 						currentTimestamp.UnixMilli(),
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
 				p.CreateFn = func(ctx context.Context, p repository.CreateFnParam) error {
 					return fmt.Errorf("execute error")
 				}
@@ -788,6 +1321,24 @@ var _ = Describe("File Repository", func() {
 			})
 		})
 
+		When("failed insert outbox event", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
 		When("failed execute create fn", func() {
 			It("should return error", func() {
 				dbClient.ExpectBegin()
@@ -796,10 +1347,17 @@ var _ = Describe("File Repository", func() {
 					WithArgs(
 						p.UniqueId, p.Name, p.Path,
 						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						p.Visibility,
 						currentTimestamp.UnixMilli(),
 						currentTimestamp.UnixMilli(),
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
 				p.CreateFn = func(ctx context.Context, p repository.CreateFnParam) error {
 					return fmt.Errorf("execute error")
 				}
@@ -820,10 +1378,17 @@ var _ = Describe("File Repository", func() {
 					WithArgs(
 						p.UniqueId, p.Name, p.Path,
 						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						p.Visibility,
 						currentTimestamp.UnixMilli(),
 						currentTimestamp.UnixMilli(),
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
 				dbClient.
 					ExpectCommit().
 					WillReturnError(fmt.Errorf("commit error"))
@@ -843,27 +1408,1177 @@ var _ = Describe("File Repository", func() {
 					WithArgs(
 						p.UniqueId, p.Name, p.Path,
 						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						p.Visibility,
 						currentTimestamp.UnixMilli(),
 						currentTimestamp.UnixMilli(),
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WithArgs(
+						"file.uploaded:mock-unique-id",
+						"file.uploaded",
+						p.UniqueId,
+						p.ClientId,
+						p.Name,
+						p.Mimetype,
+						p.Extension,
+						p.Size,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(1))
 				dbClient.ExpectCommit()
 
 				res, err := repo.CreateFile(ctx, p)
 
 				expectedRes := &repository.CreateFileResult{
-					UniqueId:  p.UniqueId,
-					Name:      p.Name,
-					Path:      p.Path,
-					Mimetype:  p.Mimetype,
-					Extension: p.Extension,
-					Size:      p.Size,
-					CreatedAt: currentTimestamp,
+					UniqueId:   p.UniqueId,
+					Name:       p.Name,
+					Path:       p.Path,
+					Mimetype:   p.Mimetype,
+					Extension:  p.Extension,
+					Size:       p.Size,
+					Codec:      p.Codec,
+					ClientId:   p.ClientId,
+					Visibility: p.Visibility,
+					CreatedAt:  currentTimestamp,
 				}
 				Expect(res).To(Equal(expectedRes))
 				Expect(err).To(BeNil())
 			})
 		})
-	})
 
+		When("visibility is not specified", func() {
+			It("should create private file", func() {
+				p.Visibility = ""
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WithArgs(
+						p.UniqueId, p.Name, p.Path,
+						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						repository.VISIBILITY_PRIVATE,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res.Visibility).To(Equal(repository.VISIBILITY_PRIVATE))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed rollback insert attribute", func() {
+			It("should return error", func() {
+				p.Attributes = map[string]string{
+					"width": "720",
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(insertAttrQuery).
+					WithArgs(
+						p.UniqueId, "width", "720", currentTimestamp.UnixMilli(),
+					).
+					WillReturnError(fmt.Errorf("insert error"))
+				dbClient.
+					ExpectRollback().
+					WillReturnError(fmt.Errorf("rollback error"))
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+			})
+		})
+
+		When("failed insert attribute", func() {
+			It("should return error", func() {
+				p.Attributes = map[string]string{
+					"width": "720",
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(insertAttrQuery).
+					WithArgs(
+						p.UniqueId, "width", "720", currentTimestamp.UnixMilli(),
+					).
+					WillReturnError(fmt.Errorf("insert error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("insert error")))
+			})
+		})
+
+		When("success create file with attributes", func() {
+			It("should return result", func() {
+				p.Attributes = map[string]string{
+					"width":  "720",
+					"height": "480",
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(regexp.QuoteMeta(`
+						INSERT INTO file_attribute (
+							file_id, name, value, created_at
+						)
+						VALUES (?, ?, ?, ?), (?, ?, ?, ?)
+					`)).
+					WithArgs(
+						p.UniqueId, "height", "480", currentTimestamp.UnixMilli(),
+						p.UniqueId, "width", "720", currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				expectedRes := &repository.CreateFileResult{
+					UniqueId:   p.UniqueId,
+					Name:       p.Name,
+					Path:       p.Path,
+					Mimetype:   p.Mimetype,
+					Extension:  p.Extension,
+					Size:       p.Size,
+					Attributes: p.Attributes,
+					Codec:      p.Codec,
+					ClientId:   p.ClientId,
+					Visibility: p.Visibility,
+					CreatedAt:  currentTimestamp,
+				}
+				Expect(res).To(Equal(expectedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed rollback insert scan", func() {
+			It("should return error", func() {
+				p.Scan = &repository.FileScan{
+					Status:    "clean",
+					Engine:    "clamav",
+					ScannedAt: currentTimestamp,
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(insertScanQuery).
+					WithArgs(
+						p.UniqueId, "clean", "clamav", "", currentTimestamp.UnixMilli(),
+					).
+					WillReturnError(fmt.Errorf("insert error"))
+				dbClient.
+					ExpectRollback().
+					WillReturnError(fmt.Errorf("rollback error"))
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+			})
+		})
+
+		When("failed insert scan", func() {
+			It("should return error", func() {
+				p.Scan = &repository.FileScan{
+					Status:    "clean",
+					Engine:    "clamav",
+					ScannedAt: currentTimestamp,
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(insertScanQuery).
+					WithArgs(
+						p.UniqueId, "clean", "clamav", "", currentTimestamp.UnixMilli(),
+					).
+					WillReturnError(fmt.Errorf("insert error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("insert error")))
+			})
+		})
+
+		When("success create file with scan result", func() {
+			It("should return result", func() {
+				p.Scan = &repository.FileScan{
+					Status:    "failed",
+					Engine:    "clamav",
+					ScannedAt: currentTimestamp,
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(insertScanQuery).
+					WithArgs(
+						p.UniqueId, "failed", "clamav", "", currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				expectedRes := &repository.CreateFileResult{
+					UniqueId:   p.UniqueId,
+					Name:       p.Name,
+					Path:       p.Path,
+					Mimetype:   p.Mimetype,
+					Extension:  p.Extension,
+					Size:       p.Size,
+					Scan:       p.Scan,
+					Codec:      p.Codec,
+					ClientId:   p.ClientId,
+					Visibility: p.Visibility,
+					CreatedAt:  currentTimestamp,
+				}
+				Expect(res).To(Equal(expectedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed find client quota", func() {
+			It("should return error", func() {
+				p.ClientId = "mock-client-id"
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WithArgs(p.ClientId).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client quota size is exceeded", func() {
+			It("should return error", func() {
+				p.ClientId = "mock-client-id"
+				rows := sqlmock.NewRows([]string{
+					"max_size", "max_file",
+					"used_size", "used_file",
+				}).AddRow(1000, 0, 900, 5)
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WithArgs(p.ClientId).
+					WillReturnRows(rows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorQuotaExceeded))
+			})
+		})
+
+		When("client quota file count is exceeded", func() {
+			It("should return error", func() {
+				p.ClientId = "mock-client-id"
+				rows := sqlmock.NewRows([]string{
+					"max_size", "max_file",
+					"used_size", "used_file",
+				}).AddRow(0, 5, 900, 5)
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WillReturnRows(rows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorQuotaExceeded))
+			})
+		})
+
+		When("client quota is available", func() {
+			It("should count the usage", func() {
+				p.ClientId = "mock-client-id"
+				rows := sqlmock.NewRows([]string{
+					"max_size", "max_file",
+					"used_size", "used_file",
+				}).AddRow(1000, 5, 800, 4)
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WithArgs(
+						p.UniqueId, p.Name, p.Path,
+						p.Mimetype, p.Extension, p.Size,
+						p.EncryptionKeyId,
+						p.Codec,
+						p.ClientId,
+						p.Visibility,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WithArgs(p.ClientId).
+					WillReturnRows(rows)
+				dbClient.
+					ExpectExec(updateQuotaQuery).
+					WithArgs(
+						p.Size,
+						currentTimestamp.UnixMilli(),
+						p.ClientId,
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(res.ClientId).To(Equal("mock-client-id"))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("client quota is not found", func() {
+			It("should create the usage record", func() {
+				p.ClientId = "mock-client-id"
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertSqlQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WithArgs(p.ClientId).
+					WillReturnError(sql.ErrNoRows)
+				dbClient.
+					ExpectExec(insertQuotaQuery).
+					WithArgs(
+						p.ClientId, p.Size, 1,
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(outboxQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.CreateFile(ctx, p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListFile function", Label("unit"), func() {
+		var (
+			ctx           context.Context
+			dbClient      sqlmock.Sqlmock
+			repo          *repository_mysql.FileRepository
+			p             repository.ListFileParam
+			listFileQuery string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewFileRepository(repository_mysql.WithDbClient(db))
+			p = repository.ListFileParam{
+				AfterId: "mock-after-id",
+				Limit:   2,
+			}
+			listFileQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, name, path,
+					mimetype, extension, size,
+					encryption_key_id, client_id, created_at
+				FROM file
+				WHERE id > ? AND deleted_at IS NULL
+				ORDER BY id ASC
+				LIMIT ?
+			`)
+		})
+
+		When("failed query files", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(listFileQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan row", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("mock-unique-id")
+				dbClient.
+					ExpectQuery(listFileQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnRows(rows)
+
+				res, err := repo.ListFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("failed iterate rows", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "client_id", "created_at",
+				}).
+					AddRow("mock-unique-id", "mock-name", "mock-path", "image/jpeg", "jpg", 100, "mock-key-id", "mock-client-id", 1659830400000).
+					RowError(0, fmt.Errorf("network error"))
+				dbClient.
+					ExpectQuery(listFileQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnRows(rows)
+
+				res, err := repo.ListFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("success list files", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "client_id", "created_at",
+				}).
+					AddRow("mock-id-1", "mock-name-1", "mock-path-1", "image/jpeg", "jpg", 100, "mock-key-id", "mock-client-id", 1659830400000).
+					AddRow("mock-id-2", "mock-name-2", "mock-path-2", "", "", 0, "", "", 1659830400001)
+				dbClient.
+					ExpectQuery(listFileQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnRows(rows)
+
+				res, err := repo.ListFile(ctx, p)
+
+				expectedRes := &repository.ListFileResult{
+					Items: []repository.ListFileItem{
+						{
+							UniqueId:        "mock-id-1",
+							Name:            "mock-name-1",
+							Path:            "mock-path-1",
+							Mimetype:        "image/jpeg",
+							Extension:       "jpg",
+							Size:            100,
+							EncryptionKeyId: "mock-key-id",
+							ClientId:        "mock-client-id",
+							CreatedAt:       time.UnixMilli(1659830400000),
+						},
+						{
+							UniqueId:  "mock-id-2",
+							Name:      "mock-name-2",
+							Path:      "mock-path-2",
+							CreatedAt: time.UnixMilli(1659830400001),
+						},
+					},
+				}
+				Expect(res).To(Equal(expectedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateFilePath function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             *repository_mysql.FileRepository
+			p                repository.UpdateFilePathParam
+			findFileQuery    string
+			updateFileQuery  string
+			fileRows         *sqlmock.Rows
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			clockOpt := repository_mysql.WithClock(clock)
+			dbOpt := repository_mysql.WithDbClient(db)
+			repo, _ = repository_mysql.NewFileRepository(clockOpt, dbOpt)
+
+			p = repository.UpdateFilePathParam{
+				UniqueId: "mock-unique-id",
+				OldPath:  "mock-path",
+				NewPath:  "mock-new-path",
+				MoveFn: func(ctx context.Context, p repository.MoveFnParam) error {
+					return nil
+				},
+			}
+			findFileQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, name, path,
+					mimetype, extension, size,
+					encryption_key_id, codec, client_id, visibility,
+					created_at, updated_at, deleted_at
+				FROM file
+				WHERE id = ?
+			`)
+			updateFileQuery = regexp.QuoteMeta(`
+				UPDATE file 
+				SET path = ?, updated_at = ?
+				WHERE id = ?
+			`)
+			fileRows = sqlmock.NewRows([]string{
+				"id", "name", "path",
+				"mimetype", "extension", "size",
+				"encryption_key_id", "codec", "client_id", "visibility",
+				"created_at", "updated_at", "deleted_at",
+			}).AddRow(
+				"mock-unique-id",
+				"mock-name",
+				"mock-path",
+				"mock-mimetype",
+				"mock-extension",
+				0,
+				"",
+				"",
+				"",
+				"private",
+				0,
+				0,
+				nil,
+			)
+		})
+
+		When("failed start db transaction", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectBegin().
+					WillReturnError(fmt.Errorf("failed start db trx"))
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed start db trx")))
+			})
+		})
+
+		When("failed find file", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnError(sql.ErrNoRows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("failed rollback find file", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.
+					ExpectRollback().
+					WillReturnError(fmt.Errorf("rollback error"))
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+			})
+		})
+
+		When("file is deleted", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"mock-mimetype", "mock-extension", 0,
+					"", "", "", "private", 0, 0, 1,
+				)
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(rows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordDeleted))
+			})
+		})
+
+		When("file path has been changed", func() {
+			It("should return error", func() {
+				p.OldPath = "mock-other-path"
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordChanged))
+			})
+		})
+
+		When("failed update file", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewPath, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("record is not updated", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewPath, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("record is not updated")))
+			})
+		})
+
+		When("failed execute move fn", func() {
+			It("should return error", func() {
+				var moveParam repository.MoveFnParam
+				p.MoveFn = func(ctx context.Context, mp repository.MoveFnParam) error {
+					moveParam = mp
+					return fmt.Errorf("disk error")
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewPath, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
+				Expect(moveParam).To(Equal(repository.MoveFnParam{
+					OldPath: "mock-path",
+					NewPath: "mock-new-path",
+				}))
+			})
+		})
+
+		When("failed commit db trx", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewPath, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectCommit().
+					WillReturnError(fmt.Errorf("commit error"))
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("commit error")))
+			})
+		})
+
+		When("success update file path", func() {
+			It("should return result", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewPath, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.UpdateFilePath(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateFilePathResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateFileKey function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             *repository_mysql.FileRepository
+			p                repository.UpdateFileKeyParam
+			findFileQuery    string
+			updateFileQuery  string
+			fileRows         *sqlmock.Rows
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			clockOpt := repository_mysql.WithClock(clock)
+			dbOpt := repository_mysql.WithDbClient(db)
+			repo, _ = repository_mysql.NewFileRepository(clockOpt, dbOpt)
+
+			p = repository.UpdateFileKeyParam{
+				UniqueId: "mock-unique-id",
+				OldKeyId: "mock-old-key-id",
+				NewKeyId: "mock-new-key-id",
+				RekeyFn: func(ctx context.Context, p repository.RekeyFnParam) error {
+					return nil
+				},
+			}
+			findFileQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, name, path,
+					mimetype, extension, size,
+					encryption_key_id, codec, client_id, visibility,
+					created_at, updated_at, deleted_at
+				FROM file
+				WHERE id = ?
+			`)
+			updateFileQuery = regexp.QuoteMeta(`
+				UPDATE file 
+				SET encryption_key_id = ?, updated_at = ?
+				WHERE id = ?
+			`)
+			fileRows = sqlmock.NewRows([]string{
+				"id", "name", "path",
+				"mimetype", "extension", "size",
+				"encryption_key_id", "codec", "client_id", "visibility",
+				"created_at", "updated_at", "deleted_at",
+			}).AddRow(
+				"mock-unique-id",
+				"mock-name",
+				"mock-path",
+				"mock-mimetype",
+				"mock-extension",
+				0,
+				"mock-old-key-id",
+				"",
+				"",
+				"private",
+				0,
+				0,
+				nil,
+			)
+		})
+
+		When("failed start db transaction", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectBegin().
+					WillReturnError(fmt.Errorf("failed start db trx"))
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed start db trx")))
+			})
+		})
+
+		When("failed find file", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnError(sql.ErrNoRows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("failed rollback find file", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.
+					ExpectRollback().
+					WillReturnError(fmt.Errorf("rollback error"))
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+			})
+		})
+
+		When("file is deleted", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "path",
+					"mimetype", "extension", "size",
+					"encryption_key_id", "codec", "client_id", "visibility",
+					"created_at", "updated_at", "deleted_at",
+				}).AddRow(
+					"mock-unique-id", "mock-name", "mock-path",
+					"mock-mimetype", "mock-extension", 0,
+					"mock-old-key-id", "", "", "private", 0, 0, 1,
+				)
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(rows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordDeleted))
+			})
+		})
+
+		When("file key has been changed", func() {
+			It("should return error", func() {
+				p.OldKeyId = "mock-other-key-id"
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordChanged))
+			})
+		})
+
+		When("failed update file", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewKeyId, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("record is not updated", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewKeyId, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("record is not updated")))
+			})
+		})
+
+		When("failed execute rekey fn", func() {
+			It("should return error", func() {
+				var rekeyParam repository.RekeyFnParam
+				p.RekeyFn = func(ctx context.Context, rp repository.RekeyFnParam) error {
+					rekeyParam = rp
+					return fmt.Errorf("disk error")
+				}
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewKeyId, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.ExpectRollback()
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
+				Expect(rekeyParam).To(Equal(repository.RekeyFnParam{
+					FilePath: "mock-path",
+				}))
+			})
+		})
+
+		When("failed commit db trx", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewKeyId, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectCommit().
+					WillReturnError(fmt.Errorf("commit error"))
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("commit error")))
+			})
+		})
+
+		When("success update file key", func() {
+			It("should return result", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectQuery(findFileQuery).
+					WithArgs(p.UniqueId).
+					WillReturnRows(fileRows)
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.NewKeyId, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.UpdateFileKey(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateFileKeyResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateFileVisibility function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             *repository_mysql.FileRepository
+			p                repository.UpdateFileVisibilityParam
+			updateFileQuery  string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			clockOpt := repository_mysql.WithClock(clock)
+			dbOpt := repository_mysql.WithDbClient(db)
+			repo, _ = repository_mysql.NewFileRepository(clockOpt, dbOpt)
+
+			p = repository.UpdateFileVisibilityParam{
+				UniqueId:   "mock-unique-id",
+				Visibility: repository.VISIBILITY_PUBLIC,
+			}
+			updateFileQuery = regexp.QuoteMeta(`
+				UPDATE file
+				SET visibility = ?, updated_at = ?
+				WHERE id = ? AND deleted_at IS NULL
+			`)
+		})
+
+		When("failed update file", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.Visibility, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.UpdateFileVisibility(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("file is not available", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.Visibility, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				res, err := repo.UpdateFileVisibility(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success update file visibility", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateFileQuery).
+					WithArgs(p.Visibility, currentTimestamp.UnixMilli(), p.UniqueId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				res, err := repo.UpdateFileVisibility(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateFileVisibilityResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
 })
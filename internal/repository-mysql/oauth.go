@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-seidon/local/internal/datetime"
 	"github.com/go-seidon/local/internal/repository"
@@ -18,25 +20,518 @@ type oAuthRepository struct {
 func (r *oAuthRepository) FindClient(ctx context.Context, p repository.FindClientParam) (*repository.FindClientResult, error) {
 	sqlQuery := `
 		SELECT 
-			client_id, client_secret
+			client_id, scopes, allowed_cidrs
 		FROM oauth_client
-		WHERE client_id = ?
+		WHERE client_id = ? AND disabled_at IS NULL
 	`
 
 	var res repository.FindClientResult
+	var scopes, allowedCidrs string
+	row := r.dbClient.QueryRow(sqlQuery, p.ClientId)
+	err := row.Scan(
+		&res.ClientId,
+		&scopes,
+		&allowedCidrs,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrorRecordNotFound
+		}
+		return nil, err
+	}
+	res.Scopes = strings.Fields(scopes)
+	res.AllowedCidrs = strings.Fields(allowedCidrs)
+
+	secretQuery := `
+		SELECT 
+			id, label, secret, signing_key, created_at,
+			expires_at, last_used_at
+		FROM oauth_client_secret
+		WHERE client_id = ? AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at DESC
+	`
+	secrets, err := r.querySecrets(secretQuery, p.ClientId, r.clock.Now().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	res.Secrets = secrets
+	return &res, nil
+}
+
+func (r *oAuthRepository) CreateClient(ctx context.Context, p repository.CreateClientParam) (*repository.CreateClientResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	tx, err := r.dbClient.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO oauth_client (
+			id, name, client_id, 
+			scopes, allowed_cidrs, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.Exec(
+		insertQuery,
+		p.Id,
+		p.Name,
+		p.ClientId,
+		strings.Join(p.Scopes, " "),
+		strings.Join(p.AllowedCidrs, " "),
+		currentTimestamp.UnixMilli(),
+		currentTimestamp.UnixMilli(),
+	)
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	secretQuery := `
+		INSERT INTO oauth_client_secret (
+			id, client_id, label, secret, signing_key, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.Exec(
+		secretQuery,
+		p.SecretId,
+		p.ClientId,
+		p.SecretLabel,
+		p.ClientSecret,
+		p.SigningKey,
+		currentTimestamp.UnixMilli(),
+	)
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	txErr := tx.Commit()
+	if txErr != nil {
+		return nil, txErr
+	}
+	res := &repository.CreateClientResult{
+		CreatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) ListClient(ctx context.Context, p repository.ListClientParam) (*repository.ListClientResult, error) {
+	listQuery := `
+		SELECT 
+			id, name, client_id, scopes, allowed_cidrs,
+			disabled_at, created_at, updated_at
+		FROM oauth_client
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`
+	rows, err := r.dbClient.Query(listQuery, p.AfterId, p.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []repository.ListClientItem{}
+	for rows.Next() {
+		var item repository.ListClientItem
+		var scopes, allowedCidrs string
+		var disabledAt sql.NullInt64
+		var createdAt, updatedAt int64
+		err := rows.Scan(
+			&item.Id,
+			&item.Name,
+			&item.ClientId,
+			&scopes,
+			&allowedCidrs,
+			&disabledAt,
+			&createdAt,
+			&updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.Scopes = strings.Fields(scopes)
+		item.AllowedCidrs = strings.Fields(allowedCidrs)
+		if disabledAt.Valid {
+			disabledTime := time.UnixMilli(disabledAt.Int64)
+			item.DisabledAt = &disabledTime
+		}
+		item.CreatedAt = time.UnixMilli(createdAt)
+		item.UpdatedAt = time.UnixMilli(updatedAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListClientResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) DisableClient(ctx context.Context, p repository.DisableClientParam) (*repository.DisableClientResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	updateQuery := `
+		UPDATE oauth_client
+		SET disabled_at = ?, updated_at = ?
+		WHERE client_id = ? AND disabled_at IS NULL
+	`
+	qRes, err := r.dbClient.Exec(
+		updateQuery,
+		currentTimestamp.UnixMilli(),
+		currentTimestamp.UnixMilli(),
+		p.ClientId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.DisableClientResult{
+		DisabledAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) DeleteClient(ctx context.Context, p repository.DeleteClientParam) (*repository.DeleteClientResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	deleteQuery := `
+		DELETE FROM oauth_client
+		WHERE client_id = ?
+	`
+	qRes, err := r.dbClient.Exec(deleteQuery, p.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.DeleteClientResult{
+		DeletedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) CreateClientSecret(ctx context.Context, p repository.CreateClientSecretParam) (*repository.CreateClientSecretResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	var expiresAt sql.NullInt64
+	if p.ExpiresAt != nil {
+		expiresAt = sql.NullInt64{Int64: p.ExpiresAt.UnixMilli(), Valid: true}
+	}
+
+	insertQuery := `
+		INSERT INTO oauth_client_secret (
+			id, client_id, label, secret, signing_key,
+			created_at, expires_at
+		)
+		SELECT ?, client_id, ?, ?, ?, ?, ?
+		FROM oauth_client
+		WHERE client_id = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		insertQuery,
+		p.Id,
+		p.Label,
+		p.Secret,
+		p.SigningKey,
+		currentTimestamp.UnixMilli(),
+		expiresAt,
+		p.ClientId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.CreateClientSecretResult{
+		CreatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) ListClientSecret(ctx context.Context, p repository.ListClientSecretParam) (*repository.ListClientSecretResult, error) {
+	listQuery := `
+		SELECT 
+			id, label, '', '', created_at,
+			expires_at, last_used_at
+		FROM oauth_client_secret
+		WHERE client_id = ?
+		ORDER BY created_at DESC
+	`
+	secrets, err := r.querySecrets(listQuery, p.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListClientSecretResult{
+		Items: secrets,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) ExpireClientSecret(ctx context.Context, p repository.ExpireClientSecretParam) (*repository.ExpireClientSecretResult, error) {
+	updateQuery := `
+		UPDATE oauth_client_secret
+		SET expires_at = ?
+		WHERE client_id = ? AND id <> ? 
+		AND (expires_at IS NULL OR expires_at > ?)
+	`
+	qRes, err := r.dbClient.Exec(
+		updateQuery,
+		p.ExpiresAt.UnixMilli(),
+		p.ClientId,
+		p.ExceptId,
+		p.ExpiresAt.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+
+	res := &repository.ExpireClientSecretResult{
+		TotalExpired: totalAffected,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) DeleteClientSecret(ctx context.Context, p repository.DeleteClientSecretParam) (*repository.DeleteClientSecretResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	deleteQuery := `
+		DELETE FROM oauth_client_secret
+		WHERE id = ? AND client_id = ?
+	`
+	qRes, err := r.dbClient.Exec(deleteQuery, p.SecretId, p.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.DeleteClientSecretResult{
+		DeletedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) UpdateSecretUsage(ctx context.Context, p repository.UpdateSecretUsageParam) (*repository.UpdateSecretUsageResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	updateQuery := `
+		UPDATE oauth_client_secret
+		SET last_used_at = ?
+		WHERE id = ?
+	`
+	_, err := r.dbClient.Exec(
+		updateQuery,
+		currentTimestamp.UnixMilli(),
+		p.SecretId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.UpdateSecretUsageResult{
+		LastUsedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) UpdateSecretHash(ctx context.Context, p repository.UpdateSecretHashParam) (*repository.UpdateSecretHashResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	updateQuery := `
+		UPDATE oauth_client_secret
+		SET secret = ?
+		WHERE id = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		updateQuery,
+		p.Secret,
+		p.SecretId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.UpdateSecretHashResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) UpdateClientAllowlist(ctx context.Context, p repository.UpdateClientAllowlistParam) (*repository.UpdateClientAllowlistResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	updateQuery := `
+		UPDATE oauth_client
+		SET allowed_cidrs = ?, updated_at = ?
+		WHERE client_id = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		updateQuery,
+		strings.Join(p.AllowedCidrs, " "),
+		currentTimestamp.UnixMilli(),
+		p.ClientId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.UpdateClientAllowlistResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) FindClientRateLimit(ctx context.Context, p repository.FindClientRateLimitParam) (*repository.FindClientRateLimitResult, error) {
+	sqlQuery := `
+		SELECT 
+			client_id, request_rate, byte_rate
+		FROM oauth_client
+		WHERE client_id = ?
+	`
+
+	var res repository.FindClientRateLimitResult
+	var requestRate, byteRate sql.NullInt64
 	row := r.dbClient.QueryRow(sqlQuery, p.ClientId)
 	err := row.Scan(
 		&res.ClientId,
-		&res.ClientSecret,
+		&requestRate,
+		&byteRate,
 	)
-	if err == nil {
-		return &res, nil
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrorRecordNotFound
+		}
+		return nil, err
+	}
+	if requestRate.Valid {
+		res.RequestRate = &requestRate.Int64
+	}
+	if byteRate.Valid {
+		res.ByteRate = &byteRate.Int64
+	}
+	return &res, nil
+}
+
+func (r *oAuthRepository) UpdateClientRateLimit(ctx context.Context, p repository.UpdateClientRateLimitParam) (*repository.UpdateClientRateLimitResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	var requestRate, byteRate sql.NullInt64
+	if p.RequestRate != nil {
+		requestRate = sql.NullInt64{Int64: *p.RequestRate, Valid: true}
+	}
+	if p.ByteRate != nil {
+		byteRate = sql.NullInt64{Int64: *p.ByteRate, Valid: true}
 	}
 
-	if errors.Is(err, sql.ErrNoRows) {
+	updateQuery := `
+		UPDATE oauth_client
+		SET request_rate = ?, byte_rate = ?, updated_at = ?
+		WHERE client_id = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		updateQuery,
+		requestRate,
+		byteRate,
+		currentTimestamp.UnixMilli(),
+		p.ClientId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
 		return nil, repository.ErrorRecordNotFound
 	}
-	return nil, err
+
+	res := &repository.UpdateClientRateLimitResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *oAuthRepository) querySecrets(query string, args ...interface{}) ([]repository.ClientSecret, error) {
+	rows, err := r.dbClient.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	secrets := []repository.ClientSecret{}
+	for rows.Next() {
+		var secret repository.ClientSecret
+		var createdAt int64
+		var signingKey sql.NullString
+		var expiresAt, lastUsedAt sql.NullInt64
+		err := rows.Scan(
+			&secret.Id,
+			&secret.Label,
+			&secret.Secret,
+			&signingKey,
+			&createdAt,
+			&expiresAt,
+			&lastUsedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		secret.SigningKey = signingKey.String
+		secret.CreatedAt = time.UnixMilli(createdAt)
+		if expiresAt.Valid {
+			expiresTime := time.UnixMilli(expiresAt.Int64)
+			secret.ExpiresAt = &expiresTime
+		}
+		if lastUsedAt.Valid {
+			lastUsedTime := time.UnixMilli(lastUsedAt.Int64)
+			secret.LastUsedAt = &lastUsedTime
+		}
+		secrets = append(secrets, secret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return secrets, nil
 }
 
 func NewOAuthRepository(opts ...RepoOption) (*oAuthRepository, error) {
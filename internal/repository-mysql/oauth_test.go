@@ -3,13 +3,16 @@ package repository_mysql_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-seidon/local/internal/mock"
 	"github.com/go-seidon/local/internal/repository"
 	repository_mysql "github.com/go-seidon/local/internal/repository-mysql"
+	"github.com/golang/mock/gomock"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -51,15 +54,22 @@ var _ = Describe("OAuth Repository", func() {
 
 	Context("FindClient function", Label("unit"), func() {
 		var (
-			ctx             context.Context
-			dbClient        sqlmock.Sqlmock
-			repo            repository.OAuthRepository
-			p               repository.FindClientParam
-			findClientQuery string
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.FindClientParam
+			findClientQuery  string
+			findSecretQuery  string
+			secretColumns    []string
 		)
 
 		BeforeEach(func() {
 			ctx = context.Background()
+			currentTimestamp = time.UnixMilli(1660000000000)
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).AnyTimes()
 
 			db, mock, err := sqlmock.New()
 			if err != nil {
@@ -67,18 +77,32 @@ var _ = Describe("OAuth Repository", func() {
 			}
 			dbClient = mock
 
-			dbOpt := repository_mysql.WithDbClient(db)
-			repo, _ = repository_mysql.NewOAuthRepository(dbOpt)
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
 			p = repository.FindClientParam{
 				ClientId: "client_id",
 			}
 
 			findClientQuery = regexp.QuoteMeta(`
 				SELECT 
-					client_id, client_secret
+					client_id, scopes, allowed_cidrs
 				FROM oauth_client
-				WHERE client_id = ?
+				WHERE client_id = ? AND disabled_at IS NULL
+			`)
+			findSecretQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, label, secret, signing_key, created_at,
+					expires_at, last_used_at
+				FROM oauth_client_secret
+				WHERE client_id = ? AND (expires_at IS NULL OR expires_at > ?)
+				ORDER BY created_at DESC
 			`)
+			secretColumns = []string{
+				"id", "label", "secret", "signing_key", "created_at",
+				"expires_at", "last_used_at",
+			}
 		})
 
 		When("failed client not found", func() {
@@ -107,23 +131,136 @@ var _ = Describe("OAuth Repository", func() {
 			})
 		})
 
+		When("failed query secrets", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{
+					"client_id", "scopes", "allowed_cidrs",
+				}).AddRow("mock-client-id", "file:read", "")
+				dbClient.
+					ExpectQuery(findClientQuery).
+					WillReturnRows(rows)
+				dbClient.
+					ExpectQuery(findSecretQuery).
+					WithArgs(p.ClientId, currentTimestamp.UnixMilli()).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.FindClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan secret", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{
+					"client_id", "scopes", "allowed_cidrs",
+				}).AddRow("mock-client-id", "file:read", "")
+				dbClient.
+					ExpectQuery(findClientQuery).
+					WillReturnRows(rows)
+				dbClient.
+					ExpectQuery(findSecretQuery).
+					WithArgs(p.ClientId, currentTimestamp.UnixMilli()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("mock-secret-id"))
+
+				res, err := repo.FindClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("failed iterate secrets", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{
+					"client_id", "scopes", "allowed_cidrs",
+				}).AddRow("mock-client-id", "file:read", "")
+				dbClient.
+					ExpectQuery(findClientQuery).
+					WillReturnRows(rows)
+				secretRows := sqlmock.NewRows(secretColumns).
+					AddRow("mock-secret-id", "default", "hashed-secret", nil, 1650000000000, nil, nil).
+					RowError(0, fmt.Errorf("network error"))
+				dbClient.
+					ExpectQuery(findSecretQuery).
+					WithArgs(p.ClientId, currentTimestamp.UnixMilli()).
+					WillReturnRows(secretRows)
+
+				res, err := repo.FindClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("client has no scope", func() {
+			It("should return empty scopes", func() {
+				rows := sqlmock.NewRows([]string{
+					"client_id", "scopes", "allowed_cidrs",
+				}).AddRow("mock-client-id", "", "")
+				dbClient.
+					ExpectQuery(findClientQuery).
+					WillReturnRows(rows)
+				dbClient.
+					ExpectQuery(findSecretQuery).
+					WithArgs(p.ClientId, currentTimestamp.UnixMilli()).
+					WillReturnRows(sqlmock.NewRows(secretColumns))
+
+				res, err := repo.FindClient(ctx, p)
+
+				Expect(res.Scopes).To(BeEmpty())
+				Expect(res.AllowedCidrs).To(BeEmpty())
+				Expect(res.Secrets).To(BeEmpty())
+				Expect(err).To(BeNil())
+			})
+		})
+
 		When("client is available", func() {
 			It("should return result", func() {
 				rows := sqlmock.NewRows([]string{
-					"client_id", "client_secret",
+					"client_id", "scopes", "allowed_cidrs",
 				}).AddRow(
 					"mock-client-id",
-					"mock-client-client_secret",
+					"file:read  file:write",
+					"10.0.0.0/8 192.168.1.10/32",
 				)
 				dbClient.
 					ExpectQuery(findClientQuery).
 					WillReturnRows(rows)
+				secretRows := sqlmock.NewRows(secretColumns).
+					AddRow("mock-secret-id-2", "next", "hashed-secret-2", "sealed-key-2", 1655000000000, nil, nil).
+					AddRow("mock-secret-id-1", "default", "hashed-secret-1", nil, 1650000000000, 1660000005000, 1659000000000)
+				dbClient.
+					ExpectQuery(findSecretQuery).
+					WithArgs(p.ClientId, currentTimestamp.UnixMilli()).
+					WillReturnRows(secretRows)
 
 				res, err := repo.FindClient(ctx, p)
 
+				expiresAt := time.UnixMilli(1660000005000)
+				lastUsedAt := time.UnixMilli(1659000000000)
 				expectedRes := &repository.FindClientResult{
 					ClientId:     "mock-client-id",
-					ClientSecret: "mock-client-client_secret",
+					Scopes:       []string{"file:read", "file:write"},
+					AllowedCidrs: []string{"10.0.0.0/8", "192.168.1.10/32"},
+					Secrets: []repository.ClientSecret{
+						{
+							Id:         "mock-secret-id-2",
+							Label:      "next",
+							Secret:     "hashed-secret-2",
+							SigningKey: "sealed-key-2",
+							CreatedAt:  time.UnixMilli(1655000000000),
+						},
+						{
+							Id:         "mock-secret-id-1",
+							Label:      "default",
+							Secret:     "hashed-secret-1",
+							CreatedAt:  time.UnixMilli(1650000000000),
+							ExpiresAt:  &expiresAt,
+							LastUsedAt: &lastUsedAt,
+						},
+					},
 				}
 				Expect(res).To(Equal(expectedRes))
 				Expect(err).To(BeNil())
@@ -131,4 +268,1200 @@ var _ = Describe("OAuth Repository", func() {
 		})
 	})
 
+	Context("CreateClient function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.CreateClientParam
+			insertQuery      string
+			secretQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.CreateClientParam{
+				Id:           "mock-id",
+				Name:         "mock-name",
+				ClientId:     "mock-client-id",
+				Scopes:       []string{"file:read", "file:write"},
+				AllowedCidrs: []string{"10.0.0.0/8"},
+				ClientSecret: "mock-hashed-secret",
+				SigningKey:   "mock-sealed-key",
+				SecretId:     "mock-secret-id",
+				SecretLabel:  "default",
+			}
+			insertQuery = regexp.QuoteMeta(`
+				INSERT INTO oauth_client (
+					id, name, client_id, 
+					scopes, allowed_cidrs, created_at, updated_at
+				)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`)
+			secretQuery = regexp.QuoteMeta(`
+				INSERT INTO oauth_client_secret (
+					id, client_id, label, secret, signing_key, created_at
+				)
+				VALUES (?, ?, ?, ?, ?, ?)
+			`)
+		})
+
+		When("failed begin transaction", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectBegin().
+					WillReturnError(fmt.Errorf("begin error"))
+
+				res, err := repo.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("begin error")))
+			})
+		})
+
+		When("failed insert client", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed rollback insert client", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.
+					ExpectRollback().
+					WillReturnError(fmt.Errorf("rollback error"))
+
+				res, err := repo.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+			})
+		})
+
+		When("failed insert secret", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(secretQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed commit", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(secretQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectCommit().
+					WillReturnError(fmt.Errorf("commit error"))
+
+				res, err := repo.CreateClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("commit error")))
+			})
+		})
+
+		When("success insert client", func() {
+			It("should return result", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(insertQuery).
+					WithArgs(
+						p.Id, p.Name, p.ClientId,
+						"file:read file:write",
+						"10.0.0.0/8",
+						currentTimestamp.UnixMilli(),
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.
+					ExpectExec(secretQuery).
+					WithArgs(
+						p.SecretId, p.ClientId, p.SecretLabel,
+						p.ClientSecret, p.SigningKey,
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbClient.ExpectCommit()
+
+				res, err := repo.CreateClient(ctx, p)
+
+				Expect(res).To(Equal(&repository.CreateClientResult{
+					CreatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListClient function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			dbClient  sqlmock.Sqlmock
+			repo      repository.OAuthRepository
+			p         repository.ListClientParam
+			listQuery string
+			columns   []string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(repository_mysql.WithDbClient(db))
+			p = repository.ListClientParam{
+				AfterId: "mock-after-id",
+				Limit:   2,
+			}
+			listQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, name, client_id, scopes, allowed_cidrs,
+					disabled_at, created_at, updated_at
+				FROM oauth_client
+				WHERE id > ?
+				ORDER BY id ASC
+				LIMIT ?
+			`)
+			columns = []string{
+				"id", "name", "client_id", "scopes", "allowed_cidrs",
+				"disabled_at", "created_at", "updated_at",
+			}
+		})
+
+		When("failed query clients", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan row", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("mock-id")
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnRows(rows)
+
+				res, err := repo.ListClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("failed iterate rows", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow("mock-id", "mock-name", "mock-client-id", "file:read", "", nil, 1660000000000, 1660000000000).
+					RowError(0, fmt.Errorf("network error"))
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnRows(rows)
+
+				res, err := repo.ListClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("clients are available", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow("mock-id-1", "mock-name-1", "mock-client-id-1", "file:read", "", nil, 1660000000000, 1660000000000).
+					AddRow("mock-id-2", "mock-name-2", "mock-client-id-2", "admin", "10.0.0.0/8", 1660000005000, 1660000000000, 1660000005000)
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(p.AfterId, p.Limit).
+					WillReturnRows(rows)
+
+				res, err := repo.ListClient(ctx, p)
+
+				disabledAt := time.UnixMilli(1660000005000)
+				Expect(res).To(Equal(&repository.ListClientResult{
+					Items: []repository.ListClientItem{
+						{
+							Id:           "mock-id-1",
+							Name:         "mock-name-1",
+							ClientId:     "mock-client-id-1",
+							Scopes:       []string{"file:read"},
+							AllowedCidrs: []string{},
+							CreatedAt:    time.UnixMilli(1660000000000),
+							UpdatedAt:    time.UnixMilli(1660000000000),
+						},
+						{
+							Id:           "mock-id-2",
+							Name:         "mock-name-2",
+							ClientId:     "mock-client-id-2",
+							Scopes:       []string{"admin"},
+							AllowedCidrs: []string{"10.0.0.0/8"},
+							DisabledAt:   &disabledAt,
+							CreatedAt:    time.UnixMilli(1660000000000),
+							UpdatedAt:    time.UnixMilli(1660000005000),
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DisableClient function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.DisableClientParam
+			updateQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.DisableClientParam{
+				ClientId: "mock-client-id",
+			}
+			updateQuery = regexp.QuoteMeta(`
+				UPDATE oauth_client
+				SET disabled_at = ?, updated_at = ?
+				WHERE client_id = ? AND disabled_at IS NULL
+			`)
+		})
+
+		When("failed update client", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.DisableClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("active client is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(currentTimestamp.UnixMilli(), currentTimestamp.UnixMilli(), p.ClientId).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.DisableClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success disable client", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(currentTimestamp.UnixMilli(), currentTimestamp.UnixMilli(), p.ClientId).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.DisableClient(ctx, p)
+
+				Expect(res).To(Equal(&repository.DisableClientResult{
+					DisabledAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DeleteClient function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.DeleteClientParam
+			deleteQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.DeleteClientParam{
+				ClientId: "mock-client-id",
+			}
+			deleteQuery = regexp.QuoteMeta(`
+				DELETE FROM oauth_client
+				WHERE client_id = ?
+			`)
+		})
+
+		When("failed delete client", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.DeleteClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(p.ClientId).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.DeleteClient(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success delete client", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(p.ClientId).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.DeleteClient(ctx, p)
+
+				Expect(res).To(Equal(&repository.DeleteClientResult{
+					DeletedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CreateClientSecret function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.CreateClientSecretParam
+			insertQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.CreateClientSecretParam{
+				Id:         "mock-secret-id",
+				ClientId:   "mock-client-id",
+				Label:      "next",
+				Secret:     "mock-hashed-secret",
+				SigningKey: "mock-sealed-key",
+			}
+			insertQuery = regexp.QuoteMeta(`
+				INSERT INTO oauth_client_secret (
+					id, client_id, label, secret, signing_key,
+					created_at, expires_at
+				)
+				SELECT ?, client_id, ?, ?, ?, ?, ?
+				FROM oauth_client
+				WHERE client_id = ?
+			`)
+		})
+
+		When("failed insert secret", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.CreateClientSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WithArgs(
+						p.Id, p.Label, p.Secret, p.SigningKey,
+						currentTimestamp.UnixMilli(), nil, p.ClientId,
+					).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.CreateClientSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success insert expiring secret", func() {
+			It("should return result", func() {
+				expiresAt := currentTimestamp.Add(24 * time.Hour)
+				p.ExpiresAt = &expiresAt
+				dbClient.
+					ExpectExec(insertQuery).
+					WithArgs(
+						p.Id, p.Label, p.Secret, p.SigningKey,
+						currentTimestamp.UnixMilli(), expiresAt.UnixMilli(), p.ClientId,
+					).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.CreateClientSecret(ctx, p)
+
+				Expect(res).To(Equal(&repository.CreateClientSecretResult{
+					CreatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListClientSecret function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			dbClient  sqlmock.Sqlmock
+			repo      repository.OAuthRepository
+			p         repository.ListClientSecretParam
+			listQuery string
+			columns   []string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(repository_mysql.WithDbClient(db))
+			p = repository.ListClientSecretParam{
+				ClientId: "mock-client-id",
+			}
+			listQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, label, '', '', created_at,
+					expires_at, last_used_at
+				FROM oauth_client_secret
+				WHERE client_id = ?
+				ORDER BY created_at DESC
+			`)
+			columns = []string{
+				"id", "label", "secret", "signing_key", "created_at",
+				"expires_at", "last_used_at",
+			}
+		})
+
+		When("failed query secrets", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(p.ClientId).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListClientSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("secrets are available", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow("mock-secret-id", "default", "", "", 1650000000000, nil, 1659000000000)
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(p.ClientId).
+					WillReturnRows(rows)
+
+				res, err := repo.ListClientSecret(ctx, p)
+
+				lastUsedAt := time.UnixMilli(1659000000000)
+				Expect(res).To(Equal(&repository.ListClientSecretResult{
+					Items: []repository.ClientSecret{
+						{
+							Id:         "mock-secret-id",
+							Label:      "default",
+							CreatedAt:  time.UnixMilli(1650000000000),
+							LastUsedAt: &lastUsedAt,
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ExpireClientSecret function", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			expiresAt   time.Time
+			dbClient    sqlmock.Sqlmock
+			repo        repository.OAuthRepository
+			p           repository.ExpireClientSecretParam
+			updateQuery string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			expiresAt = time.UnixMilli(1660000000000)
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(repository_mysql.WithDbClient(db))
+			p = repository.ExpireClientSecretParam{
+				ClientId:  "mock-client-id",
+				ExceptId:  "mock-secret-id",
+				ExpiresAt: expiresAt,
+			}
+			updateQuery = regexp.QuoteMeta(`
+				UPDATE oauth_client_secret
+				SET expires_at = ?
+				WHERE client_id = ? AND id <> ? 
+				AND (expires_at IS NULL OR expires_at > ?)
+			`)
+		})
+
+		When("failed update secrets", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ExpireClientSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success update secrets", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(
+						expiresAt.UnixMilli(), p.ClientId,
+						p.ExceptId, expiresAt.UnixMilli(),
+					).
+					WillReturnResult(driver.RowsAffected(2))
+
+				res, err := repo.ExpireClientSecret(ctx, p)
+
+				Expect(res).To(Equal(&repository.ExpireClientSecretResult{
+					TotalExpired: 2,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DeleteClientSecret function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.DeleteClientSecretParam
+			deleteQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.DeleteClientSecretParam{
+				ClientId: "mock-client-id",
+				SecretId: "mock-secret-id",
+			}
+			deleteQuery = regexp.QuoteMeta(`
+				DELETE FROM oauth_client_secret
+				WHERE id = ? AND client_id = ?
+			`)
+		})
+
+		When("failed delete secret", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.DeleteClientSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("secret is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(p.SecretId, p.ClientId).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.DeleteClientSecret(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success delete secret", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(p.SecretId, p.ClientId).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.DeleteClientSecret(ctx, p)
+
+				Expect(res).To(Equal(&repository.DeleteClientSecretResult{
+					DeletedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateSecretUsage function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.UpdateSecretUsageParam
+			updateQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.UpdateSecretUsageParam{
+				SecretId: "mock-secret-id",
+			}
+			updateQuery = regexp.QuoteMeta(`
+				UPDATE oauth_client_secret
+				SET last_used_at = ?
+				WHERE id = ?
+			`)
+		})
+
+		When("failed update secret", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.UpdateSecretUsage(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success update secret", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(currentTimestamp.UnixMilli(), p.SecretId).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.UpdateSecretUsage(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateSecretUsageResult{
+					LastUsedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateSecretHash function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.UpdateSecretHashParam
+			updateQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.UpdateSecretHashParam{
+				SecretId: "mock-secret-id",
+				Secret:   "new-hashed-secret",
+			}
+			updateQuery = regexp.QuoteMeta(`
+				UPDATE oauth_client_secret
+				SET secret = ?
+				WHERE id = ?
+			`)
+		})
+
+		When("failed update secret", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.UpdateSecretHash(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("secret is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(p.Secret, p.SecretId).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.UpdateSecretHash(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success update secret", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(p.Secret, p.SecretId).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.UpdateSecretHash(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateSecretHashResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateClientAllowlist function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.UpdateClientAllowlistParam
+			updateQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.UpdateClientAllowlistParam{
+				ClientId:     "mock-client-id",
+				AllowedCidrs: []string{"10.0.0.0/8", "192.168.1.10/32"},
+			}
+			updateQuery = regexp.QuoteMeta(`
+				UPDATE oauth_client
+				SET allowed_cidrs = ?, updated_at = ?
+				WHERE client_id = ?
+			`)
+		})
+
+		When("failed update allowlist", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.UpdateClientAllowlist(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs("10.0.0.0/8 192.168.1.10/32", currentTimestamp.UnixMilli(), p.ClientId).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.UpdateClientAllowlist(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success update allowlist", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs("10.0.0.0/8 192.168.1.10/32", currentTimestamp.UnixMilli(), p.ClientId).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.UpdateClientAllowlist(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateClientAllowlistResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("FindClientRateLimit function", Label("unit"), func() {
+		var (
+			ctx       context.Context
+			dbClient  sqlmock.Sqlmock
+			repo      repository.OAuthRepository
+			p         repository.FindClientRateLimitParam
+			findQuery string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+			)
+			p = repository.FindClientRateLimitParam{
+				ClientId: "mock-client-id",
+			}
+			findQuery = regexp.QuoteMeta(`
+				SELECT 
+					client_id, request_rate, byte_rate
+				FROM oauth_client
+				WHERE client_id = ?
+			`)
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(findQuery).
+					WithArgs(p.ClientId).
+					WillReturnError(sql.ErrNoRows)
+
+				res, err := repo.FindClientRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("failed find rate limit", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(findQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.FindClientRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("rate limit is not overridden", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows([]string{
+					"client_id", "request_rate", "byte_rate",
+				}).AddRow(
+					"mock-client-id", nil, nil,
+				)
+				dbClient.
+					ExpectQuery(findQuery).
+					WithArgs(p.ClientId).
+					WillReturnRows(rows)
+
+				res, err := repo.FindClientRateLimit(ctx, p)
+
+				Expect(res).To(Equal(&repository.FindClientRateLimitResult{
+					ClientId: "mock-client-id",
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("rate limit is overridden", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows([]string{
+					"client_id", "request_rate", "byte_rate",
+				}).AddRow(
+					"mock-client-id", 50, 0,
+				)
+				dbClient.
+					ExpectQuery(findQuery).
+					WithArgs(p.ClientId).
+					WillReturnRows(rows)
+
+				res, err := repo.FindClientRateLimit(ctx, p)
+
+				requestRate := int64(50)
+				byteRate := int64(0)
+				Expect(res).To(Equal(&repository.FindClientRateLimitResult{
+					ClientId:    "mock-client-id",
+					RequestRate: &requestRate,
+					ByteRate:    &byteRate,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateClientRateLimit function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.OAuthRepository
+			p                repository.UpdateClientRateLimitParam
+			updateQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewOAuthRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			requestRate := int64(50)
+			p = repository.UpdateClientRateLimitParam{
+				ClientId:    "mock-client-id",
+				RequestRate: &requestRate,
+			}
+			updateQuery = regexp.QuoteMeta(`
+				UPDATE oauth_client
+				SET request_rate = ?, byte_rate = ?, updated_at = ?
+				WHERE client_id = ?
+			`)
+		})
+
+		When("failed update rate limit", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.UpdateClientRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(int64(50), nil, currentTimestamp.UnixMilli(), p.ClientId).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.UpdateClientRateLimit(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success update rate limit", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(int64(50), nil, currentTimestamp.UnixMilli(), p.ClientId).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.UpdateClientRateLimit(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateClientRateLimitResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
 })
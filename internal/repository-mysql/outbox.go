@@ -0,0 +1,296 @@
+package repository_mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+type outboxRepository struct {
+	dbClient *sql.DB
+	clock    datetime.Clock
+}
+
+func (r *outboxRepository) ListPendingEvent(ctx context.Context, p repository.ListPendingEventParam) (*repository.ListPendingEventResult, error) {
+	listQuery := `
+		SELECT 
+			id, idempotency_key, event_type, file_id, client_id,
+			name, mimetype, extension, size,
+			attempt, next_attempt_at, created_at
+		FROM file_event_outbox
+		WHERE published_at IS NULL AND next_attempt_at <= ?
+		ORDER BY id ASC
+		LIMIT ?
+	`
+	items, err := r.queryEvents(listQuery, p.DueAt.UnixMilli(), p.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListPendingEventResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) ClaimEvent(ctx context.Context, p repository.ClaimEventParam) (*repository.ClaimEventResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	claimQuery := `
+		UPDATE file_event_outbox
+		SET next_attempt_at = ?
+		WHERE id = ? AND published_at IS NULL AND next_attempt_at = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		claimQuery,
+		p.LeaseUntil.UnixMilli(),
+		p.Id,
+		p.NextAttemptAt.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordChanged
+	}
+
+	res := &repository.ClaimEventResult{
+		ClaimedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) PublishEvent(ctx context.Context, p repository.PublishEventParam) (*repository.PublishEventResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	publishQuery := `
+		UPDATE file_event_outbox
+		SET published_at = ?
+		WHERE id = ? AND published_at IS NULL
+	`
+	qRes, err := r.dbClient.Exec(
+		publishQuery,
+		currentTimestamp.UnixMilli(),
+		p.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.PublishEventResult{
+		PublishedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) RetryEvent(ctx context.Context, p repository.RetryEventParam) (*repository.RetryEventResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	retryQuery := `
+		UPDATE file_event_outbox
+		SET attempt = ?, next_attempt_at = ?, last_error = ?
+		WHERE id = ? AND published_at IS NULL
+	`
+	qRes, err := r.dbClient.Exec(
+		retryQuery,
+		p.Attempt,
+		p.NextAttemptAt.UnixMilli(),
+		p.LastError,
+		p.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.RetryEventResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) DeletePublishedEvent(ctx context.Context, p repository.DeletePublishedEventParam) (*repository.DeletePublishedEventResult, error) {
+	deleteQuery := `
+		DELETE FROM file_event_outbox
+		WHERE published_at IS NOT NULL AND published_at < ?
+		ORDER BY id ASC
+		LIMIT ?
+	`
+	qRes, err := r.dbClient.Exec(
+		deleteQuery,
+		p.PublishedBefore.UnixMilli(),
+		p.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalDeleted, _ := qRes.RowsAffected()
+
+	res := &repository.DeletePublishedEventResult{
+		TotalDeleted: totalDeleted,
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) ListClientEvent(ctx context.Context, p repository.ListClientEventParam) (*repository.ListClientEventResult, error) {
+	listQuery := `
+		SELECT 
+			id, idempotency_key, event_type, file_id, client_id,
+			name, mimetype, extension, size,
+			attempt, next_attempt_at, created_at
+		FROM file_event_outbox
+		WHERE id > ? AND (
+			client_id = ? OR
+			file_id IN (
+				SELECT file_id
+				FROM file_share
+				WHERE client_id = ? AND permission = ?
+			)
+		)
+		ORDER BY id ASC
+		LIMIT ?
+	`
+	items, err := r.queryEvents(
+		listQuery,
+		p.AfterId,
+		p.ClientId,
+		p.ClientId,
+		repository.PERMISSION_READ,
+		p.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListClientEventResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) FindLastEvent(ctx context.Context, p repository.FindLastEventParam) (*repository.FindLastEventResult, error) {
+	findQuery := `
+		SELECT COALESCE(MAX(id), 0)
+		FROM file_event_outbox
+	`
+	res := &repository.FindLastEventResult{}
+	err := r.dbClient.QueryRow(findQuery).Scan(&res.Id)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) queryEvents(query string, args ...interface{}) ([]repository.OutboxEvent, error) {
+	rows, err := r.dbClient.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []repository.OutboxEvent{}
+	for rows.Next() {
+		var item repository.OutboxEvent
+		var nextAttemptAt, createdAt int64
+		err := rows.Scan(
+			&item.Id,
+			&item.IdempotencyKey,
+			&item.EventType,
+			&item.FileId,
+			&item.ClientId,
+			&item.Name,
+			&item.Mimetype,
+			&item.Extension,
+			&item.Size,
+			&item.Attempt,
+			&nextAttemptAt,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+		item.CreatedAt = time.UnixMilli(createdAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func insertOutboxEvent(tx *sql.Tx, p insertOutboxEventParam) error {
+	insertQuery := `
+		INSERT INTO file_event_outbox (
+			idempotency_key, event_type, file_id, client_id,
+			name, mimetype, extension, size,
+			next_attempt_at, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.Exec(
+		insertQuery,
+		p.EventType+":"+p.FileId,
+		p.EventType,
+		p.FileId,
+		p.ClientId,
+		p.Name,
+		p.Mimetype,
+		p.Extension,
+		p.Size,
+		p.Timestamp,
+		p.Timestamp,
+	)
+	return err
+}
+
+type insertOutboxEventParam struct {
+	EventType string
+	FileId    string
+	ClientId  string
+	Name      string
+	Mimetype  string
+	Extension string
+	Size      int64
+	Timestamp int64
+}
+
+func NewOutboxRepository(opts ...RepoOption) (*outboxRepository, error) {
+	option := RepositoryOption{}
+	for _, opt := range opts {
+		opt(&option)
+	}
+
+	if option.dbClient == nil {
+		return nil, fmt.Errorf("invalid db client specified")
+	}
+
+	var clock datetime.Clock
+	if option.clock == nil {
+		clock = datetime.NewClock()
+	} else {
+		clock = option.clock
+	}
+
+	r := &outboxRepository{
+		dbClient: option.dbClient,
+		clock:    clock,
+	}
+	return r, nil
+}
@@ -0,0 +1,541 @@
+package repository_mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	repository_mysql "github.com/go-seidon/local/internal/repository-mysql"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outbox Repository", func() {
+
+	Context("NewOutboxRepository function", Label("unit"), func() {
+		When("db client is not specified", func() {
+			It("should return error", func() {
+				res, err := repository_mysql.NewOutboxRepository()
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid db client specified")))
+			})
+		})
+
+		When("required parameter is specified", func() {
+			It("should return result", func() {
+				opt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewOutboxRepository(opt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("clock is specified", func() {
+			It("should return result", func() {
+				clockOpt := repository_mysql.WithClock(&mock.MockClock{})
+				dbOpt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewOutboxRepository(clockOpt, dbOpt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	var (
+		ctx              context.Context
+		currentTimestamp time.Time
+		dbClient         sqlmock.Sqlmock
+		repo             repository.OutboxRepository
+	)
+
+	setup := func() {
+		ctx = context.Background()
+		currentTimestamp = time.UnixMilli(1660000000000)
+		ctrl := gomock.NewController(GinkgoT())
+		clock := mock.NewMockClock(ctrl)
+		clock.EXPECT().Now().Return(currentTimestamp).AnyTimes()
+
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			AbortSuite("failed create db mock: " + err.Error())
+		}
+		dbClient = mock
+
+		repo, _ = repository_mysql.NewOutboxRepository(
+			repository_mysql.WithDbClient(db),
+			repository_mysql.WithClock(clock),
+		)
+	}
+
+	Context("ListPendingEvent function", Label("unit"), func() {
+		var (
+			p         repository.ListPendingEventParam
+			listQuery string
+			columns   []string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.ListPendingEventParam{
+				DueAt: currentTimestamp,
+				Limit: 10,
+			}
+			listQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, idempotency_key, event_type, file_id, client_id,
+					name, mimetype, extension, size,
+					attempt, next_attempt_at, created_at
+				FROM file_event_outbox
+				WHERE published_at IS NULL AND next_attempt_at <= ?
+				ORDER BY id ASC
+				LIMIT ?
+			`)
+			columns = []string{
+				"id", "idempotency_key", "event_type", "file_id", "client_id",
+				"name", "mimetype", "extension", "size",
+				"attempt", "next_attempt_at", "created_at",
+			}
+		})
+
+		When("failed list pending event", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(listQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListPendingEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan event", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows(columns).AddRow(
+					"invalid", "file.uploaded:mock-file-id", "file.uploaded", "mock-file-id", "mock-client-id",
+					"dolpin", "image/jpeg", "jpg", 200,
+					0, 1660000000000, 1660000000000,
+				)
+				dbClient.
+					ExpectQuery(listQuery).
+					WillReturnRows(rows)
+
+				res, err := repo.ListPendingEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("success list pending event", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows(columns).AddRow(
+					1, "file.uploaded:mock-file-id", "file.uploaded", "mock-file-id", "mock-client-id",
+					"dolpin", "image/jpeg", "jpg", 200,
+					2, 1659999990000, 1659999900000,
+				)
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(int64(1660000000000), 10).
+					WillReturnRows(rows)
+
+				res, err := repo.ListPendingEvent(ctx, p)
+
+				Expect(res).To(Equal(&repository.ListPendingEventResult{
+					Items: []repository.OutboxEvent{
+						{
+							Id:             1,
+							IdempotencyKey: "file.uploaded:mock-file-id",
+							EventType:      "file.uploaded",
+							FileId:         "mock-file-id",
+							ClientId:       "mock-client-id",
+							Name:           "dolpin",
+							Mimetype:       "image/jpeg",
+							Extension:      "jpg",
+							Size:           200,
+							Attempt:        2,
+							NextAttemptAt:  time.UnixMilli(1659999990000),
+							CreatedAt:      time.UnixMilli(1659999900000),
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ClaimEvent function", Label("unit"), func() {
+		var (
+			p          repository.ClaimEventParam
+			claimQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.ClaimEventParam{
+				Id:            1,
+				NextAttemptAt: time.UnixMilli(1659999990000),
+				LeaseUntil:    time.UnixMilli(1660000030000),
+			}
+			claimQuery = regexp.QuoteMeta(`WHERE id = ? AND published_at IS NULL AND next_attempt_at = ?`)
+		})
+
+		When("failed claim event", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(claimQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ClaimEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("event has been changed", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(claimQuery).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.ClaimEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordChanged))
+			})
+		})
+
+		When("success claim event", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(claimQuery).
+					WithArgs(int64(1660000030000), int64(1), int64(1659999990000)).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.ClaimEvent(ctx, p)
+
+				Expect(res).To(Equal(&repository.ClaimEventResult{
+					ClaimedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("PublishEvent function", Label("unit"), func() {
+		var (
+			p            repository.PublishEventParam
+			publishQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.PublishEventParam{
+				Id: 1,
+			}
+			publishQuery = regexp.QuoteMeta(`
+				UPDATE file_event_outbox
+				SET published_at = ?
+				WHERE id = ? AND published_at IS NULL
+			`)
+		})
+
+		When("failed publish event", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(publishQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.PublishEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("event is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(publishQuery).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.PublishEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success publish event", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(publishQuery).
+					WithArgs(int64(1660000000000), int64(1)).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.PublishEvent(ctx, p)
+
+				Expect(res).To(Equal(&repository.PublishEventResult{
+					PublishedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RetryEvent function", Label("unit"), func() {
+		var (
+			p          repository.RetryEventParam
+			retryQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.RetryEventParam{
+				Id:            1,
+				Attempt:       3,
+				NextAttemptAt: time.UnixMilli(1660000020000),
+				LastError:     "webhook: db error",
+			}
+			retryQuery = regexp.QuoteMeta(`
+				UPDATE file_event_outbox
+				SET attempt = ?, next_attempt_at = ?, last_error = ?
+				WHERE id = ? AND published_at IS NULL
+			`)
+		})
+
+		When("failed retry event", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(retryQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.RetryEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("event is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(retryQuery).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.RetryEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success retry event", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(retryQuery).
+					WithArgs(3, int64(1660000020000), "webhook: db error", int64(1)).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.RetryEvent(ctx, p)
+
+				Expect(res).To(Equal(&repository.RetryEventResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DeletePublishedEvent function", Label("unit"), func() {
+		var (
+			p           repository.DeletePublishedEventParam
+			deleteQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.DeletePublishedEventParam{
+				PublishedBefore: time.UnixMilli(1659000000000),
+				Limit:           100,
+			}
+			deleteQuery = regexp.QuoteMeta(`
+				DELETE FROM file_event_outbox
+				WHERE published_at IS NOT NULL AND published_at < ?
+				ORDER BY id ASC
+				LIMIT ?
+			`)
+		})
+
+		When("failed delete published event", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.DeletePublishedEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success delete published event", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(int64(1659000000000), 100).
+					WillReturnResult(driver.RowsAffected(5))
+
+				res, err := repo.DeletePublishedEvent(ctx, p)
+
+				Expect(res).To(Equal(&repository.DeletePublishedEventResult{
+					TotalDeleted: 5,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListClientEvent function", Label("unit"), func() {
+		var (
+			p         repository.ListClientEventParam
+			listQuery string
+			columns   []string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.ListClientEventParam{
+				ClientId: "mock-client-id",
+				AfterId:  5,
+				Limit:    10,
+			}
+			listQuery = regexp.QuoteMeta(`
+				SELECT 
+					id, idempotency_key, event_type, file_id, client_id,
+					name, mimetype, extension, size,
+					attempt, next_attempt_at, created_at
+				FROM file_event_outbox
+				WHERE id > ? AND (
+					client_id = ? OR
+					file_id IN (
+						SELECT file_id
+						FROM file_share
+						WHERE client_id = ? AND permission = ?
+					)
+				)
+				ORDER BY id ASC
+				LIMIT ?
+			`)
+			columns = []string{
+				"id", "idempotency_key", "event_type", "file_id", "client_id",
+				"name", "mimetype", "extension", "size",
+				"attempt", "next_attempt_at", "created_at",
+			}
+		})
+
+		When("failed list client event", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(listQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListClientEvent(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success list client event", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows(columns).AddRow(
+					6, "file.deleted:mock-file-id", "file.deleted", "mock-file-id", "mock-owner-id",
+					"dolpin", "image/jpeg", "jpg", 200,
+					0, 1659999990000, 1659999900000,
+				)
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs(int64(5), "mock-client-id", "mock-client-id", "read", 10).
+					WillReturnRows(rows)
+
+				res, err := repo.ListClientEvent(ctx, p)
+
+				Expect(res).To(Equal(&repository.ListClientEventResult{
+					Items: []repository.OutboxEvent{
+						{
+							Id:             6,
+							IdempotencyKey: "file.deleted:mock-file-id",
+							EventType:      "file.deleted",
+							FileId:         "mock-file-id",
+							ClientId:       "mock-owner-id",
+							Name:           "dolpin",
+							Mimetype:       "image/jpeg",
+							Extension:      "jpg",
+							Size:           200,
+							NextAttemptAt:  time.UnixMilli(1659999990000),
+							CreatedAt:      time.UnixMilli(1659999900000),
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("FindLastEvent function", Label("unit"), func() {
+		var (
+			findQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			findQuery = regexp.QuoteMeta(`
+				SELECT COALESCE(MAX(id), 0)
+				FROM file_event_outbox
+			`)
+		})
+
+		When("failed find last event", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(findQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.FindLastEvent(ctx, repository.FindLastEventParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success find last event", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectQuery(findQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+
+				res, err := repo.FindLastEvent(ctx, repository.FindLastEventParam{})
+
+				Expect(res).To(Equal(&repository.FindLastEventResult{
+					Id: 42,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,112 @@
+package repository_mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+type quotaRepository struct {
+	dbClient *sql.DB
+	clock    datetime.Clock
+}
+
+func (r *quotaRepository) FindQuota(ctx context.Context, p repository.FindQuotaParam) (*repository.FindQuotaResult, error) {
+	sqlQuery := `
+		SELECT 
+			client_id, max_size, max_file,
+			used_size, used_file, updated_at
+		FROM client_quota
+		WHERE client_id = ?
+	`
+
+	var res repository.FindQuotaResult
+	var updatedAt int64
+	row := r.dbClient.QueryRow(sqlQuery, p.ClientId)
+	err := row.Scan(
+		&res.ClientId,
+		&res.MaxSize,
+		&res.MaxFile,
+		&res.UsedSize,
+		&res.UsedFile,
+		&updatedAt,
+	)
+	if err == nil {
+		res.UpdatedAt = time.UnixMilli(updatedAt)
+		return &res, nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrorRecordNotFound
+	}
+	return nil, err
+}
+
+// the usage is kept as is, only the limit of the existing client is updated
+func (r *quotaRepository) UpdateQuota(ctx context.Context, p repository.UpdateQuotaParam) (*repository.UpdateQuotaResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	upsertQuery := `
+		INSERT INTO client_quota (
+			client_id, max_size, max_file,
+			created_at, updated_at
+		)
+		SELECT client_id, ?, ?, ?, ?
+		FROM oauth_client
+		WHERE client_id = ?
+		ON DUPLICATE KEY UPDATE 
+			max_size = VALUES(max_size), 
+			max_file = VALUES(max_file), 
+			updated_at = VALUES(updated_at)
+	`
+	qRes, err := r.dbClient.Exec(
+		upsertQuery,
+		p.MaxSize,
+		p.MaxFile,
+		currentTimestamp.UnixMilli(),
+		currentTimestamp.UnixMilli(),
+		p.ClientId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.UpdateQuotaResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func NewQuotaRepository(opts ...RepoOption) (*quotaRepository, error) {
+	option := RepositoryOption{}
+	for _, opt := range opts {
+		opt(&option)
+	}
+
+	if option.dbClient == nil {
+		return nil, fmt.Errorf("invalid db client specified")
+	}
+
+	var clock datetime.Clock
+	if option.clock == nil {
+		clock = datetime.NewClock()
+	} else {
+		clock = option.clock
+	}
+
+	r := &quotaRepository{
+		dbClient: option.dbClient,
+		clock:    clock,
+	}
+	return r, nil
+}
@@ -0,0 +1,244 @@
+package repository_mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	repository_mysql "github.com/go-seidon/local/internal/repository-mysql"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quota Repository", func() {
+
+	Context("NewQuotaRepository function", Label("unit"), func() {
+		When("db client is not specified", func() {
+			It("should return error", func() {
+				res, err := repository_mysql.NewQuotaRepository()
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid db client specified")))
+			})
+		})
+
+		When("required parameter is specified", func() {
+			It("should return result", func() {
+				opt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewQuotaRepository(opt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("clock is specified", func() {
+			It("should return result", func() {
+				clockOpt := repository_mysql.WithClock(&mock.MockClock{})
+				dbOpt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewQuotaRepository(clockOpt, dbOpt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("FindQuota function", Label("unit"), func() {
+		var (
+			ctx            context.Context
+			dbClient       sqlmock.Sqlmock
+			repo           repository.QuotaRepository
+			p              repository.FindQuotaParam
+			findQuotaQuery string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			dbOpt := repository_mysql.WithDbClient(db)
+			repo, _ = repository_mysql.NewQuotaRepository(dbOpt)
+			p = repository.FindQuotaParam{
+				ClientId: "mock-client-id",
+			}
+
+			findQuotaQuery = regexp.QuoteMeta(`
+				SELECT 
+					client_id, max_size, max_file,
+					used_size, used_file, updated_at
+				FROM client_quota
+				WHERE client_id = ?
+			`)
+		})
+
+		When("quota is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WithArgs(p.ClientId).
+					WillReturnError(sql.ErrNoRows)
+
+				res, err := repo.FindQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("unexpected error happened", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WillReturnError(fmt.Errorf("error"))
+
+				res, err := repo.FindQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("error")))
+			})
+		})
+
+		When("quota is available", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows([]string{
+					"client_id", "max_size", "max_file",
+					"used_size", "used_file", "updated_at",
+				}).AddRow(
+					"mock-client-id", 2048, 10,
+					1024, 3, 1659830400000,
+				)
+				dbClient.
+					ExpectQuery(findQuotaQuery).
+					WithArgs(p.ClientId).
+					WillReturnRows(rows)
+
+				res, err := repo.FindQuota(ctx, p)
+
+				expectedRes := &repository.FindQuotaResult{
+					ClientId:  "mock-client-id",
+					MaxSize:   2048,
+					MaxFile:   10,
+					UsedSize:  1024,
+					UsedFile:  3,
+					UpdatedAt: time.UnixMilli(1659830400000),
+				}
+				Expect(res).To(Equal(expectedRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateQuota function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.QuotaRepository
+			p                repository.UpdateQuotaParam
+			upsertQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewQuotaRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.UpdateQuotaParam{
+				ClientId: "mock-client-id",
+				MaxSize:  2048,
+				MaxFile:  10,
+			}
+			upsertQuery = regexp.QuoteMeta(`
+				INSERT INTO client_quota (
+					client_id, max_size, max_file,
+					created_at, updated_at
+				)
+				SELECT client_id, ?, ?, ?, ?
+				FROM oauth_client
+				WHERE client_id = ?
+				ON DUPLICATE KEY UPDATE 
+					max_size = VALUES(max_size), 
+					max_file = VALUES(max_file), 
+					updated_at = VALUES(updated_at)
+			`)
+		})
+
+		When("failed update quota", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(upsertQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.UpdateQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(upsertQuery).
+					WithArgs(
+						p.MaxSize, p.MaxFile,
+						currentTimestamp.UnixMilli(), currentTimestamp.UnixMilli(),
+						p.ClientId,
+					).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.UpdateQuota(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success update quota", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(upsertQuery).
+					WithArgs(
+						p.MaxSize, p.MaxFile,
+						currentTimestamp.UnixMilli(), currentTimestamp.UnixMilli(),
+						p.ClientId,
+					).
+					WillReturnResult(driver.RowsAffected(2))
+
+				res, err := repo.UpdateQuota(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateQuotaResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+})
@@ -0,0 +1,93 @@
+package repository_mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+type shareRepository struct {
+	dbClient *sql.DB
+	clock    datetime.Clock
+}
+
+func (r *shareRepository) CreateShare(ctx context.Context, p repository.CreateShareParam) (*repository.CreateShareResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	insertQuery := `
+		INSERT INTO file_share (
+			file_id, client_id, permission, created_at
+		)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE created_at = created_at
+	`
+	_, err := r.dbClient.Exec(
+		insertQuery,
+		p.FileId,
+		p.ClientId,
+		p.Permission,
+		currentTimestamp.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.CreateShareResult{
+		CreatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *shareRepository) DeleteShare(ctx context.Context, p repository.DeleteShareParam) (*repository.DeleteShareResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	deleteQuery := `
+		DELETE FROM file_share
+		WHERE file_id = ? AND client_id = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		deleteQuery,
+		p.FileId,
+		p.ClientId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.DeleteShareResult{
+		DeletedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func NewShareRepository(opts ...RepoOption) (*shareRepository, error) {
+	option := RepositoryOption{}
+	for _, opt := range opts {
+		opt(&option)
+	}
+
+	if option.dbClient == nil {
+		return nil, fmt.Errorf("invalid db client specified")
+	}
+
+	var clock datetime.Clock
+	if option.clock == nil {
+		clock = datetime.NewClock()
+	} else {
+		clock = option.clock
+	}
+
+	r := &shareRepository{
+		dbClient: option.dbClient,
+		clock:    clock,
+	}
+	return r, nil
+}
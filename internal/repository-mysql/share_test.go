@@ -0,0 +1,209 @@
+package repository_mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	repository_mysql "github.com/go-seidon/local/internal/repository-mysql"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Share Repository", func() {
+
+	Context("NewShareRepository function", Label("unit"), func() {
+		When("db client is not specified", func() {
+			It("should return error", func() {
+				res, err := repository_mysql.NewShareRepository()
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid db client specified")))
+			})
+		})
+
+		When("required parameter is specified", func() {
+			It("should return result", func() {
+				opt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewShareRepository(opt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("clock is specified", func() {
+			It("should return result", func() {
+				clockOpt := repository_mysql.WithClock(&mock.MockClock{})
+				dbOpt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewShareRepository(clockOpt, dbOpt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CreateShare function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.ShareRepository
+			p                repository.CreateShareParam
+			insertQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewShareRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.CreateShareParam{
+				FileId:     "mock-file-id",
+				ClientId:   "mock-client-id",
+				Permission: repository.PERMISSION_READ,
+			}
+			insertQuery = regexp.QuoteMeta(`
+				INSERT INTO file_share (
+					file_id, client_id, permission, created_at
+				)
+				VALUES (?, ?, ?, ?)
+				ON DUPLICATE KEY UPDATE created_at = created_at
+			`)
+		})
+
+		When("failed insert share", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.CreateShare(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success insert share", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WithArgs(
+						p.FileId, p.ClientId, p.Permission,
+						currentTimestamp.UnixMilli(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				res, err := repo.CreateShare(ctx, p)
+
+				Expect(res).To(Equal(&repository.CreateShareResult{
+					CreatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DeleteShare function", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			dbClient         sqlmock.Sqlmock
+			repo             repository.ShareRepository
+			p                repository.DeleteShareParam
+			deleteQuery      string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			clock := mock.NewMockClock(ctrl)
+			clock.EXPECT().Now().Return(currentTimestamp).Times(1)
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				AbortSuite("failed create db mock: " + err.Error())
+			}
+			dbClient = mock
+
+			repo, _ = repository_mysql.NewShareRepository(
+				repository_mysql.WithDbClient(db),
+				repository_mysql.WithClock(clock),
+			)
+			p = repository.DeleteShareParam{
+				FileId:   "mock-file-id",
+				ClientId: "mock-client-id",
+			}
+			deleteQuery = regexp.QuoteMeta(`
+				DELETE FROM file_share
+				WHERE file_id = ? AND client_id = ?
+			`)
+		})
+
+		When("failed delete share", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.DeleteShare(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("share is not found", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(p.FileId, p.ClientId).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.DeleteShare(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("success delete share", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(p.FileId, p.ClientId).
+					WillReturnResult(driver.RowsAffected(2))
+
+				res, err := repo.DeleteShare(ctx, p)
+
+				Expect(res).To(Equal(&repository.DeleteShareResult{
+					DeletedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
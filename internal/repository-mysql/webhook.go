@@ -0,0 +1,431 @@
+package repository_mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+type webhookRepository struct {
+	dbClient *sql.DB
+	clock    datetime.Clock
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, p repository.CreateSubscriptionParam) (*repository.CreateSubscriptionResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	insertQuery := `
+		INSERT INTO webhook_subscription (
+			id, client_id, url, secret, event_types,
+			created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.dbClient.Exec(
+		insertQuery,
+		p.Id,
+		p.ClientId,
+		p.Url,
+		p.Secret,
+		strings.Join(p.EventTypes, " "),
+		currentTimestamp.UnixMilli(),
+		currentTimestamp.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.CreateSubscriptionResult{
+		CreatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) ListSubscription(ctx context.Context, p repository.ListSubscriptionParam) (*repository.ListSubscriptionResult, error) {
+	listQuery := `
+		SELECT 
+			id, client_id, url, secret, event_types,
+			created_at, updated_at
+		FROM webhook_subscription
+		WHERE client_id = ? AND deleted_at IS NULL
+		ORDER BY id ASC
+	`
+	rows, err := r.dbClient.Query(listQuery, p.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []repository.WebhookSubscription{}
+	for rows.Next() {
+		var item repository.WebhookSubscription
+		var eventTypes string
+		var createdAt, updatedAt int64
+		err := rows.Scan(
+			&item.Id,
+			&item.ClientId,
+			&item.Url,
+			&item.Secret,
+			&eventTypes,
+			&createdAt,
+			&updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.EventTypes = strings.Fields(eventTypes)
+		item.CreatedAt = time.UnixMilli(createdAt)
+		item.UpdatedAt = time.UnixMilli(updatedAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListSubscriptionResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, p repository.DeleteSubscriptionParam) (*repository.DeleteSubscriptionResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	tx, err := r.dbClient.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteQuery := `
+		UPDATE webhook_subscription
+		SET deleted_at = ?, updated_at = ?
+		WHERE id = ? AND client_id = ? AND deleted_at IS NULL
+	`
+	qRes, err := tx.Exec(
+		deleteQuery,
+		currentTimestamp.UnixMilli(),
+		currentTimestamp.UnixMilli(),
+		p.Id,
+		p.ClientId,
+	)
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	cancelQuery := `
+		UPDATE webhook_delivery
+		SET status = ?, updated_at = ?
+		WHERE subscription_id = ? AND status = ?
+	`
+	_, err = tx.Exec(
+		cancelQuery,
+		repository.DELIVERY_CANCELED,
+		currentTimestamp.UnixMilli(),
+		p.Id,
+		repository.DELIVERY_PENDING,
+	)
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			return nil, txErr
+		}
+		return nil, err
+	}
+
+	txErr := tx.Commit()
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	res := &repository.DeleteSubscriptionResult{
+		DeletedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, p repository.CreateDeliveryParam) (*repository.CreateDeliveryResult, error) {
+	currentTimestamp := r.clock.Now()
+	if len(p.Items) == 0 {
+		return &repository.CreateDeliveryResult{CreatedAt: currentTimestamp}, nil
+	}
+
+	values := []string{}
+	args := []interface{}{}
+	for _, item := range p.Items {
+		values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			item.Id,
+			item.SubscriptionId,
+			item.ClientId,
+			item.EventId,
+			item.EventType,
+			item.Payload,
+			repository.DELIVERY_PENDING,
+			currentTimestamp.UnixMilli(),
+			currentTimestamp.UnixMilli(),
+			currentTimestamp.UnixMilli(),
+		)
+	}
+
+	// the delivery of the published event is ignored, so the event is idempotent per subscription
+	insertQuery := `
+		INSERT IGNORE INTO webhook_delivery (
+			id, subscription_id, client_id, event_id, event_type,
+			payload, status, next_attempt_at, created_at, updated_at
+		)
+		VALUES ` + strings.Join(values, ", ")
+	_, err := r.dbClient.Exec(insertQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &repository.CreateDeliveryResult{
+		CreatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) ListDueDelivery(ctx context.Context, p repository.ListDueDeliveryParam) (*repository.ListDueDeliveryResult, error) {
+	listQuery := `
+		SELECT 
+			d.id, d.subscription_id, d.event_id, d.event_type,
+			d.payload, d.attempt, d.next_attempt_at,
+			s.url, s.secret
+		FROM webhook_delivery d
+		INNER JOIN webhook_subscription s ON s.id = d.subscription_id
+		WHERE d.status = ? AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at ASC
+		LIMIT ?
+	`
+	rows, err := r.dbClient.Query(
+		listQuery,
+		repository.DELIVERY_PENDING,
+		p.DueAt.UnixMilli(),
+		p.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []repository.DueDelivery{}
+	for rows.Next() {
+		var item repository.DueDelivery
+		var nextAttemptAt int64
+		err := rows.Scan(
+			&item.Id,
+			&item.SubscriptionId,
+			&item.EventId,
+			&item.EventType,
+			&item.Payload,
+			&item.Attempt,
+			&nextAttemptAt,
+			&item.Url,
+			&item.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListDueDeliveryResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) ClaimDelivery(ctx context.Context, p repository.ClaimDeliveryParam) (*repository.ClaimDeliveryResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	claimQuery := `
+		UPDATE webhook_delivery
+		SET next_attempt_at = ?, updated_at = ?
+		WHERE id = ? AND status = ? AND next_attempt_at = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		claimQuery,
+		p.LeaseUntil.UnixMilli(),
+		currentTimestamp.UnixMilli(),
+		p.Id,
+		repository.DELIVERY_PENDING,
+		p.NextAttemptAt.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordChanged
+	}
+
+	res := &repository.ClaimDeliveryResult{
+		ClaimedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, p repository.UpdateDeliveryParam) (*repository.UpdateDeliveryResult, error) {
+	currentTimestamp := r.clock.Now()
+
+	deliveredAt := sql.NullInt64{}
+	if p.Status == repository.DELIVERY_DELIVERED {
+		deliveredAt = sql.NullInt64{Int64: currentTimestamp.UnixMilli(), Valid: true}
+	}
+
+	updateQuery := `
+		UPDATE webhook_delivery
+		SET status = ?, attempt = ?, next_attempt_at = ?, response_code = ?, 
+			last_error = ?, delivered_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+	qRes, err := r.dbClient.Exec(
+		updateQuery,
+		p.Status,
+		p.Attempt,
+		p.NextAttemptAt.UnixMilli(),
+		p.ResponseCode,
+		p.LastError,
+		deliveredAt,
+		currentTimestamp.UnixMilli(),
+		p.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAffected, _ := qRes.RowsAffected()
+	if totalAffected == 0 {
+		return nil, repository.ErrorRecordNotFound
+	}
+
+	res := &repository.UpdateDeliveryResult{
+		UpdatedAt: currentTimestamp,
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) ListDelivery(ctx context.Context, p repository.ListDeliveryParam) (*repository.ListDeliveryResult, error) {
+	conditions := []string{"id > ?"}
+	args := []interface{}{p.AfterId}
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"client_id", p.ClientId},
+		{"subscription_id", p.SubscriptionId},
+		{"status", p.Status},
+	}
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+		conditions = append(conditions, filter.column+" = ?")
+		args = append(args, filter.value)
+	}
+	args = append(args, p.Limit)
+
+	listQuery := `
+		SELECT 
+			id, subscription_id, client_id, event_id, event_type,
+			status, attempt, next_attempt_at, response_code, last_error,
+			created_at, updated_at, delivered_at
+		FROM webhook_delivery
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY id ASC
+		LIMIT ?
+	`
+	rows, err := r.dbClient.Query(listQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []repository.WebhookDelivery{}
+	for rows.Next() {
+		var item repository.WebhookDelivery
+		var nextAttemptAt, createdAt, updatedAt int64
+		var deliveredAt sql.NullInt64
+		err := rows.Scan(
+			&item.Id,
+			&item.SubscriptionId,
+			&item.ClientId,
+			&item.EventId,
+			&item.EventType,
+			&item.Status,
+			&item.Attempt,
+			&nextAttemptAt,
+			&item.ResponseCode,
+			&item.LastError,
+			&createdAt,
+			&updatedAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+		item.CreatedAt = time.UnixMilli(createdAt)
+		item.UpdatedAt = time.UnixMilli(updatedAt)
+		if deliveredAt.Valid {
+			delivered := time.UnixMilli(deliveredAt.Int64)
+			item.DeliveredAt = &delivered
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res := &repository.ListDeliveryResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+func NewWebhookRepository(opts ...RepoOption) (*webhookRepository, error) {
+	option := RepositoryOption{}
+	for _, opt := range opts {
+		opt(&option)
+	}
+
+	if option.dbClient == nil {
+		return nil, fmt.Errorf("invalid db client specified")
+	}
+
+	var clock datetime.Clock
+	if option.clock == nil {
+		clock = datetime.NewClock()
+	} else {
+		clock = option.clock
+	}
+
+	r := &webhookRepository{
+		dbClient: option.dbClient,
+		clock:    clock,
+	}
+	return r, nil
+}
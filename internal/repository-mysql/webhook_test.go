@@ -0,0 +1,750 @@
+package repository_mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	repository_mysql "github.com/go-seidon/local/internal/repository-mysql"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook Repository", func() {
+
+	Context("NewWebhookRepository function", Label("unit"), func() {
+		When("db client is not specified", func() {
+			It("should return error", func() {
+				res, err := repository_mysql.NewWebhookRepository()
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid db client specified")))
+			})
+		})
+
+		When("required parameter is specified", func() {
+			It("should return result", func() {
+				opt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewWebhookRepository(opt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("clock is specified", func() {
+			It("should return result", func() {
+				clockOpt := repository_mysql.WithClock(&mock.MockClock{})
+				dbOpt := repository_mysql.WithDbClient(&sql.DB{})
+				res, err := repository_mysql.NewWebhookRepository(clockOpt, dbOpt)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	var (
+		ctx              context.Context
+		currentTimestamp time.Time
+		dbClient         sqlmock.Sqlmock
+		repo             repository.WebhookRepository
+	)
+
+	setup := func() {
+		ctx = context.Background()
+		currentTimestamp = time.UnixMilli(1660000000000)
+		ctrl := gomock.NewController(GinkgoT())
+		clock := mock.NewMockClock(ctrl)
+		clock.EXPECT().Now().Return(currentTimestamp).AnyTimes()
+
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			AbortSuite("failed create db mock: " + err.Error())
+		}
+		dbClient = mock
+
+		repo, _ = repository_mysql.NewWebhookRepository(
+			repository_mysql.WithDbClient(db),
+			repository_mysql.WithClock(clock),
+		)
+	}
+
+	Context("CreateSubscription function", Label("unit"), func() {
+		var (
+			p           repository.CreateSubscriptionParam
+			insertQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.CreateSubscriptionParam{
+				Id:         "mock-id",
+				ClientId:   "mock-client-id",
+				Url:        "https://example.com/hook",
+				Secret:     "mock-secret",
+				EventTypes: []string{"file.uploaded", "file.deleted"},
+			}
+			insertQuery = regexp.QuoteMeta(`INSERT INTO webhook_subscription`)
+		})
+
+		When("failed create subscription", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.CreateSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success create subscription", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(insertQuery).
+					WithArgs(
+						"mock-id", "mock-client-id", "https://example.com/hook", "mock-secret",
+						"file.uploaded file.deleted", int64(1660000000000), int64(1660000000000),
+					).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.CreateSubscription(ctx, p)
+
+				Expect(res).To(Equal(&repository.CreateSubscriptionResult{
+					CreatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListSubscription function", Label("unit"), func() {
+		var (
+			p         repository.ListSubscriptionParam
+			listQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.ListSubscriptionParam{
+				ClientId: "mock-client-id",
+			}
+			listQuery = regexp.QuoteMeta(`WHERE client_id = ? AND deleted_at IS NULL`)
+		})
+
+		When("failed query subscriptions", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs("mock-client-id").
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan row", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("mock-id")
+				dbClient.
+					ExpectQuery(listQuery).
+					WillReturnRows(rows)
+
+				res, err := repo.ListSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("success list subscriptions", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "client_id", "url", "secret", "event_types", "created_at", "updated_at",
+				}).AddRow(
+					"mock-id", "mock-client-id", "https://example.com/hook", "mock-secret",
+					"file.uploaded file.deleted", int64(1660000000000), int64(1660000001000),
+				)
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs("mock-client-id").
+					WillReturnRows(rows)
+
+				res, err := repo.ListSubscription(ctx, p)
+
+				Expect(res).To(Equal(&repository.ListSubscriptionResult{
+					Items: []repository.WebhookSubscription{
+						{
+							Id:         "mock-id",
+							ClientId:   "mock-client-id",
+							Url:        "https://example.com/hook",
+							Secret:     "mock-secret",
+							EventTypes: []string{"file.uploaded", "file.deleted"},
+							CreatedAt:  time.UnixMilli(1660000000000),
+							UpdatedAt:  time.UnixMilli(1660000001000),
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("DeleteSubscription function", Label("unit"), func() {
+		var (
+			p           repository.DeleteSubscriptionParam
+			deleteQuery string
+			cancelQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.DeleteSubscriptionParam{
+				Id:       "mock-id",
+				ClientId: "mock-client-id",
+			}
+			deleteQuery = regexp.QuoteMeta(`UPDATE webhook_subscription`)
+			cancelQuery = regexp.QuoteMeta(`UPDATE webhook_delivery`)
+		})
+
+		When("failed begin transaction", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectBegin().
+					WillReturnError(fmt.Errorf("tx error"))
+
+				res, err := repo.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("tx error")))
+			})
+		})
+
+		When("failed delete subscription", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed rollback transaction", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.
+					ExpectRollback().
+					WillReturnError(fmt.Errorf("rollback error"))
+
+				res, err := repo.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("rollback error")))
+			})
+		})
+
+		When("subscription is not available", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(deleteQuery).
+					WithArgs(int64(1660000000000), int64(1660000000000), "mock-id", "mock-client-id").
+					WillReturnResult(driver.RowsAffected(0))
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("failed cancel pending deliveries", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(cancelQuery).
+					WillReturnError(fmt.Errorf("db error"))
+				dbClient.ExpectRollback()
+
+				res, err := repo.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed commit transaction", func() {
+			It("should return error", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(cancelQuery).
+					WillReturnResult(driver.RowsAffected(2))
+				dbClient.
+					ExpectCommit().
+					WillReturnError(fmt.Errorf("commit error"))
+
+				res, err := repo.DeleteSubscription(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("commit error")))
+			})
+		})
+
+		When("success delete subscription", func() {
+			It("should return result", func() {
+				dbClient.ExpectBegin()
+				dbClient.
+					ExpectExec(deleteQuery).
+					WillReturnResult(driver.RowsAffected(1))
+				dbClient.
+					ExpectExec(cancelQuery).
+					WithArgs("canceled", int64(1660000000000), "mock-id", "pending").
+					WillReturnResult(driver.RowsAffected(2))
+				dbClient.ExpectCommit()
+
+				res, err := repo.DeleteSubscription(ctx, p)
+
+				Expect(res).To(Equal(&repository.DeleteSubscriptionResult{
+					DeletedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CreateDelivery function", Label("unit"), func() {
+		var (
+			p repository.CreateDeliveryParam
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.CreateDeliveryParam{
+				Items: []repository.CreateDeliveryItem{
+					{
+						Id:             "mock-id-1",
+						SubscriptionId: "mock-subscription-id-1",
+						ClientId:       "mock-client-id",
+						EventId:        "mock-event-id",
+						EventType:      "file.uploaded",
+						Payload:        "{}",
+					},
+					{
+						Id:             "mock-id-2",
+						SubscriptionId: "mock-subscription-id-2",
+						ClientId:       "mock-client-id",
+						EventId:        "mock-event-id",
+						EventType:      "file.uploaded",
+						Payload:        "{}",
+					},
+				},
+			}
+		})
+
+		When("there is no delivery", func() {
+			It("should return result", func() {
+				res, err := repo.CreateDelivery(ctx, repository.CreateDeliveryParam{})
+
+				Expect(res).To(Equal(&repository.CreateDeliveryResult{
+					CreatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed create delivery", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(regexp.QuoteMeta(`INSERT IGNORE INTO webhook_delivery`)).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.CreateDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success create delivery", func() {
+			It("should return result", func() {
+				ts := int64(1660000000000)
+				dbClient.
+					ExpectExec(regexp.QuoteMeta(`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)).
+					WithArgs(
+						"mock-id-1", "mock-subscription-id-1", "mock-client-id", "mock-event-id", "file.uploaded", "{}", "pending", ts, ts, ts,
+						"mock-id-2", "mock-subscription-id-2", "mock-client-id", "mock-event-id", "file.uploaded", "{}", "pending", ts, ts, ts,
+					).
+					WillReturnResult(driver.RowsAffected(2))
+
+				res, err := repo.CreateDelivery(ctx, p)
+
+				Expect(res).To(Equal(&repository.CreateDeliveryResult{
+					CreatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListDueDelivery function", Label("unit"), func() {
+		var (
+			p         repository.ListDueDeliveryParam
+			listQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.ListDueDeliveryParam{
+				DueAt: currentTimestamp,
+				Limit: 10,
+			}
+			listQuery = regexp.QuoteMeta(`WHERE d.status = ? AND d.next_attempt_at <= ?`)
+		})
+
+		When("failed query due deliveries", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs("pending", int64(1660000000000), 10).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListDueDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan row", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("mock-id")
+				dbClient.
+					ExpectQuery(listQuery).
+					WillReturnRows(rows)
+
+				res, err := repo.ListDueDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("success list due deliveries", func() {
+			It("should return result", func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "subscription_id", "event_id", "event_type",
+					"payload", "attempt", "next_attempt_at", "url", "secret",
+				}).AddRow(
+					"mock-id", "mock-subscription-id", "mock-event-id", "file.deleted",
+					"{}", 2, int64(1659999990000), "https://example.com/hook", "mock-secret",
+				)
+				dbClient.
+					ExpectQuery(listQuery).
+					WithArgs("pending", int64(1660000000000), 10).
+					WillReturnRows(rows)
+
+				res, err := repo.ListDueDelivery(ctx, p)
+
+				Expect(res).To(Equal(&repository.ListDueDeliveryResult{
+					Items: []repository.DueDelivery{
+						{
+							Id:             "mock-id",
+							SubscriptionId: "mock-subscription-id",
+							EventId:        "mock-event-id",
+							EventType:      "file.deleted",
+							Payload:        "{}",
+							Attempt:        2,
+							NextAttemptAt:  time.UnixMilli(1659999990000),
+							Url:            "https://example.com/hook",
+							Secret:         "mock-secret",
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ClaimDelivery function", Label("unit"), func() {
+		var (
+			p          repository.ClaimDeliveryParam
+			claimQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.ClaimDeliveryParam{
+				Id:            "mock-id",
+				NextAttemptAt: time.UnixMilli(1659999990000),
+				LeaseUntil:    time.UnixMilli(1660000030000),
+			}
+			claimQuery = regexp.QuoteMeta(`WHERE id = ? AND status = ? AND next_attempt_at = ?`)
+		})
+
+		When("failed claim delivery", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(claimQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ClaimDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("delivery has been changed", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(claimQuery).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.ClaimDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordChanged))
+			})
+		})
+
+		When("success claim delivery", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(claimQuery).
+					WithArgs(int64(1660000030000), int64(1660000000000), "mock-id", "pending", int64(1659999990000)).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.ClaimDelivery(ctx, p)
+
+				Expect(res).To(Equal(&repository.ClaimDeliveryResult{
+					ClaimedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateDelivery function", Label("unit"), func() {
+		var (
+			p           repository.UpdateDeliveryParam
+			updateQuery string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.UpdateDeliveryParam{
+				Id:            "mock-id",
+				Status:        repository.DELIVERY_PENDING,
+				Attempt:       1,
+				NextAttemptAt: time.UnixMilli(1660000010000),
+				ResponseCode:  500,
+				LastError:     "unexpected response status: 500",
+			}
+			updateQuery = regexp.QuoteMeta(`UPDATE webhook_delivery`)
+		})
+
+		When("failed update delivery", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.UpdateDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("delivery is not available", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WillReturnResult(driver.RowsAffected(0))
+
+				res, err := repo.UpdateDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(repository.ErrorRecordNotFound))
+			})
+		})
+
+		When("delivery is rescheduled", func() {
+			It("should return result", func() {
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(
+						"pending", 1, int64(1660000010000), 500,
+						"unexpected response status: 500", nil, int64(1660000000000), "mock-id",
+					).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.UpdateDelivery(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateDeliveryResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("delivery is delivered", func() {
+			It("should set delivered time", func() {
+				p.Status = repository.DELIVERY_DELIVERED
+				p.ResponseCode = 200
+				p.LastError = ""
+				dbClient.
+					ExpectExec(updateQuery).
+					WithArgs(
+						"delivered", 1, int64(1660000010000), 200,
+						"", int64(1660000000000), int64(1660000000000), "mock-id",
+					).
+					WillReturnResult(driver.RowsAffected(1))
+
+				res, err := repo.UpdateDelivery(ctx, p)
+
+				Expect(res).To(Equal(&repository.UpdateDeliveryResult{
+					UpdatedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListDelivery function", Label("unit"), func() {
+		var (
+			p       repository.ListDeliveryParam
+			columns []string
+		)
+
+		BeforeEach(func() {
+			setup()
+			p = repository.ListDeliveryParam{
+				ClientId: "mock-client-id",
+				Limit:    10,
+			}
+			columns = []string{
+				"id", "subscription_id", "client_id", "event_id", "event_type",
+				"status", "attempt", "next_attempt_at", "response_code", "last_error",
+				"created_at", "updated_at", "delivered_at",
+			}
+		})
+
+		When("failed query deliveries", func() {
+			It("should return error", func() {
+				dbClient.
+					ExpectQuery(regexp.QuoteMeta(`WHERE id > ? AND client_id = ?`)).
+					WithArgs("", "mock-client-id", 10).
+					WillReturnError(fmt.Errorf("db error"))
+
+				res, err := repo.ListDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("failed scan row", func() {
+			It("should return error", func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("mock-id")
+				dbClient.
+					ExpectQuery(regexp.QuoteMeta(`FROM webhook_delivery`)).
+					WillReturnRows(rows)
+
+				res, err := repo.ListDelivery(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("filters are specified", func() {
+			It("should return result", func() {
+				p.SubscriptionId = "mock-subscription-id"
+				p.Status = "delivered"
+				p.AfterId = "mock-after-id"
+				rows := sqlmock.NewRows(columns).
+					AddRow(
+						"mock-id-1", "mock-subscription-id", "mock-client-id", "mock-event-id", "file.uploaded",
+						"delivered", 1, int64(1660000000000), 204, "",
+						int64(1660000000000), int64(1660000001000), int64(1660000001000),
+					).
+					AddRow(
+						"mock-id-2", "mock-subscription-id", "mock-client-id", "mock-event-id", "file.uploaded",
+						"dead", 8, int64(1660000000000), 0, "timeout",
+						int64(1660000000000), int64(1660000001000), nil,
+					)
+				dbClient.
+					ExpectQuery(regexp.QuoteMeta(`WHERE id > ? AND client_id = ? AND subscription_id = ? AND status = ?`)).
+					WithArgs("mock-after-id", "mock-client-id", "mock-subscription-id", "delivered", 10).
+					WillReturnRows(rows)
+
+				res, err := repo.ListDelivery(ctx, p)
+
+				deliveredAt := time.UnixMilli(1660000001000)
+				Expect(res).To(Equal(&repository.ListDeliveryResult{
+					Items: []repository.WebhookDelivery{
+						{
+							Id:             "mock-id-1",
+							SubscriptionId: "mock-subscription-id",
+							ClientId:       "mock-client-id",
+							EventId:        "mock-event-id",
+							EventType:      "file.uploaded",
+							Status:         "delivered",
+							Attempt:        1,
+							NextAttemptAt:  time.UnixMilli(1660000000000),
+							ResponseCode:   204,
+							CreatedAt:      time.UnixMilli(1660000000000),
+							UpdatedAt:      time.UnixMilli(1660000001000),
+							DeliveredAt:    &deliveredAt,
+						},
+						{
+							Id:             "mock-id-2",
+							SubscriptionId: "mock-subscription-id",
+							ClientId:       "mock-client-id",
+							EventId:        "mock-event-id",
+							EventType:      "file.uploaded",
+							Status:         "dead",
+							Attempt:        8,
+							NextAttemptAt:  time.UnixMilli(1660000000000),
+							LastError:      "timeout",
+							CreatedAt:      time.UnixMilli(1660000000000),
+							UpdatedAt:      time.UnixMilli(1660000001000),
+						},
+					},
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
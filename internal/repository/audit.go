@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// audit log is append-only, the record is never updated nor deleted
+// and it is kept after the file or the client is deleted
+type AuditRepository interface {
+	CreateAuditLog(ctx context.Context, p CreateAuditLogParam) (*CreateAuditLogResult, error)
+	ListAuditLog(ctx context.Context, p ListAuditLogParam) (*ListAuditLogResult, error)
+}
+
+type CreateAuditLogParam struct {
+	Id        string
+	ClientId  string
+	Action    string
+	FileId    string
+	IpAddress string
+	RequestId string
+	Outcome   string
+	Reason    string
+}
+
+type CreateAuditLogResult struct {
+	CreatedAt time.Time
+}
+
+type ListAuditLogParam struct {
+	ClientId    string
+	Action      string
+	FileId      string
+	Outcome     string
+	RequestId   string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	AfterId     string
+	Limit       int
+}
+
+type ListAuditLogResult struct {
+	Items []AuditLog
+}
+
+type AuditLog struct {
+	Id        string
+	ClientId  string
+	Action    string
+	FileId    string
+	IpAddress string
+	RequestId string
+	Outcome   string
+	Reason    string
+	CreatedAt time.Time
+}
@@ -5,4 +5,7 @@ import "errors"
 var (
 	ErrorRecordNotFound = errors.New("record not found")
 	ErrorRecordDeleted  = errors.New("record deleted")
+	ErrorRecordChanged  = errors.New("record has been changed")
+	ErrorQuotaExceeded  = errors.New("quota exceeded")
+	ErrorAccessDenied   = errors.New("access denied")
 )
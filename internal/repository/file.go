@@ -8,17 +8,30 @@ import (
 type (
 	DeleteFn func(ctx context.Context, p DeleteFnParam) error
 	CreateFn func(ctx context.Context, p CreateFnParam) error
+	MoveFn   func(ctx context.Context, p MoveFnParam) error
+	RekeyFn  func(ctx context.Context, p RekeyFnParam) error
+)
+
+const (
+	VISIBILITY_PUBLIC  = "public"
+	VISIBILITY_PRIVATE = "private"
 )
 
 type FileRepository interface {
 	DeleteFile(ctx context.Context, p DeleteFileParam) (*DeleteFileResult, error)
 	RetrieveFile(ctx context.Context, p RetrieveFileParam) (*RetrieveFileResult, error)
 	CreateFile(ctx context.Context, p CreateFileParam) (*CreateFileResult, error)
+	ListFile(ctx context.Context, p ListFileParam) (*ListFileResult, error)
+	UpdateFilePath(ctx context.Context, p UpdateFilePathParam) (*UpdateFilePathResult, error)
+	UpdateFileKey(ctx context.Context, p UpdateFileKeyParam) (*UpdateFileKeyResult, error)
+	UpdateFileVisibility(ctx context.Context, p UpdateFileVisibilityParam) (*UpdateFileVisibilityResult, error)
 }
 
 type DeleteFileParam struct {
-	UniqueId string
-	DeleteFn DeleteFn
+	UniqueId     string
+	ClientId     string
+	AllowUnowned bool
+	DeleteFn     DeleteFn
 }
 
 type DeleteFnParam struct {
@@ -26,29 +39,49 @@ type DeleteFnParam struct {
 }
 
 type DeleteFileResult struct {
+	UniqueId  string
+	Name      string
+	MimeType  string
+	Extension string
+	Size      int64
+	ClientId  string
 	DeletedAt time.Time
 }
 
 type RetrieveFileParam struct {
-	UniqueId string
+	UniqueId     string
+	ClientId     string
+	AllowUnowned bool
+	// access is not checked, e.g: the public file or the owner check by the caller
+	SkipAccessCheck bool
 }
 
 type RetrieveFileResult struct {
-	UniqueId  string
-	Name      string
-	Path      string
-	MimeType  string
-	Extension string
+	UniqueId   string
+	Name       string
+	Path       string
+	MimeType   string
+	Extension  string
+	Size       int64
+	Codec      string
+	ClientId   string
+	Visibility string
 }
 
 type CreateFileParam struct {
-	UniqueId  string
-	Name      string
-	Path      string
-	Mimetype  string
-	Extension string
-	Size      int64
-	CreateFn  CreateFn
+	UniqueId        string
+	Name            string
+	Path            string
+	Mimetype        string
+	Extension       string
+	Size            int64
+	Attributes      map[string]string
+	Scan            *FileScan
+	EncryptionKeyId string
+	Codec           string
+	ClientId        string
+	Visibility      string
+	CreateFn        CreateFn
 }
 
 type CreateFnParam struct {
@@ -56,11 +89,87 @@ type CreateFnParam struct {
 }
 
 type CreateFileResult struct {
-	UniqueId  string
-	Name      string
-	Path      string
-	Mimetype  string
-	Extension string
-	Size      int64
-	CreatedAt time.Time
+	UniqueId        string
+	Name            string
+	Path            string
+	Mimetype        string
+	Extension       string
+	Size            int64
+	Attributes      map[string]string
+	Scan            *FileScan
+	EncryptionKeyId string
+	Codec           string
+	ClientId        string
+	Visibility      string
+	CreatedAt       time.Time
+}
+
+type FileScan struct {
+	Status    string
+	Engine    string
+	Signature string
+	ScannedAt time.Time
+}
+
+type ListFileParam struct {
+	AfterId string
+	Limit   int
+}
+
+type ListFileResult struct {
+	Items []ListFileItem
+}
+
+type ListFileItem struct {
+	UniqueId        string
+	Name            string
+	Path            string
+	Mimetype        string
+	Extension       string
+	Size            int64
+	EncryptionKeyId string
+	ClientId        string
+	CreatedAt       time.Time
+}
+
+type UpdateFilePathParam struct {
+	UniqueId string
+	// current path, the update is rejected when the record has been changed
+	OldPath string
+	NewPath string
+	MoveFn  MoveFn
+}
+
+type MoveFnParam struct {
+	OldPath string
+	NewPath string
+}
+
+type UpdateFilePathResult struct {
+	UpdatedAt time.Time
+}
+
+type UpdateFileKeyParam struct {
+	UniqueId string
+	// current key id, the update is rejected when the record has been changed
+	OldKeyId string
+	NewKeyId string
+	RekeyFn  RekeyFn
+}
+
+type RekeyFnParam struct {
+	FilePath string
+}
+
+type UpdateFileKeyResult struct {
+	UpdatedAt time.Time
+}
+
+type UpdateFileVisibilityParam struct {
+	UniqueId   string
+	Visibility string
+}
+
+type UpdateFileVisibilityResult struct {
+	UpdatedAt time.Time
 }
@@ -1,9 +1,25 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type OAuthRepository interface {
 	FindClient(ctx context.Context, p FindClientParam) (*FindClientResult, error)
+	CreateClient(ctx context.Context, p CreateClientParam) (*CreateClientResult, error)
+	ListClient(ctx context.Context, p ListClientParam) (*ListClientResult, error)
+	DisableClient(ctx context.Context, p DisableClientParam) (*DisableClientResult, error)
+	DeleteClient(ctx context.Context, p DeleteClientParam) (*DeleteClientResult, error)
+	CreateClientSecret(ctx context.Context, p CreateClientSecretParam) (*CreateClientSecretResult, error)
+	ListClientSecret(ctx context.Context, p ListClientSecretParam) (*ListClientSecretResult, error)
+	ExpireClientSecret(ctx context.Context, p ExpireClientSecretParam) (*ExpireClientSecretResult, error)
+	DeleteClientSecret(ctx context.Context, p DeleteClientSecretParam) (*DeleteClientSecretResult, error)
+	UpdateSecretUsage(ctx context.Context, p UpdateSecretUsageParam) (*UpdateSecretUsageResult, error)
+	UpdateSecretHash(ctx context.Context, p UpdateSecretHashParam) (*UpdateSecretHashResult, error)
+	UpdateClientAllowlist(ctx context.Context, p UpdateClientAllowlistParam) (*UpdateClientAllowlistResult, error)
+	FindClientRateLimit(ctx context.Context, p FindClientRateLimitParam) (*FindClientRateLimitResult, error)
+	UpdateClientRateLimit(ctx context.Context, p UpdateClientRateLimitParam) (*UpdateClientRateLimitResult, error)
 }
 
 type FindClientParam struct {
@@ -12,5 +28,158 @@ type FindClientParam struct {
 
 type FindClientResult struct {
 	ClientId     string
+	Scopes       []string
+	AllowedCidrs []string
+	Secrets      []ClientSecret
+}
+
+type ClientSecret struct {
+	Id         string
+	Label      string
+	Secret     string
+	SigningKey string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+type CreateClientParam struct {
+	Id           string
+	Name         string
+	ClientId     string
+	Scopes       []string
+	AllowedCidrs []string
 	ClientSecret string
+	SecretId     string
+	SecretLabel  string
+	SigningKey   string
+}
+
+type CreateClientResult struct {
+	CreatedAt time.Time
+}
+
+type ListClientParam struct {
+	AfterId string
+	Limit   int
+}
+
+type ListClientResult struct {
+	Items []ListClientItem
+}
+
+type ListClientItem struct {
+	Id           string
+	Name         string
+	ClientId     string
+	Scopes       []string
+	AllowedCidrs []string
+	DisabledAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type DisableClientParam struct {
+	ClientId string
+}
+
+type DisableClientResult struct {
+	DisabledAt time.Time
+}
+
+type DeleteClientParam struct {
+	ClientId string
+}
+
+type DeleteClientResult struct {
+	DeletedAt time.Time
+}
+
+type CreateClientSecretParam struct {
+	Id         string
+	ClientId   string
+	Label      string
+	Secret     string
+	SigningKey string
+	ExpiresAt  *time.Time
+}
+
+type CreateClientSecretResult struct {
+	CreatedAt time.Time
+}
+
+type ListClientSecretParam struct {
+	ClientId string
+}
+
+type ListClientSecretResult struct {
+	Items []ClientSecret
+}
+
+// expire every secret of the client except the specified one,
+// secret expiring earlier than the specified time is not extended
+type ExpireClientSecretParam struct {
+	ClientId  string
+	ExceptId  string
+	ExpiresAt time.Time
+}
+
+type ExpireClientSecretResult struct {
+	TotalExpired int64
+}
+
+type DeleteClientSecretParam struct {
+	ClientId string
+	SecretId string
+}
+
+type DeleteClientSecretResult struct {
+	DeletedAt time.Time
+}
+
+type UpdateSecretUsageParam struct {
+	SecretId string
+}
+
+type UpdateSecretUsageResult struct {
+	LastUsedAt time.Time
+}
+
+type UpdateSecretHashParam struct {
+	SecretId string
+	Secret   string
+}
+
+type UpdateSecretHashResult struct {
+	UpdatedAt time.Time
+}
+
+type UpdateClientAllowlistParam struct {
+	ClientId     string
+	AllowedCidrs []string
+}
+
+type UpdateClientAllowlistResult struct {
+	UpdatedAt time.Time
+}
+
+type FindClientRateLimitParam struct {
+	ClientId string
+}
+
+// nil rate means the default limit is applied, zero rate means unlimited
+type FindClientRateLimitResult struct {
+	ClientId    string
+	RequestRate *int64
+	ByteRate    *int64
+}
+
+type UpdateClientRateLimitParam struct {
+	ClientId    string
+	RequestRate *int64
+	ByteRate    *int64
+}
+
+type UpdateClientRateLimitResult struct {
+	UpdatedAt time.Time
 }
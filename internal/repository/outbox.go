@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	EVENT_FILE_UPLOADED = "file.uploaded"
+	EVENT_FILE_DELETED  = "file.deleted"
+)
+
+// file event is recorded by the file repository within the file transaction,
+// so the event is only recorded when the file change is committed
+type OutboxRepository interface {
+	ListPendingEvent(ctx context.Context, p ListPendingEventParam) (*ListPendingEventResult, error)
+	ClaimEvent(ctx context.Context, p ClaimEventParam) (*ClaimEventResult, error)
+	PublishEvent(ctx context.Context, p PublishEventParam) (*PublishEventResult, error)
+	RetryEvent(ctx context.Context, p RetryEventParam) (*RetryEventResult, error)
+	DeletePublishedEvent(ctx context.Context, p DeletePublishedEventParam) (*DeletePublishedEventResult, error)
+	ListClientEvent(ctx context.Context, p ListClientEventParam) (*ListClientEventResult, error)
+	FindLastEvent(ctx context.Context, p FindLastEventParam) (*FindLastEventResult, error)
+}
+
+type ListPendingEventParam struct {
+	DueAt time.Time
+	Limit int
+}
+
+type ListPendingEventResult struct {
+	Items []OutboxEvent
+}
+
+type OutboxEvent struct {
+	Id             int64
+	IdempotencyKey string
+	EventType      string
+	FileId         string
+	ClientId       string
+	Name           string
+	Mimetype       string
+	Extension      string
+	Size           int64
+	Attempt        int
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+}
+
+// the event is leased until the specified time, so the other relay skips it,
+// the claim is rejected when the event has been claimed or published
+type ClaimEventParam struct {
+	Id            int64
+	NextAttemptAt time.Time
+	LeaseUntil    time.Time
+}
+
+type ClaimEventResult struct {
+	ClaimedAt time.Time
+}
+
+type PublishEventParam struct {
+	Id int64
+}
+
+type PublishEventResult struct {
+	PublishedAt time.Time
+}
+
+type RetryEventParam struct {
+	Id            int64
+	Attempt       int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+type RetryEventResult struct {
+	UpdatedAt time.Time
+}
+
+type DeletePublishedEventParam struct {
+	PublishedBefore time.Time
+	Limit           int
+}
+
+type DeletePublishedEventResult struct {
+	TotalDeleted int64
+}
+
+// the events of the owned files and the files shared to the client with read permission,
+// the events are listed regardless of the publish status
+type ListClientEventParam struct {
+	ClientId string
+	AfterId  int64
+	Limit    int
+}
+
+type ListClientEventResult struct {
+	Items []OutboxEvent
+}
+
+type FindLastEventParam struct {
+}
+
+type FindLastEventResult struct {
+	// zero when there is no event
+	Id int64
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type QuotaRepository interface {
+	FindQuota(ctx context.Context, p FindQuotaParam) (*FindQuotaResult, error)
+	UpdateQuota(ctx context.Context, p UpdateQuotaParam) (*UpdateQuotaResult, error)
+}
+
+type FindQuotaParam struct {
+	ClientId string
+}
+
+type FindQuotaResult struct {
+	ClientId  string
+	MaxSize   int64
+	MaxFile   int64
+	UsedSize  int64
+	UsedFile  int64
+	UpdatedAt time.Time
+}
+
+type UpdateQuotaParam struct {
+	ClientId string
+	MaxSize  int64
+	MaxFile  int64
+}
+
+type UpdateQuotaResult struct {
+	UpdatedAt time.Time
+}
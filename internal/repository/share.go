@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	PERMISSION_READ   = "read"
+	PERMISSION_DELETE = "delete"
+)
+
+type ShareRepository interface {
+	CreateShare(ctx context.Context, p CreateShareParam) (*CreateShareResult, error)
+	DeleteShare(ctx context.Context, p DeleteShareParam) (*DeleteShareResult, error)
+}
+
+type CreateShareParam struct {
+	FileId     string
+	ClientId   string
+	Permission string
+}
+
+type CreateShareResult struct {
+	CreatedAt time.Time
+}
+
+type DeleteShareParam struct {
+	FileId   string
+	ClientId string
+}
+
+type DeleteShareResult struct {
+	DeletedAt time.Time
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DELIVERY_PENDING   = "pending"
+	DELIVERY_DELIVERED = "delivered"
+	DELIVERY_DEAD      = "dead"
+	DELIVERY_CANCELED  = "canceled"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, p CreateSubscriptionParam) (*CreateSubscriptionResult, error)
+	ListSubscription(ctx context.Context, p ListSubscriptionParam) (*ListSubscriptionResult, error)
+	DeleteSubscription(ctx context.Context, p DeleteSubscriptionParam) (*DeleteSubscriptionResult, error)
+	CreateDelivery(ctx context.Context, p CreateDeliveryParam) (*CreateDeliveryResult, error)
+	ListDueDelivery(ctx context.Context, p ListDueDeliveryParam) (*ListDueDeliveryResult, error)
+	ClaimDelivery(ctx context.Context, p ClaimDeliveryParam) (*ClaimDeliveryResult, error)
+	UpdateDelivery(ctx context.Context, p UpdateDeliveryParam) (*UpdateDeliveryResult, error)
+	ListDelivery(ctx context.Context, p ListDeliveryParam) (*ListDeliveryResult, error)
+}
+
+type CreateSubscriptionParam struct {
+	Id         string
+	ClientId   string
+	Url        string
+	Secret     string
+	EventTypes []string
+}
+
+type CreateSubscriptionResult struct {
+	CreatedAt time.Time
+}
+
+type ListSubscriptionParam struct {
+	ClientId string
+}
+
+type ListSubscriptionResult struct {
+	Items []WebhookSubscription
+}
+
+type WebhookSubscription struct {
+	Id         string
+	ClientId   string
+	Url        string
+	Secret     string
+	EventTypes []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type DeleteSubscriptionParam struct {
+	Id       string
+	ClientId string
+}
+
+type DeleteSubscriptionResult struct {
+	DeletedAt time.Time
+}
+
+type CreateDeliveryParam struct {
+	Items []CreateDeliveryItem
+}
+
+type CreateDeliveryItem struct {
+	Id             string
+	SubscriptionId string
+	ClientId       string
+	EventId        string
+	EventType      string
+	Payload        string
+}
+
+type CreateDeliveryResult struct {
+	CreatedAt time.Time
+}
+
+type ListDueDeliveryParam struct {
+	DueAt time.Time
+	Limit int
+}
+
+type ListDueDeliveryResult struct {
+	Items []DueDelivery
+}
+
+type DueDelivery struct {
+	Id             string
+	SubscriptionId string
+	EventId        string
+	EventType      string
+	Payload        string
+	Attempt        int
+	NextAttemptAt  time.Time
+	Url            string
+	Secret         string
+}
+
+// the delivery is claimed by postponing the next attempt, so the other dispatcher skips it,
+// the claim is rejected when the delivery has been changed
+type ClaimDeliveryParam struct {
+	Id            string
+	NextAttemptAt time.Time
+	LeaseUntil    time.Time
+}
+
+type ClaimDeliveryResult struct {
+	ClaimedAt time.Time
+}
+
+type UpdateDeliveryParam struct {
+	Id            string
+	Status        string
+	Attempt       int
+	NextAttemptAt time.Time
+	ResponseCode  int
+	LastError     string
+}
+
+type UpdateDeliveryResult struct {
+	UpdatedAt time.Time
+}
+
+type ListDeliveryParam struct {
+	ClientId       string
+	SubscriptionId string
+	Status         string
+	AfterId        string
+	Limit          int
+}
+
+type ListDeliveryResult struct {
+	Items []WebhookDelivery
+}
+
+type WebhookDelivery struct {
+	Id             string
+	SubscriptionId string
+	ClientId       string
+	EventId        string
+	EventType      string
+	Status         string
+	Attempt        int
+	NextAttemptAt  time.Time
+	ResponseCode   int
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time
+}
@@ -7,15 +7,21 @@ import (
 	"time"
 
 	"github.com/go-seidon/local/internal/app"
+	"github.com/go-seidon/local/internal/auditing"
 	"github.com/go-seidon/local/internal/auth"
 	"github.com/go-seidon/local/internal/deleting"
 	"github.com/go-seidon/local/internal/encoding"
-	"github.com/go-seidon/local/internal/filesystem"
-	"github.com/go-seidon/local/internal/hashing"
+	"github.com/go-seidon/local/internal/encrypting"
 	"github.com/go-seidon/local/internal/healthcheck"
+	"github.com/go-seidon/local/internal/imaging"
+	"github.com/go-seidon/local/internal/limiting"
 	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/managing"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/quota"
 	"github.com/go-seidon/local/internal/retrieving"
 	"github.com/go-seidon/local/internal/serialization"
+	"github.com/go-seidon/local/internal/sharing"
 	"github.com/go-seidon/local/internal/text"
 	"github.com/go-seidon/local/internal/uploading"
 
@@ -28,6 +34,8 @@ type RestApp struct {
 	logger logging.Logger
 
 	healthService healthcheck.HealthCheck
+	dispatcher    notifying.Dispatcher
+	relay         notifying.Relay
 }
 
 func (a *RestApp) Run() error {
@@ -38,6 +46,16 @@ func (a *RestApp) Run() error {
 		return err
 	}
 
+	err = a.dispatcher.Start()
+	if err != nil {
+		return err
+	}
+
+	err = a.relay.Start()
+	if err != nil {
+		return err
+	}
+
 	a.logger.Infof("Listening on: %s", a.config.GetAddress())
 	err = a.server.ListenAndServe()
 	if err != http.ErrServerClosed {
@@ -48,7 +66,15 @@ func (a *RestApp) Run() error {
 
 func (a *RestApp) Stop() error {
 	a.logger.Infof("Stopping %s on: %s", a.config.GetAppName(), a.config.GetAddress())
-	return a.server.Shutdown(context.Background())
+	err := a.server.Shutdown(context.Background())
+	if err != nil {
+		return err
+	}
+	err = a.relay.Stop()
+	if err != nil {
+		return err
+	}
+	return a.dispatcher.Stop()
 }
 
 func NewRestApp(opts ...Option) (*RestApp, error) {
@@ -123,34 +149,280 @@ func NewRestApp(opts ...Option) (*RestApp, error) {
 		return nil, err
 	}
 
-	fileManager := filesystem.NewFileManager()
-	dirManager := filesystem.NewDirectoryManager()
+	storage, err := app.NewStorage(app.NewStorageParam{
+		Provider:      option.Config.StorageProvider,
+		S3Endpoint:    option.Config.S3Endpoint,
+		S3Region:      option.Config.S3Region,
+		S3Bucket:      option.Config.S3Bucket,
+		S3AccessKey:   option.Config.S3AccessKey,
+		S3SecretKey:   option.Config.S3SecretKey,
+		S3VirtualHost: option.Config.S3VirtualHost,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := app.NewKeyring(app.NewKeyringParam{
+		KeyId:     option.Config.EncryptionKeyId,
+		MasterKey: option.Config.EncryptionMasterKey,
+		Keyfile:   option.Config.EncryptionKeyfile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fileManager := storage.FileManager
+	dirManager := storage.DirManager
+	if keyring != nil {
+		fileManager, err = encrypting.NewEncryptedFileManager(encrypting.NewEncryptedFileManagerParam{
+			FileManager: storage.FileManager,
+			Keyring:     keyring,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// compression is applied before the encryption since encrypted data is not compressible
+	compression, err := app.NewCompression(app.NewCompressionParam{
+		FileManager: fileManager,
+		Codec:       option.Config.CompressionCodec,
+		Mimetypes:   option.Config.CompressionMimetype,
+		MinSize:     option.Config.CompressionMinSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	fileManager = compression.FileManager
 	identifier := text.NewKsuid()
 
+	webhookService, err := notifying.NewWebhook(notifying.NewWebhookParam{
+		WebhookRepo:         repo.WebhookRepo,
+		Identifier:          identifier,
+		Logger:              logger,
+		AllowPrivateNetwork: option.Config.WebhookAllowPrivateNetwork,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dispatcher := option.Dispatcher
+	if option.Dispatcher == nil {
+		webhookDispatcher, err := notifying.NewDispatcher(notifying.NewDispatcherParam{
+			WebhookRepo:         repo.WebhookRepo,
+			Logger:              logger,
+			Interval:            time.Duration(option.Config.WebhookDispatchInterval) * time.Second,
+			BatchSize:           option.Config.WebhookBatchSize,
+			MaxAttempts:         option.Config.WebhookMaxAttempts,
+			BackoffBase:         time.Duration(option.Config.WebhookBackoffBase) * time.Second,
+			BackoffMax:          time.Duration(option.Config.WebhookBackoffMax) * time.Second,
+			Timeout:             time.Duration(option.Config.WebhookTimeout) * time.Second,
+			AllowPrivateNetwork: option.Config.WebhookAllowPrivateNetwork,
+		})
+		if err != nil {
+			return nil, err
+		}
+		dispatcher = webhookDispatcher
+	}
+
+	serializer := serialization.NewJsonSerializer()
+
+	broker, err := notifying.NewBroker(notifying.NewBrokerParam{})
+	if err != nil {
+		return nil, err
+	}
+
+	eventStream, err := notifying.NewEventStream(notifying.NewEventStreamParam{
+		OutboxRepo: repo.OutboxRepo,
+		Interval:   time.Duration(option.Config.EventStreamInterval) * time.Second,
+		BatchSize:  option.Config.EventStreamBatchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	relay := option.Relay
+	if option.Relay == nil {
+		sinks, err := app.NewOutboxSinks(app.NewOutboxSinksParam{
+			Sinks:      option.Config.OutboxSinks,
+			Publisher:  webhookService,
+			Broker:     broker,
+			Serializer: serializer,
+		})
+		if err != nil {
+			return nil, err
+		}
+		outboxRelay, err := notifying.NewRelay(notifying.NewRelayParam{
+			OutboxRepo:  repo.OutboxRepo,
+			Sinks:       sinks,
+			Logger:      logger,
+			Interval:    time.Duration(option.Config.OutboxRelayInterval) * time.Second,
+			BatchSize:   option.Config.OutboxBatchSize,
+			BackoffBase: time.Duration(option.Config.OutboxBackoffBase) * time.Second,
+			BackoffMax:  time.Duration(option.Config.OutboxBackoffMax) * time.Second,
+			Retention:   time.Duration(option.Config.OutboxRetention) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+		relay = outboxRelay
+	}
+
 	deleteService, err := deleting.NewDeleter(deleting.NewDeleterParam{
-		FileRepo:    repo.FileRepo,
-		Logger:      logger,
-		FileManager: fileManager,
+		FileRepo:         repo.FileRepo,
+		Logger:           logger,
+		FileManager:      fileManager,
+		LegacyFileAccess: option.Config.AuthLegacyFileAccess,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	retrieveService, err := retrieving.NewRetriever(retrieving.NewRetrieverParam{
-		FileRepo:    repo.FileRepo,
-		Logger:      logger,
-		FileManager: fileManager,
+		FileRepo:         repo.FileRepo,
+		Logger:           logger,
+		FileManager:      fileManager,
+		LegacyFileAccess: option.Config.AuthLegacyFileAccess,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imageProcessor, err := imaging.NewImageProcessor(imaging.NewImageProcessorParam{
+		MaxPixels: option.Config.UploadMaxPixels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mimetypeLimits := []uploading.MimetypeLimit{}
+	for _, l := range option.Config.UploadPolicyMimetypeSize {
+		mimetypeLimits = append(mimetypeLimits, uploading.MimetypeLimit{
+			Mimetype: l.Mimetype,
+			MaxSize:  l.MaxSize,
+		})
+	}
+	clientLimits := []uploading.ClientLimit{}
+	for _, l := range option.Config.UploadPolicyClientSize {
+		clientLimits = append(clientLimits, uploading.ClientLimit{
+			ClientId: l.ClientId,
+			MaxSize:  l.MaxSize,
+		})
+	}
+	uploadPolicy, err := uploading.NewUploadPolicy(uploading.NewUploadPolicyParam{
+		AllowedMimetypes: option.Config.UploadPolicyAllowedMimetype,
+		MimetypeLimits:   mimetypeLimits,
+		ClientLimits:     clientLimits,
+		CheckExtension:   option.Config.UploadPolicyCheckExtension,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scanner, err := app.NewScanner(app.NewScannerParam{
+		Provider:      option.Config.ScanProvider,
+		ClamavNetwork: option.Config.ClamavNetwork,
+		ClamavAddress: option.Config.ClamavAddress,
+		ClamavTimeout: time.Duration(option.Config.ClamavTimeout) * time.Second,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	uploadService, err := uploading.NewUploader(uploading.NewUploaderParam{
-		FileRepo:    repo.FileRepo,
-		FileManager: fileManager,
-		Logger:      logger,
-		Identifier:  identifier,
-		DirManager:  dirManager,
+		FileRepo:        repo.FileRepo,
+		FileManager:     fileManager,
+		Logger:          logger,
+		Identifier:      identifier,
+		DirManager:      dirManager,
+		ImageProcessor:  imageProcessor,
+		ExifPolicy:      option.Config.UploadExifPolicy,
+		Policy:          uploadPolicy,
+		Scanner:         scanner,
+		ScanFailure:     option.Config.ScanFailure,
+		QuarantineDir:   option.Config.ScanQuarantineDirectory,
+		EncryptionKeyId: option.Config.EncryptionKeyId,
+		Compression:     compression.Policy,
+		QuotaRepo:       repo.QuotaRepo,
+		Visibility:      option.Config.UploadVisibility,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shareService, err := sharing.NewSharer(sharing.NewSharerParam{
+		FileRepo:  repo.FileRepo,
+		ShareRepo: repo.ShareRepo,
+		Logger:    logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	quotaService, err := quota.NewQuota(quota.NewQuotaParam{
+		QuotaRepo: repo.QuotaRepo,
+		Logger:    logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	locator, err := app.NewUploadLocation(app.NewUploadLocationParam{
+		Strategy: option.Config.UploadLocation,
+		Template: option.Config.UploadLocationTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := app.NewHasher(app.NewHasherParam{
+		Algorithm:         option.Config.HashAlgorithm,
+		BcryptCost:        option.Config.HashBcryptCost,
+		Argon2Memory:      option.Config.HashArgon2Memory,
+		Argon2Iterations:  option.Config.HashArgon2Iterations,
+		Argon2Parallelism: option.Config.HashArgon2Parallelism,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var credentialCache auth.CredentialCache
+	if option.Config.AuthCacheTTL > 0 {
+		credentialCache, err = auth.NewCredentialCache(auth.NewCredentialCacheParam{
+			MaxSize: option.Config.AuthCacheSize,
+			TTL:     time.Duration(option.Config.AuthCacheTTL) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var signatureAuth auth.SignatureAuth
+	if option.Config.AuthSignatureKey != "" {
+		signatureAuth, err = auth.NewSignatureAuth(auth.NewSignatureAuthParam{
+			OAuthRepo: repo.OAuthRepo,
+			Key:       []byte(option.Config.AuthSignatureKey),
+			Skew:      time.Duration(option.Config.AuthSignatureSkew) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clientManager, err := managing.NewClientManager(managing.NewClientManagerParam{
+		OAuthRepo:  repo.OAuthRepo,
+		Hasher:     hasher,
+		Identifier: identifier,
+		Cache:      credentialCache,
+		Signature:  signatureAuth,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditor, err := auditing.NewAuditor(auditing.NewAuditorParam{
+		AuditRepo:  repo.AuditRepo,
+		Identifier: identifier,
+		Logger:     logger,
 	})
 	if err != nil {
 		return nil, err
@@ -164,16 +436,22 @@ func NewRestApp(opts ...Option) (*RestApp, error) {
 		UploadFormSize: option.Config.UploadFormSize,
 		UploadDir:      option.Config.UploadDirectory,
 	}
-	locator := uploading.NewDailyRotate(uploading.NewDailyRotateParam{})
-	serializer := serialization.NewJsonSerializer()
 	encoder := encoding.NewBase64Encoder()
-	hasher := hashing.NewBcryptHasher()
+
+	ipResolver, err := NewIpResolver(NewIpResolverParam{
+		TrustedProxies: option.Config.TrustedProxies,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	router := mux.NewRouter()
 	generalRouter := router.NewRoute().Subrouter()
 	fileRouter := router.NewRoute().Subrouter()
+	streamRouter := router.NewRoute().Subrouter()
+	adminRouter := router.NewRoute().Subrouter()
 
-	router.Use(DefaultHeaderMiddleware)
+	router.Use(DefaultHeaderMiddleware, NewClientIpMiddleware(ipResolver), NewRequestIdMiddleware(identifier))
 	router.HandleFunc(
 		"/",
 		NewRootHandler(logger, serializer, raCfg),
@@ -182,18 +460,124 @@ func NewRestApp(opts ...Option) (*RestApp, error) {
 		"/health",
 		NewHealthCheckHandler(logger, serializer, healthService),
 	).Methods(http.MethodGet)
-	fileRouter.HandleFunc(
+	readScope := NewScopeMiddleware(serializer, auth.SCOPE_FILE_READ)
+	writeScope := NewScopeMiddleware(serializer, auth.SCOPE_FILE_WRITE)
+	deleteScope := NewScopeMiddleware(serializer, auth.SCOPE_FILE_DELETE)
+	uploadAudit := NewAuditMiddleware(logger, auditor, auditing.ACTION_UPLOAD)
+	retrieveAudit := NewAuditMiddleware(logger, auditor, auditing.ACTION_RETRIEVE)
+	deleteAudit := NewAuditMiddleware(logger, auditor, auditing.ACTION_DELETE)
+
+	fileRouter.Handle(
 		"/file/{id}",
-		NewDeleteFileHandler(logger, serializer, deleteService),
+		deleteAudit(deleteScope(NewDeleteFileHandler(logger, serializer, deleteService))),
 	).Methods(http.MethodDelete)
-	fileRouter.HandleFunc(
+	fileRouter.Handle(
 		"/file/{id}",
-		NewRetrieveFileHandler(logger, serializer, retrieveService),
+		retrieveAudit(readScope(NewRetrieveFileHandler(logger, serializer, retrieveService))),
 	).Methods(http.MethodGet)
-	fileRouter.HandleFunc(
+	fileRouter.Handle(
 		"/file",
-		NewUploadFileHandler(logger, serializer, uploadService, locator, raCfg),
+		uploadAudit(writeScope(NewUploadFileHandler(logger, serializer, uploadService, locator, raCfg))),
+	).Methods(http.MethodPost)
+
+	fileRouter.Handle(
+		"/file/{id}/share",
+		writeScope(NewShareFileHandler(logger, serializer, shareService)),
+	).Methods(http.MethodPost)
+	fileRouter.Handle(
+		"/file/{id}/share/{client_id}",
+		writeScope(NewUnshareFileHandler(logger, serializer, shareService)),
+	).Methods(http.MethodDelete)
+
+	fileRouter.Handle(
+		"/file/{id}/visibility",
+		writeScope(NewUpdateVisibilityHandler(logger, serializer, shareService)),
+	).Methods(http.MethodPut)
+
+	fileRouter.Handle(
+		"/usage",
+		readScope(NewRetrieveUsageHandler(logger, serializer, quotaService)),
+	).Methods(http.MethodGet)
+
+	fileRouter.Handle(
+		"/webhook",
+		writeScope(NewCreateWebhookHandler(logger, serializer, webhookService)),
+	).Methods(http.MethodPost)
+	fileRouter.Handle(
+		"/webhook",
+		readScope(NewListWebhookHandler(logger, serializer, webhookService)),
+	).Methods(http.MethodGet)
+	fileRouter.Handle(
+		"/webhook/delivery",
+		readScope(NewListWebhookDeliveryHandler(logger, serializer, webhookService)),
+	).Methods(http.MethodGet)
+	fileRouter.Handle(
+		"/webhook/{id}",
+		writeScope(NewDeleteWebhookHandler(logger, serializer, webhookService)),
+	).Methods(http.MethodDelete)
+
+	eventHeartbeat := time.Duration(option.Config.EventStreamHeartbeat) * time.Second
+	streamRouter.Handle(
+		"/events",
+		readScope(NewEventStreamHandler(logger, serializer, eventStream, eventHeartbeat)),
+	).Methods(http.MethodGet)
+
+	adminRouter.HandleFunc(
+		"/client",
+		NewCreateClientHandler(logger, serializer, clientManager),
 	).Methods(http.MethodPost)
+	adminRouter.HandleFunc(
+		"/client",
+		NewListClientHandler(logger, serializer, clientManager),
+	).Methods(http.MethodGet)
+	adminRouter.HandleFunc(
+		"/client/{client_id}/secret",
+		NewRotateSecretHandler(logger, serializer, clientManager),
+	).Methods(http.MethodPost)
+	adminRouter.HandleFunc(
+		"/client/{client_id}/secret",
+		NewListSecretHandler(logger, serializer, clientManager),
+	).Methods(http.MethodGet)
+	adminRouter.HandleFunc(
+		"/client/{client_id}/secret/{secret_id}",
+		NewRevokeSecretHandler(logger, serializer, clientManager),
+	).Methods(http.MethodDelete)
+	adminRouter.HandleFunc(
+		"/client/{client_id}/disable",
+		NewDisableClientHandler(logger, serializer, clientManager),
+	).Methods(http.MethodPost)
+	adminRouter.HandleFunc(
+		"/client/{client_id}/allowlist",
+		NewUpdateAllowlistHandler(logger, serializer, clientManager),
+	).Methods(http.MethodPut)
+	adminRouter.HandleFunc(
+		"/client/{client_id}/rate-limit",
+		NewUpdateRateLimitHandler(logger, serializer, clientManager),
+	).Methods(http.MethodPut)
+	adminRouter.HandleFunc(
+		"/client/{client_id}/quota",
+		NewUpdateQuotaHandler(logger, serializer, quotaService),
+	).Methods(http.MethodPut)
+	adminRouter.HandleFunc(
+		"/client/{client_id}",
+		NewDeleteClientHandler(logger, serializer, clientManager),
+	).Methods(http.MethodDelete)
+	adminRouter.HandleFunc(
+		"/audit-log",
+		NewListAuditLogHandler(logger, serializer, auditor),
+	).Methods(http.MethodGet)
+	if credentialCache != nil {
+		adminRouter.HandleFunc(
+			"/metric/credential-cache",
+			NewCredentialCacheStatsHandler(logger, serializer, credentialCache),
+		).Methods(http.MethodGet)
+	}
+
+	// public file is served without authentication, so the request is anonymous and is not audited
+	router.HandleFunc(
+		"/public/file/{id}",
+		NewRetrievePublicFileHandler(logger, serializer, retrieveService),
+	).Methods(http.MethodGet)
 
 	router.NotFoundHandler = NewNotFoundHandler(logger, serializer)
 	router.MethodNotAllowedHandler = NewMethodNotAllowedHandler(logger, serializer)
@@ -202,13 +586,74 @@ func NewRestApp(opts ...Option) (*RestApp, error) {
 		Encoder:   encoder,
 		Hasher:    hasher,
 		OAuthRepo: repo.OAuthRepo,
+		Cache:     credentialCache,
 	})
 	if err != nil {
 		return nil, err
 	}
-	basicAuthMiddleware := NewBasicAuthMiddleware(basicAuth, serializer)
-	generalRouter.Use(basicAuthMiddleware)
-	fileRouter.Use(basicAuthMiddleware)
+	lockout, err := auth.NewLockout(auth.NewLockoutParam{
+		ClientThreshold: option.Config.AuthLockoutClientThreshold,
+		IpThreshold:     option.Config.AuthLockoutIpThreshold,
+		BaseDuration:    time.Duration(option.Config.AuthLockoutBaseDuration) * time.Second,
+		MaxDuration:     time.Duration(option.Config.AuthLockoutMaxDuration) * time.Second,
+		ResetAfter:      time.Duration(option.Config.AuthLockoutResetAfter) * time.Second,
+		Logger:          logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	authSchemes := map[string]func(h http.Handler) http.Handler{
+		"Basic": NewBasicAuthMiddleware(basicAuth, serializer, lockout),
+	}
+
+	if signatureAuth != nil {
+		authSchemes[auth.SIGNATURE_SCHEME] = NewSignatureAuthMiddleware(signatureAuth, serializer, lockout)
+	}
+
+	if option.Config.OAuthTokenSecret != "" {
+		tokenAuth, err := auth.NewTokenAuth(auth.NewTokenAuthParam{
+			OAuthRepo:  repo.OAuthRepo,
+			Hasher:     hasher,
+			SigningKey: []byte(option.Config.OAuthTokenSecret),
+			Issuer:     option.Config.OAuthTokenIssuer,
+			TTL:        time.Duration(option.Config.OAuthTokenTTL) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		router.Handle(
+			"/oauth/token",
+			NewTokenAuditMiddleware(logger, auditor)(NewIssueTokenHandler(logger, serializer, tokenAuth, lockout)),
+		).Methods(http.MethodPost)
+		authSchemes["Bearer"] = NewBearerAuthMiddleware(tokenAuth, serializer)
+	}
+
+	rateLimitStore, err := limiting.NewMemoryStore(limiting.NewMemoryStoreParam{})
+	if err != nil {
+		return nil, err
+	}
+	limiter, err := limiting.NewLimiter(limiting.NewLimiterParam{
+		Store:       rateLimitStore,
+		OAuthRepo:   repo.OAuthRepo,
+		RequestRate: option.Config.RateLimitRequestRate,
+		ByteRate:    option.Config.RateLimitByteRate,
+		MaxInFlight: option.Config.RateLimitMaxInFlight,
+		MaxStream:   option.Config.RateLimitMaxStream,
+		BurstPeriod: time.Duration(option.Config.RateLimitBurstPeriod) * time.Second,
+		PolicyTTL:   time.Duration(option.Config.RateLimitPolicyTTL) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authMiddleware := NewAuthAuditMiddleware(logger, auditor, NewAuthSchemeMiddleware(serializer, authSchemes))
+	allowlistMiddleware := NewAuthAuditMiddleware(logger, auditor, NewAllowlistMiddleware(logger, serializer))
+	rateLimitMiddleware := NewRateLimitMiddleware(logger, serializer, limiter)
+	generalRouter.Use(authMiddleware, allowlistMiddleware, rateLimitMiddleware)
+	fileRouter.Use(authMiddleware, allowlistMiddleware, rateLimitMiddleware)
+	streamRouter.Use(authMiddleware, allowlistMiddleware, NewStreamLimitMiddleware(logger, serializer, limiter))
+	adminRouter.Use(authMiddleware, allowlistMiddleware, rateLimitMiddleware, NewScopeMiddleware(serializer, auth.SCOPE_ADMIN))
 
 	server := option.Server
 	if option.Server == nil {
@@ -223,6 +668,8 @@ func NewRestApp(opts ...Option) (*RestApp, error) {
 		config:        raCfg,
 		logger:        logger,
 		healthService: healthService,
+		dispatcher:    dispatcher,
+		relay:         relay,
 	}
 	return app, nil
 }
@@ -131,6 +131,8 @@ var _ = Describe("Response Package", func() {
 			logger        *mock.MockLogger
 			server        *mock.MockServer
 			healthService *mock.MockHealthCheck
+			dispatcher    *mock.MockDispatcher
+			relay         *mock.MockRelay
 		)
 
 		BeforeEach(func() {
@@ -139,6 +141,8 @@ var _ = Describe("Response Package", func() {
 			logger = mock.NewMockLogger(ctrl)
 			healthService = mock.NewMockHealthCheck(ctrl)
 			server = mock.NewMockServer(ctrl)
+			dispatcher = mock.NewMockDispatcher(ctrl)
+			relay = mock.NewMockRelay(ctrl)
 			ra, _ = rest_app.NewRestApp(
 				rest_app.WithConfig(app.Config{
 					AppName:     "mock-name",
@@ -150,6 +154,8 @@ var _ = Describe("Response Package", func() {
 				rest_app.WithLogger(logger),
 				rest_app.WithServer(server),
 				rest_app.WithService(healthService),
+				rest_app.WithDispatcher(dispatcher),
+				rest_app.WithRelay(relay),
 			)
 		})
 
@@ -172,6 +178,62 @@ var _ = Describe("Response Package", func() {
 			})
 		})
 
+		When("failed start dispatcher", func() {
+			It("should return error", func() {
+				logger.
+					EXPECT().
+					Infof(gomock.Eq("Running %s:%s"), gomock.Eq("mock-name"), gomock.Eq("mock-version")).
+					Times(1)
+
+				healthService.
+					EXPECT().
+					Start().
+					Return(nil).
+					Times(1)
+
+				dispatcher.
+					EXPECT().
+					Start().
+					Return(fmt.Errorf("dispatcher is already started")).
+					Times(1)
+
+				err := ra.Run()
+
+				Expect(err).To(Equal(fmt.Errorf("dispatcher is already started")))
+			})
+		})
+
+		When("failed start relay", func() {
+			It("should return error", func() {
+				logger.
+					EXPECT().
+					Infof(gomock.Eq("Running %s:%s"), gomock.Eq("mock-name"), gomock.Eq("mock-version")).
+					Times(1)
+
+				healthService.
+					EXPECT().
+					Start().
+					Return(nil).
+					Times(1)
+
+				dispatcher.
+					EXPECT().
+					Start().
+					Return(nil).
+					Times(1)
+
+				relay.
+					EXPECT().
+					Start().
+					Return(fmt.Errorf("relay is already started")).
+					Times(1)
+
+				err := ra.Run()
+
+				Expect(err).To(Equal(fmt.Errorf("relay is already started")))
+			})
+		})
+
 		When("failed listen and serve", func() {
 			It("should return error", func() {
 				logger.
@@ -185,6 +247,18 @@ var _ = Describe("Response Package", func() {
 					Return(nil).
 					Times(1)
 
+				dispatcher.
+					EXPECT().
+					Start().
+					Return(nil).
+					Times(1)
+
+				relay.
+					EXPECT().
+					Start().
+					Return(nil).
+					Times(1)
+
 				logger.
 					EXPECT().
 					Infof(gomock.Eq("Listening on: %s"), gomock.Eq("localhost:4949")).
@@ -215,6 +289,18 @@ var _ = Describe("Response Package", func() {
 					Return(nil).
 					Times(1)
 
+				dispatcher.
+					EXPECT().
+					Start().
+					Return(nil).
+					Times(1)
+
+				relay.
+					EXPECT().
+					Start().
+					Return(nil).
+					Times(1)
+
 				logger.
 					EXPECT().
 					Infof(gomock.Eq("Listening on: %s"), gomock.Eq("localhost:4949")).
@@ -240,6 +326,8 @@ var _ = Describe("Response Package", func() {
 			logger        *mock.MockLogger
 			server        *mock.MockServer
 			healthService *mock.MockHealthCheck
+			dispatcher    *mock.MockDispatcher
+			relay         *mock.MockRelay
 		)
 
 		BeforeEach(func() {
@@ -248,6 +336,8 @@ var _ = Describe("Response Package", func() {
 			logger = mock.NewMockLogger(ctrl)
 			healthService = mock.NewMockHealthCheck(ctrl)
 			server = mock.NewMockServer(ctrl)
+			dispatcher = mock.NewMockDispatcher(ctrl)
+			relay = mock.NewMockRelay(ctrl)
 			ra, _ = rest_app.NewRestApp(
 				rest_app.WithConfig(app.Config{
 					AppName:     "mock-name",
@@ -259,6 +349,8 @@ var _ = Describe("Response Package", func() {
 				rest_app.WithLogger(logger),
 				rest_app.WithServer(server),
 				rest_app.WithService(healthService),
+				rest_app.WithDispatcher(dispatcher),
+				rest_app.WithRelay(relay),
 			)
 		})
 
@@ -281,6 +373,62 @@ var _ = Describe("Response Package", func() {
 			})
 		})
 
+		When("failed stop relay", func() {
+			It("should return error", func() {
+				logger.
+					EXPECT().
+					Infof(gomock.Eq("Stopping %s on: %s"), gomock.Eq("mock-name"), gomock.Eq("localhost:4949")).
+					Times(1)
+
+				server.
+					EXPECT().
+					Shutdown(gomock.Eq(context.Background())).
+					Return(nil).
+					Times(1)
+
+				relay.
+					EXPECT().
+					Stop().
+					Return(fmt.Errorf("relay error")).
+					Times(1)
+
+				err := ra.Stop()
+
+				Expect(err).To(Equal(fmt.Errorf("relay error")))
+			})
+		})
+
+		When("failed stop dispatcher", func() {
+			It("should return error", func() {
+				logger.
+					EXPECT().
+					Infof(gomock.Eq("Stopping %s on: %s"), gomock.Eq("mock-name"), gomock.Eq("localhost:4949")).
+					Times(1)
+
+				server.
+					EXPECT().
+					Shutdown(gomock.Eq(context.Background())).
+					Return(nil).
+					Times(1)
+
+				relay.
+					EXPECT().
+					Stop().
+					Return(nil).
+					Times(1)
+
+				dispatcher.
+					EXPECT().
+					Stop().
+					Return(fmt.Errorf("dispatcher error")).
+					Times(1)
+
+				err := ra.Stop()
+
+				Expect(err).To(Equal(fmt.Errorf("dispatcher error")))
+			})
+		})
+
 		When("success stop app", func() {
 			It("should return result", func() {
 				logger.
@@ -294,6 +442,18 @@ var _ = Describe("Response Package", func() {
 					Return(nil).
 					Times(1)
 
+				relay.
+					EXPECT().
+					Stop().
+					Return(nil).
+					Times(1)
+
+				dispatcher.
+					EXPECT().
+					Stop().
+					Return(nil).
+					Times(1)
+
 				err := ra.Stop()
 
 				Expect(err).To(BeNil())
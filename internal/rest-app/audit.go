@@ -0,0 +1,231 @@
+package rest_app
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-seidon/local/internal/auditing"
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/text"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	HEADER_REQUEST_ID = "X-Request-Id"
+	MAX_REQUEST_ID    = 128
+)
+
+type requestIdContextKey struct{}
+
+func NewRequestIdMiddleware(i text.Identifier) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestId := r.Header.Get(HEADER_REQUEST_ID)
+			if !isValidRequestId(requestId) {
+				id, err := i.GenerateId()
+				if err != nil {
+					h.ServeHTTP(w, r)
+					return
+				}
+				requestId = id
+			}
+
+			w.Header().Set(HEADER_REQUEST_ID, requestId)
+			ctx := context.WithValue(r.Context(), requestIdContextKey{}, requestId)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func RequestId(r *http.Request) string {
+	requestId, _ := r.Context().Value(requestIdContextKey{}).(string)
+	return requestId
+}
+
+func isValidRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > MAX_REQUEST_ID {
+		return false
+	}
+	for _, c := range requestId {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum && c != '-' && c != '_' && c != '.' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+type auditEntry struct {
+	fileId string
+}
+
+type auditEntryContextKey struct{}
+
+func SetAuditFileId(r *http.Request, fileId string) {
+	entry, ok := r.Context().Value(auditEntryContextKey{}).(*auditEntry)
+	if ok {
+		entry.fileId = fileId
+	}
+}
+
+func NewAuditMiddleware(log logging.Logger, a auditing.Auditor, action string) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			entry := &auditEntry{
+				fileId: mux.Vars(r)["id"],
+			}
+			ctx := context.WithValue(r.Context(), auditEntryContextKey{}, entry)
+			sw := &statusWriter{ResponseWriter: w}
+			h.ServeHTTP(sw, r.WithContext(ctx))
+
+			clientId, _ := auth.ClientFromContext(r.Context())
+			p := auditing.RecordParam{
+				ClientId:  clientId,
+				Action:    action,
+				FileId:    entry.fileId,
+				IpAddress: RemoteIpAddress(r),
+				RequestId: RequestId(r),
+				Outcome:   auditOutcome(sw.Status()),
+			}
+			if p.Outcome != auditing.OUTCOME_SUCCESS {
+				p.Reason = http.StatusText(sw.Status())
+			}
+			_, err := a.Record(r.Context(), p)
+			if err != nil {
+				log.Errorf("Failed record audit log: %s", err.Error())
+			}
+		})
+	}
+}
+
+type authPassedContextKey struct{}
+
+func NewAuthAuditMiddleware(log logging.Logger, a auditing.Auditor, m func(h http.Handler) http.Handler) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		handler := m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			passed, ok := r.Context().Value(authPassedContextKey{}).(*bool)
+			if ok {
+				*passed = true
+			}
+			h.ServeHTTP(w, r)
+		}))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			passed := false
+			ctx := context.WithValue(r.Context(), authPassedContextKey{}, &passed)
+			sw := &statusWriter{ResponseWriter: w}
+			handler.ServeHTTP(sw, r.WithContext(ctx))
+			if passed {
+				return
+			}
+
+			clientId, ok := presentedClientId(r)
+			if !ok {
+				return
+			}
+			_, err := a.Record(r.Context(), auditing.RecordParam{
+				ClientId:  clientId,
+				Action:    auditing.ACTION_AUTH_FAILURE,
+				IpAddress: RemoteIpAddress(r),
+				RequestId: RequestId(r),
+				Outcome:   auditing.OUTCOME_DENIED,
+				Reason:    http.StatusText(sw.Status()),
+			})
+			if err != nil {
+				log.Errorf("Failed record audit log: %s", err.Error())
+			}
+		})
+	}
+}
+
+// the token is not issued through the auth middleware, so its failure is audited once the handler is returned
+func NewTokenAuditMiddleware(log logging.Logger, a auditing.Auditor) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := &statusWriter{ResponseWriter: w}
+			h.ServeHTTP(sw, r)
+			if sw.Status() < http.StatusBadRequest {
+				return
+			}
+
+			clientId, ok := presentedClientId(r)
+			if !ok {
+				return
+			}
+			_, err := a.Record(r.Context(), auditing.RecordParam{
+				ClientId:  clientId,
+				Action:    auditing.ACTION_AUTH_FAILURE,
+				IpAddress: RemoteIpAddress(r),
+				RequestId: RequestId(r),
+				Outcome:   auditOutcome(sw.Status()),
+				Reason:    http.StatusText(sw.Status()),
+			})
+			if err != nil {
+				log.Errorf("Failed record audit log: %s", err.Error())
+			}
+		})
+	}
+}
+
+// the request without any credential is anonymous and is never audited, so the unauthenticated caller
+// can not flood the audit log, the claimed client of the rejected credential is recorded as is
+func presentedClientId(r *http.Request) (string, bool) {
+	if clientId, ok := auth.ClientFromContext(r.Context()); ok {
+		return clientId, true
+	}
+	if clientId, _, ok := r.BasicAuth(); ok {
+		return clientId, true
+	}
+	if r.Header.Get("Authorization") != "" {
+		return "", true
+	}
+	// the form is only parsed by the token handler
+	if clientId := r.PostForm.Get("client_id"); clientId != "" {
+		return clientId, true
+	}
+	return "", false
+}
+
+func auditOutcome(status int) string {
+	if status < http.StatusBadRequest {
+		return auditing.OUTCOME_SUCCESS
+	}
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return auditing.OUTCOME_DENIED
+	}
+	return auditing.OUTCOME_FAILURE
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
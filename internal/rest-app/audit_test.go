@@ -0,0 +1,517 @@
+package rest_app_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/go-seidon/local/internal/auditing"
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/mock"
+	rest_app "github.com/go-seidon/local/internal/rest-app"
+	"github.com/go-seidon/local/internal/serialization"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit Package", func() {
+
+	Context("NewRequestIdMiddleware", Label("unit"), func() {
+		var (
+			identifier *mock.MockIdentifier
+			requestId  string
+			m          http.Handler
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			identifier = mock.NewMockIdentifier(ctrl)
+			requestId = ""
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestId = rest_app.RequestId(r)
+			})
+			m = rest_app.NewRequestIdMiddleware(identifier)(handler)
+		})
+
+		When("request id is not specified", func() {
+			It("should generate request id", func() {
+				identifier.EXPECT().GenerateId().Return("generated-id", nil).Times(1)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, r)
+
+				Expect(requestId).To(Equal("generated-id"))
+				Expect(w.Header().Get("X-Request-Id")).To(Equal("generated-id"))
+			})
+		})
+
+		When("request id is valid", func() {
+			It("should keep request id", func() {
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				r.Header.Set("X-Request-Id", "trace-01:abc_def.1")
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, r)
+
+				Expect(requestId).To(Equal("trace-01:abc_def.1"))
+				Expect(w.Header().Get("X-Request-Id")).To(Equal("trace-01:abc_def.1"))
+			})
+		})
+
+		When("request id contains invalid character", func() {
+			It("should generate request id", func() {
+				identifier.EXPECT().GenerateId().Return("generated-id", nil).Times(1)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				r.Header.Set("X-Request-Id", "trace id\r\n")
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, r)
+
+				Expect(requestId).To(Equal("generated-id"))
+			})
+		})
+
+		When("request id is too long", func() {
+			It("should generate request id", func() {
+				identifier.EXPECT().GenerateId().Return("generated-id", nil).Times(1)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				r.Header.Set("X-Request-Id", strings.Repeat("a", 129))
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, r)
+
+				Expect(requestId).To(Equal("generated-id"))
+			})
+		})
+
+		When("failed generate request id", func() {
+			It("should call the handler without request id", func() {
+				identifier.EXPECT().GenerateId().Return("", fmt.Errorf("id error")).Times(1)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, r)
+
+				Expect(requestId).To(Equal(""))
+				Expect(w.Header().Get("X-Request-Id")).To(Equal(""))
+			})
+		})
+	})
+
+	Context("NewAuditMiddleware", Label("unit"), func() {
+		var (
+			log         *mock.MockLogger
+			auditor     *mock.MockAuditor
+			status      int
+			fileId      string
+			req         *http.Request
+			recordParam auditing.RecordParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			auditor = mock.NewMockAuditor(ctrl)
+			status = http.StatusOK
+			fileId = ""
+
+			req = httptest.NewRequest(http.MethodDelete, "/file/mock-file-id", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "mock-file-id"})
+			req = req.WithContext(auth.NewClientContext(req.Context(), "mock-client-id"))
+			recordParam = auditing.RecordParam{
+				ClientId:  "mock-client-id",
+				Action:    auditing.ACTION_DELETE,
+				FileId:    "mock-file-id",
+				IpAddress: "192.0.2.1",
+				Outcome:   auditing.OUTCOME_SUCCESS,
+			}
+		})
+
+		serve := func(action string) *httptest.ResponseRecorder {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if fileId != "" {
+					rest_app.SetAuditFileId(r, fileId)
+				}
+				w.WriteHeader(status)
+				w.Write([]byte("{}"))
+			})
+			m := rest_app.NewAuditMiddleware(log, auditor, action)(handler)
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, req)
+			return w
+		}
+
+		When("request is success", func() {
+			It("should record success outcome", func() {
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				w := serve(auditing.ACTION_DELETE)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("request is forbidden", func() {
+			It("should record denied outcome", func() {
+				status = http.StatusForbidden
+				recordParam.Outcome = auditing.OUTCOME_DENIED
+				recordParam.Reason = "Forbidden"
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				w := serve(auditing.ACTION_DELETE)
+
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("request is failed", func() {
+			It("should record failure outcome", func() {
+				status = http.StatusNotFound
+				recordParam.Outcome = auditing.OUTCOME_FAILURE
+				recordParam.Reason = "Not Found"
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				w := serve(auditing.ACTION_DELETE)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("file id is set by the handler", func() {
+			It("should record the file id", func() {
+				req = httptest.NewRequest(http.MethodPost, "/file", nil)
+				req = req.WithContext(auth.NewClientContext(req.Context(), "mock-client-id"))
+				fileId = "uploaded-file-id"
+				recordParam.Action = auditing.ACTION_UPLOAD
+				recordParam.FileId = "uploaded-file-id"
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				w := serve(auditing.ACTION_UPLOAD)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("failed record audit log", func() {
+			It("should not fail the request", func() {
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed record audit log: %s"), gomock.Eq("db error")).
+					Times(1)
+
+				w := serve(auditing.ACTION_DELETE)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Body.String()).To(Equal("{}"))
+			})
+		})
+	})
+
+	Context("NewAuthAuditMiddleware", Label("unit"), func() {
+		var (
+			log         *mock.MockLogger
+			s           serialization.Serializer
+			auditor     *mock.MockAuditor
+			handler     *mock.MockHandler
+			recordParam auditing.RecordParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			s = serialization.NewJsonSerializer()
+			auditor = mock.NewMockAuditor(ctrl)
+			handler = mock.NewMockHandler(ctrl)
+			recordParam = auditing.RecordParam{
+				Action:    auditing.ACTION_AUTH_FAILURE,
+				IpAddress: "192.0.2.1",
+				Outcome:   auditing.OUTCOME_DENIED,
+				Reason:    "Unauthorized",
+			}
+		})
+
+		When("request is authenticated", func() {
+			It("should not record audit log", func() {
+				passing := func(h http.Handler) http.Handler {
+					return h
+				}
+				m := rest_app.NewAuthAuditMiddleware(log, auditor, passing)(handler)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+			})
+		})
+
+		When("credential is rejected", func() {
+			It("should record claimed client as auth failure", func() {
+				schemes := map[string]func(h http.Handler) http.Handler{}
+				m := rest_app.NewAuthAuditMiddleware(log, auditor, rest_app.NewAuthSchemeMiddleware(s, schemes))(handler)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				r.SetBasicAuth("claimed-client-id", "secret")
+				w := httptest.NewRecorder()
+				recordParam.ClientId = "claimed-client-id"
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("address is not allowed", func() {
+			It("should record authenticated client as auth failure", func() {
+				log.EXPECT().WithFields(gomock.Any()).Return(log).Times(1)
+				log.EXPECT().Warn(gomock.Any()).Times(1)
+				m := rest_app.NewAuthAuditMiddleware(log, auditor, rest_app.NewAllowlistMiddleware(log, s))(handler)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				ctx := auth.NewClientContext(r.Context(), "mock-client-id")
+				ctx = auth.NewAllowlistContext(ctx, []string{"10.0.0.0/8"})
+				r = r.WithContext(ctx)
+				w := httptest.NewRecorder()
+				recordParam.ClientId = "mock-client-id"
+				recordParam.Reason = "Forbidden"
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("request has no credential", func() {
+			It("should not record audit log", func() {
+				schemes := map[string]func(h http.Handler) http.Handler{}
+				m := rest_app.NewAuthAuditMiddleware(log, auditor, rest_app.NewAuthSchemeMiddleware(s, schemes))(handler)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("credential has no claimed client", func() {
+			It("should record anonymous auth failure", func() {
+				schemes := map[string]func(h http.Handler) http.Handler{}
+				m := rest_app.NewAuthAuditMiddleware(log, auditor, rest_app.NewAuthSchemeMiddleware(s, schemes))(handler)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				r.Header.Set("Authorization", "Bearer invalid-token")
+				w := httptest.NewRecorder()
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("failed record audit log", func() {
+			It("should return the rejected response", func() {
+				schemes := map[string]func(h http.Handler) http.Handler{}
+				m := rest_app.NewAuthAuditMiddleware(log, auditor, rest_app.NewAuthSchemeMiddleware(s, schemes))(handler)
+				r := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				r.Header.Set("Authorization", "Bearer invalid-token")
+				w := httptest.NewRecorder()
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed record audit log: %s"), gomock.Eq("db error")).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(bytes.TrimSpace(w.Body.Bytes()), &resBody)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+				Expect(resBody.Code).To(Equal("UNAUTHORIZED"))
+			})
+		})
+	})
+
+	Context("NewTokenAuditMiddleware", Label("unit"), func() {
+		var (
+			log         *mock.MockLogger
+			auditor     *mock.MockAuditor
+			handler     *mock.MockHandler
+			m           http.Handler
+			recordParam auditing.RecordParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			auditor = mock.NewMockAuditor(ctrl)
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewTokenAuditMiddleware(log, auditor)(handler)
+			recordParam = auditing.RecordParam{
+				ClientId:  "claimed-client-id",
+				Action:    auditing.ACTION_AUTH_FAILURE,
+				IpAddress: "192.0.2.1",
+				Outcome:   auditing.OUTCOME_DENIED,
+				Reason:    "Unauthorized",
+			}
+		})
+
+		respond := func(status int) func(w http.ResponseWriter, r *http.Request) {
+			return func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				w.WriteHeader(status)
+			}
+		}
+
+		When("token is issued", func() {
+			It("should not record audit log", func() {
+				r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader("grant_type=client_credentials"))
+				r.SetBasicAuth("claimed-client-id", "secret")
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(respond(http.StatusOK)).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("basic credential is rejected", func() {
+			It("should record claimed client as auth failure", func() {
+				r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader("grant_type=client_credentials"))
+				r.SetBasicAuth("claimed-client-id", "secret")
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(respond(http.StatusUnauthorized)).
+					Times(1)
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("form credential is locked out", func() {
+			It("should record claimed client as auth failure", func() {
+				r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader("grant_type=client_credentials&client_id=claimed-client-id&client_secret=secret"))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(respond(http.StatusTooManyRequests)).
+					Times(1)
+				recordParam.Outcome = auditing.OUTCOME_FAILURE
+				recordParam.Reason = "Too Many Requests"
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Eq(recordParam)).
+					Return(&auditing.RecordResult{}, nil).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+			})
+		})
+
+		When("request has no credential", func() {
+			It("should not record audit log", func() {
+				r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader("grant_type=client_credentials"))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(respond(http.StatusUnauthorized)).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("failed record audit log", func() {
+			It("should write log", func() {
+				r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader("grant_type=client_credentials"))
+				r.SetBasicAuth("claimed-client-id", "secret")
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(respond(http.StatusUnauthorized)).
+					Times(1)
+				auditor.
+					EXPECT().
+					Record(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed record audit log: %s"), gomock.Eq("db error")).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+})
@@ -6,17 +6,34 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-seidon/local/internal/auditing"
+	"github.com/go-seidon/local/internal/auth"
 	"github.com/go-seidon/local/internal/deleting"
 	"github.com/go-seidon/local/internal/healthcheck"
 	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/managing"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/quota"
 	"github.com/go-seidon/local/internal/retrieving"
 	"github.com/go-seidon/local/internal/serialization"
+	"github.com/go-seidon/local/internal/sharing"
 	"github.com/go-seidon/local/internal/uploading"
 	"github.com/gorilla/mux"
 )
 
+const (
+	// public file is cached for a year, the id of the file never changes
+	// but the cached copy may still be served after the file is made private
+	PUBLIC_CACHE_CONTROL = "public, max-age=31536000"
+
+	HEADER_LAST_EVENT_ID    = "Last-Event-ID"
+	DEFAULT_EVENT_HEARTBEAT = 15 * time.Second
+)
+
 func NewNotFoundHandler(log logging.Logger, s serialization.Serializer) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		log.Debug("In function: NotFoundHandler")
@@ -132,9 +149,12 @@ func NewDeleteFileHandler(log logging.Logger, s serialization.Serializer, delete
 
 		vars := mux.Vars(req)
 
+		clientId, _ := auth.ClientFromContext(req.Context())
+
 		ctx := context.Background()
 		r, err := deleter.DeleteFile(ctx, deleting.DeleteFileParam{
-			FileId: vars["id"],
+			FileId:   vars["id"],
+			ClientId: clientId,
 		})
 		if err == nil {
 
@@ -162,6 +182,16 @@ func NewDeleteFileHandler(log logging.Logger, s serialization.Serializer, delete
 			return
 		}
 
+		if errors.Is(err, deleting.ErrorForbidden) {
+			Response(
+				WithWriterSerializer(w, s),
+				WithHttpCode(http.StatusForbidden),
+				WithCode(CODE_FORBIDDEN),
+				WithMessage(err.Error()),
+			)
+			return
+		}
+
 		Response(
 			WithWriterSerializer(w, s),
 			WithCode(CODE_ERROR),
@@ -177,52 +207,98 @@ func NewRetrieveFileHandler(log logging.Logger, s serialization.Serializer, retr
 		defer log.Debug("Returning function: RetrieveFileHandler")
 
 		vars := mux.Vars(req)
+		clientId, _ := auth.ClientFromContext(req.Context())
 
-		ctx := context.Background()
-		r, err := retriever.RetrieveFile(ctx, retrieving.RetrieveFileParam{
-			FileId: vars["id"],
-		})
-		if err == nil {
+		writeRetrievedFile(w, req, s, retriever, retrieving.RetrieveFileParam{
+			FileId:         vars["id"],
+			ClientId:       clientId,
+			AcceptedCodecs: parseAcceptEncoding(req.Header.Get("Accept-Encoding")),
+		}, "")
+	}
+}
 
-			defer r.Data.Close()
-			data, err := io.ReadAll(r.Data)
-			if err != nil {
-				Response(
-					WithWriterSerializer(w, s),
-					WithCode(CODE_ERROR),
-					WithMessage(err.Error()),
-					WithHttpCode(http.StatusBadRequest),
-				)
-				return
-			}
+func NewRetrievePublicFileHandler(log logging.Logger, s serialization.Serializer, retriever retrieving.Retriever) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: RetrievePublicFileHandler")
+		defer log.Debug("Returning function: RetrievePublicFileHandler")
 
-			if r.MimeType != "" {
-				w.Header().Set("Content-Type", r.MimeType)
-			} else {
-				w.Header().Del("Content-Type")
-			}
+		vars := mux.Vars(req)
 
-			w.Write(data)
-			return
-		}
+		writeRetrievedFile(w, req, s, retriever, retrieving.RetrieveFileParam{
+			FileId:         vars["id"],
+			AcceptedCodecs: parseAcceptEncoding(req.Header.Get("Accept-Encoding")),
+			PublicOnly:     true,
+		}, PUBLIC_CACHE_CONTROL)
+	}
+}
 
-		if errors.Is(err, retrieving.ErrorResourceNotFound) {
+// the file is streamed and the range request is served from the seekable file,
+// the range of the compressed file applies to the compressed content
+func writeRetrievedFile(w http.ResponseWriter, req *http.Request, s serialization.Serializer, retriever retrieving.Retriever, p retrieving.RetrieveFileParam, cacheControl string) {
+	ctx := context.Background()
+	r, err := retriever.RetrieveFile(ctx, p)
+	if err == nil {
+
+		defer r.Data.Close()
+		// the file is checked before the header is written, so the failure is still reported as json
+		_, err := r.Data.Seek(0, io.SeekEnd)
+		if err == nil {
+			_, err = r.Data.Seek(0, io.SeekStart)
+		}
+		if err != nil {
 			Response(
 				WithWriterSerializer(w, s),
-				WithHttpCode(http.StatusNotFound),
-				WithCode(CODE_NOT_FOUND),
+				WithCode(CODE_ERROR),
 				WithMessage(err.Error()),
+				WithHttpCode(http.StatusBadRequest),
 			)
 			return
 		}
 
+		header := w.Header()
+		if r.MimeType != "" {
+			header.Set("Content-Type", r.MimeType)
+		} else {
+			header.Del("Content-Type")
+		}
+		if r.Codec != "" {
+			header.Set("Content-Encoding", r.Codec)
+			header.Set("Vary", "Accept-Encoding")
+		}
+		if cacheControl != "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+
+		http.ServeContent(w, req, "", time.Time{}, r.Data)
+		return
+	}
+
+	if errors.Is(err, retrieving.ErrorResourceNotFound) {
 		Response(
 			WithWriterSerializer(w, s),
-			WithCode(CODE_ERROR),
+			WithHttpCode(http.StatusNotFound),
+			WithCode(CODE_NOT_FOUND),
+			WithMessage(err.Error()),
+		)
+		return
+	}
+
+	if errors.Is(err, retrieving.ErrorForbidden) {
+		Response(
+			WithWriterSerializer(w, s),
+			WithHttpCode(http.StatusForbidden),
+			WithCode(CODE_FORBIDDEN),
 			WithMessage(err.Error()),
-			WithHttpCode(http.StatusBadRequest),
 		)
+		return
 	}
+
+	Response(
+		WithWriterSerializer(w, s),
+		WithCode(CODE_ERROR),
+		WithMessage(err.Error()),
+		WithHttpCode(http.StatusBadRequest),
+	)
 }
 
 func NewUploadFileHandler(log logging.Logger, s serialization.Serializer, uploader uploading.Uploader, locator uploading.UploadLocation, config *RestAppConfig) http.HandlerFunc {
@@ -234,6 +310,21 @@ func NewUploadFileHandler(log logging.Logger, s serialization.Serializer, upload
 		req.Body = http.MaxBytesReader(w, req.Body, config.UploadFormSize+1024)
 
 		file, fileHeader, err := req.FormFile("file")
+		if err == nil {
+			defer file.Close()
+			// the multipart reader stops at the closing boundary, the rest of the body is drained
+			// so the mismatch of the signed body hash is surfaced before the file is uploaded
+			_, err = io.Copy(io.Discard, req.Body)
+		}
+		if errors.Is(err, auth.ErrorBodyHashMismatch) {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_UNAUTHORIZED),
+				WithMessage(auth.ErrorBodyHashMismatch.Error()),
+				WithHttpCode(http.StatusUnauthorized),
+			)
+			return
+		}
 		if err != nil {
 			Response(
 				WithWriterSerializer(w, s),
@@ -243,7 +334,6 @@ func NewUploadFileHandler(log logging.Logger, s serialization.Serializer, upload
 			)
 			return
 		}
-		defer file.Close()
 
 		fileInfo, err := ParseMultipartFile(file, fileHeader)
 		if err != nil {
@@ -256,19 +346,67 @@ func NewUploadFileHandler(log logging.Logger, s serialization.Serializer, upload
 			return
 		}
 
-		uploadDir := fmt.Sprintf("%s/%s", config.UploadDir, locator.GetLocation())
+		clientId, _ := auth.ClientFromContext(req.Context())
 
-		ctx := context.Background()
+		ctx := uploading.NewLocationContext(context.Background(), locator)
 		uploadRes, err := uploader.UploadFile(ctx,
 			uploading.WithReader(file),
-			uploading.WithDirectory(uploadDir),
+			uploading.WithDirectory(config.UploadDir),
 			uploading.WithFileInfo(
 				fileInfo.Name,
 				fileInfo.Mimetype,
 				fileInfo.Extension,
 				fileInfo.Size,
 			),
+			uploading.WithClient(clientId),
+			uploading.WithVisibility(req.FormValue("visibility")),
 		)
+		if errors.Is(err, uploading.ErrorFileTooLarge) {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_TOO_LARGE),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusRequestEntityTooLarge),
+			)
+			return
+		}
+		if errors.Is(err, uploading.ErrorQuotaExceeded) {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_QUOTA_EXCEEDED),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusInsufficientStorage),
+			)
+			return
+		}
+		if errors.Is(err, uploading.ErrorMimetypeNotAllowed) ||
+			errors.Is(err, uploading.ErrorExtensionMismatch) {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_UNSUPPORTED_MEDIA),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusUnsupportedMediaType),
+			)
+			return
+		}
+		if errors.Is(err, uploading.ErrorFileInfected) {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_INFECTED),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusUnprocessableEntity),
+			)
+			return
+		}
+		if errors.Is(err, uploading.ErrorScanFailed) {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_UNAVAILABLE),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusServiceUnavailable),
+			)
+			return
+		}
 		if err != nil {
 			Response(
 				WithWriterSerializer(w, s),
@@ -279,19 +417,27 @@ func NewUploadFileHandler(log logging.Logger, s serialization.Serializer, upload
 			return
 		}
 
+		SetAuditFileId(req, uploadRes.UniqueId)
+
 		d := struct {
-			UniqueId   string `json:"id"`
-			Name       string `json:"name"`
-			Mimetype   string `json:"mimetype"`
-			Extension  string `json:"extension"`
-			Size       int64  `json:"size"`
-			UploadedAt int64  `json:"uploaded_at"`
+			UniqueId   string            `json:"id"`
+			Name       string            `json:"name"`
+			Mimetype   string            `json:"mimetype"`
+			Extension  string            `json:"extension"`
+			Size       int64             `json:"size"`
+			Attributes map[string]string `json:"attributes"`
+			ScanStatus string            `json:"scan_status,omitempty"`
+			Visibility string            `json:"visibility"`
+			UploadedAt int64             `json:"uploaded_at"`
 		}{
 			UniqueId:   uploadRes.UniqueId,
 			Name:       uploadRes.Name,
 			Mimetype:   uploadRes.Mimetype,
 			Extension:  uploadRes.Extension,
 			Size:       uploadRes.Size,
+			Attributes: uploadRes.Attributes,
+			ScanStatus: uploadRes.ScanStatus,
+			Visibility: uploadRes.Visibility,
 			UploadedAt: uploadRes.UploadedAt.UnixMilli(),
 		}
 
@@ -302,3 +448,1400 @@ func NewUploadFileHandler(log logging.Logger, s serialization.Serializer, upload
 		)
 	}
 }
+
+func NewRetrieveUsageHandler(log logging.Logger, s serialization.Serializer, quotaService quota.Quota) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: RetrieveUsageHandler")
+		defer log.Debug("Returning function: RetrieveUsageHandler")
+
+		clientId, _ := auth.ClientFromContext(req.Context())
+
+		r, err := quotaService.RetrieveUsage(context.Background(), quota.RetrieveUsageParam{
+			ClientId: clientId,
+		})
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		d := struct {
+			ClientId string `json:"client_id"`
+			UsedSize int64  `json:"used_size"`
+			UsedFile int64  `json:"used_file"`
+			MaxSize  int64  `json:"max_size"`
+			MaxFile  int64  `json:"max_file"`
+		}{
+			ClientId: r.ClientId,
+			UsedSize: r.UsedSize,
+			UsedFile: r.UsedFile,
+			MaxSize:  r.MaxSize,
+			MaxFile:  r.MaxFile,
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success retrieve usage"),
+		)
+	}
+}
+
+func NewUpdateQuotaHandler(log logging.Logger, s serialization.Serializer, quotaService quota.Quota) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: UpdateQuotaHandler")
+		defer log.Debug("Returning function: UpdateQuotaHandler")
+
+		vars := mux.Vars(req)
+
+		body := struct {
+			MaxSize int64 `json:"max_size"`
+			MaxFile int64 `json:"max_file"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := quotaService.UpdateQuota(context.Background(), quota.UpdateQuotaParam{
+			ClientId: vars["client_id"],
+			MaxSize:  body.MaxSize,
+			MaxFile:  body.MaxFile,
+		})
+		if err != nil {
+			if errors.Is(err, quota.ErrorResourceNotFound) {
+				Response(
+					WithWriterSerializer(w, s),
+					WithHttpCode(http.StatusNotFound),
+					WithCode(CODE_NOT_FOUND),
+					WithMessage(err.Error()),
+				)
+				return
+			}
+
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		d := struct {
+			ClientId  string `json:"client_id"`
+			MaxSize   int64  `json:"max_size"`
+			MaxFile   int64  `json:"max_file"`
+			UpdatedAt int64  `json:"updated_at"`
+		}{
+			ClientId:  vars["client_id"],
+			MaxSize:   body.MaxSize,
+			MaxFile:   body.MaxFile,
+			UpdatedAt: r.UpdatedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success update client quota"),
+		)
+	}
+}
+
+func NewShareFileHandler(log logging.Logger, s serialization.Serializer, sharer sharing.Sharer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: ShareFileHandler")
+		defer log.Debug("Returning function: ShareFileHandler")
+
+		vars := mux.Vars(req)
+		ownerId, _ := auth.ClientFromContext(req.Context())
+
+		body := struct {
+			ClientId   string `json:"client_id"`
+			Permission string `json:"permission"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := sharer.ShareFile(context.Background(), sharing.ShareFileParam{
+			FileId:     vars["id"],
+			OwnerId:    ownerId,
+			ClientId:   body.ClientId,
+			Permission: body.Permission,
+		})
+		if err != nil {
+			writeSharingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			FileId     string `json:"file_id"`
+			ClientId   string `json:"client_id"`
+			Permission string `json:"permission"`
+			SharedAt   int64  `json:"shared_at"`
+		}{
+			FileId:     vars["id"],
+			ClientId:   body.ClientId,
+			Permission: body.Permission,
+			SharedAt:   r.SharedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success share file"),
+		)
+	}
+}
+
+func NewUnshareFileHandler(log logging.Logger, s serialization.Serializer, sharer sharing.Sharer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: UnshareFileHandler")
+		defer log.Debug("Returning function: UnshareFileHandler")
+
+		vars := mux.Vars(req)
+		ownerId, _ := auth.ClientFromContext(req.Context())
+
+		r, err := sharer.UnshareFile(context.Background(), sharing.UnshareFileParam{
+			FileId:   vars["id"],
+			OwnerId:  ownerId,
+			ClientId: vars["client_id"],
+		})
+		if err != nil {
+			writeSharingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			RevokedAt int64 `json:"revoked_at"`
+		}{
+			RevokedAt: r.RevokedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success unshare file"),
+		)
+	}
+}
+
+func NewUpdateVisibilityHandler(log logging.Logger, s serialization.Serializer, sharer sharing.Sharer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: UpdateVisibilityHandler")
+		defer log.Debug("Returning function: UpdateVisibilityHandler")
+
+		vars := mux.Vars(req)
+		ownerId, _ := auth.ClientFromContext(req.Context())
+
+		body := struct {
+			Visibility string `json:"visibility"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := sharer.UpdateVisibility(context.Background(), sharing.UpdateVisibilityParam{
+			FileId:     vars["id"],
+			OwnerId:    ownerId,
+			Visibility: body.Visibility,
+		})
+		if err != nil {
+			writeSharingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			FileId     string `json:"file_id"`
+			Visibility string `json:"visibility"`
+			UpdatedAt  int64  `json:"updated_at"`
+		}{
+			FileId:     vars["id"],
+			Visibility: body.Visibility,
+			UpdatedAt:  r.UpdatedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success update visibility"),
+		)
+	}
+}
+
+// the response follows RFC 6749 instead of the common response body
+// so the endpoint is usable by standard oauth2 client library,
+// lockout is optional, the failed attempt is not tracked when it is not specified
+func NewIssueTokenHandler(log logging.Logger, s serialization.Serializer, tokenAuth auth.TokenAuth, l auth.Lockout) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: IssueTokenHandler")
+		defer log.Debug("Returning function: IssueTokenHandler")
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		err := req.ParseForm()
+		if err != nil {
+			writeOAuthError(w, s, http.StatusBadRequest, "invalid_request", "invalid request body")
+			return
+		}
+
+		grantType := req.PostForm.Get("grant_type")
+		if grantType == "" {
+			writeOAuthError(w, s, http.StatusBadRequest, "invalid_request", "grant type is not specified")
+			return
+		}
+		if grantType != auth.GRANT_CLIENT_CREDENTIALS {
+			writeOAuthError(w, s, http.StatusBadRequest, "unsupported_grant_type", "grant type is not supported")
+			return
+		}
+
+		clientId, clientSecret, basicAuth := req.BasicAuth()
+		if !basicAuth {
+			clientId = req.PostForm.Get("client_id")
+			clientSecret = req.PostForm.Get("client_secret")
+		}
+
+		ipAddress := RemoteIpAddress(req)
+		if l != nil {
+			lRes, err := l.Check(context.Background(), auth.CheckLockoutParam{
+				ClientId:  clientId,
+				IpAddress: ipAddress,
+			})
+			if err == nil && lRes.Locked {
+				writeOAuthTooManyRequests(w, s, lRes.RetryAfter)
+				return
+			}
+		}
+
+		r, err := tokenAuth.IssueToken(context.Background(), auth.IssueTokenParam{
+			ClientId:     clientId,
+			ClientSecret: clientSecret,
+			Scopes:       strings.Fields(req.PostForm.Get("scope")),
+			IpAddress:    ipAddress,
+		})
+		if errors.Is(err, auth.ErrorInvalidClient) || errors.Is(err, auth.ErrorUnknownClient) {
+			if l != nil {
+				// the unknown client is not tracked, otherwise random client ids grow the lockout entries
+				failedClientId := clientId
+				if errors.Is(err, auth.ErrorUnknownClient) {
+					failedClientId = ""
+				}
+				fRes, err := l.RecordFailure(context.Background(), auth.RecordFailureParam{
+					ClientId:  failedClientId,
+					IpAddress: ipAddress,
+				})
+				if err == nil && fRes.Locked {
+					writeOAuthTooManyRequests(w, s, fRes.RetryAfter)
+					return
+				}
+			}
+			if basicAuth {
+				w.Header().Set("WWW-Authenticate", `Basic realm="local"`)
+			}
+			writeOAuthError(w, s, http.StatusUnauthorized, "invalid_client", err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrorInvalidScope) {
+			writeOAuthError(w, s, http.StatusBadRequest, "invalid_scope", err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrorAddressNotAllowed) {
+			log.WithFields(map[string]interface{}{
+				"event":      "auth.ip_denied",
+				"client_id":  clientId,
+				"ip_address": ipAddress,
+				"path":       req.URL.Path,
+			}).Warn("Request is denied by the client allowlist")
+			writeOAuthError(w, s, http.StatusForbidden, "access_denied", err.Error())
+			return
+		}
+		if err != nil {
+			writeOAuthError(w, s, http.StatusInternalServerError, "server_error", err.Error())
+			return
+		}
+
+		if l != nil {
+			l.RecordSuccess(context.Background(), auth.RecordSuccessParam{
+				ClientId:  clientId,
+				IpAddress: ipAddress,
+			})
+		}
+
+		d := struct {
+			AccessToken string `json:"access_token"`
+			TokenType   string `json:"token_type"`
+			ExpiresIn   int64  `json:"expires_in"`
+			Scope       string `json:"scope,omitempty"`
+		}{
+			AccessToken: r.AccessToken,
+			TokenType:   r.TokenType,
+			ExpiresIn:   int64(r.ExpiresIn.Seconds()),
+			Scope:       strings.Join(r.Scopes, " "),
+		}
+		writeJson(w, s, http.StatusOK, d)
+	}
+}
+
+func NewCreateClientHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: CreateClientHandler")
+		defer log.Debug("Returning function: CreateClientHandler")
+
+		body := struct {
+			Name         string   `json:"name"`
+			Scopes       []string `json:"scopes"`
+			AllowedCidrs []string `json:"allowed_cidrs"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := manager.CreateClient(context.Background(), managing.CreateClientParam{
+			Name:         body.Name,
+			Scopes:       body.Scopes,
+			AllowedCidrs: body.AllowedCidrs,
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		d := struct {
+			Id           string   `json:"id"`
+			Name         string   `json:"name"`
+			ClientId     string   `json:"client_id"`
+			ClientSecret string   `json:"client_secret"`
+			SecretId     string   `json:"secret_id"`
+			SigningKey   string   `json:"signing_key,omitempty"`
+			Scopes       []string `json:"scopes"`
+			CreatedAt    int64    `json:"created_at"`
+		}{
+			Id:           r.Id,
+			Name:         r.Name,
+			ClientId:     r.ClientId,
+			ClientSecret: r.ClientSecret,
+			SecretId:     r.SecretId,
+			SigningKey:   r.SigningKey,
+			Scopes:       r.Scopes,
+			CreatedAt:    r.CreatedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success create client"),
+		)
+	}
+}
+
+func NewListClientHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: ListClientHandler")
+		defer log.Debug("Returning function: ListClientHandler")
+
+		limit := 0
+		if l := req.URL.Query().Get("limit"); l != "" {
+			var err error
+			limit, err = strconv.Atoi(l)
+			if err != nil {
+				Response(
+					WithWriterSerializer(w, s),
+					WithCode(CODE_ERROR),
+					WithMessage("invalid limit parameter"),
+					WithHttpCode(http.StatusBadRequest),
+				)
+				return
+			}
+		}
+
+		r, err := manager.ListClient(context.Background(), managing.ListClientParam{
+			AfterId: req.URL.Query().Get("after_id"),
+			Limit:   limit,
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		type clientItem struct {
+			Id           string   `json:"id"`
+			Name         string   `json:"name"`
+			ClientId     string   `json:"client_id"`
+			Scopes       []string `json:"scopes"`
+			AllowedCidrs []string `json:"allowed_cidrs"`
+			DisabledAt   *int64   `json:"disabled_at"`
+			CreatedAt    int64    `json:"created_at"`
+			UpdatedAt    int64    `json:"updated_at"`
+		}
+		items := []clientItem{}
+		for _, client := range r.Items {
+			item := clientItem{
+				Id:           client.Id,
+				Name:         client.Name,
+				ClientId:     client.ClientId,
+				Scopes:       client.Scopes,
+				AllowedCidrs: append([]string{}, client.AllowedCidrs...),
+				CreatedAt:    client.CreatedAt.UnixMilli(),
+				UpdatedAt:    client.UpdatedAt.UnixMilli(),
+			}
+			if client.DisabledAt != nil {
+				disabledAt := client.DisabledAt.UnixMilli()
+				item.DisabledAt = &disabledAt
+			}
+			items = append(items, item)
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(items),
+			WithMessage("success list client"),
+		)
+	}
+}
+
+func NewRotateSecretHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: RotateSecretHandler")
+		defer log.Debug("Returning function: RotateSecretHandler")
+
+		vars := mux.Vars(req)
+
+		body := struct {
+			Label       string `json:"label"`
+			ExpiresIn   int64  `json:"expires_in"`
+			GracePeriod int64  `json:"grace_period"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil && len(data) > 0 {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := manager.RotateSecret(context.Background(), managing.RotateSecretParam{
+			ClientId:    vars["client_id"],
+			Label:       body.Label,
+			ExpiresIn:   time.Duration(body.ExpiresIn) * time.Second,
+			GracePeriod: time.Duration(body.GracePeriod) * time.Second,
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		d := struct {
+			ClientId          string `json:"client_id"`
+			SecretId          string `json:"secret_id"`
+			ClientSecret      string `json:"client_secret"`
+			SigningKey        string `json:"signing_key,omitempty"`
+			RotatedAt         int64  `json:"rotated_at"`
+			PreviousExpiresAt int64  `json:"previous_expires_at"`
+		}{
+			ClientId:          r.ClientId,
+			SecretId:          r.SecretId,
+			ClientSecret:      r.ClientSecret,
+			SigningKey:        r.SigningKey,
+			RotatedAt:         r.RotatedAt.UnixMilli(),
+			PreviousExpiresAt: r.PreviousExpiresAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success rotate client secret"),
+		)
+	}
+}
+
+func NewListSecretHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: ListSecretHandler")
+		defer log.Debug("Returning function: ListSecretHandler")
+
+		vars := mux.Vars(req)
+
+		r, err := manager.ListSecret(context.Background(), managing.ListSecretParam{
+			ClientId: vars["client_id"],
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		type secretItem struct {
+			Id         string `json:"id"`
+			Label      string `json:"label"`
+			CreatedAt  int64  `json:"created_at"`
+			ExpiresAt  *int64 `json:"expires_at"`
+			LastUsedAt *int64 `json:"last_used_at"`
+		}
+		items := []secretItem{}
+		for _, secret := range r.Items {
+			item := secretItem{
+				Id:        secret.Id,
+				Label:     secret.Label,
+				CreatedAt: secret.CreatedAt.UnixMilli(),
+			}
+			if secret.ExpiresAt != nil {
+				expiresAt := secret.ExpiresAt.UnixMilli()
+				item.ExpiresAt = &expiresAt
+			}
+			if secret.LastUsedAt != nil {
+				lastUsedAt := secret.LastUsedAt.UnixMilli()
+				item.LastUsedAt = &lastUsedAt
+			}
+			items = append(items, item)
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(items),
+			WithMessage("success list client secret"),
+		)
+	}
+}
+
+func NewRevokeSecretHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: RevokeSecretHandler")
+		defer log.Debug("Returning function: RevokeSecretHandler")
+
+		vars := mux.Vars(req)
+
+		r, err := manager.RevokeSecret(context.Background(), managing.RevokeSecretParam{
+			ClientId: vars["client_id"],
+			SecretId: vars["secret_id"],
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			ClientId  string `json:"client_id"`
+			SecretId  string `json:"secret_id"`
+			RevokedAt int64  `json:"revoked_at"`
+		}{
+			ClientId:  vars["client_id"],
+			SecretId:  vars["secret_id"],
+			RevokedAt: r.RevokedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success revoke client secret"),
+		)
+	}
+}
+
+func NewDisableClientHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: DisableClientHandler")
+		defer log.Debug("Returning function: DisableClientHandler")
+
+		vars := mux.Vars(req)
+
+		r, err := manager.DisableClient(context.Background(), managing.DisableClientParam{
+			ClientId: vars["client_id"],
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			ClientId   string `json:"client_id"`
+			DisabledAt int64  `json:"disabled_at"`
+		}{
+			ClientId:   vars["client_id"],
+			DisabledAt: r.DisabledAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success disable client"),
+		)
+	}
+}
+
+func NewUpdateAllowlistHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: UpdateAllowlistHandler")
+		defer log.Debug("Returning function: UpdateAllowlistHandler")
+
+		vars := mux.Vars(req)
+
+		body := struct {
+			AllowedCidrs []string `json:"allowed_cidrs"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := manager.UpdateAllowlist(context.Background(), managing.UpdateAllowlistParam{
+			ClientId:     vars["client_id"],
+			AllowedCidrs: body.AllowedCidrs,
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			ClientId     string   `json:"client_id"`
+			AllowedCidrs []string `json:"allowed_cidrs"`
+			UpdatedAt    int64    `json:"updated_at"`
+		}{
+			ClientId:     vars["client_id"],
+			AllowedCidrs: append([]string{}, body.AllowedCidrs...),
+			UpdatedAt:    r.UpdatedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success update client allowlist"),
+		)
+	}
+}
+
+func NewUpdateRateLimitHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: UpdateRateLimitHandler")
+		defer log.Debug("Returning function: UpdateRateLimitHandler")
+
+		vars := mux.Vars(req)
+
+		body := struct {
+			RequestRate *int64 `json:"request_rate"`
+			ByteRate    *int64 `json:"byte_rate"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := manager.UpdateRateLimit(context.Background(), managing.UpdateRateLimitParam{
+			ClientId:    vars["client_id"],
+			RequestRate: body.RequestRate,
+			ByteRate:    body.ByteRate,
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			ClientId    string `json:"client_id"`
+			RequestRate *int64 `json:"request_rate"`
+			ByteRate    *int64 `json:"byte_rate"`
+			UpdatedAt   int64  `json:"updated_at"`
+		}{
+			ClientId:    vars["client_id"],
+			RequestRate: body.RequestRate,
+			ByteRate:    body.ByteRate,
+			UpdatedAt:   r.UpdatedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success update client rate limit"),
+		)
+	}
+}
+
+func NewDeleteClientHandler(log logging.Logger, s serialization.Serializer, manager managing.ClientManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: DeleteClientHandler")
+		defer log.Debug("Returning function: DeleteClientHandler")
+
+		vars := mux.Vars(req)
+
+		r, err := manager.DeleteClient(context.Background(), managing.DeleteClientParam{
+			ClientId: vars["client_id"],
+		})
+		if err != nil {
+			writeManagingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			ClientId  string `json:"client_id"`
+			DeletedAt int64  `json:"deleted_at"`
+		}{
+			ClientId:  vars["client_id"],
+			DeletedAt: r.DeletedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success delete client"),
+		)
+	}
+}
+
+func NewCredentialCacheStatsHandler(log logging.Logger, s serialization.Serializer, cache auth.CredentialCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: CredentialCacheStatsHandler")
+		defer log.Debug("Returning function: CredentialCacheStatsHandler")
+
+		stats := cache.Stats()
+		hitRatio := float64(0)
+		if total := stats.Hits + stats.Misses; total > 0 {
+			hitRatio = float64(stats.Hits) / float64(total)
+		}
+
+		d := struct {
+			Hits      uint64  `json:"hits"`
+			Misses    uint64  `json:"misses"`
+			Evictions uint64  `json:"evictions"`
+			Size      int     `json:"size"`
+			HitRatio  float64 `json:"hit_ratio"`
+		}{
+			Hits:      stats.Hits,
+			Misses:    stats.Misses,
+			Evictions: stats.Evictions,
+			Size:      stats.Size,
+			HitRatio:  hitRatio,
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success retrieve credential cache stats"),
+		)
+	}
+}
+
+func NewListAuditLogHandler(log logging.Logger, s serialization.Serializer, auditor auditing.Auditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: ListAuditLogHandler")
+		defer log.Debug("Returning function: ListAuditLogHandler")
+
+		query := req.URL.Query()
+		p := auditing.ListLogParam{
+			ClientId:  query.Get("client_id"),
+			Action:    query.Get("action"),
+			FileId:    query.Get("file_id"),
+			Outcome:   query.Get("outcome"),
+			RequestId: query.Get("request_id"),
+			AfterId:   query.Get("after_id"),
+		}
+
+		if l := query.Get("limit"); l != "" {
+			limit, err := strconv.Atoi(l)
+			if err != nil {
+				Response(
+					WithWriterSerializer(w, s),
+					WithCode(CODE_ERROR),
+					WithMessage("invalid limit parameter"),
+					WithHttpCode(http.StatusBadRequest),
+				)
+				return
+			}
+			p.Limit = limit
+		}
+		if f := query.Get("from"); f != "" {
+			from, err := strconv.ParseInt(f, 10, 64)
+			if err != nil {
+				Response(
+					WithWriterSerializer(w, s),
+					WithCode(CODE_ERROR),
+					WithMessage("invalid from parameter"),
+					WithHttpCode(http.StatusBadRequest),
+				)
+				return
+			}
+			createdFrom := time.UnixMilli(from)
+			p.CreatedFrom = &createdFrom
+		}
+		if t := query.Get("to"); t != "" {
+			to, err := strconv.ParseInt(t, 10, 64)
+			if err != nil {
+				Response(
+					WithWriterSerializer(w, s),
+					WithCode(CODE_ERROR),
+					WithMessage("invalid to parameter"),
+					WithHttpCode(http.StatusBadRequest),
+				)
+				return
+			}
+			createdTo := time.UnixMilli(to)
+			p.CreatedTo = &createdTo
+		}
+
+		r, err := auditor.ListLog(context.Background(), p)
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage(err.Error()),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		type logItem struct {
+			Id        string `json:"id"`
+			ClientId  string `json:"client_id"`
+			Action    string `json:"action"`
+			FileId    string `json:"file_id"`
+			IpAddress string `json:"ip_address"`
+			RequestId string `json:"request_id"`
+			Outcome   string `json:"outcome"`
+			Reason    string `json:"reason"`
+			CreatedAt int64  `json:"created_at"`
+		}
+		items := []logItem{}
+		for _, l := range r.Items {
+			items = append(items, logItem{
+				Id:        l.Id,
+				ClientId:  l.ClientId,
+				Action:    l.Action,
+				FileId:    l.FileId,
+				IpAddress: l.IpAddress,
+				RequestId: l.RequestId,
+				Outcome:   l.Outcome,
+				Reason:    l.Reason,
+				CreatedAt: l.CreatedAt.UnixMilli(),
+			})
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(items),
+			WithMessage("success list audit log"),
+		)
+	}
+}
+
+func NewCreateWebhookHandler(log logging.Logger, s serialization.Serializer, webhook notifying.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: CreateWebhookHandler")
+		defer log.Debug("Returning function: CreateWebhookHandler")
+
+		clientId, _ := auth.ClientFromContext(req.Context())
+
+		body := struct {
+			Url        string   `json:"url"`
+			EventTypes []string `json:"event_types"`
+		}{}
+		data, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = s.Unmarshal(data, &body)
+		}
+		if err != nil {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("invalid request body"),
+				WithHttpCode(http.StatusBadRequest),
+			)
+			return
+		}
+
+		r, err := webhook.CreateSubscription(context.Background(), notifying.CreateSubscriptionParam{
+			ClientId:   clientId,
+			Url:        body.Url,
+			EventTypes: body.EventTypes,
+		})
+		if err != nil {
+			writeNotifyingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			Id         string   `json:"id"`
+			Url        string   `json:"url"`
+			EventTypes []string `json:"event_types"`
+			Secret     string   `json:"secret"`
+			CreatedAt  int64    `json:"created_at"`
+		}{
+			Id:         r.Id,
+			Url:        r.Url,
+			EventTypes: r.EventTypes,
+			Secret:     r.Secret,
+			CreatedAt:  r.CreatedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success create webhook"),
+		)
+	}
+}
+
+func NewListWebhookHandler(log logging.Logger, s serialization.Serializer, webhook notifying.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: ListWebhookHandler")
+		defer log.Debug("Returning function: ListWebhookHandler")
+
+		clientId, _ := auth.ClientFromContext(req.Context())
+
+		r, err := webhook.ListSubscription(context.Background(), notifying.ListSubscriptionParam{
+			ClientId: clientId,
+		})
+		if err != nil {
+			writeNotifyingError(w, s, err)
+			return
+		}
+
+		type subscriptionItem struct {
+			Id         string   `json:"id"`
+			Url        string   `json:"url"`
+			EventTypes []string `json:"event_types"`
+			CreatedAt  int64    `json:"created_at"`
+			UpdatedAt  int64    `json:"updated_at"`
+		}
+		items := []subscriptionItem{}
+		for _, subscription := range r.Items {
+			items = append(items, subscriptionItem{
+				Id:         subscription.Id,
+				Url:        subscription.Url,
+				EventTypes: subscription.EventTypes,
+				CreatedAt:  subscription.CreatedAt.UnixMilli(),
+				UpdatedAt:  subscription.UpdatedAt.UnixMilli(),
+			})
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(items),
+			WithMessage("success list webhook"),
+		)
+	}
+}
+
+func NewDeleteWebhookHandler(log logging.Logger, s serialization.Serializer, webhook notifying.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: DeleteWebhookHandler")
+		defer log.Debug("Returning function: DeleteWebhookHandler")
+
+		vars := mux.Vars(req)
+		clientId, _ := auth.ClientFromContext(req.Context())
+
+		r, err := webhook.DeleteSubscription(context.Background(), notifying.DeleteSubscriptionParam{
+			Id:       vars["id"],
+			ClientId: clientId,
+		})
+		if err != nil {
+			writeNotifyingError(w, s, err)
+			return
+		}
+
+		d := struct {
+			DeletedAt int64 `json:"deleted_at"`
+		}{
+			DeletedAt: r.DeletedAt.UnixMilli(),
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(d),
+			WithMessage("success delete webhook"),
+		)
+	}
+}
+
+func NewListWebhookDeliveryHandler(log logging.Logger, s serialization.Serializer, webhook notifying.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: ListWebhookDeliveryHandler")
+		defer log.Debug("Returning function: ListWebhookDeliveryHandler")
+
+		clientId, _ := auth.ClientFromContext(req.Context())
+
+		query := req.URL.Query()
+		p := notifying.ListDeliveryParam{
+			ClientId:       clientId,
+			SubscriptionId: query.Get("subscription_id"),
+			Status:         query.Get("status"),
+			AfterId:        query.Get("after_id"),
+		}
+
+		if l := query.Get("limit"); l != "" {
+			limit, err := strconv.Atoi(l)
+			if err != nil {
+				Response(
+					WithWriterSerializer(w, s),
+					WithCode(CODE_ERROR),
+					WithMessage("invalid limit parameter"),
+					WithHttpCode(http.StatusBadRequest),
+				)
+				return
+			}
+			p.Limit = limit
+		}
+
+		r, err := webhook.ListDelivery(context.Background(), p)
+		if err != nil {
+			writeNotifyingError(w, s, err)
+			return
+		}
+
+		type deliveryItem struct {
+			Id             string `json:"id"`
+			SubscriptionId string `json:"subscription_id"`
+			EventId        string `json:"event_id"`
+			EventType      string `json:"event_type"`
+			Status         string `json:"status"`
+			Attempt        int    `json:"attempt"`
+			NextAttemptAt  int64  `json:"next_attempt_at"`
+			ResponseCode   int    `json:"response_code"`
+			LastError      string `json:"last_error"`
+			CreatedAt      int64  `json:"created_at"`
+			UpdatedAt      int64  `json:"updated_at"`
+			DeliveredAt    *int64 `json:"delivered_at"`
+		}
+		items := []deliveryItem{}
+		for _, delivery := range r.Items {
+			item := deliveryItem{
+				Id:             delivery.Id,
+				SubscriptionId: delivery.SubscriptionId,
+				EventId:        delivery.EventId,
+				EventType:      delivery.EventType,
+				Status:         delivery.Status,
+				Attempt:        delivery.Attempt,
+				NextAttemptAt:  delivery.NextAttemptAt.UnixMilli(),
+				ResponseCode:   delivery.ResponseCode,
+				LastError:      delivery.LastError,
+				CreatedAt:      delivery.CreatedAt.UnixMilli(),
+				UpdatedAt:      delivery.UpdatedAt.UnixMilli(),
+			}
+			if delivery.DeliveredAt != nil {
+				deliveredAt := delivery.DeliveredAt.UnixMilli()
+				item.DeliveredAt = &deliveredAt
+			}
+			items = append(items, item)
+		}
+
+		Response(
+			WithWriterSerializer(w, s),
+			WithData(items),
+			WithMessage("success list webhook delivery"),
+		)
+	}
+}
+
+// the events are streamed as server-sent events, the event id is the sequence of the event,
+// so the client resumes after the last received event using the Last-Event-ID header
+// or the last_event_id query when the header is not able to be specified,
+// heartbeat default to 15 seconds when it is not specified
+func NewEventStreamHandler(log logging.Logger, s serialization.Serializer, stream notifying.EventStream, heartbeat time.Duration) http.HandlerFunc {
+	if heartbeat <= 0 {
+		heartbeat = DEFAULT_EVENT_HEARTBEAT
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		log.Debug("In function: EventStreamHandler")
+		defer log.Debug("Returning function: EventStreamHandler")
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			Response(
+				WithWriterSerializer(w, s),
+				WithCode(CODE_ERROR),
+				WithMessage("streaming is not supported"),
+				WithHttpCode(http.StatusInternalServerError),
+			)
+			return
+		}
+
+		clientId, _ := auth.ClientFromContext(req.Context())
+
+		lastEventId := req.Header.Get(HEADER_LAST_EVENT_ID)
+		if lastEventId == "" {
+			lastEventId = req.URL.Query().Get("last_event_id")
+		}
+		var afterSequence int64
+		if lastEventId != "" {
+			sequence, err := strconv.ParseInt(lastEventId, 10, 64)
+			if err != nil || sequence < 0 {
+				Response(
+					WithWriterSerializer(w, s),
+					WithCode(CODE_ERROR),
+					WithMessage("invalid last event id"),
+					WithHttpCode(http.StatusBadRequest),
+				)
+				return
+			}
+			afterSequence = sequence
+		}
+
+		ctx := req.Context()
+		r, err := stream.Subscribe(ctx, notifying.SubscribeParam{
+			ClientId:      clientId,
+			AfterSequence: afterSequence,
+		})
+		if err != nil {
+			writeNotifyingError(w, s, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		flusher.Flush()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-r.Events:
+				if !ok {
+					return
+				}
+				err = writeServerSentEvent(w, s, event)
+				if err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-ticker.C:
+				_, err = io.WriteString(w, ": heartbeat\n\n")
+				if err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func writeOAuthError(w http.ResponseWriter, s serialization.Serializer, httpCode int, code, description string) {
+	d := struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description,omitempty"`
+	}{
+		Error:            code,
+		ErrorDescription: description,
+	}
+	writeJson(w, s, httpCode, d)
+}
+
+func writeOAuthTooManyRequests(w http.ResponseWriter, s serialization.Serializer, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", formatSeconds(retryAfter))
+	writeOAuthError(w, s, http.StatusTooManyRequests, "invalid_client", "too many failed attempts")
+}
+
+func writeJson(w http.ResponseWriter, s serialization.Serializer, httpCode int, d interface{}) {
+	r, err := s.Marshal(d)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(httpCode)
+	w.Write(r)
+}
+
+func writeSharingError(w http.ResponseWriter, s serialization.Serializer, err error) {
+	if errors.Is(err, sharing.ErrorResourceNotFound) {
+		Response(
+			WithWriterSerializer(w, s),
+			WithHttpCode(http.StatusNotFound),
+			WithCode(CODE_NOT_FOUND),
+			WithMessage(err.Error()),
+		)
+		return
+	}
+
+	if errors.Is(err, sharing.ErrorForbidden) {
+		Response(
+			WithWriterSerializer(w, s),
+			WithHttpCode(http.StatusForbidden),
+			WithCode(CODE_FORBIDDEN),
+			WithMessage(err.Error()),
+		)
+		return
+	}
+
+	Response(
+		WithWriterSerializer(w, s),
+		WithCode(CODE_ERROR),
+		WithMessage(err.Error()),
+		WithHttpCode(http.StatusBadRequest),
+	)
+}
+
+func writeManagingError(w http.ResponseWriter, s serialization.Serializer, err error) {
+	if errors.Is(err, managing.ErrorResourceNotFound) {
+		Response(
+			WithWriterSerializer(w, s),
+			WithHttpCode(http.StatusNotFound),
+			WithCode(CODE_NOT_FOUND),
+			WithMessage(err.Error()),
+		)
+		return
+	}
+
+	Response(
+		WithWriterSerializer(w, s),
+		WithCode(CODE_ERROR),
+		WithMessage(err.Error()),
+		WithHttpCode(http.StatusBadRequest),
+	)
+}
+
+func writeServerSentEvent(w io.Writer, s serialization.Serializer, e notifying.Event) error {
+	d := struct {
+		Id        string `json:"id"`
+		Type      string `json:"type"`
+		CreatedAt int64  `json:"created_at"`
+		Data      struct {
+			Id        string `json:"id"`
+			Name      string `json:"name"`
+			Mimetype  string `json:"mimetype"`
+			Extension string `json:"extension"`
+			Size      int64  `json:"size"`
+			ClientId  string `json:"client_id"`
+		} `json:"data"`
+	}{
+		Id:        e.Id,
+		Type:      e.Type,
+		CreatedAt: e.OccurredAt.UnixMilli(),
+	}
+	d.Data.Id = e.File.Id
+	d.Data.Name = e.File.Name
+	d.Data.Mimetype = e.File.Mimetype
+	d.Data.Extension = e.File.Extension
+	d.Data.Size = e.File.Size
+	d.Data.ClientId = e.ClientId
+
+	data, err := s.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Sequence, e.Type, data)
+	return err
+}
+
+func writeNotifyingError(w http.ResponseWriter, s serialization.Serializer, err error) {
+	if errors.Is(err, notifying.ErrorResourceNotFound) {
+		Response(
+			WithWriterSerializer(w, s),
+			WithHttpCode(http.StatusNotFound),
+			WithCode(CODE_NOT_FOUND),
+			WithMessage(err.Error()),
+		)
+		return
+	}
+
+	Response(
+		WithWriterSerializer(w, s),
+		WithCode(CODE_ERROR),
+		WithMessage(err.Error()),
+		WithHttpCode(http.StatusBadRequest),
+	)
+}
+
+func parseAcceptEncoding(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	codecs := []string{}
+	for _, part := range strings.Split(value, ",") {
+		params := strings.Split(part, ";")
+		codec := strings.ToLower(strings.TrimSpace(params[0]))
+		if codec == "" {
+			continue
+		}
+
+		accepted := true
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+			if err == nil && q == 0 {
+				accepted = false
+			}
+		}
+		if accepted {
+			codecs = append(codecs, codec)
+		}
+	}
+	return codecs
+}
@@ -3,19 +3,29 @@ package rest_app_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/go-seidon/local/internal/auditing"
+	"github.com/go-seidon/local/internal/auth"
 	"github.com/go-seidon/local/internal/deleting"
 	"github.com/go-seidon/local/internal/healthcheck"
+	"github.com/go-seidon/local/internal/managing"
 	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/notifying"
+	"github.com/go-seidon/local/internal/quota"
 	rest_app "github.com/go-seidon/local/internal/rest-app"
 	"github.com/go-seidon/local/internal/retrieving"
 	"github.com/go-seidon/local/internal/serialization"
+	"github.com/go-seidon/local/internal/sharing"
 	"github.com/go-seidon/local/internal/uploading"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -500,6 +510,53 @@ var _ = Describe("Handler Package", func() {
 			})
 		})
 
+		When("client has no access to the file", func() {
+			It("should write response", func() {
+				r = r.WithContext(auth.NewClientContext(r.Context(), "other-client"))
+				p.ClientId = "other-client"
+
+				err := deleting.ErrorForbidden
+
+				b := rest_app.ResponseBody{
+					Code:    "FORBIDDEN",
+					Message: err.Error(),
+				}
+
+				log.
+					EXPECT().
+					Debug("In function: DeleteFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: DeleteFileHandler").
+					Times(1)
+
+				deleteService.
+					EXPECT().
+					DeleteFile(gomock.Any(), gomock.Eq(p)).
+					Return(nil, err).
+					Times(1)
+
+				serializer.
+					EXPECT().
+					Marshal(b).
+					Return([]byte{}, nil).
+					Times(1)
+
+				w.
+					EXPECT().
+					WriteHeader(gomock.Eq(403)).
+					Times(1)
+
+				w.
+					EXPECT().
+					Write([]byte{}).
+					Times(1)
+
+				handler.ServeHTTP(w, r)
+			})
+		})
+
 		When("success delete file", func() {
 			It("should write response", func() {
 				res := &deleting.DeleteFileResult{
@@ -560,7 +617,7 @@ var _ = Describe("Handler Package", func() {
 			log             *mock.MockLogger
 			serializer      *mock.MockSerializer
 			retrieveService *mock.MockRetriever
-			fileData        *mock.MockReadCloser
+			fileData        *mock.MockReadSeekCloser
 			p               retrieving.RetrieveFileParam
 		)
 
@@ -575,7 +632,7 @@ var _ = Describe("Handler Package", func() {
 			log = mock.NewMockLogger(ctrl)
 			serializer = mock.NewMockSerializer(ctrl)
 			retrieveService = mock.NewMockRetriever(ctrl)
-			fileData = mock.NewMockReadCloser(ctrl)
+			fileData = mock.NewMockReadSeekCloser(ctrl)
 			handler = rest_app.NewRetrieveFileHandler(log, serializer, retrieveService)
 			p = retrieving.RetrieveFileParam{
 				FileId: "mock-file-id",
@@ -672,7 +729,54 @@ var _ = Describe("Handler Package", func() {
 			})
 		})
 
-		When("failed read file", func() {
+		When("client has no access to the file", func() {
+			It("should write response", func() {
+				r = r.WithContext(auth.NewClientContext(r.Context(), "other-client"))
+				p.ClientId = "other-client"
+
+				err := retrieving.ErrorForbidden
+
+				b := rest_app.ResponseBody{
+					Code:    "FORBIDDEN",
+					Message: err.Error(),
+				}
+
+				log.
+					EXPECT().
+					Debug("In function: RetrieveFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: RetrieveFileHandler").
+					Times(1)
+
+				retrieveService.
+					EXPECT().
+					RetrieveFile(gomock.Any(), gomock.Eq(p)).
+					Return(nil, err).
+					Times(1)
+
+				serializer.
+					EXPECT().
+					Marshal(b).
+					Return([]byte{}, nil).
+					Times(1)
+
+				w.
+					EXPECT().
+					WriteHeader(gomock.Eq(403)).
+					Times(1)
+
+				w.
+					EXPECT().
+					Write([]byte{}).
+					Times(1)
+
+				handler.ServeHTTP(w, r)
+			})
+		})
+
+		When("failed seek file", func() {
 			It("should write response", func() {
 
 				fileData.
@@ -682,8 +786,8 @@ var _ = Describe("Handler Package", func() {
 
 				fileData.
 					EXPECT().
-					Read(gomock.Any()).
-					Return(0, fmt.Errorf("read error")).
+					Seek(gomock.Eq(int64(0)), gomock.Eq(io.SeekEnd)).
+					Return(int64(0), fmt.Errorf("seek error")).
 					Times(1)
 
 				res := &retrieving.RetrieveFileResult{
@@ -692,7 +796,7 @@ var _ = Describe("Handler Package", func() {
 
 				b := rest_app.ResponseBody{
 					Code:    "ERROR",
-					Message: "read error",
+					Message: "seek error",
 				}
 
 				log.
@@ -733,27 +837,85 @@ var _ = Describe("Handler Package", func() {
 		When("mimetype is empty", func() {
 			It("should write response", func() {
 
-				fileData.
+				res := &retrieving.RetrieveFileResult{
+					Data:      newSeekableFile("mock-data"),
+					UniqueId:  "mock-unique-id",
+					Name:      "mock-name",
+					Path:      "mock-path",
+					MimeType:  "",
+					Extension: "mock-extension",
+					DeletedAt: nil,
+				}
+
+				log.
 					EXPECT().
-					Close().
+					Debug("In function: RetrieveFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: RetrieveFileHandler").
 					Times(1)
 
-				fileData.
+				retrieveService.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(res, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Content-Length")).To(Equal("9"))
+				Expect(w.Body.String()).To(Equal("mock-data"))
+			})
+		})
+
+		When("mimetype is not empty", func() {
+			It("should write response", func() {
+
+				log.
+					EXPECT().
+					Debug("In function: RetrieveFileHandler").
+					Times(1)
+				log.
 					EXPECT().
-					Read(gomock.Any()).
-					Return(0, io.EOF).
+					Debug("Returning function: RetrieveFileHandler").
 					Times(1)
 
 				res := &retrieving.RetrieveFileResult{
-					Data:      fileData,
+					Data:      newSeekableFile("mock-data"),
 					UniqueId:  "mock-unique-id",
 					Name:      "mock-name",
 					Path:      "mock-path",
-					MimeType:  "",
+					MimeType:  "text/plain",
 					Extension: "mock-extension",
 					DeletedAt: nil,
 				}
 
+				retrieveService.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(res, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Content-Type")).To(Equal("text/plain"))
+				Expect(w.Header().Get("Accept-Ranges")).To(Equal("bytes"))
+				Expect(w.Body.String()).To(Equal("mock-data"))
+			})
+		})
+
+		When("range is requested", func() {
+			It("should write the partial content", func() {
+				r.Header = http.Header{}
+				r.Header.Set("Range", "bytes=5-8")
+
 				log.
 					EXPECT().
 					Debug("In function: RetrieveFileHandler").
@@ -763,28 +925,33 @@ var _ = Describe("Handler Package", func() {
 					Debug("Returning function: RetrieveFileHandler").
 					Times(1)
 
+				res := &retrieving.RetrieveFileResult{
+					Data:      newSeekableFile("mock-data"),
+					UniqueId:  "mock-unique-id",
+					MimeType:  "text/plain",
+					Extension: "mock-extension",
+				}
+
 				retrieveService.
 					EXPECT().
 					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
 					Return(res, nil).
 					Times(1)
 
-				w.EXPECT().
-					Header().
-					Return(http.Header{}).
-					Times(1)
-
-				w.
-					EXPECT().
-					Write([]byte{}).
-					Times(1)
+				w := httptest.NewRecorder()
 
 				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(206))
+				Expect(w.Header().Get("Content-Range")).To(Equal("bytes 5-8/9"))
+				Expect(w.Body.String()).To(Equal("data"))
 			})
 		})
 
-		When("mimetype is not empty", func() {
-			It("should write response", func() {
+		When("range is not satisfiable", func() {
+			It("should write range error", func() {
+				r.Header = http.Header{}
+				r.Header.Set("Range", "bytes=20-30")
 
 				log.
 					EXPECT().
@@ -795,25 +962,50 @@ var _ = Describe("Handler Package", func() {
 					Debug("Returning function: RetrieveFileHandler").
 					Times(1)
 
-				fileData.
+				res := &retrieving.RetrieveFileResult{
+					Data:     newSeekableFile("mock-data"),
+					UniqueId: "mock-unique-id",
+					MimeType: "text/plain",
+				}
+
+				retrieveService.
 					EXPECT().
-					Close().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(res, nil).
 					Times(1)
 
-				fileData.
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(416))
+				Expect(w.Header().Get("Content-Range")).To(Equal("bytes */9"))
+			})
+		})
+
+		When("file is compressed and the codec is accepted", func() {
+			It("should write the compressed data", func() {
+				r.Header = http.Header{}
+				r.Header.Set("Accept-Encoding", "br;q=0, GZIP;q=0.8, identity")
+				p.AcceptedCodecs = []string{"gzip", "identity"}
+
+				log.
+					EXPECT().
+					Debug("In function: RetrieveFileHandler").
+					Times(1)
+				log.
 					EXPECT().
-					Read(gomock.Any()).
-					Return(0, io.EOF).
+					Debug("Returning function: RetrieveFileHandler").
 					Times(1)
 
 				res := &retrieving.RetrieveFileResult{
-					Data:      fileData,
+					Data:      newSeekableFile("compressed-data"),
 					UniqueId:  "mock-unique-id",
 					Name:      "mock-name",
 					Path:      "mock-path",
 					MimeType:  "text/plain",
 					Extension: "mock-extension",
-					DeletedAt: nil,
+					Codec:     "gzip",
 				}
 
 				retrieveService.
@@ -822,17 +1014,15 @@ var _ = Describe("Handler Package", func() {
 					Return(res, nil).
 					Times(1)
 
-				w.EXPECT().
-					Header().
-					Return(http.Header{}).
-					Times(1)
-
-				w.
-					EXPECT().
-					Write([]byte{}).
-					Times(1)
+				w := httptest.NewRecorder()
 
 				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Content-Type")).To(Equal("text/plain"))
+				Expect(w.Header().Get("Content-Encoding")).To(Equal("gzip"))
+				Expect(w.Header().Get("Vary")).To(Equal("Accept-Encoding"))
+				Expect(w.Body.String()).To(Equal("compressed-data"))
 			})
 		})
 	})
@@ -878,6 +1068,7 @@ var _ = Describe("Handler Package", func() {
 				log, serializer, uploadService,
 				locator, cfg,
 			)
+			ctx = uploading.NewLocationContext(ctx, locator)
 		})
 
 		When("failed parse form file", func() {
@@ -917,12 +1108,6 @@ var _ = Describe("Handler Package", func() {
 					Debug("Returning function: UploadFileHandler").
 					Times(1)
 
-				locator.
-					EXPECT().
-					GetLocation().
-					Return("mock/location").
-					Times(1)
-
 				uploadService.
 					EXPECT().
 					UploadFile(gomock.Eq(ctx), gomock.Any()).
@@ -943,8 +1128,8 @@ var _ = Describe("Handler Package", func() {
 			})
 		})
 
-		When("success upload file", func() {
-			It("should return result", func() {
+		When("file is too large", func() {
+			It("should return error", func() {
 				log.
 					EXPECT().
 					Debug("In function: UploadFileHandler").
@@ -954,25 +1139,41 @@ var _ = Describe("Handler Package", func() {
 					Debug("Returning function: UploadFileHandler").
 					Times(1)
 
-				locator.
+				uploadService.
 					EXPECT().
-					GetLocation().
-					Return("mock/location").
+					UploadFile(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, uploading.ErrorFileTooLarge).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(413))
+				Expect(resBody.Code).To(Equal("TOO_LARGE"))
+				Expect(resBody.Message).To(Equal(uploading.ErrorFileTooLarge.Error()))
+				Expect(resBody.Data).To(BeNil())
+			})
+		})
+
+		When("mimetype is not allowed", func() {
+			It("should return error", func() {
+				log.
+					EXPECT().
+					Debug("In function: UploadFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: UploadFileHandler").
 					Times(1)
 
-				uploadRes := &uploading.UploadFileResult{
-					UniqueId:   "mock-unique-id",
-					Name:       "dolpin.jpg",
-					Path:       "mock/location/mock-unique-id.jpg",
-					Mimetype:   "image/jpeg",
-					Extension:  "jpg",
-					Size:       200,
-					UploadedAt: currentTimestamp,
-				}
 				uploadService.
 					EXPECT().
 					UploadFile(gomock.Eq(ctx), gomock.Any()).
-					Return(uploadRes, nil).
+					Return(nil, uploading.ErrorMimetypeNotAllowed).
 					Times(1)
 
 				w := httptest.NewRecorder()
@@ -981,20 +1182,3141 @@ var _ = Describe("Handler Package", func() {
 
 				resBody := rest_app.ResponseBody{}
 				serializer.Unmarshal(w.Body.Bytes(), &resBody)
-				data := map[string]interface{}{
-					"id":          uploadRes.UniqueId,
-					"name":        uploadRes.Name,
-					"mimetype":    uploadRes.Mimetype,
-					"extension":   uploadRes.Extension,
-					"size":        float64(200),
-					"uploaded_at": float64(uploadRes.UploadedAt.UnixMilli()),
-				}
 
-				Expect(w.Code).To(Equal(200))
-				Expect(resBody.Code).To(Equal("SUCCESS"))
-				Expect(resBody.Message).To(Equal("success upload file"))
-				Expect(resBody.Data).To(Equal(data))
+				Expect(w.Code).To(Equal(415))
+				Expect(resBody.Code).To(Equal("UNSUPPORTED_MEDIA"))
+				Expect(resBody.Message).To(Equal(uploading.ErrorMimetypeNotAllowed.Error()))
+				Expect(resBody.Data).To(BeNil())
 			})
 		})
-	})
-})
+
+		When("file extension does not match", func() {
+			It("should return error", func() {
+				log.
+					EXPECT().
+					Debug("In function: UploadFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: UploadFileHandler").
+					Times(1)
+
+				uploadService.
+					EXPECT().
+					UploadFile(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, uploading.ErrorExtensionMismatch).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(415))
+				Expect(resBody.Code).To(Equal("UNSUPPORTED_MEDIA"))
+				Expect(resBody.Message).To(Equal(uploading.ErrorExtensionMismatch.Error()))
+				Expect(resBody.Data).To(BeNil())
+			})
+		})
+
+		When("file is infected", func() {
+			It("should return error", func() {
+				log.
+					EXPECT().
+					Debug("In function: UploadFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: UploadFileHandler").
+					Times(1)
+
+				uploadService.
+					EXPECT().
+					UploadFile(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, uploading.ErrorFileInfected).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(422))
+				Expect(resBody.Code).To(Equal("INFECTED"))
+				Expect(resBody.Message).To(Equal(uploading.ErrorFileInfected.Error()))
+				Expect(resBody.Data).To(BeNil())
+			})
+		})
+
+		When("failed scan file", func() {
+			It("should return error", func() {
+				log.
+					EXPECT().
+					Debug("In function: UploadFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: UploadFileHandler").
+					Times(1)
+
+				uploadService.
+					EXPECT().
+					UploadFile(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, uploading.ErrorScanFailed).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(503))
+				Expect(resBody.Code).To(Equal("UNAVAILABLE"))
+				Expect(resBody.Message).To(Equal(uploading.ErrorScanFailed.Error()))
+				Expect(resBody.Data).To(BeNil())
+			})
+		})
+
+		When("quota is exceeded", func() {
+			It("should return error", func() {
+				log.
+					EXPECT().
+					Debug("In function: UploadFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: UploadFileHandler").
+					Times(1)
+
+				uploadService.
+					EXPECT().
+					UploadFile(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, uploading.ErrorQuotaExceeded).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(507))
+				Expect(resBody.Code).To(Equal("QUOTA_EXCEEDED"))
+				Expect(resBody.Message).To(Equal(uploading.ErrorQuotaExceeded.Error()))
+				Expect(resBody.Data).To(BeNil())
+			})
+		})
+
+		When("success upload file", func() {
+			It("should return result", func() {
+				log.
+					EXPECT().
+					Debug("In function: UploadFileHandler").
+					Times(1)
+				log.
+					EXPECT().
+					Debug("Returning function: UploadFileHandler").
+					Times(1)
+
+				uploadRes := &uploading.UploadFileResult{
+					UniqueId:  "mock-unique-id",
+					Name:      "dolpin.jpg",
+					Path:      "mock/location/mock-unique-id.jpg",
+					Mimetype:  "image/jpeg",
+					Extension: "jpg",
+					Size:      200,
+					Attributes: map[string]string{
+						"width":  "720",
+						"height": "480",
+					},
+					ScanStatus: "clean",
+					Visibility: "private",
+					UploadedAt: currentTimestamp,
+				}
+				uploadService.
+					EXPECT().
+					UploadFile(gomock.Eq(ctx), gomock.Any()).
+					Return(uploadRes, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+				data := map[string]interface{}{
+					"id":        uploadRes.UniqueId,
+					"name":      uploadRes.Name,
+					"mimetype":  uploadRes.Mimetype,
+					"extension": uploadRes.Extension,
+					"size":      float64(200),
+					"attributes": map[string]interface{}{
+						"width":  "720",
+						"height": "480",
+					},
+					"scan_status": "clean",
+					"visibility":  "private",
+					"uploaded_at": float64(uploadRes.UploadedAt.UnixMilli()),
+				}
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Code).To(Equal("SUCCESS"))
+				Expect(resBody.Message).To(Equal("success upload file"))
+				Expect(resBody.Data).To(Equal(data))
+			})
+		})
+	})
+
+	Context("NewUploadFileHandler with signed body", Label("unit"), func() {
+		var (
+			r             *http.Request
+			handler       http.Handler
+			log           *mock.MockLogger
+			serializer    serialization.Serializer
+			signatureAuth *mock.MockSignatureAuth
+			uploadService *mock.MockUploader
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			signatureAuth = mock.NewMockSignatureAuth(ctrl)
+			uploadService = mock.NewMockUploader(ctrl)
+			handler = rest_app.NewSignatureAuthMiddleware(signatureAuth, serializer, nil)(
+				rest_app.NewUploadFileHandler(
+					log, serializer, uploadService,
+					mock.NewMockUploadLocation(ctrl), &rest_app.RestAppConfig{UploadFormSize: 1024},
+				),
+			)
+
+			multipartBody := func(content string) (*bytes.Buffer, string) {
+				body := new(bytes.Buffer)
+				writer := multipart.NewWriter(body)
+				writer.SetBoundary("mock-boundary")
+				part, _ := writer.CreateFormFile("file", "dolphin.txt")
+				part.Write([]byte(content))
+				writer.Close()
+				return body, writer.FormDataContentType()
+			}
+			signedBody, _ := multipartBody("original content")
+			sum := sha256.Sum256(signedBody.Bytes())
+			tamperedBody, contentType := multipartBody("tampered content")
+
+			r = httptest.NewRequest(http.MethodPost, "/file", tamperedBody)
+			r.Header.Set("Content-Type", contentType)
+			r.Header.Set("Authorization", "HMAC-SHA256 Credential=client-id/secret-id, Signature=abc")
+			r.Header.Set("X-Content-Sha256", hex.EncodeToString(sum[:]))
+
+			signatureAuth.
+				EXPECT().
+				ParseAuthorization(gomock.Any(), gomock.Any()).
+				Return(&auth.ParseAuthorizationResult{ClientId: "client-id", SecretId: "secret-id", Signature: "abc"}, nil).
+				Times(1)
+			signatureAuth.
+				EXPECT().
+				VerifySignature(gomock.Any(), gomock.Any()).
+				Return(&auth.VerifySignatureResult{ClientId: "client-id", SecretId: "secret-id"}, nil).
+				Times(1)
+			log.
+				EXPECT().
+				Debug("In function: UploadFileHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UploadFileHandler").
+				Times(1)
+		})
+
+		When("file content is tampered", func() {
+			It("should reject the upload", func() {
+				uploadService.
+					EXPECT().
+					UploadFile(gomock.Any(), gomock.Any()).
+					Times(0)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Code).To(Equal("UNAUTHORIZED"))
+				Expect(resBody.Message).To(Equal("request body does not match the signed hash"))
+			})
+		})
+	})
+
+	Context("NewRetrievePublicFileHandler", Label("unit"), func() {
+		var (
+			ctx             context.Context
+			handler         http.HandlerFunc
+			r               *http.Request
+			log             *mock.MockLogger
+			serializer      serialization.Serializer
+			retrieveService *mock.MockRetriever
+			p               retrieving.RetrieveFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			r = httptest.NewRequest(http.MethodGet, "/public/file/mock-file-id", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"id": "mock-file-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			retrieveService = mock.NewMockRetriever(ctrl)
+			handler = rest_app.NewRetrievePublicFileHandler(log, serializer, retrieveService)
+			p = retrieving.RetrieveFileParam{
+				FileId:     "mock-file-id",
+				PublicOnly: true,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: RetrievePublicFileHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: RetrievePublicFileHandler").
+				Times(1)
+		})
+
+		When("file is not public", func() {
+			It("should return error", func() {
+				retrieveService.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, retrieving.ErrorResourceNotFound).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(404))
+				Expect(w.Header().Get("Cache-Control")).To(Equal(""))
+				Expect(resBody.Code).To(Equal("NOT_FOUND"))
+			})
+		})
+
+		When("success retrieve public file", func() {
+			It("should return cacheable result", func() {
+				retrieveService.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&retrieving.RetrieveFileResult{
+						Data:     newSeekableFile("mock-data"),
+						UniqueId: "mock-file-id",
+						MimeType: "text/plain",
+					}, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Cache-Control")).To(Equal(rest_app.PUBLIC_CACHE_CONTROL))
+				Expect(w.Header().Get("Content-Type")).To(Equal("text/plain"))
+				Expect(w.Body.String()).To(Equal("mock-data"))
+			})
+		})
+	})
+
+	Context("NewUpdateVisibilityHandler", Label("unit"), func() {
+		var (
+			ctx          context.Context
+			currentTs    time.Time
+			handler      http.HandlerFunc
+			r            *http.Request
+			log          *mock.MockLogger
+			serializer   serialization.Serializer
+			shareService *mock.MockSharer
+			p            sharing.UpdateVisibilityParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			body := bytes.NewBufferString(`{"visibility":"public"}`)
+			r = httptest.NewRequest(http.MethodPut, "/file/mock-file-id/visibility", body)
+			r = mux.SetURLVars(r, map[string]string{
+				"id": "mock-file-id",
+			})
+			r = r.WithContext(auth.NewClientContext(r.Context(), "owner-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			shareService = mock.NewMockSharer(ctrl)
+			handler = rest_app.NewUpdateVisibilityHandler(log, serializer, shareService)
+			p = sharing.UpdateVisibilityParam{
+				FileId:     "mock-file-id",
+				OwnerId:    "owner-id",
+				Visibility: "public",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UpdateVisibilityHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UpdateVisibilityHandler").
+				Times(1)
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r.Body = io.NopCloser(bytes.NewBufferString("["))
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid request body"))
+			})
+		})
+
+		When("client is not the owner", func() {
+			It("should return error", func() {
+				shareService.
+					EXPECT().
+					UpdateVisibility(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, sharing.ErrorForbidden).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(403))
+				Expect(resBody.Code).To(Equal("FORBIDDEN"))
+			})
+		})
+
+		When("success update visibility", func() {
+			It("should return result", func() {
+				shareService.
+					EXPECT().
+					UpdateVisibility(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&sharing.UpdateVisibilityResult{UpdatedAt: currentTs}, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Code).To(Equal("SUCCESS"))
+				Expect(resBody.Message).To(Equal("success update visibility"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"file_id":    "mock-file-id",
+					"visibility": "public",
+					"updated_at": float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewRetrieveUsageHandler", Label("unit"), func() {
+		var (
+			ctx          context.Context
+			handler      http.HandlerFunc
+			r            *http.Request
+			log          *mock.MockLogger
+			serializer   serialization.Serializer
+			quotaService *mock.MockQuota
+			p            quota.RetrieveUsageParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			r = httptest.NewRequest(http.MethodGet, "/usage", nil)
+			r = r.WithContext(auth.NewClientContext(r.Context(), "mock-client-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			quotaService = mock.NewMockQuota(ctrl)
+			handler = rest_app.NewRetrieveUsageHandler(log, serializer, quotaService)
+			p = quota.RetrieveUsageParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: RetrieveUsageHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: RetrieveUsageHandler").
+				Times(1)
+		})
+
+		When("failed retrieve usage", func() {
+			It("should return error", func() {
+				quotaService.
+					EXPECT().
+					RetrieveUsage(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Code).To(Equal("ERROR"))
+				Expect(resBody.Message).To(Equal("db error"))
+			})
+		})
+
+		When("success retrieve usage", func() {
+			It("should return result", func() {
+				quotaService.
+					EXPECT().
+					RetrieveUsage(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&quota.RetrieveUsageResult{
+						ClientId: "mock-client-id",
+						UsedSize: 1024,
+						UsedFile: 3,
+						MaxSize:  2048,
+						MaxFile:  10,
+					}, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Code).To(Equal("SUCCESS"))
+				Expect(resBody.Message).To(Equal("success retrieve usage"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id": "mock-client-id",
+					"used_size": float64(1024),
+					"used_file": float64(3),
+					"max_size":  float64(2048),
+					"max_file":  float64(10),
+				}))
+			})
+		})
+	})
+
+	Context("NewShareFileHandler", Label("unit"), func() {
+		var (
+			ctx          context.Context
+			currentTs    time.Time
+			handler      http.HandlerFunc
+			r            *http.Request
+			log          *mock.MockLogger
+			serializer   serialization.Serializer
+			shareService *mock.MockSharer
+			p            sharing.ShareFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			body := bytes.NewBufferString(`{"client_id":"client-id","permission":"read"}`)
+			r = httptest.NewRequest(http.MethodPost, "/file/mock-file-id/share", body)
+			r = mux.SetURLVars(r, map[string]string{
+				"id": "mock-file-id",
+			})
+			r = r.WithContext(auth.NewClientContext(r.Context(), "owner-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			shareService = mock.NewMockSharer(ctrl)
+			handler = rest_app.NewShareFileHandler(log, serializer, shareService)
+			p = sharing.ShareFileParam{
+				FileId:     "mock-file-id",
+				OwnerId:    "owner-id",
+				ClientId:   "client-id",
+				Permission: "read",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ShareFileHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ShareFileHandler").
+				Times(1)
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r.Body = io.NopCloser(bytes.NewBufferString("{"))
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Code).To(Equal("ERROR"))
+				Expect(resBody.Message).To(Equal("invalid request body"))
+			})
+		})
+
+		When("file is not found", func() {
+			It("should return error", func() {
+				shareService.
+					EXPECT().
+					ShareFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, sharing.ErrorResourceNotFound).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(404))
+				Expect(resBody.Code).To(Equal("NOT_FOUND"))
+			})
+		})
+
+		When("client is not the owner", func() {
+			It("should return error", func() {
+				shareService.
+					EXPECT().
+					ShareFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, sharing.ErrorForbidden).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(403))
+				Expect(resBody.Code).To(Equal("FORBIDDEN"))
+			})
+		})
+
+		When("failed share file", func() {
+			It("should return error", func() {
+				shareService.
+					EXPECT().
+					ShareFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Code).To(Equal("ERROR"))
+				Expect(resBody.Message).To(Equal("db error"))
+			})
+		})
+
+		When("success share file", func() {
+			It("should return result", func() {
+				shareService.
+					EXPECT().
+					ShareFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&sharing.ShareFileResult{SharedAt: currentTs}, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Code).To(Equal("SUCCESS"))
+				Expect(resBody.Message).To(Equal("success share file"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"file_id":    "mock-file-id",
+					"client_id":  "client-id",
+					"permission": "read",
+					"shared_at":  float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewUnshareFileHandler", Label("unit"), func() {
+		var (
+			ctx          context.Context
+			currentTs    time.Time
+			handler      http.HandlerFunc
+			r            *http.Request
+			log          *mock.MockLogger
+			serializer   serialization.Serializer
+			shareService *mock.MockSharer
+			p            sharing.UnshareFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodDelete, "/file/mock-file-id/share/client-id", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"id":        "mock-file-id",
+				"client_id": "client-id",
+			})
+			r = r.WithContext(auth.NewClientContext(r.Context(), "owner-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			shareService = mock.NewMockSharer(ctrl)
+			handler = rest_app.NewUnshareFileHandler(log, serializer, shareService)
+			p = sharing.UnshareFileParam{
+				FileId:   "mock-file-id",
+				OwnerId:  "owner-id",
+				ClientId: "client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UnshareFileHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UnshareFileHandler").
+				Times(1)
+		})
+
+		When("share is not found", func() {
+			It("should return error", func() {
+				shareService.
+					EXPECT().
+					UnshareFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, sharing.ErrorResourceNotFound).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(404))
+				Expect(resBody.Code).To(Equal("NOT_FOUND"))
+			})
+		})
+
+		When("success unshare file", func() {
+			It("should return result", func() {
+				shareService.
+					EXPECT().
+					UnshareFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&sharing.UnshareFileResult{RevokedAt: currentTs}, nil).
+					Times(1)
+
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Code).To(Equal("SUCCESS"))
+				Expect(resBody.Message).To(Equal("success unshare file"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"revoked_at": float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewIssueTokenHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			handler    http.HandlerFunc
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			tokenAuth  *mock.MockTokenAuth
+			form       url.Values
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			tokenAuth = mock.NewMockTokenAuth(ctrl)
+			handler = rest_app.NewIssueTokenHandler(log, serializer, tokenAuth, nil)
+			form = url.Values{
+				"grant_type": []string{"client_credentials"},
+				"scope":      []string{"file:read file:write"},
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: IssueTokenHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: IssueTokenHandler").
+				Times(1)
+		})
+
+		newRequest := func(form url.Values) *http.Request {
+			r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return r
+		}
+
+		When("grant type is not specified", func() {
+			It("should return error", func() {
+				form.Del("grant_type")
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, newRequest(form))
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody["error"]).To(Equal("invalid_request"))
+			})
+		})
+
+		When("grant type is not supported", func() {
+			It("should return error", func() {
+				form.Set("grant_type", "password")
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, newRequest(form))
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody["error"]).To(Equal("unsupported_grant_type"))
+			})
+		})
+
+		When("client credential is invalid", func() {
+			It("should return error", func() {
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Eq(auth.IssueTokenParam{
+						ClientId:     "client-id",
+						ClientSecret: "wrong-secret",
+						Scopes:       []string{"file:read", "file:write"},
+						IpAddress:    "192.0.2.1",
+					})).
+					Return(nil, auth.ErrorInvalidClient).
+					Times(1)
+				r := newRequest(form)
+				r.SetBasicAuth("client-id", "wrong-secret")
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(w.Header().Get("WWW-Authenticate")).To(Equal(`Basic realm="local"`))
+				Expect(resBody["error"]).To(Equal("invalid_client"))
+			})
+		})
+
+		When("requested scope is not granted", func() {
+			It("should return error", func() {
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, auth.ErrorInvalidScope).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, newRequest(form))
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody["error"]).To(Equal("invalid_scope"))
+			})
+		})
+
+		When("ip address is not allowed", func() {
+			It("should return error", func() {
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, auth.ErrorAddressNotAllowed).
+					Times(1)
+				log.
+					EXPECT().
+					WithFields(gomock.Eq(map[string]interface{}{
+						"event":      "auth.ip_denied",
+						"client_id":  "client-id",
+						"ip_address": "192.0.2.1",
+						"path":       "/oauth/token",
+					})).
+					Return(log).
+					Times(1)
+				log.
+					EXPECT().
+					Warn(gomock.Eq("Request is denied by the client allowlist")).
+					Times(1)
+				r := newRequest(form)
+				r.SetBasicAuth("client-id", "client-secret")
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(403))
+				Expect(resBody["error"]).To(Equal("access_denied"))
+			})
+		})
+
+		When("failed issue token", func() {
+			It("should return error", func() {
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, newRequest(form))
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(500))
+				Expect(resBody["error"]).To(Equal("server_error"))
+			})
+		})
+
+		When("client credential is specified in the body", func() {
+			It("should return token", func() {
+				form.Set("client_id", "client-id")
+				form.Set("client_secret", "client-secret")
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Eq(auth.IssueTokenParam{
+						ClientId:     "client-id",
+						ClientSecret: "client-secret",
+						Scopes:       []string{"file:read", "file:write"},
+						IpAddress:    "192.0.2.1",
+					})).
+					Return(&auth.IssueTokenResult{
+						AccessToken: "access-token",
+						TokenType:   "Bearer",
+						Scopes:      []string{"file:read", "file:write"},
+						ExpiresIn:   time.Hour,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, newRequest(form))
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Cache-Control")).To(Equal("no-store"))
+				Expect(resBody).To(Equal(map[string]interface{}{
+					"access_token": "access-token",
+					"token_type":   "Bearer",
+					"expires_in":   float64(3600),
+					"scope":        "file:read file:write",
+				}))
+			})
+		})
+	})
+
+	Context("NewIssueTokenHandler with lockout", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			handler    http.HandlerFunc
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			tokenAuth  *mock.MockTokenAuth
+			l          *mock.MockLockout
+			r          *http.Request
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			tokenAuth = mock.NewMockTokenAuth(ctrl)
+			l = mock.NewMockLockout(ctrl)
+			handler = rest_app.NewIssueTokenHandler(log, serializer, tokenAuth, l)
+			form := url.Values{
+				"grant_type": []string{"client_credentials"},
+			}
+			r = httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.SetBasicAuth("client-id", "client-secret")
+
+			log.
+				EXPECT().
+				Debug("In function: IssueTokenHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: IssueTokenHandler").
+				Times(1)
+		})
+
+		When("client is locked", func() {
+			It("should return error", func() {
+				l.
+					EXPECT().
+					Check(gomock.Eq(ctx), gomock.Eq(auth.CheckLockoutParam{
+						ClientId:  "client-id",
+						IpAddress: "192.0.2.1",
+					})).
+					Return(&auth.CheckLockoutResult{
+						Locked:     true,
+						RetryAfter: 30 * time.Second,
+					}, nil).
+					Times(1)
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Any(), gomock.Any()).
+					Times(0)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("30"))
+				Expect(resBody["error"]).To(Equal("invalid_client"))
+			})
+		})
+
+		When("failed attempt locks the client", func() {
+			It("should return error", func() {
+				l.
+					EXPECT().
+					Check(gomock.Eq(ctx), gomock.Any()).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, auth.ErrorInvalidClient).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Eq(ctx), gomock.Eq(auth.RecordFailureParam{
+						ClientId:  "client-id",
+						IpAddress: "192.0.2.1",
+					})).
+					Return(&auth.RecordFailureResult{
+						Locked:     true,
+						RetryAfter: time.Minute,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("60"))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should only record the ip address failure", func() {
+				l.
+					EXPECT().
+					Check(gomock.Eq(ctx), gomock.Any()).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, auth.ErrorUnknownClient).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Eq(ctx), gomock.Eq(auth.RecordFailureParam{
+						ClientId:  "",
+						IpAddress: "192.0.2.1",
+					})).
+					Return(&auth.RecordFailureResult{}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := map[string]interface{}{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody["error"]).To(Equal("invalid_client"))
+			})
+		})
+
+		When("failed attempt does not lock the client", func() {
+			It("should return error", func() {
+				l.
+					EXPECT().
+					Check(gomock.Eq(ctx), gomock.Any()).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, auth.ErrorInvalidClient).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Eq(ctx), gomock.Any()).
+					Return(&auth.RecordFailureResult{}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(401))
+			})
+		})
+
+		When("token is issued", func() {
+			It("should record the success", func() {
+				l.
+					EXPECT().
+					Check(gomock.Eq(ctx), gomock.Any()).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				tokenAuth.
+					EXPECT().
+					IssueToken(gomock.Eq(ctx), gomock.Any()).
+					Return(&auth.IssueTokenResult{
+						AccessToken: "access-token",
+						TokenType:   "Bearer",
+						ExpiresIn:   time.Hour,
+					}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					RecordSuccess(gomock.Eq(ctx), gomock.Eq(auth.RecordSuccessParam{
+						ClientId:  "client-id",
+						IpAddress: "192.0.2.1",
+					})).
+					Return(nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+			})
+		})
+	})
+
+	Context("NewCreateClientHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.CreateClientParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			body := bytes.NewBufferString(`{"name":"frontend","scopes":["file:read"]}`)
+			r = httptest.NewRequest(http.MethodPost, "/client", body)
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewCreateClientHandler(log, serializer, manager)
+			p = managing.CreateClientParam{
+				Name:   "frontend",
+				Scopes: []string{"file:read"},
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: CreateClientHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: CreateClientHandler").
+				Times(1)
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r.Body = io.NopCloser(bytes.NewBufferString("{"))
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid request body"))
+			})
+		})
+
+		When("failed create client", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					CreateClient(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("invalid scope parameter")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Code).To(Equal("ERROR"))
+				Expect(resBody.Message).To(Equal("invalid scope parameter"))
+			})
+		})
+
+		When("success create client", func() {
+			It("should return the secret", func() {
+				manager.
+					EXPECT().
+					CreateClient(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.CreateClientResult{
+						Id:           "mock-id",
+						Name:         "frontend",
+						ClientId:     "mock-client-id",
+						ClientSecret: "plain-secret",
+						SecretId:     "mock-secret-id",
+						SigningKey:   "mock-signing-key",
+						Scopes:       []string{"file:read"},
+						CreatedAt:    currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Cache-Control")).To(Equal("no-store"))
+				Expect(resBody.Message).To(Equal("success create client"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"id":            "mock-id",
+					"name":          "frontend",
+					"client_id":     "mock-client-id",
+					"client_secret": "plain-secret",
+					"secret_id":     "mock-secret-id",
+					"signing_key":   "mock-signing-key",
+					"scopes":        []interface{}{"file:read"},
+					"created_at":    float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewListClientHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.UnixMilli(1660000000000)
+			r = httptest.NewRequest(http.MethodGet, "/client?after_id=mock-after-id&limit=10", nil)
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewListClientHandler(log, serializer, manager)
+
+			log.
+				EXPECT().
+				Debug("In function: ListClientHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ListClientHandler").
+				Times(1)
+		})
+
+		When("limit is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodGet, "/client?limit=ten", nil)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid limit parameter"))
+			})
+		})
+
+		When("failed list client", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					ListClient(gomock.Eq(ctx), gomock.Eq(managing.ListClientParam{
+						AfterId: "mock-after-id",
+						Limit:   10,
+					})).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(400))
+			})
+		})
+
+		When("success list client", func() {
+			It("should return result", func() {
+				manager.
+					EXPECT().
+					ListClient(gomock.Eq(ctx), gomock.Eq(managing.ListClientParam{
+						AfterId: "mock-after-id",
+						Limit:   10,
+					})).
+					Return(&managing.ListClientResult{
+						Items: []managing.ClientItem{
+							{
+								Id:        "mock-id-1",
+								Name:      "frontend",
+								ClientId:  "mock-client-id-1",
+								Scopes:    []string{"file:read"},
+								CreatedAt: currentTs,
+								UpdatedAt: currentTs,
+							},
+							{
+								Id:           "mock-id-2",
+								Name:         "backoffice",
+								ClientId:     "mock-client-id-2",
+								Scopes:       []string{"file:delete"},
+								AllowedCidrs: []string{"10.0.0.0/8"},
+								DisabledAt:   &currentTs,
+								CreatedAt:    currentTs,
+								UpdatedAt:    currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal([]interface{}{
+					map[string]interface{}{
+						"id":            "mock-id-1",
+						"name":          "frontend",
+						"client_id":     "mock-client-id-1",
+						"scopes":        []interface{}{"file:read"},
+						"allowed_cidrs": []interface{}{},
+						"disabled_at":   nil,
+						"created_at":    float64(1660000000000),
+						"updated_at":    float64(1660000000000),
+					},
+					map[string]interface{}{
+						"id":            "mock-id-2",
+						"name":          "backoffice",
+						"client_id":     "mock-client-id-2",
+						"scopes":        []interface{}{"file:delete"},
+						"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+						"disabled_at":   float64(1660000000000),
+						"created_at":    float64(1660000000000),
+						"updated_at":    float64(1660000000000),
+					},
+				}))
+			})
+		})
+	})
+
+	Context("NewRotateSecretHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.RotateSecretParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodPost, "/client/mock-client-id/secret", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewRotateSecretHandler(log, serializer, manager)
+			p = managing.RotateSecretParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: RotateSecretHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: RotateSecretHandler").
+				Times(1)
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					RotateSecret(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, managing.ErrorResourceNotFound).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(404))
+				Expect(resBody.Code).To(Equal("NOT_FOUND"))
+			})
+		})
+
+		When("success rotate secret", func() {
+			It("should return the secret", func() {
+				manager.
+					EXPECT().
+					RotateSecret(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.RotateSecretResult{
+						ClientId:          "mock-client-id",
+						SecretId:          "mock-secret-id",
+						ClientSecret:      "plain-secret",
+						RotatedAt:         currentTs,
+						PreviousExpiresAt: currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Cache-Control")).To(Equal("no-store"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id":           "mock-client-id",
+					"secret_id":           "mock-secret-id",
+					"client_secret":       "plain-secret",
+					"rotated_at":          float64(currentTs.UnixMilli()),
+					"previous_expires_at": float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodPost, "/client/mock-client-id/secret", strings.NewReader("{"))
+				r = mux.SetURLVars(r, map[string]string{
+					"client_id": "mock-client-id",
+				})
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(400))
+			})
+		})
+
+		When("grace period is specified", func() {
+			It("should pass the grace period", func() {
+				r = httptest.NewRequest(http.MethodPost, "/client/mock-client-id/secret", strings.NewReader(`{"label":"ci","expires_in":3600,"grace_period":86400}`))
+				r = mux.SetURLVars(r, map[string]string{
+					"client_id": "mock-client-id",
+				})
+				manager.
+					EXPECT().
+					RotateSecret(gomock.Eq(ctx), gomock.Eq(managing.RotateSecretParam{
+						ClientId:    "mock-client-id",
+						Label:       "ci",
+						ExpiresIn:   time.Hour,
+						GracePeriod: 24 * time.Hour,
+					})).
+					Return(&managing.RotateSecretResult{
+						ClientId:          "mock-client-id",
+						SecretId:          "mock-secret-id",
+						ClientSecret:      "plain-secret",
+						RotatedAt:         currentTs,
+						PreviousExpiresAt: currentTs.Add(24 * time.Hour),
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+			})
+		})
+	})
+
+	Context("NewListSecretHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.ListSecretParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodGet, "/client/mock-client-id/secret", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewListSecretHandler(log, serializer, manager)
+			p = managing.ListSecretParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ListSecretHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ListSecretHandler").
+				Times(1)
+		})
+
+		When("failed list secret", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					ListSecret(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("invalid client id parameter")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(400))
+			})
+		})
+
+		When("success list secret", func() {
+			It("should return result", func() {
+				manager.
+					EXPECT().
+					ListSecret(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.ListSecretResult{
+						Items: []managing.SecretItem{
+							{
+								Id:         "mock-secret-id",
+								Label:      "default",
+								CreatedAt:  currentTs,
+								LastUsedAt: &currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal([]interface{}{
+					map[string]interface{}{
+						"id":           "mock-secret-id",
+						"label":        "default",
+						"created_at":   float64(currentTs.UnixMilli()),
+						"expires_at":   nil,
+						"last_used_at": float64(currentTs.UnixMilli()),
+					},
+				}))
+			})
+		})
+	})
+
+	Context("NewRevokeSecretHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.RevokeSecretParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodDelete, "/client/mock-client-id/secret/mock-secret-id", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+				"secret_id": "mock-secret-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewRevokeSecretHandler(log, serializer, manager)
+			p = managing.RevokeSecretParam{
+				ClientId: "mock-client-id",
+				SecretId: "mock-secret-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: RevokeSecretHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: RevokeSecretHandler").
+				Times(1)
+		})
+
+		When("secret is not found", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					RevokeSecret(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, managing.ErrorResourceNotFound).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(404))
+			})
+		})
+
+		When("success revoke secret", func() {
+			It("should return result", func() {
+				manager.
+					EXPECT().
+					RevokeSecret(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.RevokeSecretResult{
+						RevokedAt: currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id":  "mock-client-id",
+					"secret_id":  "mock-secret-id",
+					"revoked_at": float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewDisableClientHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.DisableClientParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodPost, "/client/mock-client-id/disable", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewDisableClientHandler(log, serializer, manager)
+			p = managing.DisableClientParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: DisableClientHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: DisableClientHandler").
+				Times(1)
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					DisableClient(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, managing.ErrorResourceNotFound).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(404))
+			})
+		})
+
+		When("success disable client", func() {
+			It("should return result", func() {
+				manager.
+					EXPECT().
+					DisableClient(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.DisableClientResult{
+						DisabledAt: currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id":   "mock-client-id",
+					"disabled_at": float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewUpdateAllowlistHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.UpdateAllowlistParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodPut, "/client/mock-client-id/allowlist", strings.NewReader(`{"allowed_cidrs":["10.0.0.0/8"]}`))
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewUpdateAllowlistHandler(log, serializer, manager)
+			p = managing.UpdateAllowlistParam{
+				ClientId:     "mock-client-id",
+				AllowedCidrs: []string{"10.0.0.0/8"},
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UpdateAllowlistHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UpdateAllowlistHandler").
+				Times(1)
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodPut, "/client/mock-client-id/allowlist", strings.NewReader(`{"allowed_cidrs":"10.0.0.0/8"}`))
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid request body"))
+			})
+		})
+
+		When("allowed cidr is invalid", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					UpdateAllowlist(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("invalid allowed cidrs parameter")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid allowed cidrs parameter"))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					UpdateAllowlist(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, managing.ErrorResourceNotFound).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(404))
+			})
+		})
+
+		When("success update allowlist", func() {
+			It("should return result", func() {
+				manager.
+					EXPECT().
+					UpdateAllowlist(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.UpdateAllowlistResult{
+						UpdatedAt: currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id":     "mock-client-id",
+					"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+					"updated_at":    float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewUpdateRateLimitHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.UpdateRateLimitParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodPut, "/client/mock-client-id/rate-limit", strings.NewReader(`{"request_rate":50,"byte_rate":null}`))
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewUpdateRateLimitHandler(log, serializer, manager)
+			requestRate := int64(50)
+			p = managing.UpdateRateLimitParam{
+				ClientId:    "mock-client-id",
+				RequestRate: &requestRate,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UpdateRateLimitHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UpdateRateLimitHandler").
+				Times(1)
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodPut, "/client/mock-client-id/rate-limit", strings.NewReader(`{"request_rate":"fast"}`))
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid request body"))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					UpdateRateLimit(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, managing.ErrorResourceNotFound).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(404))
+			})
+		})
+
+		When("success update rate limit", func() {
+			It("should return result", func() {
+				manager.
+					EXPECT().
+					UpdateRateLimit(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.UpdateRateLimitResult{
+						UpdatedAt: currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id":    "mock-client-id",
+					"request_rate": float64(50),
+					"byte_rate":    nil,
+					"updated_at":   float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewUpdateQuotaHandler", Label("unit"), func() {
+		var (
+			ctx          context.Context
+			currentTs    time.Time
+			handler      http.HandlerFunc
+			r            *http.Request
+			log          *mock.MockLogger
+			serializer   serialization.Serializer
+			quotaService *mock.MockQuota
+			p            quota.UpdateQuotaParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodPut, "/client/mock-client-id/quota", strings.NewReader(`{"max_size":2048,"max_file":10}`))
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			quotaService = mock.NewMockQuota(ctrl)
+			handler = rest_app.NewUpdateQuotaHandler(log, serializer, quotaService)
+			p = quota.UpdateQuotaParam{
+				ClientId: "mock-client-id",
+				MaxSize:  2048,
+				MaxFile:  10,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UpdateQuotaHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UpdateQuotaHandler").
+				Times(1)
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodPut, "/client/mock-client-id/quota", strings.NewReader(`{"max_size":"large"}`))
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid request body"))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should return error", func() {
+				quotaService.
+					EXPECT().
+					UpdateQuota(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, quota.ErrorResourceNotFound).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(404))
+			})
+		})
+
+		When("failed update quota", func() {
+			It("should return error", func() {
+				quotaService.
+					EXPECT().
+					UpdateQuota(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("invalid max size parameter")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid max size parameter"))
+			})
+		})
+
+		When("success update quota", func() {
+			It("should return result", func() {
+				quotaService.
+					EXPECT().
+					UpdateQuota(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&quota.UpdateQuotaResult{
+						UpdatedAt: currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id":  "mock-client-id",
+					"max_size":   float64(2048),
+					"max_file":   float64(10),
+					"updated_at": float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewDeleteClientHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			manager    *mock.MockClientManager
+			p          managing.DeleteClientParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			r = httptest.NewRequest(http.MethodDelete, "/client/mock-client-id", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"client_id": "mock-client-id",
+			})
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			manager = mock.NewMockClientManager(ctrl)
+			handler = rest_app.NewDeleteClientHandler(log, serializer, manager)
+			p = managing.DeleteClientParam{
+				ClientId: "mock-client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: DeleteClientHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: DeleteClientHandler").
+				Times(1)
+		})
+
+		When("failed delete client", func() {
+			It("should return error", func() {
+				manager.
+					EXPECT().
+					DeleteClient(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("db error"))
+			})
+		})
+
+		When("success delete client", func() {
+			It("should return result", func() {
+				manager.
+					EXPECT().
+					DeleteClient(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&managing.DeleteClientResult{
+						DeletedAt: currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"client_id":  "mock-client-id",
+					"deleted_at": float64(currentTs.UnixMilli()),
+				}))
+			})
+		})
+	})
+
+	Context("NewCredentialCacheStatsHandler", Label("unit"), func() {
+		var (
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			cache      *mock.MockCredentialCache
+		)
+
+		BeforeEach(func() {
+			r = httptest.NewRequest(http.MethodGet, "/metric/credential-cache", nil)
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			cache = mock.NewMockCredentialCache(ctrl)
+			handler = rest_app.NewCredentialCacheStatsHandler(log, serializer, cache)
+
+			log.
+				EXPECT().
+				Debug("In function: CredentialCacheStatsHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: CredentialCacheStatsHandler").
+				Times(1)
+		})
+
+		When("cache is not used", func() {
+			It("should return zero hit ratio", func() {
+				cache.
+					EXPECT().
+					Stats().
+					Return(auth.CacheStats{}).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"hits":      float64(0),
+					"misses":    float64(0),
+					"evictions": float64(0),
+					"size":      float64(0),
+					"hit_ratio": float64(0),
+				}))
+			})
+		})
+
+		When("cache is used", func() {
+			It("should return the stats", func() {
+				cache.
+					EXPECT().
+					Stats().
+					Return(auth.CacheStats{
+						Hits:      3,
+						Misses:    1,
+						Evictions: 2,
+						Size:      10,
+					}).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"hits":      float64(3),
+					"misses":    float64(1),
+					"evictions": float64(2),
+					"size":      float64(10),
+					"hit_ratio": float64(0.75),
+				}))
+			})
+		})
+	})
+
+	Context("NewListAuditLogHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			auditor    *mock.MockAuditor
+			listParam  auditing.ListLogParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.UnixMilli(1660000000000)
+			r = httptest.NewRequest(http.MethodGet, "/audit-log?client_id=mock-client-id&action=delete&file_id=mock-file-id&outcome=denied&request_id=mock-request-id&from=1660000000000&to=1660086400000&after_id=mock-after-id&limit=10", nil)
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			auditor = mock.NewMockAuditor(ctrl)
+			handler = rest_app.NewListAuditLogHandler(log, serializer, auditor)
+			createdFrom := time.UnixMilli(1660000000000)
+			createdTo := time.UnixMilli(1660086400000)
+			listParam = auditing.ListLogParam{
+				ClientId:    "mock-client-id",
+				Action:      "delete",
+				FileId:      "mock-file-id",
+				Outcome:     "denied",
+				RequestId:   "mock-request-id",
+				CreatedFrom: &createdFrom,
+				CreatedTo:   &createdTo,
+				AfterId:     "mock-after-id",
+				Limit:       10,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ListAuditLogHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ListAuditLogHandler").
+				Times(1)
+		})
+
+		When("limit is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodGet, "/audit-log?limit=ten", nil)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid limit parameter"))
+			})
+		})
+
+		When("from is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodGet, "/audit-log?from=yesterday", nil)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid from parameter"))
+			})
+		})
+
+		When("to is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodGet, "/audit-log?to=today", nil)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid to parameter"))
+			})
+		})
+
+		When("failed list audit log", func() {
+			It("should return error", func() {
+				auditor.
+					EXPECT().
+					ListLog(gomock.Eq(ctx), gomock.Eq(listParam)).
+					Return(nil, fmt.Errorf("invalid outcome parameter")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid outcome parameter"))
+			})
+		})
+
+		When("success list audit log", func() {
+			It("should return result", func() {
+				auditor.
+					EXPECT().
+					ListLog(gomock.Eq(ctx), gomock.Eq(listParam)).
+					Return(&auditing.ListLogResult{
+						Items: []auditing.LogItem{
+							{
+								Id:        "mock-id",
+								ClientId:  "mock-client-id",
+								Action:    "delete",
+								FileId:    "mock-file-id",
+								IpAddress: "10.0.0.1",
+								RequestId: "mock-request-id",
+								Outcome:   "denied",
+								Reason:    "Forbidden",
+								CreatedAt: currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Message).To(Equal("success list audit log"))
+				Expect(resBody.Data).To(Equal([]interface{}{
+					map[string]interface{}{
+						"id":         "mock-id",
+						"client_id":  "mock-client-id",
+						"action":     "delete",
+						"file_id":    "mock-file-id",
+						"ip_address": "10.0.0.1",
+						"request_id": "mock-request-id",
+						"outcome":    "denied",
+						"reason":     "Forbidden",
+						"created_at": float64(1660000000000),
+					},
+				}))
+			})
+		})
+	})
+
+	Context("NewCreateWebhookHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			webhook    *mock.MockWebhook
+			p          notifying.CreateSubscriptionParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.UnixMilli(1660000000000)
+			body := bytes.NewBufferString(`{"url":"https://example.com/hook","event_types":["file.uploaded"]}`)
+			r = httptest.NewRequest(http.MethodPost, "/webhook", body)
+			r = r.WithContext(auth.NewClientContext(r.Context(), "client-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			webhook = mock.NewMockWebhook(ctrl)
+			handler = rest_app.NewCreateWebhookHandler(log, serializer, webhook)
+			p = notifying.CreateSubscriptionParam{
+				ClientId:   "client-id",
+				Url:        "https://example.com/hook",
+				EventTypes: []string{"file.uploaded"},
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: CreateWebhookHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: CreateWebhookHandler").
+				Times(1)
+		})
+
+		When("request body is invalid", func() {
+			It("should return error", func() {
+				r.Body = io.NopCloser(bytes.NewBufferString("{"))
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Code).To(Equal("ERROR"))
+				Expect(resBody.Message).To(Equal("invalid request body"))
+			})
+		})
+
+		When("failed create subscription", func() {
+			It("should return error", func() {
+				webhook.
+					EXPECT().
+					CreateSubscription(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("subscription limit exceeded")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Code).To(Equal("ERROR"))
+				Expect(resBody.Message).To(Equal("subscription limit exceeded"))
+			})
+		})
+
+		When("success create subscription", func() {
+			It("should return result", func() {
+				webhook.
+					EXPECT().
+					CreateSubscription(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&notifying.CreateSubscriptionResult{
+						Id:         "subscription-id",
+						Url:        "https://example.com/hook",
+						EventTypes: []string{"file.uploaded"},
+						Secret:     "secret",
+						CreatedAt:  currentTs,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Code).To(Equal("SUCCESS"))
+				Expect(resBody.Message).To(Equal("success create webhook"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"id":          "subscription-id",
+					"url":         "https://example.com/hook",
+					"event_types": []interface{}{"file.uploaded"},
+					"secret":      "secret",
+					"created_at":  float64(1660000000000),
+				}))
+			})
+		})
+	})
+
+	Context("NewListWebhookHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			webhook    *mock.MockWebhook
+			p          notifying.ListSubscriptionParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.UnixMilli(1660000000000)
+			r = httptest.NewRequest(http.MethodGet, "/webhook", nil)
+			r = r.WithContext(auth.NewClientContext(r.Context(), "client-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			webhook = mock.NewMockWebhook(ctrl)
+			handler = rest_app.NewListWebhookHandler(log, serializer, webhook)
+			p = notifying.ListSubscriptionParam{
+				ClientId: "client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ListWebhookHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ListWebhookHandler").
+				Times(1)
+		})
+
+		When("failed list subscription", func() {
+			It("should return error", func() {
+				webhook.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("db error"))
+			})
+		})
+
+		When("success list subscription", func() {
+			It("should return result", func() {
+				webhook.
+					EXPECT().
+					ListSubscription(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&notifying.ListSubscriptionResult{
+						Items: []notifying.SubscriptionItem{
+							{
+								Id:         "subscription-id",
+								Url:        "https://example.com/hook",
+								EventTypes: []string{"file.uploaded", "file.deleted"},
+								CreatedAt:  currentTs,
+								UpdatedAt:  currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Message).To(Equal("success list webhook"))
+				Expect(resBody.Data).To(Equal([]interface{}{
+					map[string]interface{}{
+						"id":          "subscription-id",
+						"url":         "https://example.com/hook",
+						"event_types": []interface{}{"file.uploaded", "file.deleted"},
+						"created_at":  float64(1660000000000),
+						"updated_at":  float64(1660000000000),
+					},
+				}))
+			})
+		})
+	})
+
+	Context("NewDeleteWebhookHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			webhook    *mock.MockWebhook
+			p          notifying.DeleteSubscriptionParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.UnixMilli(1660000000000)
+			r = httptest.NewRequest(http.MethodDelete, "/webhook/subscription-id", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"id": "subscription-id",
+			})
+			r = r.WithContext(auth.NewClientContext(r.Context(), "client-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			webhook = mock.NewMockWebhook(ctrl)
+			handler = rest_app.NewDeleteWebhookHandler(log, serializer, webhook)
+			p = notifying.DeleteSubscriptionParam{
+				Id:       "subscription-id",
+				ClientId: "client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: DeleteWebhookHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: DeleteWebhookHandler").
+				Times(1)
+		})
+
+		When("subscription is not found", func() {
+			It("should return error", func() {
+				webhook.
+					EXPECT().
+					DeleteSubscription(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, notifying.ErrorResourceNotFound).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(404))
+				Expect(resBody.Code).To(Equal("NOT_FOUND"))
+			})
+		})
+
+		When("failed delete subscription", func() {
+			It("should return error", func() {
+				webhook.
+					EXPECT().
+					DeleteSubscription(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("db error"))
+			})
+		})
+
+		When("success delete subscription", func() {
+			It("should return result", func() {
+				webhook.
+					EXPECT().
+					DeleteSubscription(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&notifying.DeleteSubscriptionResult{DeletedAt: currentTs}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Message).To(Equal("success delete webhook"))
+				Expect(resBody.Data).To(Equal(map[string]interface{}{
+					"deleted_at": float64(1660000000000),
+				}))
+			})
+		})
+	})
+
+	Context("NewListWebhookDeliveryHandler", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			webhook    *mock.MockWebhook
+			p          notifying.ListDeliveryParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.UnixMilli(1660000000000)
+			r = httptest.NewRequest(http.MethodGet, "/webhook/delivery?subscription_id=subscription-id&status=dead&after_id=mock-after-id&limit=10", nil)
+			r = r.WithContext(auth.NewClientContext(r.Context(), "client-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			webhook = mock.NewMockWebhook(ctrl)
+			handler = rest_app.NewListWebhookDeliveryHandler(log, serializer, webhook)
+			p = notifying.ListDeliveryParam{
+				ClientId:       "client-id",
+				SubscriptionId: "subscription-id",
+				Status:         "dead",
+				AfterId:        "mock-after-id",
+				Limit:          10,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ListWebhookDeliveryHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ListWebhookDeliveryHandler").
+				Times(1)
+		})
+
+		When("limit is invalid", func() {
+			It("should return error", func() {
+				r = httptest.NewRequest(http.MethodGet, "/webhook/delivery?limit=ten", nil)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid limit parameter"))
+			})
+		})
+
+		When("failed list delivery", func() {
+			It("should return error", func() {
+				webhook.
+					EXPECT().
+					ListDelivery(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("invalid status parameter")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid status parameter"))
+			})
+		})
+
+		When("success list delivery", func() {
+			It("should return result", func() {
+				webhook.
+					EXPECT().
+					ListDelivery(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&notifying.ListDeliveryResult{
+						Items: []notifying.DeliveryItem{
+							{
+								Id:             "delivery-id",
+								SubscriptionId: "subscription-id",
+								EventId:        "event-id",
+								EventType:      "file.uploaded",
+								Status:         "dead",
+								Attempt:        8,
+								NextAttemptAt:  currentTs,
+								ResponseCode:   500,
+								LastError:      "unexpected response status: 500",
+								CreatedAt:      currentTs,
+								UpdatedAt:      currentTs,
+							},
+							{
+								Id:             "delivery-id-2",
+								SubscriptionId: "subscription-id",
+								EventId:        "event-id-2",
+								EventType:      "file.deleted",
+								Status:         "delivered",
+								Attempt:        1,
+								NextAttemptAt:  currentTs,
+								ResponseCode:   204,
+								CreatedAt:      currentTs,
+								UpdatedAt:      currentTs,
+								DeliveredAt:    &currentTs,
+							},
+						},
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(resBody.Message).To(Equal("success list webhook delivery"))
+				Expect(resBody.Data).To(Equal([]interface{}{
+					map[string]interface{}{
+						"id":              "delivery-id",
+						"subscription_id": "subscription-id",
+						"event_id":        "event-id",
+						"event_type":      "file.uploaded",
+						"status":          "dead",
+						"attempt":         float64(8),
+						"next_attempt_at": float64(1660000000000),
+						"response_code":   float64(500),
+						"last_error":      "unexpected response status: 500",
+						"created_at":      float64(1660000000000),
+						"updated_at":      float64(1660000000000),
+						"delivered_at":    nil,
+					},
+					map[string]interface{}{
+						"id":              "delivery-id-2",
+						"subscription_id": "subscription-id",
+						"event_id":        "event-id-2",
+						"event_type":      "file.deleted",
+						"status":          "delivered",
+						"attempt":         float64(1),
+						"next_attempt_at": float64(1660000000000),
+						"response_code":   float64(204),
+						"last_error":      "",
+						"created_at":      float64(1660000000000),
+						"updated_at":      float64(1660000000000),
+						"delivered_at":    float64(1660000000000),
+					},
+				}))
+			})
+		})
+	})
+
+	Context("NewEventStreamHandler", Label("unit"), func() {
+		var (
+			currentTs  time.Time
+			handler    http.HandlerFunc
+			r          *http.Request
+			log        *mock.MockLogger
+			serializer serialization.Serializer
+			stream     *mock.MockEventStream
+			p          notifying.SubscribeParam
+			event      notifying.Event
+			eventText  string
+		)
+
+		BeforeEach(func() {
+			currentTs = time.UnixMilli(1660000000000)
+			r = httptest.NewRequest(http.MethodGet, "/events", nil)
+			r = r.WithContext(auth.NewClientContext(r.Context(), "client-id"))
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			serializer = serialization.NewJsonSerializer()
+			stream = mock.NewMockEventStream(ctrl)
+			handler = rest_app.NewEventStreamHandler(log, serializer, stream, time.Hour)
+			p = notifying.SubscribeParam{
+				ClientId: "client-id",
+			}
+			event = notifying.Event{
+				Id:       "file.uploaded:file-id",
+				Sequence: 2,
+				Type:     "file.uploaded",
+				ClientId: "client-id",
+				File: notifying.FileData{
+					Id:        "file-id",
+					Name:      "dolphin",
+					Mimetype:  "image/jpeg",
+					Extension: "jpg",
+					Size:      200,
+				},
+				OccurredAt: currentTs,
+			}
+			eventText = "id: 2\nevent: file.uploaded\n" +
+				`data: {"id":"file.uploaded:file-id","type":"file.uploaded","created_at":1660000000000,` +
+				`"data":{"id":"file-id","name":"dolphin","mimetype":"image/jpeg","extension":"jpg","size":200,"client_id":"client-id"}}` +
+				"\n\n"
+
+			log.
+				EXPECT().
+				Debug("In function: EventStreamHandler").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: EventStreamHandler").
+				Times(1)
+		})
+
+		closedEvents := func(events ...notifying.Event) <-chan notifying.Event {
+			ch := make(chan notifying.Event, len(events))
+			for _, e := range events {
+				ch <- e
+			}
+			close(ch)
+			return ch
+		}
+
+		When("streaming is not supported", func() {
+			It("should return error", func() {
+				rec := httptest.NewRecorder()
+				w := struct{ http.ResponseWriter }{rec}
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(rec.Body.Bytes(), &resBody)
+
+				Expect(rec.Code).To(Equal(500))
+				Expect(resBody.Message).To(Equal("streaming is not supported"))
+			})
+		})
+
+		When("last event id is invalid", func() {
+			It("should return error", func() {
+				r.Header.Set("Last-Event-ID", "invalid")
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid last event id"))
+			})
+		})
+
+		When("failed subscribe event stream", func() {
+			It("should return error", func() {
+				stream.
+					EXPECT().
+					Subscribe(gomock.Eq(r.Context()), gomock.Eq(p)).
+					Return(nil, fmt.Errorf("invalid client id parameter")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				resBody := rest_app.ResponseBody{}
+				serializer.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(400))
+				Expect(resBody.Message).To(Equal("invalid client id parameter"))
+			})
+		})
+
+		When("last event id is specified", func() {
+			It("should stream the events after it", func() {
+				r.Header.Set("Last-Event-ID", "1")
+				p.AfterSequence = 1
+				stream.
+					EXPECT().
+					Subscribe(gomock.Eq(r.Context()), gomock.Eq(p)).
+					Return(&notifying.SubscribeResult{
+						Events: closedEvents(event),
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+				Expect(w.Header().Get("Cache-Control")).To(Equal("no-cache"))
+				Expect(w.Body.String()).To(Equal(eventText))
+			})
+		})
+
+		When("last event id is specified in the query", func() {
+			It("should stream the events after it", func() {
+				r = httptest.NewRequest(http.MethodGet, "/events?last_event_id=1", nil)
+				r = r.WithContext(auth.NewClientContext(r.Context(), "client-id"))
+				p.AfterSequence = 1
+				stream.
+					EXPECT().
+					Subscribe(gomock.Eq(r.Context()), gomock.Eq(p)).
+					Return(&notifying.SubscribeResult{
+						Events: closedEvents(event),
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Body.String()).To(Equal(eventText))
+			})
+		})
+
+		When("event is received", func() {
+			It("should stream the event", func() {
+				stream.
+					EXPECT().
+					Subscribe(gomock.Eq(r.Context()), gomock.Eq(p)).
+					Return(&notifying.SubscribeResult{
+						Events: closedEvents(event),
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Body.String()).To(Equal(eventText))
+			})
+		})
+
+		When("no event is received", func() {
+			It("should send heartbeat", func() {
+				handler = rest_app.NewEventStreamHandler(log, serializer, stream, time.Millisecond)
+				events := make(chan notifying.Event)
+				stream.
+					EXPECT().
+					Subscribe(gomock.Eq(r.Context()), gomock.Eq(p)).
+					Return(&notifying.SubscribeResult{
+						Events: events,
+					}, nil).
+					Times(1)
+				time.AfterFunc(50*time.Millisecond, func() {
+					close(events)
+				})
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Body.String()).To(HavePrefix(": heartbeat\n\n"))
+			})
+		})
+
+		When("request is closed", func() {
+			It("should stop streaming", func() {
+				ctx, cancel := context.WithCancel(r.Context())
+				cancel()
+				r = r.WithContext(ctx)
+				stream.
+					EXPECT().
+					Subscribe(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&notifying.SubscribeResult{
+						Events: make(chan notifying.Event),
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+
+				Expect(w.Code).To(Equal(200))
+				Expect(w.Body.String()).To(Equal(""))
+			})
+		})
+	})
+})
+
+type seekableFile struct {
+	*strings.Reader
+}
+
+func (seekableFile) Close() error {
+	return nil
+}
+
+func newSeekableFile(data string) io.ReadSeekCloser {
+	return seekableFile{strings.NewReader(data)}
+}
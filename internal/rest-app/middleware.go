@@ -2,10 +2,21 @@ package rest_app
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/logging"
 	"github.com/go-seidon/local/internal/serialization"
 )
 
@@ -17,7 +28,7 @@ func DefaultHeaderMiddleware(h http.Handler) http.Handler {
 	})
 }
 
-func NewBasicAuthMiddleware(a auth.BasicAuth, s serialization.Serializer) func(h http.Handler) http.Handler {
+func NewBasicAuthMiddleware(a auth.BasicAuth, s serialization.Serializer, l auth.Lockout) func(h http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authTokens := strings.Split(r.Header.Get("Authorization"), "Basic ")
@@ -31,6 +42,27 @@ func NewBasicAuthMiddleware(a auth.BasicAuth, s serialization.Serializer) func(h
 				return
 			}
 
+			clientId := ""
+			ipAddress := RemoteIpAddress(r)
+			if l != nil {
+				// error is ommited since malformed token is rejected by the credential check
+				token, err := a.ParseAuthToken(context.Background(), auth.ParseAuthTokenParam{
+					Token: authTokens[1],
+				})
+				if err == nil {
+					clientId = token.ClientId
+				}
+
+				lRes, err := l.Check(context.Background(), auth.CheckLockoutParam{
+					ClientId:  clientId,
+					IpAddress: ipAddress,
+				})
+				if err == nil && lRes.Locked {
+					writeTooManyRequests(w, s, "too many failed attempts", lRes.RetryAfter)
+					return
+				}
+			}
+
 			res, err := a.CheckCredential(context.Background(), auth.CheckCredentialParam{
 				AuthToken: authTokens[1],
 			})
@@ -44,6 +76,21 @@ func NewBasicAuthMiddleware(a auth.BasicAuth, s serialization.Serializer) func(h
 				return
 			}
 			if !res.TokenValid {
+				if l != nil {
+					// the unknown client is not tracked, otherwise random client ids grow the lockout entries
+					if !res.ClientFound {
+						clientId = ""
+					}
+					fRes, err := l.RecordFailure(context.Background(), auth.RecordFailureParam{
+						ClientId:  clientId,
+						IpAddress: ipAddress,
+					})
+					if err == nil && fRes.Locked {
+						writeTooManyRequests(w, s, "too many failed attempts", fRes.RetryAfter)
+						return
+					}
+				}
+
 				Response(
 					WithWriterSerializer(w, s),
 					WithMessage("credential is invalid"),
@@ -53,7 +100,304 @@ func NewBasicAuthMiddleware(a auth.BasicAuth, s serialization.Serializer) func(h
 				return
 			}
 
+			if l != nil {
+				l.RecordSuccess(context.Background(), auth.RecordSuccessParam{
+					ClientId:  res.ClientId,
+					IpAddress: ipAddress,
+				})
+			}
+
+			ctx := auth.NewClientContext(r.Context(), res.ClientId)
+			ctx = auth.NewScopeContext(ctx, res.Scopes)
+			ctx = auth.NewAllowlistContext(ctx, res.AllowedCidrs)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func NewBearerAuthMiddleware(a auth.TokenAuth, s serialization.Serializer) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authTokens := strings.Split(r.Header.Get("Authorization"), "Bearer ")
+			if len(authTokens) != 2 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="local"`)
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage("credential is not specified"),
+					WithHttpCode(http.StatusUnauthorized),
+					WithCode(CODE_UNAUTHORIZED),
+				)
+				return
+			}
+
+			res, err := a.VerifyToken(context.Background(), auth.VerifyTokenParam{
+				Token: authTokens[1],
+			})
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="local", error="invalid_token"`)
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage(err.Error()),
+					WithHttpCode(http.StatusUnauthorized),
+					WithCode(CODE_UNAUTHORIZED),
+				)
+				return
+			}
+
+			ctx := auth.NewClientContext(r.Context(), res.ClientId)
+			ctx = auth.NewScopeContext(ctx, res.Scopes)
+			ctx = auth.NewAllowlistContext(ctx, res.AllowedCidrs)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// the signed body hash is verified while the body is read by the handler,
+// so the uploaded file is not buffered in the memory
+func NewSignatureAuthMiddleware(a auth.SignatureAuth, s serialization.Serializer, l auth.Lockout) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authTokens := strings.SplitN(r.Header.Get("Authorization"), auth.SIGNATURE_SCHEME+" ", 2)
+			if len(authTokens) != 2 {
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage("credential is not specified"),
+					WithHttpCode(http.StatusUnauthorized),
+					WithCode(CODE_UNAUTHORIZED),
+				)
+				return
+			}
+
+			bodyHash := strings.ToLower(r.Header.Get(auth.HEADER_CONTENT_SHA256))
+			expectedHash, err := hex.DecodeString(bodyHash)
+			if err != nil || len(expectedHash) != sha256.Size {
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage("content hash is invalid"),
+					WithHttpCode(http.StatusUnauthorized),
+					WithCode(CODE_UNAUTHORIZED),
+				)
+				return
+			}
+
+			authorization, err := a.ParseAuthorization(context.Background(), auth.ParseAuthorizationParam{
+				Authorization: authTokens[1],
+			})
+			if err != nil {
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage(err.Error()),
+					WithHttpCode(http.StatusUnauthorized),
+					WithCode(CODE_UNAUTHORIZED),
+				)
+				return
+			}
+
+			ipAddress := RemoteIpAddress(r)
+			if l != nil {
+				lRes, err := l.Check(context.Background(), auth.CheckLockoutParam{
+					ClientId:  authorization.ClientId,
+					IpAddress: ipAddress,
+				})
+				if err == nil && lRes.Locked {
+					writeTooManyRequests(w, s, "too many failed attempts", lRes.RetryAfter)
+					return
+				}
+			}
+
+			res, err := a.VerifySignature(context.Background(), auth.VerifySignatureParam{
+				ClientId:  authorization.ClientId,
+				SecretId:  authorization.SecretId,
+				Signature: authorization.Signature,
+				Timestamp: r.Header.Get(auth.HEADER_SIGNATURE_TIMESTAMP),
+				Nonce:     r.Header.Get(auth.HEADER_SIGNATURE_NONCE),
+				Method:    r.Method,
+				Path:      r.URL.EscapedPath(),
+				Query:     r.URL.RawQuery,
+				BodyHash:  bodyHash,
+			})
+			if errors.Is(err, auth.ErrorNonceExhausted) {
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage(err.Error()),
+					WithHttpCode(http.StatusServiceUnavailable),
+					WithCode(CODE_UNAVAILABLE),
+				)
+				return
+			}
+			if l != nil && (errors.Is(err, auth.ErrorInvalidSignature) || errors.Is(err, auth.ErrorSignatureMismatch)) {
+				// only the mismatch of an existing client is counted to the client id
+				clientId := ""
+				if errors.Is(err, auth.ErrorSignatureMismatch) {
+					clientId = authorization.ClientId
+				}
+				fRes, fErr := l.RecordFailure(context.Background(), auth.RecordFailureParam{
+					ClientId:  clientId,
+					IpAddress: ipAddress,
+				})
+				if fErr == nil && fRes.Locked {
+					writeTooManyRequests(w, s, "too many failed attempts", fRes.RetryAfter)
+					return
+				}
+			}
+			if err != nil {
+				message := "failed verify signature"
+				if errors.Is(err, auth.ErrorInvalidSignature) ||
+					errors.Is(err, auth.ErrorSignatureMismatch) ||
+					errors.Is(err, auth.ErrorSignatureExpired) ||
+					errors.Is(err, auth.ErrorReplayedRequest) {
+					message = err.Error()
+				}
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage(message),
+					WithHttpCode(http.StatusUnauthorized),
+					WithCode(CODE_UNAUTHORIZED),
+				)
+				return
+			}
+
+			if l != nil {
+				l.RecordSuccess(context.Background(), auth.RecordSuccessParam{
+					ClientId:  res.ClientId,
+					IpAddress: ipAddress,
+				})
+			}
+
+			if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+				emptyHash := sha256.Sum256(nil)
+				if subtle.ConstantTimeCompare(emptyHash[:], expectedHash) != 1 {
+					Response(
+						WithWriterSerializer(w, s),
+						WithMessage(auth.ErrorBodyHashMismatch.Error()),
+						WithHttpCode(http.StatusUnauthorized),
+						WithCode(CODE_UNAUTHORIZED),
+					)
+					return
+				}
+			} else {
+				r.Body = &hashVerifyingBody{
+					body:     r.Body,
+					hash:     sha256.New(),
+					expected: expectedHash,
+				}
+			}
+
+			ctx := auth.NewClientContext(r.Context(), res.ClientId)
+			ctx = auth.NewScopeContext(ctx, res.Scopes)
+			ctx = auth.NewAllowlistContext(ctx, res.AllowedCidrs)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// the mismatch is reported as read error on the end of the body,
+// so the handler fails before the content is committed
+type hashVerifyingBody struct {
+	body     io.ReadCloser
+	hash     hash.Hash
+	expected []byte
+}
+
+func (b *hashVerifyingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.hash.Write(p[:n])
+	if err == io.EOF && subtle.ConstantTimeCompare(b.hash.Sum(nil), b.expected) != 1 {
+		return n, auth.ErrorBodyHashMismatch
+	}
+	return n, err
+}
+
+func (b *hashVerifyingBody) Close() error {
+	return b.body.Close()
+}
+
+func NewAuthSchemeMiddleware(s serialization.Serializer, schemes map[string]func(h http.Handler) http.Handler) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		handlers := map[string]http.Handler{}
+		for scheme, m := range schemes {
+			handlers[strings.ToLower(scheme)] = m(h)
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme := strings.SplitN(r.Header.Get("Authorization"), " ", 2)[0]
+			handler, ok := handlers[strings.ToLower(scheme)]
+			if !ok {
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage("credential is not specified"),
+					WithHttpCode(http.StatusUnauthorized),
+					WithCode(CODE_UNAUTHORIZED),
+				)
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// must be placed after the auth middleware since the scopes are read from the request context
+func NewScopeMiddleware(s serialization.Serializer, scope string) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, _ := auth.ScopesFromContext(r.Context())
+			if !auth.HasScope(scopes, scope) {
+				if strings.EqualFold(strings.SplitN(r.Header.Get("Authorization"), " ", 2)[0], "Bearer") {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="local", error="insufficient_scope", scope="%s"`, scope))
+				}
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage("insufficient scope"),
+					WithHttpCode(http.StatusForbidden),
+					WithCode(CODE_FORBIDDEN),
+				)
+				return
+			}
+
 			h.ServeHTTP(w, r)
 		})
 	}
 }
+
+// must be placed after the auth middleware since the allowlist is read from the request context
+func NewAllowlistMiddleware(log logging.Logger, s serialization.Serializer) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowedCidrs, _ := auth.AllowlistFromContext(r.Context())
+			ipAddress := RemoteIpAddress(r)
+			if !auth.IsAllowedAddress(allowedCidrs, ipAddress) {
+				clientId, _ := auth.ClientFromContext(r.Context())
+				log.WithFields(map[string]interface{}{
+					"event":      "auth.ip_denied",
+					"client_id":  clientId,
+					"ip_address": ipAddress,
+					"path":       r.URL.Path,
+				}).Warn("Request is denied by the client allowlist")
+				Response(
+					WithWriterSerializer(w, s),
+					WithMessage("ip address is not allowed"),
+					WithHttpCode(http.StatusForbidden),
+					WithCode(CODE_FORBIDDEN),
+				)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeTooManyRequests(w http.ResponseWriter, s serialization.Serializer, message string, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", formatSeconds(retryAfter))
+	Response(
+		WithWriterSerializer(w, s),
+		WithMessage(message),
+		WithHttpCode(http.StatusTooManyRequests),
+		WithCode(CODE_TOO_MANY_REQUESTS),
+	)
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -1,14 +1,22 @@
 package rest_app_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
 
 	"github.com/go-seidon/local/internal/auth"
 	"github.com/go-seidon/local/internal/mock"
 	rest_app "github.com/go-seidon/local/internal/rest-app"
+	"github.com/go-seidon/local/internal/serialization"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Middleware Package", func() {
@@ -64,7 +72,7 @@ var _ = Describe("Middleware Package", func() {
 			a = mock.NewMockBasicAuth(ctrl)
 			s = mock.NewMockSerializer(ctrl)
 			handler = mock.NewMockHandler(ctrl)
-			fn := rest_app.NewBasicAuthMiddleware(a, s)
+			fn := rest_app.NewBasicAuthMiddleware(a, s, nil)
 			m = fn(handler)
 
 			rw = mock.NewMockResponseWriter(ctrl)
@@ -176,6 +184,8 @@ var _ = Describe("Middleware Package", func() {
 				}
 				checkRes := &auth.CheckCredentialResult{
 					TokenValid: true,
+					ClientId:   "mock-client-id",
+					Scopes:     []string{"file:read"},
 				}
 				a.
 					EXPECT().
@@ -185,7 +195,14 @@ var _ = Describe("Middleware Package", func() {
 
 				handler.
 					EXPECT().
-					ServeHTTP(gomock.Eq(rw), gomock.Eq(req)).
+					ServeHTTP(gomock.Eq(rw), gomock.Any()).
+					Do(func(w http.ResponseWriter, r *http.Request) {
+						clientId, ok := auth.ClientFromContext(r.Context())
+						Expect(ok).To(BeTrue())
+						Expect(clientId).To(Equal("mock-client-id"))
+						scopes, _ := auth.ScopesFromContext(r.Context())
+						Expect(scopes).To(Equal([]string{"file:read"}))
+					}).
 					Times(1)
 
 				m.ServeHTTP(rw, req)
@@ -193,4 +210,1011 @@ var _ = Describe("Middleware Package", func() {
 		})
 	})
 
+	Context("NewBasicAuthMiddleware with lockout", Label("unit"), func() {
+		var (
+			a       *mock.MockBasicAuth
+			l       *mock.MockLockout
+			s       serialization.Serializer
+			handler *mock.MockHandler
+			m       http.Handler
+
+			req          *http.Request
+			parseParam   auth.ParseAuthTokenParam
+			lockoutParam auth.CheckLockoutParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			a = mock.NewMockBasicAuth(ctrl)
+			l = mock.NewMockLockout(ctrl)
+			s = serialization.NewJsonSerializer()
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewBasicAuthMiddleware(a, s, l)(handler)
+
+			req = httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+			req.RemoteAddr = "10.0.0.1:51234"
+			req.Header.Set("Authorization", "Basic basic-token")
+			parseParam = auth.ParseAuthTokenParam{
+				Token: "basic-token",
+			}
+			lockoutParam = auth.CheckLockoutParam{
+				ClientId:  "mock-client-id",
+				IpAddress: "10.0.0.1",
+			}
+			a.
+				EXPECT().
+				ParseAuthToken(gomock.Any(), gomock.Eq(parseParam)).
+				Return(&auth.ParseAuthTokenResult{
+					ClientId: "mock-client-id",
+				}, nil).
+				AnyTimes()
+		})
+
+		When("credential is locked out", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{
+						Locked:     true,
+						RetryAfter: 1500 * time.Millisecond,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("2"))
+				Expect(resBody.Code).To(Equal("TOO_MANY_REQUESTS"))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should only record the ip address failure", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					CheckCredential(gomock.Any(), gomock.Any()).
+					Return(&auth.CheckCredentialResult{TokenValid: false}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Any(), gomock.Eq(auth.RecordFailureParam{
+						ClientId:  "",
+						IpAddress: "10.0.0.1",
+					})).
+					Return(&auth.RecordFailureResult{}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(401))
+			})
+		})
+
+		When("credential is invalid below the threshold", func() {
+			It("should return unauthorized", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					CheckCredential(gomock.Any(), gomock.Any()).
+					Return(&auth.CheckCredentialResult{TokenValid: false, ClientFound: true}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Any(), gomock.Eq(auth.RecordFailureParam{
+						ClientId:  "mock-client-id",
+						IpAddress: "10.0.0.1",
+					})).
+					Return(&auth.RecordFailureResult{}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(w.Header().Get("Retry-After")).To(Equal(""))
+			})
+		})
+
+		When("credential is invalid and threshold is reached", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					CheckCredential(gomock.Any(), gomock.Any()).
+					Return(&auth.CheckCredentialResult{TokenValid: false}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Any(), gomock.Any()).
+					Return(&auth.RecordFailureResult{
+						Locked:     true,
+						RetryAfter: 30 * time.Second,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("30"))
+			})
+		})
+
+		When("token is malformed", func() {
+			It("should track the ip address only", func() {
+				req.Header.Set("Authorization", "Basic malformed-token")
+				a.
+					EXPECT().
+					ParseAuthToken(gomock.Any(), gomock.Eq(auth.ParseAuthTokenParam{
+						Token: "malformed-token",
+					})).
+					Return(nil, fmt.Errorf("invalid token")).
+					Times(1)
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(auth.CheckLockoutParam{
+						IpAddress: "10.0.0.1",
+					})).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					CheckCredential(gomock.Any(), gomock.Any()).
+					Return(&auth.CheckCredentialResult{TokenValid: false}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Any(), gomock.Eq(auth.RecordFailureParam{
+						IpAddress: "10.0.0.1",
+					})).
+					Return(&auth.RecordFailureResult{}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(401))
+			})
+		})
+
+		When("credential is valid", func() {
+			It("should reset the failed attempt", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					CheckCredential(gomock.Any(), gomock.Any()).
+					Return(&auth.CheckCredentialResult{
+						TokenValid: true,
+						ClientId:   "mock-client-id",
+					}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					RecordSuccess(gomock.Any(), gomock.Eq(auth.RecordSuccessParam{
+						ClientId:  "mock-client-id",
+						IpAddress: "10.0.0.1",
+					})).
+					Return(nil).
+					Times(1)
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+			})
+		})
+	})
+
+	Context("NewBearerAuthMiddleware", Label("unit"), func() {
+		var (
+			a       *mock.MockTokenAuth
+			s       serialization.Serializer
+			handler *mock.MockHandler
+			m       http.Handler
+
+			req *http.Request
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			a = mock.NewMockTokenAuth(ctrl)
+			s = serialization.NewJsonSerializer()
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewBearerAuthMiddleware(a, s)(handler)
+
+			req = httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+			req.Header.Set("Authorization", "Bearer access-token")
+		})
+
+		When("bearer token is not specified", func() {
+			It("should return error", func() {
+				req.Header.Set("Authorization", "Basic basic-token")
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(w.Header().Get("WWW-Authenticate")).To(Equal(`Bearer realm="local"`))
+				Expect(resBody.Message).To(Equal("credential is not specified"))
+			})
+		})
+
+		When("token is expired", func() {
+			It("should return error", func() {
+				a.
+					EXPECT().
+					VerifyToken(gomock.Any(), gomock.Eq(auth.VerifyTokenParam{
+						Token: "access-token",
+					})).
+					Return(nil, auth.ErrorTokenExpired).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(w.Header().Get("WWW-Authenticate")).To(Equal(`Bearer realm="local", error="invalid_token"`))
+				Expect(resBody.Code).To(Equal("UNAUTHORIZED"))
+				Expect(resBody.Message).To(Equal("access token is expired"))
+			})
+		})
+
+		When("token is valid", func() {
+			It("should call the handler", func() {
+				a.
+					EXPECT().
+					VerifyToken(gomock.Any(), gomock.Eq(auth.VerifyTokenParam{
+						Token: "access-token",
+					})).
+					Return(&auth.VerifyTokenResult{
+						ClientId: "mock-client-id",
+						Scopes:   []string{"file:write"},
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Do(func(w http.ResponseWriter, r *http.Request) {
+						clientId, ok := auth.ClientFromContext(r.Context())
+						Expect(ok).To(BeTrue())
+						Expect(clientId).To(Equal("mock-client-id"))
+						scopes, _ := auth.ScopesFromContext(r.Context())
+						Expect(scopes).To(Equal([]string{"file:write"}))
+					}).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+			})
+		})
+	})
+
+	Context("NewSignatureAuthMiddleware", Label("unit"), func() {
+		var (
+			a        *mock.MockSignatureAuth
+			s        serialization.Serializer
+			handler  *mock.MockHandler
+			m        http.Handler
+			req      *http.Request
+			bodyHash string
+
+			parseParam  auth.ParseAuthorizationParam
+			parseResult *auth.ParseAuthorizationResult
+			verifyParam auth.VerifySignatureParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			a = mock.NewMockSignatureAuth(ctrl)
+			s = serialization.NewJsonSerializer()
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewSignatureAuthMiddleware(a, s, nil)(handler)
+
+			sum := sha256.Sum256([]byte("content"))
+			bodyHash = hex.EncodeToString(sum[:])
+			req = httptest.NewRequest(http.MethodPost, "/file?a=b", strings.NewReader("content"))
+			req.Header.Set("Authorization", "HMAC-SHA256 Credential=client-id/secret-id, Signature=abc")
+			req.Header.Set("X-Signature-Timestamp", "1660000000")
+			req.Header.Set("X-Signature-Nonce", "0123456789abcdef")
+			req.Header.Set("X-Content-Sha256", bodyHash)
+
+			parseParam = auth.ParseAuthorizationParam{
+				Authorization: "Credential=client-id/secret-id, Signature=abc",
+			}
+			parseResult = &auth.ParseAuthorizationResult{
+				ClientId:  "client-id",
+				SecretId:  "secret-id",
+				Signature: "abc",
+			}
+			verifyParam = auth.VerifySignatureParam{
+				ClientId:  "client-id",
+				SecretId:  "secret-id",
+				Signature: "abc",
+				Timestamp: "1660000000",
+				Nonce:     "0123456789abcdef",
+				Method:    "POST",
+				Path:      "/file",
+				Query:     "a=b",
+				BodyHash:  bodyHash,
+			}
+		})
+
+		When("signature is not specified", func() {
+			It("should return error", func() {
+				req.Header.Set("Authorization", "Basic basic-token")
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("credential is not specified"))
+			})
+		})
+
+		When("content hash is invalid", func() {
+			It("should return error", func() {
+				req.Header.Set("X-Content-Sha256", "not-a-hash")
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("content hash is invalid"))
+			})
+		})
+
+		When("failed parse authorization", func() {
+			It("should return error", func() {
+				a.
+					EXPECT().
+					ParseAuthorization(gomock.Any(), gomock.Eq(parseParam)).
+					Return(nil, auth.ErrorInvalidSignature).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("invalid request signature"))
+			})
+		})
+
+		When("request is replayed", func() {
+			It("should return error", func() {
+				a.
+					EXPECT().
+					ParseAuthorization(gomock.Any(), gomock.Eq(parseParam)).
+					Return(parseResult, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Eq(verifyParam)).
+					Return(nil, auth.ErrorReplayedRequest).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("request nonce is already used"))
+			})
+		})
+
+		When("remembered nonces are exhausted", func() {
+			It("should return error", func() {
+				a.
+					EXPECT().
+					ParseAuthorization(gomock.Any(), gomock.Eq(parseParam)).
+					Return(parseResult, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Eq(verifyParam)).
+					Return(nil, auth.ErrorNonceExhausted).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(503))
+				Expect(resBody.Message).To(Equal("too many signed requests are remembered"))
+			})
+		})
+
+		When("failed verify signature", func() {
+			It("should return error", func() {
+				a.
+					EXPECT().
+					ParseAuthorization(gomock.Any(), gomock.Eq(parseParam)).
+					Return(parseResult, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Eq(verifyParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("failed verify signature"))
+			})
+		})
+
+		When("empty body does not match the hash", func() {
+			It("should return error", func() {
+				req = httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				req.Header.Set("Authorization", "HMAC-SHA256 Credential=client-id/secret-id, Signature=abc")
+				req.Header.Set("X-Content-Sha256", bodyHash)
+				a.
+					EXPECT().
+					ParseAuthorization(gomock.Any(), gomock.Eq(parseParam)).
+					Return(parseResult, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Any()).
+					Return(&auth.VerifySignatureResult{ClientId: "client-id"}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("request body does not match the signed hash"))
+			})
+		})
+
+		When("body does not match the hash", func() {
+			It("should fail reading the body", func() {
+				req = httptest.NewRequest(http.MethodPost, "/file?a=b", strings.NewReader("tampered"))
+				req.Header.Set("Authorization", "HMAC-SHA256 Credential=client-id/secret-id, Signature=abc")
+				req.Header.Set("X-Signature-Timestamp", "1660000000")
+				req.Header.Set("X-Signature-Nonce", "0123456789abcdef")
+				req.Header.Set("X-Content-Sha256", bodyHash)
+				a.
+					EXPECT().
+					ParseAuthorization(gomock.Any(), gomock.Eq(parseParam)).
+					Return(parseResult, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Eq(verifyParam)).
+					Return(&auth.VerifySignatureResult{ClientId: "client-id"}, nil).
+					Times(1)
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(func(w http.ResponseWriter, r *http.Request) {
+						_, err := io.ReadAll(r.Body)
+						Expect(err).To(Equal(auth.ErrorBodyHashMismatch))
+					}).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+			})
+		})
+
+		When("signature is valid", func() {
+			It("should call the handler", func() {
+				a.
+					EXPECT().
+					ParseAuthorization(gomock.Any(), gomock.Eq(parseParam)).
+					Return(parseResult, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Eq(verifyParam)).
+					Return(&auth.VerifySignatureResult{
+						ClientId: "client-id",
+						Scopes:   []string{"file:write"},
+						SecretId: "secret-id",
+					}, nil).
+					Times(1)
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(func(w http.ResponseWriter, r *http.Request) {
+						body, err := io.ReadAll(r.Body)
+						Expect(err).To(BeNil())
+						Expect(string(body)).To(Equal("content"))
+						clientId, _ := auth.ClientFromContext(r.Context())
+						Expect(clientId).To(Equal("client-id"))
+						scopes, _ := auth.ScopesFromContext(r.Context())
+						Expect(scopes).To(Equal([]string{"file:write"}))
+					}).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+			})
+		})
+	})
+
+	Context("NewSignatureAuthMiddleware with lockout", Label("unit"), func() {
+		var (
+			a       *mock.MockSignatureAuth
+			l       *mock.MockLockout
+			s       serialization.Serializer
+			handler *mock.MockHandler
+			m       http.Handler
+
+			req          *http.Request
+			lockoutParam auth.CheckLockoutParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			a = mock.NewMockSignatureAuth(ctrl)
+			l = mock.NewMockLockout(ctrl)
+			s = serialization.NewJsonSerializer()
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewSignatureAuthMiddleware(a, s, l)(handler)
+
+			sum := sha256.Sum256(nil)
+			req = httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+			req.RemoteAddr = "10.0.0.1:51234"
+			req.Header.Set("Authorization", "HMAC-SHA256 Credential=client-id/secret-id, Signature=abc")
+			req.Header.Set("X-Signature-Timestamp", "1660000000")
+			req.Header.Set("X-Signature-Nonce", "0123456789abcdef")
+			req.Header.Set("X-Content-Sha256", hex.EncodeToString(sum[:]))
+			lockoutParam = auth.CheckLockoutParam{
+				ClientId:  "client-id",
+				IpAddress: "10.0.0.1",
+			}
+			a.
+				EXPECT().
+				ParseAuthorization(gomock.Any(), gomock.Any()).
+				Return(&auth.ParseAuthorizationResult{
+					ClientId:  "client-id",
+					SecretId:  "secret-id",
+					Signature: "abc",
+				}, nil).
+				AnyTimes()
+		})
+
+		When("credential is locked out", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{
+						Locked:     true,
+						RetryAfter: 1500 * time.Millisecond,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("2"))
+				Expect(resBody.Code).To(Equal("TOO_MANY_REQUESTS"))
+			})
+		})
+
+		When("signature does not match", func() {
+			It("should record the client failure", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Any()).
+					Return(nil, auth.ErrorSignatureMismatch).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Any(), gomock.Eq(auth.RecordFailureParam{
+						ClientId:  "client-id",
+						IpAddress: "10.0.0.1",
+					})).
+					Return(&auth.RecordFailureResult{}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("invalid request signature"))
+			})
+		})
+
+		When("client is not found", func() {
+			It("should only record the ip address failure", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Any()).
+					Return(nil, auth.ErrorInvalidSignature).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Any(), gomock.Eq(auth.RecordFailureParam{
+						ClientId:  "",
+						IpAddress: "10.0.0.1",
+					})).
+					Return(&auth.RecordFailureResult{}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("invalid request signature"))
+			})
+		})
+
+		When("signature does not match and threshold is reached", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Any()).
+					Return(nil, auth.ErrorSignatureMismatch).
+					Times(1)
+				l.
+					EXPECT().
+					RecordFailure(gomock.Any(), gomock.Any()).
+					Return(&auth.RecordFailureResult{
+						Locked:     true,
+						RetryAfter: 30 * time.Second,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("30"))
+			})
+		})
+
+		When("request is replayed", func() {
+			It("should not record the failure", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Any()).
+					Return(nil, auth.ErrorReplayedRequest).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(401))
+			})
+		})
+
+		When("signature is valid", func() {
+			It("should reset the failed attempt", func() {
+				l.
+					EXPECT().
+					Check(gomock.Any(), gomock.Eq(lockoutParam)).
+					Return(&auth.CheckLockoutResult{}, nil).
+					Times(1)
+				a.
+					EXPECT().
+					VerifySignature(gomock.Any(), gomock.Any()).
+					Return(&auth.VerifySignatureResult{ClientId: "client-id"}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					RecordSuccess(gomock.Any(), gomock.Eq(auth.RecordSuccessParam{
+						ClientId:  "client-id",
+						IpAddress: "10.0.0.1",
+					})).
+					Return(nil).
+					Times(1)
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+			})
+		})
+	})
+
+	Context("NewAuthSchemeMiddleware", Label("unit"), func() {
+		var (
+			s       serialization.Serializer
+			handler *mock.MockHandler
+			m       http.Handler
+			called  string
+
+			req *http.Request
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			s = serialization.NewJsonSerializer()
+			handler = mock.NewMockHandler(ctrl)
+			called = ""
+			scheme := func(name string) func(h http.Handler) http.Handler {
+				return func(h http.Handler) http.Handler {
+					return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						called = name
+						h.ServeHTTP(w, r)
+					})
+				}
+			}
+			m = rest_app.NewAuthSchemeMiddleware(s, map[string]func(h http.Handler) http.Handler{
+				"Basic":  scheme("basic"),
+				"Bearer": scheme("bearer"),
+			})(handler)
+
+			req = httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+		})
+
+		When("authorization is not specified", func() {
+			It("should return error", func() {
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(resBody.Message).To(Equal("credential is not specified"))
+			})
+		})
+
+		When("scheme is not supported", func() {
+			It("should return error", func() {
+				req.Header.Set("Authorization", "Digest username=client")
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(401))
+				Expect(called).To(Equal(""))
+			})
+		})
+
+		When("scheme is supported", func() {
+			It("should dispatch to the scheme middleware", func() {
+				req.Header.Set("Authorization", "bearer access-token")
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+
+				Expect(called).To(Equal("bearer"))
+			})
+		})
+	})
+
+	Context("NewScopeMiddleware", Label("unit"), func() {
+		var (
+			s       serialization.Serializer
+			handler *mock.MockHandler
+			m       http.Handler
+
+			req *http.Request
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			s = serialization.NewJsonSerializer()
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewScopeMiddleware(s, "file:delete")(handler)
+
+			req = httptest.NewRequest(http.MethodDelete, "/file/mock-id", nil)
+		})
+
+		When("scopes are not attached", func() {
+			It("should return error", func() {
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(403))
+				Expect(resBody.Code).To(Equal("FORBIDDEN"))
+				Expect(resBody.Message).To(Equal("insufficient scope"))
+			})
+		})
+
+		When("scope is not granted to bearer token", func() {
+			It("should return error", func() {
+				req.Header.Set("Authorization", "Bearer access-token")
+				ctx := auth.NewScopeContext(req.Context(), []string{"file:read"})
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req.WithContext(ctx))
+
+				Expect(w.Code).To(Equal(403))
+				Expect(w.Header().Get("WWW-Authenticate")).To(Equal(`Bearer realm="local", error="insufficient_scope", scope="file:delete"`))
+			})
+		})
+
+		When("scope is granted", func() {
+			It("should call the handler", func() {
+				ctx := auth.NewScopeContext(req.Context(), []string{"file:read", "file:delete"})
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req.WithContext(ctx))
+			})
+		})
+
+		When("admin scope is granted", func() {
+			It("should call the handler", func() {
+				ctx := auth.NewScopeContext(req.Context(), []string{"admin"})
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req.WithContext(ctx))
+			})
+		})
+	})
+
+	Context("NewAllowlistMiddleware", Label("unit"), func() {
+		var (
+			log     *mock.MockLogger
+			s       serialization.Serializer
+			handler *mock.MockHandler
+			m       http.Handler
+
+			req *http.Request
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			s = serialization.NewJsonSerializer()
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewAllowlistMiddleware(log, s)(handler)
+
+			req = httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+			req.RemoteAddr = "10.0.0.1:51234"
+		})
+
+		When("allowlist is not attached", func() {
+			It("should call the handler", func() {
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+			})
+		})
+
+		When("ip address is in the allowlist", func() {
+			It("should call the handler", func() {
+				ctx := auth.NewAllowlistContext(req.Context(), []string{"192.168.0.0/16", "10.0.0.0/8"})
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req.WithContext(ctx))
+			})
+		})
+
+		When("ip address is not in the allowlist", func() {
+			It("should return error", func() {
+				ctx := auth.NewClientContext(req.Context(), "mock-client-id")
+				ctx = auth.NewAllowlistContext(ctx, []string{"192.168.0.0/16"})
+				w := httptest.NewRecorder()
+				log.
+					EXPECT().
+					WithFields(gomock.Eq(map[string]interface{}{
+						"event":      "auth.ip_denied",
+						"client_id":  "mock-client-id",
+						"ip_address": "10.0.0.1",
+						"path":       "/file/mock-id",
+					})).
+					Return(log).
+					Times(1)
+				log.
+					EXPECT().
+					Warn(gomock.Eq("Request is denied by the client allowlist")).
+					Times(1)
+
+				m.ServeHTTP(w, req.WithContext(ctx))
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(403))
+				Expect(resBody.Code).To(Equal("FORBIDDEN"))
+				Expect(resBody.Message).To(Equal("ip address is not allowed"))
+			})
+		})
+	})
 })
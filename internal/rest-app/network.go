@@ -0,0 +1,125 @@
+package rest_app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type IpResolver interface {
+	Resolve(r *http.Request) string
+}
+
+type ipResolver struct {
+	trustedProxies []*net.IPNet
+}
+
+// X-Forwarded-For is read from the right, since the left most entries are set by the client,
+// the first address which is not a trusted proxy is the client address,
+// empty address is returned when the malformed entry is reached so the allowlist is failed closed
+func (i *ipResolver) Resolve(r *http.Request) string {
+	remoteIp := remoteAddress(r)
+	if !i.isTrusted(remoteIp) {
+		return remoteIp
+	}
+
+	forwarded := []string{}
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, ip := range strings.Split(header, ",") {
+			forwarded = append(forwarded, strings.TrimSpace(ip))
+		}
+	}
+
+	clientIp := remoteIp
+	for idx := len(forwarded) - 1; idx >= 0; idx-- {
+		if net.ParseIP(forwarded[idx]) == nil {
+			return ""
+		}
+		clientIp = forwarded[idx]
+		if !i.isTrusted(clientIp) {
+			break
+		}
+	}
+	return clientIp
+}
+
+func (i *ipResolver) isTrusted(ipAddress string) bool {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for _, network := range i.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type NewIpResolverParam struct {
+	TrustedProxies []string
+}
+
+func NewIpResolver(p NewIpResolverParam) (*ipResolver, error) {
+	trustedProxies := []*net.IPNet{}
+	for _, proxy := range p.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			trustedProxies = append(trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", proxy)
+		}
+		trustedProxies = append(trustedProxies, network)
+	}
+
+	i := &ipResolver{
+		trustedProxies: trustedProxies,
+	}
+	return i, nil
+}
+
+type clientIpContextKey struct{}
+
+// must be placed before the middleware reading the client address
+func NewClientIpMiddleware(i IpResolver) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIpContextKey{}, i.Resolve(r))
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func RemoteIpAddress(r *http.Request) string {
+	clientIp, ok := r.Context().Value(clientIpContextKey{}).(string)
+	if ok {
+		return clientIp
+	}
+	return remoteAddress(r)
+}
+
+func remoteAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
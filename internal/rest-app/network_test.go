@@ -0,0 +1,197 @@
+package rest_app_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/mock"
+	rest_app "github.com/go-seidon/local/internal/rest-app"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network Package", func() {
+
+	Context("NewIpResolver function", Label("unit"), func() {
+		When("trusted proxy is not specified", func() {
+			It("should return result", func() {
+				res, err := rest_app.NewIpResolver(rest_app.NewIpResolverParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("trusted proxy is invalid ip address", func() {
+			It("should return error", func() {
+				res, err := rest_app.NewIpResolver(rest_app.NewIpResolverParam{
+					TrustedProxies: []string{"10.0.0.300"},
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid trusted proxy: 10.0.0.300")))
+			})
+		})
+
+		When("trusted proxy is invalid cidr", func() {
+			It("should return error", func() {
+				res, err := rest_app.NewIpResolver(rest_app.NewIpResolverParam{
+					TrustedProxies: []string{"10.0.0.0/33"},
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid trusted proxy: 10.0.0.0/33")))
+			})
+		})
+
+		When("trusted proxy is valid", func() {
+			It("should return result", func() {
+				res, err := rest_app.NewIpResolver(rest_app.NewIpResolverParam{
+					TrustedProxies: []string{"10.0.0.1", "172.16.0.0/12", "::1"},
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("Resolve function", Label("unit"), func() {
+		var (
+			resolver rest_app.IpResolver
+			req      *http.Request
+		)
+
+		BeforeEach(func() {
+			resolver, _ = rest_app.NewIpResolver(rest_app.NewIpResolverParam{
+				TrustedProxies: []string{"10.0.0.1", "172.16.0.0/12"},
+			})
+			req = httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+			req.RemoteAddr = "10.0.0.1:51234"
+		})
+
+		When("request is not sent by trusted proxy", func() {
+			It("should ignore the forwarded address", func() {
+				req.RemoteAddr = "203.0.113.7:51234"
+				req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+				res := resolver.Resolve(req)
+
+				Expect(res).To(Equal("203.0.113.7"))
+			})
+		})
+
+		When("forwarded address is not specified", func() {
+			It("should return the proxy address", func() {
+				res := resolver.Resolve(req)
+
+				Expect(res).To(Equal("10.0.0.1"))
+			})
+		})
+
+		When("forwarded address is sent through multiple proxies", func() {
+			It("should return the first untrusted address from the right", func() {
+				req.Header.Add("X-Forwarded-For", "1.1.1.1, 198.51.100.1")
+				req.Header.Add("X-Forwarded-For", "172.16.0.5")
+
+				res := resolver.Resolve(req)
+
+				Expect(res).To(Equal("198.51.100.1"))
+			})
+		})
+
+		When("forwarded address is malformed", func() {
+			It("should return empty address", func() {
+				req.Header.Set("X-Forwarded-For", "198.51.100.1, unknown, 172.16.0.5")
+
+				res := resolver.Resolve(req)
+
+				Expect(res).To(Equal(""))
+			})
+		})
+
+		When("right most forwarded address has port", func() {
+			It("should return empty address", func() {
+				req.Header.Set("X-Forwarded-For", "198.51.100.1:443")
+
+				res := resolver.Resolve(req)
+
+				Expect(res).To(Equal(""))
+				Expect(auth.IsAllowedAddress([]string{"10.0.0.0/8"}, res)).To(BeFalse())
+			})
+		})
+
+		When("malformed address is sent before the client address", func() {
+			It("should return the client address", func() {
+				req.Header.Set("X-Forwarded-For", "unknown, 198.51.100.1, 172.16.0.5")
+
+				res := resolver.Resolve(req)
+
+				Expect(res).To(Equal("198.51.100.1"))
+			})
+		})
+	})
+
+	Context("NewClientIpMiddleware", Label("unit"), func() {
+		var (
+			resolver *mock.MockIpResolver
+			handler  *mock.MockHandler
+			m        http.Handler
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			resolver = mock.NewMockIpResolver(ctrl)
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewClientIpMiddleware(resolver)(handler)
+		})
+
+		When("request is received", func() {
+			It("should attach the client address", func() {
+				req := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				w := httptest.NewRecorder()
+				resolver.
+					EXPECT().
+					Resolve(gomock.Eq(req)).
+					Return("198.51.100.1").
+					Times(1)
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Do(func(w http.ResponseWriter, r *http.Request) {
+						Expect(rest_app.RemoteIpAddress(r)).To(Equal("198.51.100.1"))
+					}).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+			})
+		})
+	})
+
+	Context("RemoteIpAddress function", Label("unit"), func() {
+		When("client address is not resolved", func() {
+			It("should return the connection address", func() {
+				req := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				req.RemoteAddr = "10.0.0.1:51234"
+
+				res := rest_app.RemoteIpAddress(req)
+
+				Expect(res).To(Equal("10.0.0.1"))
+			})
+		})
+
+		When("connection address has no port", func() {
+			It("should return the connection address", func() {
+				req := httptest.NewRequest(http.MethodGet, "/file/mock-id", nil)
+				req.RemoteAddr = "10.0.0.1"
+
+				res := rest_app.RemoteIpAddress(req)
+
+				Expect(res).To(Equal("10.0.0.1"))
+			})
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"github.com/go-seidon/local/internal/app"
 	"github.com/go-seidon/local/internal/healthcheck"
 	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/notifying"
 )
 
 type RestAppConfig struct {
@@ -34,6 +35,8 @@ type RestAppOption struct {
 	Logger        logging.Logger
 	Server        app.Server
 	HealthService healthcheck.HealthCheck
+	Dispatcher    notifying.Dispatcher
+	Relay         notifying.Relay
 }
 
 type Option func(*RestAppOption)
@@ -61,3 +64,15 @@ func WithService(healthService healthcheck.HealthCheck) Option {
 		rao.HealthService = healthService
 	}
 }
+
+func WithDispatcher(dispatcher notifying.Dispatcher) Option {
+	return func(rao *RestAppOption) {
+		rao.Dispatcher = dispatcher
+	}
+}
+
+func WithRelay(relay notifying.Relay) Option {
+	return func(rao *RestAppOption) {
+		rao.Relay = relay
+	}
+}
@@ -0,0 +1,185 @@
+package rest_app
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/limiting"
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/serialization"
+)
+
+const (
+	HEADER_RATELIMIT_LIMIT     = "RateLimit-Limit"
+	HEADER_RATELIMIT_REMAINING = "RateLimit-Remaining"
+	HEADER_RATELIMIT_RESET     = "RateLimit-Reset"
+)
+
+// must be placed after the auth middleware since the client is read from the request context,
+// the request is allowed when the limiter is failed so the limiter outage does not block every client
+func NewRateLimitMiddleware(log logging.Logger, s serialization.Serializer, l limiting.Limiter) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientId, ok := auth.ClientFromContext(r.Context())
+			if !ok {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, err := allowRequest(w, r, s, l, clientId)
+			if err != nil {
+				log.Errorf("Failed allow request: %s", err.Error())
+				h.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				return
+			}
+
+			aRes, err := l.Acquire(r.Context(), limiting.AcquireParam{
+				ClientId: clientId,
+			})
+			if err != nil {
+				log.Errorf("Failed acquire request: %s", err.Error())
+				h.ServeHTTP(w, r)
+				return
+			}
+			if !aRes.Allowed {
+				writeTooManyRequests(w, s, "too many concurrent requests", time.Second)
+				return
+			}
+			defer l.Release(r.Context(), limiting.ReleaseParam{
+				ClientId: clientId,
+			})
+
+			tRes, err := l.AllowTransfer(r.Context(), limiting.AllowTransferParam{
+				ClientId: clientId,
+			})
+			if err != nil {
+				log.Errorf("Failed allow transfer: %s", err.Error())
+				h.ServeHTTP(w, r)
+				return
+			}
+			if !tRes.Allowed {
+				writeTooManyRequests(w, s, "transfer rate limit exceeded", tRes.RetryAfter)
+				return
+			}
+			if tRes.Limit == 0 {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			body := &countingBody{body: r.Body}
+			r.Body = body
+			cw := &countingWriter{ResponseWriter: w}
+			h.ServeHTTP(cw, r)
+
+			err = l.RecordTransfer(r.Context(), limiting.RecordTransferParam{
+				ClientId: clientId,
+				Size:     body.size + cw.size,
+			})
+			if err != nil {
+				log.Errorf("Failed record transfer: %s", err.Error())
+			}
+		})
+	}
+}
+
+// the stream is counted toward the request rate once and is limited by the concurrent stream,
+// the stream does not hold the in-flight request and its bytes are not counted to the transfer rate
+func NewStreamLimitMiddleware(log logging.Logger, s serialization.Serializer, l limiting.Limiter) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientId, ok := auth.ClientFromContext(r.Context())
+			if !ok {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, err := allowRequest(w, r, s, l, clientId)
+			if err != nil {
+				log.Errorf("Failed allow request: %s", err.Error())
+				h.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				return
+			}
+
+			aRes, err := l.Acquire(r.Context(), limiting.AcquireParam{
+				ClientId: clientId,
+				Stream:   true,
+			})
+			if err != nil {
+				log.Errorf("Failed acquire stream: %s", err.Error())
+				h.ServeHTTP(w, r)
+				return
+			}
+			if !aRes.Allowed {
+				writeTooManyRequests(w, s, "too many concurrent streams", time.Second)
+				return
+			}
+			defer l.Release(r.Context(), limiting.ReleaseParam{
+				ClientId: clientId,
+				Stream:   true,
+			})
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// the rate limit header is written, false is returned when the rejection is written
+func allowRequest(w http.ResponseWriter, r *http.Request, s serialization.Serializer, l limiting.Limiter, clientId string) (bool, error) {
+	rRes, err := l.AllowRequest(r.Context(), limiting.AllowRequestParam{
+		ClientId: clientId,
+	})
+	if err != nil {
+		return false, err
+	}
+	if rRes.Limit > 0 {
+		w.Header().Set(HEADER_RATELIMIT_LIMIT, strconv.FormatInt(rRes.Limit, 10))
+		w.Header().Set(HEADER_RATELIMIT_REMAINING, strconv.FormatInt(rRes.Remaining, 10))
+		w.Header().Set(HEADER_RATELIMIT_RESET, formatSeconds(rRes.ResetAfter))
+	}
+	if !rRes.Allowed {
+		writeTooManyRequests(w, s, "request rate limit exceeded", rRes.RetryAfter)
+		return false, nil
+	}
+	return true, nil
+}
+
+type countingBody struct {
+	body io.ReadCloser
+	size int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	return b.body.Close()
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	size int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *countingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
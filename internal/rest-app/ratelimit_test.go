@@ -0,0 +1,493 @@
+package rest_app_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/auth"
+	"github.com/go-seidon/local/internal/limiting"
+	"github.com/go-seidon/local/internal/mock"
+	rest_app "github.com/go-seidon/local/internal/rest-app"
+	"github.com/go-seidon/local/internal/serialization"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rate Limit Package", func() {
+
+	Context("NewRateLimitMiddleware", Label("unit"), func() {
+		var (
+			log     *mock.MockLogger
+			s       serialization.Serializer
+			l       *mock.MockLimiter
+			handler *mock.MockHandler
+			m       http.Handler
+
+			req           *http.Request
+			requestParam  limiting.AllowRequestParam
+			requestResult *limiting.AllowRequestResult
+			transferParam limiting.AllowTransferParam
+			acquireParam  limiting.AcquireParam
+			releaseParam  limiting.ReleaseParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			s = serialization.NewJsonSerializer()
+			l = mock.NewMockLimiter(ctrl)
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewRateLimitMiddleware(log, s, l)(handler)
+
+			req = httptest.NewRequest(http.MethodPost, "/file", strings.NewReader("content"))
+			req = req.WithContext(auth.NewClientContext(req.Context(), "mock-client-id"))
+			requestParam = limiting.AllowRequestParam{
+				ClientId: "mock-client-id",
+			}
+			requestResult = &limiting.AllowRequestResult{
+				Allowed:    true,
+				Limit:      20,
+				Remaining:  19,
+				ResetAfter: 50 * time.Millisecond,
+			}
+			transferParam = limiting.AllowTransferParam{
+				ClientId: "mock-client-id",
+			}
+			acquireParam = limiting.AcquireParam{
+				ClientId: "mock-client-id",
+			}
+			releaseParam = limiting.ReleaseParam{
+				ClientId: "mock-client-id",
+			}
+		})
+
+		When("client is not attached", func() {
+			It("should call the handler", func() {
+				r := httptest.NewRequest(http.MethodGet, "/health", nil)
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Eq(r)).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+			})
+		})
+
+		When("request rate limit is exceeded", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(&limiting.AllowRequestResult{
+						Allowed:    false,
+						Limit:      20,
+						Remaining:  0,
+						RetryAfter: 50 * time.Millisecond,
+						ResetAfter: 2 * time.Second,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("RateLimit-Limit")).To(Equal("20"))
+				Expect(w.Header().Get("RateLimit-Remaining")).To(Equal("0"))
+				Expect(w.Header().Get("RateLimit-Reset")).To(Equal("2"))
+				Expect(w.Header().Get("Retry-After")).To(Equal("1"))
+				Expect(resBody.Code).To(Equal("TOO_MANY_REQUESTS"))
+				Expect(resBody.Message).To(Equal("request rate limit exceeded"))
+			})
+		})
+
+		When("failed allow request", func() {
+			It("should call the handler", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(nil, fmt.Errorf("store error")).
+					Times(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed allow request: %s"), gomock.Eq("store error")).
+					Times(1)
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+			})
+		})
+
+		When("concurrent request limit is exceeded", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(&limiting.AcquireResult{Allowed: false, Limit: 4}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("1"))
+				Expect(resBody.Message).To(Equal("too many concurrent requests"))
+			})
+		})
+
+		When("transfer rate limit is exceeded", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(&limiting.AcquireResult{Allowed: true, Limit: 4}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Release(gomock.Any(), gomock.Eq(releaseParam)).
+					Return(nil).
+					Times(1)
+				l.
+					EXPECT().
+					AllowTransfer(gomock.Any(), gomock.Eq(transferParam)).
+					Return(&limiting.AllowTransferResult{
+						Allowed:    false,
+						Limit:      1024,
+						RetryAfter: 3 * time.Second,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("RateLimit-Remaining")).To(Equal("19"))
+				Expect(w.Header().Get("Retry-After")).To(Equal("3"))
+				Expect(resBody.Message).To(Equal("transfer rate limit exceeded"))
+			})
+		})
+
+		When("transfer is unlimited", func() {
+			It("should call the handler without recording", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(&limiting.AcquireResult{Allowed: true, Limit: 4}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Release(gomock.Any(), gomock.Eq(releaseParam)).
+					Return(nil).
+					Times(1)
+				l.
+					EXPECT().
+					AllowTransfer(gomock.Any(), gomock.Eq(transferParam)).
+					Return(&limiting.AllowTransferResult{Allowed: true}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Header().Get("RateLimit-Limit")).To(Equal("20"))
+				Expect(w.Header().Get("RateLimit-Reset")).To(Equal("1"))
+			})
+		})
+
+		When("content is transferred", func() {
+			It("should record the uploaded and downloaded bytes", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(&limiting.AcquireResult{Allowed: true, Limit: 4}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Release(gomock.Any(), gomock.Eq(releaseParam)).
+					Return(nil).
+					Times(1)
+				l.
+					EXPECT().
+					AllowTransfer(gomock.Any(), gomock.Eq(transferParam)).
+					Return(&limiting.AllowTransferResult{
+						Allowed:   true,
+						Limit:     1024,
+						Remaining: 1024,
+					}, nil).
+					Times(1)
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(func(w http.ResponseWriter, r *http.Request) {
+						io.ReadAll(r.Body)
+						w.Write([]byte("response"))
+					}).
+					Times(1)
+				l.
+					EXPECT().
+					RecordTransfer(gomock.Any(), gomock.Eq(limiting.RecordTransferParam{
+						ClientId: "mock-client-id",
+						Size:     15,
+					})).
+					Return(nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Body.String()).To(Equal("response"))
+			})
+		})
+
+		When("failed record transfer", func() {
+			It("should write log", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(&limiting.AcquireResult{Allowed: true, Limit: 4}, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Release(gomock.Any(), gomock.Eq(releaseParam)).
+					Return(nil).
+					Times(1)
+				l.
+					EXPECT().
+					AllowTransfer(gomock.Any(), gomock.Eq(transferParam)).
+					Return(&limiting.AllowTransferResult{
+						Allowed: true,
+						Limit:   1024,
+					}, nil).
+					Times(1)
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Any(), gomock.Any()).
+					Do(func(w http.ResponseWriter, r *http.Request) {
+						w.Write([]byte("response"))
+					}).
+					Times(1)
+				l.
+					EXPECT().
+					RecordTransfer(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("store error")).
+					Times(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed record transfer: %s"), gomock.Eq("store error")).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+			})
+		})
+	})
+
+	Context("NewStreamLimitMiddleware", Label("unit"), func() {
+		var (
+			log     *mock.MockLogger
+			s       serialization.Serializer
+			l       *mock.MockLimiter
+			handler *mock.MockHandler
+			m       http.Handler
+
+			req           *http.Request
+			requestParam  limiting.AllowRequestParam
+			requestResult *limiting.AllowRequestResult
+			acquireParam  limiting.AcquireParam
+			releaseParam  limiting.ReleaseParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log = mock.NewMockLogger(ctrl)
+			s = serialization.NewJsonSerializer()
+			l = mock.NewMockLimiter(ctrl)
+			handler = mock.NewMockHandler(ctrl)
+			m = rest_app.NewStreamLimitMiddleware(log, s, l)(handler)
+
+			req = httptest.NewRequest(http.MethodGet, "/events", nil)
+			req = req.WithContext(auth.NewClientContext(req.Context(), "mock-client-id"))
+			requestParam = limiting.AllowRequestParam{
+				ClientId: "mock-client-id",
+			}
+			requestResult = &limiting.AllowRequestResult{
+				Allowed:    true,
+				Limit:      20,
+				Remaining:  19,
+				ResetAfter: 50 * time.Millisecond,
+			}
+			acquireParam = limiting.AcquireParam{
+				ClientId: "mock-client-id",
+				Stream:   true,
+			}
+			releaseParam = limiting.ReleaseParam{
+				ClientId: "mock-client-id",
+				Stream:   true,
+			}
+		})
+
+		When("client is not attached", func() {
+			It("should call the handler", func() {
+				r := httptest.NewRequest(http.MethodGet, "/events", nil)
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Eq(r)).
+					Times(1)
+
+				m.ServeHTTP(w, r)
+			})
+		})
+
+		When("request rate limit is exceeded", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(&limiting.AllowRequestResult{
+						Allowed:    false,
+						Limit:      20,
+						RetryAfter: time.Second,
+						ResetAfter: time.Second,
+					}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(resBody.Message).To(Equal("request rate limit exceeded"))
+			})
+		})
+
+		When("concurrent stream limit is exceeded", func() {
+			It("should return too many requests", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(&limiting.AcquireResult{Allowed: false, Limit: 2}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, req)
+
+				resBody := rest_app.ResponseBody{}
+				s.Unmarshal(w.Body.Bytes(), &resBody)
+
+				Expect(w.Code).To(Equal(429))
+				Expect(w.Header().Get("Retry-After")).To(Equal("1"))
+				Expect(resBody.Message).To(Equal("too many concurrent streams"))
+			})
+		})
+
+		When("failed acquire stream", func() {
+			It("should call the handler", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(nil, fmt.Errorf("store error")).
+					Times(1)
+				log.
+					EXPECT().
+					Errorf(gomock.Eq("Failed acquire stream: %s"), gomock.Eq("store error")).
+					Times(1)
+				w := httptest.NewRecorder()
+				handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+			})
+		})
+
+		When("stream is allowed", func() {
+			It("should release the stream once it is closed", func() {
+				l.
+					EXPECT().
+					AllowRequest(gomock.Any(), gomock.Eq(requestParam)).
+					Return(requestResult, nil).
+					Times(1)
+				l.
+					EXPECT().
+					Acquire(gomock.Any(), gomock.Eq(acquireParam)).
+					Return(&limiting.AcquireResult{Allowed: true, Limit: 2}, nil).
+					Times(1)
+				w := httptest.NewRecorder()
+				served := handler.
+					EXPECT().
+					ServeHTTP(gomock.Eq(w), gomock.Any()).
+					Times(1)
+				l.
+					EXPECT().
+					Release(gomock.Any(), gomock.Eq(releaseParam)).
+					Return(nil).
+					After(served).
+					Times(1)
+
+				m.ServeHTTP(w, req)
+
+				Expect(w.Header().Get("RateLimit-Limit")).To(Equal("20"))
+			})
+		})
+	})
+})
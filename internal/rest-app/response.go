@@ -8,10 +8,17 @@ import (
 )
 
 const (
-	CODE_SUCCESS      = "SUCCESS"
-	CODE_ERROR        = "ERROR"
-	CODE_NOT_FOUND    = "NOT_FOUND"
-	CODE_UNAUTHORIZED = "UNAUTHORIZED"
+	CODE_SUCCESS           = "SUCCESS"
+	CODE_ERROR             = "ERROR"
+	CODE_NOT_FOUND         = "NOT_FOUND"
+	CODE_UNAUTHORIZED      = "UNAUTHORIZED"
+	CODE_TOO_LARGE         = "TOO_LARGE"
+	CODE_UNSUPPORTED_MEDIA = "UNSUPPORTED_MEDIA"
+	CODE_INFECTED          = "INFECTED"
+	CODE_UNAVAILABLE       = "UNAVAILABLE"
+	CODE_QUOTA_EXCEEDED    = "QUOTA_EXCEEDED"
+	CODE_FORBIDDEN         = "FORBIDDEN"
+	CODE_TOO_MANY_REQUESTS = "TOO_MANY_REQUESTS"
 )
 
 type ResponseBody struct {
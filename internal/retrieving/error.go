@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrorResourceNotFound = errors.New("resource not found")
+	ErrorForbidden        = errors.New("access to the resource is forbidden")
 )
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/go-seidon/local/internal/compressing"
 	"github.com/go-seidon/local/internal/filesystem"
 	"github.com/go-seidon/local/internal/logging"
 	"github.com/go-seidon/local/internal/repository"
@@ -16,23 +17,30 @@ type Retriever interface {
 }
 
 type RetrieveFileParam struct {
-	FileId string
+	FileId         string
+	ClientId       string
+	AcceptedCodecs []string
+	// only public file is returned, private file is reported as not found
+	// to avoid revealing the existence of the file to anonymous client
+	PublicOnly bool
 }
 
 type RetrieveFileResult struct {
-	Data      io.ReadCloser
+	Data      io.ReadSeekCloser
 	UniqueId  string
 	Name      string
 	Path      string
 	MimeType  string
 	Extension string
+	Codec     string
 	DeletedAt *int64
 }
 
 type retriever struct {
-	fileRepo    repository.FileRepository
-	fileManager filesystem.FileManager
-	log         logging.Logger
+	fileRepo         repository.FileRepository
+	fileManager      filesystem.FileManager
+	log              logging.Logger
+	legacyFileAccess bool
 }
 
 func (s *retriever) RetrieveFile(ctx context.Context, p RetrieveFileParam) (*RetrieveFileResult, error) {
@@ -44,15 +52,35 @@ func (s *retriever) RetrieveFile(ctx context.Context, p RetrieveFileParam) (*Ret
 	}
 
 	file, err := s.fileRepo.RetrieveFile(ctx, repository.RetrieveFileParam{
-		UniqueId: p.FileId,
+		UniqueId:        p.FileId,
+		ClientId:        p.ClientId,
+		AllowUnowned:    s.legacyFileAccess,
+		SkipAccessCheck: p.PublicOnly,
 	})
 	if err != nil {
 		if errors.Is(err, repository.ErrorRecordNotFound) {
 			return nil, ErrorResourceNotFound
 		}
+		if errors.Is(err, repository.ErrorAccessDenied) {
+			return nil, ErrorForbidden
+		}
 		return nil, err
 	}
 
+	if p.PublicOnly && file.Visibility != repository.VISIBILITY_PUBLIC {
+		return nil, ErrorResourceNotFound
+	}
+
+	codec := ""
+	if file.Codec != "" {
+		if isCodecAccepted(file.Codec, p.AcceptedCodecs) {
+			codec = file.Codec
+		} else {
+			ctx = compressing.NewCodecContext(ctx, file.Codec)
+			ctx = compressing.NewSizeContext(ctx, file.Size)
+		}
+	}
+
 	oRes, err := s.fileManager.OpenFile(ctx, filesystem.OpenFileParam{
 		Path: file.Path,
 	})
@@ -70,15 +98,26 @@ func (s *retriever) RetrieveFile(ctx context.Context, p RetrieveFileParam) (*Ret
 		Path:      file.Path,
 		MimeType:  file.MimeType,
 		Extension: file.Extension,
+		Codec:     codec,
 	}
 
 	return res, nil
 }
 
+func isCodecAccepted(codec string, accepted []string) bool {
+	for _, c := range accepted {
+		if c == codec || c == "*" {
+			return true
+		}
+	}
+	return false
+}
+
 type NewRetrieverParam struct {
-	FileRepo    repository.FileRepository
-	FileManager filesystem.FileManager
-	Logger      logging.Logger
+	FileRepo         repository.FileRepository
+	FileManager      filesystem.FileManager
+	Logger           logging.Logger
+	LegacyFileAccess bool
 }
 
 func NewRetriever(p NewRetrieverParam) (*retriever, error) {
@@ -93,9 +132,10 @@ func NewRetriever(p NewRetrieverParam) (*retriever, error) {
 	}
 
 	r := &retriever{
-		fileRepo:    p.FileRepo,
-		fileManager: p.FileManager,
-		log:         p.Logger,
+		fileRepo:         p.FileRepo,
+		fileManager:      p.FileManager,
+		log:              p.Logger,
+		legacyFileAccess: p.LegacyFileAccess,
 	}
 	return r, nil
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/go-seidon/local/internal/compressing"
 	"github.com/go-seidon/local/internal/filesystem"
 	"github.com/go-seidon/local/internal/mock"
 	"github.com/go-seidon/local/internal/repository"
@@ -121,6 +122,7 @@ var _ = Describe("Retriever Service", func() {
 				Path:      "mock-path",
 				MimeType:  "mock-mimetype",
 				Extension: "mock-extension",
+				Size:      1024,
 			}
 			openParam = filesystem.OpenFileParam{
 				Path: retrieveRes.Path,
@@ -171,6 +173,93 @@ var _ = Describe("Retriever Service", func() {
 			})
 		})
 
+		When("client has no access to the file", func() {
+			It("should return error", func() {
+				p.ClientId = "other-client"
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(repository.RetrieveFileParam{
+						UniqueId: p.FileId,
+						ClientId: "other-client",
+					})).
+					Return(nil, repository.ErrorAccessDenied).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(retrieving.ErrorForbidden))
+			})
+		})
+
+		When("private file is retrieved publicly", func() {
+			It("should return error", func() {
+				p.PublicOnly = true
+				retrieveRes.Visibility = repository.VISIBILITY_PRIVATE
+				retrieveParam.SkipAccessCheck = true
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(retrieving.ErrorResourceNotFound))
+			})
+		})
+
+		When("public file is retrieved publicly", func() {
+			It("should return result", func() {
+				p.PublicOnly = true
+				retrieveRes.Visibility = repository.VISIBILITY_PUBLIC
+				retrieveParam.SkipAccessCheck = true
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(openParam)).
+					Return(openRes, nil).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(Equal(r))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("legacy file access is allowed", func() {
+			It("should allow the file without owner", func() {
+				s, _ = retrieving.NewRetriever(retrieving.NewRetrieverParam{
+					FileRepo:         fileRepo,
+					FileManager:      fileManager,
+					Logger:           log,
+					LegacyFileAccess: true,
+				})
+				retrieveParam.AllowUnowned = true
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(openParam)).
+					Return(openRes, nil).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(Equal(r))
+				Expect(err).To(BeNil())
+			})
+		})
+
 		When("failed find file record", func() {
 			It("should return error", func() {
 				fileRepo.
@@ -248,5 +337,51 @@ var _ = Describe("Retriever Service", func() {
 				Expect(err).To(BeNil())
 			})
 		})
+
+		When("codec is accepted by the client", func() {
+			It("should return the compressed data", func() {
+				p.AcceptedCodecs = []string{"br", "gzip"}
+				retrieveRes.Codec = "gzip"
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(ctx), gomock.Eq(openParam)).
+					Return(openRes, nil).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				r.Codec = "gzip"
+				Expect(res).To(Equal(r))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("codec is not accepted by the client", func() {
+			It("should return the decompressed data", func() {
+				retrieveRes.Codec = "gzip"
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				fileManager.
+					EXPECT().
+					OpenFile(gomock.Eq(compressing.NewSizeContext(compressing.NewCodecContext(ctx, "gzip"), 1024)), gomock.Eq(openParam)).
+					Return(openRes, nil).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(Equal(r))
+				Expect(err).To(BeNil())
+			})
+		})
 	})
 })
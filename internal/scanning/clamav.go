@@ -0,0 +1,175 @@
+package scanning
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-seidon/local/internal/datetime"
+)
+
+const (
+	ENGINE_CLAMAV = "clamav"
+
+	CLAMAV_NETWORK_TCP  = "tcp"
+	CLAMAV_NETWORK_UNIX = "unix"
+
+	CLAMAV_DEFAULT_TIMEOUT    = 30 * time.Second
+	CLAMAV_DEFAULT_CHUNK_SIZE = 64 * 1024
+)
+
+type clamavScanner struct {
+	network   string
+	address   string
+	timeout   time.Duration
+	chunkSize int
+	clock     datetime.Clock
+}
+
+// stream the data using clamd INSTREAM command
+// each chunk is prefixed by its length (4 bytes, network order) and terminated by zero length chunk
+func (s *clamavScanner) ScanFile(ctx context.Context, p ScanFileParam) (*ScanFileResult, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(s.clock.Now().Add(s.timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(conn)
+	_, err = w.WriteString("zINSTREAM\x00")
+	if err != nil {
+		return nil, err
+	}
+
+	size := make([]byte, 4)
+	for offset := 0; offset < len(p.Data); offset += s.chunkSize {
+		end := offset + s.chunkSize
+		if end > len(p.Data) {
+			end = len(p.Data)
+		}
+		binary.BigEndian.PutUint32(size, uint32(end-offset))
+		_, err = w.Write(size)
+		if err != nil {
+			return nil, err
+		}
+		_, err = w.Write(p.Data[offset:end])
+		if err != nil {
+			return nil, err
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	_, err = w.Write(size)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Flush()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && len(reply) == 0 {
+		return nil, err
+	}
+
+	res, err := ParseClamavReply(string(bytes.TrimRight(reply, "\x00\n")))
+	if err != nil {
+		return nil, err
+	}
+	res.ScannedAt = s.clock.Now()
+	return res, nil
+}
+
+func ParseClamavReply(reply string) (*ScanFileResult, error) {
+	reply = strings.TrimSpace(reply)
+
+	if strings.HasSuffix(reply, " ERROR") {
+		return nil, fmt.Errorf("clamav error: %s", strings.TrimSuffix(reply, " ERROR"))
+	}
+
+	i := strings.Index(reply, ": ")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid clamav reply")
+	}
+	result := reply[i+2:]
+
+	if result == "OK" {
+		res := &ScanFileResult{
+			Status: STATUS_CLEAN,
+			Engine: ENGINE_CLAMAV,
+		}
+		return res, nil
+	}
+
+	if strings.HasSuffix(result, " FOUND") {
+		res := &ScanFileResult{
+			Status:    STATUS_INFECTED,
+			Engine:    ENGINE_CLAMAV,
+			Signature: strings.TrimSuffix(result, " FOUND"),
+		}
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("invalid clamav reply")
+}
+
+type NewClamavScannerParam struct {
+	Network string
+	Address string
+	Timeout time.Duration
+	// default to 64KB, must not exceed clamd StreamMaxLength
+	ChunkSize int
+	Clock     datetime.Clock
+}
+
+func NewClamavScanner(p NewClamavScannerParam) (*clamavScanner, error) {
+	network := CLAMAV_NETWORK_TCP
+	if p.Network != "" {
+		network = p.Network
+	}
+	if network != CLAMAV_NETWORK_TCP && network != CLAMAV_NETWORK_UNIX {
+		return nil, fmt.Errorf("invalid network")
+	}
+	if p.Address == "" {
+		return nil, fmt.Errorf("address is not specified")
+	}
+	if p.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout")
+	}
+	if p.ChunkSize < 0 {
+		return nil, fmt.Errorf("invalid chunk size")
+	}
+
+	timeout := CLAMAV_DEFAULT_TIMEOUT
+	if p.Timeout > 0 {
+		timeout = p.Timeout
+	}
+	chunkSize := CLAMAV_DEFAULT_CHUNK_SIZE
+	if p.ChunkSize > 0 {
+		chunkSize = p.ChunkSize
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
+	s := &clamavScanner{
+		network:   network,
+		address:   p.Address,
+		timeout:   timeout,
+		chunkSize: chunkSize,
+		clock:     clock,
+	}
+	return s, nil
+}
@@ -0,0 +1,253 @@
+package scanning_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/go-seidon/local/internal/scanning"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// @note: fake clamd which replies once the INSTREAM terminator is received
+func serveClamd(listener net.Listener, reply string, received chan<- []byte) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	cmd, err := r.ReadString(0)
+	if err != nil || cmd != "zINSTREAM\x00" {
+		return
+	}
+
+	data := []byte{}
+	size := make([]byte, 4)
+	for {
+		_, err := io.ReadFull(r, size)
+		if err != nil {
+			return
+		}
+		length := binary.BigEndian.Uint32(size)
+		if length == 0 {
+			break
+		}
+		chunk := make([]byte, length)
+		_, err = io.ReadFull(r, chunk)
+		if err != nil {
+			return
+		}
+		data = append(data, chunk...)
+	}
+	received <- data
+
+	conn.Write([]byte(reply + "\x00"))
+}
+
+var _ = Describe("Clamav Scanner", func() {
+	Context("NewClamavScanner function", Label("unit"), func() {
+		var (
+			p scanning.NewClamavScannerParam
+		)
+
+		BeforeEach(func() {
+			p = scanning.NewClamavScannerParam{
+				Network:   "unix",
+				Address:   "/var/run/clamav/clamd.ctl",
+				Timeout:   5 * time.Second,
+				ChunkSize: 1024,
+			}
+		})
+
+		When("success create scanner", func() {
+			It("should return result", func() {
+				res, err := scanning.NewClamavScanner(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("optional param is not specified", func() {
+			It("should return result", func() {
+				res, err := scanning.NewClamavScanner(scanning.NewClamavScannerParam{
+					Address: "localhost:3310",
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("network is invalid", func() {
+			It("should return error", func() {
+				p.Network = "udp"
+				res, err := scanning.NewClamavScanner(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid network")))
+			})
+		})
+
+		When("address is not specified", func() {
+			It("should return error", func() {
+				p.Address = ""
+				res, err := scanning.NewClamavScanner(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("address is not specified")))
+			})
+		})
+
+		When("timeout is invalid", func() {
+			It("should return error", func() {
+				p.Timeout = -1
+				res, err := scanning.NewClamavScanner(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid timeout")))
+			})
+		})
+
+		When("chunk size is invalid", func() {
+			It("should return error", func() {
+				p.ChunkSize = -1
+				res, err := scanning.NewClamavScanner(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid chunk size")))
+			})
+		})
+	})
+
+	Context("ScanFile function", Label("unit"), func() {
+		var (
+			ctx      context.Context
+			listener net.Listener
+			received chan []byte
+			scanner  scanning.Scanner
+			data     []byte
+		)
+
+		BeforeEach(func() {
+			var err error
+			ctx = context.Background()
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				AbortSuite("failed create listener: " + err.Error())
+			}
+			received = make(chan []byte, 1)
+			scanner, _ = scanning.NewClamavScanner(scanning.NewClamavScannerParam{
+				Address:   listener.Addr().String(),
+				Timeout:   5 * time.Second,
+				ChunkSize: 4,
+			})
+			data = []byte("mock file content")
+		})
+
+		AfterEach(func() {
+			listener.Close()
+		})
+
+		When("file is clean", func() {
+			It("should return clean result", func() {
+				go serveClamd(listener, "stream: OK", received)
+
+				res, err := scanner.ScanFile(ctx, scanning.ScanFileParam{Data: data})
+
+				Expect(err).To(BeNil())
+				Expect(res.Status).To(Equal(scanning.STATUS_CLEAN))
+				Expect(res.Engine).To(Equal(scanning.ENGINE_CLAMAV))
+				Expect(res.ScannedAt).ToNot(BeZero())
+				Expect(<-received).To(Equal(data))
+			})
+		})
+
+		When("file is infected", func() {
+			It("should return infected result", func() {
+				go serveClamd(listener, "stream: Eicar-Test-Signature FOUND", received)
+
+				res, err := scanner.ScanFile(ctx, scanning.ScanFileParam{Data: data})
+
+				Expect(err).To(BeNil())
+				Expect(res.Status).To(Equal(scanning.STATUS_INFECTED))
+				Expect(res.Signature).To(Equal("Eicar-Test-Signature"))
+				Expect(<-received).To(Equal(data))
+			})
+		})
+
+		When("clamd returns error", func() {
+			It("should return error", func() {
+				go serveClamd(listener, "INSTREAM size limit exceeded. ERROR", received)
+
+				res, err := scanner.ScanFile(ctx, scanning.ScanFileParam{Data: data})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("clamav error: INSTREAM size limit exceeded.")))
+			})
+		})
+
+		When("clamd is unavailable", func() {
+			It("should return error", func() {
+				listener.Close()
+
+				res, err := scanner.ScanFile(ctx, scanning.ScanFileParam{Data: data})
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("ParseClamavReply function", Label("unit"), func() {
+		When("reply is ok", func() {
+			It("should return clean result", func() {
+				res, err := scanning.ParseClamavReply("stream: OK")
+
+				Expect(res).To(Equal(&scanning.ScanFileResult{
+					Status: scanning.STATUS_CLEAN,
+					Engine: scanning.ENGINE_CLAMAV,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("reply is found", func() {
+			It("should return infected result", func() {
+				res, err := scanning.ParseClamavReply("stream: Win.Test.EICAR_HDB-1 FOUND")
+
+				Expect(res).To(Equal(&scanning.ScanFileResult{
+					Status:    scanning.STATUS_INFECTED,
+					Engine:    scanning.ENGINE_CLAMAV,
+					Signature: "Win.Test.EICAR_HDB-1",
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("reply is unknown", func() {
+			It("should return error", func() {
+				res, err := scanning.ParseClamavReply("PONG")
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid clamav reply")))
+			})
+		})
+
+		When("reply has unknown result", func() {
+			It("should return error", func() {
+				res, err := scanning.ParseClamavReply("stream: UNKNOWN")
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid clamav reply")))
+			})
+		})
+	})
+})
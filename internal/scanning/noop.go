@@ -0,0 +1,38 @@
+package scanning
+
+import (
+	"context"
+
+	"github.com/go-seidon/local/internal/datetime"
+)
+
+const (
+	ENGINE_NOOP = "noop"
+)
+
+type noopScanner struct {
+	clock datetime.Clock
+}
+
+func (s *noopScanner) ScanFile(ctx context.Context, p ScanFileParam) (*ScanFileResult, error) {
+	res := &ScanFileResult{
+		Status:    STATUS_SKIPPED,
+		Engine:    ENGINE_NOOP,
+		ScannedAt: s.clock.Now(),
+	}
+	return res, nil
+}
+
+type NewNoopScannerParam struct {
+	Clock datetime.Clock
+}
+
+func NewNoopScanner(p NewNoopScannerParam) *noopScanner {
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+	return &noopScanner{
+		clock: clock,
+	}
+}
@@ -0,0 +1,58 @@
+package scanning_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/scanning"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestScanning(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scanning Package")
+}
+
+var _ = Describe("Noop Scanner", func() {
+	Context("NewNoopScanner function", Label("unit"), func() {
+		When("clock is not specified", func() {
+			It("should return result", func() {
+				res := scanning.NewNoopScanner(scanning.NewNoopScannerParam{})
+
+				Expect(res).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("ScanFile function", Label("unit"), func() {
+		When("file is scanned", func() {
+			It("should return skipped result", func() {
+				currentTimestamp := time.Now()
+				clock := mock.NewMockClock(gomock.NewController(GinkgoT()))
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTimestamp).
+					Times(1)
+				scanner := scanning.NewNoopScanner(scanning.NewNoopScannerParam{
+					Clock: clock,
+				})
+
+				res, err := scanner.ScanFile(context.Background(), scanning.ScanFileParam{
+					Data: []byte("content"),
+				})
+
+				Expect(res).To(Equal(&scanning.ScanFileResult{
+					Status:    scanning.STATUS_SKIPPED,
+					Engine:    scanning.ENGINE_NOOP,
+					ScannedAt: currentTimestamp,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
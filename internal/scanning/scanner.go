@@ -0,0 +1,28 @@
+package scanning
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	STATUS_CLEAN    = "clean"
+	STATUS_INFECTED = "infected"
+	STATUS_SKIPPED  = "skipped"
+	STATUS_FAILED   = "failed"
+)
+
+type Scanner interface {
+	ScanFile(ctx context.Context, p ScanFileParam) (*ScanFileResult, error)
+}
+
+type ScanFileParam struct {
+	Data []byte
+}
+
+type ScanFileResult struct {
+	Status    string
+	Engine    string
+	Signature string
+	ScannedAt time.Time
+}
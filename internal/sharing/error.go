@@ -0,0 +1,8 @@
+package sharing
+
+import "errors"
+
+var (
+	ErrorResourceNotFound = errors.New("resource not found")
+	ErrorForbidden        = errors.New("access to the resource is forbidden")
+)
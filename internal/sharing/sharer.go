@@ -0,0 +1,203 @@
+package sharing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/local/internal/logging"
+	"github.com/go-seidon/local/internal/repository"
+)
+
+type Sharer interface {
+	ShareFile(ctx context.Context, p ShareFileParam) (*ShareFileResult, error)
+	UnshareFile(ctx context.Context, p UnshareFileParam) (*UnshareFileResult, error)
+	UpdateVisibility(ctx context.Context, p UpdateVisibilityParam) (*UpdateVisibilityResult, error)
+}
+
+type ShareFileParam struct {
+	FileId     string
+	OwnerId    string
+	ClientId   string
+	Permission string
+}
+
+type ShareFileResult struct {
+	SharedAt time.Time
+}
+
+type UnshareFileParam struct {
+	FileId   string
+	OwnerId  string
+	ClientId string
+}
+
+type UnshareFileResult struct {
+	RevokedAt time.Time
+}
+
+type UpdateVisibilityParam struct {
+	FileId     string
+	OwnerId    string
+	Visibility string
+}
+
+type UpdateVisibilityResult struct {
+	UpdatedAt time.Time
+}
+
+type sharer struct {
+	fileRepo  repository.FileRepository
+	shareRepo repository.ShareRepository
+	log       logging.Logger
+}
+
+func (s *sharer) ShareFile(ctx context.Context, p ShareFileParam) (*ShareFileResult, error) {
+	s.log.Debug("In function: ShareFile")
+	defer s.log.Debug("Returning function: ShareFile")
+
+	if p.FileId == "" {
+		return nil, fmt.Errorf("invalid file id parameter")
+	}
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+	if p.Permission != repository.PERMISSION_READ &&
+		p.Permission != repository.PERMISSION_DELETE {
+		return nil, fmt.Errorf("invalid permission parameter")
+	}
+
+	err := s.checkOwner(ctx, p.FileId, p.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.ClientId == p.OwnerId {
+		return nil, fmt.Errorf("file can not be shared to the owner")
+	}
+
+	share, err := s.shareRepo.CreateShare(ctx, repository.CreateShareParam{
+		FileId:     p.FileId,
+		ClientId:   p.ClientId,
+		Permission: p.Permission,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ShareFileResult{
+		SharedAt: share.CreatedAt,
+	}
+	return res, nil
+}
+
+func (s *sharer) UnshareFile(ctx context.Context, p UnshareFileParam) (*UnshareFileResult, error) {
+	s.log.Debug("In function: UnshareFile")
+	defer s.log.Debug("Returning function: UnshareFile")
+
+	if p.FileId == "" {
+		return nil, fmt.Errorf("invalid file id parameter")
+	}
+	if p.ClientId == "" {
+		return nil, fmt.Errorf("invalid client id parameter")
+	}
+
+	err := s.checkOwner(ctx, p.FileId, p.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.shareRepo.DeleteShare(ctx, repository.DeleteShareParam{
+		FileId:   p.FileId,
+		ClientId: p.ClientId,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	res := &UnshareFileResult{
+		RevokedAt: share.DeletedAt,
+	}
+	return res, nil
+}
+
+func (s *sharer) UpdateVisibility(ctx context.Context, p UpdateVisibilityParam) (*UpdateVisibilityResult, error) {
+	s.log.Debug("In function: UpdateVisibility")
+	defer s.log.Debug("Returning function: UpdateVisibility")
+
+	if p.FileId == "" {
+		return nil, fmt.Errorf("invalid file id parameter")
+	}
+	if p.Visibility != repository.VISIBILITY_PUBLIC &&
+		p.Visibility != repository.VISIBILITY_PRIVATE {
+		return nil, fmt.Errorf("invalid visibility parameter")
+	}
+
+	err := s.checkOwner(ctx, p.FileId, p.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.fileRepo.UpdateFileVisibility(ctx, repository.UpdateFileVisibilityParam{
+		UniqueId:   p.FileId,
+		Visibility: p.Visibility,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return nil, ErrorResourceNotFound
+		}
+		return nil, err
+	}
+
+	res := &UpdateVisibilityResult{
+		UpdatedAt: file.UpdatedAt,
+	}
+	return res, nil
+}
+
+func (s *sharer) checkOwner(ctx context.Context, fileId, ownerId string) error {
+	file, err := s.fileRepo.RetrieveFile(ctx, repository.RetrieveFileParam{
+		UniqueId:        fileId,
+		SkipAccessCheck: true,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrorRecordNotFound) {
+			return ErrorResourceNotFound
+		}
+		return err
+	}
+
+	if file.ClientId == "" || file.ClientId != ownerId {
+		return ErrorForbidden
+	}
+	return nil
+}
+
+type NewSharerParam struct {
+	FileRepo  repository.FileRepository
+	ShareRepo repository.ShareRepository
+	Logger    logging.Logger
+}
+
+func NewSharer(p NewSharerParam) (*sharer, error) {
+	if p.FileRepo == nil {
+		return nil, fmt.Errorf("file repo is not specified")
+	}
+	if p.ShareRepo == nil {
+		return nil, fmt.Errorf("share repo is not specified")
+	}
+	if p.Logger == nil {
+		return nil, fmt.Errorf("logger is not specified")
+	}
+
+	s := &sharer{
+		fileRepo:  p.FileRepo,
+		shareRepo: p.ShareRepo,
+		log:       p.Logger,
+	}
+	return s, nil
+}
@@ -0,0 +1,588 @@
+package sharing_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-seidon/local/internal/mock"
+	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/sharing"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSharing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sharing Package")
+}
+
+var _ = Describe("Sharer Service", func() {
+	Context("NewSharer function", Label("unit"), func() {
+		var (
+			p sharing.NewSharerParam
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			p = sharing.NewSharerParam{
+				FileRepo:  mock.NewMockFileRepository(ctrl),
+				ShareRepo: mock.NewMockShareRepository(ctrl),
+				Logger:    mock.NewMockLogger(ctrl),
+			}
+		})
+
+		When("success create service", func() {
+			It("should return result", func() {
+				res, err := sharing.NewSharer(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file repo is not specified", func() {
+			It("should return error", func() {
+				p.FileRepo = nil
+				res, err := sharing.NewSharer(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file repo is not specified")))
+			})
+		})
+
+		When("share repo is not specified", func() {
+			It("should return error", func() {
+				p.ShareRepo = nil
+				res, err := sharing.NewSharer(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("share repo is not specified")))
+			})
+		})
+
+		When("logger is not specified", func() {
+			It("should return error", func() {
+				p.Logger = nil
+				res, err := sharing.NewSharer(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("logger is not specified")))
+			})
+		})
+	})
+
+	Context("ShareFile function", Label("unit"), func() {
+		var (
+			ctx           context.Context
+			currentTs     time.Time
+			fileRepo      *mock.MockFileRepository
+			shareRepo     *mock.MockShareRepository
+			log           *mock.MockLogger
+			s             sharing.Sharer
+			p             sharing.ShareFileParam
+			retrieveParam repository.RetrieveFileParam
+			retrieveRes   *repository.RetrieveFileResult
+			createParam   repository.CreateShareParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			shareRepo = mock.NewMockShareRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			s, _ = sharing.NewSharer(sharing.NewSharerParam{
+				FileRepo:  fileRepo,
+				ShareRepo: shareRepo,
+				Logger:    log,
+			})
+			p = sharing.ShareFileParam{
+				FileId:     "mock-file-id",
+				OwnerId:    "owner-id",
+				ClientId:   "client-id",
+				Permission: repository.PERMISSION_READ,
+			}
+			retrieveParam = repository.RetrieveFileParam{
+				UniqueId:        "mock-file-id",
+				SkipAccessCheck: true,
+			}
+			retrieveRes = &repository.RetrieveFileResult{
+				UniqueId: "mock-file-id",
+				ClientId: "owner-id",
+			}
+			createParam = repository.CreateShareParam{
+				FileId:     "mock-file-id",
+				ClientId:   "client-id",
+				Permission: repository.PERMISSION_READ,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: ShareFile").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: ShareFile").
+				Times(1)
+		})
+
+		When("file id is not specified", func() {
+			It("should return error", func() {
+				p.FileId = ""
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid file id parameter")))
+			})
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("permission is invalid", func() {
+			It("should return error", func() {
+				p.Permission = "write"
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid permission parameter")))
+			})
+		})
+
+		When("file is not found", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(sharing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed retrieve file", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("client is not the owner", func() {
+			It("should return error", func() {
+				p.OwnerId = "other-id"
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(sharing.ErrorForbidden))
+			})
+		})
+
+		When("file has no owner", func() {
+			It("should return error", func() {
+				retrieveRes.ClientId = ""
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(sharing.ErrorForbidden))
+			})
+		})
+
+		When("file is shared to the owner", func() {
+			It("should return error", func() {
+				p.ClientId = "owner-id"
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("file can not be shared to the owner")))
+			})
+		})
+
+		When("failed create share", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				shareRepo.
+					EXPECT().
+					CreateShare(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success share file", func() {
+			It("should return result", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				shareRepo.
+					EXPECT().
+					CreateShare(gomock.Eq(ctx), gomock.Eq(createParam)).
+					Return(&repository.CreateShareResult{CreatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := s.ShareFile(ctx, p)
+
+				Expect(res).To(Equal(&sharing.ShareFileResult{
+					SharedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UnshareFile function", Label("unit"), func() {
+		var (
+			ctx           context.Context
+			currentTs     time.Time
+			fileRepo      *mock.MockFileRepository
+			shareRepo     *mock.MockShareRepository
+			log           *mock.MockLogger
+			s             sharing.Sharer
+			p             sharing.UnshareFileParam
+			retrieveParam repository.RetrieveFileParam
+			retrieveRes   *repository.RetrieveFileResult
+			deleteParam   repository.DeleteShareParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			shareRepo = mock.NewMockShareRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			s, _ = sharing.NewSharer(sharing.NewSharerParam{
+				FileRepo:  fileRepo,
+				ShareRepo: shareRepo,
+				Logger:    log,
+			})
+			p = sharing.UnshareFileParam{
+				FileId:   "mock-file-id",
+				OwnerId:  "owner-id",
+				ClientId: "client-id",
+			}
+			retrieveParam = repository.RetrieveFileParam{
+				UniqueId:        "mock-file-id",
+				SkipAccessCheck: true,
+			}
+			retrieveRes = &repository.RetrieveFileResult{
+				UniqueId: "mock-file-id",
+				ClientId: "owner-id",
+			}
+			deleteParam = repository.DeleteShareParam{
+				FileId:   "mock-file-id",
+				ClientId: "client-id",
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UnshareFile").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UnshareFile").
+				Times(1)
+		})
+
+		When("file id is not specified", func() {
+			It("should return error", func() {
+				p.FileId = ""
+				res, err := s.UnshareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid file id parameter")))
+			})
+		})
+
+		When("client id is not specified", func() {
+			It("should return error", func() {
+				p.ClientId = ""
+				res, err := s.UnshareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client id parameter")))
+			})
+		})
+
+		When("client is not the owner", func() {
+			It("should return error", func() {
+				p.OwnerId = "other-id"
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				res, err := s.UnshareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(sharing.ErrorForbidden))
+			})
+		})
+
+		When("share is not found", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				shareRepo.
+					EXPECT().
+					DeleteShare(gomock.Eq(ctx), gomock.Eq(deleteParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := s.UnshareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(sharing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed delete share", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				shareRepo.
+					EXPECT().
+					DeleteShare(gomock.Eq(ctx), gomock.Eq(deleteParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.UnshareFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success unshare file", func() {
+			It("should return result", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				shareRepo.
+					EXPECT().
+					DeleteShare(gomock.Eq(ctx), gomock.Eq(deleteParam)).
+					Return(&repository.DeleteShareResult{DeletedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := s.UnshareFile(ctx, p)
+
+				Expect(res).To(Equal(&sharing.UnshareFileResult{
+					RevokedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UpdateVisibility function", Label("unit"), func() {
+		var (
+			ctx           context.Context
+			currentTs     time.Time
+			fileRepo      *mock.MockFileRepository
+			log           *mock.MockLogger
+			s             sharing.Sharer
+			p             sharing.UpdateVisibilityParam
+			retrieveParam repository.RetrieveFileParam
+			retrieveRes   *repository.RetrieveFileResult
+			updateParam   repository.UpdateFileVisibilityParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTs = time.Now()
+			ctrl := gomock.NewController(GinkgoT())
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			log = mock.NewMockLogger(ctrl)
+			s, _ = sharing.NewSharer(sharing.NewSharerParam{
+				FileRepo:  fileRepo,
+				ShareRepo: mock.NewMockShareRepository(ctrl),
+				Logger:    log,
+			})
+			p = sharing.UpdateVisibilityParam{
+				FileId:     "mock-file-id",
+				OwnerId:    "owner-id",
+				Visibility: repository.VISIBILITY_PUBLIC,
+			}
+			retrieveParam = repository.RetrieveFileParam{
+				UniqueId:        "mock-file-id",
+				SkipAccessCheck: true,
+			}
+			retrieveRes = &repository.RetrieveFileResult{
+				UniqueId: "mock-file-id",
+				ClientId: "owner-id",
+			}
+			updateParam = repository.UpdateFileVisibilityParam{
+				UniqueId:   "mock-file-id",
+				Visibility: repository.VISIBILITY_PUBLIC,
+			}
+
+			log.
+				EXPECT().
+				Debug("In function: UpdateVisibility").
+				Times(1)
+			log.
+				EXPECT().
+				Debug("Returning function: UpdateVisibility").
+				Times(1)
+		})
+
+		When("file id is not specified", func() {
+			It("should return error", func() {
+				p.FileId = ""
+				res, err := s.UpdateVisibility(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid file id parameter")))
+			})
+		})
+
+		When("visibility is invalid", func() {
+			It("should return error", func() {
+				p.Visibility = "hidden"
+				res, err := s.UpdateVisibility(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid visibility parameter")))
+			})
+		})
+
+		When("client is not the owner", func() {
+			It("should return error", func() {
+				p.OwnerId = "other-id"
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+
+				res, err := s.UpdateVisibility(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(sharing.ErrorForbidden))
+			})
+		})
+
+		When("file is deleted in the meantime", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFileVisibility(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+
+				res, err := s.UpdateVisibility(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(sharing.ErrorResourceNotFound))
+			})
+		})
+
+		When("failed update visibility", func() {
+			It("should return error", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFileVisibility(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.UpdateVisibility(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("success update visibility", func() {
+			It("should return result", func() {
+				fileRepo.
+					EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(retrieveParam)).
+					Return(retrieveRes, nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					UpdateFileVisibility(gomock.Eq(ctx), gomock.Eq(updateParam)).
+					Return(&repository.UpdateFileVisibilityResult{UpdatedAt: currentTs}, nil).
+					Times(1)
+
+				res, err := s.UpdateVisibility(ctx, p)
+
+				Expect(res).To(Equal(&sharing.UpdateVisibilityResult{
+					UpdatedAt: currentTs,
+				}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
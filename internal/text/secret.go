@@ -0,0 +1,30 @@
+package text
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+const (
+	DEFAULT_SECRET_SIZE = 32
+)
+
+type randomSecret struct {
+	size int
+}
+
+func (i *randomSecret) GenerateId() (string, error) {
+	b := make([]byte, i.size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func NewRandomSecret(size int) *randomSecret {
+	if size <= 0 {
+		size = DEFAULT_SECRET_SIZE
+	}
+	return &randomSecret{size: size}
+}
@@ -0,0 +1,39 @@
+package text_test
+
+import (
+	"encoding/base64"
+
+	"github.com/go-seidon/local/internal/text"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Random Secret", func() {
+	Context("GenerateId function", Label("unit"), func() {
+		When("size is not specified", func() {
+			It("should return 32 bytes secret", func() {
+				secret := text.NewRandomSecret(0)
+				res, err := secret.GenerateId()
+
+				b, _ := base64.RawURLEncoding.DecodeString(res)
+				Expect(err).To(BeNil())
+				Expect(b).To(HaveLen(32))
+			})
+		})
+
+		When("size is specified", func() {
+			It("should return different secret", func() {
+				secret := text.NewRandomSecret(16)
+				res1, err1 := secret.GenerateId()
+				res2, err2 := secret.GenerateId()
+
+				b, _ := base64.RawURLEncoding.DecodeString(res1)
+				Expect(err1).To(BeNil())
+				Expect(err2).To(BeNil())
+				Expect(b).To(HaveLen(16))
+				Expect(res1).ToNot(Equal(res2))
+			})
+		})
+	})
+})
@@ -3,5 +3,11 @@ package uploading
 import "errors"
 
 var (
-	ErrorResourceExists = errors.New("resource already exists")
+	ErrorResourceExists     = errors.New("resource already exists")
+	ErrorMimetypeNotAllowed = errors.New("mimetype is not allowed")
+	ErrorExtensionMismatch  = errors.New("file extension does not match its content")
+	ErrorFileTooLarge       = errors.New("file size exceeds the limit")
+	ErrorFileInfected       = errors.New("file is infected")
+	ErrorScanFailed         = errors.New("failed scan file")
+	ErrorQuotaExceeded      = errors.New("storage quota exceeded")
 )
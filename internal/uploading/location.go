@@ -1,21 +1,61 @@
 package uploading
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/go-seidon/local/internal/datetime"
 )
 
+const (
+	LOCATION_DAILY    = "daily"
+	LOCATION_HOURLY   = "hourly"
+	LOCATION_HASH     = "hash"
+	LOCATION_CLIENT   = "client"
+	LOCATION_TEMPLATE = "template"
+
+	LOCATION_NO_CLIENT = "anonymous"
+)
+
 type UploadLocation interface {
-	GetLocation() string
+	GetLocation(p GetLocationParam) string
+}
+
+type GetLocationParam struct {
+	UniqueId string
+	ClientId string
+	Time     time.Time
+}
+
+type locationContextKey struct{}
+
+func NewLocationContext(ctx context.Context, l UploadLocation) context.Context {
+	return context.WithValue(ctx, locationContextKey{}, l)
+}
+
+func LocationFromContext(ctx context.Context) (UploadLocation, bool) {
+	l, ok := ctx.Value(locationContextKey{}).(UploadLocation)
+	return l, ok
+}
+
+func locationTime(clock datetime.Clock, t time.Time) time.Time {
+	if t.IsZero() {
+		return clock.Now()
+	}
+	return t
 }
 
 type dailyRotate struct {
 	clock datetime.Clock
 }
 
-func (l *dailyRotate) GetLocation() string {
-	currentTimestamp := l.clock.Now()
+func (l *dailyRotate) GetLocation(p GetLocationParam) string {
+	currentTimestamp := locationTime(l.clock, p.Time)
 	year := currentTimestamp.Format("2006")
 	month := currentTimestamp.Format("01")
 	day := currentTimestamp.Format("02")
@@ -39,3 +79,170 @@ func NewDailyRotate(p NewDailyRotateParam) *dailyRotate {
 	}
 	return l
 }
+
+type hourlyRotate struct {
+	clock datetime.Clock
+}
+
+func (l *hourlyRotate) GetLocation(p GetLocationParam) string {
+	currentTimestamp := locationTime(l.clock, p.Time)
+	return currentTimestamp.Format("2006/01/02/15")
+}
+
+type NewHourlyRotateParam struct {
+	Clock datetime.Clock
+}
+
+func NewHourlyRotate(p NewHourlyRotateParam) *hourlyRotate {
+	var clock datetime.Clock
+	if p.Clock != nil {
+		clock = p.Clock
+	} else {
+		clock = datetime.NewClock()
+	}
+
+	l := &hourlyRotate{
+		clock: clock,
+	}
+	return l
+}
+
+type hashPrefix struct {
+	depth int
+	width int
+}
+
+func (l *hashPrefix) GetLocation(p GetLocationParam) string {
+	sum := sha256.Sum256([]byte(p.UniqueId))
+	hash := hex.EncodeToString(sum[:])
+
+	dirs := make([]string, 0, l.depth)
+	for i := 0; i < l.depth; i++ {
+		dirs = append(dirs, hash[i*l.width:(i+1)*l.width])
+	}
+	return strings.Join(dirs, "/")
+}
+
+type NewHashPrefixParam struct {
+	Depth int
+	Width int
+}
+
+func NewHashPrefix(p NewHashPrefixParam) (*hashPrefix, error) {
+	depth := 2
+	if p.Depth != 0 {
+		depth = p.Depth
+	}
+	width := 2
+	if p.Width != 0 {
+		width = p.Width
+	}
+	if depth < 0 || width < 0 || depth*width > sha256.Size*2 {
+		return nil, fmt.Errorf("invalid hash prefix size")
+	}
+
+	l := &hashPrefix{
+		depth: depth,
+		width: width,
+	}
+	return l, nil
+}
+
+var unsafeDirChars = regexp.MustCompile(`[^A-Za-z0-9_\-]`)
+
+// client id is sanitized to prevent it from escaping the upload directory
+func clientDirectory(clientId string) string {
+	if clientId == "" {
+		return LOCATION_NO_CLIENT
+	}
+	return unsafeDirChars.ReplaceAllString(clientId, "_")
+}
+
+type clientPrefix struct {
+	location UploadLocation
+}
+
+func (l *clientPrefix) GetLocation(p GetLocationParam) string {
+	return fmt.Sprintf("%s/%s", clientDirectory(p.ClientId), l.location.GetLocation(p))
+}
+
+type NewClientPrefixParam struct {
+	Location UploadLocation
+}
+
+func NewClientPrefix(p NewClientPrefixParam) *clientPrefix {
+	var location UploadLocation
+	if p.Location != nil {
+		location = p.Location
+	} else {
+		location = NewDailyRotate(NewDailyRotateParam{})
+	}
+
+	l := &clientPrefix{
+		location: location,
+	}
+	return l
+}
+
+var templatePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+type templateLocation struct {
+	template string
+	clock    datetime.Clock
+	hash     *hashPrefix
+}
+
+func (l *templateLocation) GetLocation(p GetLocationParam) string {
+	currentTimestamp := locationTime(l.clock, p.Time)
+	replacer := strings.NewReplacer(
+		"{client}", clientDirectory(p.ClientId),
+		"{yyyy}", currentTimestamp.Format("2006"),
+		"{mm}", currentTimestamp.Format("01"),
+		"{dd}", currentTimestamp.Format("02"),
+		"{hh}", currentTimestamp.Format("15"),
+		"{hash}", l.hash.GetLocation(p),
+	)
+	return replacer.Replace(l.template)
+}
+
+type NewTemplateLocationParam struct {
+	// supported placeholder: {client}, {yyyy}, {mm}, {dd}, {hh}, {hash}
+	// e.g: {client}/{yyyy}/{mm}
+	Template string
+	Clock    datetime.Clock
+}
+
+func NewTemplateLocation(p NewTemplateLocationParam) (*templateLocation, error) {
+	template := strings.Trim(p.Template, "/")
+	if template == "" {
+		return nil, fmt.Errorf("template is not specified")
+	}
+
+	for _, segment := range strings.Split(template, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid location template")
+		}
+	}
+	for _, placeholder := range templatePlaceholder.FindAllString(template, -1) {
+		switch placeholder {
+		case "{client}", "{yyyy}", "{mm}", "{dd}", "{hh}", "{hash}":
+		default:
+			return nil, fmt.Errorf("invalid location template")
+		}
+	}
+
+	var clock datetime.Clock
+	if p.Clock != nil {
+		clock = p.Clock
+	} else {
+		clock = datetime.NewClock()
+	}
+	hash, _ := NewHashPrefix(NewHashPrefixParam{})
+
+	l := &templateLocation{
+		template: template,
+		clock:    clock,
+		hash:     hash,
+	}
+	return l, nil
+}
@@ -1,6 +1,8 @@
 package uploading_test
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-seidon/local/internal/mock"
@@ -67,7 +69,7 @@ var _ = Describe("Daily Rotate Service", func() {
 					Return(currentTimestamp).
 					Times(1)
 
-				res := s.GetLocation()
+				res := s.GetLocation(uploading.GetLocationParam{})
 
 				Expect(res).To(Equal("2022/02/28"))
 			})
@@ -82,10 +84,312 @@ var _ = Describe("Daily Rotate Service", func() {
 					Return(currentTimestamp).
 					Times(1)
 
-				res := s.GetLocation()
+				res := s.GetLocation(uploading.GetLocationParam{})
 
 				Expect(res).To(Equal("1990/02/01"))
 			})
 		})
 	})
+
+	Context("GetLocation function with time", Label("unit"), func() {
+		When("time is specified", func() {
+			It("should use the specified time", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				clock := mock.NewMockClock(ctrl)
+				clock.
+					EXPECT().
+					Now().
+					Times(0)
+				s := uploading.NewDailyRotate(uploading.NewDailyRotateParam{
+					Clock: clock,
+				})
+				uploadedAt, _ := time.Parse("2006-01-02", "2021-12-31")
+
+				res := s.GetLocation(uploading.GetLocationParam{
+					Time: uploadedAt,
+				})
+
+				Expect(res).To(Equal("2021/12/31"))
+			})
+		})
+	})
+})
+
+var _ = Describe("Hourly Rotate Service", func() {
+	Context("NewHourlyRotate function", Label("unit"), func() {
+		When("clock is not specified", func() {
+			It("should return result", func() {
+				res := uploading.NewHourlyRotate(uploading.NewHourlyRotateParam{})
+
+				Expect(res).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("GetLocation function", Label("unit"), func() {
+		When("function is called", func() {
+			It("should return result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				clock := mock.NewMockClock(ctrl)
+				currentTimestamp, _ := time.Parse("2006-01-02 15", "2022-02-28 07")
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTimestamp).
+					Times(1)
+				s := uploading.NewHourlyRotate(uploading.NewHourlyRotateParam{
+					Clock: clock,
+				})
+
+				res := s.GetLocation(uploading.GetLocationParam{})
+
+				Expect(res).To(Equal("2022/02/28/07"))
+			})
+		})
+	})
+})
+
+var _ = Describe("Hash Prefix Service", func() {
+	Context("NewHashPrefix function", Label("unit"), func() {
+		When("param is not specified", func() {
+			It("should return result", func() {
+				res, err := uploading.NewHashPrefix(uploading.NewHashPrefixParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("size is invalid", func() {
+			It("should return error", func() {
+				res, err := uploading.NewHashPrefix(uploading.NewHashPrefixParam{
+					Depth: 33,
+					Width: 2,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid hash prefix size")))
+			})
+		})
+
+		When("size is negative", func() {
+			It("should return error", func() {
+				res, err := uploading.NewHashPrefix(uploading.NewHashPrefixParam{
+					Depth: -1,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid hash prefix size")))
+			})
+		})
+	})
+
+	Context("GetLocation function", Label("unit"), func() {
+		When("default size is used", func() {
+			It("should return result", func() {
+				s, _ := uploading.NewHashPrefix(uploading.NewHashPrefixParam{})
+
+				res := s.GetLocation(uploading.GetLocationParam{
+					UniqueId: "mock-unique-id",
+				})
+
+				Expect(res).To(MatchRegexp("^[0-9a-f]{2}/[0-9a-f]{2}$"))
+				Expect(res).To(Equal(s.GetLocation(uploading.GetLocationParam{
+					UniqueId: "mock-unique-id",
+				})))
+			})
+		})
+
+		When("custom size is used", func() {
+			It("should return result", func() {
+				s, _ := uploading.NewHashPrefix(uploading.NewHashPrefixParam{
+					Depth: 3,
+					Width: 1,
+				})
+
+				res := s.GetLocation(uploading.GetLocationParam{
+					UniqueId: "mock-unique-id",
+				})
+
+				Expect(res).To(MatchRegexp("^[0-9a-f]/[0-9a-f]/[0-9a-f]$"))
+			})
+		})
+	})
+})
+
+var _ = Describe("Client Prefix Service", func() {
+	Context("NewClientPrefix function", Label("unit"), func() {
+		When("location is not specified", func() {
+			It("should return result", func() {
+				res := uploading.NewClientPrefix(uploading.NewClientPrefixParam{})
+
+				Expect(res).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("GetLocation function", Label("unit"), func() {
+		var (
+			s        uploading.UploadLocation
+			location *mock.MockUploadLocation
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			location = mock.NewMockUploadLocation(ctrl)
+			s = uploading.NewClientPrefix(uploading.NewClientPrefixParam{
+				Location: location,
+			})
+		})
+
+		When("client is specified", func() {
+			It("should return result", func() {
+				p := uploading.GetLocationParam{
+					ClientId: "mock-client-id",
+				}
+				location.
+					EXPECT().
+					GetLocation(gomock.Eq(p)).
+					Return("2022/02/28").
+					Times(1)
+
+				res := s.GetLocation(p)
+
+				Expect(res).To(Equal("mock-client-id/2022/02/28"))
+			})
+		})
+
+		When("client is not specified", func() {
+			It("should return result", func() {
+				p := uploading.GetLocationParam{}
+				location.
+					EXPECT().
+					GetLocation(gomock.Eq(p)).
+					Return("2022/02/28").
+					Times(1)
+
+				res := s.GetLocation(p)
+
+				Expect(res).To(Equal("anonymous/2022/02/28"))
+			})
+		})
+
+		When("client contains unsafe character", func() {
+			It("should return sanitized result", func() {
+				p := uploading.GetLocationParam{
+					ClientId: "../etc/passwd",
+				}
+				location.
+					EXPECT().
+					GetLocation(gomock.Eq(p)).
+					Return("2022/02/28").
+					Times(1)
+
+				res := s.GetLocation(p)
+
+				Expect(res).To(Equal("___etc_passwd/2022/02/28"))
+			})
+		})
+	})
+})
+
+var _ = Describe("Template Location Service", func() {
+	Context("NewTemplateLocation function", Label("unit"), func() {
+		When("template is valid", func() {
+			It("should return result", func() {
+				res, err := uploading.NewTemplateLocation(uploading.NewTemplateLocationParam{
+					Template: "{client}/{yyyy}/{mm}",
+				})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("template is not specified", func() {
+			It("should return error", func() {
+				res, err := uploading.NewTemplateLocation(uploading.NewTemplateLocationParam{
+					Template: "/",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("template is not specified")))
+			})
+		})
+
+		When("template contains unknown placeholder", func() {
+			It("should return error", func() {
+				res, err := uploading.NewTemplateLocation(uploading.NewTemplateLocationParam{
+					Template: "{client}/{year}",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid location template")))
+			})
+		})
+
+		When("template contains relative segment", func() {
+			It("should return error", func() {
+				res, err := uploading.NewTemplateLocation(uploading.NewTemplateLocationParam{
+					Template: "{client}/../{yyyy}",
+				})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid location template")))
+			})
+		})
+	})
+
+	Context("GetLocation function", Label("unit"), func() {
+		When("every placeholder is used", func() {
+			It("should return result", func() {
+				ctrl := gomock.NewController(GinkgoT())
+				clock := mock.NewMockClock(ctrl)
+				currentTimestamp, _ := time.Parse("2006-01-02 15", "2022-02-28 07")
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTimestamp).
+					Times(1)
+				s, _ := uploading.NewTemplateLocation(uploading.NewTemplateLocationParam{
+					Template: "/files/{client}/{yyyy}/{mm}/{dd}/{hh}/{hash}/",
+					Clock:    clock,
+				})
+				hash, _ := uploading.NewHashPrefix(uploading.NewHashPrefixParam{})
+				p := uploading.GetLocationParam{
+					UniqueId: "mock-unique-id",
+					ClientId: "mock-client-id",
+				}
+
+				res := s.GetLocation(p)
+
+				Expect(res).To(Equal("files/mock-client-id/2022/02/28/07/" + hash.GetLocation(p)))
+			})
+		})
+	})
+})
+
+var _ = Describe("Location Context", func() {
+	Context("LocationFromContext function", Label("unit"), func() {
+		When("location is not specified", func() {
+			It("should return false", func() {
+				res, ok := uploading.LocationFromContext(context.Background())
+
+				Expect(res).To(BeNil())
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("location is specified", func() {
+			It("should return location", func() {
+				location := uploading.NewDailyRotate(uploading.NewDailyRotateParam{})
+				ctx := uploading.NewLocationContext(context.Background(), location)
+
+				res, ok := uploading.LocationFromContext(ctx)
+
+				Expect(res).To(Equal(location))
+				Expect(ok).To(BeTrue())
+			})
+		})
+	})
 })
@@ -0,0 +1,184 @@
+package uploading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type UploadPolicy interface {
+	ValidateFile(ctx context.Context, p ValidateFileParam) error
+}
+
+type ValidateFileParam struct {
+	ClientId  string
+	Mimetype  string
+	Extension string
+	Size      int64
+}
+
+type MimetypeLimit struct {
+	Mimetype string
+	MaxSize  int64
+}
+
+type ClientLimit struct {
+	ClientId string
+	MaxSize  int64
+}
+
+var extensionMimetypes = map[string]string{
+	"avi":  "video/x-msvideo",
+	"bmp":  "image/bmp",
+	"css":  "text/css",
+	"csv":  "text/csv",
+	"gif":  "image/gif",
+	"htm":  "text/html",
+	"html": "text/html",
+	"ico":  "image/x-icon",
+	"jpeg": "image/jpeg",
+	"jpg":  "image/jpeg",
+	"js":   "text/javascript",
+	"json": "application/json",
+	"mp3":  "audio/mpeg",
+	"mp4":  "video/mp4",
+	"ogg":  "audio/ogg",
+	"pdf":  "application/pdf",
+	"png":  "image/png",
+	"svg":  "image/svg+xml",
+	"tif":  "image/tiff",
+	"tiff": "image/tiff",
+	"txt":  "text/plain",
+	"wav":  "audio/wav",
+	"webm": "video/webm",
+	"webp": "image/webp",
+	"woff": "font/woff",
+	"xml":  "text/xml",
+	"zip":  "application/zip",
+}
+
+// content type detection returns these for any content it can not recognize,
+// so they are not taken as evidence of extension mismatch
+var genericMimetypes = map[string]bool{
+	"application/octet-stream": true,
+	"application/zip":          true,
+	"text/plain":               true,
+	"text/xml":                 true,
+}
+
+type uploadPolicy struct {
+	allowedMimetypes map[string]bool
+	mimetypeLimits   map[string]int64
+	clientLimits     map[string]int64
+	checkExtension   bool
+}
+
+func (up *uploadPolicy) ValidateFile(ctx context.Context, p ValidateFileParam) error {
+	mimetype := NormalizeMimetype(p.Mimetype)
+
+	if len(up.allowedMimetypes) > 0 {
+		_, exact := up.allowedMimetypes[mimetype]
+		_, wildcard := up.allowedMimetypes[wildcardMimetype(mimetype)]
+		if !exact && !wildcard {
+			return ErrorMimetypeNotAllowed
+		}
+	}
+
+	if up.checkExtension && IsExtensionMismatch(p.Extension, mimetype) {
+		return ErrorExtensionMismatch
+	}
+
+	maxSize, ok := up.mimetypeLimits[mimetype]
+	if !ok {
+		maxSize, ok = up.mimetypeLimits[wildcardMimetype(mimetype)]
+	}
+	if ok && p.Size > maxSize {
+		return ErrorFileTooLarge
+	}
+
+	maxSize, ok = up.clientLimits[p.ClientId]
+	if ok && p.Size > maxSize {
+		return ErrorFileTooLarge
+	}
+
+	return nil
+}
+
+func NormalizeMimetype(mimetype string) string {
+	m := strings.Split(mimetype, ";")[0]
+	return strings.ToLower(strings.TrimSpace(m))
+}
+
+// only the top level type is compared since detection is not granular enough, e.g: image/png vs image/jpeg
+func IsExtensionMismatch(extension, mimetype string) bool {
+	if extension == "" || mimetype == "" {
+		return false
+	}
+
+	expected := extensionMimetypes[strings.ToLower(extension)]
+	if expected == "" || expected == mimetype {
+		return false
+	}
+	if genericMimetypes[mimetype] {
+		return false
+	}
+
+	return topLevelMimetype(expected) != topLevelMimetype(mimetype)
+}
+
+func wildcardMimetype(mimetype string) string {
+	return topLevelMimetype(mimetype) + "/*"
+}
+
+func topLevelMimetype(mimetype string) string {
+	return strings.Split(mimetype, "/")[0]
+}
+
+type NewUploadPolicyParam struct {
+	AllowedMimetypes []string
+	MimetypeLimits   []MimetypeLimit
+	ClientLimits     []ClientLimit
+	CheckExtension   bool
+}
+
+func NewUploadPolicy(p NewUploadPolicyParam) (*uploadPolicy, error) {
+	allowedMimetypes := map[string]bool{}
+	for _, m := range p.AllowedMimetypes {
+		mimetype := NormalizeMimetype(m)
+		if mimetype == "" {
+			return nil, fmt.Errorf("invalid allowed mimetype")
+		}
+		allowedMimetypes[mimetype] = true
+	}
+
+	mimetypeLimits := map[string]int64{}
+	for _, l := range p.MimetypeLimits {
+		mimetype := NormalizeMimetype(l.Mimetype)
+		if mimetype == "" {
+			return nil, fmt.Errorf("invalid mimetype limit")
+		}
+		if l.MaxSize <= 0 {
+			return nil, fmt.Errorf("invalid mimetype max size")
+		}
+		mimetypeLimits[mimetype] = l.MaxSize
+	}
+
+	clientLimits := map[string]int64{}
+	for _, l := range p.ClientLimits {
+		if strings.TrimSpace(l.ClientId) == "" {
+			return nil, fmt.Errorf("invalid client limit")
+		}
+		if l.MaxSize <= 0 {
+			return nil, fmt.Errorf("invalid client max size")
+		}
+		clientLimits[l.ClientId] = l.MaxSize
+	}
+
+	up := &uploadPolicy{
+		allowedMimetypes: allowedMimetypes,
+		mimetypeLimits:   mimetypeLimits,
+		clientLimits:     clientLimits,
+		checkExtension:   p.CheckExtension,
+	}
+	return up, nil
+}
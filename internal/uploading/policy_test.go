@@ -0,0 +1,227 @@
+package uploading_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-seidon/local/internal/uploading"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Upload Policy", func() {
+	Context("NewUploadPolicy function", Label("unit"), func() {
+		var (
+			p uploading.NewUploadPolicyParam
+		)
+
+		BeforeEach(func() {
+			p = uploading.NewUploadPolicyParam{
+				AllowedMimetypes: []string{"image/*", "application/pdf"},
+				MimetypeLimits: []uploading.MimetypeLimit{
+					{Mimetype: "image/*", MaxSize: 100},
+				},
+				ClientLimits: []uploading.ClientLimit{
+					{ClientId: "mock-client-id", MaxSize: 100},
+				},
+				CheckExtension: true,
+			}
+		})
+
+		When("param is empty", func() {
+			It("should return result", func() {
+				res, err := uploading.NewUploadPolicy(uploading.NewUploadPolicyParam{})
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("param is specified", func() {
+			It("should return result", func() {
+				res, err := uploading.NewUploadPolicy(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("allowed mimetype is invalid", func() {
+			It("should return error", func() {
+				p.AllowedMimetypes = []string{" "}
+				res, err := uploading.NewUploadPolicy(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid allowed mimetype")))
+			})
+		})
+
+		When("mimetype limit is invalid", func() {
+			It("should return error", func() {
+				p.MimetypeLimits = []uploading.MimetypeLimit{{MaxSize: 100}}
+				res, err := uploading.NewUploadPolicy(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid mimetype limit")))
+			})
+		})
+
+		When("mimetype max size is invalid", func() {
+			It("should return error", func() {
+				p.MimetypeLimits = []uploading.MimetypeLimit{{Mimetype: "image/png"}}
+				res, err := uploading.NewUploadPolicy(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid mimetype max size")))
+			})
+		})
+
+		When("client limit is invalid", func() {
+			It("should return error", func() {
+				p.ClientLimits = []uploading.ClientLimit{{MaxSize: 100}}
+				res, err := uploading.NewUploadPolicy(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client limit")))
+			})
+		})
+
+		When("client max size is invalid", func() {
+			It("should return error", func() {
+				p.ClientLimits = []uploading.ClientLimit{{ClientId: "mock-client-id", MaxSize: -1}}
+				res, err := uploading.NewUploadPolicy(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid client max size")))
+			})
+		})
+	})
+
+	Context("ValidateFile function", Label("unit"), func() {
+		var (
+			ctx    context.Context
+			policy uploading.UploadPolicy
+			p      uploading.ValidateFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			policy, _ = uploading.NewUploadPolicy(uploading.NewUploadPolicyParam{
+				AllowedMimetypes: []string{"image/*", "application/pdf", "text/plain"},
+				MimetypeLimits: []uploading.MimetypeLimit{
+					{Mimetype: "image/*", MaxSize: 300},
+					{Mimetype: "image/gif", MaxSize: 100},
+				},
+				ClientLimits: []uploading.ClientLimit{
+					{ClientId: "limited-client", MaxSize: 50},
+				},
+				CheckExtension: true,
+			})
+			p = uploading.ValidateFileParam{
+				ClientId:  "mock-client-id",
+				Mimetype:  "image/jpeg",
+				Extension: "jpg",
+				Size:      200,
+			}
+		})
+
+		When("file is valid", func() {
+			It("should return nil", func() {
+				err := policy.ValidateFile(ctx, p)
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("mimetype is not allowed", func() {
+			It("should return error", func() {
+				p.Mimetype = "application/x-msdownload"
+				p.Extension = "exe"
+				err := policy.ValidateFile(ctx, p)
+
+				Expect(err).To(Equal(uploading.ErrorMimetypeNotAllowed))
+			})
+		})
+
+		When("mimetype contains parameter", func() {
+			It("should return nil", func() {
+				p.Mimetype = "text/plain; charset=utf-8"
+				p.Extension = "json"
+				err := policy.ValidateFile(ctx, p)
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("extension does not match the content", func() {
+			It("should return error", func() {
+				p.Mimetype = "application/pdf"
+				err := policy.ValidateFile(ctx, p)
+
+				Expect(err).To(Equal(uploading.ErrorExtensionMismatch))
+			})
+		})
+
+		When("size exceeds wildcard mimetype limit", func() {
+			It("should return error", func() {
+				p.Size = 301
+				err := policy.ValidateFile(ctx, p)
+
+				Expect(err).To(Equal(uploading.ErrorFileTooLarge))
+			})
+		})
+
+		When("size exceeds exact mimetype limit", func() {
+			It("should return error", func() {
+				p.Mimetype = "image/gif"
+				p.Extension = "gif"
+				p.Size = 101
+				err := policy.ValidateFile(ctx, p)
+
+				Expect(err).To(Equal(uploading.ErrorFileTooLarge))
+			})
+		})
+
+		When("size exceeds client limit", func() {
+			It("should return error", func() {
+				p.ClientId = "limited-client"
+				err := policy.ValidateFile(ctx, p)
+
+				Expect(err).To(Equal(uploading.ErrorFileTooLarge))
+			})
+		})
+	})
+
+	Context("IsExtensionMismatch function", Label("unit"), func() {
+		When("extension is empty", func() {
+			It("should return false", func() {
+				Expect(uploading.IsExtensionMismatch("", "image/png")).To(BeFalse())
+			})
+		})
+
+		When("extension is unknown", func() {
+			It("should return false", func() {
+				Expect(uploading.IsExtensionMismatch("unknown-ext", "image/png")).To(BeFalse())
+			})
+		})
+
+		When("detected mimetype is generic", func() {
+			It("should return false", func() {
+				Expect(uploading.IsExtensionMismatch("pdf", "application/octet-stream")).To(BeFalse())
+				Expect(uploading.IsExtensionMismatch("json", "text/plain")).To(BeFalse())
+			})
+		})
+
+		When("top level type is equal", func() {
+			It("should return false", func() {
+				Expect(uploading.IsExtensionMismatch("PNG", "image/jpeg")).To(BeFalse())
+			})
+		})
+
+		When("top level type is different", func() {
+			It("should return true", func() {
+				Expect(uploading.IsExtensionMismatch("jpg", "application/pdf")).To(BeTrue())
+			})
+		})
+	})
+})
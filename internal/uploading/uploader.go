@@ -2,16 +2,37 @@ package uploading
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-seidon/local/internal/compressing"
+	"github.com/go-seidon/local/internal/datetime"
 	"github.com/go-seidon/local/internal/filesystem"
+	"github.com/go-seidon/local/internal/imaging"
 	"github.com/go-seidon/local/internal/logging"
 	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/scanning"
 	"github.com/go-seidon/local/internal/text"
 )
 
+const (
+	EXIF_POLICY_KEEP         = "keep"
+	EXIF_POLICY_STRIP        = "strip"
+	EXIF_POLICY_ORIENT_STRIP = "orient-strip"
+
+	ATTRIBUTE_WIDTH       = "width"
+	ATTRIBUTE_HEIGHT      = "height"
+	ATTRIBUTE_ORIENTATION = "orientation"
+	ATTRIBUTE_EXIF_PREFIX = "exif_"
+
+	SCAN_FAILURE_CLOSED = "closed"
+	SCAN_FAILURE_OPEN   = "open"
+)
+
 type Uploader interface {
 	UploadFile(ctx context.Context, opts ...UploadFileOption) (*UploadFileResult, error)
 }
@@ -22,6 +43,10 @@ type UploadFileParam struct {
 
 	fileDir string
 
+	clientId string
+
+	visibility string
+
 	fileName      string
 	fileMimetype  string
 	fileExtension string
@@ -50,6 +75,18 @@ func WithDirectory(path string) UploadFileOption {
 	}
 }
 
+func WithClient(clientId string) UploadFileOption {
+	return func(ufp *UploadFileParam) {
+		ufp.clientId = clientId
+	}
+}
+
+func WithVisibility(visibility string) UploadFileOption {
+	return func(ufp *UploadFileParam) {
+		ufp.visibility = visibility
+	}
+}
+
 func WithFileInfo(name, mimetype, extension string, size int64) UploadFileOption {
 	return func(ufp *UploadFileParam) {
 		ufp.fileName = name
@@ -66,6 +103,9 @@ type UploadFileResult struct {
 	Mimetype   string
 	Extension  string
 	Size       int64
+	Attributes map[string]string
+	ScanStatus string
+	Visibility string
 	UploadedAt time.Time
 }
 
@@ -95,11 +135,22 @@ func NewCreateFn(data []byte, fileManager filesystem.FileManager) repository.Cre
 }
 
 type uploader struct {
-	fileRepo    repository.FileRepository
-	fileManager filesystem.FileManager
-	dirManager  filesystem.DirectoryManager
-	log         logging.Logger
-	identifier  text.Identifier
+	fileRepo       repository.FileRepository
+	fileManager    filesystem.FileManager
+	dirManager     filesystem.DirectoryManager
+	log            logging.Logger
+	identifier     text.Identifier
+	imageProcessor imaging.ImageProcessor
+	exifPolicy     string
+	policy         UploadPolicy
+	scanner        scanning.Scanner
+	scanFailure    string
+	quarantineDir  string
+	keyId          string
+	compression    compressing.CompressionPolicy
+	quotaRepo      repository.QuotaRepository
+	visibility     string
+	clock          datetime.Clock
 }
 
 func (s *uploader) UploadFile(ctx context.Context, opts ...UploadFileOption) (*UploadFileResult, error) {
@@ -118,8 +169,48 @@ func (s *uploader) UploadFile(ctx context.Context, opts ...UploadFileOption) (*U
 		return nil, fmt.Errorf("invalid upload directory is not specified")
 	}
 
+	visibility := s.visibility
+	if p.visibility != "" {
+		visibility = p.visibility
+	}
+	if !IsValidVisibility(visibility) {
+		return nil, fmt.Errorf("invalid visibility")
+	}
+
+	if s.policy != nil {
+		err := s.policy.ValidateFile(ctx, ValidateFileParam{
+			ClientId:  p.clientId,
+			Mimetype:  p.fileMimetype,
+			Extension: p.fileExtension,
+			Size:      p.fileSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.checkQuota(ctx, p.clientId, p.fileSize)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueId, err := s.identifier.GenerateId()
+	if err != nil {
+		return nil, err
+	}
+
+	fileDir := p.fileDir
+	locator, ok := LocationFromContext(ctx)
+	if ok {
+		location := locator.GetLocation(GetLocationParam{
+			UniqueId: uniqueId,
+			ClientId: p.clientId,
+		})
+		fileDir = fmt.Sprintf("%s/%s", p.fileDir, location)
+	}
+
 	exists, err := s.dirManager.IsDirectoryExists(ctx, filesystem.IsDirectoryExistsParam{
-		Path: p.fileDir,
+		Path: fileDir,
 	})
 	if err != nil {
 		return nil, err
@@ -127,7 +218,7 @@ func (s *uploader) UploadFile(ctx context.Context, opts ...UploadFileOption) (*U
 
 	if !exists {
 		_, err := s.dirManager.CreateDir(ctx, filesystem.CreateDirParam{
-			Path:       p.fileDir,
+			Path:       fileDir,
 			Permission: 0644,
 		})
 		if err != nil {
@@ -144,25 +235,53 @@ func (s *uploader) UploadFile(ctx context.Context, opts ...UploadFileOption) (*U
 		data = byte
 	}
 
-	uniqueId, err := s.identifier.GenerateId()
+	scan, err := s.scanFile(ctx, uniqueId, p, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, attributes, err := s.processImage(ctx, p.fileMimetype, data)
 	if err != nil {
 		return nil, err
 	}
+	if s.exifPolicy != EXIF_POLICY_KEEP && attributes != nil {
+		p.fileSize = int64(len(data))
+	}
 
-	path := fmt.Sprintf("%s/%s", p.fileDir, uniqueId)
+	path := fmt.Sprintf("%s/%s", fileDir, uniqueId)
 	if p.fileExtension != "" {
 		path = fmt.Sprintf("%s.%s", path, p.fileExtension)
 	}
 
+	codec := ""
+	if s.compression != nil {
+		codec = s.compression.SelectCodec(ctx, compressing.SelectCodecParam{
+			Mimetype: p.fileMimetype,
+			Size:     int64(len(data)),
+		})
+	}
+	if codec != "" {
+		ctx = compressing.NewCodecContext(ctx, codec)
+	}
+
 	cRes, err := s.fileRepo.CreateFile(ctx, repository.CreateFileParam{
-		UniqueId:  uniqueId,
-		Path:      path,
-		Name:      p.fileName,
-		Mimetype:  p.fileMimetype,
-		Extension: p.fileExtension,
-		Size:      p.fileSize,
-		CreateFn:  NewCreateFn(data, s.fileManager),
+		UniqueId:        uniqueId,
+		Path:            path,
+		Name:            p.fileName,
+		Mimetype:        p.fileMimetype,
+		Extension:       p.fileExtension,
+		Size:            p.fileSize,
+		Attributes:      attributes,
+		Scan:            scan,
+		EncryptionKeyId: s.keyId,
+		Codec:           codec,
+		ClientId:        p.clientId,
+		Visibility:      visibility,
+		CreateFn:        NewCreateFn(data, s.fileManager),
 	})
+	if errors.Is(err, repository.ErrorQuotaExceeded) {
+		return nil, ErrorQuotaExceeded
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -174,17 +293,187 @@ func (s *uploader) UploadFile(ctx context.Context, opts ...UploadFileOption) (*U
 		Mimetype:   cRes.Mimetype,
 		Extension:  cRes.Extension,
 		Size:       cRes.Size,
+		Attributes: cRes.Attributes,
+		Visibility: cRes.Visibility,
 		UploadedAt: cRes.CreatedAt,
 	}
+	if cRes.Scan != nil {
+		res.ScanStatus = cRes.Scan.Status
+	}
 	return res, nil
 }
 
+// reject the file early before it's processed, the quota is enforced
+// again when the file is created since the usage may change in the meantime
+func (s *uploader) checkQuota(ctx context.Context, clientId string, size int64) error {
+	if s.quotaRepo == nil || clientId == "" {
+		return nil
+	}
+
+	quota, err := s.quotaRepo.FindQuota(ctx, repository.FindQuotaParam{
+		ClientId: clientId,
+	})
+	if errors.Is(err, repository.ErrorRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if quota.MaxSize > 0 && size > quota.MaxSize {
+		return ErrorFileTooLarge
+	}
+	if quota.MaxSize > 0 && quota.UsedSize+size > quota.MaxSize {
+		return ErrorQuotaExceeded
+	}
+	if quota.MaxFile > 0 && quota.UsedFile >= quota.MaxFile {
+		return ErrorQuotaExceeded
+	}
+	return nil
+}
+
+func (s *uploader) scanFile(ctx context.Context, uniqueId string, p UploadFileParam, data []byte) (*repository.FileScan, error) {
+	if s.scanner == nil {
+		return nil, nil
+	}
+
+	sRes, err := s.scanner.ScanFile(ctx, scanning.ScanFileParam{
+		Data: data,
+	})
+	if err != nil {
+		if s.scanFailure == SCAN_FAILURE_CLOSED {
+			s.log.Errorf("Failed scan file: %s", err.Error())
+			return nil, ErrorScanFailed
+		}
+
+		s.log.Warnf("Failed scan file, file is accepted: %s", err.Error())
+		scan := &repository.FileScan{
+			Status:    scanning.STATUS_FAILED,
+			ScannedAt: s.clock.Now(),
+		}
+		return scan, nil
+	}
+
+	if sRes.Status == scanning.STATUS_INFECTED {
+		s.log.Warnf("Infected file is rejected: %s", sRes.Signature)
+		if s.quarantineDir != "" {
+			err := s.quarantineFile(ctx, uniqueId, p, data)
+			if err != nil {
+				s.log.Errorf("Failed quarantine file: %s", err.Error())
+			}
+		}
+		return nil, ErrorFileInfected
+	}
+
+	scan := &repository.FileScan{
+		Status:    sRes.Status,
+		Engine:    sRes.Engine,
+		Signature: sRes.Signature,
+		ScannedAt: sRes.ScannedAt,
+	}
+	return scan, nil
+}
+
+func (s *uploader) quarantineFile(ctx context.Context, uniqueId string, p UploadFileParam, data []byte) error {
+	exists, err := s.dirManager.IsDirectoryExists(ctx, filesystem.IsDirectoryExistsParam{
+		Path: s.quarantineDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		_, err := s.dirManager.CreateDir(ctx, filesystem.CreateDirParam{
+			Path:       s.quarantineDir,
+			Permission: 0700,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	path := fmt.Sprintf("%s/%s", s.quarantineDir, uniqueId)
+	if p.fileExtension != "" {
+		path = fmt.Sprintf("%s.%s", path, p.fileExtension)
+	}
+
+	_, err = s.fileManager.SaveFile(ctx, filesystem.SaveFileParam{
+		Name:       path,
+		Data:       data,
+		Permission: 0600,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.Infof("Infected file is quarantined: %s", path)
+	return nil
+}
+
+func (s *uploader) processImage(ctx context.Context, mimetype string, data []byte) ([]byte, map[string]string, error) {
+	if s.imageProcessor == nil || !imaging.IsSupportedMimetype(mimetype) {
+		return data, nil, nil
+	}
+
+	attributes := map[string]string{}
+	meta, err := s.imageProcessor.ExtractMetadata(ctx, imaging.ExtractMetadataParam{
+		Data: data,
+	})
+	if err != nil {
+		s.log.Warnf("Failed extract image metadata: %s", err.Error())
+	} else {
+		attributes[ATTRIBUTE_WIDTH] = strconv.Itoa(meta.Width)
+		attributes[ATTRIBUTE_HEIGHT] = strconv.Itoa(meta.Height)
+		attributes[ATTRIBUTE_ORIENTATION] = strconv.Itoa(meta.Orientation)
+		for name, value := range meta.Exif {
+			if s.exifPolicy != EXIF_POLICY_KEEP && strings.HasPrefix(name, "gps_") {
+				continue
+			}
+			attributes[ATTRIBUTE_EXIF_PREFIX+name] = value
+		}
+	}
+
+	if s.exifPolicy == EXIF_POLICY_KEEP {
+		return data, attributes, nil
+	}
+
+	applyOrientation := s.exifPolicy == EXIF_POLICY_ORIENT_STRIP
+	sRes, err := s.imageProcessor.StripMetadata(ctx, imaging.StripMetadataParam{
+		Data:             data,
+		ApplyOrientation: applyOrientation,
+	})
+	if errors.Is(err, imaging.ErrorImageTooLarge) {
+		return nil, nil, ErrorFileTooLarge
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attributes[ATTRIBUTE_WIDTH] = strconv.Itoa(sRes.Width)
+	attributes[ATTRIBUTE_HEIGHT] = strconv.Itoa(sRes.Height)
+	if applyOrientation {
+		attributes[ATTRIBUTE_ORIENTATION] = strconv.Itoa(imaging.ORIENTATION_NORMAL)
+	}
+	return sRes.Data, attributes, nil
+}
+
 type NewUploaderParam struct {
-	FileRepo    repository.FileRepository
-	FileManager filesystem.FileManager
-	DirManager  filesystem.DirectoryManager
-	Logger      logging.Logger
-	Identifier  text.Identifier
+	FileRepo        repository.FileRepository
+	FileManager     filesystem.FileManager
+	DirManager      filesystem.DirectoryManager
+	Logger          logging.Logger
+	Identifier      text.Identifier
+	ImageProcessor  imaging.ImageProcessor
+	ExifPolicy      string
+	Policy          UploadPolicy
+	Scanner         scanning.Scanner
+	ScanFailure     string
+	QuarantineDir   string
+	EncryptionKeyId string
+	Compression     compressing.CompressionPolicy
+	QuotaRepo       repository.QuotaRepository
+	Visibility      string
+	Clock           datetime.Clock
 }
 
 func NewUploader(p NewUploaderParam) (*uploader, error) {
@@ -204,12 +493,63 @@ func NewUploader(p NewUploaderParam) (*uploader, error) {
 		return nil, fmt.Errorf("identifier is not specified")
 	}
 
+	exifPolicy := EXIF_POLICY_KEEP
+	if p.ExifPolicy != "" {
+		exifPolicy = p.ExifPolicy
+	}
+	if exifPolicy != EXIF_POLICY_KEEP &&
+		exifPolicy != EXIF_POLICY_STRIP &&
+		exifPolicy != EXIF_POLICY_ORIENT_STRIP {
+		return nil, fmt.Errorf("invalid exif policy")
+	}
+	if exifPolicy != EXIF_POLICY_KEEP && p.ImageProcessor == nil {
+		return nil, fmt.Errorf("image processor is not specified")
+	}
+
+	scanFailure := SCAN_FAILURE_CLOSED
+	if p.ScanFailure != "" {
+		scanFailure = p.ScanFailure
+	}
+	if scanFailure != SCAN_FAILURE_CLOSED &&
+		scanFailure != SCAN_FAILURE_OPEN {
+		return nil, fmt.Errorf("invalid scan failure")
+	}
+
+	visibility := repository.VISIBILITY_PRIVATE
+	if p.Visibility != "" {
+		visibility = p.Visibility
+	}
+	if !IsValidVisibility(visibility) {
+		return nil, fmt.Errorf("invalid visibility")
+	}
+
+	clock := p.Clock
+	if clock == nil {
+		clock = datetime.NewClock()
+	}
+
 	s := &uploader{
-		fileRepo:    p.FileRepo,
-		fileManager: p.FileManager,
-		dirManager:  p.DirManager,
-		log:         p.Logger,
-		identifier:  p.Identifier,
+		fileRepo:       p.FileRepo,
+		fileManager:    p.FileManager,
+		dirManager:     p.DirManager,
+		log:            p.Logger,
+		identifier:     p.Identifier,
+		imageProcessor: p.ImageProcessor,
+		exifPolicy:     exifPolicy,
+		policy:         p.Policy,
+		scanner:        p.Scanner,
+		scanFailure:    scanFailure,
+		quarantineDir:  p.QuarantineDir,
+		keyId:          p.EncryptionKeyId,
+		compression:    p.Compression,
+		quotaRepo:      p.QuotaRepo,
+		visibility:     visibility,
+		clock:          clock,
 	}
 	return s, nil
 }
+
+func IsValidVisibility(visibility string) bool {
+	return visibility == repository.VISIBILITY_PUBLIC ||
+		visibility == repository.VISIBILITY_PRIVATE
+}
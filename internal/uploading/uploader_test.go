@@ -7,9 +7,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-seidon/local/internal/compressing"
 	"github.com/go-seidon/local/internal/filesystem"
+	"github.com/go-seidon/local/internal/imaging"
 	"github.com/go-seidon/local/internal/mock"
 	"github.com/go-seidon/local/internal/repository"
+	"github.com/go-seidon/local/internal/scanning"
 	"github.com/go-seidon/local/internal/uploading"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -107,6 +110,57 @@ var _ = Describe("Uploader Service", func() {
 				Expect(err).To(Equal(fmt.Errorf("identifier is not specified")))
 			})
 		})
+
+		When("exif policy is invalid", func() {
+			It("should return error", func() {
+				p.ExifPolicy = "invalid"
+				res, err := uploading.NewUploader(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid exif policy")))
+			})
+		})
+
+		When("visibility is invalid", func() {
+			It("should return error", func() {
+				p.Visibility = "invalid"
+				res, err := uploading.NewUploader(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid visibility")))
+			})
+		})
+
+		When("scan failure is invalid", func() {
+			It("should return error", func() {
+				p.ScanFailure = "invalid"
+				res, err := uploading.NewUploader(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid scan failure")))
+			})
+		})
+
+		When("image processor is not specified", func() {
+			It("should return error", func() {
+				p.ExifPolicy = uploading.EXIF_POLICY_STRIP
+				res, err := uploading.NewUploader(p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("image processor is not specified")))
+			})
+		})
+
+		When("exif policy is specified", func() {
+			It("should return result", func() {
+				p.ExifPolicy = uploading.EXIF_POLICY_ORIENT_STRIP
+				p.ImageProcessor = mock.NewMockImageProcessor(gomock.NewController(GinkgoT()))
+				res, err := uploading.NewUploader(p)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
 	})
 
 	Context("NewCreateFn function", Label("unit"), func() {
@@ -296,71 +350,90 @@ var _ = Describe("Uploader Service", func() {
 			})
 		})
 
-		When("failed check directory existance", func() {
+		When("visibility is invalid", func() {
 			It("should return error", func() {
-				dirManager.
+				res, err := s.UploadFile(ctx,
+					uploading.WithData([]byte{}),
+					uploading.WithDirectory("temp"),
+					uploading.WithVisibility("hidden"),
+				)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid visibility")))
+			})
+		})
+
+		When("failed generate file id", func() {
+			It("should return error", func() {
+				identifier.
 					EXPECT().
-					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(dirExistsParam)).
-					Return(false, fmt.Errorf("disk error")).
+					GenerateId().
+					Return("", fmt.Errorf("generate error")).
 					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Any(), gomock.Any()).
+					Times(0)
 
 				res, err := s.UploadFile(ctx, opts...)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("disk error")))
+				Expect(err).To(Equal(fmt.Errorf("generate error")))
 			})
 		})
 
-		When("failed create upload directory", func() {
+		When("failed check directory existance", func() {
 			It("should return error", func() {
-				dirManager.
+				identifier.
 					EXPECT().
-					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(dirExistsParam)).
-					Return(false, nil).
+					GenerateId().
+					Return("mock-unique-id", nil).
 					Times(1)
 				dirManager.
 					EXPECT().
-					CreateDir(gomock.Eq(ctx), gomock.Eq(createDirParam)).
-					Return(nil, fmt.Errorf("r/w error")).
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(dirExistsParam)).
+					Return(false, fmt.Errorf("disk error")).
 					Times(1)
 
 				res, err := s.UploadFile(ctx, opts...)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("r/w error")))
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
 			})
 		})
 
-		When("failed read from file reader", func() {
+		When("failed create upload directory", func() {
 			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
 				dirManager.
 					EXPECT().
 					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(dirExistsParam)).
-					Return(true, nil).
+					Return(false, nil).
 					Times(1)
 				dirManager.
 					EXPECT().
 					CreateDir(gomock.Eq(ctx), gomock.Eq(createDirParam)).
-					Times(0)
-				reader.
-					EXPECT().
-					Read(gomock.Any()).
-					Return(0, fmt.Errorf("disk error")).
+					Return(nil, fmt.Errorf("r/w error")).
 					Times(1)
 
-				fwOpt := uploading.WithReader(reader)
-				copts := opts
-				copts = append(copts, fwOpt)
-
-				res, err := s.UploadFile(ctx, copts...)
+				res, err := s.UploadFile(ctx, opts...)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("disk error")))
+				Expect(err).To(Equal(fmt.Errorf("r/w error")))
 			})
 		})
 
-		When("failed generate file id", func() {
+		When("failed read from file reader", func() {
 			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
 				dirManager.
 					EXPECT().
 					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(dirExistsParam)).
@@ -373,12 +446,7 @@ var _ = Describe("Uploader Service", func() {
 				reader.
 					EXPECT().
 					Read(gomock.Any()).
-					Return(0, io.EOF).
-					Times(1)
-				identifier.
-					EXPECT().
-					GenerateId().
-					Return("", fmt.Errorf("generate error")).
+					Return(0, fmt.Errorf("disk error")).
 					Times(1)
 
 				fwOpt := uploading.WithReader(reader)
@@ -388,7 +456,7 @@ var _ = Describe("Uploader Service", func() {
 				res, err := s.UploadFile(ctx, copts...)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("generate error")))
+				Expect(err).To(Equal(fmt.Errorf("disk error")))
 			})
 		})
 
@@ -459,5 +527,1091 @@ var _ = Describe("Uploader Service", func() {
 			})
 		})
 
+		When("location is specified in context", func() {
+			It("should upload into the location", func() {
+				locator := mock.NewMockUploadLocation(gomock.NewController(GinkgoT()))
+				lctx := uploading.NewLocationContext(ctx, locator)
+
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				locator.
+					EXPECT().
+					GetLocation(gomock.Eq(uploading.GetLocationParam{
+						UniqueId: "mock-unique-id",
+						ClientId: "mock-client-id",
+					})).
+					Return("mock-client-id/2022/02").
+					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(lctx), gomock.Eq(filesystem.IsDirectoryExistsParam{
+						Path: "temp/mock-client-id/2022/02",
+					})).
+					Return(true, nil).
+					Times(1)
+				reader.
+					EXPECT().
+					Read(gomock.Any()).
+					Return(0, io.EOF).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(lctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(p.Path).To(Equal("temp/mock-client-id/2022/02/mock-unique-id.jpg"))
+						return createFileRes, nil
+					}).
+					Times(1)
+
+				copts := append(opts, uploading.WithReader(reader), uploading.WithClient("mock-client-id"))
+				res, err := s.UploadFile(lctx, copts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+	})
+
+	Context("UploadFile function with image processor", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			ctrl             *gomock.Controller
+			fileRepo         *mock.MockFileRepository
+			fileManager      *mock.MockFileManager
+			dirManager       *mock.MockDirectoryManager
+			logger           *mock.MockLogger
+			identifier       *mock.MockIdentifier
+			processor        *mock.MockImageProcessor
+			p                uploading.NewUploaderParam
+			data             []byte
+			opts             []uploading.UploadFileOption
+			extractParam     imaging.ExtractMetadataParam
+			extractRes       *imaging.ExtractMetadataResult
+		)
+
+		BeforeEach(func() {
+			currentTimestamp = time.Now()
+			ctx = context.Background()
+			t := GinkgoT()
+			ctrl = gomock.NewController(t)
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			fileManager = mock.NewMockFileManager(ctrl)
+			dirManager = mock.NewMockDirectoryManager(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			processor = mock.NewMockImageProcessor(ctrl)
+			p = uploading.NewUploaderParam{
+				FileRepo:       fileRepo,
+				FileManager:    fileManager,
+				DirManager:     dirManager,
+				Logger:         logger,
+				Identifier:     identifier,
+				ImageProcessor: processor,
+			}
+			data = []byte("mock-image-data")
+			opts = []uploading.UploadFileOption{
+				uploading.WithData(data),
+				uploading.WithDirectory("temp"),
+				uploading.WithFileInfo("mock-name", "image/jpeg", "jpg", 100),
+			}
+			extractParam = imaging.ExtractMetadataParam{
+				Data: data,
+			}
+			extractRes = &imaging.ExtractMetadataResult{
+				Format:      "jpeg",
+				Width:       720,
+				Height:      480,
+				Orientation: 6,
+				Exif: map[string]string{
+					"make":          "Canon",
+					"gps_latitude":  "-6.500000",
+					"gps_longitude": "106.750000",
+				},
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: UploadFile").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: UploadFile").
+				Times(1)
+			dirManager.
+				EXPECT().
+				IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+				Return(true, nil).
+				Times(1)
+		})
+
+		When("file is not an image", func() {
+			It("should not process the file", func() {
+				s, _ := uploading.NewUploader(p)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Attributes).To(BeNil())
+						return &repository.CreateFileResult{CreatedAt: currentTimestamp}, nil
+					}).
+					Times(1)
+
+				copts := append(opts, uploading.WithFileInfo("mock-name", "text/plain", "txt", 100))
+				res, err := s.UploadFile(ctx, copts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("encryption key id is specified", func() {
+			It("should record the key id", func() {
+				p.EncryptionKeyId = "mock-key-id"
+				s, _ := uploading.NewUploader(p)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.EncryptionKeyId).To(Equal("mock-key-id"))
+						return &repository.CreateFileResult{CreatedAt: currentTimestamp}, nil
+					}).
+					Times(1)
+
+				copts := append(opts, uploading.WithFileInfo("mock-name", "text/plain", "txt", 100))
+				res, err := s.UploadFile(ctx, copts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("visibility is not specified", func() {
+			It("should use the default visibility", func() {
+				p.Visibility = repository.VISIBILITY_PUBLIC
+				s, _ := uploading.NewUploader(p)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Visibility).To(Equal(repository.VISIBILITY_PUBLIC))
+						return &repository.CreateFileResult{
+							Visibility: cp.Visibility,
+							CreatedAt:  currentTimestamp,
+						}, nil
+					}).
+					Times(1)
+
+				copts := append(opts, uploading.WithFileInfo("mock-name", "text/plain", "txt", 100))
+				res, err := s.UploadFile(ctx, copts...)
+
+				Expect(res.Visibility).To(Equal(repository.VISIBILITY_PUBLIC))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("visibility is specified", func() {
+			It("should override the default visibility", func() {
+				p.Visibility = repository.VISIBILITY_PUBLIC
+				s, _ := uploading.NewUploader(p)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Visibility).To(Equal(repository.VISIBILITY_PRIVATE))
+						return &repository.CreateFileResult{
+							Visibility: cp.Visibility,
+							CreatedAt:  currentTimestamp,
+						}, nil
+					}).
+					Times(1)
+
+				copts := append(opts,
+					uploading.WithFileInfo("mock-name", "text/plain", "txt", 100),
+					uploading.WithVisibility(repository.VISIBILITY_PRIVATE),
+				)
+				res, err := s.UploadFile(ctx, copts...)
+
+				Expect(res.Visibility).To(Equal(repository.VISIBILITY_PRIVATE))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("compression policy selects a codec", func() {
+			It("should compress the file", func() {
+				policy := mock.NewMockCompressionPolicy(ctrl)
+				p.Compression = policy
+				s, _ := uploading.NewUploader(p)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				policy.
+					EXPECT().
+					SelectCodec(gomock.Eq(ctx), gomock.Eq(compressing.SelectCodecParam{
+						Mimetype: "text/plain",
+						Size:     int64(len(data)),
+					})).
+					Return(compressing.CODEC_GZIP).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(compressing.CodecFromContext(ctx)).To(Equal(compressing.CODEC_GZIP))
+						Expect(cp.Codec).To(Equal(compressing.CODEC_GZIP))
+						Expect(cp.Size).To(Equal(int64(100)))
+						return &repository.CreateFileResult{CreatedAt: currentTimestamp}, nil
+					}).
+					Times(1)
+
+				copts := append(opts, uploading.WithFileInfo("mock-name", "text/plain", "txt", 100))
+				res, err := s.UploadFile(ctx, copts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("compression policy skips the file", func() {
+			It("should store the file as is", func() {
+				policy := mock.NewMockCompressionPolicy(ctrl)
+				p.Compression = policy
+				s, _ := uploading.NewUploader(p)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				policy.
+					EXPECT().
+					SelectCodec(gomock.Eq(ctx), gomock.Any()).
+					Return("").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Codec).To(Equal(""))
+						return &repository.CreateFileResult{CreatedAt: currentTimestamp}, nil
+					}).
+					Times(1)
+
+				copts := append(opts, uploading.WithFileInfo("mock-name", "text/plain", "txt", 100))
+				res, err := s.UploadFile(ctx, copts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed extract metadata", func() {
+			It("should upload without attributes", func() {
+				s, _ := uploading.NewUploader(p)
+				processor.
+					EXPECT().
+					ExtractMetadata(gomock.Eq(ctx), gomock.Eq(extractParam)).
+					Return(nil, fmt.Errorf("image: unknown format")).
+					Times(1)
+				logger.
+					EXPECT().
+					Warnf("Failed extract image metadata: %s", "image: unknown format").
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Attributes).To(Equal(map[string]string{}))
+						Expect(cp.Size).To(Equal(int64(100)))
+						return &repository.CreateFileResult{CreatedAt: currentTimestamp}, nil
+					}).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("exif policy is keep", func() {
+			It("should store all attributes", func() {
+				s, _ := uploading.NewUploader(p)
+				processor.
+					EXPECT().
+					ExtractMetadata(gomock.Eq(ctx), gomock.Eq(extractParam)).
+					Return(extractRes, nil).
+					Times(1)
+				processor.
+					EXPECT().
+					StripMetadata(gomock.Any(), gomock.Any()).
+					Times(0)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				attributes := map[string]string{
+					"width":              "720",
+					"height":             "480",
+					"orientation":        "6",
+					"exif_make":          "Canon",
+					"exif_gps_latitude":  "-6.500000",
+					"exif_gps_longitude": "106.750000",
+				}
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Attributes).To(Equal(attributes))
+						Expect(cp.Size).To(Equal(int64(100)))
+						return &repository.CreateFileResult{
+							Attributes: cp.Attributes,
+							CreatedAt:  currentTimestamp,
+						}, nil
+					}).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res.Attributes).To(Equal(attributes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed strip metadata", func() {
+			It("should return error", func() {
+				p.ExifPolicy = uploading.EXIF_POLICY_STRIP
+				s, _ := uploading.NewUploader(p)
+				processor.
+					EXPECT().
+					ExtractMetadata(gomock.Eq(ctx), gomock.Eq(extractParam)).
+					Return(extractRes, nil).
+					Times(1)
+				processor.
+					EXPECT().
+					StripMetadata(gomock.Eq(ctx), gomock.Eq(imaging.StripMetadataParam{
+						Data: data,
+					})).
+					Return(nil, fmt.Errorf("invalid jpeg data")).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid jpeg data")))
+			})
+		})
+
+		When("image dimension exceeds the limit", func() {
+			It("should return error", func() {
+				p.ExifPolicy = uploading.EXIF_POLICY_ORIENT_STRIP
+				s, _ := uploading.NewUploader(p)
+				processor.
+					EXPECT().
+					ExtractMetadata(gomock.Eq(ctx), gomock.Eq(extractParam)).
+					Return(extractRes, nil).
+					Times(1)
+				processor.
+					EXPECT().
+					StripMetadata(gomock.Eq(ctx), gomock.Eq(imaging.StripMetadataParam{
+						Data:             data,
+						ApplyOrientation: true,
+					})).
+					Return(nil, imaging.ErrorImageTooLarge).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorFileTooLarge))
+			})
+		})
+
+		When("exif policy is strip", func() {
+			It("should store stripped data", func() {
+				p.ExifPolicy = uploading.EXIF_POLICY_STRIP
+				s, _ := uploading.NewUploader(p)
+				processor.
+					EXPECT().
+					ExtractMetadata(gomock.Eq(ctx), gomock.Eq(extractParam)).
+					Return(extractRes, nil).
+					Times(1)
+				processor.
+					EXPECT().
+					StripMetadata(gomock.Eq(ctx), gomock.Eq(imaging.StripMetadataParam{
+						Data: data,
+					})).
+					Return(&imaging.StripMetadataResult{
+						Data:   []byte("stripped"),
+						Width:  720,
+						Height: 480,
+					}, nil).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Attributes).To(Equal(map[string]string{
+							"width":       "720",
+							"height":      "480",
+							"orientation": "6",
+							"exif_make":   "Canon",
+						}))
+						Expect(cp.Size).To(Equal(int64(8)))
+						return &repository.CreateFileResult{CreatedAt: currentTimestamp}, nil
+					}).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("exif policy is orient strip", func() {
+			It("should store oriented data", func() {
+				p.ExifPolicy = uploading.EXIF_POLICY_ORIENT_STRIP
+				s, _ := uploading.NewUploader(p)
+				processor.
+					EXPECT().
+					ExtractMetadata(gomock.Eq(ctx), gomock.Eq(extractParam)).
+					Return(extractRes, nil).
+					Times(1)
+				processor.
+					EXPECT().
+					StripMetadata(gomock.Eq(ctx), gomock.Eq(imaging.StripMetadataParam{
+						Data:             data,
+						ApplyOrientation: true,
+					})).
+					Return(&imaging.StripMetadataResult{
+						Data:   []byte("oriented"),
+						Width:  480,
+						Height: 720,
+					}, nil).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Attributes).To(Equal(map[string]string{
+							"width":       "480",
+							"height":      "720",
+							"orientation": "1",
+							"exif_make":   "Canon",
+						}))
+						return &repository.CreateFileResult{CreatedAt: currentTimestamp}, nil
+					}).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadFile function with upload policy", Label("unit"), func() {
+		var (
+			ctx         context.Context
+			fileRepo    *mock.MockFileRepository
+			dirManager  *mock.MockDirectoryManager
+			logger      *mock.MockLogger
+			identifier  *mock.MockIdentifier
+			policy      *mock.MockUploadPolicy
+			s           uploading.Uploader
+			opts        []uploading.UploadFileOption
+			policyParam uploading.ValidateFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			dirManager = mock.NewMockDirectoryManager(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			policy = mock.NewMockUploadPolicy(ctrl)
+			s, _ = uploading.NewUploader(uploading.NewUploaderParam{
+				FileRepo:    fileRepo,
+				FileManager: mock.NewMockFileManager(ctrl),
+				DirManager:  dirManager,
+				Logger:      logger,
+				Identifier:  identifier,
+				Policy:      policy,
+			})
+			opts = []uploading.UploadFileOption{
+				uploading.WithData([]byte{}),
+				uploading.WithDirectory("temp"),
+				uploading.WithClient("mock-client-id"),
+				uploading.WithFileInfo("mock-name", "image/jpeg", "jpg", 100),
+			}
+			policyParam = uploading.ValidateFileParam{
+				ClientId:  "mock-client-id",
+				Mimetype:  "image/jpeg",
+				Extension: "jpg",
+				Size:      100,
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: UploadFile").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: UploadFile").
+				Times(1)
+		})
+
+		When("file is rejected by policy", func() {
+			It("should return error", func() {
+				policy.
+					EXPECT().
+					ValidateFile(gomock.Eq(ctx), gomock.Eq(policyParam)).
+					Return(uploading.ErrorFileTooLarge).
+					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorFileTooLarge))
+			})
+		})
+
+		When("file is accepted by policy", func() {
+			It("should return result", func() {
+				policy.
+					EXPECT().
+					ValidateFile(gomock.Eq(ctx), gomock.Eq(policyParam)).
+					Return(nil).
+					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(true, nil).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					Return(&repository.CreateFileResult{}, nil).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadFile function with quota", Label("unit"), func() {
+		var (
+			ctx        context.Context
+			fileRepo   *mock.MockFileRepository
+			quotaRepo  *mock.MockQuotaRepository
+			dirManager *mock.MockDirectoryManager
+			logger     *mock.MockLogger
+			identifier *mock.MockIdentifier
+			s          uploading.Uploader
+			opts       []uploading.UploadFileOption
+			quotaParam repository.FindQuotaParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			quotaRepo = mock.NewMockQuotaRepository(ctrl)
+			dirManager = mock.NewMockDirectoryManager(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			s, _ = uploading.NewUploader(uploading.NewUploaderParam{
+				FileRepo:    fileRepo,
+				FileManager: mock.NewMockFileManager(ctrl),
+				DirManager:  dirManager,
+				Logger:      logger,
+				Identifier:  identifier,
+				QuotaRepo:   quotaRepo,
+			})
+			opts = []uploading.UploadFileOption{
+				uploading.WithData([]byte{}),
+				uploading.WithDirectory("temp"),
+				uploading.WithClient("mock-client-id"),
+				uploading.WithFileInfo("mock-name", "image/jpeg", "jpg", 100),
+			}
+			quotaParam = repository.FindQuotaParam{
+				ClientId: "mock-client-id",
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: UploadFile").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: UploadFile").
+				Times(1)
+		})
+
+		When("failed find quota", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(nil, fmt.Errorf("db error")).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("db error")))
+			})
+		})
+
+		When("file is larger than the quota", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(&repository.FindQuotaResult{MaxSize: 99}, nil).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorFileTooLarge))
+			})
+		})
+
+		When("quota size is exceeded", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(&repository.FindQuotaResult{MaxSize: 1000, UsedSize: 901}, nil).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorQuotaExceeded))
+			})
+		})
+
+		When("quota file count is exceeded", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(&repository.FindQuotaResult{MaxFile: 3, UsedFile: 3}, nil).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorQuotaExceeded))
+			})
+		})
+
+		When("quota is exceeded while creating the file", func() {
+			It("should return error", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(&repository.FindQuotaResult{MaxSize: 1000, UsedSize: 900}, nil).
+					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(true, nil).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, repository.ErrorQuotaExceeded).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorQuotaExceeded))
+			})
+		})
+
+		When("quota is not found", func() {
+			It("should return result", func() {
+				quotaRepo.
+					EXPECT().
+					FindQuota(gomock.Eq(ctx), gomock.Eq(quotaParam)).
+					Return(nil, repository.ErrorRecordNotFound).
+					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Any()).
+					Return(true, nil).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.ClientId).To(Equal("mock-client-id"))
+						return &repository.CreateFileResult{}, nil
+					}).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadFile function with scanner", Label("unit"), func() {
+		var (
+			ctx              context.Context
+			currentTimestamp time.Time
+			fileRepo         *mock.MockFileRepository
+			fileManager      *mock.MockFileManager
+			dirManager       *mock.MockDirectoryManager
+			logger           *mock.MockLogger
+			identifier       *mock.MockIdentifier
+			scanner          *mock.MockScanner
+			clock            *mock.MockClock
+			p                uploading.NewUploaderParam
+			s                uploading.Uploader
+			opts             []uploading.UploadFileOption
+			scanParam        scanning.ScanFileParam
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			currentTimestamp = time.Now()
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fileRepo = mock.NewMockFileRepository(ctrl)
+			fileManager = mock.NewMockFileManager(ctrl)
+			dirManager = mock.NewMockDirectoryManager(ctrl)
+			logger = mock.NewMockLogger(ctrl)
+			identifier = mock.NewMockIdentifier(ctrl)
+			scanner = mock.NewMockScanner(ctrl)
+			clock = mock.NewMockClock(ctrl)
+			p = uploading.NewUploaderParam{
+				FileRepo:      fileRepo,
+				FileManager:   fileManager,
+				DirManager:    dirManager,
+				Logger:        logger,
+				Identifier:    identifier,
+				Scanner:       scanner,
+				QuarantineDir: "quarantine",
+				Clock:         clock,
+			}
+			s, _ = uploading.NewUploader(p)
+			opts = []uploading.UploadFileOption{
+				uploading.WithData([]byte("content")),
+				uploading.WithDirectory("temp"),
+				uploading.WithFileInfo("mock-name", "application/pdf", "pdf", 7),
+			}
+			scanParam = scanning.ScanFileParam{
+				Data: []byte("content"),
+			}
+
+			logger.
+				EXPECT().
+				Debug("In function: UploadFile").
+				Times(1)
+			logger.
+				EXPECT().
+				Debug("Returning function: UploadFile").
+				Times(1)
+			dirManager.
+				EXPECT().
+				IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsDirectoryExistsParam{
+					Path: "temp",
+				})).
+				Return(true, nil).
+				Times(1)
+		})
+
+		When("failed scan file with closed failure", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				scanner.
+					EXPECT().
+					ScanFile(gomock.Eq(ctx), gomock.Eq(scanParam)).
+					Return(nil, fmt.Errorf("connection refused")).
+					Times(1)
+				logger.
+					EXPECT().
+					Errorf("Failed scan file: %s", "connection refused").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorScanFailed))
+			})
+		})
+
+		When("failed scan file with open failure", func() {
+			It("should record failed scan", func() {
+				p.ScanFailure = uploading.SCAN_FAILURE_OPEN
+				s, _ = uploading.NewUploader(p)
+
+				scanner.
+					EXPECT().
+					ScanFile(gomock.Eq(ctx), gomock.Eq(scanParam)).
+					Return(nil, fmt.Errorf("connection refused")).
+					Times(1)
+				logger.
+					EXPECT().
+					Warnf("Failed scan file, file is accepted: %s", "connection refused").
+					Times(1)
+				clock.
+					EXPECT().
+					Now().
+					Return(currentTimestamp).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				scan := &repository.FileScan{
+					Status:    scanning.STATUS_FAILED,
+					ScannedAt: currentTimestamp,
+				}
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Scan).To(Equal(scan))
+						return &repository.CreateFileResult{Scan: cp.Scan}, nil
+					}).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res.ScanStatus).To(Equal(scanning.STATUS_FAILED))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file is infected", func() {
+			It("should quarantine the file", func() {
+				scanner.
+					EXPECT().
+					ScanFile(gomock.Eq(ctx), gomock.Eq(scanParam)).
+					Return(&scanning.ScanFileResult{
+						Status:    scanning.STATUS_INFECTED,
+						Engine:    "clamav",
+						Signature: "Eicar-Test-Signature",
+						ScannedAt: currentTimestamp,
+					}, nil).
+					Times(1)
+				logger.
+					EXPECT().
+					Warnf("Infected file is rejected: %s", "Eicar-Test-Signature").
+					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsDirectoryExistsParam{
+						Path: "quarantine",
+					})).
+					Return(false, nil).
+					Times(1)
+				dirManager.
+					EXPECT().
+					CreateDir(gomock.Eq(ctx), gomock.Eq(filesystem.CreateDirParam{
+						Path:       "quarantine",
+						Permission: 0700,
+					})).
+					Return(&filesystem.CreateDirResult{}, nil).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				fileManager.
+					EXPECT().
+					SaveFile(gomock.Eq(ctx), gomock.Eq(filesystem.SaveFileParam{
+						Name:       "quarantine/mock-unique-id.pdf",
+						Data:       []byte("content"),
+						Permission: 0600,
+					})).
+					Return(&filesystem.SaveFileResult{}, nil).
+					Times(1)
+				logger.
+					EXPECT().
+					Infof("Infected file is quarantined: %s", "quarantine/mock-unique-id.pdf").
+					Times(1)
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorFileInfected))
+			})
+		})
+
+		When("failed quarantine infected file", func() {
+			It("should return error", func() {
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				scanner.
+					EXPECT().
+					ScanFile(gomock.Eq(ctx), gomock.Eq(scanParam)).
+					Return(&scanning.ScanFileResult{
+						Status:    scanning.STATUS_INFECTED,
+						Signature: "Eicar-Test-Signature",
+					}, nil).
+					Times(1)
+				logger.
+					EXPECT().
+					Warnf("Infected file is rejected: %s", "Eicar-Test-Signature").
+					Times(1)
+				dirManager.
+					EXPECT().
+					IsDirectoryExists(gomock.Eq(ctx), gomock.Eq(filesystem.IsDirectoryExistsParam{
+						Path: "quarantine",
+					})).
+					Return(false, fmt.Errorf("disk error")).
+					Times(1)
+				logger.
+					EXPECT().
+					Errorf("Failed quarantine file: %s", "disk error").
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(uploading.ErrorFileInfected))
+			})
+		})
+
+		When("file is clean", func() {
+			It("should record scan result", func() {
+				scanner.
+					EXPECT().
+					ScanFile(gomock.Eq(ctx), gomock.Eq(scanParam)).
+					Return(&scanning.ScanFileResult{
+						Status:    scanning.STATUS_CLEAN,
+						Engine:    "clamav",
+						ScannedAt: currentTimestamp,
+					}, nil).
+					Times(1)
+				identifier.
+					EXPECT().
+					GenerateId().
+					Return("mock-unique-id", nil).
+					Times(1)
+				scan := &repository.FileScan{
+					Status:    scanning.STATUS_CLEAN,
+					Engine:    "clamav",
+					ScannedAt: currentTimestamp,
+				}
+				fileRepo.
+					EXPECT().
+					CreateFile(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cp repository.CreateFileParam) (*repository.CreateFileResult, error) {
+						Expect(cp.Scan).To(Equal(scan))
+						return &repository.CreateFileResult{Scan: cp.Scan}, nil
+					}).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, opts...)
+
+				Expect(res.ScanStatus).To(Equal(scanning.STATUS_CLEAN))
+				Expect(err).To(BeNil())
+			})
+		})
 	})
 })
@@ -0,0 +1,14 @@
+CREATE TABLE `file_attribute` (
+  `file_id` VARCHAR(128) NOT NULL,
+  `name` VARCHAR(128) NOT NULL,
+  `value` TEXT NOT NULL,
+  `created_at` BIGINT NOT NULL,
+  PRIMARY KEY (`file_id`, `name`),
+  INDEX idx_name(`name`),
+  CONSTRAINT fk_file_attribute_file_id
+    FOREIGN KEY (`file_id`) REFERENCES `file` (`id`)
+    ON DELETE CASCADE
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
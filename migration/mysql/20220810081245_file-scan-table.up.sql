@@ -0,0 +1,15 @@
+CREATE TABLE `file_scan` (
+  `file_id` VARCHAR(128) NOT NULL,
+  `status` VARCHAR(32) NOT NULL,
+  `engine` VARCHAR(64) NOT NULL,
+  `signature` VARCHAR(256) NOT NULL DEFAULT '',
+  `scanned_at` BIGINT NOT NULL,
+  PRIMARY KEY (`file_id`),
+  INDEX idx_status(`status`),
+  CONSTRAINT fk_file_scan_file_id
+    FOREIGN KEY (`file_id`) REFERENCES `file` (`id`)
+    ON DELETE CASCADE
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
@@ -0,0 +1,3 @@
+ALTER TABLE `file`
+  DROP INDEX idx_encryption_key_id,
+  DROP COLUMN `encryption_key_id`;
@@ -0,0 +1,3 @@
+ALTER TABLE `file`
+  ADD COLUMN `encryption_key_id` VARCHAR(64) NOT NULL DEFAULT '' AFTER `size`,
+  ADD INDEX idx_encryption_key_id(`encryption_key_id`);
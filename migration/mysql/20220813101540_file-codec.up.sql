@@ -0,0 +1,2 @@
+ALTER TABLE `file`
+  ADD COLUMN `codec` VARCHAR(16) NOT NULL DEFAULT '' AFTER `encryption_key_id`;
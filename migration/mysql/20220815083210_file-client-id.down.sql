@@ -0,0 +1,3 @@
+ALTER TABLE `file`
+  DROP INDEX idx_client_id,
+  DROP COLUMN `client_id`;
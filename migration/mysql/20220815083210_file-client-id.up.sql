@@ -0,0 +1,3 @@
+ALTER TABLE `file`
+  ADD COLUMN `client_id` VARCHAR(256) NOT NULL DEFAULT '' AFTER `codec`,
+  ADD INDEX idx_client_id(`client_id`);
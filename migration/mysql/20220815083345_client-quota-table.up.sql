@@ -0,0 +1,13 @@
+CREATE TABLE `client_quota` (
+  `client_id` VARCHAR(256) NOT NULL,
+  `max_size` BIGINT NOT NULL DEFAULT 0,
+  `max_file` BIGINT NOT NULL DEFAULT 0,
+  `used_size` BIGINT NOT NULL DEFAULT 0,
+  `used_file` BIGINT NOT NULL DEFAULT 0,
+  `created_at` BIGINT NOT NULL,
+  `updated_at` BIGINT NOT NULL,
+  PRIMARY KEY (`client_id`)
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
@@ -0,0 +1,14 @@
+CREATE TABLE `file_share` (
+  `file_id` VARCHAR(128) NOT NULL,
+  `client_id` VARCHAR(256) NOT NULL,
+  `permission` VARCHAR(16) NOT NULL,
+  `created_at` BIGINT NOT NULL,
+  PRIMARY KEY (`file_id`, `client_id`, `permission`),
+  INDEX idx_client_id(`client_id`),
+  CONSTRAINT fk_file_share_file_id
+    FOREIGN KEY (`file_id`) REFERENCES `file` (`id`)
+    ON DELETE CASCADE
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
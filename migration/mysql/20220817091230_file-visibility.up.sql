@@ -0,0 +1,2 @@
+ALTER TABLE `file`
+  ADD COLUMN `visibility` VARCHAR(16) NOT NULL DEFAULT 'private' AFTER `client_id`;
@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_client`
+  ADD COLUMN `scopes` VARCHAR(255) NOT NULL DEFAULT 'file:read file:write file:delete' AFTER `client_secret`;
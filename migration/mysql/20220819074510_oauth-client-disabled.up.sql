@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_client`
+  ADD COLUMN `disabled_at` BIGINT(20) NULL AFTER `scopes`;
@@ -0,0 +1,17 @@
+CREATE TABLE `oauth_client_secret` (
+  `id` VARCHAR(128) NOT NULL,
+  `client_id` VARCHAR(256) NOT NULL,
+  `label` VARCHAR(128) NOT NULL,
+  `secret` TEXT NOT NULL,
+  `created_at` BIGINT NOT NULL,
+  `expires_at` BIGINT NULL,
+  `last_used_at` BIGINT NULL,
+  PRIMARY KEY (`id`),
+  INDEX idx_client_id(`client_id`),
+  CONSTRAINT fk_oauth_client_secret_client_id
+    FOREIGN KEY (`client_id`) REFERENCES `oauth_client` (`client_id`)
+    ON DELETE CASCADE
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
@@ -0,0 +1,7 @@
+UPDATE `oauth_client` c
+SET c.`client_secret` = COALESCE((
+  SELECT s.`secret` FROM `oauth_client_secret` s
+  WHERE s.`client_id` = c.`client_id`
+  ORDER BY s.`created_at` DESC
+  LIMIT 1
+), '');
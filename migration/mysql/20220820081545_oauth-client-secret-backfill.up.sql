@@ -0,0 +1,6 @@
+INSERT INTO `oauth_client_secret` (
+  `id`, `client_id`, `label`, `secret`, `created_at`
+)
+SELECT 
+  CONCAT(`id`, '-default'), `client_id`, 'default', `client_secret`, `created_at`
+FROM `oauth_client`;
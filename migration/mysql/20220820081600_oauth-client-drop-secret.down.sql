@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_client`
+  ADD COLUMN `client_secret` TEXT NOT NULL AFTER `client_id`;
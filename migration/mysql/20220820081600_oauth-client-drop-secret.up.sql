@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_client`
+  DROP COLUMN `client_secret`;
@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_client`
+  ADD COLUMN `allowed_cidrs` VARCHAR(1024) NOT NULL DEFAULT '' AFTER `scopes`;
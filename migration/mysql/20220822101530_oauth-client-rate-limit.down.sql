@@ -0,0 +1,3 @@
+ALTER TABLE `oauth_client`
+  DROP COLUMN `byte_rate`,
+  DROP COLUMN `request_rate`;
@@ -0,0 +1,3 @@
+ALTER TABLE `oauth_client`
+  ADD COLUMN `request_rate` INT NULL AFTER `allowed_cidrs`,
+  ADD COLUMN `byte_rate` BIGINT NULL AFTER `request_rate`;
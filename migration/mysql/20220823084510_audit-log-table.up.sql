@@ -0,0 +1,18 @@
+CREATE TABLE `audit_log` (
+  `id` VARCHAR(128) NOT NULL,
+  `client_id` VARCHAR(256) NOT NULL DEFAULT '',
+  `action` VARCHAR(32) NOT NULL,
+  `file_id` VARCHAR(128) NOT NULL DEFAULT '',
+  `ip_address` VARCHAR(64) NOT NULL DEFAULT '',
+  `request_id` VARCHAR(128) NOT NULL DEFAULT '',
+  `outcome` VARCHAR(16) NOT NULL,
+  `reason` VARCHAR(256) NOT NULL DEFAULT '',
+  `created_at` BIGINT NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX idx_client_id(`client_id`, `created_at`),
+  INDEX idx_file_id(`file_id`, `created_at`),
+  INDEX idx_created_at(`created_at`)
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
@@ -0,0 +1,15 @@
+CREATE TABLE `webhook_subscription` (
+  `id` VARCHAR(128) NOT NULL,
+  `client_id` VARCHAR(256) NOT NULL,
+  `url` VARCHAR(2048) NOT NULL,
+  `secret` VARCHAR(128) NOT NULL,
+  `event_types` VARCHAR(256) NOT NULL,
+  `created_at` BIGINT NOT NULL,
+  `updated_at` BIGINT NOT NULL,
+  `deleted_at` BIGINT NULL,
+  PRIMARY KEY (`id`),
+  INDEX idx_client_id(`client_id`)
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
@@ -0,0 +1,26 @@
+CREATE TABLE `webhook_delivery` (
+  `id` VARCHAR(128) NOT NULL,
+  `subscription_id` VARCHAR(128) NOT NULL,
+  `client_id` VARCHAR(256) NOT NULL,
+  `event_id` VARCHAR(128) NOT NULL,
+  `event_type` VARCHAR(64) NOT NULL,
+  `payload` TEXT NOT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `attempt` INT NOT NULL DEFAULT 0,
+  `next_attempt_at` BIGINT NOT NULL,
+  `response_code` INT NOT NULL DEFAULT 0,
+  `last_error` VARCHAR(256) NOT NULL DEFAULT '',
+  `created_at` BIGINT NOT NULL,
+  `updated_at` BIGINT NOT NULL,
+  `delivered_at` BIGINT NULL,
+  PRIMARY KEY (`id`),
+  INDEX idx_status_next_attempt_at(`status`, `next_attempt_at`),
+  INDEX idx_subscription_id(`subscription_id`, `id`),
+  INDEX idx_client_id(`client_id`, `id`),
+  CONSTRAINT fk_webhook_delivery_subscription_id
+    FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscription` (`id`)
+    ON DELETE CASCADE
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
@@ -0,0 +1,22 @@
+CREATE TABLE `file_event_outbox` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `idempotency_key` VARCHAR(256) NOT NULL,
+  `event_type` VARCHAR(64) NOT NULL,
+  `file_id` VARCHAR(128) NOT NULL,
+  `client_id` VARCHAR(256) NOT NULL DEFAULT '',
+  `name` VARCHAR(4096) NOT NULL,
+  `mimetype` VARCHAR(256) NOT NULL,
+  `extension` VARCHAR(128) NOT NULL,
+  `size` BIGINT NOT NULL,
+  `attempt` INT NOT NULL DEFAULT 0,
+  `next_attempt_at` BIGINT NOT NULL,
+  `last_error` VARCHAR(256) NOT NULL DEFAULT '',
+  `created_at` BIGINT NOT NULL,
+  `published_at` BIGINT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX uq_idempotency_key(`idempotency_key`),
+  INDEX idx_published_at_next_attempt_at(`published_at`, `next_attempt_at`)
+) 
+DEFAULT CHARACTER SET utf8
+COLLATE utf8_unicode_ci
+ENGINE = InnoDB;
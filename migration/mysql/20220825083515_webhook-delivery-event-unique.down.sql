@@ -0,0 +1,2 @@
+ALTER TABLE `webhook_delivery`
+  DROP INDEX uq_subscription_id_event_id;
@@ -0,0 +1,2 @@
+ALTER TABLE `webhook_delivery`
+  ADD UNIQUE INDEX uq_subscription_id_event_id(`subscription_id`, `event_id`);
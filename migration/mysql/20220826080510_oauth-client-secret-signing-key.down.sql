@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_client_secret`
+  DROP COLUMN `signing_key`;
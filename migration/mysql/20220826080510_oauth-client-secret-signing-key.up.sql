@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_client_secret`
+  ADD COLUMN `signing_key` TEXT NULL AFTER `secret`;
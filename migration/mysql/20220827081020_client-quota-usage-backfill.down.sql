@@ -0,0 +1,2 @@
+-- the usage is recalculated from the existing files, there is nothing to restore
+SELECT 1;
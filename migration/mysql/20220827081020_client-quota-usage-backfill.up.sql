@@ -0,0 +1,17 @@
+INSERT INTO `client_quota` (
+  `client_id`, `used_size`, `used_file`, `created_at`, `updated_at`
+)
+SELECT 
+  c.`client_id`, COALESCE(SUM(f.`size`), 0), COUNT(f.`id`), 
+  FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000), FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)
+FROM (
+  SELECT `client_id` FROM `file` WHERE `client_id` <> ''
+  UNION
+  SELECT `client_id` FROM `client_quota`
+) c
+LEFT JOIN `file` f ON f.`client_id` = c.`client_id` AND f.`deleted_at` IS NULL
+GROUP BY c.`client_id`
+ON DUPLICATE KEY UPDATE 
+  `used_size` = VALUES(`used_size`), 
+  `used_file` = VALUES(`used_file`), 
+  `updated_at` = VALUES(`updated_at`);